@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+func runAdmin(cli *CLI, flags *flag.FlagSet, args []string) error {
+	if len(args) == 0 {
+		flags.Usage()
+		return fmt.Errorf("admin task must be provided")
+	}
+
+	task := args[0]
+	flags.Init("admin "+task, flag.ContinueOnError)
+	var method, path string
+	query := url.Values{}
+	switch task {
+	case "change-tld":
+		source := flags.String("source", "", "Source name, like klmanga")
+		tld := flags.String("tld", "", "New TLD, without the dot, like st")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		if *source == "" || *tld == "" {
+			return fmt.Errorf("source and tld must be provided")
+		}
+		method, path = http.MethodPatch, "/v1/admin/source_tld"
+		query.Set("source", *source)
+		query.Set("tld", *tld)
+	case "migrate":
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		method, path = http.MethodPost, "/v1/admin/migrations"
+	case "refetch-covers":
+		includeFixed := flags.Bool("include-fixed", false, "Also refetch the cover images set by the user")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		method, path = http.MethodPost, "/v1/admin/cover_imgs"
+		if *includeFixed {
+			query.Set("include_fixed", "true")
+		}
+	default:
+		flags.Usage()
+		return fmt.Errorf("unknown admin task '%s'", task)
+	}
+
+	var resp responseMessage
+	err := cli.Client.Request(method, path, query, nil, &resp)
+	if err != nil {
+		return err
+	}
+
+	return cli.Output.PrintMessage(resp.Message)
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Client is a client of the Mantium API.
+type Client struct {
+	c *http.Client
+	// APIURL is the API base URL, like http://localhost:8080.
+	APIURL string
+}
+
+// NewClient returns a new client of the API in apiURL.
+func NewClient(apiURL string) *Client {
+	return &Client{
+		APIURL: strings.TrimSuffix(apiURL, "/"),
+		// No timeout, as some routes, like the one to update the
+		// mangas metadata, can take a long time to respond.
+		c: &http.Client{},
+	}
+}
+
+// APIError is an error response of the API.
+type APIError struct {
+	Message    string
+	StatusCode int
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API returned status code %d: %s", e.StatusCode, e.Message)
+}
+
+// Request sends a request to the API path, like /v1/mangas. If body is not nil, it's sent as JSON.
+// If the response status code is not 200, an *APIError is returned. Else the response body is
+// decoded into target, if it's not nil.
+func (c *Client) Request(method, path string, query url.Values, body, target any) error {
+	errorContext := fmt.Sprintf("error while doing %s request to %s", method, path)
+
+	reqURL := c.APIURL + path
+	if len(query) > 0 {
+		reqURL += "?" + query.Encode()
+	}
+
+	var reqBody io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("%s: %w", errorContext, err)
+		}
+		reqBody = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequest(method, reqURL, reqBody)
+	if err != nil {
+		return fmt.Errorf("%s: %w", errorContext, err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.c.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", errorContext, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("%s: %w", errorContext, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		apiErr := &APIError{StatusCode: resp.StatusCode, Message: string(respBody)}
+		var message responseMessage
+		if json.Unmarshal(respBody, &message) == nil && message.Message != "" {
+			apiErr.Message = message.Message
+		}
+		return fmt.Errorf("%s: %w", errorContext, apiErr)
+	}
+
+	if target != nil {
+		err = json.Unmarshal(respBody, target)
+		if err != nil {
+			return fmt.Errorf("%s: error decoding response body: %w", errorContext, err)
+		}
+	}
+
+	return nil
+}
+
+// responseMessage is the response of the API routes that only return a message.
+type responseMessage struct {
+	Message string `json:"message"`
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/routes"
+	"github.com/diogovalentte/mantium/api/src/sources/models"
+)
+
+var statusNames = map[manga.Status]string{
+	1: "reading",
+	2: "completed",
+	3: "on hold",
+	4: "dropped",
+	5: "plan to read",
+}
+
+func runSearch(cli *CLI, flags *flag.FlagSet, args []string) error {
+	source := flags.String("source", "", "Source to search in, like mangadex")
+	limit := flags.Int("limit", 20, "Max number of results")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	term := strings.Join(flags.Args(), " ")
+	if *source == "" || term == "" {
+		flags.Usage()
+		return fmt.Errorf("source and term must be provided")
+	}
+
+	var resp map[string][]*models.MangaSearchResult
+	err := cli.Client.Request(http.MethodPost, "/v1/mangas/search", nil, routes.SearchMangaRequest{Source: *source, Term: term, Limit: *limit}, &resp)
+	if err != nil {
+		return err
+	}
+
+	mangas := resp["mangas"]
+	rows := make([][]string, 0, len(mangas))
+	for _, m := range mangas {
+		rows = append(rows, []string{m.Name, m.LastChapter, m.Status, m.URL})
+	}
+
+	return cli.Output.Print(mangas, []string{"NAME", "LAST CHAPTER", "STATUS", "URL"}, rows)
+}
+
+func runAdd(cli *CLI, flags *flag.FlagSet, args []string) error {
+	status := flags.Int("status", 1, "Status: 1=reading, 2=completed, 3=on hold, 4=dropped, 5=plan to read")
+	internalID := flags.String("internal-id", "", "Manga internal ID, needed by some sources, like mangaplus")
+	lastReadChapter := flags.String("last-read-chapter", "", "Last read chapter")
+	lastReadChapterURL := flags.String("last-read-chapter-url", "", "Last read chapter URL")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("manga URL must be provided")
+	}
+
+	request := routes.AddMangaRequest{
+		URL:                flags.Arg(0),
+		MangaInternalID:    *internalID,
+		LastReadChapter:    *lastReadChapter,
+		LastReadChapterURL: *lastReadChapterURL,
+		Status:             *status,
+	}
+	var resp responseMessage
+	err := cli.Client.Request(http.MethodPost, "/v1/multimanga", nil, request, &resp)
+	if err != nil {
+		return err
+	}
+
+	return cli.Output.PrintMessage(resp.Message)
+}
+
+func runList(cli *CLI, flags *flag.FlagSet, args []string) error {
+	unread := flags.Bool("unread", false, "Only list the mangas with unread chapters")
+	return listMangas(cli, flags, args, unread)
+}
+
+func runUnread(cli *CLI, flags *flag.FlagSet, args []string) error {
+	unread := true
+	return listMangas(cli, flags, args, &unread)
+}
+
+func listMangas(cli *CLI, flags *flag.FlagSet, args []string, unread *bool) error {
+	statusFilterStr := flags.String("status", "", "Comma separated list of statuses: 1=reading, 2=completed, 3=on hold, 4=dropped, 5=plan to read")
+	tag := flags.String("tag", "", "Only list the mangas with the tag")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	statusFilter, err := parseStatusFilter(*statusFilterStr)
+	if err != nil {
+		return err
+	}
+
+	var resp map[string][]*manga.Manga
+	err = cli.Client.Request(http.MethodGet, "/v1/mangas", nil, nil, &resp)
+	if err != nil {
+		return err
+	}
+
+	mangas := []*manga.Manga{}
+	rows := [][]string{}
+	for _, m := range resp["mangas"] {
+		if *unread && m.UnreadChapters == 0 {
+			continue
+		}
+		if len(statusFilter) > 0 && !slices.Contains(statusFilter, m.Status) {
+			continue
+		}
+		if *tag != "" && !slices.Contains(m.Tags, strings.ToLower(*tag)) {
+			continue
+		}
+		// The images are not useful in the CLI and make the JSON output huge
+		m.CoverImg = nil
+		mangas = append(mangas, m)
+
+		multimangaID := "-"
+		if m.MultiMangaID != 0 {
+			multimangaID = strconv.Itoa(int(m.MultiMangaID))
+		}
+		rows = append(rows, []string{multimangaID, m.Name, m.Source, statusNames[m.Status], chapterString(m.LastReadChapter), chapterString(m.LastReleasedChapter), strconv.Itoa(m.UnreadChapters)})
+	}
+
+	return cli.Output.Print(mangas, []string{"MULTIMANGA ID", "NAME", "SOURCE", "STATUS", "LAST READ", "LAST RELEASED", "UNREAD"}, rows)
+}
+
+func runMarkRead(cli *CLI, flags *flag.FlagSet, args []string) error {
+	mangaID := flags.Int("manga-id", 0, "ID of the multimanga's manga the chapter is from. Defaults to the current manga")
+	chapter := flags.String("chapter", "", "Chapter to set as the last read chapter. Defaults to the last released chapter")
+	chapterURL := flags.String("chapter-url", "", "URL of the chapter to set as the last read chapter")
+	unmarkAfter := flags.Bool("unmark-after", false, "Mark the chapters after the chapter as unread")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("multimanga ID must be provided")
+	}
+	multimangaID, err := strconv.Atoi(flags.Arg(0))
+	if err != nil {
+		return fmt.Errorf("multimanga ID must be a number")
+	}
+
+	if *mangaID == 0 {
+		multimanga, err := getMultiManga(cli.Client, manga.ID(multimangaID))
+		if err != nil {
+			return err
+		}
+		*mangaID = int(multimanga.CurrentManga.ID)
+	}
+
+	query := url.Values{}
+	query.Set("id", strconv.Itoa(multimangaID))
+	query.Set("manga_id", strconv.Itoa(*mangaID))
+	if *unmarkAfter {
+		query.Set("unmark_after", "true")
+	}
+	var resp responseMessage
+	err = cli.Client.Request(http.MethodPatch, "/v1/multimanga/last_read_chapter", query, routes.UpdateMangaChapterRequest{Chapter: *chapter, ChapterURL: *chapterURL}, &resp)
+	if err != nil {
+		return err
+	}
+
+	return cli.Output.PrintMessage(resp.Message)
+}
+
+func runUpdateMetadata(cli *CLI, flags *flag.FlagSet, args []string) error {
+	notify := flags.Bool("notify", false, "Notify the new released chapters")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	if *notify {
+		query.Set("notify", "true")
+	}
+	var resp responseMessage
+	err := cli.Client.Request(http.MethodPatch, "/v1/mangas/metadata", query, nil, &resp)
+	if err != nil {
+		return err
+	}
+
+	return cli.Output.PrintMessage(resp.Message)
+}
+
+func getMultiManga(client *Client, multimangaID manga.ID) (*manga.MultiManga, error) {
+	resp, err := getMultiMangaResponse(client, multimangaID)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.MultiManga, nil
+}
+
+// multimangaResponse is the response of the get multimanga route.
+type multimangaResponse struct {
+	MultiManga  *manga.MultiManga  `json:"multimanga"`
+	ExternalIDs *manga.ExternalIDs `json:"external_ids"`
+}
+
+func getMultiMangaResponse(client *Client, multimangaID manga.ID) (*multimangaResponse, error) {
+	query := url.Values{}
+	query.Set("id", strconv.Itoa(int(multimangaID)))
+	var resp multimangaResponse
+	err := client.Request(http.MethodGet, "/v1/multimanga", query, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if resp.MultiManga == nil {
+		return nil, fmt.Errorf("multimanga %d not found in response", multimangaID)
+	}
+
+	return &resp, nil
+}
+
+func parseStatusFilter(statusFilterStr string) ([]manga.Status, error) {
+	var statusFilter []manga.Status
+	if statusFilterStr == "" {
+		return statusFilter, nil
+	}
+	for _, statusStr := range strings.Split(statusFilterStr, ",") {
+		status, err := strconv.Atoi(strings.TrimSpace(statusStr))
+		if err != nil || status < 1 || status > 5 {
+			return nil, fmt.Errorf("status must be a list of numbers from 1 to 5")
+		}
+		statusFilter = append(statusFilter, manga.Status(status))
+	}
+
+	return statusFilter, nil
+}
+
+func chapterString(chapter *manga.Chapter) string {
+	if chapter == nil {
+		return "-"
+	}
+
+	return chapter.Chapter
+}
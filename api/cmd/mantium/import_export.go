@@ -0,0 +1,336 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/routes"
+)
+
+// ExportedMultiManga is a multimanga in the export file.
+type ExportedMultiManga struct {
+	// LastReadMangaURL is the URL of the manga the last read chapter is from.
+	LastReadMangaURL   string           `json:"last_read_manga_url,omitempty"`
+	LastReadChapter    string           `json:"last_read_chapter,omitempty"`
+	LastReadChapterURL string           `json:"last_read_chapter_url,omitempty"`
+	Mangas             []*ExportedManga `json:"mangas"`
+	Tags               []string         `json:"tags"`
+	// ExternalIDs are the multimanga IDs in MangaUpdates, AniList, and MyAnimeList.
+	// They're used to skip the multimangas already in Mantium when importing.
+	ExternalIDs *routes.SetMultiMangaExternalIDsRequest `json:"external_ids,omitempty"`
+	Status      manga.Status                            `json:"status"`
+}
+
+// ExportedManga is a multimanga's manga in the export file.
+type ExportedManga struct {
+	Name       string `json:"name"`
+	URL        string `json:"url"`
+	InternalID string `json:"internal_id,omitempty"`
+}
+
+func runExport(cli *CLI, flags *flag.FlagSet, args []string) error {
+	filePath := flags.String("file", "", "File to write the export to. Defaults to stdout")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var resp map[string][]*manga.MultiManga
+	err := cli.Client.Request(http.MethodGet, "/v1/multimangas", nil, nil, &resp)
+	if err != nil {
+		return err
+	}
+
+	exported := make([]*ExportedMultiManga, 0, len(resp["multimangas"]))
+	for _, mm := range resp["multimangas"] {
+		// The multimangas route returns only the current manga
+		resp, err := getMultiMangaResponse(cli.Client, mm.ID)
+		if err != nil {
+			return err
+		}
+		exported = append(exported, exportMultiManga(resp.MultiManga, resp.ExternalIDs))
+	}
+
+	var w io.Writer = cli.Output.w
+	if *filePath != "" {
+		file, err := os.Create(*filePath)
+		if err != nil {
+			return fmt.Errorf("error creating export file: %w", err)
+		}
+		defer file.Close()
+		w = file
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	err = encoder.Encode(exported)
+	if err != nil {
+		return fmt.Errorf("error writing export: %w", err)
+	}
+
+	if *filePath != "" {
+		return cli.Output.PrintMessage(fmt.Sprintf("%d multimangas exported to %s", len(exported), *filePath))
+	}
+
+	return nil
+}
+
+func exportMultiManga(multimanga *manga.MultiManga, externalIDs *manga.ExternalIDs) *ExportedMultiManga {
+	exported := &ExportedMultiManga{
+		Status: multimanga.Status,
+		Tags:   multimanga.Tags,
+	}
+	if exported.Tags == nil {
+		exported.Tags = []string{}
+	}
+	if !externalIDs.IsEmpty() {
+		exported.ExternalIDs = &routes.SetMultiMangaExternalIDsRequest{
+			MangaUpdatesID: externalIDs.MangaUpdatesID,
+			AniListID:      externalIDs.AniListID,
+			MALID:          externalIDs.MALID,
+		}
+	}
+	for _, m := range multimanga.Mangas {
+		exported.Mangas = append(exported.Mangas, &ExportedManga{Name: m.Name, URL: m.URL, InternalID: m.InternalID})
+	}
+
+	if multimanga.LastReadChapter != nil {
+		exported.LastReadChapter = multimanga.LastReadChapter.Chapter
+		exported.LastReadChapterURL = multimanga.LastReadChapter.URL
+		// The chapter is got from the source of the manga it's from when importing
+		chapterHost := getURLHost(multimanga.LastReadChapter.URL)
+		for _, m := range multimanga.Mangas {
+			if chapterHost != "" && getURLHost(m.URL) == chapterHost {
+				exported.LastReadMangaURL = m.URL
+				break
+			}
+		}
+	}
+
+	return exported
+}
+
+// ImportResult is the result of importing a multimanga.
+type ImportResult struct {
+	Name  string `json:"name"`
+	Error string `json:"error,omitempty"`
+	// SkippedDuplicateOf is the ID of the multimanga already in Mantium with the same external IDs.
+	SkippedDuplicateOf manga.ID `json:"skipped_duplicate_of,omitempty"`
+}
+
+func runImport(cli *CLI, flags *flag.FlagSet, args []string) error {
+	filePath := flags.String("file", "", "File to read the export from. Defaults to stdin")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	r := cli.Stdin
+	if *filePath != "" {
+		file, err := os.Open(*filePath)
+		if err != nil {
+			return fmt.Errorf("error opening import file: %w", err)
+		}
+		defer file.Close()
+		r = file
+	}
+	var multimangas []*ExportedMultiManga
+	err := json.NewDecoder(r).Decode(&multimangas)
+	if err != nil {
+		return fmt.Errorf("error reading import: %w", err)
+	}
+
+	var existingIDs *externalIDsIndex
+	for _, multimanga := range multimangas {
+		if multimanga.ExternalIDs != nil {
+			existingIDs, err = getExternalIDsIndex(cli.Client)
+			if err != nil {
+				return err
+			}
+			break
+		}
+	}
+
+	results := make([]*ImportResult, 0, len(multimangas))
+	rows := make([][]string, 0, len(multimangas))
+	var failed int
+	for _, multimanga := range multimangas {
+		result := &ImportResult{}
+		if len(multimanga.Mangas) > 0 {
+			result.Name = multimanga.Mangas[0].Name
+		}
+		if multimangaID, ok := existingIDs.find(multimanga.ExternalIDs); ok {
+			result.SkippedDuplicateOf = multimangaID
+			results = append(results, result)
+			rows = append(rows, []string{result.Name, fmt.Sprintf("skipped: same work as multimanga %d", multimangaID)})
+			continue
+		}
+		// It continues with the next multimanga if one fails, like when it's already in Mantium
+		multimangaID, err := importMultiManga(cli.Client, multimanga)
+		status := "imported"
+		if err != nil {
+			result.Error = err.Error()
+			status = "error: " + err.Error()
+			failed++
+		} else {
+			existingIDs.add(multimangaID, multimanga.ExternalIDs)
+		}
+		results = append(results, result)
+		rows = append(rows, []string{result.Name, status})
+	}
+
+	err = cli.Output.Print(results, []string{"NAME", "RESULT"}, rows)
+	if err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d multimangas were not imported", failed, len(multimangas))
+	}
+
+	return nil
+}
+
+// importMultiManga adds the multimanga to Mantium and returns its ID.
+func importMultiManga(client *Client, multimanga *ExportedMultiManga) (manga.ID, error) {
+	if len(multimanga.Mangas) == 0 {
+		return 0, fmt.Errorf("multimanga has no mangas")
+	}
+
+	// The multimanga is created with the manga of the last read chapter,
+	// so the chapter is got from the right source.
+	first := multimanga.Mangas[0]
+	for _, m := range multimanga.Mangas {
+		if m.URL == multimanga.LastReadMangaURL {
+			first = m
+			break
+		}
+	}
+	request := routes.AddMangaRequest{
+		URL:             first.URL,
+		MangaInternalID: first.InternalID,
+		LastReadChapter: multimanga.LastReadChapter,
+		Status:          int(multimanga.Status),
+	}
+	// Else the chapter URL is from a source not in the multimanga anymore
+	if multimanga.LastReadMangaURL != "" {
+		request.LastReadChapterURL = multimanga.LastReadChapterURL
+	}
+	err := client.Request(http.MethodPost, "/v1/multimanga", nil, request, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	query := url.Values{}
+	query.Set("url", first.URL)
+	var resp map[string]*manga.Manga
+	err = client.Request(http.MethodGet, "/v1/manga", query, nil, &resp)
+	if err != nil {
+		return 0, err
+	}
+	if resp["manga"] == nil {
+		return 0, fmt.Errorf("manga '%s' not found in response", first.URL)
+	}
+	multimangaID := resp["manga"].MultiMangaID
+	query = url.Values{}
+	query.Set("id", strconv.Itoa(int(multimangaID)))
+
+	for _, m := range multimanga.Mangas {
+		if m == first {
+			continue
+		}
+		err = client.Request(http.MethodPost, "/v1/multimanga/manga", query, routes.AddMangaToMultiMangaRequest{MangaURL: m.URL, MangaInternalID: m.InternalID}, nil)
+		if err != nil {
+			return multimangaID, err
+		}
+	}
+
+	if len(multimanga.Tags) > 0 {
+		err = client.Request(http.MethodPatch, "/v1/multimanga/tags", query, routes.UpdateTagsRequest{Tags: multimanga.Tags}, nil)
+		if err != nil {
+			return multimangaID, err
+		}
+	}
+
+	if multimanga.ExternalIDs != nil {
+		err = client.Request(http.MethodPut, "/v1/multimanga/external_ids", query, multimanga.ExternalIDs, nil)
+		if err != nil {
+			return multimangaID, err
+		}
+	}
+
+	return multimangaID, nil
+}
+
+// externalIDsIndex has the multimangas IDs by their external IDs, like "anilist_id: 30002".
+type externalIDsIndex struct {
+	multimangas map[string]manga.ID
+}
+
+func getExternalIDsIndex(client *Client) (*externalIDsIndex, error) {
+	var resp map[string][]*manga.ExternalIDs
+	err := client.Request(http.MethodGet, "/v1/multimangas/external_ids", nil, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	index := &externalIDsIndex{multimangas: map[string]manga.ID{}}
+	for _, ids := range resp["external_ids"] {
+		index.add(ids.MultiMangaID, &routes.SetMultiMangaExternalIDsRequest{MangaUpdatesID: ids.MangaUpdatesID, AniListID: ids.AniListID, MALID: ids.MALID})
+	}
+
+	return index, nil
+}
+
+func (index *externalIDsIndex) add(multimangaID manga.ID, ids *routes.SetMultiMangaExternalIDsRequest) {
+	if index == nil {
+		return
+	}
+	for _, key := range getExternalIDsKeys(ids) {
+		index.multimangas[key] = multimangaID
+	}
+}
+
+// find returns the ID of the multimanga with any of the external IDs.
+func (index *externalIDsIndex) find(ids *routes.SetMultiMangaExternalIDsRequest) (manga.ID, bool) {
+	if index == nil {
+		return 0, false
+	}
+	for _, key := range getExternalIDsKeys(ids) {
+		if multimangaID, ok := index.multimangas[key]; ok {
+			return multimangaID, true
+		}
+	}
+
+	return 0, false
+}
+
+func getExternalIDsKeys(ids *routes.SetMultiMangaExternalIDsRequest) []string {
+	if ids == nil {
+		return nil
+	}
+	keys := []string{}
+	if ids.MangaUpdatesID != "" {
+		keys = append(keys, "mangaupdates_id: "+ids.MangaUpdatesID)
+	}
+	if ids.AniListID != "" {
+		keys = append(keys, "anilist_id: "+ids.AniListID)
+	}
+	if ids.MALID != "" {
+		keys = append(keys, "mal_id: "+ids.MALID)
+	}
+
+	return keys
+}
+
+func getURLHost(rawURL string) string {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+
+	return parsedURL.Hostname()
+}
@@ -0,0 +1,116 @@
+// Package main implements the mantium command, a command-line client of the Mantium API.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const defaultAPIURL = "http://localhost:8080"
+
+// CLI holds what the commands need to run.
+type CLI struct {
+	Client *Client
+	Output *Output
+	// Stdin is where the commands read the input from, like the import command.
+	Stdin io.Reader
+}
+
+type command struct {
+	// run runs the command with its flag set, which has the command usage.
+	run         func(cli *CLI, flags *flag.FlagSet, args []string) error
+	name        string
+	usage       string
+	description string
+}
+
+var commands = []*command{
+	{name: "search", usage: "search -source <source> [-limit <n>] <term>", description: "Search mangas in a source", run: runSearch},
+	{name: "add", usage: "add [-status <status>] [-internal-id <id>] [-last-read-chapter <chapter>] [-last-read-chapter-url <url>] <manga url>", description: "Add a manga as a new multimanga", run: runAdd},
+	{name: "list", usage: "list [-unread] [-status <statuses>] [-tag <tag>]", description: "List the multimangas' current manga and the custom mangas", run: runList},
+	{name: "unread", usage: "unread [-status <statuses>] [-tag <tag>]", description: "List the mangas with unread chapters", run: runUnread},
+	{name: "mark-read", usage: "mark-read [-manga-id <id>] [-chapter <chapter>] [-chapter-url <url>] <multimanga id>", description: "Set a multimanga last read chapter, the last released chapter by default", run: runMarkRead},
+	{name: "update-metadata", usage: "update-metadata [-notify]", description: "Update the mangas metadata, like the last released chapter", run: runUpdateMetadata},
+	{name: "export", usage: "export [-file <path>]", description: "Export the multimangas to a JSON file, or to stdout", run: runExport},
+	{name: "import", usage: "import [-file <path>]", description: "Import the multimangas from a JSON file created by export, or from stdin", run: runImport},
+	{name: "admin", usage: "admin <change-tld|migrate|refetch-covers> [flags]", description: "Run admin tasks, like changing a source TLD", run: runAdmin},
+}
+
+func main() {
+	err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	if err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("mantium", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	apiURL := flags.String("api-url", getEnv("MANTIUM_API_URL", defaultAPIURL), "Mantium API URL. Defaults to the MANTIUM_API_URL environment variable")
+	outputFormat := flags.String("output", outputTable, "Output format: table or json")
+	flags.Usage = func() { printUsage(flags, stderr) }
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *outputFormat != outputTable && *outputFormat != outputJSON {
+		return fmt.Errorf("output must be table or json")
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return flag.ErrHelp
+	}
+
+	cli := &CLI{
+		Client: NewClient(*apiURL),
+		Output: &Output{w: stdout, Format: *outputFormat},
+		Stdin:  stdin,
+	}
+
+	name := flags.Arg(0)
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd.run(cli, newFlagSet(cmd.name, cmd.usage, stderr), flags.Args()[1:])
+		}
+	}
+	flags.Usage()
+
+	return fmt.Errorf("unknown command '%s'", name)
+}
+
+func printUsage(flags *flag.FlagSet, w io.Writer) {
+	fmt.Fprintln(w, "Usage: mantium [-api-url <url>] [-output table|json] <command> [flags] [args]")
+	fmt.Fprintln(w, "\nCommands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-16s %s\n", cmd.name, cmd.description)
+		fmt.Fprintf(w, "  %-16s   mantium %s\n", "", cmd.usage)
+	}
+	fmt.Fprintln(w, "\nFlags:")
+	flags.PrintDefaults()
+}
+
+// newFlagSet returns the flag set of a command.
+func newFlagSet(name, usage string, output io.Writer) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(output)
+	flags.Usage = func() {
+		fmt.Fprintf(output, "Usage: mantium %s\n", usage)
+		flags.PrintDefaults()
+	}
+
+	return flags
+}
+
+func getEnv(name, defaultValue string) string {
+	if value := strings.TrimSpace(os.Getenv(name)); value != "" {
+		return value
+	}
+
+	return defaultValue
+}
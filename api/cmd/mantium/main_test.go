@@ -0,0 +1,234 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/diogovalentte/mantium/api/src/manga"
+)
+
+var multimanga = &manga.MultiManga{
+	ID:              1,
+	Status:          1,
+	Tags:            []string{"weekly"},
+	LastReadChapter: &manga.Chapter{Chapter: "100", URL: "https://comick.io/comic/berserk/abc-chapter-100-en"},
+	CurrentManga:    &manga.Manga{ID: 2, Name: "Berserk", URL: "https://mangadex.org/title/berserk", Source: "mangadex"},
+	Mangas: []*manga.Manga{
+		{ID: 2, Name: "Berserk", URL: "https://mangadex.org/title/berserk", Source: "mangadex"},
+		{ID: 3, Name: "Berserk", URL: "https://comick.io/comic/berserk", Source: "comick"},
+	},
+}
+
+// request is a request received by the fake API.
+type request struct {
+	Body   map[string]any
+	Method string
+	Path   string
+	Query  string
+}
+
+func newFakeAPI(t *testing.T, requests *[]*request) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received := &request{Method: r.Method, Path: r.URL.Path, Query: r.URL.RawQuery}
+		body, _ := io.ReadAll(r.Body)
+		if len(body) > 0 {
+			if err := json.Unmarshal(body, &received.Body); err != nil {
+				t.Errorf("invalid request body: %s", err)
+			}
+		}
+		*requests = append(*requests, received)
+
+		var resp any = responseMessage{Message: "OK"}
+		switch r.Method + " " + r.URL.Path {
+		case "GET /v1/mangas":
+			resp = map[string][]*manga.Manga{"mangas": {
+				{Name: "Berserk", Source: "mangadex", Status: 1, MultiMangaID: 1, UnreadChapters: 2, Tags: []string{"weekly"}, LastReleasedChapter: &manga.Chapter{Chapter: "102"}, LastReadChapter: &manga.Chapter{Chapter: "100"}},
+				{Name: "Dandadan", Source: "comick", Status: 5, MultiMangaID: 4},
+			}}
+		case "GET /v1/multimangas":
+			resp = map[string][]*manga.MultiManga{"multimangas": {{ID: 1}}}
+		case "GET /v1/multimanga":
+			resp = map[string]any{
+				"multimanga":   multimanga,
+				"relations":    []*manga.Relation{{MangaID: 2, Type: "sequel", Name: "Berserk 2", URL: "https://mangadex.org/title/berserk-2"}},
+				"external_ids": &manga.ExternalIDs{MultiMangaID: 1, MangaUpdatesID: "55099564912", AniListID: "30002"},
+			}
+		case "GET /v1/multimangas/external_ids":
+			resp = map[string][]*manga.ExternalIDs{"external_ids": {{MultiMangaID: 9, MALID: "2"}}}
+		case "GET /v1/manga":
+			resp = map[string]*manga.Manga{"manga": {ID: 3, MultiMangaID: 7}}
+		case "PATCH /v1/admin/source_tld":
+			w.WriteHeader(http.StatusBadRequest)
+			resp = responseMessage{Message: "source 'x' not found"}
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+}
+
+func runCLI(t *testing.T, apiURL, stdin string, args ...string) (string, error) {
+	t.Helper()
+	var stdout bytes.Buffer
+	err := run(append([]string{"-api-url", apiURL}, args...), strings.NewReader(stdin), &stdout, io.Discard)
+	return stdout.String(), err
+}
+
+func TestList(t *testing.T) {
+	var requests []*request
+	server := newFakeAPI(t, &requests)
+	defer server.Close()
+
+	t.Run("Should list the unread mangas as a table", func(t *testing.T) {
+		output, err := runCLI(t, server.URL, "", "unread")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(output, "Berserk") || strings.Contains(output, "Dandadan") {
+			t.Fatalf("expected only Berserk to be listed, got:\n%s", output)
+		}
+		if !strings.Contains(output, "MULTIMANGA ID") {
+			t.Fatalf("expected a table header, got:\n%s", output)
+		}
+	})
+	t.Run("Should filter by status as JSON", func(t *testing.T) {
+		output, err := runCLI(t, server.URL, "", "-output", "json", "list", "-status", "5")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var mangas []*manga.Manga
+		if err := json.Unmarshal([]byte(output), &mangas); err != nil {
+			t.Fatal(err)
+		}
+		if len(mangas) != 1 || mangas[0].Name != "Dandadan" {
+			t.Fatalf("expected only Dandadan to be listed, got %v", mangas)
+		}
+	})
+}
+
+func TestMarkRead(t *testing.T) {
+	var requests []*request
+	server := newFakeAPI(t, &requests)
+	defer server.Close()
+
+	_, err := runCLI(t, server.URL, "", "mark-read", "-chapter", "101", "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	last := requests[len(requests)-1]
+	if last.Method != http.MethodPatch || last.Path != "/v1/multimanga/last_read_chapter" {
+		t.Fatalf("expected the last read chapter to be updated, got %s %s", last.Method, last.Path)
+	}
+	if last.Query != "id=1&manga_id=2" {
+		t.Fatalf("expected the current manga to be used, got query %s", last.Query)
+	}
+	if last.Body["chapter"] != "101" {
+		t.Fatalf("expected chapter 101, got %v", last.Body["chapter"])
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	var requests []*request
+	server := newFakeAPI(t, &requests)
+	defer server.Close()
+
+	exported, err := runCLI(t, server.URL, "", "export")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var multimangas []*ExportedMultiManga
+	if err := json.Unmarshal([]byte(exported), &multimangas); err != nil {
+		t.Fatal(err)
+	}
+	if len(multimangas) != 1 || len(multimangas[0].Mangas) != 2 {
+		t.Fatalf("expected 1 multimanga with 2 mangas, got %v", multimangas)
+	}
+	if multimangas[0].LastReadMangaURL != "https://comick.io/comic/berserk" {
+		t.Fatalf("expected the last read chapter to be from the comick manga, got %s", multimangas[0].LastReadMangaURL)
+	}
+	if ids := multimangas[0].ExternalIDs; ids == nil || ids.MangaUpdatesID != "55099564912" || ids.AniListID != "30002" || ids.MALID != "" {
+		t.Fatalf("expected the external IDs to be exported, got %v", multimangas[0].ExternalIDs)
+	}
+
+	requests = nil
+	_, err = runCLI(t, server.URL, exported, "import")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"GET /v1/multimangas/external_ids",
+		"POST /v1/multimanga",
+		"GET /v1/manga",
+		"POST /v1/multimanga/manga",
+		"PATCH /v1/multimanga/tags",
+		"PUT /v1/multimanga/external_ids",
+	}
+	if len(requests) != len(expected) {
+		t.Fatalf("expected %d requests, got %d", len(expected), len(requests))
+	}
+	for i, req := range requests {
+		if req.Method+" "+req.Path != expected[i] {
+			t.Fatalf("expected request %d to be %s, got %s %s", i, expected[i], req.Method, req.Path)
+		}
+	}
+	if requests[1].Body["url"] != "https://comick.io/comic/berserk" || requests[1].Body["last_read_chapter_url"] == nil {
+		t.Fatalf("expected the multimanga to be created with the comick manga and the chapter URL, got %v", requests[1].Body)
+	}
+	if requests[3].Query != "id=7" || requests[3].Body["manga_url"] != "https://mangadex.org/title/berserk" {
+		t.Fatalf("expected the mangadex manga to be added to multimanga 7, got %s %v", requests[3].Query, requests[3].Body)
+	}
+	if requests[5].Query != "id=7" || requests[5].Body["mangaupdates_id"] != "55099564912" || requests[5].Body["anilist_id"] != "30002" {
+		t.Fatalf("expected the external IDs to be set in multimanga 7, got %s %v", requests[5].Query, requests[5].Body)
+	}
+}
+
+func TestImportSkipsSameExternalIDs(t *testing.T) {
+	var requests []*request
+	server := newFakeAPI(t, &requests)
+	defer server.Close()
+
+	// The first multimanga has the same MAL ID as multimanga 9, and the second
+	// has the same AniList ID as the first one imported before it
+	exported := `[
+		{"mangas": [{"name": "Berserk", "url": "https://mangadex.org/title/berserk"}], "tags": [], "status": 1, "external_ids": {"mal_id": "2"}},
+		{"mangas": [{"name": "Vagabond", "url": "https://mangadex.org/title/vagabond"}], "tags": [], "status": 1, "external_ids": {"anilist_id": "20"}},
+		{"mangas": [{"name": "Vagabond", "url": "https://comick.io/comic/vagabond"}], "tags": [], "status": 1, "external_ids": {"anilist_id": "20"}}
+	]`
+	output, err := runCLI(t, server.URL, exported, "-output", "json", "import")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var results []*ImportResult
+	if err := json.Unmarshal([]byte(output), &results); err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 3 || results[0].SkippedDuplicateOf != 9 || results[1].SkippedDuplicateOf != 0 || results[2].SkippedDuplicateOf != 7 {
+		t.Fatalf("expected the first and last multimangas to be skipped, got %v", output)
+	}
+	var created int
+	for _, req := range requests {
+		if req.Method+" "+req.Path == "POST /v1/multimanga" {
+			created++
+		}
+	}
+	if created != 1 {
+		t.Fatalf("expected 1 multimanga to be created, got %d", created)
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	var requests []*request
+	server := newFakeAPI(t, &requests)
+	defer server.Close()
+
+	_, err := runCLI(t, server.URL, "", "admin", "change-tld", "-source", "x", "-tld", "io")
+	if err == nil || !strings.Contains(err.Error(), "source 'x' not found") {
+		t.Fatalf("expected the API error message, got %v", err)
+	}
+}
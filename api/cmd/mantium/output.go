@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// Output writes the commands' results as a table or JSON.
+type Output struct {
+	w      io.Writer
+	Format string
+}
+
+// Print writes v as indented JSON, or the headers and rows as a table.
+func (o *Output) Print(v any, headers []string, rows [][]string) error {
+	if o.Format == outputJSON {
+		return o.printJSON(v)
+	}
+
+	tw := tabwriter.NewWriter(o.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+
+	return tw.Flush()
+}
+
+// PrintMessage writes the message, or a JSON object with the message.
+func (o *Output) PrintMessage(message string) error {
+	if o.Format == outputJSON {
+		return o.printJSON(responseMessage{Message: message})
+	}
+
+	_, err := fmt.Fprintln(o.w, message)
+	return err
+}
+
+func (o *Output) printJSON(v any) error {
+	encoder := json.NewEncoder(o.w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
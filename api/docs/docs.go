@@ -15,25 +15,21 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/custom_manga": {
-            "post": {
-                "description": "Inserts a custom manga into the database.",
-                "consumes": [
-                    "application/json"
-                ],
+        "/actions/mark_read": {
+            "get": {
+                "description": "Sets a multimanga last read chapter to the chapter signed in the action link token. The links are created by the API and used in the iFrame and notifications, and expire after ACTION_LINKS_TTL_HOURS. GET requests (opened in the browser) return an HTML page, POST requests return JSON. If the chapter was already read, nothing changes.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/html"
                 ],
-                "summary": "Add custom manga",
+                "summary": "Mark chapter as read using an action link",
                 "parameters": [
                     {
-                        "description": "Manga data",
-                        "name": "manga",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/routes.AddCustomMangaRequest"
-                        }
+                        "type": "string",
+                        "description": "Action link token",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -44,38 +40,19 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/custom_manga/has_more_chapters": {
-            "patch": {
-                "description": "Update if a custom manga has more chapters or not.",
-                "consumes": [
-                    "application/json"
-                ],
+            },
+            "post": {
+                "description": "Sets a multimanga last read chapter to the chapter signed in the action link token. The links are created by the API and used in the iFrame and notifications, and expire after ACTION_LINKS_TTL_HOURS. GET requests (opened in the browser) return an HTML page, POST requests return JSON. If the chapter was already read, nothing changes.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/html"
                 ],
-                "summary": "Update custom manga no more chapters",
+                "summary": "Mark chapter as read using an action link",
                 "parameters": [
-                    {
-                        "type": "integer",
-                        "example": 1,
-                        "description": "Manga ID",
-                        "name": "id",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
-                        "example": "\"https://mangadex.org/title/1/one-piece\"",
-                        "description": "Manga current URL",
-                        "name": "url",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "example": false,
-                        "description": "Manga has more chapters",
-                        "name": "has_more_chapters",
+                        "description": "Action link token",
+                        "name": "token",
                         "in": "query",
                         "required": true
                     }
@@ -90,40 +67,20 @@ const docTemplate = `{
                 }
             }
         },
-        "/dashboard/configs": {
-            "get": {
-                "description": "Returns the dashboard configs",
-                "produces": [
-                    "application/json"
-                ],
-                "summary": "Get the dashboard configs",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/config.DashboardConfigs"
-                        }
-                    }
-                }
-            },
+        "/admin/cover_imgs": {
             "post": {
-                "description": "Update the dashboard configs in the DB. Cannot update version.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Gets the cover image of all multimangas' mangas from their source again. Custom mangas and mangas with a cover image set by the user are ignored. If it fails to get a manga cover image, it will continue with the next manga.",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Update dashboard configs",
+                "summary": "Refetch cover images",
                 "parameters": [
                     {
-                        "description": "Dashboard configs",
-                        "name": "configs",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/config.DashboardConfigs"
-                        }
+                        "type": "boolean",
+                        "example": true,
+                        "description": "Also refetch the cover images set by the user",
+                        "name": "include_fixed",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -136,28 +93,48 @@ const docTemplate = `{
                 }
             }
         },
-        "/dashboard/last_background_error": {
-            "get": {
-                "description": "Returns the last error that happened in the background. Usually used to display the error in the dashboard.",
+        "/admin/migrations": {
+            "post": {
+                "description": "Creates the database tables and applies the database migrations again, like when the API starts. It can be used to fix the database after restoring a backup.",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Get the last background error",
+                "summary": "Apply migrations",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dashboard.BackgroundError"
+                            "$ref": "#/definitions/routes.responseMessage"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Deletes the last error that happened in the background. Usually used to clear the error in the dashboard.",
+            }
+        },
+        "/admin/source_tld": {
+            "patch": {
+                "description": "Changes the TLD of all mangas URLs of a source in the database, like when the source changes its domain and the old one doesn't redirect to the new one. Sources with a TLD set in the code, like klmanga, have it applied again when the API starts.",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Delete the last background error",
+                "summary": "Change source TLD",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "klmanga",
+                        "description": "Source name",
+                        "name": "source",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "st",
+                        "description": "New TLD, without the dot",
+                        "name": "tld",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -168,13 +145,27 @@ const docTemplate = `{
                 }
             }
         },
-        "/dashboard/last_update": {
-            "get": {
-                "description": "Returns the last time a resource that should trigger an update in the iframe/dashboard was updated. Usually used to update the dashboard when an event not triggered by the user occurs.",
+        "/custom_manga": {
+            "post": {
+                "description": "Inserts a custom manga into the database.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Get the last update date",
+                "summary": "Add custom manga",
+                "parameters": [
+                    {
+                        "description": "Manga data",
+                        "name": "manga",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/routes.AddCustomMangaRequest"
+                        }
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -185,30 +176,54 @@ const docTemplate = `{
                 }
             }
         },
-        "/health": {
-            "get": {
-                "description": "Returns status OK",
+        "/custom_manga/chapter": {
+            "delete": {
+                "description": "Deletes a chapter from the custom manga chapter list. If the list is empty after deleting the chapter, the custom manga's last released chapter is kept. You must provide either the manga ID or the manga URL.",
                 "produces": [
-                    "text/plain"
+                    "application/json"
+                ],
+                "summary": "Delete custom manga chapter",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Manga ID",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"https://mangadex.org/title/1/one-piece\"",
+                        "description": "Manga URL",
+                        "name": "url",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"12\"",
+                        "description": "Chapter",
+                        "name": "chapter",
+                        "in": "query",
+                        "required": true
+                    }
                 ],
-                "summary": "Health check route",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/routes.responseMessage"
                         }
                     }
                 }
             }
         },
-        "/manga": {
+        "/custom_manga/chapters": {
             "get": {
-                "description": "Gets a manga from the database. You must provide either the manga ID or the manga URL.",
+                "description": "Gets the custom manga chapter list, sorted from the first to the last chapter. You must provide either the manga ID or the manga URL.",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Get manga",
+                "summary": "Get custom manga chapters",
                 "parameters": [
                     {
                         "type": "integer",
@@ -227,30 +242,47 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "{\"manga\": mangaObj}",
+                        "description": "{\"chapters\": [chapterObj]}",
                         "schema": {
-                            "$ref": "#/definitions/manga.Manga"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/manga.CustomChapter"
+                            }
                         }
                     }
                 }
             },
             "post": {
-                "description": "Gets a manga metadata from source and inserts into the database.",
+                "description": "Adds the chapters to the custom manga chapter list, or updates them if they're already in the list. The custom manga's last released chapter is the last chapter of the list, so the custom manga has unread chapters when the last chapter of the list comes after its last read chapter. The chapter name defaults to the chapter, and the release date to now. You must provide either the manga ID or the manga URL.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Add manga",
+                "summary": "Add custom manga chapters",
                 "parameters": [
                     {
-                        "description": "Manga data",
-                        "name": "manga",
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Manga ID",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"https://mangadex.org/title/1/one-piece\"",
+                        "description": "Manga URL",
+                        "name": "url",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Chapters",
+                        "name": "chapters",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/routes.AddMangaRequest"
+                            "$ref": "#/definitions/routes.AddCustomMangaChaptersRequest"
                         }
                     }
                 ],
@@ -262,13 +294,18 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Deletes a manga from the database. You must provide either the manga ID or the manga URL.",
+            }
+        },
+        "/custom_manga/has_more_chapters": {
+            "patch": {
+                "description": "Update if a custom manga has more chapters or not. It can't be used with custom mangas with a chapter list, as they have more chapters when the last chapter of the list comes after their last read chapter.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Delete manga",
+                "summary": "Update custom manga no more chapters",
                 "parameters": [
                     {
                         "type": "integer",
@@ -280,9 +317,17 @@ const docTemplate = `{
                     {
                         "type": "string",
                         "example": "\"https://mangadex.org/title/1/one-piece\"",
-                        "description": "Manga URL",
+                        "description": "Manga current URL",
                         "name": "url",
                         "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": false,
+                        "description": "Manga has more chapters",
+                        "name": "has_more_chapters",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -295,13 +340,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/manga/chapters": {
-            "get": {
-                "description": "Get a manga chapters from the source. You must provide either the manga ID or the manga URL.",
+        "/custom_manga/tags": {
+            "patch": {
+                "description": "Replaces the custom manga tags. The tags are stored trimmed, in lower case, and sorted. An empty list removes all tags.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Get manga chapters",
+                "summary": "Update custom manga tags",
                 "parameters": [
                     {
                         "type": "integer",
@@ -318,33 +366,32 @@ const docTemplate = `{
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "example": "\"1as4fa7\"",
-                        "description": "Manga Internal ID",
-                        "name": "manga_internal_id",
-                        "in": "query"
+                        "description": "Manga tags",
+                        "name": "tags",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/routes.UpdateTagsRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "{\"chapters\": [chapterObj]}",
+                        "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/manga.Chapter"
-                            }
+                            "$ref": "#/definitions/routes.responseMessage"
                         }
                     }
                 }
             }
         },
-        "/manga/cover_img": {
-            "patch": {
-                "description": "Updates a manga/custom manga cover image in the database. You must provide either the manga ID or the manga URL. You must provide only one of the following: cover_img, cover_img_url, get_cover_img_from_source. If it's a custom manga, using get_cover_img_from_source will return an error message.",
+        "/custom_manga/watcher": {
+            "get": {
+                "description": "Gets the custom manga watcher, with the result of its last check. You must provide either the manga ID or the manga URL.",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Update manga cover image",
+                "summary": "Get custom manga watcher",
                 "parameters": [
                     {
                         "type": "integer",
@@ -359,60 +406,27 @@ const docTemplate = `{
                         "description": "Manga URL",
                         "name": "url",
                         "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "\"1as4fa7\"",
-                        "description": "Manga Internal ID",
-                        "name": "manga_internal_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "file",
-                        "description": "Manga cover image file. Remember to set the Content-Type header to 'multipart/form-data' when sending the request.",
-                        "name": "cover_img",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "example": "\"https://example.com/cover.jpg\"",
-                        "description": "Manga cover image URL",
-                        "name": "cover_img_url",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "example": true,
-                        "description": "Let Mantium fetch the cover image from the source site",
-                        "name": "get_cover_img_from_source",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "example": true,
-                        "description": "Update manga cover image to  Mantium's default cover image",
-                        "name": "use_mantium_default_img",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/routes.responseMessage"
-                        }
-                    }
-                }
-            }
-        },
-        "/manga/last_read_chapter": {
-            "patch": {
-                "description": "Updates a manga last read chapter in the database. If both ` + "`" + `chapter` + "`" + ` and ` + "`" + `chapter_url` + "`" + ` are empty strings in the body, set the last read chapter to the last released chapter for normal mangas. For custom mangas, deletes the manga's last read chapter. You can't provide only the chapter_url for custom mangas. You must provide either the manga ID or the manga URL.",
-                "produces": [
-                    "application/json"
-                ],
-                "summary": "Update manga last read chapter",
-                "parameters": [
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "{\"watcher\": watcherObj}",
+                        "schema": {
+                            "$ref": "#/definitions/manga.CustomMangaWatcher"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Sets a watcher that checks a page for new chapters of the custom manga when the mangas metadata is updated. The chapters are the text of the elements matched by the CSS selector, or the whole page text if it's empty. If the regex is set, the chapters are its matches in the text instead, or its first capturing group if it has one. The last chapter found is added to the custom manga chapter list; the first check doesn't notify. If the page has no chapters anymore, the check fails and the next chapter found is handled like in the first check. The page is checked right away, and the chapter found is returned. You must provide either the manga ID or the manga URL.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Set custom manga watcher",
+                "parameters": [
                     {
                         "type": "integer",
                         "example": 1,
@@ -428,66 +442,30 @@ const docTemplate = `{
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "example": "\"1as4fa7\"",
-                        "description": "Manga Internal ID",
-                        "name": "manga_internal_id",
-                        "in": "query"
-                    },
-                    {
-                        "description": "Chapter",
-                        "name": "chapter",
+                        "description": "Watcher",
+                        "name": "watcher",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/routes.UpdateMangaChapterRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/routes.responseMessage"
+                            "$ref": "#/definitions/routes.SetCustomMangaWatcherRequest"
                         }
                     }
-                }
-            }
-        },
-        "/manga/metadata": {
-            "get": {
-                "description": "Gets the metadata for a manga from the source site.",
-                "produces": [
-                    "application/json"
-                ],
-                "summary": "Get manga metadata",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "example": "\"https://mangadex.org/title/1/one-piece\"",
-                        "description": "Manga URL",
-                        "name": "url",
-                        "in": "query",
-                        "required": true
-                    }
                 ],
                 "responses": {
                     "200": {
-                        "description": "{\"manga\": mangaObj}",
+                        "description": "{\"message\": \"Custom manga watcher set successfully\", \"watcher\": watcherObj}",
                         "schema": {
-                            "$ref": "#/definitions/manga.Manga"
+                            "$ref": "#/definitions/manga.CustomMangaWatcher"
                         }
                     }
                 }
-            }
-        },
-        "/manga/name": {
-            "patch": {
-                "description": "Updates a manga name in the database. You must provide either the manga ID or the manga URL.",
+            },
+            "delete": {
+                "description": "Deletes the custom manga watcher. The chapters it found are kept in the chapter list. You must provide either the manga ID or the manga URL.",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Update manga name",
+                "summary": "Delete custom manga watcher",
                 "parameters": [
                     {
                         "type": "integer",
@@ -499,17 +477,9 @@ const docTemplate = `{
                     {
                         "type": "string",
                         "example": "\"https://mangadex.org/title/1/one-piece\"",
-                        "description": "Manga current URL",
+                        "description": "Manga URL",
                         "name": "url",
                         "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "\"One Piece\"",
-                        "description": "New manga name",
-                        "name": "name",
-                        "in": "query",
-                        "required": true
                     }
                 ],
                 "responses": {
@@ -522,13 +492,13 @@ const docTemplate = `{
                 }
             }
         },
-        "/manga/status": {
-            "patch": {
-                "description": "Updates a manga status in the database. You must provide either the manga ID or the manga URL.",
+        "/custom_manga/watcher/check": {
+            "post": {
+                "description": "Checks the custom manga watcher page for new chapters now. It doesn't notify. You must provide either the manga ID or the manga URL.",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Update manga status",
+                "summary": "Check custom manga watcher",
                 "parameters": [
                     {
                         "type": "integer",
@@ -543,89 +513,52 @@ const docTemplate = `{
                         "description": "Manga URL",
                         "name": "url",
                         "in": "query"
-                    },
-                    {
-                        "description": "Manga status",
-                        "name": "status",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/routes.UpdateMangaStatusRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "{\"message\": \"New chapter found\", \"new_chapter\": true, \"watcher\": watcherObj}",
                         "schema": {
-                            "$ref": "#/definitions/routes.responseMessage"
+                            "$ref": "#/definitions/manga.CustomMangaWatcher"
                         }
                     }
                 }
             }
         },
-        "/manga/turn_into_multimanga": {
-            "post": {
-                "description": "Turns a manga into a multimanga. You must provide either the manga ID or the manga URL.",
+        "/dashboard/configs": {
+            "get": {
+                "description": "Returns the dashboard configs",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Turn manga into multimanga",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "example": 1,
-                        "description": "Manga ID",
-                        "name": "id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "\"https://mangadex.org/title/1/one-piece\"",
-                        "description": "Manga URL",
-                        "name": "url",
-                        "in": "query"
-                    }
-                ],
+                "summary": "Get the dashboard configs",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/routes.responseMessage"
+                            "$ref": "#/definitions/config.DashboardConfigs"
                         }
                     }
                 }
-            }
-        },
-        "/manga/url": {
-            "patch": {
-                "description": "Updates a manga URL in the database. If URL is empty and it's a custom manga, the operation is execute, else, an error is returned. You must provide either the manga ID or the manga current URL.",
+            },
+            "post": {
+                "description": "Update the dashboard configs in the DB. Cannot update version.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Update manga URL",
+                "summary": "Update dashboard configs",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "example": 1,
-                        "description": "Manga ID",
-                        "name": "id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "\"https://mangadex.org/title/1/one-piece\"",
-                        "description": "Manga current URL",
-                        "name": "url",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "\"https://mangadex.org/title/2/two-piece\"",
-                        "description": "Manga new URL ",
-                        "name": "new_url",
-                        "in": "query",
-                        "required": true
+                        "description": "Dashboard configs",
+                        "name": "configs",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/config.DashboardConfigs"
+                        }
                     }
                 ],
                 "responses": {
@@ -638,45 +571,28 @@ const docTemplate = `{
                 }
             }
         },
-        "/mangas": {
+        "/dashboard/last_background_error": {
             "get": {
-                "description": "Gets the current manga of multimangas and all custom mangas.",
+                "description": "Returns the last error that happened in the background. Usually used to display the error in the dashboard.",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Get mangas",
+                "summary": "Get the last background error",
                 "responses": {
                     "200": {
-                        "description": "{\"mangas\": [mangaObj]}",
+                        "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/manga.Manga"
-                            }
+                            "$ref": "#/definitions/dashboard.BackgroundError"
                         }
                     }
                 }
-            }
-        },
-        "/mangas/add_to_kaizoku": {
-            "post": {
-                "description": "Add the multimangas' current manga to Kaizoku. If it fails to add a manga, it will continue with the next manga. This is a heavy operation depending on the number of mangas in the database.",
+            },
+            "delete": {
+                "description": "Deletes the last error that happened in the background. Usually used to clear the error in the dashboard.",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Add mangas to Kaizoku",
-                "parameters": [
-                    {
-                        "type": "array",
-                        "items": {
-                            "type": "integer"
-                        },
-                        "collectionFormat": "csv",
-                        "description": "Filter which mangas to add by status. 1=reading, 2=completed, 3=on hold, 4=dropped, 5=plan to read. Example: status=1,2,3,5",
-                        "name": "status",
-                        "in": "query"
-                    }
-                ],
+                "summary": "Delete the last background error",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -687,25 +603,13 @@ const docTemplate = `{
                 }
             }
         },
-        "/mangas/add_to_suwayomi": {
-            "post": {
-                "description": "Add the multimangas' current manga to Suwayomi. If it fails to add a manga, it will continue with the next manga. This is a heavy operation depending on the number of mangas in the database.",
+        "/dashboard/last_update": {
+            "get": {
+                "description": "Returns the last time a resource that should trigger an update in the iframe/dashboard was updated. Usually used to update the dashboard when an event not triggered by the user occurs.",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Add mangas to Suwayomi",
-                "parameters": [
-                    {
-                        "type": "array",
-                        "items": {
-                            "type": "integer"
-                        },
-                        "collectionFormat": "csv",
-                        "description": "Filter which mangas to add by status. 1=reading, 2=completed, 3=on hold, 4=dropped, 5=plan to read. Example: status=1,2,3,5",
-                        "name": "status",
-                        "in": "query"
-                    }
-                ],
+                "summary": "Get the last update date",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -716,76 +620,121 @@ const docTemplate = `{
                 }
             }
         },
-        "/mangas/add_to_tranga": {
-            "post": {
-                "description": "Add the multimangas' current manga to Tranga. If it fails to add a manga, it will continue with the next manga. This is a heavy operation depending on the number of mangas in the database. Currently, only MangaDex mangas can be added to Tranga, but it'll try all mangas anyway.",
+        "/feed/atom": {
+            "get": {
+                "description": "Returns an Atom 1.0 feed with the last released chapter of the mangas, newest first.",
                 "produces": [
-                    "application/json"
+                    "text/xml"
                 ],
-                "summary": "Add mangas to Tranga",
+                "summary": "Atom feed",
                 "parameters": [
                     {
-                        "type": "array",
-                        "items": {
-                            "type": "integer"
-                        },
-                        "collectionFormat": "csv",
-                        "description": "Filter which mangas to add by status. 1=reading, 2=completed, 3=on hold, 4=dropped, 5=plan to read. Example: status=1,2,3,5",
+                        "type": "string",
+                        "example": "1,2",
+                        "description": "Comma separated list of statuses of the mangas (1: reading, 2: completed, 3: on hold, 4: dropped, 5: plan to read). Defaults to all statuses.",
                         "name": "status",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "weekly",
+                        "description": "Comma separated list of tags. Only mangas with any of the tags are used.",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "mangadex",
+                        "description": "Comma separated list of sources. Only mangas from the sources are used.",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 50,
+                        "description": "Max number of items. Defaults to 50.",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": true,
+                        "description": "If true, only mangas with unread chapters are used. Defaults to false.",
+                        "name": "unread",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "https://sub.domain.com",
+                        "description": "API URL used in the feed links. Defaults to the request URL.",
+                        "name": "api_url",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Atom feed",
                         "schema": {
-                            "$ref": "#/definitions/routes.responseMessage"
+                            "type": "string"
                         }
                     }
                 }
             }
         },
-        "/mangas/iframe": {
+        "/feed/json": {
             "get": {
-                "description": "Returns an iFrame with mangas. Only mangas with unread chapters, and status reading or completed. Sort by last released chapter date.",
+                "description": "Returns a JSON Feed 1.1 with the last released chapter of the mangas, newest first.",
                 "produces": [
-                    "text/html"
+                    "application/json"
                 ],
-                "summary": "Mangas iFrame",
+                "summary": "JSON feed",
                 "parameters": [
                     {
                         "type": "string",
-                        "example": "https://sub.domain.com",
-                        "description": "API URL used by your browser. Used for the button that updates the last read chater, as your browser needs to send a request to the API to update the chapter.",
-                        "name": "api_url",
-                        "in": "query",
-                        "required": true
+                        "example": "1,2",
+                        "description": "Comma separated list of statuses of the mangas (1: reading, 2: completed, 3: on hold, 4: dropped, 5: plan to read). Defaults to all statuses.",
+                        "name": "status",
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "example": "light",
-                        "description": "IFrame theme, defaults to light. If it's different from your dashboard theme, the background turns may turn white",
-                        "name": "theme",
+                        "example": "weekly",
+                        "description": "Comma separated list of tags. Only mangas with any of the tags are used.",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "mangadex",
+                        "description": "Comma separated list of sources. Only mangas from the sources are used.",
+                        "name": "source",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "example": 5,
-                        "description": "Limits the number of items in the iFrame.",
+                        "example": 50,
+                        "description": "Max number of items. Defaults to 50.",
                         "name": "limit",
                         "in": "query"
                     },
                     {
                         "type": "boolean",
                         "example": true,
-                        "description": "If true, shows a warning in the iFrame if an error occurred in the background. Defaults to true.",
-                        "name": "showBackgroundErrorWarning",
+                        "description": "If true, only mangas with unread chapters are used. Defaults to false.",
+                        "name": "unread",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "https://sub.domain.com",
+                        "description": "API URL used in the feed links. Defaults to the request URL.",
+                        "name": "api_url",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "HTML content",
+                        "description": "JSON feed",
                         "schema": {
                             "type": "string"
                         }
@@ -793,151 +742,224 @@ const docTemplate = `{
                 }
             }
         },
-        "/mangas/metadata": {
-            "patch": {
-                "description": "Get the mangas metadata from the sources and update them in the database.",
+        "/feed/rss": {
+            "get": {
+                "description": "Returns an RSS 2.0 feed with the last released chapter of the mangas, newest first.",
                 "produces": [
-                    "application/json"
+                    "text/xml"
                 ],
-                "summary": "Update mangas metadata",
+                "summary": "RSS feed",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Notify if a new chapter was released for the manga (only of mangas with status reading or completed).",
-                        "name": "notify",
+                        "example": "1,2",
+                        "description": "Comma separated list of statuses of the mangas (1: reading, 2: completed, 3: on hold, 4: dropped, 5: plan to read). Defaults to all statuses.",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "weekly",
+                        "description": "Comma separated list of tags. Only mangas with any of the tags are used.",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "mangadex",
+                        "description": "Comma separated list of sources. Only mangas from the sources are used.",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 50,
+                        "description": "Max number of items. Defaults to 50.",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": true,
+                        "description": "If true, only mangas with unread chapters are used. Defaults to false.",
+                        "name": "unread",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "https://sub.domain.com",
+                        "description": "API URL used in the feed links. Defaults to the request URL.",
+                        "name": "api_url",
                         "in": "query"
                     }
                 ],
+                "responses": {
+                    "200": {
+                        "description": "RSS feed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Returns status OK",
+                "produces": [
+                    "text/plain"
+                ],
+                "summary": "Health check route",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/routes.responseMessage"
+                            "type": "string"
                         }
                     }
                 }
             }
         },
-        "/mangas/search": {
+        "/integrations/downloads/cleanup": {
             "post": {
-                "description": "Searches a manga in the source. You must provide the source name like \"mangadex\" and the search query.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Applies the removal policies of the download integrations (Kaizoku, Tranga, and Suwayomi) to the whole library. The \"delete\" policy action is done with the mangas in the integrations that are not in Mantium, like mangas deleted in Mantium, and the \"drop\" and \"complete\" policy actions with the mangas of dropped and completed multimangas. The policies are set by the environment variables ` + "`" + `KAIZOKU_REMOVAL_POLICY` + "`" + `, ` + "`" + `TRANGA_REMOVAL_POLICY` + "`" + `, and ` + "`" + `SUWAYOMI_REMOVAL_POLICY` + "`" + `. Be careful, the \"delete\" policy action is also done with the mangas added to the integrations outside Mantium, so use the dry-run mode first.",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Search manga",
+                "summary": "Clean up download integrations",
                 "parameters": [
                     {
-                        "description": "Search data",
-                        "name": "search",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/routes.SearchMangaRequest"
-                        }
+                        "type": "string",
+                        "example": "suwayomi",
+                        "description": "Only clean up this integration.",
+                        "name": "integration",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": true,
+                        "description": "Only return the actions that would be done.",
+                        "name": "dry_run",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "{\"mangas\": [mangaSearchResultObj]}",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "array",
-                                "items": {
-                                    "$ref": "#/definitions/models.MangaSearchResult"
-                                }
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/downloads.Removal"
                             }
                         }
                     }
                 }
             }
         },
-        "/mangas/stats": {
+        "/integrations/downloads/drift": {
             "get": {
-                "description": "Get the library stats from all multimangas and custom mangas.",
+                "description": "Compares Mantium's library with the library of the download integrations (Kaizoku, Tranga, and Suwayomi). Mantium's library is the multimangas' current manga, or all multimangas' mangas if set in the dashboard configs. The multimangas' other mangas are neither added nor reported as extra, and Kaizoku doesn't report extra mangas if it's set to try other sources. Custom mangas and mangas whose status has an action in the integration's removal policy, like dropped mangas, are ignored.",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Get library stats",
+                "summary": "Get download integrations drift",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "suwayomi",
+                        "description": "Only get the drift of this integration.",
+                        "name": "integration",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
-                        "description": "{\"property\": value}",
+                        "description": "OK",
                         "schema": {
-                            "type": "map"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/downloads.Drift"
+                            }
                         }
                     }
                 }
             }
         },
-        "/multimanga": {
-            "get": {
-                "description": "Gets a multimanga from the database.",
+        "/integrations/downloads/reconcile": {
+            "post": {
+                "description": "Adds the mangas in Mantium's library that are missing in the download integrations (Kaizoku, Tranga, and Suwayomi) to them. Mantium's library is the multimangas' current manga, or all multimangas' mangas if set in the dashboard configs. The multimangas' other mangas are neither added nor reported as extra, and Kaizoku doesn't report extra mangas if it's set to try other sources. Custom mangas and mangas whose status has an action in the integration's removal policy, like dropped mangas, are ignored. If it fails to add or remove a manga, it will continue with the next manga.",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Get multimanga",
+                "summary": "Reconcile download integrations",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "example": 1,
-                        "description": "Multimanga ID",
-                        "name": "id",
-                        "in": "query",
-                        "required": true
+                        "type": "string",
+                        "example": "suwayomi",
+                        "description": "Only reconcile this integration.",
+                        "name": "integration",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": true,
+                        "description": "Also remove the mangas that are not in Mantium from the integrations, like mangas deleted in Mantium. The downloaded chapters are kept. Be careful, it also removes the mangas added to the integrations outside Mantium.",
+                        "name": "remove",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "{\"multimanga\": multimangaObj}",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/manga.MultiManga"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/downloads.ReconcileResult"
+                            }
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Gets a manga metadata from source and inserts it as the current manga of a new multimanga into the database.",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/job": {
+            "get": {
+                "description": "Gets a background job, like to check if a manga was added to a download integration.",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Add multimanga",
+                "summary": "Get job",
                 "parameters": [
                     {
-                        "description": "Current manga data",
-                        "name": "manga",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/routes.AddMangaRequest"
-                        }
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "{\"job\": jobObj}",
                         "schema": {
-                            "$ref": "#/definitions/routes.responseMessage"
+                            "$ref": "#/definitions/jobs.Job"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Deletes a multimanga from the database.",
+            }
+        },
+        "/job/retry": {
+            "post": {
+                "description": "Runs a failed background job again now, with all its attempts again.",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Delete multimanga",
+                "summary": "Retry job",
                 "parameters": [
                     {
                         "type": "integer",
                         "example": 1,
-                        "description": "Multimanga ID",
+                        "description": "Job ID",
                         "name": "id",
                         "in": "query",
                         "required": true
@@ -945,65 +967,87 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "{\"message\": \"Job scheduled to run again\", \"job\": jobObj}",
                         "schema": {
-                            "$ref": "#/definitions/routes.responseMessage"
+                            "$ref": "#/definitions/jobs.Job"
                         }
                     }
                 }
             }
         },
-        "/multimanga/chapters": {
+        "/jobs": {
             "get": {
-                "description": "Get chapters of the current manga of a multimanga from the source.",
+                "description": "Gets the background jobs, the newest first, like the jobs that add the mangas to the download integrations. Succeeded jobs are deleted after 7 days.",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Get multimanga current manga chapters",
+                "summary": "Get jobs",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "example": "failed",
+                        "description": "Filter by status: pending, running, succeeded, or failed",
+                        "name": "status",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "example": 1,
-                        "description": "Multimanga ID",
-                        "name": "id",
-                        "in": "query",
-                        "required": true
+                        "description": "Filter by manga ID",
+                        "name": "manga_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "{\"chapters\": [chapterObj]}",
+                        "description": "{\"jobs\": [jobObj]}",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/manga.Chapter"
+                                "$ref": "#/definitions/jobs.Job"
                             }
                         }
                     }
                 }
             }
         },
-        "/multimanga/choose_current_manga": {
+        "/jobs/retry": {
+            "post": {
+                "description": "Runs all failed background jobs again now, with all their attempts again.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Retry failed jobs",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.responseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/manga": {
             "get": {
-                "description": "Check a multimanga mangas and returns which manga should be the current manga.",
+                "description": "Gets a manga from the database. You must provide either the manga ID or the manga URL.",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Choose current manga",
+                "summary": "Get manga",
                 "parameters": [
                     {
                         "type": "integer",
                         "example": 1,
-                        "description": "Multimanga ID",
+                        "description": "Manga ID",
                         "name": "id",
-                        "in": "query",
-                        "required": true
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "example": "\"1,2,3\"",
-                        "description": "Manga IDs to exclude from the check",
-                        "name": "exclude_manga_ids",
+                        "example": "\"https://mangadex.org/title/1/one-piece\"",
+                        "description": "Manga URL",
+                        "name": "url",
                         "in": "query"
                     }
                 ],
@@ -1015,42 +1059,55 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/multimanga/cover_img": {
-            "patch": {
-                "description": "Updates a multimanga cover image in the database. You must provide only one of the following: cover_img, cover_img_url, use_current_manga_cover_img.",
+            },
+            "post": {
+                "description": "Gets a manga metadata from source and inserts into the database. The manga is added to the download integrations by background jobs, whose IDs are returned; check them in the jobs routes.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Update multimanga cover image",
+                "summary": "Add manga",
+                "parameters": [
+                    {
+                        "description": "Manga data",
+                        "name": "manga",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/routes.AddMangaRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.responseMessageWithJobs"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a manga from the database. You must provide either the manga ID or the manga URL.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Delete manga",
                 "parameters": [
                     {
                         "type": "integer",
                         "example": 1,
-                        "description": "Multimanga ID",
+                        "description": "Manga ID",
                         "name": "id",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "file",
-                        "description": "Multimanga cover image file. Remember to set the Content-Type header to 'multipart/form-data' when sending the request.",
-                        "name": "cover_img",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "example": "\"https://example.com/cover.jpg\"",
-                        "description": "Multimanga cover image URL",
-                        "name": "cover_img_url",
                         "in": "query"
                     },
                     {
-                        "type": "boolean",
-                        "example": true,
-                        "description": "Use the multimanga's current manga cover image",
-                        "name": "use_current_manga_cover_img",
+                        "type": "string",
+                        "example": "\"https://mangadex.org/title/1/one-piece\"",
+                        "description": "Manga URL",
+                        "name": "url",
                         "in": "query"
                     }
                 ],
@@ -1064,113 +1121,180 @@ const docTemplate = `{
                 }
             }
         },
-        "/multimanga/last_read_chapter": {
-            "patch": {
-                "description": "Updates a multimanga last read chapter in the database. It also needs to know from which manga the chapter is from. If both ` + "`" + `chapter` + "`" + ` and ` + "`" + `chapter_url` + "`" + ` are empty strings in the body, set the last read chapter to the last released chapter in the database.",
+        "/manga/chapter_preferences": {
+            "get": {
+                "description": "Gets the manga chapter preferences. They're empty if the preferences were never set. You must provide either the manga ID or the manga URL.",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Update multimanga last read chapter",
+                "summary": "Get manga chapter preferences",
                 "parameters": [
                     {
                         "type": "integer",
                         "example": 1,
-                        "description": "Multimanga ID",
+                        "description": "Manga ID",
                         "name": "id",
-                        "in": "query",
-                        "required": true
+                        "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "example": "\"https://mangadex.org/title/1/one-piece\"",
+                        "description": "Manga URL",
+                        "name": "url",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "{\"chapter_preferences\": preferencesObj}",
+                        "schema": {
+                            "$ref": "#/definitions/manga.ChapterPreferences"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Sets the preferences used to choose between the releases of the same chapter in sources with releases in multiple languages or by multiple scanlation groups, like MangaDex and ComicK. Only releases in the languages are used, from the most to the least preferred; if empty, the source's default language is used. The release by the group with the highest priority is used, and the releases by blocked groups are ignored. If wait_hours is set, a new chapter isn't used until a preferred group releases it or the hours pass. The preferences are used to get the manga chapters and, in the next mangas metadata update, its last released chapter. You must provide either the manga ID or the manga URL.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Set manga chapter preferences",
+                "parameters": [
                     {
                         "type": "integer",
                         "example": 1,
                         "description": "Manga ID",
-                        "name": "manga_id",
-                        "in": "query",
-                        "required": true
+                        "name": "id",
+                        "in": "query"
                     },
                     {
-                        "description": "Chapter",
-                        "name": "chapter",
+                        "type": "string",
+                        "example": "\"https://mangadex.org/title/1/one-piece\"",
+                        "description": "Manga URL",
+                        "name": "url",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Chapter preferences",
+                        "name": "chapter_preferences",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/routes.UpdateMangaChapterRequest"
+                            "$ref": "#/definitions/routes.SetMangaChapterPreferencesRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "{\"message\": \"Manga chapter preferences set successfully\", \"chapter_preferences\": preferencesObj}",
                         "schema": {
-                            "$ref": "#/definitions/routes.responseMessage"
+                            "$ref": "#/definitions/manga.ChapterPreferences"
                         }
                     }
                 }
             }
         },
-        "/multimanga/manga": {
-            "post": {
-                "description": "Adds a manga to a multimanga list in the database.",
-                "consumes": [
-                    "application/json"
-                ],
+        "/manga/chapters": {
+            "get": {
+                "description": "Get a manga chapters from the source. You must provide either the manga ID or the manga URL.",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Add manga to multimanga list",
+                "summary": "Get manga chapters",
                 "parameters": [
                     {
                         "type": "integer",
                         "example": 1,
-                        "description": "Multimanga ID",
+                        "description": "Manga ID",
                         "name": "id",
-                        "in": "query",
-                        "required": true
+                        "in": "query"
                     },
                     {
-                        "description": "Manga data",
-                        "name": "manga",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/routes.AddMangaToMultiMangaRequest"
-                        }
+                        "type": "string",
+                        "example": "\"https://mangadex.org/title/1/one-piece\"",
+                        "description": "Manga URL",
+                        "name": "url",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"1as4fa7\"",
+                        "description": "Manga Internal ID",
+                        "name": "manga_internal_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "{\"chapters\": [chapterObj]}",
                         "schema": {
-                            "$ref": "#/definitions/routes.responseMessage"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/manga.Chapter"
+                            }
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Removes a manga from a multimanga list in the database.",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/manga/cover_img": {
+            "patch": {
+                "description": "Updates a manga/custom manga cover image in the database. You must provide either the manga ID or the manga URL. You must provide only one of the following: cover_img, cover_img_url, get_cover_img_from_source. If it's a custom manga, using get_cover_img_from_source will return an error message.",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Remove manga from multimanga list",
+                "summary": "Update manga cover image",
                 "parameters": [
                     {
                         "type": "integer",
                         "example": 1,
-                        "description": "Multimanga ID",
+                        "description": "Manga ID",
                         "name": "id",
-                        "in": "query",
-                        "required": true
+                        "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "example": 1,
-                        "description": "Manga ID",
-                        "name": "manga_id",
-                        "in": "query",
-                        "required": true
+                        "type": "string",
+                        "example": "\"https://mangadex.org/title/1/one-piece\"",
+                        "description": "Manga URL",
+                        "name": "url",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"1as4fa7\"",
+                        "description": "Manga Internal ID",
+                        "name": "manga_internal_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Manga cover image file. Remember to set the Content-Type header to 'multipart/form-data' when sending the request.",
+                        "name": "cover_img",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"https://example.com/cover.jpg\"",
+                        "description": "Manga cover image URL",
+                        "name": "cover_img_url",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": true,
+                        "description": "Let Mantium fetch the cover image from the source site",
+                        "name": "get_cover_img_from_source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": true,
+                        "description": "Update manga cover image to  Mantium's default cover image",
+                        "name": "use_mantium_default_img",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -1183,29 +1307,42 @@ const docTemplate = `{
                 }
             }
         },
-        "/multimanga/status": {
+        "/manga/last_read_chapter": {
             "patch": {
-                "description": "Updates a multimanga status in the database.",
+                "description": "Updates a manga last read chapter in the database. If both ` + "`" + `chapter` + "`" + ` and ` + "`" + `chapter_url` + "`" + ` are empty strings in the body, set the last read chapter to the last released chapter for normal mangas. For custom mangas, deletes the manga's last read chapter. You can't provide only the chapter_url for custom mangas. You must provide either the manga ID or the manga URL.",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Update multimanga status",
+                "summary": "Update manga last read chapter",
                 "parameters": [
                     {
                         "type": "integer",
                         "example": 1,
-                        "description": "Multimanga ID",
+                        "description": "Manga ID",
                         "name": "id",
-                        "in": "query",
-                        "required": true
+                        "in": "query"
                     },
                     {
-                        "description": "Multimanga status",
-                        "name": "status",
+                        "type": "string",
+                        "example": "\"https://mangadex.org/title/1/one-piece\"",
+                        "description": "Manga URL",
+                        "name": "url",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"1as4fa7\"",
+                        "description": "Manga Internal ID",
+                        "name": "manga_internal_id",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Chapter",
+                        "name": "chapter",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/routes.UpdateMangaStatusRequest"
+                            "$ref": "#/definitions/routes.UpdateMangaChapterRequest"
                         }
                     }
                 ],
@@ -1219,417 +1356,3867 @@ const docTemplate = `{
                 }
             }
         },
-        "/multimangas": {
+        "/manga/metadata": {
             "get": {
-                "description": "Gets all multimangas. The multimanga's mangas will have only the current manga. The current manga will have a possible wrong status, so use the multimanga's status.",
+                "description": "Gets the metadata for a manga from the source site.",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Get multimangas",
+                "summary": "Get manga metadata",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"https://mangadex.org/title/1/one-piece\"",
+                        "description": "Manga URL",
+                        "name": "url",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
                 "responses": {
                     "200": {
-                        "description": "{\"multimangas\": [multimangaObj]}",
+                        "description": "{\"manga\": mangaObj}",
+                        "schema": {
+                            "$ref": "#/definitions/manga.Manga"
+                        }
+                    }
+                }
+            }
+        },
+        "/manga/name": {
+            "patch": {
+                "description": "Updates a manga name in the database. You must provide either the manga ID or the manga URL.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Update manga name",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Manga ID",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"https://mangadex.org/title/1/one-piece\"",
+                        "description": "Manga current URL",
+                        "name": "url",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"One Piece\"",
+                        "description": "New manga name",
+                        "name": "name",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.responseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/manga/status": {
+            "patch": {
+                "description": "Updates a manga status in the database. You must provide either the manga ID or the manga URL.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Update manga status",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Manga ID",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"https://mangadex.org/title/1/one-piece\"",
+                        "description": "Manga URL",
+                        "name": "url",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Manga status",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/routes.UpdateMangaStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.responseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/manga/turn_into_multimanga": {
+            "post": {
+                "description": "Turns a manga into a multimanga. You must provide either the manga ID or the manga URL.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Turn manga into multimanga",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Manga ID",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"https://mangadex.org/title/1/one-piece\"",
+                        "description": "Manga URL",
+                        "name": "url",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.responseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/manga/url": {
+            "patch": {
+                "description": "Updates a manga URL in the database. If URL is empty and it's a custom manga, the operation is execute, else, an error is returned. You must provide either the manga ID or the manga current URL.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Update manga URL",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Manga ID",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"https://mangadex.org/title/1/one-piece\"",
+                        "description": "Manga current URL",
+                        "name": "url",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"https://mangadex.org/title/2/two-piece\"",
+                        "description": "Manga new URL ",
+                        "name": "new_url",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.responseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/mangas": {
+            "get": {
+                "description": "Gets the current manga of multimangas and all custom mangas.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get mangas",
+                "responses": {
+                    "200": {
+                        "description": "{\"mangas\": [mangaObj]}",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/manga.MultiManga"
+                                "$ref": "#/definitions/manga.Manga"
                             }
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "config.DashboardConfigs": {
-            "type": "object",
-            "properties": {
-                "display": {
-                    "type": "object",
-                    "properties": {
-                        "columns": {
-                            "type": "integer"
-                        },
-                        "displayMode": {
-                            "type": "string"
-                        },
-                        "searchResultsLimit": {
+        },
+        "/mangas/add_to_kaizoku": {
+            "post": {
+                "description": "Add the multimangas' current manga to Kaizoku. If it fails to add a manga, it will continue with the next manga. This is a heavy operation depending on the number of mangas in the database.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Add mangas to Kaizoku",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
                             "type": "integer"
                         },
-                        "showBackgroundErrorWarning": {
-                            "type": "boolean"
+                        "collectionFormat": "csv",
+                        "description": "Filter which mangas to add by status. 1=reading, 2=completed, 3=on hold, 4=dropped, 5=plan to read. Example: status=1,2,3,5",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.responseMessage"
                         }
                     }
-                },
-                "integrations": {
-                    "type": "object",
-                    "properties": {
-                        "addAllMultiMangaMangasToDownloadIntegrations": {
-                            "type": "boolean"
+                }
+            }
+        },
+        "/mangas/add_to_suwayomi": {
+            "post": {
+                "description": "Add the multimangas' current manga to Suwayomi. If it fails to add a manga, it will continue with the next manga. This is a heavy operation depending on the number of mangas in the database.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Add mangas to Suwayomi",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
                         },
-                        "enqueueAllSuwayomiChaptersToDownload": {
-                            "type": "boolean"
+                        "collectionFormat": "csv",
+                        "description": "Filter which mangas to add by status. 1=reading, 2=completed, 3=on hold, 4=dropped, 5=plan to read. Example: status=1,2,3,5",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.responseMessage"
                         }
                     }
-                },
-                "manga": {
-                    "type": "object",
+                }
+            }
+        },
+        "/mangas/add_to_tranga": {
+            "post": {
+                "description": "Add the multimangas' current manga to Tranga. If it fails to add a manga, it will continue with the next manga. This is a heavy operation depending on the number of mangas in the database. Currently, only MangaDex mangas can be added to Tranga, but it'll try all mangas anyway.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Add mangas to Tranga",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Filter which mangas to add by status. 1=reading, 2=completed, 3=on hold, 4=dropped, 5=plan to read. Example: status=1,2,3,5",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.responseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/mangas/iframe": {
+            "get": {
+                "description": "Returns an iFrame with mangas. By default, only mangas with unread chapters, and status reading or completed, sorted by last released chapter date. The templates can be overridden in the directory set by the IFRAME_TEMPLATES_DIR environment variable.",
+                "produces": [
+                    "text/html"
+                ],
+                "summary": "Mangas iFrame",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "https://sub.domain.com",
+                        "description": "API URL used by your browser. Used for the button that updates the last read chater, as your browser needs to send a request to the API to update the chapter.",
+                        "name": "api_url",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "light",
+                        "description": "IFrame theme, defaults to light. If it's different from your dashboard theme, the background turns may turn white",
+                        "name": "theme",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "grid",
+                        "description": "IFrame layout: list, compact, grid, carousel, or next_up. Defaults to list.",
+                        "name": "layout",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 5,
+                        "description": "Limits the number of items in the iFrame.",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "1,2,5",
+                        "description": "Comma separated list of statuses of the mangas to show (1: reading, 2: completed, 3: on hold, 4: dropped, 5: plan to read). 0 means all statuses. Defaults to 1,2.",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "action,weekly",
+                        "description": "Comma separated list of tags. Only mangas with any of the tags are shown.",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "mangadex,comick",
+                        "description": "Comma separated list of sources. Only mangas from the sources are shown.",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "name",
+                        "description": "Sort by released (last released chapter date), name, unread (unread chapters count), or read (last read chapter date). Defaults to released.",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": false,
+                        "description": "If true, shows only mangas with unread chapters. Defaults to true.",
+                        "name": "unread",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": true,
+                        "description": "If true, shows a warning in the iFrame if an error occurred in the background. Defaults to true.",
+                        "name": "showBackgroundErrorWarning",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "%23fa5252",
+                        "description": "Sets a CSS variable of the iFrame, like css_released_color=%23fa5252 sets --mantium-released-color. Overrides the iFrame CSS variables of the dashboard configs.",
+                        "name": "css_variable",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "HTML content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/mangas/metadata": {
+            "patch": {
+                "description": "Get the mangas metadata from the sources and update them in the database. The titles related to the mangas, like sequels, are fetched at most once a day from the sources that list them. The multimangas IDs in MangaUpdates, AniList, and MyAnimeList are resolved if they never were, or at most once a week if some are missing. The custom manga watchers are also checked for new chapters. If Komga or Kavita are configured, their libraries are scanned when there are new chapters, and the read progress is synced from them.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Update mangas metadata",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Notify if a new chapter was released for the manga (only of mangas with status reading or completed), and if the NOTIFICATIONS_NEW_RELATIONS environment variable is true, if a completed manga has a new related title, like a sequel. The notifications follow the notification rules, and the deferred notifications that are due are also sent.",
+                        "name": "notify",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.responseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/mangas/search": {
+            "post": {
+                "description": "Searches a manga in the source. You must provide the source name like \"mangadex\" and the search query.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Search manga",
+                "parameters": [
+                    {
+                        "description": "Search data",
+                        "name": "search",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/routes.SearchMangaRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "{\"mangas\": [mangaSearchResultObj]}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/models.MangaSearchResult"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/mangas/stats": {
+            "get": {
+                "description": "Get the library stats from all multimangas and custom mangas.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get library stats",
+                "responses": {
+                    "200": {
+                        "description": "{\"property\": value}",
+                        "schema": {
+                            "type": "map"
+                        }
+                    }
+                }
+            }
+        },
+        "/mangas/sync_read_progress": {
+            "post": {
+                "description": "Sets the multimangas' last read chapter to the last chapter read in Komga and Kavita if it's newer. The multimangas are matched to the series by name, and by the series links in Komga. It's also done when the mangas metadata is updated.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Sync read progress",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.responseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/multimanga": {
+            "get": {
+                "description": "Gets a multimanga from the database, with the titles related to its mangas, like sequels and spin-offs, and its IDs in MangaUpdates, AniList, and MyAnimeList. The relations are fetched from the sources that list them (MangaDex and MangaUpdates) when updating the mangas metadata, at most once a day.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get multimanga",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Multimanga ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "{\"multimanga\": multimangaObj, \"relations\": [relationObj], \"external_ids\": externalIDsObj}",
+                        "schema": {
+                            "$ref": "#/definitions/manga.MultiManga"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Gets a manga metadata from source and inserts it as the current manga of a new multimanga into the database. The manga is added to the download integrations by background jobs, whose IDs are returned; check them in the jobs routes.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Add multimanga",
+                "parameters": [
+                    {
+                        "description": "Current manga data",
+                        "name": "manga",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/routes.AddMangaRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.responseMessageWithJobs"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a multimanga from the database. The \"delete\" removal policy of the download integrations is applied to its mangas.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Delete multimanga",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Multimanga ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.responseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/multimanga/chapters": {
+            "get": {
+                "description": "Get chapters of the current manga of a multimanga from the source.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get multimanga current manga chapters",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Multimanga ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "{\"chapters\": [chapterObj]}",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/manga.Chapter"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/multimanga/choose_current_manga": {
+            "get": {
+                "description": "Check a multimanga mangas and returns which manga should be the current manga.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Choose current manga",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Multimanga ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"1,2,3\"",
+                        "description": "Manga IDs to exclude from the check",
+                        "name": "exclude_manga_ids",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "{\"manga\": mangaObj}",
+                        "schema": {
+                            "$ref": "#/definitions/manga.Manga"
+                        }
+                    }
+                }
+            }
+        },
+        "/multimanga/cover_img": {
+            "patch": {
+                "description": "Updates a multimanga cover image in the database. You must provide only one of the following: cover_img, cover_img_url, use_current_manga_cover_img.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Update multimanga cover image",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Multimanga ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Multimanga cover image file. Remember to set the Content-Type header to 'multipart/form-data' when sending the request.",
+                        "name": "cover_img",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"https://example.com/cover.jpg\"",
+                        "description": "Multimanga cover image URL",
+                        "name": "cover_img_url",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": true,
+                        "description": "Use the multimanga's current manga cover image",
+                        "name": "use_current_manga_cover_img",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.responseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/multimanga/external_ids": {
+            "get": {
+                "description": "Gets the multimanga IDs in MangaUpdates, AniList, and MyAnimeList, and the URLs of these sites' pages of the multimanga. The IDs are empty if they were never resolved or set. The MangaUpdates URL can be added to the multimanga to also track it in the MangaUpdates source.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get multimanga external IDs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Multimanga ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "{\"external_ids\": externalIDsObj, \"urls\": {\"mangaupdates\": \"https://www.mangaupdates.com/series/pb8uwds\"}}",
+                        "schema": {
+                            "$ref": "#/definitions/manga.ExternalIDs"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Sets the multimanga IDs in MangaUpdates, AniList, and MyAnimeList, replacing the current IDs. An empty ID removes it. The IDs changed or removed are marked as manual and aren't filled or replaced when the IDs are resolved from the sources. The IDs in automatic_ids, like \"anilist_id\", stop being manual, so they're filled again if empty.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Set multimanga external IDs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Multimanga ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "description": "External IDs",
+                        "name": "external_ids",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/routes.SetMultiMangaExternalIDsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "{\"message\": \"Multimanga external IDs set successfully\", \"external_ids\": externalIDsObj}",
+                        "schema": {
+                            "$ref": "#/definitions/manga.ExternalIDs"
+                        }
+                    }
+                }
+            }
+        },
+        "/multimanga/external_ids/resolve": {
+            "post": {
+                "description": "Gets the multimanga IDs in MangaUpdates, AniList, and MyAnimeList from the sources that know them (MangaDex, MangaUpdates, and ComicK) and fills the empty IDs that aren't manual. The IDs are also resolved when updating the mangas metadata.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Resolve multimanga external IDs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Multimanga ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "{\"message\": \"Multimanga external IDs resolved successfully\", \"external_ids\": externalIDsObj}",
+                        "schema": {
+                            "$ref": "#/definitions/manga.ExternalIDs"
+                        }
+                    }
+                }
+            }
+        },
+        "/multimanga/last_read_chapter": {
+            "patch": {
+                "description": "Updates a multimanga last read chapter in the database. It also needs to know from which manga the chapter is from. If both ` + "`" + `chapter` + "`" + ` and ` + "`" + `chapter_url` + "`" + ` are empty strings in the body, set the last read chapter to the last released chapter in the database. The chapters up to the chapter are marked as read. The chapters after it keep their read state, unless ` + "`" + `unmark_after` + "`" + ` is true.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Update multimanga last read chapter",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Multimanga ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Manga ID",
+                        "name": "manga_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "example": false,
+                        "description": "Mark the chapters after the chapter as unread, so it becomes the last read chapter even if a later chapter was read",
+                        "name": "unmark_after",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Chapter",
+                        "name": "chapter",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/routes.UpdateMangaChapterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.responseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/multimanga/manga": {
+            "post": {
+                "description": "Adds a manga to a multimanga list in the database. If the dashboard is configured to add all multimanga's mangas to the download integrations, the manga is added to them by background jobs, whose IDs are returned.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Add manga to multimanga list",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Multimanga ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "description": "Manga data",
+                        "name": "manga",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/routes.AddMangaToMultiMangaRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.responseMessageWithJobs"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Removes a manga from a multimanga list in the database. The \"delete\" removal policy of the download integrations is applied to the manga.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Remove manga from multimanga list",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Multimanga ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Manga ID",
+                        "name": "manga_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.responseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/multimanga/read_chapters": {
+            "get": {
+                "description": "Gets the multimanga stored chapters with their read state, sorted from the first to the last chapter. It also returns the unread chapters count and the reread count.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get multimanga chapters read state",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Multimanga ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "example": true,
+                        "description": "Sync the stored chapters with the current manga's chapters from the source first",
+                        "name": "sync",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "{\"chapters\": [readChapterObj], \"unread_chapters\": 0, \"reread_count\": 0}",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/manga.ReadChapter"
+                            }
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Marks specific multimanga chapters as read or unread, like an out-of-order special chapter. The chapters must be stored, use the sync query to store them first. The multimanga last read chapter is set to the last read chapter.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Mark multimanga chapters",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Multimanga ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "example": true,
+                        "description": "Sync the stored chapters with the current manga's chapters from the source first",
+                        "name": "sync",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Chapters to mark",
+                        "name": "chapters",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/routes.MarkChaptersRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.responseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/multimanga/read_chapters/range": {
+            "patch": {
+                "description": "Marks all multimanga chapters from from_chapter to to_chapter (inclusive) as read or unread. The chapters must be stored, use the sync query to store them first. The multimanga last read chapter is set to the last read chapter.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Mark multimanga chapters range",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Multimanga ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "example": true,
+                        "description": "Sync the stored chapters with the current manga's chapters from the source first",
+                        "name": "sync",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Chapters range to mark",
+                        "name": "range",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/routes.MarkChapterRangeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.responseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/multimanga/read_chapters/up_to": {
+            "patch": {
+                "description": "Marks all multimanga chapters up to the chapter (inclusive) as read. The chapters after it keep their read state, unless ` + "`" + `unmark_after` + "`" + ` is true. The chapter must be stored, use the sync query to store it first.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Mark multimanga chapters read up to a chapter",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Multimanga ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "example": false,
+                        "description": "Mark the chapters after the chapter as unread, so it becomes the multimanga last read chapter",
+                        "name": "unmark_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": true,
+                        "description": "Sync the stored chapters with the current manga's chapters from the source first",
+                        "name": "sync",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Last chapter to mark as read",
+                        "name": "chapter",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/routes.MarkChaptersReadUpToRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.responseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/multimanga/relation": {
+            "post": {
+                "description": "Adds a title related to a manga, like a sequel, as a new multimanga. The title must be one of the relations returned by the get multimanga route. The manga is added to the download integrations by background jobs, whose IDs are returned; check them in the jobs routes.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Add related multimanga",
+                "parameters": [
+                    {
+                        "description": "Related title data",
+                        "name": "relation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/routes.AddRelatedMultiMangaRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.responseMessageWithJobs"
+                        }
+                    }
+                }
+            }
+        },
+        "/multimanga/reread": {
+            "post": {
+                "description": "Increments the multimanga reread count and marks all its stored chapters as unread. The chapters keep their read count.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Start multimanga reread",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Multimanga ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.responseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/multimanga/status": {
+            "patch": {
+                "description": "Updates a multimanga status in the database. If the status is set to completed or dropped, the \"complete\" or \"drop\" removal policy of the download integrations is applied to its mangas.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Update multimanga status",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Multimanga ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "description": "Multimanga status",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/routes.UpdateMangaStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.responseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/multimanga/tags": {
+            "patch": {
+                "description": "Replaces the multimanga tags. The tags are stored trimmed, in lower case, and sorted. An empty list removes all tags.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Update multimanga tags",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Multimanga ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "description": "Multimanga tags",
+                        "name": "tags",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/routes.UpdateTagsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.responseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/multimangas": {
+            "get": {
+                "description": "Gets all multimangas. The multimanga's mangas will have only the current manga. The current manga will have a possible wrong status, so use the multimanga's status.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get multimangas",
+                "responses": {
+                    "200": {
+                        "description": "{\"multimangas\": [multimangaObj]}",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/manga.MultiManga"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/multimangas/bulk": {
+            "post": {
+                "description": "Runs an operation with many multimangas, selected by their IDs or by a filter. The operations are: set_status (requires status), mark_latest_read (sets the last read chapter to the current manga's last released chapter), delete, add_tag (requires tag), and push_to_integration (requires integration, like suwayomi; the current manga is added to it by a background job, and custom mangas and mangas from sources the integration doesn't support are skipped). The set_status, add_tag, and delete operations run in one transaction. The removal policies of the download integrations are applied like when updating or deleting one multimanga. It returns the result of each multimanga, and status 500 if any failed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Bulk multimangas operation",
+                "parameters": [
+                    {
+                        "description": "Multimangas and operation, like the dropped multimangas filter and the delete operation",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/routes.BulkMultiMangasRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.bulkMultiMangasResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/multimangas/duplicates": {
+            "get": {
+                "description": "Gets the groups of multimangas that seem to be the same work, as they have the same MangaUpdates, AniList, or MyAnimeList ID, or the same current manga name ignoring case, spaces, and punctuation. The mangas of a group can be moved to a single multimanga.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get duplicated multimangas",
+                "responses": {
+                    "200": {
+                        "description": "{\"duplicates\": [duplicateGroupObj]}",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/manga.DuplicateGroup"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/multimangas/external_ids": {
+            "get": {
+                "description": "Gets the IDs in MangaUpdates, AniList, and MyAnimeList of all multimangas with external IDs, sorted by multimanga ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get multimangas external IDs",
+                "responses": {
+                    "200": {
+                        "description": "{\"external_ids\": [externalIDsObj]}",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/manga.ExternalIDs"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/digest": {
+            "get": {
+                "description": "Gets the new chapters buffered for the next digest, grouped by multimanga, and when the digest is scheduled. The digest is sent periodically when it's due.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get notifications digest",
+                "parameters": [
+                    {
+                        "enum": [
+                            "ntfy",
+                            "email",
+                            "webhook"
+                        ],
+                        "type": "string",
+                        "description": "Render the digest with a notifier template instead of returning it as JSON",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.notificationsDigestResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/queue": {
+            "get": {
+                "description": "Gets the notifications deferred by the notification rules, like by the quiet hours or the minimum gap. They're sent periodically when they're due.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get notifications queue",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.notificationsQueueResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/queue/send": {
+            "post": {
+                "description": "Sends the notifications in the queue that are due and the digest if it's due. It's called periodically by the API, see NOTIFICATIONS_SEND_QUEUE_MINUTES. If it fails to send a notification, it will continue with the next one.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Send due notifications",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.responseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/rules": {
+            "get": {
+                "description": "Gets the notification rules of a multimanga or the global rules if the multimanga_id is not provided. The multimanga rules' null fields inherit the global rules, the effective rules are returned in the effective_rules field.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get notification rules",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Multimanga ID",
+                        "name": "multimanga_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.notificationRulesResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Updates the notification rules of a multimanga or the global rules if the multimanga_id is not provided. For the global rules, null fields keep their current value. For a multimanga, null fields inherit the global rules. The quiet hours and digest time must be like HH:MM, set the quiet hours to empty strings to disable them. The digest schedule (digest_frequency, digest_weekday, and digest_time) is only set in the global rules.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Update notification rules",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Multimanga ID",
+                        "name": "multimanga_id",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Notification rules",
+                        "name": "rules",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/notifications.Rules"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.responseMessage"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes the notification rules of a multimanga, so it uses the global rules.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Delete multimanga notification rules",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Multimanga ID",
+                        "name": "multimanga_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.responseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/opds/archive": {
+            "get": {
+                "description": "Downloads a multimanga chapter archive listed in the multimanga feed.",
+                "produces": [
+                    "application/vnd.comicbook+zip"
+                ],
+                "summary": "OPDS chapter archive",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Multimanga ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "Berserk/Berserk - Ch. 1.cbz",
+                        "description": "Archive ID from the multimanga feed",
+                        "name": "archive",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Chapter archive",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/opds/cover": {
+            "get": {
+                "description": "Returns the multimanga cover image.",
+                "produces": [
+                    "image/jpeg",
+                    "image/png"
+                ],
+                "summary": "OPDS multimanga cover",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Multimanga ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Cover image",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/opds/v1.2/catalog": {
+            "get": {
+                "description": "Returns the OPDS 1.2 navigation feed with the multimangas. Each entry links to the multimanga feed with its downloaded chapter archives. It's used by e-readers like KOReader, Panels, and Chunky.",
+                "produces": [
+                    "text/xml"
+                ],
+                "summary": "OPDS 1.2 catalog",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "https://sub.domain.com",
+                        "description": "API URL used in the feed links. Defaults to the request URL.",
+                        "name": "api_url",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OPDS feed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/opds/v1.2/multimanga": {
+            "get": {
+                "description": "Returns the OPDS 1.2 acquisition feed with the multimanga downloaded chapter archives, newest first. The archives are from the OPDS_ARCHIVES_DIR directory or, if not set, proxied from Suwayomi.",
+                "produces": [
+                    "text/xml"
+                ],
+                "summary": "OPDS 1.2 multimanga feed",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Multimanga ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "https://sub.domain.com",
+                        "description": "API URL used in the feed links. Defaults to the request URL.",
+                        "name": "api_url",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OPDS feed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/opds/v2.0/catalog": {
+            "get": {
+                "description": "Returns the OPDS 2.0 navigation feed with the multimangas. Each link goes to the multimanga feed with its downloaded chapter archives.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "OPDS 2.0 catalog",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "https://sub.domain.com",
+                        "description": "API URL used in the feed links. Defaults to the request URL.",
+                        "name": "api_url",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OPDS feed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/opds/v2.0/multimanga": {
+            "get": {
+                "description": "Returns the OPDS 2.0 feed with a publication for each multimanga downloaded chapter archive, newest first. The archives are from the OPDS_ARCHIVES_DIR directory or, if not set, proxied from Suwayomi.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "OPDS 2.0 multimanga feed",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Multimanga ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "https://sub.domain.com",
+                        "description": "API URL used in the feed links. Defaults to the request URL.",
+                        "name": "api_url",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OPDS feed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/recommendations": {
+            "get": {
+                "description": "Recommends titles based on the library. The multimangas with the status reading, completed, on hold, or plan to read from MangaDex, MangaUpdates, or ComicK are used as seeds, from the reading and completed ones to the most recently read. The sources are requested for the titles similar to each seed and the seed genres, which build the profile. The titles already in the library, matched by URL, name, or MangaUpdates ID, aren't recommended. The score of a title is the sum of the weights of the seeds it's similar to (3 for reading and completed, 1 for on hold and plan to read), plus 1 for each of its genres in the profile's top 5 genres. The tags only choose the seeds and aren't weighted in the profile, as all seeds have the tags and the similar titles from the sources have no tags. The multimangas have no rating, so the profile isn't weighted by ratings, and the unsupported query parameters, like ratings, are rejected. This is a heavy operation, as each seed is a request to its source.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get recommendations",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 20,
+                        "description": "Max number of recommendations. Defaults to 20.",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 10,
+                        "description": "Max number of multimangas used as seeds. Defaults to 10, max 30.",
+                        "name": "seeds",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"favorite,weekly\"",
+                        "description": "Use only the multimangas with any of the tags as seeds",
+                        "name": "tags",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.recommendationsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/recommendations/add": {
+            "post": {
+                "description": "Adds a recommended title as a new multimanga with the status plan to read. The manga is added to the download integrations by background jobs, whose IDs are returned; check them in the jobs routes.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Add recommendation",
+                "parameters": [
+                    {
+                        "description": "Recommended title data",
+                        "name": "recommendation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/routes.AddRecommendationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.responseMessageWithJobs"
+                        }
+                    }
+                }
+            }
+        },
+        "/sources/scraper/validate": {
+            "post": {
+                "description": "Validates a scraper source config and test-runs it with a manga URL without registering it. It returns the manga and chapters found in the manga page, the search results if search_term is provided, and the error of each step. The config can't have the name of a built-in source.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Validate scraper source",
+                "parameters": [
+                    {
+                        "description": "Config and manga URL.",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/routes.ValidateScraperSourceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "{\"message\": \"Config is valid\", \"result\": resultObj}",
+                        "schema": {
+                            "$ref": "#/definitions/routes.ValidateScraperSourceResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/activity": {
+            "get": {
+                "description": "Returns the reading activity log, newest first. An activity is recorded every time the last read chapter of a multimanga or custom manga advances.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get reading activity",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "2024-01-01",
+                        "description": "Start date (inclusive)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2024-12-31",
+                        "description": "End date (inclusive)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 50,
+                        "description": "Max number of activities to return",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "{\"activities\": [readingActivityObj]}",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/manga.ReadingActivity"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/backlog": {
+            "get": {
+                "description": "Returns the daily snapshots of the number of mangas with unread chapters. A snapshot is saved when the mangas metadata are updated and when a chapter is read.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get backlog over time",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "2024-01-01",
+                        "description": "Start date (inclusive)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2024-12-31",
+                        "description": "End date (inclusive)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "{\"snapshots\": [backlogSnapshotObj]}",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/manga.BacklogSnapshot"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/chapters_read": {
+            "get": {
+                "description": "Returns the number of chapters and mangas read per day, week, or month.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get chapters read per period",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "week",
+                        "description": "Period to group by: day, week, or month. Defaults to day.",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2024-01-01",
+                        "description": "Start date (inclusive)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2024-12-31",
+                        "description": "End date (inclusive)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "{\"stats\": [readingPeriodStatsObj]}",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/manga.ReadingPeriodStats"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/genres": {
+            "get": {
+                "description": "Returns the number of chapters and mangas read grouped by genre. The genres are got from the sources that list them (MangaDex, MangaUpdates, and ComicK) when the reading activity is recorded. Activities without genres, like of custom mangas, are grouped in the \"Unknown\" genre.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get chapters read per genre",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "2024-01-01",
+                        "description": "Start date (inclusive)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2024-12-31",
+                        "description": "End date (inclusive)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "{\"stats\": [readingBreakdownObj]}",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/manga.ReadingBreakdown"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/release_to_read": {
+            "get": {
+                "description": "Returns the average and median time in seconds between a chapter being released and being read.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get release to read stats",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "2024-01-01",
+                        "description": "Start date (inclusive)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2024-12-31",
+                        "description": "End date (inclusive)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "{\"stats\": releaseToReadStatsObj}",
+                        "schema": {
+                            "$ref": "#/definitions/manga.ReleaseToReadStats"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/sources": {
+            "get": {
+                "description": "Returns the number of chapters and mangas read grouped by source.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get chapters read per source",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "2024-01-01",
+                        "description": "Start date (inclusive)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2024-12-31",
+                        "description": "End date (inclusive)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "{\"stats\": [readingBreakdownObj]}",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/manga.ReadingBreakdown"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/streaks": {
+            "get": {
+                "description": "Returns the current and longest streaks of consecutive days with at least one chapter read.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get reading streaks",
+                "responses": {
+                    "200": {
+                        "description": "{\"streaks\": readingStreaksObj}",
+                        "schema": {
+                            "$ref": "#/definitions/manga.ReadingStreaks"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/chapter_read": {
+            "post": {
+                "description": "Receives a chapter read in an external reader, like Suwayomi, Komga, or a Tachiyomi sync server, and sets the multimanga last read chapter to it. The manga is matched by its URL in the source. The last read chapter only moves forward, so events of chapters older than the last read chapter are ignored. If the chapters numbers can't be compared (like ` + "`" + `Extra` + "`" + ` and ` + "`" + `Oneshot` + "`" + `), the event chapter must have been released after the last read chapter or be listed before it in the source, otherwise it's ignored. Events with an idempotency key already received in the last 7 days are also ignored. Each integration has its own token, set in the READ_WEBHOOK_TOKENS environment variable and sent as a bearer token in the Authorization header or in the token query.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Chapter read webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Integration token, if not sent in the Authorization header",
+                        "name": "token",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event idempotency key, if not sent in the body",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Chapter read event",
+                        "name": "event",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/webhooks.ChapterReadEvent"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.responseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/widget": {
+            "get": {
+                "description": "Returns a summary of the library for dashboard widgets, like Homepage's customapi widget: the number of unread mangas and chapters, the latest released chapters, and the number of mangas with unread chapters per status (backlog). The tags and source filters apply to everything, the status filter doesn't apply to the backlog.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Dashboard widget",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "1,2",
+                        "description": "Comma separated list of statuses of the mangas (1: reading, 2: completed, 3: on hold, 4: dropped, 5: plan to read). 0 means all statuses. Defaults to 1,2.",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "weekly",
+                        "description": "Comma separated list of tags. Only mangas with any of the tags are used.",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "mangadex",
+                        "description": "Comma separated list of sources. Only mangas from the sources are used.",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 5,
+                        "description": "Number of latest releases. Defaults to 5.",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": true,
+                        "description": "If true, the latest releases have only mangas with unread chapters. Defaults to false.",
+                        "name": "unread",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/feed.Widget"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "config.DashboardConfigs": {
+            "type": "object",
+            "properties": {
+                "display": {
+                    "type": "object",
+                    "properties": {
+                        "columns": {
+                            "type": "integer"
+                        },
+                        "displayMode": {
+                            "type": "string"
+                        },
+                        "iframeCSSVariables": {
+                            "description": "IframeCSSVariables are the default iframe CSS variables, like {\"released-color\": \"#fa5252\"}.\nThey can be overridden by the iframe URL query.",
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        },
+                        "searchResultsLimit": {
+                            "type": "integer"
+                        },
+                        "showBackgroundErrorWarning": {
+                            "type": "boolean"
+                        }
+                    }
+                },
+                "integrations": {
+                    "type": "object",
+                    "properties": {
+                        "addAllMultiMangaMangasToDownloadIntegrations": {
+                            "type": "boolean"
+                        },
+                        "enqueueAllSuwayomiChaptersToDownload": {
+                            "type": "boolean"
+                        }
+                    }
+                },
+                "manga": {
+                    "type": "object",
                     "properties": {
                         "allowedAddingMethods": {
                             "type": "array",
                             "items": {
                                 "type": "string"
-                            }
-                        },
-                        "allowedSources": {
-                            "type": "array",
-                            "items": {
+                            }
+                        },
+                        "allowedSources": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "mantium": {
+                    "type": "object",
+                    "properties": {
+                        "version": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "dashboard.BackgroundError": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "description": "Error message.",
+                    "type": "string"
+                },
+                "time": {
+                    "description": "Time when the error occurred.",
+                    "type": "string"
+                }
+            }
+        },
+        "downloads.Drift": {
+            "type": "object",
+            "properties": {
+                "extra": {
+                    "description": "Extra are the integration mangas that are not in Mantium.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/downloads.Manga"
+                    }
+                },
+                "integration": {
+                    "type": "string"
+                },
+                "missing": {
+                    "description": "Missing are the Mantium mangas that are not in the integration.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/downloads.Manga"
+                    }
+                },
+                "unsupported": {
+                    "description": "Unsupported are the Mantium mangas from sources the integration doesn't support.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/downloads.Manga"
+                    }
+                }
+            }
+        },
+        "downloads.Manga": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "ID is the manga ID in the integration. It's empty for Mantium mangas.",
+                    "type": "string"
+                },
+                "key": {
+                    "description": "Key is used to match the integration mangas with the Mantium mangas.",
+                    "type": "string"
+                },
+                "source": {
+                    "description": "Source is the Mantium source of Mantium mangas, or the integration source of the integration mangas.",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "url": {
+                    "description": "URL is the manga URL in the source, if known.",
+                    "type": "string"
+                }
+            }
+        },
+        "downloads.ReconcileResult": {
+            "type": "object",
+            "properties": {
+                "added": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/downloads.Manga"
+                    }
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "extra": {
+                    "description": "Extra are the integration mangas that are not in Mantium.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/downloads.Manga"
+                    }
+                },
+                "integration": {
+                    "type": "string"
+                },
+                "missing": {
+                    "description": "Missing are the Mantium mangas that are not in the integration.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/downloads.Manga"
+                    }
+                },
+                "removed": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/downloads.Manga"
+                    }
+                },
+                "unsupported": {
+                    "description": "Unsupported are the Mantium mangas from sources the integration doesn't support.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/downloads.Manga"
+                    }
+                }
+            }
+        },
+        "downloads.Removal": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "error": {
+                    "description": "Error is the error while doing the action, if any.",
+                    "type": "string"
+                },
+                "event": {
+                    "type": "string"
+                },
+                "integration": {
+                    "type": "string"
+                },
+                "manga": {
+                    "$ref": "#/definitions/downloads.Manga"
+                }
+            }
+        },
+        "feed.Widget": {
+            "type": "object",
+            "properties": {
+                "backlog": {
+                    "description": "Backlog is the number of mangas with unread chapters per status name.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "latest_releases": {
+                    "description": "LatestReleases are the latest released chapters.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/feed.WidgetRelease"
+                    }
+                },
+                "total_mangas": {
+                    "description": "TotalMangas is the number of mangas.",
+                    "type": "integer"
+                },
+                "unread_chapters": {
+                    "description": "UnreadChapters is the number of unread chapters.",
+                    "type": "integer"
+                },
+                "unread_mangas": {
+                    "description": "UnreadMangas is the number of mangas with unread chapters.",
+                    "type": "integer"
+                }
+            }
+        },
+        "feed.WidgetRelease": {
+            "type": "object",
+            "properties": {
+                "chapter": {
+                    "type": "string"
+                },
+                "chapter_name": {
+                    "type": "string"
+                },
+                "manga_url": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "released_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "unread_chapters": {
+                    "type": "integer"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "jobs.Job": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "description": "Attempts is the number of times the job was run.",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "integration": {
+                    "description": "Integration is the name of the download integration, like \"Kaizoku\".",
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "manga_id": {
+                    "type": "integer"
+                },
+                "max_attempts": {
+                    "type": "integer"
+                },
+                "run_at": {
+                    "description": "RunAt is when the job will run next if pending.",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "manga.BacklogSnapshot": {
+            "type": "object",
+            "properties": {
+                "day": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "unread": {
+                    "type": "integer"
+                }
+            }
+        },
+        "manga.Chapter": {
+            "type": "object",
+            "properties": {
+                "chapter": {
+                    "description": "Chapter usually is the chapter number, but in some cases it can be a one-shot or a special chapter",
+                    "type": "string"
+                },
+                "groups": {
+                    "description": "Groups are the scanlation groups of the chapter release. They're only set by the sources\nwith releases by multiple groups and aren't stored in the database.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "internalID": {
+                    "description": "InteralID is a unique identifier for the chapter in the source",
+                    "type": "string"
+                },
+                "language": {
+                    "description": "Language is the language of the chapter release. It's only set by the sources\nwith releases in multiple languages and isn't stored in the database.",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name is the name of the chapter",
+                    "type": "string"
+                },
+                "type": {
+                    "type": "integer"
+                },
+                "updatedAt": {
+                    "description": "UpdatedAt is the time when the chapter was released or updated (read).\nShould truncate at the second.\nThe timezone should be the default/system timezone.",
+                    "type": "string"
+                },
+                "url": {
+                    "description": "URL is the URL of the chapter\nIf custom manga chapter doesn't have a URL provided by the user, it should be like http://custom_manga/\u003cuuid\u003e.",
+                    "type": "string"
+                }
+            }
+        },
+        "manga.ChapterPreferences": {
+            "type": "object",
+            "properties": {
+                "blocked_groups": {
+                    "description": "BlockedGroups are the groups whose releases are ignored.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "groups": {
+                    "description": "Groups are the preferred groups, from the most to the least preferred.\nReleases by other groups are used only when there is no release by these groups.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "languages": {
+                    "description": "Languages are the languages of the releases to use, from the most to the least preferred.\nReleases in other languages are ignored. If it's empty, the source's default language is used.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "manga_id": {
+                    "type": "integer"
+                },
+                "wait_hours": {
+                    "description": "WaitHours is how many hours to wait for a release by one of the preferred groups\nbefore using a release by other groups. If it's 0, it doesn't wait.",
+                    "type": "integer"
+                }
+            }
+        },
+        "manga.CustomChapter": {
+            "type": "object",
+            "properties": {
+                "chapter": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "released_at": {
+                    "description": "ReleasedAt is when the chapter was released, or when the watcher found it.",
+                    "type": "string"
+                },
+                "url": {
+                    "description": "URL is the URL of the chapter.\nIf the user doesn't provide a URL, it should be like CustomMangaURLPrefix/\u003cuuid\u003e.",
+                    "type": "string"
+                }
+            }
+        },
+        "manga.CustomMangaWatcher": {
+            "type": "object",
+            "properties": {
+                "last_chapter": {
+                    "description": "LastChapter is the last chapter found in the page.",
+                    "type": "string"
+                },
+                "last_checked_at": {
+                    "description": "LastCheckedAt is when the page was last checked. It's zero if it was never checked.",
+                    "type": "string"
+                },
+                "last_error": {
+                    "description": "LastError is the error of the last check, if it failed.",
+                    "type": "string"
+                },
+                "manga_id": {
+                    "type": "integer"
+                },
+                "regex": {
+                    "description": "Regex is matched against the text of the selected elements to get the chapters.\nIf it has a capturing group, the first group is the chapter, else the whole match.\nIf it's empty, the text of each element is a chapter.",
+                    "type": "string"
+                },
+                "selector": {
+                    "description": "Selector is the CSS selector of the elements with the chapters, like \"ul.chapters a\".\nIf it's empty, the whole page text is used.",
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "manga.DuplicateGroup": {
+            "type": "object",
+            "properties": {
+                "multimanga_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "names": {
+                    "description": "Names are the multimangas names, in the same order as the IDs.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "reasons": {
+                    "description": "Reasons are why the multimangas are grouped, like \"mangaupdates_id: 1\" or \"name: berserk\".",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "manga.ExternalIDs": {
+            "type": "object",
+            "properties": {
+                "anilist_id": {
+                    "type": "string"
+                },
+                "mal_id": {
+                    "type": "string"
+                },
+                "mangaupdates_id": {
+                    "description": "MangaUpdatesID is the MangaUpdates series ID, like the MangaUpdates source's manga internal ID.",
+                    "type": "string"
+                },
+                "manual_ids": {
+                    "description": "ManualIDs are the names of the IDs set or removed by the user, like \"anilist_id\".\nThey aren't filled when the IDs are resolved from the sources.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "multimanga_id": {
+                    "type": "integer"
+                },
+                "resolved_at": {
+                    "description": "ResolvedAt is when the IDs were last resolved from the sources. It's zero if they never were.",
+                    "type": "string"
+                }
+            }
+        },
+        "manga.Manga": {
+            "type": "object",
+            "properties": {
+                "coverImg": {
+                    "description": "CoverImg is the cover image of the manga",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "coverImgFixed": {
+                    "description": "CoverImgFixed is true if the cover image is fixed. If true, the cover image will not be updated when updating the manga metadata.\nIt's used for when the cover image is manually set by the user.",
+                    "type": "boolean"
+                },
+                "coverImgResized": {
+                    "description": "CoverImgResized is true if the cover image was resized",
+                    "type": "boolean"
+                },
+                "coverImgURL": {
+                    "description": "CoverImgURL is the URL of the cover image",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "internalID": {
+                    "description": "InteralID is a unique identifier for the manga in the source",
+                    "type": "string"
+                },
+                "lastReadChapter": {
+                    "description": "LastReadChapter is the last chapter read by the user\nIn a custom manga, this field represents the next manga the user should read\nor, if it's equal to the last released chapter, the manga is considered read.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/manga.Chapter"
+                        }
+                    ]
+                },
+                "lastReleasedChapter": {
+                    "description": "LastReleasedChapter is the last chapter released by the source\nIf the custom manga has no more released chapter, it'll be equal to the LastReadChapter.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/manga.Chapter"
+                        }
+                    ]
+                },
+                "multiMangaID": {
+                    "description": "When the manga is part of a multimanga, this field should be set to the multimanga ID",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Name is the name of the manga",
+                    "type": "string"
+                },
+                "preferredGroup": {
+                    "description": "PreferredGroup is the preferred group that translates (and more) the manga.\nNot all sources have multiple groups. It's the most preferred group\nof the manga's ChapterPreferences, which are used to choose the chapters.",
+                    "type": "string"
+                },
+                "searchNames": {
+                    "description": "SearchNames should be the multimanga's mangas names.\nUsed for searching mangas by name.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "source": {
+                    "description": "Source is the source of the manga, usually the domain of the website.\nIf source is the above CustomMangaSource const, it means the manga is a custom manga created by the user.\nand without a source site.",
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/manga.Status"
+                },
+                "tags": {
+                    "description": "Tags are the user's tags of a custom manga. For a multimanga's\ncurrent manga, they're the multimanga's tags. It's only set in list responses.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "unreadChapters": {
+                    "description": "UnreadChapters is the number of unread chapters. It's only set in list responses.\nFor a multimanga's current manga, it's the multimanga's unread chapters.",
+                    "type": "integer"
+                },
+                "url": {
+                    "description": "URL is the URL of the manga.\nIf custom manga doesn't have a URL provided by the user, it should be like above CustomMangaSource/\u003cuuid\u003e.",
+                    "type": "string"
+                }
+            }
+        },
+        "manga.MultiManga": {
+            "type": "object",
+            "properties": {
+                "coverImg": {
+                    "description": "CoverImg is the cover image of the multimanga",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "coverImgFixed": {
+                    "description": "CoverImgFixed is true if the cover image is fixed. If false (default) the current manga's cover image should be used.\nElse, use the multimanga's cover image fields.\nIt's used for when the cover image is manually set by the user.",
+                    "type": "boolean"
+                },
+                "coverImgResized": {
+                    "description": "CoverImgResized is true if the cover image was resized",
+                    "type": "boolean"
+                },
+                "coverImgURL": {
+                    "description": "CoverImgURL is the URL of the cover image",
+                    "type": "string"
+                },
+                "currentManga": {
+                    "$ref": "#/definitions/manga.Manga"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lastReadChapter": {
+                    "$ref": "#/definitions/manga.Chapter"
+                },
+                "mangas": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/manga.Manga"
+                    }
+                },
+                "rereadCount": {
+                    "description": "RereadCount is how many times the user started rereading the multimanga.",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "All mangas in the multimanga should have the same status",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/manga.Status"
+                        }
+                    ]
+                },
+                "tags": {
+                    "description": "Tags are the user's tags, like \"favorite\" or \"weekly\". They're lower case and sorted.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "unreadChapters": {
+                    "description": "UnreadChapters is the number of unread chapters stored in the multimanga_chapters table\nor, if greater, estimated from the last released and last read chapters' numbers.",
+                    "type": "integer"
+                }
+            }
+        },
+        "manga.MultiMangaFilter": {
+            "type": "object",
+            "properties": {
+                "source": {
+                    "description": "Source matches the multimangas whose current manga is from the source, like \"mangadex\".",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Status matches the multimangas with any of the statuses.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/manga.Status"
+                    }
+                },
+                "tags": {
+                    "description": "Tags matches the multimangas with any of the tags.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "unread": {
+                    "description": "Unread matches the multimangas with (true) or without (false) unread chapters.",
+                    "type": "boolean"
+                }
+            }
+        },
+        "manga.ReadChapter": {
+            "type": "object",
+            "properties": {
+                "chapter": {
+                    "type": "string"
+                },
+                "internal_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "read": {
+                    "type": "boolean"
+                },
+                "read_at": {
+                    "description": "ReadAt is the last time the user read the chapter.",
+                    "type": "string"
+                },
+                "read_count": {
+                    "description": "ReadCount is how many times the user read the chapter.\nGreater than 1 means the chapter was reread.",
+                    "type": "integer"
+                },
+                "released_at": {
+                    "description": "ReleasedAt is when the chapter was released by the source.",
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "manga.ReadingActivity": {
+            "type": "object",
+            "properties": {
+                "chapter": {
+                    "description": "Chapter is the chapter the user read.",
+                    "type": "string"
+                },
+                "chapter_released_at": {
+                    "description": "ChapterReleasedAt is when the chapter was released by the source.\nIt's zero when it's unknown, like with custom mangas.",
+                    "type": "string"
+                },
+                "chapter_url": {
+                    "type": "string"
+                },
+                "chapters_read": {
+                    "description": "ChaptersRead is how many chapters the user read in this entry,\ncalculated from the previous chapter. It's 1 when the chapters\nare not numbers.",
+                    "type": "integer"
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "manga_id": {
+                    "description": "MangaID is the manga the chapter was read from.\nIt's 0 when the manga was deleted.",
+                    "type": "integer"
+                },
+                "manga_name": {
+                    "description": "MangaName and Source are stored so the log is kept\nafter the manga is deleted.",
+                    "type": "string"
+                },
+                "multimanga_id": {
+                    "description": "MultiMangaID is 0 when it's a custom manga or the multimanga was deleted.",
+                    "type": "integer"
+                },
+                "previous_chapter": {
+                    "description": "PreviousChapter is the last read chapter before this entry.",
+                    "type": "string"
+                },
+                "read_at": {
+                    "description": "ReadAt is when the user set the chapter as read.",
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
+                }
+            }
+        },
+        "manga.ReadingBreakdown": {
+            "type": "object",
+            "properties": {
+                "chapters_read": {
+                    "type": "integer"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "mangas": {
+                    "type": "integer"
+                }
+            }
+        },
+        "manga.ReadingPeriodStats": {
+            "type": "object",
+            "properties": {
+                "chapters_read": {
+                    "type": "integer"
+                },
+                "mangas": {
+                    "description": "Mangas is the number of different mangas read in the period.",
+                    "type": "integer"
+                },
+                "period_start": {
+                    "description": "PeriodStart is the first day of the period.",
+                    "type": "string"
+                }
+            }
+        },
+        "manga.ReadingStreaks": {
+            "type": "object",
+            "properties": {
+                "current_streak": {
+                    "description": "CurrentStreak is the number of days of the current streak.\nThe streak is still active if the user didn't read anything\ntoday, but read yesterday.",
+                    "type": "integer"
+                },
+                "current_streak_start": {
+                    "type": "string"
+                },
+                "longest_streak": {
+                    "type": "integer"
+                },
+                "longest_streak_end": {
+                    "type": "string"
+                },
+                "longest_streak_start": {
+                    "type": "string"
+                }
+            }
+        },
+        "manga.ReleaseToReadStats": {
+            "type": "object",
+            "properties": {
+                "average_seconds": {
+                    "type": "integer"
+                },
+                "median_seconds": {
+                    "type": "integer"
+                },
+                "samples": {
+                    "description": "Samples is the number of reading activities with\na known chapter release date used in the calculation.",
+                    "type": "integer"
+                }
+            }
+        },
+        "manga.Status": {
+            "type": "integer",
+            "enum": [
+                1,
+                2,
+                3,
+                4,
+                5
+            ],
+            "x-enum-varnames": [
+                "StatusReading",
+                "StatusCompleted",
+                "StatusOnHold",
+                "StatusDropped",
+                "StatusPlanToRead"
+            ]
+        },
+        "models.MangaSearchResult": {
+            "type": "object",
+            "properties": {
+                "coverURL": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "internalID": {
+                    "type": "string"
+                },
+                "lastChapter": {
+                    "type": "string"
+                },
+                "lastChapterURL": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "year": {
+                    "type": "integer"
+                }
+            }
+        },
+        "notifications.Digest": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string"
+                },
+                "groups": {
+                    "description": "Groups are the new chapters grouped by multimanga, sorted by manga name.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/notifications.DigestGroup"
+                    }
+                },
+                "to": {
+                    "type": "string"
+                },
+                "total_chapters": {
+                    "type": "integer"
+                }
+            }
+        },
+        "notifications.DigestEvent": {
+            "type": "object",
+            "properties": {
+                "chapter": {
+                    "type": "string"
+                },
+                "chapter_name": {
+                    "type": "string"
+                },
+                "chapter_url": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "manga_id": {
+                    "type": "integer"
+                },
+                "manga_name": {
+                    "type": "string"
+                },
+                "manga_url": {
+                    "type": "string"
+                },
+                "multimanga_id": {
+                    "type": "integer"
+                },
+                "previous_chapter": {
+                    "description": "PreviousChapter is the last released chapter before this chapter, empty if none.",
+                    "type": "string"
+                },
+                "released_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "notifications.DigestGroup": {
+            "type": "object",
+            "properties": {
+                "chapters": {
+                    "description": "Chapters are the released chapters the update job found, from the first to the last.\nSources can release many chapters at once, so it can have less chapters than ChaptersCount.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/notifications.DigestEvent"
+                    }
+                },
+                "chapters_count": {
+                    "type": "integer"
+                },
+                "first_chapter": {
+                    "description": "FirstChapter and LastChapter are the range of new chapters, like \"11\" and \"30\".\nThey're equal when there is only one new chapter.",
+                    "type": "string"
+                },
+                "last_chapter": {
+                    "type": "string"
+                },
+                "last_chapter_url": {
+                    "type": "string"
+                },
+                "manga_name": {
+                    "type": "string"
+                },
+                "manga_url": {
+                    "type": "string"
+                },
+                "multimanga_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "notifications.QueuedNotification": {
+            "type": "object",
+            "properties": {
+                "chapter": {
+                    "type": "string"
+                },
+                "chapter_name": {
+                    "type": "string"
+                },
+                "chapter_url": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deliver_after": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "manga_id": {
+                    "type": "integer"
+                },
+                "manga_name": {
+                    "type": "string"
+                },
+                "manga_url": {
+                    "type": "string"
+                },
+                "multimanga_id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "description": "Reason is why the notification was deferred, like \"quiet_hours\".",
+                    "type": "string"
+                }
+            }
+        },
+        "notifications.Rules": {
+            "type": "object",
+            "properties": {
+                "digest": {
+                    "description": "Digest buffers the new chapters into a digest sent on the digest schedule.",
+                    "type": "boolean"
+                },
+                "digest_frequency": {
+                    "description": "DigestFrequency, DigestWeekday, and DigestTime are the digest schedule.\nThey're only used in the global rules. DigestFrequency is \"daily\" or \"weekly\",\nDigestWeekday is the weekly digest day, from 0 (Sunday) to 6 (Saturday), and\nDigestTime is when the digest is sent, like \"09:00\".",
+                    "type": "string"
+                },
+                "digest_time": {
+                    "type": "string"
+                },
+                "digest_weekday": {
+                    "type": "integer"
+                },
+                "min_gap_minutes": {
+                    "description": "MinGapMinutes is the minimum time between two notifications of the same multimanga.\nNotifications inside the gap are deferred to the end of the gap.",
+                    "type": "integer"
+                },
+                "multimanga_id": {
+                    "description": "MultiMangaID is 0 for the global rules.",
+                    "type": "integer"
+                },
+                "mute": {
+                    "description": "Mute disables the notifications.",
+                    "type": "boolean"
+                },
+                "numbered_chapters_only": {
+                    "description": "NumberedChaptersOnly notifies only chapters with a number that aren't specials,\nlike \"10\" and \"10.5\", but not \"Extra\" or \"10 Extra\".",
+                    "type": "boolean"
+                },
+                "quiet_hours_end": {
+                    "type": "string"
+                },
+                "quiet_hours_start": {
+                    "description": "QuietHoursStart and QuietHoursEnd are like \"22:00\" and \"07:00\", in the API timezone.\nNotifications during the quiet hours are deferred to the end of the quiet hours.",
+                    "type": "string"
+                }
+            }
+        },
+        "recommendations.GenreWeight": {
+            "type": "object",
+            "properties": {
+                "genre": {
+                    "type": "string"
+                },
+                "weight": {
+                    "type": "number"
+                }
+            }
+        },
+        "recommendations.Profile": {
+            "type": "object",
+            "properties": {
+                "genres": {
+                    "description": "Genres are the genres of the seeds weighted by the seeds' status, from the highest weight.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/recommendations.GenreWeight"
+                    }
+                },
+                "seeds": {
+                    "description": "Seeds are the names of the library mangas used to find the recommendations.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "recommendations.Recommendation": {
+            "type": "object",
+            "properties": {
+                "cover_url": {
+                    "type": "string"
+                },
+                "internal_id": {
+                    "type": "string"
+                },
+                "matched_genres": {
+                    "description": "MatchedGenres are the title's genres in the profile's top genres.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "Reason explains the score, like \"Similar to Berserk (reading)\".",
+                    "type": "string"
+                },
+                "score": {
+                    "description": "Score is the sum of the weights of the library mangas the title is similar\nto, plus 1 for each of its genres in the profile's top genres.",
+                    "type": "number"
+                },
+                "similar_to": {
+                    "description": "SimilarTo are the names of the library mangas the title is similar to.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "source": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "routes.AddCustomMangaChaptersRequest": {
+            "type": "object",
+            "required": [
+                "chapters"
+            ],
+            "properties": {
+                "chapters": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "object",
+                        "required": [
+                            "chapter"
+                        ],
+                        "properties": {
+                            "chapter": {
+                                "type": "string"
+                            },
+                            "name": {
+                                "type": "string"
+                            },
+                            "released_at": {
+                                "type": "string"
+                            },
+                            "url": {
                                 "type": "string"
                             }
                         }
                     }
+                }
+            }
+        },
+        "routes.AddCustomMangaRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "status"
+            ],
+            "properties": {
+                "cover_img": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
-                "mantium": {
+                "cover_img_url": {
+                    "type": "string"
+                },
+                "manga_has_more_chapters": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "next_chapter": {
                     "type": "object",
                     "properties": {
-                        "version": {
+                        "chapter": {
+                            "type": "string"
+                        },
+                        "url": {
                             "type": "string"
                         }
                     }
+                },
+                "status": {
+                    "type": "integer",
+                    "maximum": 5,
+                    "minimum": 0
+                },
+                "url": {
+                    "type": "string"
                 }
             }
         },
-        "dashboard.BackgroundError": {
+        "routes.AddMangaRequest": {
             "type": "object",
+            "required": [
+                "status",
+                "url"
+            ],
             "properties": {
-                "message": {
-                    "description": "Error message.",
+                "last_read_chapter": {
                     "type": "string"
                 },
-                "time": {
-                    "description": "Time when the error occurred.",
+                "last_read_chapter_internal_id": {
+                    "type": "string"
+                },
+                "last_read_chapter_url": {
+                    "type": "string"
+                },
+                "manga_internal_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer",
+                    "maximum": 5,
+                    "minimum": 0
+                },
+                "url": {
                     "type": "string"
                 }
             }
         },
-        "manga.Chapter": {
+        "routes.AddMangaToMultiMangaRequest": {
             "type": "object",
+            "required": [
+                "manga_url"
+            ],
             "properties": {
-                "chapter": {
-                    "description": "Chapter usually is the chapter number, but in some cases it can be a one-shot or a special chapter",
+                "manga_internal_id": {
                     "type": "string"
                 },
-                "internalID": {
-                    "description": "InteralID is a unique identifier for the chapter in the source",
+                "manga_url": {
                     "type": "string"
-                },
-                "name": {
-                    "description": "Name is the name of the chapter",
+                }
+            }
+        },
+        "routes.AddRecommendationRequest": {
+            "type": "object",
+            "required": [
+                "url"
+            ],
+            "properties": {
+                "internal_id": {
                     "type": "string"
                 },
-                "type": {
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "routes.AddRelatedMultiMangaRequest": {
+            "type": "object",
+            "required": [
+                "manga_id",
+                "url"
+            ],
+            "properties": {
+                "manga_id": {
+                    "description": "MangaID is the ID of the manga the title is related to.",
                     "type": "integer"
                 },
-                "updatedAt": {
-                    "description": "UpdatedAt is the time when the chapter was released or updated (read).\nShould truncate at the second.\nThe timezone should be the default/system timezone.",
-                    "type": "string"
+                "status": {
+                    "description": "Status is the new multimanga status. Defaults to 5 (plan to read).",
+                    "type": "integer",
+                    "maximum": 5,
+                    "minimum": 0
                 },
                 "url": {
-                    "description": "URL is the URL of the chapter\nIf custom manga chapter doesn't have a URL provided by the user, it should be like http://custom_manga/\u003cuuid\u003e.",
+                    "description": "URL is the related title URL.",
                     "type": "string"
                 }
             }
         },
-        "manga.Manga": {
+        "routes.BulkMultiMangaResult": {
             "type": "object",
             "properties": {
-                "coverImg": {
-                    "description": "CoverImg is the cover image of the manga",
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
+                "error": {
+                    "type": "string"
                 },
-                "coverImgFixed": {
-                    "description": "CoverImgFixed is true if the cover image is fixed. If true, the cover image will not be updated when updating the manga metadata.\nIt's used for when the cover image is manually set by the user.",
-                    "type": "boolean"
+                "job_id": {
+                    "description": "JobID is the ID of the job enqueued by the push_to_integration operation.",
+                    "type": "integer"
                 },
-                "coverImgResized": {
-                    "description": "CoverImgResized is true if the cover image was resized",
-                    "type": "boolean"
+                "multimanga_id": {
+                    "type": "integer"
                 },
-                "coverImgURL": {
-                    "description": "CoverImgURL is the URL of the cover image",
+                "skipped": {
+                    "description": "Skipped is why the operation skipped the multimanga, like the push_to_integration\noperation with a custom manga. Skipped multimangas are successful.",
                     "type": "string"
                 },
-                "id": {
-                    "type": "integer"
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "routes.BulkMultiMangasRequest": {
+            "type": "object",
+            "required": [
+                "operation"
+            ],
+            "properties": {
+                "filter": {
+                    "$ref": "#/definitions/manga.MultiMangaFilter"
                 },
-                "internalID": {
-                    "description": "InteralID is a unique identifier for the manga in the source",
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "integration": {
+                    "description": "Integration is the download integration of the push_to_integration operation, like \"suwayomi\".",
                     "type": "string"
                 },
-                "lastReadChapter": {
-                    "description": "LastReadChapter is the last chapter read by the user\nIn a custom manga, this field represents the next manga the user should read\nor, if it's equal to the last released chapter, the manga is considered read.",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/manga.Chapter"
-                        }
+                "operation": {
+                    "description": "Operation is one of the bulk operations, like \"set_status\".",
+                    "type": "string",
+                    "enum": [
+                        "set_status",
+                        "mark_latest_read",
+                        "delete",
+                        "add_tag",
+                        "push_to_integration"
                     ]
                 },
-                "lastReleasedChapter": {
-                    "description": "LastReleasedChapter is the last chapter released by the source\nIf the custom manga has no more released chapter, it'll be equal to the LastReadChapter.",
+                "status": {
+                    "description": "Status is the new status of the set_status operation.",
+                    "maximum": 5,
+                    "minimum": 0,
                     "allOf": [
                         {
-                            "$ref": "#/definitions/manga.Chapter"
+                            "$ref": "#/definitions/manga.Status"
                         }
                     ]
                 },
-                "multiMangaID": {
-                    "description": "When the manga is part of a multimanga, this field should be set to the multimanga ID",
-                    "type": "integer"
-                },
-                "name": {
-                    "description": "Name is the name of the manga",
+                "tag": {
+                    "description": "Tag is the tag of the add_tag operation.",
                     "type": "string"
-                },
-                "preferredGroup": {
-                    "description": "PreferredGroup is the preferred group that translates (and more) the manga.\nNot all sources have multiple groups. Currently not used.",
+                }
+            }
+        },
+        "routes.MarkChapterRangeRequest": {
+            "type": "object",
+            "required": [
+                "from_chapter",
+                "read",
+                "to_chapter"
+            ],
+            "properties": {
+                "from_chapter": {
                     "type": "string"
                 },
-                "searchNames": {
-                    "description": "SearchNames should be the multimanga's mangas names.\nUsed for searching mangas by name.",
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "read": {
+                    "type": "boolean"
                 },
-                "source": {
-                    "description": "Source is the source of the manga, usually the domain of the website.\nIf source is the above CustomMangaSource const, it means the manga is a custom manga created by the user.\nand without a source site.",
+                "to_chapter": {
                     "type": "string"
-                },
-                "status": {
-                    "type": "integer"
-                },
-                "url": {
-                    "description": "URL is the URL of the manga.\nIf custom manga doesn't have a URL provided by the user, it should be like above CustomMangaSource/\u003cuuid\u003e.",
+                }
+            }
+        },
+        "routes.MarkChaptersReadUpToRequest": {
+            "type": "object",
+            "required": [
+                "chapter"
+            ],
+            "properties": {
+                "chapter": {
                     "type": "string"
                 }
             }
         },
-        "manga.MultiManga": {
+        "routes.MarkChaptersRequest": {
             "type": "object",
+            "required": [
+                "chapters",
+                "read"
+            ],
             "properties": {
-                "coverImg": {
-                    "description": "CoverImg is the cover image of the multimanga",
+                "chapters": {
                     "type": "array",
+                    "minItems": 1,
                     "items": {
-                        "type": "integer"
+                        "type": "string"
                     }
                 },
-                "coverImgFixed": {
-                    "description": "CoverImgFixed is true if the cover image is fixed. If false (default) the current manga's cover image should be used.\nElse, use the multimanga's cover image fields.\nIt's used for when the cover image is manually set by the user.",
+                "read": {
                     "type": "boolean"
+                }
+            }
+        },
+        "routes.SearchMangaRequest": {
+            "type": "object",
+            "required": [
+                "q",
+                "source"
+            ],
+            "properties": {
+                "limit": {
+                    "type": "integer"
                 },
-                "coverImgResized": {
-                    "description": "CoverImgResized is true if the cover image was resized",
-                    "type": "boolean"
+                "q": {
+                    "type": "string"
                 },
-                "coverImgURL": {
-                    "description": "CoverImgURL is the URL of the cover image",
+                "source": {
+                    "type": "string"
+                }
+            }
+        },
+        "routes.SetCustomMangaWatcherRequest": {
+            "type": "object",
+            "required": [
+                "url"
+            ],
+            "properties": {
+                "regex": {
                     "type": "string"
                 },
-                "currentManga": {
-                    "$ref": "#/definitions/manga.Manga"
+                "selector": {
+                    "type": "string"
                 },
-                "id": {
-                    "type": "integer"
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "routes.SetMangaChapterPreferencesRequest": {
+            "type": "object",
+            "properties": {
+                "blocked_groups": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "lastReadChapter": {
-                    "$ref": "#/definitions/manga.Chapter"
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "mangas": {
+                "languages": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/manga.Manga"
+                        "type": "string"
                     }
                 },
-                "status": {
-                    "description": "All mangas in the multimanga should have the same status",
-                    "type": "integer"
+                "wait_hours": {
+                    "type": "integer",
+                    "minimum": 0
                 }
             }
         },
-        "models.MangaSearchResult": {
+        "routes.SetMultiMangaExternalIDsRequest": {
             "type": "object",
             "properties": {
-                "coverURL": {
-                    "type": "string"
-                },
-                "description": {
+                "anilist_id": {
                     "type": "string"
                 },
-                "internalID": {
-                    "type": "string"
-                },
-                "lastChapter": {
-                    "type": "string"
+                "automatic_ids": {
+                    "description": "AutomaticIDs are the names of the IDs that stop being manual, like \"anilist_id\".",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "lastChapterURL": {
+                "mal_id": {
                     "type": "string"
                 },
-                "name": {
+                "mangaupdates_id": {
                     "type": "string"
-                },
-                "source": {
+                }
+            }
+        },
+        "routes.UpdateMangaChapterRequest": {
+            "type": "object",
+            "properties": {
+                "chapter": {
                     "type": "string"
                 },
-                "status": {
+                "chapter_internal_id": {
                     "type": "string"
                 },
-                "url": {
+                "chapter_url": {
                     "type": "string"
-                },
-                "year": {
-                    "type": "integer"
                 }
             }
         },
-        "routes.AddCustomMangaRequest": {
+        "routes.UpdateMangaStatusRequest": {
             "type": "object",
             "required": [
-                "name",
                 "status"
             ],
             "properties": {
-                "cover_img": {
+                "status": {
+                    "maximum": 5,
+                    "minimum": 0,
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/manga.Status"
+                        }
+                    ]
+                }
+            }
+        },
+        "routes.UpdateTagsRequest": {
+            "type": "object",
+            "required": [
+                "tags"
+            ],
+            "properties": {
+                "tags": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "type": "string"
                     }
+                }
+            }
+        },
+        "routes.ValidateScraperSourceRequest": {
+            "type": "object",
+            "required": [
+                "config",
+                "url"
+            ],
+            "properties": {
+                "config": {
+                    "$ref": "#/definitions/scraper.Config"
                 },
-                "cover_img_url": {
+                "search_term": {
+                    "description": "SearchTerm is searched if provided.",
                     "type": "string"
                 },
-                "manga_has_more_chapters": {
-                    "type": "boolean"
+                "url": {
+                    "description": "URL is the URL of a manga in the source.",
+                    "type": "string"
+                }
+            }
+        },
+        "routes.ValidateScraperSourceResult": {
+            "type": "object",
+            "properties": {
+                "chapters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/manga.Chapter"
+                    }
                 },
-                "name": {
+                "cover_img_url": {
                     "type": "string"
                 },
-                "next_chapter": {
+                "errors": {
+                    "description": "Errors are the errors of each step: manga, chapters, and search.",
                     "type": "object",
-                    "properties": {
-                        "chapter": {
-                            "type": "string"
-                        },
-                        "url": {
-                            "type": "string"
-                        }
+                    "additionalProperties": {
+                        "type": "string"
                     }
                 },
-                "status": {
-                    "type": "integer",
-                    "maximum": 5,
-                    "minimum": 0
+                "last_released_chapter": {
+                    "$ref": "#/definitions/manga.Chapter"
                 },
-                "url": {
+                "name": {
                     "type": "string"
+                },
+                "search_results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MangaSearchResult"
+                    }
                 }
             }
         },
-        "routes.AddMangaRequest": {
+        "routes.bulkMultiMangasResponse": {
             "type": "object",
-            "required": [
-                "status",
-                "url"
-            ],
             "properties": {
-                "last_read_chapter": {
+                "message": {
                     "type": "string"
                 },
-                "last_read_chapter_internal_id": {
-                    "type": "string"
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/routes.BulkMultiMangaResult"
+                    }
+                }
+            }
+        },
+        "routes.notificationRulesResponse": {
+            "type": "object",
+            "properties": {
+                "effective_rules": {
+                    "description": "EffectiveRules are the rules with the null fields inherited from the global rules.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/notifications.Rules"
+                        }
+                    ]
                 },
-                "last_read_chapter_url": {
-                    "type": "string"
+                "rules": {
+                    "$ref": "#/definitions/notifications.Rules"
+                }
+            }
+        },
+        "routes.notificationsDigestResponse": {
+            "type": "object",
+            "properties": {
+                "digest": {
+                    "$ref": "#/definitions/notifications.Digest"
                 },
-                "manga_internal_id": {
+                "next_digest_at": {
                     "type": "string"
+                }
+            }
+        },
+        "routes.notificationsQueueResponse": {
+            "type": "object",
+            "properties": {
+                "queue": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/notifications.QueuedNotification"
+                    }
+                }
+            }
+        },
+        "routes.recommendationsResponse": {
+            "type": "object",
+            "properties": {
+                "profile": {
+                    "$ref": "#/definitions/recommendations.Profile"
                 },
-                "status": {
-                    "type": "integer",
-                    "maximum": 5,
-                    "minimum": 0
-                },
-                "url": {
-                    "type": "string"
+                "recommendations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/recommendations.Recommendation"
+                    }
                 }
             }
         },
-        "routes.AddMangaToMultiMangaRequest": {
+        "routes.responseMessage": {
             "type": "object",
-            "required": [
-                "manga_url"
-            ],
             "properties": {
-                "manga_internal_id": {
+                "message": {
                     "type": "string"
+                }
+            }
+        },
+        "routes.responseMessageWithJobs": {
+            "type": "object",
+            "properties": {
+                "jobs": {
+                    "description": "Jobs are the IDs of the enqueued jobs",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
-                "manga_url": {
+                "message": {
                     "type": "string"
                 }
             }
         },
-        "routes.SearchMangaRequest": {
+        "scraper.Config": {
             "type": "object",
-            "required": [
-                "q",
-                "source"
-            ],
             "properties": {
-                "limit": {
-                    "type": "integer"
+                "base_url": {
+                    "description": "BaseURL is the site URL, like \"https://mysite.com\".",
+                    "type": "string"
                 },
-                "q": {
+                "chapters": {
+                    "description": "Chapters are the fields of the chapter list in the manga page.",
+                    "type": "object",
+                    "properties": {
+                        "date": {
+                            "$ref": "#/definitions/scraper.Field"
+                        },
+                        "date_format": {
+                            "description": "DateFormat is the Go layout of the chapter date, like \"January 2, 2006\".",
+                            "type": "string"
+                        },
+                        "list": {
+                            "description": "List is the selector of each chapter element in the manga page.\nThe other chapter fields are selected inside each chapter element.",
+                            "type": "string"
+                        },
+                        "name": {
+                            "$ref": "#/definitions/scraper.Field"
+                        },
+                        "number": {
+                            "description": "Number is the chapter number field. If it's empty, the number is the first number in the chapter name.",
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/scraper.Field"
+                                }
+                            ]
+                        },
+                        "oldest_first": {
+                            "description": "OldestFirst is true if the page lists the oldest chapter first.",
+                            "type": "boolean"
+                        },
+                        "url": {
+                            "description": "URL is the chapter URL field. It defaults to the \"href\" attribute of the chapter element.",
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/scraper.Field"
+                                }
+                            ]
+                        }
+                    }
+                },
+                "manga": {
+                    "description": "Manga are the fields of the manga page.",
+                    "type": "object",
+                    "properties": {
+                        "cover": {
+                            "$ref": "#/definitions/scraper.Field"
+                        },
+                        "name": {
+                            "$ref": "#/definitions/scraper.Field"
+                        }
+                    }
+                },
+                "name": {
+                    "description": "Name is the source name, like \"mysite\". The source is used for\nthe mangas whose URL host is the BaseURL host.",
                     "type": "string"
                 },
-                "source": {
+                "search": {
+                    "description": "Search are the fields of the search page.",
+                    "type": "object",
+                    "properties": {
+                        "cover": {
+                            "$ref": "#/definitions/scraper.Field"
+                        },
+                        "last_chapter": {
+                            "$ref": "#/definitions/scraper.Field"
+                        },
+                        "name": {
+                            "$ref": "#/definitions/scraper.Field"
+                        },
+                        "results": {
+                            "description": "Results is the selector of each search result element.\nThe other search fields are selected inside each result element.",
+                            "type": "string"
+                        },
+                        "url": {
+                            "$ref": "#/definitions/scraper.Field"
+                        }
+                    }
+                },
+                "search_url": {
+                    "description": "SearchURL is the search page URL. \"{term}\" is replaced by the search term.\nIf it's empty, the source doesn't support searching.",
                     "type": "string"
                 }
             }
         },
-        "routes.UpdateMangaChapterRequest": {
+        "scraper.Field": {
             "type": "object",
             "properties": {
-                "chapter": {
+                "attr": {
+                    "description": "Attr is the attribute with the value, like \"href\". If it's empty, the element text is used.",
                     "type": "string"
                 },
-                "chapter_internal_id": {
+                "regex": {
+                    "description": "Regex is matched against the value. If it has a capturing group, the value is the first group, else the whole match.",
                     "type": "string"
                 },
-                "chapter_url": {
+                "selector": {
+                    "description": "Selector is the CSS selector of the element. If it's empty, the current element is used,\nlike the chapter element.",
                     "type": "string"
                 }
             }
         },
-        "routes.UpdateMangaStatusRequest": {
+        "webhooks.ChapterReadEvent": {
             "type": "object",
             "required": [
-                "status"
+                "manga_url"
             ],
             "properties": {
-                "status": {
-                    "type": "integer",
-                    "maximum": 5,
-                    "minimum": 0
-                }
-            }
-        },
-        "routes.responseMessage": {
-            "type": "object",
-            "properties": {
-                "message": {
+                "chapter": {
+                    "description": "Chapter and ChapterURL are the chapter read. At least one must be set.",
+                    "type": "string"
+                },
+                "chapter_url": {
+                    "type": "string"
+                },
+                "idempotency_key": {
+                    "description": "IdempotencyKey identifies the event, so retries don't process it again.\nIt can also be sent in the Idempotency-Key header.",
+                    "type": "string"
+                },
+                "manga_url": {
+                    "description": "MangaURL is the manga URL in its source, like https://mangadex.org/title/...",
                     "type": "string"
                 }
             }
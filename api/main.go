@@ -16,10 +16,12 @@ import (
 	"github.com/diogovalentte/mantium/api/src/config"
 	"github.com/diogovalentte/mantium/api/src/dashboard"
 	"github.com/diogovalentte/mantium/api/src/db"
+	"github.com/diogovalentte/mantium/api/src/jobs"
 	"github.com/diogovalentte/mantium/api/src/manga"
 	"github.com/diogovalentte/mantium/api/src/sources"
 	"github.com/diogovalentte/mantium/api/src/sources/mangadex"
 	"github.com/diogovalentte/mantium/api/src/sources/mangahub"
+	"github.com/diogovalentte/mantium/api/src/telemetry"
 	"github.com/diogovalentte/mantium/api/src/util"
 )
 
@@ -35,6 +37,22 @@ func init() {
 	logLevel, _ := zerolog.ParseLevel(strconv.Itoa(logLevelInt))
 	log := util.GetLogger(logLevel)
 
+	if config.GlobalConfigs.Suwayomi.SourceEnabled {
+		log.Info().Msg("Registering the Suwayomi source...")
+		sources.RegisterSuwayomiSource(config.GlobalConfigs.Suwayomi.Address, config.GlobalConfigs.Suwayomi.Username, config.GlobalConfigs.Suwayomi.Password, config.GlobalConfigs.Suwayomi.SearchSources)
+	}
+	if config.GlobalConfigs.ScraperSources.Dir != "" {
+		log.Info().Msgf("Registering scraper sources from %s...", config.GlobalConfigs.ScraperSources.Dir)
+		names, err := sources.RegisterScraperSources(config.GlobalConfigs.ScraperSources.Dir)
+		if err != nil {
+			panic(err)
+		}
+		// The scraper sources are always allowed
+		config.SourcesList = append(config.SourcesList, names...)
+		config.GlobalConfigs.DashboardConfigs.Manga.AllowedSources = append(config.GlobalConfigs.DashboardConfigs.Manga.AllowedSources, names...)
+		log.Info().Msgf("Registered scraper sources: %s", strings.Join(names, ", "))
+	}
+
 	log.Info().Msg("Trying to connect to DB...")
 	_db, err := db.OpenConn()
 	if err != nil {
@@ -97,7 +115,26 @@ func init() {
 		}
 	}
 
+	if config.GlobalConfigs.Tracing.Valid {
+		// The spans not exported yet are lost when the API stops
+		_, err = telemetry.InitTracing(config.GlobalConfigs.Tracing.Endpoint, config.GlobalConfigs.Tracing.Headers, config.GlobalConfigs.Tracing.ServiceName)
+		if err != nil {
+			panic(err)
+		}
+		log.Info().Msgf("Will export the traces to %s", config.GlobalConfigs.Tracing.Endpoint)
+	} else {
+		log.Info().Msg("Will not export traces")
+	}
+
+	log.Info().Msgf("Starting the jobs worker, checking for jobs every %d seconds...", config.GlobalConfigs.Jobs.PollSeconds)
+	err = jobs.StartWorker(time.Duration(config.GlobalConfigs.Jobs.PollSeconds)*time.Second, log)
+	if err != nil {
+		panic(err)
+	}
+
 	setUpdateMangasMetadataPeriodicallyJob(log)
+	setReconcileDownloadIntegrationsPeriodicallyJob(log)
+	setSendDueNotificationsPeriodicallyJob(log)
 	dashboard.UpdateDashboard()
 
 	if config.GlobalConfigs.Kaizoku.Valid {
@@ -181,6 +218,97 @@ func setUpdateMangasMetadataPeriodicallyJob(log *zerolog.Logger) {
 	}
 }
 
+// setReconcileDownloadIntegrationsPeriodicallyJob sets a job to reconcile the download
+// integrations' libraries with Mantium's library periodically in another goroutine.
+func setReconcileDownloadIntegrationsPeriodicallyJob(log *zerolog.Logger) {
+	configs := config.GlobalConfigs.DownloadIntegrations
+	if configs.ReconcileMinutes <= 0 {
+		log.Info().Msg("Not reconciling the download integrations periodically")
+		return
+	}
+	if !config.GlobalConfigs.Kaizoku.Valid && !config.GlobalConfigs.Tranga.Valid && !config.GlobalConfigs.Suwayomi.Valid {
+		log.Info().Msg("Not reconciling the download integrations periodically because no download integration is configured")
+		return
+	}
+
+	log.Info().Msgf("Will reconcile the download integrations every %d minutes", configs.ReconcileMinutes)
+	if configs.ReconcileRemove {
+		log.Info().Msg("Will remove the mangas that are not in Mantium from the download integrations")
+	}
+
+	go func() {
+		for {
+			time.Sleep(time.Duration(configs.ReconcileMinutes) * time.Minute)
+
+			log.Info().Msg("Reconciling the download integrations...")
+			res, err := util.RequestReconcileDownloadIntegrations(configs.ReconcileRemove)
+			if err != nil {
+				errMessage := fmt.Sprintf("Error reconciling the download integrations in background: %s", err)
+				log.Error().Msgf(errMessage)
+
+				if res != nil {
+					var respMessage string
+					body, err := io.ReadAll(res.Body)
+					if err != nil {
+						respMessage = fmt.Sprintf("Error while reading response body: %s", err)
+					} else {
+						respMessage = fmt.Sprintf("Request response text: %s", string(body))
+					}
+					log.Error().Msgf(respMessage)
+					dashboard.SetLastBackgroundError(fmt.Sprintf("%s\n%s", errMessage, respMessage))
+					res.Body.Close() // cannot be defer because it's an infinite loop
+				} else {
+					dashboard.SetLastBackgroundError(fmt.Sprintf("%s\n%s", errMessage, "No response to get the body"))
+				}
+			} else {
+				res.Body.Close()
+				log.Info().Msg("Download integrations reconciled")
+			}
+		}
+	}()
+}
+
+// setSendDueNotificationsPeriodicallyJob sets a job to send the notifications deferred
+// by the notification rules and the digest when they're due in another goroutine.
+func setSendDueNotificationsPeriodicallyJob(log *zerolog.Logger) {
+	minutes := config.GlobalConfigs.Notifications.SendQueueMinutes
+	if minutes <= 0 {
+		log.Info().Msg("Will send the deferred notifications only when updating the mangas metadata")
+		return
+	}
+
+	log.Info().Msgf("Will send the due deferred notifications every %d minutes", minutes)
+
+	go func() {
+		for {
+			time.Sleep(time.Duration(minutes) * time.Minute)
+
+			res, err := util.RequestSendDueNotifications()
+			if err != nil {
+				errMessage := fmt.Sprintf("Error sending the due notifications in background: %s", err)
+				log.Error().Msgf(errMessage)
+
+				if res != nil {
+					var respMessage string
+					body, err := io.ReadAll(res.Body)
+					if err != nil {
+						respMessage = fmt.Sprintf("Error while reading response body: %s", err)
+					} else {
+						respMessage = fmt.Sprintf("Request response text: %s", string(body))
+					}
+					log.Error().Msgf(respMessage)
+					dashboard.SetLastBackgroundError(fmt.Sprintf("%s\n%s", errMessage, respMessage))
+					res.Body.Close() // cannot be defer because it's an infinite loop
+				} else {
+					dashboard.SetLastBackgroundError(fmt.Sprintf("%s\n%s", errMessage, "No response to get the body"))
+				}
+			} else {
+				res.Body.Close()
+			}
+		}
+	}()
+}
+
 // Migration to be applied if current version stored in DB is lower than the field Version.
 type Migration struct {
 	Version string
@@ -0,0 +1,132 @@
+// Package actions implements the signed, short-lived action links,
+// like the link to mark a chapter as read from a notification.
+package actions
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/diogovalentte/mantium/api/src/config"
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// TypeMarkRead is the action that sets a multimanga last read chapter.
+const TypeMarkRead = "mark_read"
+
+// MarkReadPath is the API path of the mark read action.
+const MarkReadPath = "/v1/actions/mark_read"
+
+// Action is an action signed in a link.
+type Action struct {
+	Type         string   `json:"t"`
+	MultiMangaID manga.ID `json:"mm"`
+	MangaID      manga.ID `json:"m"`
+	// Chapter and ChapterURL are the chapter to mark as read.
+	Chapter    string `json:"c"`
+	ChapterURL string `json:"u,omitempty"`
+	// ExpiresAt is a Unix timestamp.
+	ExpiresAt int64 `json:"e"`
+}
+
+// NewMarkReadAction returns an action that marks the manga's last
+// released chapter as read. The manga must be from a multimanga.
+func NewMarkReadAction(m *manga.Manga, ttl time.Duration, now time.Time) (*Action, error) {
+	if m.MultiMangaID == 0 {
+		return nil, fmt.Errorf("manga '%s' is not from a multimanga", m)
+	}
+	if m.LastReleasedChapter == nil {
+		return nil, util.AddErrorContext(fmt.Sprintf("error creating mark read action of manga '%s'", m), errordefs.ErrLastReleasedChapterNotFound)
+	}
+
+	return &Action{
+		Type:         TypeMarkRead,
+		MultiMangaID: m.MultiMangaID,
+		MangaID:      m.ID,
+		Chapter:      m.LastReleasedChapter.Chapter,
+		ChapterURL:   m.LastReleasedChapter.URL,
+		ExpiresAt:    now.Add(ttl).Unix(),
+	}, nil
+}
+
+// Sign returns the action token signed with the secret.
+// The token is the base64 encoded action and its HMAC-SHA256 signature.
+func Sign(action *Action, secret []byte) (string, error) {
+	payload, err := json.Marshal(action)
+	if err != nil {
+		return "", util.AddErrorContext("error signing action", err)
+	}
+	encodedPayload := base64.RawURLEncoding.EncodeToString(payload)
+
+	return encodedPayload + "." + base64.RawURLEncoding.EncodeToString(sign(encodedPayload, secret)), nil
+}
+
+// Verify returns the action of the token if its signature is valid and it didn't expire.
+func Verify(token string, secret []byte, now time.Time) (*Action, error) {
+	encodedPayload, encodedSignature, found := strings.Cut(token, ".")
+	if !found {
+		return nil, errordefs.ErrActionLinkInvalid
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil {
+		return nil, errordefs.ErrActionLinkInvalid
+	}
+	if !hmac.Equal(signature, sign(encodedPayload, secret)) {
+		return nil, errordefs.ErrActionLinkInvalid
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return nil, errordefs.ErrActionLinkInvalid
+	}
+	var action Action
+	err = json.Unmarshal(payload, &action)
+	if err != nil {
+		return nil, errordefs.ErrActionLinkInvalid
+	}
+	if now.Unix() > action.ExpiresAt {
+		return nil, errordefs.ErrActionLinkExpired
+	}
+
+	return &action, nil
+}
+
+func sign(payload string, secret []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+// MarkReadLink returns the signed link that marks the manga's last released
+// chapter as read, using the action links configs. If apiURL is empty,
+// the action links API URL is used.
+func MarkReadLink(apiURL string, m *manga.Manga, now time.Time) (string, error) {
+	configs := config.GlobalConfigs.ActionLinks
+	if !configs.Valid {
+		return "", errordefs.ErrActionLinksDisabled
+	}
+	if apiURL == "" {
+		apiURL = configs.APIURL
+	}
+	if apiURL == "" {
+		return "", fmt.Errorf("the ACTION_LINKS_API_URL environment variable must be set to create action links")
+	}
+
+	action, err := NewMarkReadAction(m, configs.TTL, now)
+	if err != nil {
+		return "", err
+	}
+	token, err := Sign(action, configs.Secret)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSuffix(apiURL, "/") + MarkReadPath + "?token=" + url.QueryEscape(token), nil
+}
@@ -0,0 +1,120 @@
+package actions
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diogovalentte/mantium/api/src/config"
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/manga"
+)
+
+var (
+	secret = []byte("0123456789abcdef0123456789abcdef")
+	now    = time.Date(2024, 5, 10, 9, 0, 0, 0, time.UTC)
+	m      = &manga.Manga{
+		ID:                  2,
+		MultiMangaID:        1,
+		Name:                "Berserk",
+		LastReleasedChapter: &manga.Chapter{Chapter: "10", URL: "https://mangadex.org/chapter/10"},
+	}
+)
+
+func TestSignAndVerify(t *testing.T) {
+	action, err := NewMarkReadAction(m, time.Hour, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := Sign(action, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Should verify a valid token", func(t *testing.T) {
+		verified, err := Verify(token, secret, now.Add(30*time.Minute))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if *verified != *action {
+			t.Fatalf("expected %+v, got %+v", action, verified)
+		}
+	})
+	t.Run("Should not verify an expired token", func(t *testing.T) {
+		_, err := Verify(token, secret, now.Add(2*time.Hour))
+		if err != errordefs.ErrActionLinkExpired {
+			t.Fatalf("expected expired error, got %v", err)
+		}
+	})
+	t.Run("Should not verify a token signed with another secret", func(t *testing.T) {
+		_, err := Verify(token, []byte("another secret with 32 characters"), now)
+		if err != errordefs.ErrActionLinkInvalid {
+			t.Fatalf("expected invalid error, got %v", err)
+		}
+	})
+	t.Run("Should not verify a tampered token", func(t *testing.T) {
+		tampered := *action
+		tampered.Chapter = "100"
+		tamperedToken, err := Sign(&tampered, secret)
+		if err != nil {
+			t.Fatal(err)
+		}
+		payload, _, _ := strings.Cut(tamperedToken, ".")
+		_, signature, _ := strings.Cut(token, ".")
+		_, err = Verify(payload+"."+signature, secret, now)
+		if err != errordefs.ErrActionLinkInvalid {
+			t.Fatalf("expected invalid error, got %v", err)
+		}
+	})
+	t.Run("Should not verify a malformed token", func(t *testing.T) {
+		for _, malformed := range []string{"", "abc", "abc.def", "." + token} {
+			_, err := Verify(malformed, secret, now)
+			if err != errordefs.ErrActionLinkInvalid {
+				t.Fatalf("expected invalid error for '%s', got %v", malformed, err)
+			}
+		}
+	})
+}
+
+func TestNewMarkReadAction(t *testing.T) {
+	_, err := NewMarkReadAction(&manga.Manga{ID: 1, LastReleasedChapter: &manga.Chapter{Chapter: "1"}}, time.Hour, now)
+	if err == nil {
+		t.Fatal("expected error for manga without multimanga")
+	}
+	_, err = NewMarkReadAction(&manga.Manga{ID: 1, MultiMangaID: 1}, time.Hour, now)
+	if err == nil {
+		t.Fatal("expected error for manga without last released chapter")
+	}
+}
+
+func TestMarkReadLink(t *testing.T) {
+	previousConfigs := *config.GlobalConfigs.ActionLinks
+	defer func() { *config.GlobalConfigs.ActionLinks = previousConfigs }()
+
+	*config.GlobalConfigs.ActionLinks = config.ActionLinksConfigs{}
+	_, err := MarkReadLink("http://localhost:8080", m, now)
+	if err != errordefs.ErrActionLinksDisabled {
+		t.Fatalf("expected disabled error, got %v", err)
+	}
+
+	*config.GlobalConfigs.ActionLinks = config.ActionLinksConfigs{Secret: secret, TTL: time.Hour, APIURL: "https://mantium.domain.com", Valid: true}
+	link, err := MarkReadLink("", m, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := url.Parse(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Host != "mantium.domain.com" || parsed.Path != MarkReadPath {
+		t.Fatalf("unexpected link: %s", link)
+	}
+	action, err := Verify(parsed.Query().Get("token"), secret, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if action.MultiMangaID != 1 || action.MangaID != 2 || action.Chapter != "10" {
+		t.Fatalf("unexpected action: %+v", action)
+	}
+}
@@ -8,11 +8,13 @@ import (
 
 	docs "github.com/diogovalentte/mantium/api/docs"
 	"github.com/diogovalentte/mantium/api/src/routes"
+	"github.com/diogovalentte/mantium/api/src/telemetry"
 )
 
 // SetupRouter sets up the routes for the API
 func SetupRouter() *gin.Engine {
 	router := gin.Default()
+	router.Use(telemetry.GinMiddleware())
 
 	docs.SwaggerInfo.Title = "Mantium API"
 	docs.SwaggerInfo.Description = "API for Mantium, a manga dashboard."
@@ -29,7 +31,41 @@ func SetupRouter() *gin.Engine {
 	{
 		routes.DashboardRoutes(v1)
 	}
+	{
+		routes.StatsRoutes(v1)
+	}
+	{
+		routes.NotificationsRoutes(v1)
+	}
+	{
+		routes.FeedRoutes(v1)
+	}
+	{
+		routes.ActionsRoutes(v1)
+	}
+	{
+		routes.OPDSRoutes(v1)
+	}
+	{
+		routes.DownloadIntegrationsRoutes(v1)
+	}
+	{
+		routes.WebhooksRoutes(v1)
+	}
+	{
+		routes.JobsRoutes(v1)
+	}
+	{
+		routes.SourcesRoutes(v1)
+	}
+	{
+		routes.RecommendationsRoutes(v1)
+	}
+	{
+		routes.AdminRoutes(v1)
+	}
 
+	router.GET("/metrics", gin.WrapH(telemetry.MetricsHandler()))
 	v1.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
 
 	return router
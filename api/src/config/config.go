@@ -26,6 +26,19 @@ var GlobalConfigs = &Configs{
 	Kaizoku:                  &KaizokuConfigs{},
 	Tranga:                   &TrangaConfigs{},
 	Suwayomi:                 &SuwayomiConfigs{},
+	Komga:                    &KomgaConfigs{},
+	Kavita:                   &KavitaConfigs{},
+	Email:                    &EmailConfigs{},
+	Webhook:                  &WebhookConfigs{},
+	Notifications:            &NotificationsConfigs{},
+	Iframe:                   &IframeConfigs{},
+	ActionLinks:              &ActionLinksConfigs{},
+	OPDS:                     &OPDSConfigs{},
+	DownloadIntegrations:     &DownloadIntegrationsConfigs{},
+	ReadWebhooks:             &ReadWebhooksConfigs{},
+	Tracing:                  &TracingConfigs{},
+	Jobs:                     &JobsConfigs{},
+	ScraperSources:           &ScraperSourcesConfigs{},
 }
 
 // Configs is a struct that holds all the configurations.
@@ -37,6 +50,19 @@ type Configs struct {
 	Kaizoku                  *KaizokuConfigs
 	Tranga                   *TrangaConfigs
 	Suwayomi                 *SuwayomiConfigs
+	Komga                    *KomgaConfigs
+	Kavita                   *KavitaConfigs
+	Email                    *EmailConfigs
+	Webhook                  *WebhookConfigs
+	Notifications            *NotificationsConfigs
+	Iframe                   *IframeConfigs
+	ActionLinks              *ActionLinksConfigs
+	OPDS                     *OPDSConfigs
+	DownloadIntegrations     *DownloadIntegrationsConfigs
+	ReadWebhooks             *ReadWebhooksConfigs
+	Tracing                  *TracingConfigs
+	Jobs                     *JobsConfigs
+	ScraperSources           *ScraperSourcesConfigs
 }
 
 // APIConfigs is a struct that holds the API configurations.
@@ -52,6 +78,105 @@ type NtfyConfigs struct {
 	Token   string
 }
 
+// EmailConfigs is a struct that holds the SMTP configurations to send emails.
+type EmailConfigs struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+	To       []string
+	Valid    bool
+}
+
+// WebhookConfigs is a struct that holds the webhook notifier configurations.
+type WebhookConfigs struct {
+	URL   string
+	Valid bool
+}
+
+// NotificationsConfigs is a struct that holds the notifications configurations.
+type NotificationsConfigs struct {
+	// TemplatesDir is a directory with templates that override the default
+	// notification templates, like digest_ntfy.md.tmpl.
+	TemplatesDir string
+	// NewRelations enables the notifications of new titles related to the
+	// completed mangas, like a sequel, found when updating the mangas metadata.
+	NewRelations bool
+	// SendQueueMinutes is the interval to send the notifications deferred by the
+	// notification rules and the digest when they're due. If it's 0, they're
+	// only sent when the mangas metadata is updated with notify.
+	SendQueueMinutes int
+}
+
+// IframeConfigs is a struct that holds the iframe configurations.
+type IframeConfigs struct {
+	// TemplatesDir is a directory with templates that override the default
+	// iframe templates, like list.html.tmpl.
+	TemplatesDir string
+}
+
+// ActionLinksConfigs is a struct that holds the configurations of the
+// signed action links, like the link to mark a chapter as read.
+type ActionLinksConfigs struct {
+	// APIURL is the API URL used in the links sent in notifications.
+	APIURL string
+	Secret []byte
+	TTL    time.Duration
+	Valid  bool
+}
+
+// OPDSConfigs is a struct that holds the OPDS catalog configurations.
+type OPDSConfigs struct {
+	// ArchivesDir is a directory with the downloaded chapter archives,
+	// like the download directory of Kaizoku, Tranga, or Suwayomi.
+	// If empty, the archives are proxied from Suwayomi, if configured.
+	ArchivesDir string
+}
+
+// ScraperSourcesConfigs is a struct that holds the configurations of the
+// sources scraped with CSS selectors defined in YAML or JSON files.
+type ScraperSourcesConfigs struct {
+	// Dir is a directory with the scraper sources config files.
+	// If empty, no scraper source is registered.
+	Dir string
+}
+
+// DownloadIntegrationsConfigs is a struct that holds the configurations for
+// reconciling the download integrations (Kaizoku, Tranga, and Suwayomi) periodically.
+type DownloadIntegrationsConfigs struct {
+	// ReconcileMinutes is the interval between the reconciliations. 0 disables them.
+	ReconcileMinutes int
+	// ReconcileRemove also removes the mangas that are not in Mantium from the integrations.
+	ReconcileRemove bool
+}
+
+// ReadWebhooksConfigs is a struct that holds the configurations of the inbound
+// webhooks that receive the chapters read in other readers, like Suwayomi.
+type ReadWebhooksConfigs struct {
+	// Tokens maps each integration's token to the integration name.
+	Tokens map[string]string
+	Valid  bool
+}
+
+// JobsConfigs is a struct that holds the configurations of the jobs queue,
+// like the jobs that add the mangas to the download integrations.
+type JobsConfigs struct {
+	// MaxAttempts is the number of times a job is run before it's marked as failed.
+	MaxAttempts int
+	// PollSeconds is the interval the queue is checked for jobs to run.
+	PollSeconds int
+}
+
+// TracingConfigs is a struct that holds the configurations to export
+// the OpenTelemetry traces to an OTLP/HTTP collector.
+type TracingConfigs struct {
+	Endpoint    string
+	Headers     map[string]string
+	ServiceName string
+	Valid       bool
+}
+
 // PeriodicallyUpdateMangasConfigs is a struct that holds the configurations for updating mangas metadata periodically.
 type PeriodicallyUpdateMangasConfigs struct {
 	Update       bool
@@ -66,6 +191,7 @@ type KaizokuConfigs struct {
 	DefaultInterval             string
 	WaitUntilEmptyQueuesTimeout time.Duration
 	TryOtherSources             bool
+	RemovalPolicy               RemovalPolicy
 	Valid                       bool
 }
 
@@ -73,15 +199,40 @@ type KaizokuConfigs struct {
 type TrangaConfigs struct {
 	Address         string
 	DefaultInterval string
+	RemovalPolicy   RemovalPolicy
 	Valid           bool
 }
 
 // SuwayomiConfigs is a struct that holds the configurations for the Suwayomi integration.
 type SuwayomiConfigs struct {
+	Address       string
+	Username      string
+	Password      string
+	RemovalPolicy RemovalPolicy
+	// SearchSources are the Suwayomi sources used to search mangas
+	// with the suwayomi source, like "MangaDex (EN)".
+	SearchSources []string
+	// SourceEnabled enables the suwayomi source, which gets the mangas
+	// from any source installed in Suwayomi.
+	SourceEnabled bool
+	Valid         bool
+}
+
+// KomgaConfigs is a struct that holds the configurations for the Komga integration.
+type KomgaConfigs struct {
 	Address  string
 	Username string
 	Password string
-	Valid    bool
+	// APIKey is used instead of the username and password if set.
+	APIKey string
+	Valid  bool
+}
+
+// KavitaConfigs is a struct that holds the configurations for the Kavita integration.
+type KavitaConfigs struct {
+	Address string
+	APIKey  string
+	Valid   bool
 }
 
 // DashboardConfigs is a struct that holds the configurations for the dashboard.
@@ -92,6 +243,9 @@ type DashboardConfigs struct {
 		ShowBackgroundErrorWarning bool   `json:"showBackgroundErrorWarning"`
 		SearchResultsLimit         int    `json:"searchResultsLimit"`
 		DisplayMode                string `json:"displayMode"`
+		// IframeCSSVariables are the default iframe CSS variables, like {"released-color": "#fa5252"}.
+		// They can be overridden by the iframe URL query.
+		IframeCSSVariables map[string]string `json:"iframeCSSVariables"`
 	} `json:"display"`
 	Integrations struct {
 		AddAllMultiMangaMangasToDownloadIntegrations bool `json:"addAllMultiMangaMangasToDownloadIntegrations"`
@@ -118,6 +272,10 @@ var (
 		"rawkuma",
 		"klmanga",
 		"jmanga",
+		"webtoons",
+		"bato",
+		"asura",
+		"weebcentral",
 	}
 )
 
@@ -178,6 +336,11 @@ func SetConfigs(filePath string) error {
 			}
 		}
 
+		GlobalConfigs.Kaizoku.RemovalPolicy, err = parseRemovalPolicy("KAIZOKU_REMOVAL_POLICY", os.Getenv("KAIZOKU_REMOVAL_POLICY"), []string{RemovalActionRemove, RemovalActionDeleteFiles})
+		if err != nil {
+			return err
+		}
+
 		GlobalConfigs.Kaizoku.Valid = true
 	}
 
@@ -187,15 +350,168 @@ func SetConfigs(filePath string) error {
 		GlobalConfigs.Tranga.DefaultInterval = "03:00:00"
 	}
 	if GlobalConfigs.Tranga.Address != "" {
+		// Tranga only has the mangas' monitor jobs, so unmonitoring and removing are the same
+		GlobalConfigs.Tranga.RemovalPolicy, err = parseRemovalPolicy("TRANGA_REMOVAL_POLICY", os.Getenv("TRANGA_REMOVAL_POLICY"), []string{RemovalActionUnmonitor, RemovalActionRemove})
+		if err != nil {
+			return err
+		}
 		GlobalConfigs.Tranga.Valid = true
 	}
 
 	GlobalConfigs.Suwayomi.Address = os.Getenv("SUWAYOMI_ADDRESS")
 	if GlobalConfigs.Suwayomi.Address != "" {
+		GlobalConfigs.Suwayomi.RemovalPolicy, err = parseRemovalPolicy("SUWAYOMI_REMOVAL_POLICY", os.Getenv("SUWAYOMI_REMOVAL_POLICY"), []string{RemovalActionRemove, RemovalActionDeleteFiles})
+		if err != nil {
+			return err
+		}
 		GlobalConfigs.Suwayomi.Valid = true
 	}
 	GlobalConfigs.Suwayomi.Username = os.Getenv("SUWAYOMI_USERNAME")
 	GlobalConfigs.Suwayomi.Password = os.Getenv("SUWAYOMI_PASSWORD")
+	GlobalConfigs.Suwayomi.SourceEnabled = os.Getenv("SUWAYOMI_SOURCE_ENABLED") == "true"
+	if GlobalConfigs.Suwayomi.SourceEnabled {
+		if !GlobalConfigs.Suwayomi.Valid {
+			return fmt.Errorf("SUWAYOMI_SOURCE_ENABLED requires SUWAYOMI_ADDRESS")
+		}
+		if !slices.Contains(SourcesList, "suwayomi") {
+			SourcesList = append(SourcesList, "suwayomi")
+		}
+	}
+	GlobalConfigs.Suwayomi.SearchSources = nil
+	if envSearchSources := os.Getenv("SUWAYOMI_SOURCE_SEARCH_SOURCES"); envSearchSources != "" {
+		for _, source := range strings.Split(envSearchSources, ",") {
+			GlobalConfigs.Suwayomi.SearchSources = append(GlobalConfigs.Suwayomi.SearchSources, strings.TrimSpace(source))
+		}
+	}
+
+	GlobalConfigs.Komga.Address = strings.TrimSuffix(os.Getenv("KOMGA_ADDRESS"), "/")
+	GlobalConfigs.Komga.Username = os.Getenv("KOMGA_USERNAME")
+	GlobalConfigs.Komga.Password = os.Getenv("KOMGA_PASSWORD")
+	GlobalConfigs.Komga.APIKey = os.Getenv("KOMGA_API_KEY")
+	if GlobalConfigs.Komga.Address != "" {
+		if GlobalConfigs.Komga.APIKey == "" && GlobalConfigs.Komga.Username == "" {
+			return fmt.Errorf("KOMGA_API_KEY or KOMGA_USERNAME and KOMGA_PASSWORD must be set when KOMGA_ADDRESS is set")
+		}
+		GlobalConfigs.Komga.Valid = true
+	}
+
+	GlobalConfigs.Kavita.Address = strings.TrimSuffix(os.Getenv("KAVITA_ADDRESS"), "/")
+	GlobalConfigs.Kavita.APIKey = os.Getenv("KAVITA_API_KEY")
+	if GlobalConfigs.Kavita.Address != "" {
+		if GlobalConfigs.Kavita.APIKey == "" {
+			return fmt.Errorf("KAVITA_API_KEY must be set when KAVITA_ADDRESS is set")
+		}
+		GlobalConfigs.Kavita.Valid = true
+	}
+
+	GlobalConfigs.Email.Host = os.Getenv("SMTP_HOST")
+	GlobalConfigs.Email.Port = os.Getenv("SMTP_PORT")
+	if GlobalConfigs.Email.Port == "" {
+		GlobalConfigs.Email.Port = "587"
+	}
+	GlobalConfigs.Email.Username = os.Getenv("SMTP_USERNAME")
+	GlobalConfigs.Email.Password = os.Getenv("SMTP_PASSWORD")
+	GlobalConfigs.Email.From = os.Getenv("SMTP_FROM")
+	if envTo := os.Getenv("SMTP_TO"); envTo != "" {
+		GlobalConfigs.Email.To = strings.Split(envTo, ",")
+	}
+	if GlobalConfigs.Email.Host != "" {
+		if GlobalConfigs.Email.From == "" || len(GlobalConfigs.Email.To) == 0 {
+			return fmt.Errorf("SMTP_FROM and SMTP_TO must be set when SMTP_HOST is set")
+		}
+		GlobalConfigs.Email.Valid = true
+	}
+
+	GlobalConfigs.Webhook.URL = os.Getenv("WEBHOOK_URL")
+	if GlobalConfigs.Webhook.URL != "" {
+		GlobalConfigs.Webhook.Valid = true
+	}
+
+	GlobalConfigs.Notifications.TemplatesDir = os.Getenv("NOTIFICATIONS_TEMPLATES_DIR")
+	GlobalConfigs.Notifications.NewRelations = os.Getenv("NOTIFICATIONS_NEW_RELATIONS") == "true"
+	GlobalConfigs.Notifications.SendQueueMinutes = 5
+	if envSendQueueMinutes := os.Getenv("NOTIFICATIONS_SEND_QUEUE_MINUTES"); envSendQueueMinutes != "" {
+		GlobalConfigs.Notifications.SendQueueMinutes, err = strconv.Atoi(envSendQueueMinutes)
+		if err != nil || GlobalConfigs.Notifications.SendQueueMinutes < 0 {
+			return fmt.Errorf("NOTIFICATIONS_SEND_QUEUE_MINUTES '%s' must be a number greater than or equal to 0", envSendQueueMinutes)
+		}
+	}
+	GlobalConfigs.Iframe.TemplatesDir = os.Getenv("IFRAME_TEMPLATES_DIR")
+
+	GlobalConfigs.ActionLinks.Secret = []byte(os.Getenv("ACTION_LINKS_SECRET"))
+	GlobalConfigs.ActionLinks.APIURL = strings.TrimSuffix(os.Getenv("ACTION_LINKS_API_URL"), "/")
+	GlobalConfigs.ActionLinks.TTL = 24 * time.Hour
+	if envTTL := os.Getenv("ACTION_LINKS_TTL_HOURS"); envTTL != "" {
+		hours, err := strconv.Atoi(envTTL)
+		if err != nil || hours < 1 {
+			return fmt.Errorf("ACTION_LINKS_TTL_HOURS must be a number greater than 0")
+		}
+		GlobalConfigs.ActionLinks.TTL = time.Duration(hours) * time.Hour
+	}
+	if len(GlobalConfigs.ActionLinks.Secret) > 0 {
+		if len(GlobalConfigs.ActionLinks.Secret) < 16 {
+			return fmt.Errorf("ACTION_LINKS_SECRET must have at least 16 characters")
+		}
+		GlobalConfigs.ActionLinks.Valid = true
+	}
+
+	GlobalConfigs.OPDS.ArchivesDir = os.Getenv("OPDS_ARCHIVES_DIR")
+	if GlobalConfigs.OPDS.ArchivesDir != "" && !util.FileExists(GlobalConfigs.OPDS.ArchivesDir) {
+		return fmt.Errorf("OPDS_ARCHIVES_DIR '%s' not found", GlobalConfigs.OPDS.ArchivesDir)
+	}
+
+	GlobalConfigs.ScraperSources.Dir = os.Getenv("SCRAPER_SOURCES_DIR")
+	if GlobalConfigs.ScraperSources.Dir != "" && !util.FileExists(GlobalConfigs.ScraperSources.Dir) {
+		return fmt.Errorf("SCRAPER_SOURCES_DIR '%s' not found", GlobalConfigs.ScraperSources.Dir)
+	}
+
+	if envReconcileMinutes := os.Getenv("DOWNLOAD_INTEGRATIONS_RECONCILE_MINUTES"); envReconcileMinutes != "" {
+		GlobalConfigs.DownloadIntegrations.ReconcileMinutes, err = strconv.Atoi(envReconcileMinutes)
+		if err != nil {
+			return fmt.Errorf("error converting DOWNLOAD_INTEGRATIONS_RECONCILE_MINUTES '%s' to int: %s", envReconcileMinutes, err)
+		}
+	}
+	if os.Getenv("DOWNLOAD_INTEGRATIONS_RECONCILE_REMOVE") == "true" {
+		GlobalConfigs.DownloadIntegrations.ReconcileRemove = true
+	}
+
+	GlobalConfigs.ReadWebhooks.Tokens, err = parseReadWebhookTokens(os.Getenv("READ_WEBHOOK_TOKENS"))
+	if err != nil {
+		return err
+	}
+	GlobalConfigs.ReadWebhooks.Valid = len(GlobalConfigs.ReadWebhooks.Tokens) > 0
+
+	GlobalConfigs.Jobs.MaxAttempts = 5
+	if envMaxAttempts := os.Getenv("JOBS_MAX_ATTEMPTS"); envMaxAttempts != "" {
+		GlobalConfigs.Jobs.MaxAttempts, err = strconv.Atoi(envMaxAttempts)
+		if err != nil {
+			return fmt.Errorf("error converting JOBS_MAX_ATTEMPTS '%s' to int: %s", envMaxAttempts, err)
+		}
+		if GlobalConfigs.Jobs.MaxAttempts < 1 {
+			return fmt.Errorf("JOBS_MAX_ATTEMPTS must be at least 1")
+		}
+	}
+	GlobalConfigs.Jobs.PollSeconds = 30
+	if envPollSeconds := os.Getenv("JOBS_POLL_INTERVAL_SECONDS"); envPollSeconds != "" {
+		GlobalConfigs.Jobs.PollSeconds, err = strconv.Atoi(envPollSeconds)
+		if err != nil {
+			return fmt.Errorf("error converting JOBS_POLL_INTERVAL_SECONDS '%s' to int: %s", envPollSeconds, err)
+		}
+		if GlobalConfigs.Jobs.PollSeconds < 1 {
+			return fmt.Errorf("JOBS_POLL_INTERVAL_SECONDS must be at least 1")
+		}
+	}
+
+	GlobalConfigs.Tracing.Endpoint = os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+	GlobalConfigs.Tracing.Headers, err = parseOTLPHeaders(os.Getenv("OTEL_EXPORTER_OTLP_HEADERS"))
+	if err != nil {
+		return err
+	}
+	GlobalConfigs.Tracing.ServiceName = os.Getenv("OTEL_SERVICE_NAME")
+	if GlobalConfigs.Tracing.ServiceName == "" {
+		GlobalConfigs.Tracing.ServiceName = "mantium-api"
+	}
+	GlobalConfigs.Tracing.Valid = GlobalConfigs.Tracing.Endpoint != ""
 
 	if os.Getenv("UPDATE_MANGAS_PERIODICALLY") == "true" {
 		GlobalConfigs.PeriodicallyUpdateMangas.Update = true
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"encoding/json"
 	"sync"
 
 	"github.com/diogovalentte/mantium/api/src/db"
@@ -16,10 +17,12 @@ func LoadConfigsFromDB(configs *DashboardConfigs) error {
 	}
 	defer db.Close()
 
+	var iframeCSSVariables []byte
 	err = db.QueryRow(`
 		SELECT
 			columns, show_background_error_warning, search_results_limit, display_mode,
-			add_all_multimanga_mangas_to_download_integrations, enqueue_all_suwayomi_chapters_to_download
+			add_all_multimanga_mangas_to_download_integrations, enqueue_all_suwayomi_chapters_to_download,
+			iframe_css_variables
 		FROM
 			configs;
 	`).Scan(
@@ -27,11 +30,18 @@ func LoadConfigsFromDB(configs *DashboardConfigs) error {
 		&configs.Display.SearchResultsLimit, &configs.Display.DisplayMode,
 		&configs.Integrations.AddAllMultiMangaMangasToDownloadIntegrations,
 		&configs.Integrations.EnqueueAllSuwayomiChaptersToDownload,
+		&iframeCSSVariables,
 	)
 	if err != nil {
 		return util.AddErrorContext(contextError, err)
 	}
 
+	configs.Display.IframeCSSVariables = map[string]string{}
+	err = json.Unmarshal(iframeCSSVariables, &configs.Display.IframeCSSVariables)
+	if err != nil {
+		return util.AddErrorContext(contextError, err)
+	}
+
 	return nil
 }
 
@@ -87,17 +97,28 @@ func SaveConfigsToDB(configs *DashboardConfigs) error {
 		return util.AddErrorContext(contextError, err)
 	}
 
+	if configs.Display.IframeCSSVariables == nil {
+		configs.Display.IframeCSSVariables = map[string]string{}
+	}
+	iframeCSSVariables, err := json.Marshal(configs.Display.IframeCSSVariables)
+	if err != nil {
+		tx.Rollback()
+		return util.AddErrorContext(contextError, err)
+	}
+
 	_, err = tx.Exec(`
 		UPDATE
 			configs
 		SET
 			columns = $1, show_background_error_warning = $2, search_results_limit = $3, display_mode = $4,
-			add_all_multimanga_mangas_to_download_integrations = $5, enqueue_all_suwayomi_chapters_to_download = $6
+			add_all_multimanga_mangas_to_download_integrations = $5, enqueue_all_suwayomi_chapters_to_download = $6,
+			iframe_css_variables = $7
 		;
 	`, configs.Display.Columns, configs.Display.ShowBackgroundErrorWarning,
 		configs.Display.SearchResultsLimit, configs.Display.DisplayMode,
 		configs.Integrations.AddAllMultiMangaMangasToDownloadIntegrations,
 		configs.Integrations.EnqueueAllSuwayomiChaptersToDownload,
+		iframeCSSVariables,
 	)
 	if err != nil {
 		tx.Rollback()
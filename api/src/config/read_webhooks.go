@@ -0,0 +1,40 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// parseReadWebhookTokens parses the read webhook tokens like "suwayomi:token1,komga:token2".
+// It returns a map of each token to its integration name.
+// The integration names have at most 50 characters, the size of the column that stores them.
+func parseReadWebhookTokens(value string) (map[string]string, error) {
+	tokens := map[string]string{}
+	if value == "" {
+		return tokens, nil
+	}
+
+	integrations := map[string]bool{}
+	for _, item := range strings.Split(value, ",") {
+		integration, token, ok := strings.Cut(strings.TrimSpace(item), ":")
+		if !ok || integration == "" || token == "" {
+			return nil, fmt.Errorf("error parsing READ_WEBHOOK_TOKENS: item '%s' must be like 'integration:token'", item)
+		}
+		if len(integration) > 50 {
+			return nil, fmt.Errorf("error parsing READ_WEBHOOK_TOKENS: integration '%s' must have at most 50 characters", integration)
+		}
+		if len(token) < 16 {
+			return nil, fmt.Errorf("error parsing READ_WEBHOOK_TOKENS: the token of integration '%s' must have at least 16 characters", integration)
+		}
+		if integrations[integration] {
+			return nil, fmt.Errorf("error parsing READ_WEBHOOK_TOKENS: integration '%s' has more than one token", integration)
+		}
+		if _, ok := tokens[token]; ok {
+			return nil, fmt.Errorf("error parsing READ_WEBHOOK_TOKENS: the token of integration '%s' is used by another integration", integration)
+		}
+		integrations[integration] = true
+		tokens[token] = integration
+	}
+
+	return tokens, nil
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Events in Mantium that trigger a removal policy.
+const (
+	// RemovalEventDelete is when a multimanga is deleted or a manga is removed from a multimanga.
+	RemovalEventDelete = "delete"
+	// RemovalEventDrop is when a multimanga status is set to dropped.
+	RemovalEventDrop = "drop"
+	// RemovalEventComplete is when a multimanga status is set to completed.
+	RemovalEventComplete = "complete"
+)
+
+// Actions done in a download integration by a removal policy.
+const (
+	// RemovalActionUnmonitor stops the integration from checking for new chapters of the manga.
+	RemovalActionUnmonitor = "unmonitor"
+	// RemovalActionRemove removes the manga from the integration's library, keeping the downloaded chapters.
+	RemovalActionRemove = "remove"
+	// RemovalActionDeleteFiles removes the manga from the integration's library and deletes the downloaded chapters.
+	RemovalActionDeleteFiles = "delete_files"
+)
+
+// RemovalPolicy maps the Mantium events to the action done in a download integration.
+// Events without an action don't change the integration.
+type RemovalPolicy map[string]string
+
+// parseRemovalPolicy parses a policy like "delete:delete_files,drop:remove".
+// The actions must be in supportedActions.
+func parseRemovalPolicy(envName, value string, supportedActions []string) (RemovalPolicy, error) {
+	policy := RemovalPolicy{}
+	if value == "" {
+		return policy, nil
+	}
+
+	events := []string{RemovalEventDelete, RemovalEventDrop, RemovalEventComplete}
+	for _, rule := range strings.Split(value, ",") {
+		event, action, ok := strings.Cut(strings.TrimSpace(rule), ":")
+		if !ok {
+			return nil, fmt.Errorf("error parsing %s '%s': rule '%s' must be like 'event:action'", envName, value, rule)
+		}
+		if !slices.Contains(events, event) {
+			return nil, fmt.Errorf("error parsing %s '%s': event '%s' must be one of %s", envName, value, event, strings.Join(events, ", "))
+		}
+		if !slices.Contains(supportedActions, action) {
+			return nil, fmt.Errorf("error parsing %s '%s': action '%s' must be one of %s", envName, value, action, strings.Join(supportedActions, ", "))
+		}
+		policy[event] = action
+	}
+
+	return policy, nil
+}
@@ -0,0 +1,30 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// parseOTLPHeaders parses the OTLP exporter headers like "Authorization=Bearer%20token,X-Org=mantium".
+// The values are URL-decoded, as in the OpenTelemetry specification.
+func parseOTLPHeaders(value string) (map[string]string, error) {
+	headers := map[string]string{}
+	if value == "" {
+		return headers, nil
+	}
+
+	for _, item := range strings.Split(value, ",") {
+		key, headerValue, ok := strings.Cut(strings.TrimSpace(item), "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("error parsing OTEL_EXPORTER_OTLP_HEADERS: item '%s' must be like 'key=value'", item)
+		}
+		decodedValue, err := url.PathUnescape(strings.TrimSpace(headerValue))
+		if err != nil {
+			return nil, fmt.Errorf("error parsing OTEL_EXPORTER_OTLP_HEADERS: value of header '%s' is not URL-encoded: %s", key, err)
+		}
+		headers[strings.TrimSpace(key)] = decodedValue
+	}
+
+	return headers, nil
+}
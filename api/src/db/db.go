@@ -6,7 +6,7 @@ import (
 	"fmt"
 	"os"
 
-	_ "github.com/lib/pq" // postgres driver
+	"github.com/lib/pq"
 	"github.com/rs/zerolog"
 
 	"github.com/diogovalentte/mantium/api/src/util"
@@ -34,10 +34,11 @@ func getConnString() string {
 
 // OpenConn opens a connection to the database
 func OpenConn() (*sql.DB, error) {
-	db, err := sql.Open("postgres", getConnString())
+	connector, err := pq.NewConnector(getConnString())
 	if err != nil {
 		return nil, util.AddErrorContext("error opening database connection", err)
 	}
+	db := sql.OpenDB(&instrumentedConnector{connector: connector})
 
 	err = db.Ping()
 	if err != nil {
@@ -110,6 +111,168 @@ func CreateTables(db *sql.DB, log *zerolog.Logger) error {
 			"enqueue_all_suwayomi_chapters_to_download" boolean NOT NULL DEFAULT TRUE
 		);
 
+		CREATE TABLE IF NOT EXISTS "multimanga_chapters" (
+			"multimanga_id" integer NOT NULL REFERENCES multimangas(id) ON DELETE CASCADE,
+			"chapter" varchar(255) NOT NULL,
+			"name" varchar(255) NOT NULL DEFAULT '',
+			"url" text NOT NULL DEFAULT '',
+			"internal_id" VARCHAR(100) NOT NULL DEFAULT '',
+			"released_at" timestamp,
+			"read" boolean NOT NULL DEFAULT FALSE,
+			"read_at" timestamp,
+			"read_count" integer NOT NULL DEFAULT 0,
+			PRIMARY KEY ("multimanga_id", "chapter")
+		);
+
+		CREATE TABLE IF NOT EXISTS "reading_activity" (
+			"id" serial PRIMARY KEY,
+			"multimanga_id" integer REFERENCES multimangas(id) ON DELETE SET NULL,
+			"manga_id" integer REFERENCES mangas(id) ON DELETE SET NULL,
+			"manga_name" varchar(255) NOT NULL,
+			"source" varchar(30) NOT NULL,
+			"genres" text[] NOT NULL DEFAULT '{}',
+			"chapter" varchar(255) NOT NULL,
+			"chapter_url" text NOT NULL DEFAULT '',
+			"previous_chapter" varchar(255) NOT NULL DEFAULT '',
+			"chapters_read" integer NOT NULL DEFAULT 1,
+			"chapter_released_at" timestamp,
+			"read_at" timestamp NOT NULL
+		);
+
+		CREATE INDEX IF NOT EXISTS "reading_activity_read_at_idx" ON "reading_activity" ("read_at");
+
+		CREATE TABLE IF NOT EXISTS "backlog_snapshots" (
+			"day" date PRIMARY KEY,
+			"unread" integer NOT NULL,
+			"total" integer NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS "notification_rules" (
+			"id" serial PRIMARY KEY,
+			"multimanga_id" integer UNIQUE REFERENCES multimangas(id) ON DELETE CASCADE,
+			"mute" boolean,
+			"numbered_chapters_only" boolean,
+			"digest" boolean,
+			"min_gap_minutes" integer,
+			"quiet_hours_start" varchar(5),
+			"quiet_hours_end" varchar(5),
+			"digest_time" varchar(5),
+			"last_notified_at" timestamp
+		);
+
+		CREATE UNIQUE INDEX IF NOT EXISTS "notification_rules_global_idx" ON "notification_rules" ((multimanga_id IS NULL)) WHERE multimanga_id IS NULL;
+
+		INSERT INTO notification_rules (mute, numbered_chapters_only, digest, min_gap_minutes, digest_time)
+		SELECT FALSE, FALSE, FALSE, 0, '09:00'
+		WHERE NOT EXISTS (SELECT 1 FROM notification_rules WHERE multimanga_id IS NULL);
+
+		CREATE TABLE IF NOT EXISTS "notification_queue" (
+			"id" serial PRIMARY KEY,
+			"multimanga_id" integer REFERENCES multimangas(id) ON DELETE CASCADE,
+			"manga_id" integer REFERENCES mangas(id) ON DELETE CASCADE,
+			"manga_name" varchar(255) NOT NULL,
+			"manga_url" text NOT NULL,
+			"chapter" varchar(255) NOT NULL,
+			"chapter_name" varchar(255) NOT NULL DEFAULT '',
+			"chapter_url" text NOT NULL,
+			"reason" varchar(20) NOT NULL,
+			"created_at" timestamp NOT NULL,
+			"deliver_after" timestamp NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS "digest_events" (
+			"id" serial PRIMARY KEY,
+			"multimanga_id" integer REFERENCES multimangas(id) ON DELETE CASCADE,
+			"manga_id" integer REFERENCES mangas(id) ON DELETE SET NULL,
+			"manga_name" varchar(255) NOT NULL,
+			"manga_url" text NOT NULL,
+			"previous_chapter" varchar(255),
+			"chapter" varchar(255) NOT NULL,
+			"chapter_name" varchar(255) NOT NULL DEFAULT '',
+			"chapter_url" text NOT NULL,
+			"released_at" timestamp,
+			"created_at" timestamp NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS "digest_notifiers" (
+			"notifier" varchar(20) PRIMARY KEY,
+			"last_event_id" integer NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS "read_webhook_events" (
+			"integration" varchar(50) NOT NULL,
+			"idempotency_key" varchar(255) NOT NULL,
+			"received_at" timestamp NOT NULL,
+			PRIMARY KEY ("integration", "idempotency_key")
+		);
+
+		CREATE TABLE IF NOT EXISTS "jobs" (
+			"id" serial PRIMARY KEY,
+			"type" varchar(50) NOT NULL,
+			"integration" varchar(50) NOT NULL DEFAULT '',
+			"manga_id" integer NOT NULL REFERENCES mangas(id) ON DELETE CASCADE,
+			"status" varchar(20) NOT NULL,
+			"attempts" integer NOT NULL DEFAULT 0,
+			"max_attempts" integer NOT NULL,
+			"last_error" text NOT NULL DEFAULT '',
+			"run_at" timestamp NOT NULL,
+			"created_at" timestamp NOT NULL,
+			"updated_at" timestamp NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS "jobs_status_run_at_idx" ON "jobs" ("status", "run_at");
+
+		CREATE TABLE IF NOT EXISTS "custom_manga_chapters" (
+			"manga_id" integer NOT NULL REFERENCES mangas(id) ON DELETE CASCADE,
+			"chapter" varchar(255) NOT NULL,
+			"name" varchar(255) NOT NULL,
+			"url" text NOT NULL DEFAULT '',
+			"released_at" timestamp NOT NULL,
+			PRIMARY KEY ("manga_id", "chapter")
+		);
+
+		CREATE TABLE IF NOT EXISTS "custom_manga_watchers" (
+			"manga_id" integer PRIMARY KEY REFERENCES mangas(id) ON DELETE CASCADE,
+			"url" text NOT NULL,
+			"selector" text NOT NULL DEFAULT '',
+			"regex" text NOT NULL DEFAULT '',
+			"last_chapter" varchar(255) NOT NULL DEFAULT '',
+			"last_error" text NOT NULL DEFAULT '',
+			"last_checked_at" timestamp
+		);
+
+		CREATE TABLE IF NOT EXISTS "chapter_preferences" (
+			"manga_id" integer PRIMARY KEY REFERENCES mangas(id) ON DELETE CASCADE,
+			"languages" text[] NOT NULL DEFAULT '{}',
+			"groups" text[] NOT NULL DEFAULT '{}',
+			"blocked_groups" text[] NOT NULL DEFAULT '{}',
+			"wait_hours" integer NOT NULL DEFAULT 0
+		);
+
+		CREATE TABLE IF NOT EXISTS "manga_relations" (
+			"manga_id" integer NOT NULL REFERENCES mangas(id) ON DELETE CASCADE,
+			"url" text NOT NULL,
+			"internal_id" varchar(100) NOT NULL DEFAULT '',
+			"type" varchar(50) NOT NULL,
+			"name" text NOT NULL DEFAULT '',
+			"source" varchar(100) NOT NULL,
+			"created_at" timestamp NOT NULL,
+			PRIMARY KEY ("manga_id", "url")
+		);
+
+		CREATE TABLE IF NOT EXISTS "manga_relations_checks" (
+			"manga_id" integer PRIMARY KEY REFERENCES mangas(id) ON DELETE CASCADE,
+			"checked_at" timestamp NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS "multimanga_external_ids" (
+			"multimanga_id" integer PRIMARY KEY REFERENCES multimangas(id) ON DELETE CASCADE,
+			"mangaupdates_id" varchar(50) NOT NULL DEFAULT '',
+			"anilist_id" varchar(50) NOT NULL DEFAULT '',
+			"mal_id" varchar(50) NOT NULL DEFAULT '',
+			"resolved_at" timestamp,
+			"manual_ids" text[] NOT NULL DEFAULT '{}'
+		);
+
 		CREATE TABLE IF NOT EXISTS "version" (
 			"version" VARCHAR(15) NOT NULL DEFAULT '4.0.4'
 		);
@@ -206,6 +369,17 @@ func CreateTables(db *sql.DB, log *zerolog.Logger) error {
         ALTER TABLE "chapters" ALTER COLUMN "manga_id" DROP NOT NULL;
         ALTER TABLE "chapters" ALTER COLUMN "url" TYPE text;
         ALTER TABLE "multimangas" ALTER COLUMN "cover_img_url" TYPE text;
+        ALTER TABLE "multimangas" ADD COLUMN IF NOT EXISTS "reread_count" integer NOT NULL DEFAULT 0;
+        ALTER TABLE "multimangas" ADD COLUMN IF NOT EXISTS "tags" text[] NOT NULL DEFAULT '{}';
+        ALTER TABLE "mangas" ADD COLUMN IF NOT EXISTS "tags" text[] NOT NULL DEFAULT '{}';
+        ALTER TABLE "configs" ADD COLUMN IF NOT EXISTS "iframe_css_variables" jsonb NOT NULL DEFAULT '{}';
+        ALTER TABLE "notification_rules" ADD COLUMN IF NOT EXISTS "digest_frequency" varchar(10);
+        ALTER TABLE "notification_rules" ADD COLUMN IF NOT EXISTS "digest_weekday" integer;
+        ALTER TABLE "notification_rules" ADD COLUMN IF NOT EXISTS "digest_last_sent_at" timestamp;
+        UPDATE "notification_rules" SET "digest_frequency" = 'daily', "digest_weekday" = 1 WHERE "multimanga_id" IS NULL AND "digest_frequency" IS NULL;
+        INSERT INTO "chapter_preferences" ("manga_id", "groups")
+        SELECT "id", ARRAY["preferred_group"] FROM "mangas" WHERE "preferred_group" <> ''
+        ON CONFLICT DO NOTHING;
 
         do $$
        	begin
@@ -0,0 +1,121 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/diogovalentte/mantium/api/src/telemetry"
+)
+
+// instrumentedConnector wraps the postgres connector to record
+// the queries duration metrics and spans.
+type instrumentedConnector struct {
+	connector *pq.Connector
+}
+
+func (c *instrumentedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &instrumentedConn{conn: conn}, nil
+}
+
+func (c *instrumentedConnector) Driver() driver.Driver {
+	return c.connector.Driver()
+}
+
+// postgresConn is the interface implemented by the postgres driver connections.
+type postgresConn interface {
+	driver.Conn
+	driver.ConnBeginTx
+	driver.ConnPrepareContext
+	driver.ExecerContext
+	driver.QueryerContext
+	driver.Pinger
+	driver.SessionResetter
+	driver.Validator
+}
+
+type instrumentedConn struct {
+	conn driver.Conn
+}
+
+func (c *instrumentedConn) pqConn() postgresConn {
+	return c.conn.(postgresConn)
+}
+
+func (c *instrumentedConn) Prepare(query string) (driver.Stmt, error) {
+	return c.conn.Prepare(query)
+}
+
+func (c *instrumentedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	return c.pqConn().PrepareContext(ctx, query)
+}
+
+func (c *instrumentedConn) Close() error {
+	return c.conn.Close()
+}
+
+func (c *instrumentedConn) Begin() (driver.Tx, error) {
+	return c.conn.Begin() //nolint:staticcheck // Needed to implement driver.Conn
+}
+
+func (c *instrumentedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	return c.pqConn().BeginTx(ctx, opts)
+}
+
+func (c *instrumentedConn) Ping(ctx context.Context) error {
+	return c.pqConn().Ping(ctx)
+}
+
+func (c *instrumentedConn) ResetSession(ctx context.Context) error {
+	return c.pqConn().ResetSession(ctx)
+}
+
+func (c *instrumentedConn) IsValid() bool {
+	return c.pqConn().IsValid()
+}
+
+func (c *instrumentedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	ctx, end := observeQuery(ctx, "query", query)
+	rows, err := c.pqConn().QueryContext(ctx, query, args)
+	end(err)
+
+	return rows, err
+}
+
+func (c *instrumentedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	ctx, end := observeQuery(ctx, "exec", query)
+	result, err := c.pqConn().ExecContext(ctx, query, args)
+	end(err)
+
+	return result, err
+}
+
+// observeQuery starts timing a query. It only starts a span if ctx
+// already has one, so queries outside a traced request don't create root spans.
+// The returned function must be called with the query error when it finishes.
+func observeQuery(ctx context.Context, operation, query string) (context.Context, func(error)) {
+	start := time.Now()
+	var span trace.Span
+	if trace.SpanContextFromContext(ctx).IsValid() {
+		ctx, span = telemetry.StartSpan(ctx, "db."+operation, "db.system", "postgresql", "db.query.text", query)
+	}
+
+	return ctx, func(err error) {
+		if errors.Is(err, driver.ErrSkip) {
+			err = nil
+		}
+		telemetry.ObserveDBQuery(operation, time.Since(start), err)
+		if span != nil {
+			telemetry.EndSpan(span, err)
+		}
+	}
+}
@@ -9,6 +9,7 @@ var (
 	ErrChapterNotFound             = &CustomError{Message: "chapter not found in source"}
 	ErrChapterURLNotFound          = &CustomError{Message: "chapter URL not found"}
 	ErrMangaURLNotFound            = &CustomError{Message: "manga URL not found"}
+	ErrSourceHasNoGenres           = &CustomError{Message: "the manga source doesn't list the mangas genres"}
 
 	ErrMangaNotFoundDB                      = &CustomError{Message: "manga not found in DB"}
 	ErrMultiMangaNotFoundDB                 = &CustomError{Message: "multimanga not found in DB"}
@@ -17,6 +18,39 @@ var (
 	ErrChapterNotFoundDB                    = &CustomError{Message: "chapter not found in DB"}
 	ErrAttemptedToRemoveLastMultiMangaManga = &CustomError{Message: "attempted to remove the last manga from a multimanga"}
 	ErrMultiMangaMangaListIsEmpty           = &CustomError{Message: "multimanga manga list is empty"}
+
+	ErrActionLinksDisabled = &CustomError{Message: "action links are disabled, set the ACTION_LINKS_SECRET environment variable to enable them"}
+	ErrActionLinkInvalid   = &CustomError{Message: "invalid action link"}
+	ErrActionLinkExpired   = &CustomError{Message: "action link expired"}
+
+	ErrNoDigestNotifiers = &CustomError{Message: "no digest notifier is configured, set NTFY_ADDRESS, SMTP_HOST, or WEBHOOK_URL to send the digest"}
+
+	ErrSeriesNotFound = &CustomError{Message: "series not found in the reading integration"}
+
+	ErrRemovalActionNotSupported = &CustomError{Message: "removal action not supported by the download integration"}
+	ErrMangaNotInLibrary         = &CustomError{Message: "manga not found in the download integration library"}
+
+	ErrReadWebhooksDisabled    = &CustomError{Message: "read webhooks are disabled, set the READ_WEBHOOK_TOKENS environment variable to enable them"}
+	ErrReadWebhookUnauthorized = &CustomError{Message: "invalid or missing read webhook token"}
+
+	ErrOPDSArchivesDisabled = &CustomError{Message: "chapter archives are disabled, set the OPDS_ARCHIVES_DIR environment variable or configure the Suwayomi integration to enable them"}
+	ErrOPDSArchiveNotFound  = &CustomError{Message: "chapter archive not found"}
+
+	ErrJobNotFound     = &CustomError{Message: "job not found in DB"}
+	ErrJobNotRetryable = &CustomError{Message: "only failed jobs can be retried"}
+
+	ErrCustomChapterNotFoundDB      = &CustomError{Message: "custom manga chapter not found in DB"}
+	ErrCustomMangaWatcherNotFoundDB = &CustomError{Message: "custom manga watcher not found in DB"}
+	ErrCustomMangaWatcherNoChapters = &CustomError{Message: "no chapters found in the watched page"}
+
+	ErrRelationNotFoundDB   = &CustomError{Message: "manga relation not found in DB"}
+	ErrSourceHasNoRelations = &CustomError{Message: "the manga source doesn't list related titles"}
+
+	ErrSourceHasNoSimilarTitles = &CustomError{Message: "the manga source doesn't list similar titles"}
+	ErrNoRecommendationSeeds    = &CustomError{Message: "no manga in the library can be used to find recommendations, add mangas from MangaDex, MangaUpdates, or ComicK with the status reading, completed, on hold, or plan to read"}
+
+	ErrSourceHasNoExternalIDs = &CustomError{Message: "the manga source doesn't know the mangas external IDs"}
+	ErrNoExternalIDsSources   = &CustomError{Message: "no manga of the multimanga is from a source that knows its external IDs, like MangaDex, MangaUpdates, or ComicK"}
 )
 
 // CustomError is a custom error
@@ -0,0 +1,354 @@
+// Package feed implements the RSS, Atom, and JSON feeds of new chapter releases
+// and the summary used by dashboard widgets.
+package feed
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+const (
+	// RSSContentType is the content type of the RSS feed.
+	RSSContentType = "application/rss+xml; charset=utf-8"
+	// AtomContentType is the content type of the Atom feed.
+	AtomContentType = "application/atom+xml; charset=utf-8"
+	// JSONContentType is the content type of the JSON feed.
+	JSONContentType = "application/feed+json; charset=utf-8"
+)
+
+// Feed is a feed of new chapter releases.
+type Feed struct {
+	Updated     time.Time
+	Title       string
+	Description string
+	// Link is the URL of the website of the feed.
+	Link string
+	// FeedURL is the URL of the feed itself.
+	FeedURL string
+	Items   []*Item
+}
+
+// Item is a chapter release in the feed.
+type Item struct {
+	Published   time.Time
+	ID          string
+	Title       string
+	Link        string
+	Summary     string
+	MangaName   string
+	MangaURL    string
+	Chapter     string
+	ChapterName string
+	ImageURL    string
+	Source      string
+	Tags        []string
+}
+
+// New returns a feed with the last released chapter of each manga.
+// Mangas without a last released chapter are ignored. The items are
+// in the same order as the mangas.
+func New(title, link, feedURL string, mangas []*manga.Manga) *Feed {
+	feed := &Feed{
+		Title:       title,
+		Description: "New chapters released for the mangas tracked by Mantium",
+		Link:        link,
+		FeedURL:     feedURL,
+		Items:       []*Item{},
+	}
+
+	for _, m := range mangas {
+		item := newItem(m)
+		if item == nil {
+			continue
+		}
+		if item.Published.After(feed.Updated) {
+			feed.Updated = item.Published
+		}
+		feed.Items = append(feed.Items, item)
+	}
+	if feed.Updated.IsZero() {
+		feed.Updated = time.Now()
+	}
+
+	return feed
+}
+
+// newItem returns the item of the manga's last released chapter,
+// or nil if the manga doesn't have a last released chapter.
+func newItem(m *manga.Manga) *Item {
+	if m.LastReleasedChapter == nil {
+		return nil
+	}
+	chapter := m.LastReleasedChapter
+
+	item := &Item{
+		Published:   chapter.UpdatedAt,
+		MangaName:   m.Name,
+		Chapter:     chapter.Chapter,
+		ChapterName: chapter.Name,
+		ImageURL:    m.CoverImgURL,
+		Source:      m.Source,
+		Tags:        m.Tags,
+	}
+	if !isCustomMangaURL(m.URL) {
+		item.MangaURL = m.URL
+	}
+	if !isCustomMangaURL(chapter.URL) {
+		item.Link = chapter.URL
+	} else {
+		item.Link = item.MangaURL
+	}
+	// The multimanga ID is used because its current manga can change
+	if m.MultiMangaID != 0 {
+		item.ID = fmt.Sprintf("urn:mantium:multimanga:%d:chapter:%s", m.MultiMangaID, url.PathEscape(chapter.Chapter))
+	} else {
+		item.ID = fmt.Sprintf("urn:mantium:manga:%d:chapter:%s", m.ID, url.PathEscape(chapter.Chapter))
+	}
+
+	item.Title = fmt.Sprintf("%s - Chapter %s", m.Name, chapter.Chapter)
+	if chapter.Name != "" && chapter.Name != chapter.Chapter {
+		item.Title = fmt.Sprintf("%s: %s", item.Title, chapter.Name)
+	}
+	item.Summary = fmt.Sprintf("Chapter %s of %s was released.", chapter.Chapter, m.Name)
+	if m.UnreadChapters > 0 {
+		item.Summary = fmt.Sprintf("%s %d unread chapter(s).", item.Summary, m.UnreadChapters)
+	}
+
+	return item
+}
+
+func isCustomMangaURL(u string) bool {
+	return u == "" || strings.HasPrefix(u, manga.CustomMangaURLPrefix)
+}
+
+type rss struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	AtomLink      rssLink   `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type rssItem struct {
+	Enclosure   *rssEnclosure `xml:"enclosure,omitempty"`
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link,omitempty"`
+	Description string        `xml:"description"`
+	GUID        rssGUID       `xml:"guid"`
+	PubDate     string        `xml:"pubDate"`
+	Categories  []string      `xml:"category"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Type   string `xml:"type,attr"`
+	Length int    `xml:"length,attr"`
+}
+
+// RSS returns the feed as an RSS 2.0 document.
+func (f *Feed) RSS() ([]byte, error) {
+	doc := rss{
+		Version: "2.0",
+		Atom:    "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:         f.Title,
+			Link:          f.Link,
+			Description:   f.Description,
+			LastBuildDate: f.Updated.Format(time.RFC1123Z),
+			AtomLink:      rssLink{Href: f.FeedURL, Rel: "self", Type: "application/rss+xml"},
+			Items:         []rssItem{},
+		},
+	}
+	for _, item := range f.Items {
+		rssItem := rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			Description: item.Summary,
+			GUID:        rssGUID{Value: item.ID},
+			PubDate:     item.Published.Format(time.RFC1123Z),
+			Categories:  item.Tags,
+		}
+		if item.ImageURL != "" {
+			rssItem.Enclosure = &rssEnclosure{URL: item.ImageURL, Type: "image/jpeg"}
+		}
+		doc.Channel.Items = append(doc.Channel.Items, rssItem)
+	}
+
+	return marshalXML(doc, "RSS")
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Author  atomAuthor  `xml:"author"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	Title      string         `xml:"title"`
+	ID         string         `xml:"id"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published"`
+	Summary    string         `xml:"summary"`
+	Links      []atomLink     `xml:"link"`
+	Categories []atomCategory `xml:"category"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// Atom returns the feed as an Atom 1.0 document.
+func (f *Feed) Atom() ([]byte, error) {
+	doc := atomFeed{
+		Title:   f.Title,
+		ID:      f.FeedURL,
+		Updated: f.Updated.Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: f.FeedURL, Rel: "self", Type: "application/atom+xml"},
+			{Href: f.Link, Rel: "alternate"},
+		},
+		Author:  atomAuthor{Name: "Mantium"},
+		Entries: []atomEntry{},
+	}
+	for _, item := range f.Items {
+		entry := atomEntry{
+			Title:     item.Title,
+			ID:        item.ID,
+			Updated:   item.Published.Format(time.RFC3339),
+			Published: item.Published.Format(time.RFC3339),
+			Summary:   item.Summary,
+		}
+		if item.Link != "" {
+			entry.Links = append(entry.Links, atomLink{Href: item.Link, Rel: "alternate"})
+		}
+		if item.ImageURL != "" {
+			entry.Links = append(entry.Links, atomLink{Href: item.ImageURL, Rel: "enclosure", Type: "image/jpeg"})
+		}
+		for _, tag := range item.Tags {
+			entry.Categories = append(entry.Categories, atomCategory{Term: tag})
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+
+	return marshalXML(doc, "Atom")
+}
+
+func marshalXML(doc interface{}, format string) ([]byte, error) {
+	content, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf("error creating %s feed", format), err)
+	}
+
+	return append([]byte(xml.Header), content...), nil
+}
+
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url,omitempty"`
+	FeedURL     string         `json:"feed_url,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            string   `json:"id"`
+	URL           string   `json:"url,omitempty"`
+	Title         string   `json:"title"`
+	ContentText   string   `json:"content_text"`
+	Image         string   `json:"image,omitempty"`
+	DatePublished string   `json:"date_published"`
+	Tags          []string `json:"tags,omitempty"`
+	// Mantium is a JSON Feed extension with the manga and chapter.
+	Mantium jsonFeedItemExtension `json:"_mantium"`
+}
+
+type jsonFeedItemExtension struct {
+	MangaName   string `json:"manga_name"`
+	MangaURL    string `json:"manga_url,omitempty"`
+	Chapter     string `json:"chapter"`
+	ChapterName string `json:"chapter_name,omitempty"`
+	Source      string `json:"source"`
+}
+
+// JSON returns the feed as a JSON Feed 1.1 document.
+func (f *Feed) JSON() ([]byte, error) {
+	doc := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       f.Title,
+		HomePageURL: f.Link,
+		FeedURL:     f.FeedURL,
+		Description: f.Description,
+		Items:       []jsonFeedItem{},
+	}
+	for _, item := range f.Items {
+		doc.Items = append(doc.Items, jsonFeedItem{
+			ID:            item.ID,
+			URL:           item.Link,
+			Title:         item.Title,
+			ContentText:   item.Summary,
+			Image:         item.ImageURL,
+			DatePublished: item.Published.Format(time.RFC3339),
+			Tags:          item.Tags,
+			Mantium: jsonFeedItemExtension{
+				MangaName:   item.MangaName,
+				MangaURL:    item.MangaURL,
+				Chapter:     item.Chapter,
+				ChapterName: item.ChapterName,
+				Source:      item.Source,
+			},
+		})
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(doc)
+	if err != nil {
+		return nil, util.AddErrorContext("error creating JSON feed", err)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,144 @@
+package feed
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diogovalentte/mantium/api/src/manga"
+)
+
+var released = time.Date(2024, 5, 10, 9, 0, 0, 0, time.UTC)
+
+func getTestMangas() []*manga.Manga {
+	return []*manga.Manga{
+		{
+			ID:                  1,
+			MultiMangaID:        10,
+			Name:                "Berserk & Co",
+			URL:                 "https://mangadex.org/title/1",
+			Source:              "mangadex",
+			Status:              1,
+			CoverImgURL:         "https://mangadex.org/covers/1.jpg",
+			UnreadChapters:      2,
+			Tags:                []string{"dark"},
+			LastReadChapter:     &manga.Chapter{Chapter: "8", UpdatedAt: released.Add(-time.Hour)},
+			LastReleasedChapter: &manga.Chapter{Chapter: "10", Name: "The Eclipse", URL: "https://mangadex.org/chapter/10", UpdatedAt: released},
+		},
+		{
+			ID:                  2,
+			Name:                "Custom",
+			URL:                 manga.CustomMangaURLPrefix + "/abc",
+			Source:              manga.CustomMangaSource,
+			Status:              5,
+			LastReadChapter:     &manga.Chapter{Chapter: "3", UpdatedAt: released.Add(-2 * time.Hour)},
+			LastReleasedChapter: &manga.Chapter{Chapter: "3", URL: manga.CustomMangaURLPrefix + "/def", UpdatedAt: released.Add(-2 * time.Hour)},
+		},
+		{
+			ID:     3,
+			Name:   "No chapters",
+			URL:    "https://comick.io/comic/3",
+			Source: "comick",
+			Status: 2,
+		},
+	}
+}
+
+func TestNew(t *testing.T) {
+	f := New("Mantium", "http://localhost:8080", "http://localhost:8080/v1/feed/rss", getTestMangas())
+	if len(f.Items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(f.Items))
+	}
+	if !f.Updated.Equal(released) {
+		t.Fatalf("expected updated %s, got %s", released, f.Updated)
+	}
+
+	item := f.Items[0]
+	if item.ID != "urn:mantium:multimanga:10:chapter:10" || item.Title != "Berserk & Co - Chapter 10: The Eclipse" || item.Link != "https://mangadex.org/chapter/10" {
+		t.Fatalf("unexpected item: %+v", item)
+	}
+	custom := f.Items[1]
+	if custom.ID != "urn:mantium:manga:2:chapter:3" || custom.Link != "" || custom.MangaURL != "" {
+		t.Fatalf("expected custom manga item without links, got %+v", custom)
+	}
+}
+
+func TestFeedFormats(t *testing.T) {
+	f := New("Mantium", "http://localhost:8080", "http://localhost:8080/v1/feed", getTestMangas())
+
+	t.Run("Should create a valid RSS feed", func(t *testing.T) {
+		content, err := f.RSS()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var doc struct {
+			Channel struct {
+				Items []struct {
+					Title string `xml:"title"`
+					GUID  string `xml:"guid"`
+				} `xml:"item"`
+			} `xml:"channel"`
+		}
+		err = xml.Unmarshal(content, &doc)
+		if err != nil {
+			t.Fatalf("invalid XML: %s\n%s", err, content)
+		}
+		if len(doc.Channel.Items) != 2 || doc.Channel.Items[0].Title != "Berserk & Co - Chapter 10: The Eclipse" || doc.Channel.Items[0].GUID != f.Items[0].ID {
+			t.Fatalf("unexpected RSS feed: %s", content)
+		}
+	})
+	t.Run("Should create a valid Atom feed", func(t *testing.T) {
+		content, err := f.Atom()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var doc struct {
+			XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+			Entries []struct {
+				ID      string `xml:"id"`
+				Updated string `xml:"updated"`
+			} `xml:"entry"`
+		}
+		err = xml.Unmarshal(content, &doc)
+		if err != nil {
+			t.Fatalf("invalid XML: %s\n%s", err, content)
+		}
+		if len(doc.Entries) != 2 || doc.Entries[0].ID != f.Items[0].ID || doc.Entries[0].Updated != "2024-05-10T09:00:00Z" {
+			t.Fatalf("unexpected Atom feed: %s", content)
+		}
+	})
+	t.Run("Should create a valid JSON feed", func(t *testing.T) {
+		content, err := f.JSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var doc map[string]interface{}
+		err = json.Unmarshal(content, &doc)
+		if err != nil {
+			t.Fatalf("invalid JSON: %s\n%s", err, content)
+		}
+		if doc["version"] != "https://jsonfeed.org/version/1.1" || len(doc["items"].([]interface{})) != 2 {
+			t.Fatalf("unexpected JSON feed: %s", content)
+		}
+		if !strings.Contains(string(content), `"manga_name": "Berserk & Co"`) {
+			t.Fatalf("expected the Mantium extension in the JSON feed: %s", content)
+		}
+	})
+}
+
+func TestNewWidget(t *testing.T) {
+	mangas := getTestMangas()
+	widget := NewWidget(mangas[:1], mangas, mangas)
+
+	if widget.UnreadMangas != 1 || widget.UnreadChapters != 2 || widget.TotalMangas != 1 {
+		t.Fatalf("unexpected unread counts: %+v", widget)
+	}
+	if widget.Backlog["reading"] != 1 || widget.Backlog["plan_to_read"] != 0 || widget.Backlog["completed"] != 0 || len(widget.Backlog) != len(StatusNames) {
+		t.Fatalf("unexpected backlog: %v", widget.Backlog)
+	}
+	if len(widget.LatestReleases) != 2 || widget.LatestReleases[0].Status != "reading" || widget.LatestReleases[0].URL != "https://mangadex.org/chapter/10" {
+		t.Fatalf("unexpected latest releases: %+v", widget.LatestReleases)
+	}
+}
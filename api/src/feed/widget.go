@@ -0,0 +1,87 @@
+package feed
+
+import (
+	"time"
+
+	"github.com/diogovalentte/mantium/api/src/manga"
+)
+
+// StatusNames are the names of the manga statuses used in the widget backlog.
+var StatusNames = map[manga.Status]string{
+	1: "reading",
+	2: "completed",
+	3: "on_hold",
+	4: "dropped",
+	5: "plan_to_read",
+}
+
+// Widget is the summary used by dashboard widgets, like Homepage's customapi widget.
+// The fields are flat or one level deep so they can be mapped by the widgets.
+type Widget struct {
+	// Backlog is the number of mangas with unread chapters per status name.
+	Backlog map[string]int `json:"backlog"`
+	// LatestReleases are the latest released chapters.
+	LatestReleases []*WidgetRelease `json:"latest_releases"`
+	// UnreadMangas is the number of mangas with unread chapters.
+	UnreadMangas int `json:"unread_mangas"`
+	// UnreadChapters is the number of unread chapters.
+	UnreadChapters int `json:"unread_chapters"`
+	// TotalMangas is the number of mangas.
+	TotalMangas int `json:"total_mangas"`
+}
+
+// WidgetRelease is a released chapter in the widget.
+type WidgetRelease struct {
+	ReleasedAt     time.Time `json:"released_at"`
+	Name           string    `json:"name"`
+	Chapter        string    `json:"chapter"`
+	ChapterName    string    `json:"chapter_name"`
+	URL            string    `json:"url"`
+	MangaURL       string    `json:"manga_url"`
+	Status         string    `json:"status"`
+	UnreadChapters int       `json:"unread_chapters"`
+}
+
+// NewWidget returns the widget summary. The unread counts are calculated
+// from mangas, the backlog from backlogMangas, and the latest releases are
+// the last released chapters of latestMangas, which should be sorted.
+func NewWidget(mangas, backlogMangas, latestMangas []*manga.Manga) *Widget {
+	widget := &Widget{
+		Backlog:        map[string]int{},
+		LatestReleases: []*WidgetRelease{},
+		TotalMangas:    len(mangas),
+	}
+	for _, name := range StatusNames {
+		widget.Backlog[name] = 0
+	}
+
+	for _, m := range manga.FilterUnreadChapterMangas(mangas) {
+		widget.UnreadMangas++
+		widget.UnreadChapters += m.UnreadChapters
+	}
+
+	for _, m := range manga.FilterUnreadChapterMangas(backlogMangas) {
+		if name, ok := StatusNames[m.Status]; ok {
+			widget.Backlog[name]++
+		}
+	}
+
+	for _, m := range latestMangas {
+		item := newItem(m)
+		if item == nil {
+			continue
+		}
+		widget.LatestReleases = append(widget.LatestReleases, &WidgetRelease{
+			ReleasedAt:     item.Published,
+			Name:           item.MangaName,
+			Chapter:        item.Chapter,
+			ChapterName:    item.ChapterName,
+			URL:            item.Link,
+			MangaURL:       item.MangaURL,
+			Status:         StatusNames[m.Status],
+			UnreadChapters: m.UnreadChapters,
+		})
+	}
+
+	return widget
+}
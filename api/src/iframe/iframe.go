@@ -0,0 +1,400 @@
+// Package iframe implements the mangas iframe widget: its options, layouts, and templates
+package iframe
+
+import (
+	"bytes"
+	"embed"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diogovalentte/mantium/api/src/actions"
+	"github.com/diogovalentte/mantium/api/src/config"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+//go:embed templates
+var defaultTemplates embed.FS
+
+// BaseTemplate is the template with the page head, styles, and scripts shared by all layouts.
+// Each layout template defines the "layout-styles" and "layout" templates used by it.
+const BaseTemplate = "base.html.tmpl"
+
+const (
+	// LayoutList is a list of cards with the cover as background. It's the default layout.
+	LayoutList = "list"
+	// LayoutCompact is a list of small rows without covers.
+	LayoutCompact = "compact"
+	// LayoutGrid is a grid of covers.
+	LayoutGrid = "grid"
+	// LayoutCarousel is a horizontal scrollable row of covers.
+	LayoutCarousel = "carousel"
+	// LayoutNextUp is a single card with the first manga to read.
+	LayoutNextUp = "next_up"
+)
+
+// Layouts are the valid layouts.
+var Layouts = []string{LayoutList, LayoutCompact, LayoutGrid, LayoutCarousel, LayoutNextUp}
+
+const (
+	// SortReleased sorts by the last released chapter date, newest first. It's the default sort.
+	SortReleased = "released"
+	// SortName sorts by the manga name.
+	SortName = "name"
+	// SortUnread sorts by the number of unread chapters, most first.
+	SortUnread = "unread"
+	// SortRead sorts by the last read chapter date, newest first.
+	SortRead = "read"
+)
+
+// Sorts are the valid sorts.
+var Sorts = []string{SortReleased, SortName, SortUnread, SortRead}
+
+// Options are the iframe options, usually set by the iframe URL query.
+type Options struct {
+	// CSSVariables are set in the page root, like {"released-color": "#fa5252"}
+	// sets the "--mantium-released-color" variable.
+	CSSVariables map[string]string
+	Layout       string
+	Theme        string
+	Sort         string
+	APIURL       string
+	Statuses     []manga.Status
+	Tags         []string
+	Sources      []string
+	// Limit is the max number of mangas, -1 for no limit.
+	Limit int
+	// OnlyUnread shows only mangas with unread chapters.
+	OnlyUnread                 bool
+	ShowBackgroundErrorWarning bool
+}
+
+// ParseOptions parses the iframe options from the URL query. The CSS variables
+// from the dashboard configs are used as defaults for the query CSS variables.
+//
+// Query parameters: api_url, theme, layout, showBackgroundErrorWarning, css_<variable>,
+// and the ParseFilterOptions parameters.
+func ParseOptions(query url.Values) (*Options, error) {
+	options := &Options{
+		Layout:                     LayoutList,
+		Theme:                      "light",
+		Sort:                       SortReleased,
+		Statuses:                   []manga.Status{1, 2},
+		Limit:                      -1,
+		OnlyUnread:                 true,
+		ShowBackgroundErrorWarning: true,
+		CSSVariables:               map[string]string{},
+	}
+	var err error
+
+	options.APIURL = query.Get("api_url")
+	_, err = url.ParseRequestURI(options.APIURL)
+	if err != nil {
+		return nil, fmt.Errorf("api_url must be a valid URL like 'http://192.168.1.46:8080' or 'https://sub.domain.com'")
+	}
+
+	if theme := query.Get("theme"); theme != "" {
+		if theme != "dark" && theme != "light" {
+			return nil, fmt.Errorf("theme must be 'dark' or 'light'")
+		}
+		options.Theme = theme
+	}
+
+	if layout := query.Get("layout"); layout != "" {
+		if !slices.Contains(Layouts, layout) {
+			return nil, fmt.Errorf("layout must be one of %v", Layouts)
+		}
+		options.Layout = layout
+	}
+
+	err = ParseFilterOptions(query, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if showStr := query.Get("showBackgroundErrorWarning"); showStr != "" {
+		options.ShowBackgroundErrorWarning, err = strconv.ParseBool(showStr)
+		if err != nil {
+			return nil, fmt.Errorf("showBackgroundErrorWarning must be a boolean")
+		}
+	}
+
+	for name, value := range config.GlobalConfigs.DashboardConfigs.Display.IframeCSSVariables {
+		options.CSSVariables[name] = value
+	}
+	for key, values := range query {
+		if !strings.HasPrefix(key, "css_") || len(values) == 0 {
+			continue
+		}
+		name := strings.ReplaceAll(strings.TrimPrefix(key, "css_"), "_", "-")
+		options.CSSVariables[name] = values[len(values)-1]
+	}
+	err = ValidateCSSVariables(options.CSSVariables)
+	if err != nil {
+		return nil, err
+	}
+
+	return options, nil
+}
+
+// ParseFilterOptions parses the options that filter, sort, and limit the mangas
+// from the URL query into options. Options not in the query keep their values.
+//
+// Query parameters: status, tags, source, sort, limit, and unread.
+func ParseFilterOptions(query url.Values, options *Options) error {
+	var err error
+
+	if sortBy := query.Get("sort"); sortBy != "" {
+		if !slices.Contains(Sorts, sortBy) {
+			return fmt.Errorf("sort must be one of %v", Sorts)
+		}
+		options.Sort = sortBy
+	}
+
+	if limitStr := query.Get("limit"); limitStr != "" {
+		options.Limit, err = strconv.Atoi(limitStr)
+		if err != nil {
+			return fmt.Errorf("limit must be a number")
+		}
+	}
+
+	if statusStr := query.Get("status"); statusStr != "" {
+		options.Statuses = []manga.Status{}
+		for _, s := range splitQueryList(statusStr) {
+			status, err := strconv.Atoi(s)
+			if err != nil || status < 0 || status > 5 {
+				return fmt.Errorf("status must be a comma separated list of statuses from 0 to 5, 0 means all statuses")
+			}
+			if status == 0 {
+				options.Statuses = []manga.Status{}
+				break
+			}
+			options.Statuses = append(options.Statuses, manga.Status(status))
+		}
+	}
+
+	options.Tags = splitQueryList(query.Get("tags"))
+	options.Sources = splitQueryList(query.Get("source"))
+
+	if unreadStr := query.Get("unread"); unreadStr != "" {
+		options.OnlyUnread, err = strconv.ParseBool(unreadStr)
+		if err != nil {
+			return fmt.Errorf("unread must be a boolean")
+		}
+	}
+
+	return nil
+}
+
+func splitQueryList(value string) []string {
+	list := []string{}
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			list = append(list, item)
+		}
+	}
+
+	return list
+}
+
+var (
+	cssVariableNameRegex    = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,62}$`)
+	cssVariableInvalidRegex = regexp.MustCompile(`[;{}<>\\]`)
+)
+
+// ValidateCSSVariables validates the CSS variables names and values,
+// as they're written in the page style.
+func ValidateCSSVariables(variables map[string]string) error {
+	for name, value := range variables {
+		if !cssVariableNameRegex.MatchString(name) {
+			return fmt.Errorf("invalid CSS variable name '%s', it must have only lower case letters, numbers, and '-'", name)
+		}
+		if len(value) > 200 || cssVariableInvalidRegex.MatchString(value) || strings.Contains(value, "/*") {
+			return fmt.Errorf("invalid CSS variable '%s' value '%s'", name, value)
+		}
+	}
+
+	return nil
+}
+
+// FilterMangas returns the mangas that match the options, sorted and limited by the options.
+func FilterMangas(mangas []*manga.Manga, options *Options) []*manga.Manga {
+	if options.OnlyUnread {
+		mangas = manga.FilterUnreadChapterMangas(mangas)
+	}
+
+	filtered := []*manga.Manga{}
+	for _, m := range mangas {
+		if len(options.Statuses) > 0 && !slices.Contains(options.Statuses, m.Status) {
+			continue
+		}
+		if len(options.Sources) > 0 && !slices.Contains(options.Sources, m.Source) {
+			continue
+		}
+		if !manga.HasAnyTag(m.Tags, options.Tags) {
+			continue
+		}
+		filtered = append(filtered, m)
+	}
+
+	switch options.Sort {
+	case SortName:
+		sort.SliceStable(filtered, func(i, j int) bool {
+			return strings.ToLower(filtered[i].Name) < strings.ToLower(filtered[j].Name)
+		})
+	case SortUnread:
+		manga.SortMangasByLastReleasedChapterUpdatedAt(filtered)
+		sort.SliceStable(filtered, func(i, j int) bool {
+			return filtered[i].UnreadChapters > filtered[j].UnreadChapters
+		})
+	case SortRead:
+		manga.SortMangasByLastReleasedChapterUpdatedAt(filtered)
+		sort.SliceStable(filtered, func(i, j int) bool {
+			return lastReadAt(filtered[i]).After(lastReadAt(filtered[j]))
+		})
+	default:
+		manga.SortMangasByLastReleasedChapterUpdatedAt(filtered)
+	}
+
+	if options.Limit >= 0 && options.Limit < len(filtered) {
+		filtered = filtered[:options.Limit]
+	}
+
+	return filtered
+}
+
+func lastReadAt(m *manga.Manga) time.Time {
+	if m.LastReadChapter == nil {
+		return time.Time{}
+	}
+	return m.LastReadChapter.UpdatedAt
+}
+
+// TemplateData is the data used to render the iframe templates.
+type TemplateData struct {
+	BackgroundErrorTime time.Time
+	Theme               string
+	Layout              string
+	APIURL              string
+	// CSSVariables is the CSS declarations of the variables, like "--mantium-released-color: #fa5252;"
+	CSSVariables        template.CSS
+	Mangas              []*manga.Manga
+	ShowBackgroundError bool
+}
+
+// NewTemplateData returns the template data for the mangas and options.
+func NewTemplateData(mangas []*manga.Manga, options *Options) *TemplateData {
+	variables := map[string]string{
+		"scrollbar-thumb-color": "rgba(209, 219, 227, 1)",
+		"scrollbar-track-color": "#ffffff",
+	}
+	if options.Theme == "dark" {
+		variables["scrollbar-thumb-color"] = "#484d64"
+		variables["scrollbar-track-color"] = "rgba(37, 40, 53, 1)"
+	}
+	for name, value := range options.CSSVariables {
+		variables[name] = value
+	}
+
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	var declarations strings.Builder
+	for _, name := range names {
+		declarations.WriteString(fmt.Sprintf("--mantium-%s: %s; ", name, variables[name]))
+	}
+
+	return &TemplateData{
+		Theme:  options.Theme,
+		Layout: options.Layout,
+		APIURL: options.APIURL,
+		// The variables are validated by ValidateCSSVariables
+		CSSVariables: template.CSS(strings.TrimSpace(declarations.String())),
+		Mangas:       mangas,
+	}
+}
+
+// Render renders the iframe with the data's layout. The templates are read
+// from the iframe templates directory if they exist there, else the default
+// templates are used.
+func Render(data *TemplateData) ([]byte, error) {
+	contextError := fmt.Sprintf("error rendering iframe with layout '%s'", data.Layout)
+
+	funcs := template.FuncMap{
+		"encodeImage": func(bytes []byte) string {
+			return base64.StdEncoding.EncodeToString(bytes)
+		},
+		"isCustomManga": func(source string) bool {
+			return source == manga.CustomMangaSource
+		},
+		"isCustomMangaURL": func(url string) bool {
+			return strings.HasPrefix(url, manga.CustomMangaURLPrefix)
+		},
+		// markReadLink returns the signed link to mark the manga's last released
+		// chapter as read, or an empty string if the action links are disabled.
+		"markReadLink": func(m *manga.Manga) string {
+			if m.MultiMangaID == 0 {
+				return ""
+			}
+			link, err := actions.MarkReadLink(data.APIURL, m, time.Now())
+			if err != nil {
+				return ""
+			}
+			return link
+		},
+	}
+
+	tmpl := template.New(BaseTemplate).Funcs(funcs)
+	for _, templateName := range []string{BaseTemplate, data.Layout + ".html.tmpl"} {
+		content, err := readTemplate(templateName)
+		if err != nil {
+			return nil, util.AddErrorContext(contextError, err)
+		}
+		_, err = tmpl.Parse(content)
+		if err != nil {
+			return nil, util.AddErrorContext(contextError, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	err := tmpl.ExecuteTemplate(&buf, BaseTemplate, data)
+	if err != nil {
+		return nil, util.AddErrorContext(contextError, err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func readTemplate(templateName string) (string, error) {
+	templatesDir := config.GlobalConfigs.Iframe.TemplatesDir
+	if templatesDir != "" {
+		path := filepath.Join(templatesDir, templateName)
+		if util.FileExists(path) {
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return "", err
+			}
+			return string(content), nil
+		}
+	}
+
+	content, err := defaultTemplates.ReadFile("templates/" + templateName)
+	if err != nil {
+		return "", err
+	}
+
+	return string(content), nil
+}
@@ -0,0 +1,203 @@
+package iframe
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diogovalentte/mantium/api/src/config"
+	"github.com/diogovalentte/mantium/api/src/manga"
+)
+
+func TestParseOptions(t *testing.T) {
+	t.Run("Should use the default options", func(t *testing.T) {
+		options, err := ParseOptions(url.Values{"api_url": {"http://localhost:8080"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if options.Layout != LayoutList || options.Theme != "light" || options.Sort != SortReleased || options.Limit != -1 || !options.OnlyUnread || !options.ShowBackgroundErrorWarning {
+			t.Fatalf("unexpected default options: %+v", options)
+		}
+		if len(options.Statuses) != 2 || options.Statuses[0] != 1 || options.Statuses[1] != 2 {
+			t.Fatalf("expected statuses 1,2, got %v", options.Statuses)
+		}
+	})
+	t.Run("Should parse the options", func(t *testing.T) {
+		query, err := url.ParseQuery("api_url=http://localhost:8080&theme=dark&layout=grid&status=1,5&tags=Action,%20weekly&source=mangadex&sort=name&limit=3&unread=false&css_released_color=%23fa5252")
+		if err != nil {
+			t.Fatal(err)
+		}
+		options, err := ParseOptions(query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if options.Layout != LayoutGrid || options.Theme != "dark" || options.Sort != SortName || options.Limit != 3 || options.OnlyUnread {
+			t.Fatalf("unexpected options: %+v", options)
+		}
+		if len(options.Statuses) != 2 || options.Statuses[1] != 5 {
+			t.Fatalf("expected statuses 1,5, got %v", options.Statuses)
+		}
+		if len(options.Tags) != 2 || options.Tags[1] != "weekly" || len(options.Sources) != 1 {
+			t.Fatalf("unexpected tags %v or sources %v", options.Tags, options.Sources)
+		}
+		if options.CSSVariables["released-color"] != "#fa5252" {
+			t.Fatalf("unexpected CSS variables: %v", options.CSSVariables)
+		}
+	})
+	t.Run("Should accept all statuses", func(t *testing.T) {
+		options, err := ParseOptions(url.Values{"api_url": {"http://localhost:8080"}, "status": {"0"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(options.Statuses) != 0 {
+			t.Fatalf("expected no status filter, got %v", options.Statuses)
+		}
+	})
+
+	invalidQueries := []url.Values{
+		{},
+		{"api_url": {"http://localhost:8080"}, "layout": {"table"}},
+		{"api_url": {"http://localhost:8080"}, "sort": {"random"}},
+		{"api_url": {"http://localhost:8080"}, "status": {"1,6"}},
+		{"api_url": {"http://localhost:8080"}, "css_font_family": {"a; } body { display: none"}},
+		{"api_url": {"http://localhost:8080"}, "css_Font": {"red"}},
+	}
+	for _, query := range invalidQueries {
+		t.Run("Should not parse invalid options", func(t *testing.T) {
+			_, err := ParseOptions(query)
+			if err == nil {
+				t.Fatalf("expected error for query %v", query)
+			}
+		})
+	}
+}
+
+func TestFilterMangas(t *testing.T) {
+	now := time.Now()
+	newManga := func(id manga.ID, name string, status manga.Status, source string, unread int, released time.Time, tags ...string) *manga.Manga {
+		return &manga.Manga{
+			ID:                  id,
+			Name:                name,
+			Status:              status,
+			Source:              source,
+			UnreadChapters:      unread,
+			Tags:                tags,
+			LastReadChapter:     &manga.Chapter{Chapter: "1", UpdatedAt: released.Add(-time.Hour)},
+			LastReleasedChapter: &manga.Chapter{Chapter: "10", UpdatedAt: released},
+		}
+	}
+	mangas := []*manga.Manga{
+		newManga(1, "Berserk", 1, "mangadex", 2, now.Add(-2*time.Hour), "dark"),
+		newManga(2, "Akira", 2, "comick", 5, now.Add(-time.Hour)),
+		newManga(3, "Chainsaw Man", 5, "mangadex", 1, now, "action"),
+		newManga(4, "Dandadan", 1, "mangadex", 0, now, "action"),
+	}
+	mangas[3].LastReadChapter.Chapter = "10"
+
+	tests := []struct {
+		name     string
+		options  Options
+		expected []manga.ID
+	}{
+		{
+			name:     "Should filter unread reading or completed mangas sorted by last released chapter",
+			options:  Options{Statuses: []manga.Status{1, 2}, OnlyUnread: true, Limit: -1, Sort: SortReleased},
+			expected: []manga.ID{2, 1},
+		},
+		{
+			name:     "Should filter by tags and sort by name",
+			options:  Options{OnlyUnread: false, Tags: []string{"Action"}, Limit: -1, Sort: SortName},
+			expected: []manga.ID{3, 4},
+		},
+		{
+			name:     "Should filter by source and sort by unread chapters",
+			options:  Options{OnlyUnread: true, Sources: []string{"mangadex"}, Limit: -1, Sort: SortUnread},
+			expected: []manga.ID{1, 3},
+		},
+		{
+			name:     "Should limit",
+			options:  Options{OnlyUnread: false, Limit: 1, Sort: SortName},
+			expected: []manga.ID{2},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filtered := FilterMangas(mangas, &test.options)
+			ids := []manga.ID{}
+			for _, m := range filtered {
+				ids = append(ids, m.ID)
+			}
+			if len(ids) != len(test.expected) {
+				t.Fatalf("expected %v, got %v", test.expected, ids)
+			}
+			for i := range ids {
+				if ids[i] != test.expected[i] {
+					t.Fatalf("expected %v, got %v", test.expected, ids)
+				}
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	mangas := []*manga.Manga{
+		{
+			ID:                  1,
+			MultiMangaID:        2,
+			Name:                "Berserk <b>",
+			URL:                 "https://mangadex.org/title/1",
+			Source:              "mangadex",
+			UnreadChapters:      3,
+			LastReadChapter:     &manga.Chapter{Chapter: "7", URL: "https://mangadex.org/chapter/7"},
+			LastReleasedChapter: &manga.Chapter{Chapter: "10", URL: "https://mangadex.org/chapter/10"},
+		},
+	}
+
+	for _, layout := range Layouts {
+		t.Run("Should render layout "+layout, func(t *testing.T) {
+			options := &Options{Layout: layout, Theme: "dark", APIURL: "http://localhost:8080", CSSVariables: map[string]string{"released-color": "#fa5252"}}
+			html, err := Render(NewTemplateData(mangas, options))
+			if err != nil {
+				t.Fatal(err)
+			}
+			content := string(html)
+			if !strings.Contains(content, "--mantium-released-color: #fa5252;") {
+				t.Fatal("expected the CSS variable in the page")
+			}
+			if !strings.Contains(content, "Berserk &lt;b&gt;") || !strings.Contains(content, "setMultiMangaLastReadChapter") {
+				t.Fatalf("expected the manga in the page: %s", content)
+			}
+		})
+		t.Run("Should render layout "+layout+" without mangas", func(t *testing.T) {
+			options := &Options{Layout: layout, Theme: "light", APIURL: "http://localhost:8080"}
+			_, err := Render(NewTemplateData([]*manga.Manga{}, options))
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestRenderMarkReadLink(t *testing.T) {
+	previousConfigs := *config.GlobalConfigs.ActionLinks
+	defer func() { *config.GlobalConfigs.ActionLinks = previousConfigs }()
+	*config.GlobalConfigs.ActionLinks = config.ActionLinksConfigs{Secret: []byte("0123456789abcdef"), TTL: time.Hour, Valid: true}
+
+	mangas := []*manga.Manga{
+		{ID: 1, MultiMangaID: 2, Name: "Berserk", Source: "mangadex", LastReleasedChapter: &manga.Chapter{Chapter: "10"}},
+		{ID: 3, Name: "Akira", Source: "mangadex", LastReleasedChapter: &manga.Chapter{Chapter: "5"}},
+	}
+	html, err := Render(NewTemplateData(mangas, &Options{Layout: LayoutList, Theme: "light", APIURL: "http://localhost:8080"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(html)
+	if !strings.Contains(content, `markReadWithLink('http:\/\/localhost:8080\/v1\/actions\/mark_read?token=`) {
+		t.Fatalf("expected the mark read link in the page: %s", content)
+	}
+	if !strings.Contains(content, `setMangaLastReadChapter('3')`) {
+		t.Fatal("expected the manga without multimanga to use the last read chapter request")
+	}
+}
@@ -0,0 +1,69 @@
+// Package downloads implements a common interface for the integrations that
+// download the mangas' chapters, like Kaizoku, Tranga, and Suwayomi, and the
+// reconciliation of their libraries with Mantium's library.
+package downloads
+
+import (
+	"github.com/diogovalentte/mantium/api/src/config"
+	"github.com/diogovalentte/mantium/api/src/integrations/kaizoku"
+	"github.com/diogovalentte/mantium/api/src/integrations/suwayomi"
+	"github.com/diogovalentte/mantium/api/src/integrations/tranga"
+	"github.com/diogovalentte/mantium/api/src/manga"
+)
+
+// DownloadIntegration is an integration with a server that downloads the mangas' chapters.
+type DownloadIntegration interface {
+	// Name returns the integration name, like "Kaizoku".
+	Name() string
+	// SupportedSources returns the Mantium sources of the mangas that can be added to the integration.
+	SupportedSources() []string
+	// AddManga adds the manga to the integration, so it downloads the manga's chapters.
+	AddManga(m *manga.Manga) error
+	// RemoveManga does the removal action (like config.RemovalActionRemove) with a manga
+	// returned by GetStatus. Unsupported actions return errordefs.ErrRemovalActionNotSupported.
+	RemoveManga(m *Manga, action string) error
+	// RemovalPolicy returns the actions to do when the mangas are deleted, dropped, or completed in Mantium.
+	RemovalPolicy() config.RemovalPolicy
+	// EnqueueChapter downloads the manga's chapter, like a newly released chapter.
+	EnqueueChapter(m *manga.Manga, chapter *manga.Chapter) error
+	// GetStatus returns the mangas in the integration.
+	GetStatus() ([]*Manga, error)
+	// GetMangaKey returns the key of a Mantium manga. A Mantium manga
+	// is in the integration if a manga returned by GetStatus has the same key.
+	GetMangaKey(m *manga.Manga) (string, error)
+}
+
+// Manga is a manga in a download integration, or a Mantium manga missing in it.
+type Manga struct {
+	// ID is the manga ID in the integration. It's empty for Mantium mangas.
+	ID    string `json:"id,omitempty"`
+	Title string `json:"title"`
+	// URL is the manga URL in the source, if known.
+	URL string `json:"url,omitempty"`
+	// Source is the Mantium source of Mantium mangas, or the integration source of the integration mangas.
+	Source string `json:"source,omitempty"`
+	// Key is used to match the integration mangas with the Mantium mangas.
+	Key string `json:"key"`
+}
+
+// GetIntegrations returns the download integrations configured in the API.
+func GetIntegrations() []DownloadIntegration {
+	var integrations []DownloadIntegration
+	if config.GlobalConfigs.Kaizoku.Valid {
+		k := &kaizoku.Kaizoku{}
+		k.Init()
+		integrations = append(integrations, &Kaizoku{Kaizoku: k, TryOtherSources: config.GlobalConfigs.Kaizoku.TryOtherSources, Policy: config.GlobalConfigs.Kaizoku.RemovalPolicy})
+	}
+	if config.GlobalConfigs.Tranga.Valid {
+		t := &tranga.Tranga{}
+		t.Init()
+		integrations = append(integrations, &Tranga{Tranga: t, Policy: config.GlobalConfigs.Tranga.RemovalPolicy})
+	}
+	if config.GlobalConfigs.Suwayomi.Valid {
+		s := &suwayomi.Suwayomi{}
+		s.Init()
+		integrations = append(integrations, &Suwayomi{Suwayomi: s, Policy: config.GlobalConfigs.Suwayomi.RemovalPolicy})
+	}
+
+	return integrations
+}
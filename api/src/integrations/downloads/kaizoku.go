@@ -0,0 +1,99 @@
+package downloads
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/diogovalentte/mantium/api/src/config"
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/integrations/kaizoku"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/sources"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// Kaizoku is the Kaizoku download integration.
+// Kaizoku adds the mangas by name, so they're matched by name.
+type Kaizoku struct {
+	Kaizoku *kaizoku.Kaizoku
+	// TryOtherSources makes Kaizoku try to add the manga from
+	// other sources if it fails to add it from the manga's source.
+	TryOtherSources bool
+	Policy          config.RemovalPolicy
+}
+
+func (k *Kaizoku) Name() string {
+	return "Kaizoku"
+}
+
+func (k *Kaizoku) SupportedSources() []string {
+	if k.TryOtherSources {
+		allSources := []string{}
+		for source := range sources.GetSources() {
+			allSources = append(allSources, source)
+		}
+		slices.Sort(allSources)
+		return allSources
+	}
+
+	return kaizoku.SupportedSources()
+}
+
+func (k *Kaizoku) AddManga(m *manga.Manga) error {
+	return k.Kaizoku.AddManga(m, k.TryOtherSources)
+}
+
+func (k *Kaizoku) RemoveManga(m *Manga, action string) error {
+	errorContext := fmt.Sprintf("(kaizoku) error while removing manga '%s' (action: %s)", m.Title, action)
+	if action != config.RemovalActionRemove && action != config.RemovalActionDeleteFiles {
+		return util.AddErrorContext(errorContext, errordefs.ErrRemovalActionNotSupported)
+	}
+	id, err := strconv.Atoi(m.ID)
+	if err != nil {
+		return util.AddErrorContext(errorContext, fmt.Errorf("invalid ID '%s'", m.ID))
+	}
+
+	return k.Kaizoku.RemoveManga(id, action == config.RemovalActionDeleteFiles)
+}
+
+func (k *Kaizoku) RemovalPolicy() config.RemovalPolicy {
+	return k.Policy
+}
+
+// EnqueueChapter does nothing, as Kaizoku can't download a single chapter.
+// The chapters are downloaded by checking and fixing the out of sync chapters
+// of all mangas after the mangas metadata is updated.
+func (k *Kaizoku) EnqueueChapter(_ *manga.Manga, _ *manga.Chapter) error {
+	return nil
+}
+
+func (k *Kaizoku) GetStatus() ([]*Manga, error) {
+	kaizokuMangas, err := k.Kaizoku.GetMangas()
+	if err != nil {
+		return nil, err
+	}
+
+	mangas := make([]*Manga, 0, len(kaizokuMangas))
+	for _, m := range kaizokuMangas {
+		mangas = append(mangas, &Manga{
+			ID:     strconv.Itoa(m.ID),
+			Title:  m.Title,
+			Source: m.Source,
+			Key:    util.NormalizeName(m.Title),
+		})
+	}
+
+	return mangas, nil
+}
+
+// CanDetectExtra returns false if TryOtherSources is enabled, as Kaizoku may have
+// added the mangas from another source with another title, so they don't match the
+// Mantium mangas and would be removed by the reconciliation.
+func (k *Kaizoku) CanDetectExtra() bool {
+	return !k.TryOtherSources
+}
+
+func (k *Kaizoku) GetMangaKey(m *manga.Manga) (string, error) {
+	return util.NormalizeName(m.Name), nil
+}
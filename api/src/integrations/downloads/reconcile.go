@@ -0,0 +1,165 @@
+package downloads
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/diogovalentte/mantium/api/src/config"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// Drift is the difference between Mantium's library and a download integration's library.
+type Drift struct {
+	Integration string `json:"integration"`
+	// Missing are the Mantium mangas that are not in the integration.
+	Missing []*Manga `json:"missing"`
+	// Extra are the integration mangas that are not in Mantium.
+	Extra []*Manga `json:"extra"`
+	// Unsupported are the Mantium mangas from sources the integration doesn't support.
+	Unsupported []*Manga `json:"unsupported"`
+
+	// missingMangas are the Mantium mangas of Missing, in the same order.
+	missingMangas []*manga.Manga
+	// supportedMangas is the number of Mantium mangas the integration supports.
+	supportedMangas int
+}
+
+// extraDetector is implemented by the download integrations that can't always
+// tell whether one of their mangas is in Mantium.
+type extraDetector interface {
+	// CanDetectExtra returns whether the integration mangas that don't match
+	// any Mantium manga can be reported as extra and removed.
+	CanDetectExtra() bool
+}
+
+// ReconcileResult is the result of reconciling a download integration's library.
+type ReconcileResult struct {
+	*Drift
+	Added   []*Manga `json:"added"`
+	Removed []*Manga `json:"removed"`
+	Errors  []string `json:"errors"`
+}
+
+// GetDrift compares the Mantium mangas with the mangas in the integration.
+// Custom mangas and mangas whose status has an action in the integration's removal
+// policy, like dropped mangas, are ignored. Mantium mangas with the same key, like
+// the same manga from different sources in Kaizoku, are reported once.
+// otherMangas are Mantium mangas that are not expected in the integration, like the
+// multimangas' mangas that are not the current manga. They're not reported as missing,
+// but the integration mangas with their keys are not reported as extra either.
+func GetDrift(integration DownloadIntegration, mangas, otherMangas []*manga.Manga) (*Drift, error) {
+	errorContext := fmt.Sprintf("error while getting the drift of %s", integration.Name())
+
+	integrationMangas, err := integration.GetStatus()
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+	integrationKeys := map[string]bool{}
+	for _, m := range integrationMangas {
+		integrationKeys[m.Key] = true
+	}
+
+	drift := &Drift{
+		Integration: integration.Name(),
+		Missing:     []*Manga{},
+		Extra:       []*Manga{},
+		Unsupported: []*Manga{},
+	}
+	supportedSources := integration.SupportedSources()
+	policy := integration.RemovalPolicy()
+	mantiumKeys := map[string]bool{}
+	for _, m := range mangas {
+		if m.Source == manga.CustomMangaSource {
+			continue
+		}
+		if event := StatusRemovalEvent(m.Status); event != "" && policy[event] != "" {
+			continue
+		}
+		if !slices.Contains(supportedSources, m.Source) {
+			drift.Unsupported = append(drift.Unsupported, &Manga{Title: m.Name, URL: m.URL, Source: m.Source})
+			continue
+		}
+
+		key, err := integration.GetMangaKey(m)
+		if err != nil {
+			return nil, util.AddErrorContext(errorContext, err)
+		}
+		if mantiumKeys[key] {
+			continue
+		}
+		mantiumKeys[key] = true
+
+		if !integrationKeys[key] {
+			drift.Missing = append(drift.Missing, &Manga{Title: m.Name, URL: m.URL, Source: m.Source, Key: key})
+			drift.missingMangas = append(drift.missingMangas, m)
+		}
+	}
+
+	drift.supportedMangas = len(mantiumKeys)
+
+	if detector, ok := integration.(extraDetector); ok && !detector.CanDetectExtra() {
+		return drift, nil
+	}
+
+	knownKeys := map[string]bool{}
+	for _, m := range otherMangas {
+		if m.Source == manga.CustomMangaSource || !slices.Contains(supportedSources, m.Source) {
+			continue
+		}
+		key, err := integration.GetMangaKey(m)
+		if err != nil {
+			return nil, util.AddErrorContext(errorContext, err)
+		}
+		knownKeys[key] = true
+	}
+
+	for _, m := range integrationMangas {
+		if !mantiumKeys[m.Key] && !knownKeys[m.Key] {
+			drift.Extra = append(drift.Extra, m)
+		}
+	}
+
+	return drift, nil
+}
+
+// Reconcile adds the Mantium mangas missing in the integration to it. If remove is true,
+// it also removes the integration mangas that are not in Mantium. otherMangas are used
+// like in GetDrift, so they're neither added nor removed. To not clear the
+// integration by mistake, nothing is removed if there are no Mantium mangas the
+// integration supports. If it fails to add or remove a manga, it continues with the next one.
+func Reconcile(integration DownloadIntegration, mangas, otherMangas []*manga.Manga, remove bool) (*ReconcileResult, error) {
+	drift, err := GetDrift(integration, mangas, otherMangas)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReconcileResult{
+		Drift:   drift,
+		Added:   []*Manga{},
+		Removed: []*Manga{},
+		Errors:  []string{},
+	}
+	for i, m := range drift.missingMangas {
+		err = integration.AddManga(m)
+		if err != nil {
+			result.Errors = append(result.Errors, err.Error())
+			continue
+		}
+		result.Added = append(result.Added, drift.Missing[i])
+	}
+
+	if !remove || drift.supportedMangas == 0 {
+		return result, nil
+	}
+	for _, m := range drift.Extra {
+		err = integration.RemoveManga(m, config.RemovalActionRemove)
+		if err != nil {
+			result.Errors = append(result.Errors, err.Error())
+			continue
+		}
+		result.Removed = append(result.Removed, m)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,183 @@
+package downloads
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/diogovalentte/mantium/api/src/config"
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/manga"
+)
+
+// fakeIntegration is a download integration that matches the mangas by URL.
+type fakeIntegration struct {
+	mangas  []*Manga
+	added   []string
+	removed []string
+	// failURL makes AddManga fail for the manga with this URL
+	failURL string
+	policy  config.RemovalPolicy
+	// actions are the removal actions by manga ID
+	actions map[string]string
+}
+
+func (f *fakeIntegration) Name() string { return "Fake" }
+
+func (f *fakeIntegration) SupportedSources() []string { return []string{"mangadex", "comick"} }
+
+func (f *fakeIntegration) AddManga(m *manga.Manga) error {
+	if m.URL == f.failURL {
+		return fmt.Errorf("error adding manga '%s'", m.URL)
+	}
+	f.added = append(f.added, m.URL)
+	return nil
+}
+
+func (f *fakeIntegration) RemoveManga(m *Manga, action string) error {
+	if action == config.RemovalActionUnmonitor {
+		return errordefs.ErrRemovalActionNotSupported
+	}
+	f.removed = append(f.removed, m.ID)
+	f.actions[m.ID] = action
+	return nil
+}
+
+func (f *fakeIntegration) RemovalPolicy() config.RemovalPolicy { return f.policy }
+
+func (f *fakeIntegration) EnqueueChapter(_ *manga.Manga, _ *manga.Chapter) error { return nil }
+
+func (f *fakeIntegration) GetStatus() ([]*Manga, error) { return f.mangas, nil }
+
+func (f *fakeIntegration) GetMangaKey(m *manga.Manga) (string, error) {
+	return strings.TrimSuffix(m.URL, "/"), nil
+}
+
+// noExtraIntegration is a fake integration that can't detect the extra mangas.
+type noExtraIntegration struct {
+	*fakeIntegration
+}
+
+func (n *noExtraIntegration) CanDetectExtra() bool { return false }
+
+func newFakeIntegration() *fakeIntegration {
+	return &fakeIntegration{actions: map[string]string{}, mangas: []*Manga{
+		{ID: "1", Title: "Berserk", Key: "https://mangadex.org/title/berserk"},
+		{ID: "2", Title: "Dandadan", Key: "https://mangadex.org/title/dandadan"},
+	}}
+}
+
+var mantiumMangas = []*manga.Manga{
+	{Name: "Berserk", URL: "https://mangadex.org/title/berserk/", Source: "mangadex"},
+	{Name: "Chainsaw Man", URL: "https://comick.io/comic/chainsaw-man", Source: "comick"},
+	{Name: "Chainsaw Man", URL: "https://comick.io/comic/chainsaw-man", Source: "comick"},
+	{Name: "One Piece", URL: "https://mangaplus.shueisha.co.jp/titles/100020", Source: "mangaplus"},
+	{Name: "My manga", URL: manga.CustomMangaURLPrefix + "/my-manga", Source: manga.CustomMangaSource},
+}
+
+func TestGetDrift(t *testing.T) {
+	drift, err := GetDrift(newFakeIntegration(), mantiumMangas, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(drift.Missing) != 1 || drift.Missing[0].Title != "Chainsaw Man" {
+		t.Fatalf("expected only Chainsaw Man to be missing, got %v", drift.Missing)
+	}
+	if len(drift.Extra) != 1 || drift.Extra[0].ID != "2" {
+		t.Fatalf("expected only Dandadan to be extra, got %v", drift.Extra)
+	}
+	if len(drift.Unsupported) != 1 || drift.Unsupported[0].Source != "mangaplus" {
+		t.Fatalf("expected only One Piece to be unsupported, got %v", drift.Unsupported)
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	t.Run("Should only add the missing mangas", func(t *testing.T) {
+		integration := newFakeIntegration()
+		result, err := Reconcile(integration, mantiumMangas, nil, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(integration.added) != 1 || integration.added[0] != "https://comick.io/comic/chainsaw-man" {
+			t.Fatalf("expected Chainsaw Man to be added once, got %v", integration.added)
+		}
+		if len(integration.removed) != 0 || len(result.Removed) != 0 {
+			t.Fatalf("expected no manga to be removed, got %v", integration.removed)
+		}
+	})
+	t.Run("Should add the missing mangas and remove the extra mangas", func(t *testing.T) {
+		integration := newFakeIntegration()
+		result, err := Reconcile(integration, mantiumMangas, nil, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Added) != 1 {
+			t.Fatalf("expected 1 manga to be added, got %v", result.Added)
+		}
+		if len(integration.removed) != 1 || integration.removed[0] != "2" {
+			t.Fatalf("expected Dandadan to be removed, got %v", integration.removed)
+		}
+	})
+	t.Run("Should continue if adding a manga fails", func(t *testing.T) {
+		integration := newFakeIntegration()
+		integration.failURL = "https://comick.io/comic/chainsaw-man"
+		result, err := Reconcile(integration, mantiumMangas, nil, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Added) != 0 || len(result.Errors) != 1 {
+			t.Fatalf("expected 1 error and no manga added, got %v and %v", result.Errors, result.Added)
+		}
+		if len(result.Removed) != 1 {
+			t.Fatalf("expected 1 manga to be removed, got %v", result.Removed)
+		}
+	})
+	t.Run("Should not add the mangas with a removal policy for their status", func(t *testing.T) {
+		integration := newFakeIntegration()
+		integration.policy = config.RemovalPolicy{config.RemovalEventDrop: config.RemovalActionRemove}
+		dropped := *mantiumMangas[1]
+		dropped.Status = manga.StatusDropped
+		result, err := Reconcile(integration, []*manga.Manga{mantiumMangas[0], &dropped}, nil, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Added) != 0 {
+			t.Fatalf("expected no manga to be added, got %v", result.Added)
+		}
+	})
+	t.Run("Should not add nor remove the other Mantium mangas", func(t *testing.T) {
+		integration := newFakeIntegration()
+		otherMangas := []*manga.Manga{{Name: "Dandadan", URL: "https://mangadex.org/title/dandadan", Source: "mangadex"}}
+		result, err := Reconcile(integration, mantiumMangas, otherMangas, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Extra) != 0 || len(integration.removed) != 0 {
+			t.Fatalf("expected no manga to be extra or removed, got %v and %v", result.Extra, integration.removed)
+		}
+		if len(result.Added) != 1 {
+			t.Fatalf("expected 1 manga to be added, got %v", result.Added)
+		}
+	})
+	t.Run("Should not remove mangas if the integration can't detect the extra mangas", func(t *testing.T) {
+		integration := &noExtraIntegration{newFakeIntegration()}
+		result, err := Reconcile(integration, mantiumMangas, nil, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Extra) != 0 || len(integration.removed) != 0 {
+			t.Fatalf("expected no manga to be extra or removed, got %v and %v", result.Extra, integration.removed)
+		}
+	})
+	t.Run("Should not remove mangas if there are no supported Mantium mangas", func(t *testing.T) {
+		integration := newFakeIntegration()
+		_, err := Reconcile(integration, mantiumMangas[3:], nil, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(integration.removed) != 0 {
+			t.Fatalf("expected no manga to be removed, got %v", integration.removed)
+		}
+	})
+}
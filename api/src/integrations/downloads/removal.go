@@ -0,0 +1,161 @@
+package downloads
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/diogovalentte/mantium/api/src/config"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// Removal is an action done with a manga in a download integration by its removal policy.
+type Removal struct {
+	Integration string `json:"integration"`
+	Event       string `json:"event"`
+	Action      string `json:"action"`
+	Manga       *Manga `json:"manga"`
+	// Error is the error while doing the action, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// StatusRemovalEvent returns the removal event of a multimanga status,
+// or an empty string if the status has no event.
+func StatusRemovalEvent(status manga.Status) string {
+	switch status {
+	case manga.StatusCompleted:
+		return config.RemovalEventComplete
+	case manga.StatusDropped:
+		return config.RemovalEventDrop
+	}
+
+	return ""
+}
+
+// ApplyRemovalPolicy does the action of the event in the integration's removal policy with the
+// integration mangas of the Mantium mangas, usually a multimanga's mangas. The integration mangas
+// of keepMangas are not changed, like the other mangas of a multimanga when one is removed.
+// If dryRun is true, the actions are only returned. If an action fails, the error is
+// set in the removal and it continues with the next manga.
+func ApplyRemovalPolicy(integration DownloadIntegration, event string, mangas, keepMangas []*manga.Manga, dryRun bool) ([]*Removal, error) {
+	errorContext := fmt.Sprintf("error while applying the %s removal policy of event '%s'", integration.Name(), event)
+
+	removals := []*Removal{}
+	action := integration.RemovalPolicy()[event]
+	if action == "" {
+		return removals, nil
+	}
+
+	keys, err := getMangasKeys(integration, mangas)
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+	keepKeys, err := getMangasKeys(integration, keepMangas)
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+	if len(keys) == 0 {
+		return removals, nil
+	}
+
+	integrationMangas, err := integration.GetStatus()
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+	for _, m := range integrationMangas {
+		if keys[m.Key] && !keepKeys[m.Key] {
+			removals = append(removals, &Removal{Integration: integration.Name(), Event: event, Action: action, Manga: m})
+		}
+	}
+	doRemovals(integration, removals, dryRun)
+
+	return removals, nil
+}
+
+// Cleanup applies the integration's removal policy to Mantium's whole library. The "delete"
+// action is done with the integration mangas that are not in Mantium, like mangas deleted
+// in Mantium, and the "drop" and "complete" actions with the mangas of dropped and completed
+// multimangas. The mangas must have their multimanga status. The integration mangas that are
+// also of other multimangas, like mangas with the same name in Kaizoku, are not changed.
+// To not clear the integration by mistake, nothing is done if there are no Mantium mangas
+// the integration supports. If dryRun is true, the actions are only returned.
+func Cleanup(integration DownloadIntegration, mangas []*manga.Manga, dryRun bool) ([]*Removal, error) {
+	errorContext := fmt.Sprintf("error while cleaning up %s", integration.Name())
+
+	policy := integration.RemovalPolicy()
+	supportedSources := integration.SupportedSources()
+	keepKeys := map[string]bool{}
+	events := map[string]string{}
+	for _, m := range mangas {
+		if m.Source == manga.CustomMangaSource || !slices.Contains(supportedSources, m.Source) {
+			continue
+		}
+		key, err := integration.GetMangaKey(m)
+		if err != nil {
+			return nil, util.AddErrorContext(errorContext, err)
+		}
+
+		event := StatusRemovalEvent(m.Status)
+		if event == "" || policy[event] == "" {
+			keepKeys[key] = true
+			continue
+		}
+		if _, ok := events[key]; !ok {
+			events[key] = event
+		}
+	}
+	if len(keepKeys) == 0 && len(events) == 0 {
+		return []*Removal{}, nil
+	}
+
+	integrationMangas, err := integration.GetStatus()
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+	removals := []*Removal{}
+	for _, m := range integrationMangas {
+		if keepKeys[m.Key] {
+			continue
+		}
+		event, ok := events[m.Key]
+		if !ok {
+			event = config.RemovalEventDelete
+		}
+		if action := policy[event]; action != "" {
+			removals = append(removals, &Removal{Integration: integration.Name(), Event: event, Action: action, Manga: m})
+		}
+	}
+	doRemovals(integration, removals, dryRun)
+
+	return removals, nil
+}
+
+func doRemovals(integration DownloadIntegration, removals []*Removal, dryRun bool) {
+	if dryRun {
+		return
+	}
+	for _, removal := range removals {
+		err := integration.RemoveManga(removal.Manga, removal.Action)
+		if err != nil {
+			removal.Error = err.Error()
+		}
+	}
+}
+
+// getMangasKeys returns the keys of the mangas the integration supports.
+func getMangasKeys(integration DownloadIntegration, mangas []*manga.Manga) (map[string]bool, error) {
+	supportedSources := integration.SupportedSources()
+	keys := map[string]bool{}
+	for _, m := range mangas {
+		if m.Source == manga.CustomMangaSource || !slices.Contains(supportedSources, m.Source) {
+			continue
+		}
+		key, err := integration.GetMangaKey(m)
+		if err != nil {
+			return nil, err
+		}
+		keys[key] = true
+	}
+
+	return keys, nil
+}
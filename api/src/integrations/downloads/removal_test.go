@@ -0,0 +1,128 @@
+package downloads
+
+import (
+	"testing"
+
+	"github.com/diogovalentte/mantium/api/src/config"
+	"github.com/diogovalentte/mantium/api/src/manga"
+)
+
+func TestApplyRemovalPolicy(t *testing.T) {
+	berserk := &manga.Manga{Name: "Berserk", URL: "https://mangadex.org/title/berserk", Source: "mangadex"}
+
+	t.Run("Should do the event action with the mangas", func(t *testing.T) {
+		integration := newFakeIntegration()
+		integration.policy = config.RemovalPolicy{config.RemovalEventDelete: config.RemovalActionDeleteFiles}
+		removals, err := ApplyRemovalPolicy(integration, config.RemovalEventDelete, []*manga.Manga{berserk}, nil, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(removals) != 1 || integration.actions["1"] != config.RemovalActionDeleteFiles {
+			t.Fatalf("expected Berserk files to be deleted, got %v", integration.actions)
+		}
+	})
+	t.Run("Should do nothing if the event has no action", func(t *testing.T) {
+		integration := newFakeIntegration()
+		integration.policy = config.RemovalPolicy{config.RemovalEventDelete: config.RemovalActionRemove}
+		removals, err := ApplyRemovalPolicy(integration, config.RemovalEventDrop, []*manga.Manga{berserk}, nil, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(removals) != 0 || len(integration.removed) != 0 {
+			t.Fatalf("expected no manga to be removed, got %v", integration.removed)
+		}
+	})
+	t.Run("Should keep the mangas of keepMangas", func(t *testing.T) {
+		integration := newFakeIntegration()
+		integration.policy = config.RemovalPolicy{config.RemovalEventDelete: config.RemovalActionRemove}
+		removals, err := ApplyRemovalPolicy(integration, config.RemovalEventDelete, []*manga.Manga{berserk}, []*manga.Manga{berserk}, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(removals) != 0 {
+			t.Fatalf("expected no manga to be removed, got %v", removals)
+		}
+	})
+	t.Run("Should set the error of unsupported actions", func(t *testing.T) {
+		integration := newFakeIntegration()
+		integration.policy = config.RemovalPolicy{config.RemovalEventDelete: config.RemovalActionUnmonitor}
+		removals, err := ApplyRemovalPolicy(integration, config.RemovalEventDelete, []*manga.Manga{berserk}, nil, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(removals) != 1 || removals[0].Error == "" {
+			t.Fatalf("expected the removal to have an error, got %v", removals)
+		}
+	})
+}
+
+func TestCleanup(t *testing.T) {
+	newIntegration := func() *fakeIntegration {
+		integration := newFakeIntegration()
+		integration.mangas = append(integration.mangas, &Manga{ID: "3", Title: "Chainsaw Man", Key: "https://comick.io/comic/chainsaw-man"})
+		integration.policy = config.RemovalPolicy{
+			config.RemovalEventDelete: config.RemovalActionDeleteFiles,
+			config.RemovalEventDrop:   config.RemovalActionRemove,
+		}
+		return integration
+	}
+	mangas := []*manga.Manga{
+		{Name: "Berserk", URL: "https://mangadex.org/title/berserk", Source: "mangadex", Status: 4},
+		{Name: "Chainsaw Man", URL: "https://comick.io/comic/chainsaw-man", Source: "comick", Status: 2},
+	}
+
+	t.Run("Should only return the actions in dry-run mode", func(t *testing.T) {
+		integration := newIntegration()
+		removals, err := Cleanup(integration, mangas, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(removals) != 2 {
+			t.Fatalf("expected 2 removals, got %v", removals)
+		}
+		if len(integration.removed) != 0 {
+			t.Fatalf("expected no manga to be removed in dry-run mode, got %v", integration.removed)
+		}
+	})
+	t.Run("Should apply the policy of each manga", func(t *testing.T) {
+		integration := newIntegration()
+		_, err := Cleanup(integration, mangas, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := map[string]string{"1": config.RemovalActionRemove, "2": config.RemovalActionDeleteFiles}
+		if len(integration.actions) != len(expected) {
+			t.Fatalf("expected actions %v, got %v", expected, integration.actions)
+		}
+		for id, action := range expected {
+			if integration.actions[id] != action {
+				t.Fatalf("expected actions %v, got %v", expected, integration.actions)
+			}
+		}
+	})
+	t.Run("Should keep mangas also of multimangas without an action", func(t *testing.T) {
+		integration := newIntegration()
+		reading := *mangas[0]
+		reading.Status = 1
+		_, err := Cleanup(integration, append([]*manga.Manga{&reading}, mangas...), false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := integration.actions["1"]; ok {
+			t.Fatalf("expected Berserk to be kept, got %v", integration.actions)
+		}
+	})
+	t.Run("Should not remove anything without supported mangas", func(t *testing.T) {
+		integration := newIntegration()
+		custom := []*manga.Manga{{Name: "Custom", URL: manga.CustomMangaURLPrefix + "/custom", Source: manga.CustomMangaSource, Status: 1}}
+		for _, library := range [][]*manga.Manga{{}, custom} {
+			removals, err := Cleanup(integration, library, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(removals) != 0 || len(integration.actions) != 0 {
+				t.Fatalf("expected no removals, got %v", removals)
+			}
+		}
+	})
+}
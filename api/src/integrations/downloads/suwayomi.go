@@ -0,0 +1,82 @@
+package downloads
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/diogovalentte/mantium/api/src/config"
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/integrations/suwayomi"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// Suwayomi is the Suwayomi download integration.
+// The mangas are matched by their source and URL in the source.
+type Suwayomi struct {
+	Suwayomi *suwayomi.Suwayomi
+	Policy   config.RemovalPolicy
+}
+
+func (s *Suwayomi) Name() string {
+	return "Suwayomi"
+}
+
+func (s *Suwayomi) SupportedSources() []string {
+	return suwayomi.SupportedSources()
+}
+
+// AddManga adds the manga to the library. All its chapters are
+// enqueued to download if set in the dashboard configs.
+func (s *Suwayomi) AddManga(m *manga.Manga) error {
+	return s.Suwayomi.AddManga(m, config.GlobalConfigs.DashboardConfigs.Integrations.EnqueueAllSuwayomiChaptersToDownload)
+}
+
+func (s *Suwayomi) RemoveManga(m *Manga, action string) error {
+	errorContext := fmt.Sprintf("(suwayomi) error while removing manga '%s' (action: %s)", m.Title, action)
+	if action != config.RemovalActionRemove && action != config.RemovalActionDeleteFiles {
+		return util.AddErrorContext(errorContext, errordefs.ErrRemovalActionNotSupported)
+	}
+	id, err := strconv.Atoi(m.ID)
+	if err != nil {
+		return util.AddErrorContext(errorContext, fmt.Errorf("invalid ID '%s'", m.ID))
+	}
+
+	return s.Suwayomi.RemoveManga(id, action == config.RemovalActionDeleteFiles)
+}
+
+func (s *Suwayomi) RemovalPolicy() config.RemovalPolicy {
+	return s.Policy
+}
+
+func (s *Suwayomi) EnqueueChapter(m *manga.Manga, chapter *manga.Chapter) error {
+	return s.Suwayomi.EnqueueChapterDownload(m, chapter.URL)
+}
+
+func (s *Suwayomi) GetStatus() ([]*Manga, error) {
+	libraryMangas, err := s.Suwayomi.GetLibraryMangas()
+	if err != nil {
+		return nil, err
+	}
+
+	mangas := make([]*Manga, 0, len(libraryMangas))
+	for _, m := range libraryMangas {
+		var source string
+		if m.Source != nil {
+			source = m.Source.DisplayName
+		}
+		mangas = append(mangas, &Manga{
+			ID:     strconv.Itoa(m.ID),
+			Title:  m.Title,
+			URL:    m.RealURL,
+			Source: source,
+			Key:    m.Key(),
+		})
+	}
+
+	return mangas, nil
+}
+
+func (s *Suwayomi) GetMangaKey(m *manga.Manga) (string, error) {
+	return s.Suwayomi.GetMangaKey(m)
+}
@@ -0,0 +1,73 @@
+package downloads
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/diogovalentte/mantium/api/src/config"
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/integrations/tranga"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// Tranga is the Tranga download integration.
+// The mangas are matched by the URL of their monitor jobs.
+type Tranga struct {
+	Tranga *tranga.Tranga
+	Policy config.RemovalPolicy
+}
+
+func (t *Tranga) Name() string {
+	return "Tranga"
+}
+
+func (t *Tranga) SupportedSources() []string {
+	return tranga.SupportedSources()
+}
+
+func (t *Tranga) AddManga(m *manga.Manga) error {
+	return t.Tranga.AddManga(m)
+}
+
+// RemoveManga removes the manga's monitor job. Tranga only has the monitor
+// jobs, so unmonitoring and removing the manga are the same.
+func (t *Tranga) RemoveManga(m *Manga, action string) error {
+	if action != config.RemovalActionUnmonitor && action != config.RemovalActionRemove {
+		return util.AddErrorContext(fmt.Sprintf("(tranga) error while removing manga '%s' (action: %s)", m.Title, action), errordefs.ErrRemovalActionNotSupported)
+	}
+
+	return t.Tranga.RemoveJob(m.ID)
+}
+
+func (t *Tranga) RemovalPolicy() config.RemovalPolicy {
+	return t.Policy
+}
+
+// EnqueueChapter starts the manga's monitor job, which downloads all new chapters.
+func (t *Tranga) EnqueueChapter(m *manga.Manga, _ *manga.Chapter) error {
+	return t.Tranga.StartJob(m)
+}
+
+func (t *Tranga) GetStatus() ([]*Manga, error) {
+	jobs, err := t.Tranga.GetMonitorJobs()
+	if err != nil {
+		return nil, err
+	}
+
+	mangas := make([]*Manga, 0, len(jobs))
+	for _, job := range jobs {
+		mangas = append(mangas, &Manga{
+			ID:    job.ID,
+			Title: job.Manga.SortName,
+			URL:   job.Manga.WebSiteURL,
+			Key:   strings.TrimSuffix(job.Manga.WebSiteURL, "/"),
+		})
+	}
+
+	return mangas, nil
+}
+
+func (t *Tranga) GetMangaKey(m *manga.Manga) (string, error) {
+	return strings.TrimSuffix(m.URL, "/"), nil
+}
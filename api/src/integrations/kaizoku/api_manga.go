@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
+	"slices"
 
 	"github.com/diogovalentte/mantium/api/src/manga"
 	"github.com/diogovalentte/mantium/api/src/sources"
@@ -255,32 +257,34 @@ type getMangasResponse struct {
 	} `json:"result"`
 }
 
+// kaizokuSources maps the Mantium sources to the Kaizoku sources.
+var kaizokuSources = map[string]string{
+	"mangadex": "MangaDex",
+	"comick":   "ComicK",
+	"mangahub": "MangaHub",
+	"rawkuma":  "RawKuma",
+	"klmanga":  "KLManga",
+	"jmanga":   "JManga",
+}
+
+// SupportedSources returns the Mantium sources that can be added to Kaizoku.
+func SupportedSources() []string {
+	return slices.Sorted(maps.Keys(kaizokuSources))
+}
+
 func (k *Kaizoku) getKaizokuSource(source string) (string, error) {
 	errorContext := "error while getting Kaizoku source"
-	kaizokuSources, err := k.GetSources()
+	instanceSources, err := k.GetSources()
 	if err != nil {
 		return "", util.AddErrorContext(errorContext, err)
 	}
 
-	var returnSource string
-	switch source {
-	case "mangadex":
-		returnSource = "MangaDex"
-	case "comick":
-		returnSource = "ComicK"
-	case "mangahub":
-		returnSource = "MangaHub"
-	case "rawkuma":
-		returnSource = "RawKuma"
-	case "klmanga":
-		returnSource = "KLManga"
-	case "jmanga":
-		returnSource = "JManga"
-	default:
+	returnSource, ok := kaizokuSources[source]
+	if !ok {
 		return "", util.AddErrorContext(errorContext, fmt.Errorf("unknown/not implemented source: %s", source))
 	}
 
-	for _, s := range kaizokuSources {
+	for _, s := range instanceSources {
 		if s == returnSource {
 			return returnSource, nil
 		}
@@ -0,0 +1,242 @@
+package kavita
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+func (k *Kavita) request(method, path string, retBody any) (*http.Response, error) {
+	errorContext := "error while making '%s' request to '%s'"
+
+	err := k.authenticate()
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(errorContext, method, path), err)
+	}
+
+	resp, err := k.do(method, path, retBody)
+	if util.ErrorContains(err, fmt.Sprintf("(%d)", http.StatusUnauthorized)) {
+		// The token expired
+		k.setToken("")
+		err = k.authenticate()
+		if err == nil {
+			resp, err = k.do(method, path, retBody)
+		}
+	}
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(errorContext, method, path), err)
+	}
+
+	return resp, nil
+}
+
+func (k *Kavita) do(method, path string, retBody any) (*http.Response, error) {
+	req, err := http.NewRequest(method, k.Address+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token := k.getToken(); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := k.c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("non-200 status code -> (%d). Body: %s", resp.StatusCode, string(body))
+	}
+
+	if retBody != nil {
+		body, _ := io.ReadAll(resp.Body)
+		if err = json.Unmarshal(body, retBody); err != nil {
+			return nil, fmt.Errorf("error decoding request body response. Body: %s", string(body))
+		}
+	}
+
+	return resp, nil
+}
+
+// authenticate gets the token used in the requests using the API key.
+func (k *Kavita) authenticate() error {
+	k.authMu.Lock()
+	defer k.authMu.Unlock()
+	if k.getToken() != "" {
+		return nil
+	}
+
+	var user struct {
+		Token string `json:"token"`
+	}
+	_, err := k.do(http.MethodPost, "/api/Plugin/authenticate?pluginName=Mantium&apiKey="+url.QueryEscape(k.APIKey), &user)
+	if err != nil {
+		return util.AddErrorContext("(kavita) error while authenticating", err)
+	}
+	if user.Token == "" {
+		return fmt.Errorf("(kavita) error while authenticating: empty token")
+	}
+	k.setToken(user.Token)
+
+	return nil
+}
+
+func (k *Kavita) GetLibraries() ([]*Library, error) {
+	var libraries []*Library
+	_, err := k.request(http.MethodGet, "/api/Library/libraries", &libraries)
+	if err != nil {
+		return nil, util.AddErrorContext("(kavita) error while getting libraries", err)
+	}
+
+	return libraries, nil
+}
+
+// ScanLibrary starts a scan of the library, so Kavita adds the newly downloaded chapters.
+func (k *Kavita) ScanLibrary(libraryID int) error {
+	_, err := k.request(http.MethodPost, fmt.Sprintf("/api/Library/scan?libraryId=%d", libraryID), nil)
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf("(kavita) error while scanning library '%d'", libraryID), err)
+	}
+
+	return nil
+}
+
+func (k *Kavita) SearchSeries(query string) ([]*Series, error) {
+	var result SearchResult
+	_, err := k.request(http.MethodGet, "/api/Search/search?queryString="+url.QueryEscape(query), &result)
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf("(kavita) error while searching series '%s'", query), err)
+	}
+
+	return result.Series, nil
+}
+
+// FindSeries returns the series of the mangas. The mangas are the same manga
+// from different sources, like a multimanga's mangas. A series matches if its
+// name, original name, or localized name is a manga name, ignoring case,
+// spaces, and punctuation.
+func (k *Kavita) FindSeries(mangas []*manga.Manga) (*Series, error) {
+	errorContext := "(kavita) error while finding series of manga '%s'"
+	if len(mangas) == 0 {
+		return nil, errordefs.ErrSeriesNotFound
+	}
+
+	names := map[string]bool{}
+	for _, m := range mangas {
+		names[util.NormalizeName(m.Name)] = true
+	}
+
+	searched := map[string]bool{}
+	for _, m := range mangas {
+		if searched[m.Name] {
+			continue
+		}
+		searched[m.Name] = true
+
+		seriesList, err := k.SearchSeries(m.Name)
+		if err != nil {
+			return nil, util.AddErrorContext(fmt.Sprintf(errorContext, m.Name), err)
+		}
+		for _, series := range seriesList {
+			if names[util.NormalizeName(series.Name)] || names[util.NormalizeName(series.OriginalName)] || names[util.NormalizeName(series.LocalizedName)] {
+				return series, nil
+			}
+		}
+	}
+
+	return nil, util.AddErrorContext(fmt.Sprintf(errorContext, mangas[0].Name), errordefs.ErrSeriesNotFound)
+}
+
+func (k *Kavita) GetVolumes(seriesID int) ([]*Volume, error) {
+	var volumes []*Volume
+	_, err := k.request(http.MethodGet, fmt.Sprintf("/api/Series/volumes?seriesId=%d", seriesID), &volumes)
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf("(kavita) error while getting volumes of series '%d'", seriesID), err)
+	}
+
+	return volumes, nil
+}
+
+// ScanMangasLibraries scans the libraries of the mangas' series.
+// If a manga's series is not found, like when it's a new manga, all libraries are scanned.
+func (k *Kavita) ScanMangasLibraries(mangas []*manga.Manga) error {
+	errorContext := "(kavita) error while scanning the mangas libraries"
+
+	libraryIDs := []int{}
+	scanAll := false
+	for _, m := range mangas {
+		series, err := k.FindSeries([]*manga.Manga{m})
+		if err != nil {
+			if util.ErrorContains(err, errordefs.ErrSeriesNotFound.Error()) {
+				scanAll = true
+				break
+			}
+			return util.AddErrorContext(errorContext, err)
+		}
+		if !slices.Contains(libraryIDs, series.LibraryID) {
+			libraryIDs = append(libraryIDs, series.LibraryID)
+		}
+	}
+
+	if scanAll {
+		libraries, err := k.GetLibraries()
+		if err != nil {
+			return util.AddErrorContext(errorContext, err)
+		}
+		libraryIDs = []int{}
+		for _, library := range libraries {
+			libraryIDs = append(libraryIDs, library.ID)
+		}
+	}
+
+	for _, libraryID := range libraryIDs {
+		err := k.ScanLibrary(libraryID)
+		if err != nil {
+			return util.AddErrorContext(errorContext, err)
+		}
+	}
+
+	return nil
+}
+
+// GetLastReadChapter returns the number of the last read chapter of the mangas' series.
+// The mangas are the same manga from different sources, like a multimanga's mangas.
+// A chapter is read if all its pages were read. Specials and volumes without
+// chapters are ignored. It returns an empty string if no chapter was read.
+func (k *Kavita) GetLastReadChapter(mangas []*manga.Manga) (string, error) {
+	series, err := k.FindSeries(mangas)
+	if err != nil {
+		return "", err
+	}
+	volumes, err := k.GetVolumes(series.SeriesID)
+	if err != nil {
+		return "", err
+	}
+
+	lastRead := -1.0
+	for _, volume := range volumes {
+		for _, chapter := range volume.Chapters {
+			// Kavita uses negative numbers for volumes without chapters
+			if chapter.IsSpecial || chapter.MaxNumber < 0 || chapter.Pages == 0 || chapter.PagesRead < chapter.Pages {
+				continue
+			}
+			lastRead = max(lastRead, chapter.MaxNumber)
+		}
+	}
+	if lastRead < 0 {
+		return "", nil
+	}
+
+	return strconv.FormatFloat(lastRead, 'f', -1, 64), nil
+}
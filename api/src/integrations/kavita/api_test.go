@@ -0,0 +1,161 @@
+package kavita
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/manga"
+)
+
+// fakeKavita is a fake Kavita server with a library and two series.
+type fakeKavita struct {
+	authentications  int
+	scannedLibraries []string
+	// expireToken makes the next authenticated request return 401
+	expireToken bool
+}
+
+func (f *fakeKavita) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/api/Plugin/authenticate" {
+		if r.Method != http.MethodPost || r.URL.Query().Get("apiKey") != "api-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		f.authentications++
+		writeJSON(w, map[string]any{"username": "admin", "token": "token"})
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer token" || f.expireToken {
+		f.expireToken = false
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/api/Library/libraries":
+		writeJSON(w, []map[string]any{{"id": 1, "name": "Manga"}, {"id": 2, "name": "Comics"}})
+	case r.Method == http.MethodPost && r.URL.Path == "/api/Library/scan":
+		f.scannedLibraries = append(f.scannedLibraries, r.URL.Query().Get("libraryId"))
+	case r.Method == http.MethodGet && r.URL.Path == "/api/Search/search":
+		series := []map[string]any{}
+		query := strings.ToLower(r.URL.Query().Get("queryString"))
+		if strings.Contains(query, "berserk") {
+			series = append(series, map[string]any{"seriesId": 10, "libraryId": 1, "name": "Berserk (Deluxe)", "originalName": "ベルセルク", "localizedName": "Berserk"})
+		}
+		writeJSON(w, map[string]any{"series": series})
+	case r.Method == http.MethodGet && r.URL.Path == "/api/Series/volumes":
+		writeJSON(w, []map[string]any{
+			{"id": 1, "name": "1", "chapters": []map[string]any{
+				{"id": 1, "range": "1", "minNumber": 1, "maxNumber": 1, "pages": 20, "pagesRead": 20},
+				{"id": 2, "range": "2-3", "minNumber": 2, "maxNumber": 3, "pages": 40, "pagesRead": 40},
+				{"id": 3, "range": "4", "minNumber": 4, "maxNumber": 4, "pages": 20, "pagesRead": 5},
+			}},
+			{"id": 2, "name": "2", "chapters": []map[string]any{
+				{"id": 4, "range": "2", "minNumber": -100000, "maxNumber": -100000, "pages": 200, "pagesRead": 200},
+			}},
+			{"id": 3, "name": "Specials", "chapters": []map[string]any{
+				{"id": 5, "range": "Extra", "minNumber": 100, "maxNumber": 100, "pages": 10, "pagesRead": 10, "isSpecial": true},
+			}},
+		})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func newTestKavita(t *testing.T) (*Kavita, *fakeKavita) {
+	fake := &fakeKavita{}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	return &Kavita{c: server.Client(), Address: server.URL, APIKey: "api-key"}, fake
+}
+
+func TestFindSeries(t *testing.T) {
+	k, _ := newTestKavita(t)
+
+	t.Run("Should match a series by its localized name", func(t *testing.T) {
+		series, err := k.FindSeries([]*manga.Manga{{Name: "berserk"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if series.SeriesID != 10 {
+			t.Fatalf("expected series 10, got %d", series.SeriesID)
+		}
+	})
+	t.Run("Should not find a series", func(t *testing.T) {
+		_, err := k.FindSeries([]*manga.Manga{{Name: "Berserk of Gluttony"}})
+		if err == nil || !strings.Contains(err.Error(), errordefs.ErrSeriesNotFound.Error()) {
+			t.Fatalf("expected series not found error, got %v", err)
+		}
+	})
+}
+
+func TestScanMangasLibraries(t *testing.T) {
+	t.Run("Should scan the libraries of the series", func(t *testing.T) {
+		k, fake := newTestKavita(t)
+		err := k.ScanMangasLibraries([]*manga.Manga{{Name: "Berserk"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(fake.scannedLibraries) != 1 || fake.scannedLibraries[0] != "1" {
+			t.Fatalf("expected only library 1 to be scanned, got %v", fake.scannedLibraries)
+		}
+	})
+	t.Run("Should scan all libraries if a series is not found", func(t *testing.T) {
+		k, fake := newTestKavita(t)
+		err := k.ScanMangasLibraries([]*manga.Manga{{Name: "Dandadan"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(fake.scannedLibraries) != 2 {
+			t.Fatalf("expected all libraries to be scanned, got %v", fake.scannedLibraries)
+		}
+	})
+}
+
+func TestGetLastReadChapter(t *testing.T) {
+	k, _ := newTestKavita(t)
+
+	chapter, err := k.GetLastReadChapter([]*manga.Manga{{Name: "Dandadan"}, {Name: "Berserk"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chapter != "3" {
+		t.Fatalf("expected chapter 3, got '%s'", chapter)
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	t.Run("Should authenticate again when the token expires", func(t *testing.T) {
+		k, fake := newTestKavita(t)
+		_, err := k.GetLibraries()
+		if err != nil {
+			t.Fatal(err)
+		}
+		fake.expireToken = true
+		_, err = k.GetLibraries()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fake.authentications != 2 {
+			t.Fatalf("expected 2 authentications, got %d", fake.authentications)
+		}
+	})
+	t.Run("Should not authenticate with an invalid API key", func(t *testing.T) {
+		k, _ := newTestKavita(t)
+		k.APIKey = "invalid"
+		_, err := k.GetLibraries()
+		if err == nil || !strings.Contains(err.Error(), "authenticating") {
+			t.Fatalf("expected authentication error, got %v", err)
+		}
+	})
+}
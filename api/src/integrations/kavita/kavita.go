@@ -0,0 +1,38 @@
+package kavita
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/diogovalentte/mantium/api/src/config"
+)
+
+type Kavita struct {
+	c       *http.Client
+	Address string
+	APIKey  string
+	// token is the JWT token returned by the API key authentication
+	token string
+	// tokenMu protects the token, as the requests can be made in parallel
+	tokenMu sync.Mutex
+	// authMu prevents multiple authentications at the same time
+	authMu sync.Mutex
+}
+
+func (k *Kavita) Init() {
+	k.c = &http.Client{}
+	k.Address = config.GlobalConfigs.Kavita.Address
+	k.APIKey = config.GlobalConfigs.Kavita.APIKey
+}
+
+func (k *Kavita) getToken() string {
+	k.tokenMu.Lock()
+	defer k.tokenMu.Unlock()
+	return k.token
+}
+
+func (k *Kavita) setToken(token string) {
+	k.tokenMu.Lock()
+	defer k.tokenMu.Unlock()
+	k.token = token
+}
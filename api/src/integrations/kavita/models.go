@@ -0,0 +1,35 @@
+package kavita
+
+type Library struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type SearchResult struct {
+	Series []*Series `json:"series"`
+}
+
+type Series struct {
+	SeriesID      int    `json:"seriesId"`
+	LibraryID     int    `json:"libraryId"`
+	Name          string `json:"name"`
+	OriginalName  string `json:"originalName"`
+	LocalizedName string `json:"localizedName"`
+	LibraryName   string `json:"libraryName"`
+}
+
+type Volume struct {
+	ID       int        `json:"id"`
+	Name     string     `json:"name"`
+	Chapters []*Chapter `json:"chapters"`
+}
+
+type Chapter struct {
+	ID        int     `json:"id"`
+	Range     string  `json:"range"`
+	MinNumber float64 `json:"minNumber"`
+	MaxNumber float64 `json:"maxNumber"`
+	Pages     int     `json:"pages"`
+	PagesRead int     `json:"pagesRead"`
+	IsSpecial bool    `json:"isSpecial"`
+}
@@ -0,0 +1,211 @@
+package komga
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+func (k *Komga) request(method, path string, retBody any) (*http.Response, error) {
+	errorContext := "error while making '%s' request to '%s'"
+
+	req, err := http.NewRequest(method, k.Address+path, nil)
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(errorContext, method, path), err)
+	}
+	if k.APIKey != "" {
+		req.Header.Set("X-API-Key", k.APIKey)
+	} else {
+		req.SetBasicAuth(k.Username, k.Password)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := k.c.Do(req)
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(errorContext, method, path), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, util.AddErrorContext(fmt.Sprintf(errorContext, method, path), fmt.Errorf("non-200 status code -> (%d). Body: %s", resp.StatusCode, string(body)))
+	}
+
+	if retBody != nil {
+		body, _ := io.ReadAll(resp.Body)
+		if err = json.Unmarshal(body, retBody); err != nil {
+			return nil, util.AddErrorContext(fmt.Sprintf(errorContext, method, path), fmt.Errorf("error decoding request body response. Body: %s", string(body)))
+		}
+	}
+
+	return resp, nil
+}
+
+func (k *Komga) GetLibraries() ([]*Library, error) {
+	var libraries []*Library
+	_, err := k.request(http.MethodGet, "/api/v1/libraries", &libraries)
+	if err != nil {
+		return nil, util.AddErrorContext("(komga) error while getting libraries", err)
+	}
+
+	return libraries, nil
+}
+
+// ScanLibrary starts a scan of the library, so Komga adds the newly downloaded chapters.
+func (k *Komga) ScanLibrary(libraryID string) error {
+	_, err := k.request(http.MethodPost, fmt.Sprintf("/api/v1/libraries/%s/scan", url.PathEscape(libraryID)), nil)
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf("(komga) error while scanning library '%s'", libraryID), err)
+	}
+
+	return nil
+}
+
+func (k *Komga) SearchSeries(query string) ([]*Series, error) {
+	var page Page[*Series]
+	_, err := k.request(http.MethodGet, "/api/v1/series?size=20&search="+url.QueryEscape(query), &page)
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf("(komga) error while searching series '%s'", query), err)
+	}
+
+	return page.Content, nil
+}
+
+// FindSeries returns the series of the mangas. The mangas are the same manga
+// from different sources, like a multimanga's mangas. A series matches if
+// any of its links is a manga URL or if its name, title, or any alternate
+// title is a manga name, ignoring case, spaces, and punctuation.
+func (k *Komga) FindSeries(mangas []*manga.Manga) (*Series, error) {
+	errorContext := "(komga) error while finding series of manga '%s'"
+	if len(mangas) == 0 {
+		return nil, errordefs.ErrSeriesNotFound
+	}
+
+	names := map[string]bool{}
+	urls := map[string]bool{}
+	for _, m := range mangas {
+		names[util.NormalizeName(m.Name)] = true
+		if m.URL != "" && !strings.HasPrefix(m.URL, manga.CustomMangaURLPrefix) {
+			urls[strings.TrimSuffix(m.URL, "/")] = true
+		}
+	}
+
+	searched := map[string]bool{}
+	for _, m := range mangas {
+		if searched[m.Name] {
+			continue
+		}
+		searched[m.Name] = true
+
+		seriesList, err := k.SearchSeries(m.Name)
+		if err != nil {
+			return nil, util.AddErrorContext(fmt.Sprintf(errorContext, m.Name), err)
+		}
+		for _, series := range seriesList {
+			if seriesMatches(series, names, urls) {
+				return series, nil
+			}
+		}
+	}
+
+	return nil, util.AddErrorContext(fmt.Sprintf(errorContext, mangas[0].Name), errordefs.ErrSeriesNotFound)
+}
+
+func seriesMatches(series *Series, names, urls map[string]bool) bool {
+	for _, link := range series.Metadata.Links {
+		if urls[strings.TrimSuffix(link.URL, "/")] {
+			return true
+		}
+	}
+	if names[util.NormalizeName(series.Name)] || names[util.NormalizeName(series.Metadata.Title)] {
+		return true
+	}
+	for _, title := range series.Metadata.AlternateTitles {
+		if names[util.NormalizeName(title.Title)] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetLastReadBook returns the read book with the highest number of the series, or nil if there is none.
+func (k *Komga) GetLastReadBook(seriesID string) (*Book, error) {
+	var page Page[*Book]
+	_, err := k.request(http.MethodGet, fmt.Sprintf("/api/v1/series/%s/books?read_status=READ&sort=metadata.numberSort,desc&size=1", url.PathEscape(seriesID)), &page)
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf("(komga) error while getting last read book of series '%s'", seriesID), err)
+	}
+	if len(page.Content) == 0 {
+		return nil, nil
+	}
+
+	return page.Content[0], nil
+}
+
+// ScanMangasLibraries scans the libraries of the mangas' series.
+// If a manga's series is not found, like when it's a new manga, all libraries are scanned.
+func (k *Komga) ScanMangasLibraries(mangas []*manga.Manga) error {
+	errorContext := "(komga) error while scanning the mangas libraries"
+
+	libraryIDs := []string{}
+	scanAll := false
+	for _, m := range mangas {
+		series, err := k.FindSeries([]*manga.Manga{m})
+		if err != nil {
+			if util.ErrorContains(err, errordefs.ErrSeriesNotFound.Error()) {
+				scanAll = true
+				break
+			}
+			return util.AddErrorContext(errorContext, err)
+		}
+		if !slices.Contains(libraryIDs, series.LibraryID) {
+			libraryIDs = append(libraryIDs, series.LibraryID)
+		}
+	}
+
+	if scanAll {
+		libraries, err := k.GetLibraries()
+		if err != nil {
+			return util.AddErrorContext(errorContext, err)
+		}
+		libraryIDs = []string{}
+		for _, library := range libraries {
+			libraryIDs = append(libraryIDs, library.ID)
+		}
+	}
+
+	for _, libraryID := range libraryIDs {
+		err := k.ScanLibrary(libraryID)
+		if err != nil {
+			return util.AddErrorContext(errorContext, err)
+		}
+	}
+
+	return nil
+}
+
+// GetLastReadChapter returns the number of the last read chapter of the mangas' series.
+// The mangas are the same manga from different sources, like a multimanga's mangas.
+// It returns an empty string if no chapter was read.
+func (k *Komga) GetLastReadChapter(mangas []*manga.Manga) (string, error) {
+	series, err := k.FindSeries(mangas)
+	if err != nil {
+		return "", err
+	}
+	book, err := k.GetLastReadBook(series.ID)
+	if err != nil || book == nil {
+		return "", err
+	}
+
+	return strconv.FormatFloat(book.Metadata.NumberSort, 'f', -1, 64), nil
+}
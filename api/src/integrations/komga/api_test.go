@@ -0,0 +1,158 @@
+package komga
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/manga"
+)
+
+// fakeKomga is a fake Komga server with a library and two series.
+type fakeKomga struct {
+	mu               sync.Mutex
+	scannedLibraries []string
+}
+
+func (f *fakeKomga) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-API-Key") != "api-key" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v1/libraries":
+		writeJSON(w, []map[string]any{{"id": "lib1", "name": "Manga"}, {"id": "lib2", "name": "Comics"}})
+	case r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, "/api/v1/libraries/") && strings.HasSuffix(r.URL.Path, "/scan"):
+		f.mu.Lock()
+		f.scannedLibraries = append(f.scannedLibraries, strings.Split(r.URL.Path, "/")[4])
+		f.mu.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v1/series":
+		series := []map[string]any{}
+		search := strings.ToLower(r.URL.Query().Get("search"))
+		if strings.Contains(search, "berserk") {
+			series = append(series, map[string]any{
+				"id": "s1", "libraryId": "lib1", "name": "Berserk (2020)",
+				"metadata": map[string]any{"title": "Berserk (2020)", "links": []map[string]any{{"label": "MangaDex", "url": "https://mangadex.org/title/berserk/"}}},
+			})
+		}
+		if strings.Contains(search, "chainsaw") {
+			series = append(series, map[string]any{
+				"id": "s2", "libraryId": "lib1", "name": "Chainsaw-Man",
+				"metadata": map[string]any{"title": "Chainsaw-Man"},
+			})
+		}
+		writeJSON(w, map[string]any{"content": series, "totalElements": len(series), "last": true})
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v1/series/s1/books":
+		if r.URL.Query().Get("read_status") != "READ" || r.URL.Query().Get("sort") != "metadata.numberSort,desc" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, map[string]any{"content": []map[string]any{{"id": "b1", "seriesId": "s1", "name": "Berserk Ch. 10.5", "metadata": map[string]any{"number": "10.5", "numberSort": 10.5}}}})
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v1/series/s2/books":
+		writeJSON(w, map[string]any{"content": []map[string]any{}})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func newTestKomga(t *testing.T) (*Komga, *fakeKomga) {
+	fake := &fakeKomga{}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	return &Komga{c: server.Client(), Address: server.URL, APIKey: "api-key"}, fake
+}
+
+func TestFindSeries(t *testing.T) {
+	k, _ := newTestKomga(t)
+
+	t.Run("Should match a series by its links", func(t *testing.T) {
+		series, err := k.FindSeries([]*manga.Manga{{Name: "Berserk", URL: "https://mangadex.org/title/berserk"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if series.ID != "s1" {
+			t.Fatalf("expected series s1, got %s", series.ID)
+		}
+	})
+	t.Run("Should match a series by its name", func(t *testing.T) {
+		series, err := k.FindSeries([]*manga.Manga{{Name: "Chainsaw Man", URL: "https://comick.io/comic/chainsaw-man"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if series.ID != "s2" {
+			t.Fatalf("expected series s2, got %s", series.ID)
+		}
+	})
+	t.Run("Should not find a series", func(t *testing.T) {
+		_, err := k.FindSeries([]*manga.Manga{{Name: "Berserk of Gluttony", URL: "https://mangadex.org/title/gluttony"}})
+		if err == nil || !strings.Contains(err.Error(), errordefs.ErrSeriesNotFound.Error()) {
+			t.Fatalf("expected series not found error, got %v", err)
+		}
+	})
+}
+
+func TestScanMangasLibraries(t *testing.T) {
+	t.Run("Should scan the libraries of the series", func(t *testing.T) {
+		k, fake := newTestKomga(t)
+		err := k.ScanMangasLibraries([]*manga.Manga{{Name: "Berserk (2020)"}, {Name: "Chainsaw Man"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(fake.scannedLibraries) != 1 || fake.scannedLibraries[0] != "lib1" {
+			t.Fatalf("expected only lib1 to be scanned, got %v", fake.scannedLibraries)
+		}
+	})
+	t.Run("Should scan all libraries if a series is not found", func(t *testing.T) {
+		k, fake := newTestKomga(t)
+		err := k.ScanMangasLibraries([]*manga.Manga{{Name: "Chainsaw Man"}, {Name: "Dandadan"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(fake.scannedLibraries) != 2 {
+			t.Fatalf("expected all libraries to be scanned, got %v", fake.scannedLibraries)
+		}
+	})
+}
+
+func TestGetLastReadChapter(t *testing.T) {
+	k, _ := newTestKomga(t)
+
+	chapter, err := k.GetLastReadChapter([]*manga.Manga{{Name: "Berserk", URL: "https://mangadex.org/title/berserk"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chapter != "10.5" {
+		t.Fatalf("expected chapter 10.5, got '%s'", chapter)
+	}
+
+	chapter, err = k.GetLastReadChapter([]*manga.Manga{{Name: "Chainsaw Man"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chapter != "" {
+		t.Fatalf("expected no read chapter, got '%s'", chapter)
+	}
+}
+
+func TestRequestUnauthorized(t *testing.T) {
+	k, _ := newTestKomga(t)
+	k.APIKey = ""
+	k.Username = "user"
+
+	_, err := k.GetLibraries()
+	if err == nil || !strings.Contains(err.Error(), "(401)") {
+		t.Fatalf("expected unauthorized error, got %v", err)
+	}
+}
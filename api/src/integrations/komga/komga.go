@@ -0,0 +1,23 @@
+package komga
+
+import (
+	"net/http"
+
+	"github.com/diogovalentte/mantium/api/src/config"
+)
+
+type Komga struct {
+	c        *http.Client
+	Address  string
+	Username string
+	Password string
+	APIKey   string
+}
+
+func (k *Komga) Init() {
+	k.c = &http.Client{}
+	k.Address = config.GlobalConfigs.Komga.Address
+	k.Username = config.GlobalConfigs.Komga.Username
+	k.Password = config.GlobalConfigs.Komga.Password
+	k.APIKey = config.GlobalConfigs.Komga.APIKey
+}
@@ -0,0 +1,47 @@
+package komga
+
+type Page[T any] struct {
+	Content       []T  `json:"content"`
+	TotalElements int  `json:"totalElements"`
+	Last          bool `json:"last"`
+}
+
+type Library struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type Series struct {
+	ID        string `json:"id"`
+	LibraryID string `json:"libraryId"`
+	Name      string `json:"name"`
+	Metadata  struct {
+		Title           string `json:"title"`
+		AlternateTitles []struct {
+			Label string `json:"label"`
+			Title string `json:"title"`
+		} `json:"alternateTitles"`
+		Links []struct {
+			Label string `json:"label"`
+			URL   string `json:"url"`
+		} `json:"links"`
+	} `json:"metadata"`
+	BooksCount       int `json:"booksCount"`
+	BooksReadCount   int `json:"booksReadCount"`
+	BooksUnreadCount int `json:"booksUnreadCount"`
+}
+
+type Book struct {
+	ID       string `json:"id"`
+	SeriesID string `json:"seriesId"`
+	Name     string `json:"name"`
+	Metadata struct {
+		Title      string  `json:"title"`
+		Number     string  `json:"number"`
+		NumberSort float64 `json:"numberSort"`
+	} `json:"metadata"`
+	ReadProgress *struct {
+		Page      int  `json:"page"`
+		Completed bool `json:"completed"`
+	} `json:"readProgress"`
+}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
+	"time"
 
 	"github.com/AnthonyHewins/gotfy"
 
@@ -13,6 +15,9 @@ import (
 	"github.com/diogovalentte/mantium/api/src/util"
 )
 
+// requestTimeout is the timeout of the requests to the Ntfy server
+const requestTimeout = 30 * time.Second
+
 // GetNtfyPublisher returns a new NtfyPublisher
 func GetNtfyPublisher() (*Publisher, error) {
 	contextError := "could not get Ntfy publisher"
@@ -25,6 +30,7 @@ func GetNtfyPublisher() (*Publisher, error) {
 	}
 
 	customClient := &http.Client{
+		Timeout: requestTimeout,
 		Transport: &customNtfyTransport{
 			ntfyToken: configs.Token,
 		},
@@ -36,6 +42,8 @@ func GetNtfyPublisher() (*Publisher, error) {
 
 	return &Publisher{
 		Publisher: publisher,
+		Server:    server,
+		client:    customClient,
 		Topic:     configs.Topic,
 		Token:     configs.Token,
 	}, nil
@@ -54,6 +62,8 @@ func (t *customNtfyTransport) RoundTrip(req *http.Request) (*http.Response, erro
 // Publisher is a wrapper around gotfy.Publisher
 type Publisher struct {
 	Publisher *gotfy.Publisher
+	Server    *url.URL
+	client    *http.Client
 	Topic     string
 	Token     string
 }
@@ -67,3 +77,30 @@ func (t *Publisher) SendMessage(ctx context.Context, message *gotfy.Message) err
 
 	return nil
 }
+
+// SendMarkdownMessage sends a message with a markdown body to the Ntfy server
+func (t *Publisher) SendMarkdownMessage(ctx context.Context, title, markdown string, clickURL *url.URL) error {
+	contextError := "could not send markdown message to Ntfy"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.Server.JoinPath(t.Topic).String(), strings.NewReader(markdown))
+	if err != nil {
+		return util.AddErrorContext(contextError, err)
+	}
+	req.Header.Set("Title", title)
+	req.Header.Set("Markdown", "yes")
+	if clickURL != nil {
+		req.Header.Set("Click", clickURL.String())
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return util.AddErrorContext(contextError, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return util.AddErrorContext(contextError, fmt.Errorf("non-200 status code -> (%d)", resp.StatusCode))
+	}
+
+	return nil
+}
@@ -5,11 +5,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/diogovalentte/mantium/api/src/errordefs"
 	"github.com/diogovalentte/mantium/api/src/manga"
 	"github.com/diogovalentte/mantium/api/src/util"
 )
@@ -31,15 +34,27 @@ func (s *Suwayomi) baseRequest(reqBody io.Reader, target any) (*http.Response, e
 	}
 	defer resp.Body.Close()
 
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
 		return nil, util.AddErrorContext(errorContext, fmt.Errorf("non-200 status code -> (%d). Body: %s", resp.StatusCode, string(body)))
 	}
 
+	// The GraphQL errors are returned with a 200 status code
+	var errorsResponse struct {
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err = json.Unmarshal(body, &errorsResponse); err == nil && len(errorsResponse.Errors) > 0 {
+		return nil, util.AddErrorContext(errorContext, fmt.Errorf("%s", errorsResponse.Errors[0].Message))
+	}
+
 	if target != nil {
-		err = json.NewDecoder(resp.Body).Decode(target)
+		err = json.Unmarshal(body, target)
 		if err != nil {
-			body, _ := io.ReadAll(resp.Body)
 			return nil, util.AddErrorContext(errorContext, fmt.Errorf("error while decoding response: '%s'. Body: %s", err.Error(), string(body)))
 		}
 	}
@@ -47,10 +62,39 @@ func (s *Suwayomi) baseRequest(reqBody io.Reader, target any) (*http.Response, e
 	return resp, nil
 }
 
-func (s *Suwayomi) fetchSources() ([]*Extension, error) {
+// GetImage returns an image from the Suwayomi instance, like a manga thumbnail.
+func (s *Suwayomi) GetImage(path string) ([]byte, error) {
+	errorContext := "error while getting image '%s'"
+
+	req, err := http.NewRequest("GET", s.Address+path, nil)
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(errorContext, path), err)
+	}
+	req.SetBasicAuth(s.Username, s.Password)
+
+	resp, err := s.c.Do(req)
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(errorContext, path), err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(errorContext, path), err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, util.AddErrorContext(fmt.Sprintf(errorContext, path), fmt.Errorf("non-200 status code -> (%d). Body: %s", resp.StatusCode, string(body)))
+	}
+
+	return body, nil
+}
+
+// FetchSources returns the extensions available in Suwayomi with their sources.
+// Only the installed extensions have sources.
+func (s *Suwayomi) FetchSources() ([]*Extension, error) {
 	errorContext := "error while fetching sources"
 	query := `
-mutation {
+mutation FetchExtensions {
   fetchExtensions(input: {}) {
     extensions {
       source {
@@ -65,7 +109,8 @@ mutation {
 }
 	`
 	payload := map[string]any{
-		"query": query,
+		"query":         query,
+		"operationName": "FetchExtensions",
 	}
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
@@ -83,7 +128,7 @@ mutation {
 
 func (s *Suwayomi) fetchSourceID(sourceName string) (string, error) {
 	errorContext := "error while fetching source ID for source '%s'"
-	sources, err := s.fetchSources()
+	sources, err := s.FetchSources()
 	if err != nil {
 		return "", util.AddErrorContext(fmt.Sprintf(errorContext, sourceName), err)
 	}
@@ -102,11 +147,34 @@ func (s *Suwayomi) fetchSourceID(sourceName string) (string, error) {
 func (s *Suwayomi) fetchSourceManga(sourceID string, m *manga.Manga, page int) (*APIManga, error) {
 	errorContext := "error while fetching manga from source"
 
+	mangas, err := s.FetchSourceMangas(sourceID, m.Name, page)
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+
+	mangaURL, err := s.getSourceMangaURL(m)
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+	for _, manga := range mangas {
+		if manga.URL == mangaURL {
+			return manga, nil
+		}
+	}
+
+	return nil, util.AddErrorContext(errorContext, fmt.Errorf("manga not found"))
+}
+
+// FetchSourceMangas searches the term in the Suwayomi source and returns
+// the mangas found in the page. Suwayomi saves the mangas found.
+func (s *Suwayomi) FetchSourceMangas(sourceID, term string, page int) ([]*APIManga, error) {
+	errorContext := "error while searching '%s' in source '%s'"
+
 	query := `
 		mutation FetchSourceManga($input: FetchSourceMangaInput!) {
 		  fetchSourceManga(input: $input) {
 			mangas {
-			  url,id,inLibrary
+			  url,id,inLibrary,title,thumbnailUrl,realUrl
 			}
 		  }
 		}
@@ -114,7 +182,7 @@ func (s *Suwayomi) fetchSourceManga(sourceID string, m *manga.Manga, page int) (
 
 	variables := map[string]any{
 		"input": map[string]any{
-			"query":  m.Name,
+			"query":  term,
 			"source": sourceID,
 			"type":   "SEARCH",
 			"page":   page,
@@ -128,30 +196,63 @@ func (s *Suwayomi) fetchSourceManga(sourceID string, m *manga.Manga, page int) (
 	}
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
-		return nil, util.AddErrorContext(errorContext, util.AddErrorContext("error while marshalling payload", err))
+		return nil, util.AddErrorContext(fmt.Sprintf(errorContext, term, sourceID), util.AddErrorContext("error while marshalling payload", err))
 	}
 
 	var fetchMangasResponse FetchMangasResponse
 	_, err = s.baseRequest(bytes.NewBuffer(jsonData), &fetchMangasResponse)
 	if err != nil {
-		return nil, util.AddErrorContext(errorContext, err)
+		return nil, util.AddErrorContext(fmt.Sprintf(errorContext, term, sourceID), err)
 	}
 
-	mangaURL, err := s.getSourceMangaURL(m)
+	return fetchMangasResponse.Data.FetchSourceManga.Mangas, nil
+}
+
+// FetchManga fetches the manga from its source and returns it.
+func (s *Suwayomi) FetchManga(mangaID int) (*APIManga, error) {
+	errorContext := "error while fetching manga '%d'"
+
+	payload := map[string]any{
+		"query": `
+mutation FetchManga($id: Int!) {
+  fetchManga(input: {id: $id}) {
+    manga {
+      id,url,inLibrary,title,thumbnailUrl,realUrl
+    }
+  }
+}
+	`,
+		"variables": map[string]any{
+			"id": mangaID,
+		},
+		"operationName": "FetchManga",
+	}
+	jsonData, err := json.Marshal(payload)
 	if err != nil {
-		return nil, util.AddErrorContext(errorContext, err)
+		return nil, util.AddErrorContext(fmt.Sprintf(errorContext, mangaID), util.AddErrorContext("error while marshalling payload", err))
 	}
-	for _, manga := range fetchMangasResponse.Data.FetchSourceManga.Mangas {
-		if manga.URL == mangaURL {
-			return manga, nil
-		}
+
+	var fetchMangaResponse struct {
+		Data struct {
+			FetchManga struct {
+				Manga *APIManga `json:"manga"`
+			} `json:"fetchManga"`
+		} `json:"data"`
+	}
+	_, err = s.baseRequest(bytes.NewBuffer(jsonData), &fetchMangaResponse)
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(errorContext, mangaID), err)
+	}
+	if fetchMangaResponse.Data.FetchManga.Manga == nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(errorContext, mangaID), fmt.Errorf("manga not found"))
 	}
 
-	return nil, util.AddErrorContext(errorContext, fmt.Errorf("manga not found"))
+	return fetchMangaResponse.Data.FetchManga.Manga, nil
 }
 
-func (s *Suwayomi) addManga(mangaID int) error {
-	errorContext := "error while adding manga with ID '%d'"
+// setInLibrary adds the manga to the library or removes it from the library.
+func (s *Suwayomi) setInLibrary(mangaID int, inLibrary bool) error {
+	errorContext := "error while setting manga with ID '%d' in library to '%v'"
 
 	query := `
 		mutation UpdateManga($input: UpdateMangaInput!) {
@@ -167,7 +268,7 @@ func (s *Suwayomi) addManga(mangaID int) error {
 		"input": map[string]any{
 			"id": mangaID,
 			"patch": map[string]any{
-				"inLibrary": true,
+				"inLibrary": inLibrary,
 			},
 		},
 	}
@@ -179,12 +280,12 @@ func (s *Suwayomi) addManga(mangaID int) error {
 	}
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
-		return util.AddErrorContext(fmt.Sprintf(errorContext, mangaID), util.AddErrorContext("error while marshalling payload", err))
+		return util.AddErrorContext(fmt.Sprintf(errorContext, mangaID, inLibrary), util.AddErrorContext("error while marshalling payload", err))
 	}
 
 	_, err = s.baseRequest(bytes.NewBuffer(jsonData), nil)
 	if err != nil {
-		return util.AddErrorContext(fmt.Sprintf(errorContext, mangaID), err)
+		return util.AddErrorContext(fmt.Sprintf(errorContext, mangaID, inLibrary), err)
 	}
 
 	return nil
@@ -211,7 +312,7 @@ func (s *Suwayomi) AddManga(manga *manga.Manga, enqueChapterDownloads bool) erro
 		return nil
 	}
 
-	err = s.addManga(sourceManga.ID)
+	err = s.setInLibrary(sourceManga.ID, true)
 	if err != nil {
 		return util.AddErrorContext(fmt.Sprintf(errorContext, manga.Name, manga.URL), err)
 	}
@@ -246,6 +347,82 @@ func (s *Suwayomi) AddManga(manga *manga.Manga, enqueChapterDownloads bool) erro
 	return nil
 }
 
+// RemoveManga removes the manga from the library, so Suwayomi stops
+// downloading its chapters. If deleteFiles is true, the downloaded chapters are also deleted.
+func (s *Suwayomi) RemoveManga(mangaID int, deleteFiles bool) error {
+	errorContext := "(suwayomi) error while removing manga with ID '%d' (deleteFiles: %v)"
+
+	if deleteFiles {
+		chapters, err := s.GetChapters(mangaID)
+		if err != nil {
+			return util.AddErrorContext(fmt.Sprintf(errorContext, mangaID, deleteFiles), err)
+		}
+		chapterIDs := []int{}
+		for _, chapter := range chapters {
+			if chapter.IsDownloaded {
+				chapterIDs = append(chapterIDs, chapter.ID)
+			}
+		}
+		if len(chapterIDs) > 0 {
+			err = s.DeleteDownloadedChapters(chapterIDs)
+			if err != nil {
+				return util.AddErrorContext(fmt.Sprintf(errorContext, mangaID, deleteFiles), err)
+			}
+		}
+	}
+
+	err := s.setInLibrary(mangaID, false)
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf(errorContext, mangaID, deleteFiles), err)
+	}
+
+	return nil
+}
+
+// GetLibraryMangas returns the mangas in the library.
+func (s *Suwayomi) GetLibraryMangas() ([]*APIManga, error) {
+	errorContext := "(suwayomi) error while getting library mangas"
+
+	query := `
+query LibraryMangas {
+  mangas(condition: {inLibrary: true}) {
+    nodes {
+      id
+      title
+      url
+      realUrl
+      inLibrary
+      source {
+        displayName
+      }
+    }
+  }
+}
+	`
+
+	payload := map[string]any{
+		"query": query,
+	}
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, util.AddErrorContext("error while marshalling payload", err))
+	}
+
+	var mangaResponse struct {
+		Data struct {
+			Mangas struct {
+				Nodes []*APIManga `json:"nodes"`
+			} `json:"mangas"`
+		} `json:"data"`
+	}
+	_, err = s.baseRequest(bytes.NewBuffer(jsonData), &mangaResponse)
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+
+	return mangaResponse.Data.Mangas.Nodes, nil
+}
+
 func (s *Suwayomi) GetLibraryMangaID(m *manga.Manga) (int, error) {
 	errorContext := "error while getting in-library manga ID for manga '%s'"
 
@@ -288,7 +465,7 @@ query AllCategories {
 	}
 
 	if len(mangaResponse.Data.Mangas.Nodes) == 0 {
-		return 0, util.AddErrorContext(fmt.Sprintf(errorContext, m), fmt.Errorf("manga not found in library"))
+		return 0, util.AddErrorContext(fmt.Sprintf(errorContext, m), errordefs.ErrMangaNotInLibrary)
 	} else if len(mangaResponse.Data.Mangas.Nodes) > 1 {
 		return 0, util.AddErrorContext(fmt.Sprintf(errorContext, m), fmt.Errorf("multiple mangas found in library"))
 	}
@@ -307,6 +484,9 @@ query AllCategories {
         isDownloaded
         realUrl
         id
+        name
+        chapterNumber
+        sourceOrder
       }
     }
   }
@@ -343,6 +523,47 @@ query AllCategories {
 	return chaptersResponse.Data.Manga.Chapters.Nodes, nil
 }
 
+// FetchChapters fetches the manga chapters from its source and returns them.
+// Unlike GetChapters, it updates the chapters stored in Suwayomi and
+// returns their upload date.
+func (s *Suwayomi) FetchChapters(mangaID int) ([]*APIChapter, error) {
+	errorContext := "error while fetching chapters for manga '%d'"
+
+	payload := map[string]any{
+		"query": `
+mutation FetchChapters($mangaId: Int!) {
+  fetchChapters(input: {mangaId: $mangaId}) {
+    chapters {
+      isDownloaded,realUrl,id,name,chapterNumber,uploadDate,sourceOrder
+    }
+  }
+}
+	`,
+		"variables": map[string]any{
+			"mangaId": mangaID,
+		},
+		"operationName": "FetchChapters",
+	}
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(errorContext, mangaID), util.AddErrorContext("error while marshalling payload", err))
+	}
+
+	var fetchChaptersResponse struct {
+		Data struct {
+			FetchChapters struct {
+				Chapters []*APIChapter `json:"chapters"`
+			} `json:"fetchChapters"`
+		} `json:"data"`
+	}
+	_, err = s.baseRequest(bytes.NewBuffer(jsonData), &fetchChaptersResponse)
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(errorContext, mangaID), err)
+	}
+
+	return fetchChaptersResponse.Data.FetchChapters.Chapters, nil
+}
+
 func (s *Suwayomi) GetChapter(mangaID int, chapterURL string) (*APIChapter, error) {
 	errorContext := "error while getting chapter '%s' for manga '%d'"
 
@@ -360,6 +581,30 @@ func (s *Suwayomi) GetChapter(mangaID int, chapterURL string) (*APIChapter, erro
 	return nil, util.AddErrorContext(fmt.Sprintf(errorContext, chapterURL, mangaID), fmt.Errorf("chapter not found"))
 }
 
+// GetChapterArchive returns the CBZ archive of a downloaded chapter.
+// The caller must close the returned body.
+func (s *Suwayomi) GetChapterArchive(mangaID, chapterIndex int) (io.ReadCloser, int64, error) {
+	errorContext := "error while getting archive of chapter '%d' for manga '%d'"
+
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/v1/manga/%d/chapter/%d/download", s.Address, mangaID, chapterIndex), nil)
+	if err != nil {
+		return nil, 0, util.AddErrorContext(fmt.Sprintf(errorContext, chapterIndex, mangaID), err)
+	}
+	req.SetBasicAuth(s.Username, s.Password)
+
+	resp, err := s.c.Do(req)
+	if err != nil {
+		return nil, 0, util.AddErrorContext(fmt.Sprintf(errorContext, chapterIndex, mangaID), err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return nil, 0, util.AddErrorContext(fmt.Sprintf(errorContext, chapterIndex, mangaID), fmt.Errorf("non-200 status code -> (%d). Body: %s", resp.StatusCode, string(body)))
+	}
+
+	return resp.Body, resp.ContentLength, nil
+}
+
 func (s *Suwayomi) EnqueueChapterDownloads(chapterIDs []int) error {
 	errorContext := "error while enqueueing chapter downloads for chapters '%s'"
 
@@ -394,21 +639,93 @@ mutation MyMutation($ids: [Int!] = [%s]) {
 	return nil
 }
 
+func (s *Suwayomi) DeleteDownloadedChapters(chapterIDs []int) error {
+	errorContext := "error while deleting downloaded chapters '%v'"
+
+	payload := map[string]any{
+		"query": `
+mutation DeleteDownloadedChapters($ids: [Int!]!) {
+  deleteDownloadedChapters(input: {ids: $ids}) {
+    clientMutationId
+  }
+}
+	`,
+		"variables": map[string]any{
+			"ids": chapterIDs,
+		},
+		"operationName": "DeleteDownloadedChapters",
+	}
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf(errorContext, chapterIDs), util.AddErrorContext("error while marshalling payload", err))
+	}
+
+	_, err = s.baseRequest(bytes.NewBuffer(jsonData), nil)
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf(errorContext, chapterIDs), err)
+	}
+
+	return nil
+}
+
+// EnqueueChapterDownload enqueues the download of the manga's chapter with the URL.
+// The manga must be in the library.
+func (s *Suwayomi) EnqueueChapterDownload(m *manga.Manga, chapterURL string) error {
+	errorContext := "(suwayomi) error while enqueueing download of chapter '%s' of manga '%s' / '%s'"
+
+	mangaID, err := s.GetLibraryMangaID(m)
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf(errorContext, chapterURL, m.Name, m.URL), err)
+	}
+	chapter, err := s.GetChapter(mangaID, chapterURL)
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf(errorContext, chapterURL, m.Name, m.URL), err)
+	}
+	err = s.EnqueueChapterDownloads([]int{chapter.ID})
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf(errorContext, chapterURL, m.Name, m.URL), err)
+	}
+
+	return nil
+}
+
+// suwayomiSources maps the Mantium sources to the Suwayomi sources.
+var suwayomiSources = map[string]string{
+	"comick":    "Comick (ALL)",
+	"mangadex":  "MangaDex (EN)",
+	"mangaplus": "MANGA Plus by SHUEISHA (EN)",
+	"mangahub":  "MangaHub (EN)",
+}
+
+// SupportedSources returns the Mantium sources that can be added to Suwayomi.
+func SupportedSources() []string {
+	return slices.Sorted(maps.Keys(suwayomiSources))
+}
+
+// GetMangaKey returns the key of the manga in Suwayomi, which is the same
+// as the key of the library manga returned by APIManga.Key.
+func (s *Suwayomi) GetMangaKey(m *manga.Manga) (string, error) {
+	source, err := s.translateSuwayomiSource(m.Source)
+	if err != nil {
+		return "", err
+	}
+	mangaURL, err := s.getSourceMangaURL(m)
+	if err != nil {
+		return "", err
+	}
+
+	return source + mangaURL, nil
+}
+
 func (s *Suwayomi) translateSuwayomiSource(sourceName string) (string, error) {
 	errorContext := "error while translating Mantium source '%s' to Suwayomi source"
 
-	switch sourceName {
-	case "comick":
-		return "Comick (ALL)", nil
-	case "mangadex":
-		return "MangaDex (EN)", nil
-	case "mangaplus":
-		return "MANGA Plus by SHUEISHA (EN)", nil
-	case "mangahub":
-		return "MangaHub (EN)", nil
+	source, ok := suwayomiSources[sourceName]
+	if !ok {
+		return "", util.AddErrorContext(fmt.Sprintf(errorContext, sourceName), fmt.Errorf("source not found"))
 	}
 
-	return "", util.AddErrorContext(fmt.Sprintf(errorContext, sourceName), fmt.Errorf("source not found"))
+	return source, nil
 }
 
 func (s *Suwayomi) getSourceMangaURL(manga *manga.Manga) (string, error) {
@@ -42,7 +42,7 @@ func TestFetchSources(t *testing.T) {
 	s.Init()
 
 	t.Run("Test fetch sources", func(t *testing.T) {
-		sources, err := s.fetchSources()
+		sources, err := s.FetchSources()
 		if err != nil {
 			t.Fatalf("error while fetching sources: %v", err)
 		}
@@ -36,10 +36,34 @@ type APIManga struct {
 	URL       string `json:"url"`
 	RealURL   string `json:"realURL"`
 	Title     string `json:"title"`
+	// ThumbnailURL is the path of the thumbnail in the Suwayomi instance.
+	ThumbnailURL string `json:"thumbnailUrl"`
+	// Source is only returned by GetLibraryMangas.
+	Source *struct {
+		DisplayName string `json:"displayName"`
+	} `json:"source"`
+}
+
+// Key returns the key of the manga in Suwayomi. It's the manga's source
+// and URL in the source, so it can be matched with the Mantium mangas.
+func (m *APIManga) Key() string {
+	if m.Source == nil {
+		return m.URL
+	}
+
+	return m.Source.DisplayName + m.URL
 }
 
 type APIChapter struct {
 	ID           int    `json:"id"`
 	IsDownloaded bool   `json:"isDownloaded"`
 	RealURL      string `json:"realURL"`
+	Name         string `json:"name"`
+	// ChapterNumber is -1 if the source doesn't know the chapter number.
+	ChapterNumber float64 `json:"chapterNumber"`
+	// UploadDate is the upload date in milliseconds since the epoch. It's "0" if unknown.
+	// It's only returned by FetchChapters.
+	UploadDate string `json:"uploadDate"`
+	// SourceOrder is the chapter index used by the REST API.
+	SourceOrder int `json:"sourceOrder"`
 }
@@ -2,6 +2,7 @@ package suwayomi
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/diogovalentte/mantium/api/src/config"
 )
@@ -13,6 +14,16 @@ type Suwayomi struct {
 	Password string
 }
 
+// New returns a Suwayomi client for the instance in the address.
+func New(address, username, password string) *Suwayomi {
+	return &Suwayomi{
+		c:        &http.Client{},
+		Address:  strings.TrimSuffix(address, "/"),
+		Username: username,
+		Password: password,
+	}
+}
+
 func (s *Suwayomi) Init() {
 	s.c = &http.Client{}
 	s.Address = config.GlobalConfigs.Suwayomi.Address
@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/diogovalentte/mantium/api/src/manga"
@@ -70,11 +72,11 @@ func (t *Tranga) SearchManga(manga *manga.Manga, connector string) (*Manga, erro
 	return nil, fmt.Errorf("manga not found in Tranga")
 }
 
-func (t *Tranga) GetMonitorJobs() ([]*getMonitorJobsResponse, error) {
+func (t *Tranga) GetMonitorJobs() ([]*MonitorJob, error) {
 	errorContext := "error while getting monitor jobs"
 
 	url := fmt.Sprintf("%s/Jobs/MonitorJobs", t.Address)
-	var jobs []*getMonitorJobsResponse
+	var jobs []*MonitorJob
 	_, err := t.request(http.MethodGet, url, nil, &jobs)
 	if err != nil {
 		return nil, util.AddErrorContext(errorContext, err)
@@ -83,13 +85,7 @@ func (t *Tranga) GetMonitorJobs() ([]*getMonitorJobsResponse, error) {
 	return jobs, nil
 }
 
-type getMonitorJobsResponse struct {
-	Manga   Manga  `json:"manga"`
-	ID      string `json:"id"`
-	JobType int    `json:"jobType"`
-}
-
-func (t *Tranga) GetMonitorJobBySiteURL(mangaSiteURL string) (*getMonitorJobsResponse, error) {
+func (t *Tranga) GetMonitorJobBySiteURL(mangaSiteURL string) (*MonitorJob, error) {
 	errorContext := "error while getting monitor job by site URL for manga '%s'"
 
 	jobs, err := t.GetMonitorJobs()
@@ -106,6 +102,20 @@ func (t *Tranga) GetMonitorJobBySiteURL(mangaSiteURL string) (*getMonitorJobsRes
 	return nil, util.AddErrorContext(fmt.Sprintf(errorContext, mangaSiteURL), fmt.Errorf("monitor job not found in Tranga"))
 }
 
+// RemoveJob deletes the job from Tranga. Removing a manga's monitor job stops
+// Tranga from downloading its chapters, but the downloaded chapters are kept.
+func (t *Tranga) RemoveJob(jobID string) error {
+	errorContext := "(tranga) error while removing job '%s'"
+
+	url := fmt.Sprintf("%s/Jobs?jobId=%s", t.Address, jobID)
+	_, err := t.request(http.MethodDelete, url, nil, nil)
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf(errorContext, jobID), err)
+	}
+
+	return nil
+}
+
 func (t *Tranga) StartJob(manga *manga.Manga) error {
 	errorContext := "(tranga) error while starting job for manga '%s' / '%s'"
 
@@ -174,14 +184,21 @@ func (t *Tranga) request(method, url string, reqBody io.Reader, retBody interfac
 	return resp, nil
 }
 
+// trangaConnectors maps the Mantium sources to the Tranga connectors.
+var trangaConnectors = map[string]string{
+	"mangadex": "MangaDex",
+}
+
+// SupportedSources returns the Mantium sources that can be added to Tranga.
+func SupportedSources() []string {
+	return slices.Sorted(maps.Keys(trangaConnectors))
+}
+
 func (t *Tranga) getTrangaConnector(source string) (string, error) {
 	errorContext := "error while getting manga connector"
 
-	var returnConnector string
-	switch source {
-	case "mangadex":
-		returnConnector = "MangaDex"
-	default:
+	returnConnector, ok := trangaConnectors[source]
+	if !ok {
 		return "", util.AddErrorContext(errorContext, fmt.Errorf("%s connector is not implemented in Tranga", source))
 	}
 
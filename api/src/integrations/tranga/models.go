@@ -5,3 +5,10 @@ type Manga struct {
 	InternalID              string   `json:"internalID"`
 	WebSiteURL              string   `json:"webSiteURL"`
 }
+
+// MonitorJob is a Tranga job that monitors a manga for new chapters.
+type MonitorJob struct {
+	Manga   Manga  `json:"manga"`
+	ID      string `json:"id"`
+	JobType int    `json:"jobType"`
+}
@@ -0,0 +1,259 @@
+// Package jobs implements a queue of jobs stored in the database that are run
+// in the background and retried with backoff when they fail, like the jobs
+// that add the mangas to the download integrations after they're added to Mantium.
+package jobs
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/diogovalentte/mantium/api/src/db"
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// TypeAddMangaToIntegration is the job that adds a manga to a download integration.
+const TypeAddMangaToIntegration = "add_manga_to_integration"
+
+// Job statuses
+const (
+	StatusPending   = "pending"
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+const (
+	// BackoffBase is the time until a job is run again after its first failure.
+	// It's doubled after each failure until BackoffMax.
+	BackoffBase = 30 * time.Second
+	BackoffMax  = time.Hour
+	// SucceededJobsTTL is how long the succeeded jobs are kept in the database.
+	SucceededJobsTTL = 7 * 24 * time.Hour
+	// RunningJobsLease is how long a job claimed by a worker can run. The running jobs whose
+	// lease has expired were left running, like when their API instance stopped, so they're run again.
+	RunningJobsLease = 30 * time.Minute
+)
+
+// Job is a job in the queue.
+type Job struct {
+	ID   int    `json:"id"`
+	Type string `json:"type"`
+	// Integration is the name of the download integration, like "Kaizoku".
+	Integration string   `json:"integration"`
+	MangaID     manga.ID `json:"manga_id"`
+	Status      string   `json:"status"`
+	// Attempts is the number of times the job was run.
+	Attempts    int    `json:"attempts"`
+	MaxAttempts int    `json:"max_attempts"`
+	LastError   string `json:"last_error,omitempty"`
+	// RunAt is when the job will run next if pending.
+	RunAt     time.Time `json:"run_at"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Backoff returns the time to wait before running a job again after the attempt failed.
+func Backoff(attempt int) time.Duration {
+	backoff := BackoffBase
+	for i := 1; i < attempt; i++ {
+		backoff *= 2
+		if backoff >= BackoffMax {
+			return BackoffMax
+		}
+	}
+
+	return backoff
+}
+
+// finish sets the job status after an attempt that returned err. The job is
+// scheduled to run again with backoff if it failed and still has attempts left.
+func (j *Job) finish(err error, now time.Time) {
+	j.UpdatedAt = now
+	if err == nil {
+		j.Status = StatusSucceeded
+		j.LastError = ""
+		return
+	}
+
+	j.LastError = err.Error()
+	if j.Attempts >= j.MaxAttempts {
+		j.Status = StatusFailed
+		return
+	}
+	j.Status = StatusPending
+	j.RunAt = now.Add(Backoff(j.Attempts))
+}
+
+// Enqueue inserts a pending job into the database to run now.
+func Enqueue(jobType, integration string, mangaID manga.ID, maxAttempts int, now time.Time) (*Job, error) {
+	contextError := fmt.Sprintf("error enqueueing job '%s' of manga '%d'", jobType, mangaID)
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return nil, util.AddErrorContext(contextError, err)
+	}
+	defer db.Close()
+
+	job := &Job{
+		Type:        jobType,
+		Integration: integration,
+		MangaID:     mangaID,
+		Status:      StatusPending,
+		MaxAttempts: maxAttempts,
+		RunAt:       now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	err = db.QueryRow(`
+        INSERT INTO jobs
+            (type, integration, manga_id, status, max_attempts, run_at, created_at, updated_at)
+        VALUES
+            ($1, $2, $3, $4, $5, $6, $7, $8)
+        RETURNING id;
+    `, job.Type, job.Integration, job.MangaID, job.Status, job.MaxAttempts, job.RunAt, job.CreatedAt, job.UpdatedAt).Scan(&job.ID)
+	if err != nil {
+		return nil, util.AddErrorContext(contextError, err)
+	}
+
+	return job, nil
+}
+
+const jobColumns = "id, type, integration, manga_id, status, attempts, max_attempts, last_error, run_at, created_at, updated_at"
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanJob(row rowScanner) (*Job, error) {
+	job := &Job{}
+	err := row.Scan(&job.ID, &job.Type, &job.Integration, &job.MangaID, &job.Status, &job.Attempts, &job.MaxAttempts, &job.LastError, &job.RunAt, &job.CreatedAt, &job.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// GetJob gets a job from the database.
+func GetJob(id int) (*Job, error) {
+	contextError := fmt.Sprintf("error getting job '%d' from DB", id)
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return nil, util.AddErrorContext(contextError, err)
+	}
+	defer db.Close()
+
+	job, err := scanJob(db.QueryRow(`SELECT `+jobColumns+` FROM jobs WHERE id = $1;`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, util.AddErrorContext(contextError, errordefs.ErrJobNotFound)
+		}
+		return nil, util.AddErrorContext(contextError, err)
+	}
+
+	return job, nil
+}
+
+// GetJobs gets the jobs from the database, the newest first.
+// The jobs can be filtered by status and manga ID. Empty status and 0 manga ID don't filter.
+func GetJobs(status string, mangaID manga.ID) ([]*Job, error) {
+	contextError := "error getting jobs from DB"
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return nil, util.AddErrorContext(contextError, err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`
+        SELECT `+jobColumns+`
+        FROM jobs
+        WHERE ($1 = '' OR status = $1) AND ($2 = 0 OR manga_id = $2)
+        ORDER BY created_at DESC, id DESC;
+    `, status, mangaID)
+	if err != nil {
+		return nil, util.AddErrorContext(contextError, err)
+	}
+	defer rows.Close()
+
+	jobs := []*Job{}
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, util.AddErrorContext(contextError, err)
+		}
+		jobs = append(jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, util.AddErrorContext(contextError, err)
+	}
+
+	return jobs, nil
+}
+
+// Retry schedules a failed job to run now with all its attempts again.
+func Retry(id int, now time.Time) (*Job, error) {
+	contextError := fmt.Sprintf("error retrying job '%d'", id)
+
+	job, err := GetJob(id)
+	if err != nil {
+		return nil, util.AddErrorContext(contextError, err)
+	}
+	if job.Status != StatusFailed {
+		return nil, util.AddErrorContext(contextError, errordefs.ErrJobNotRetryable)
+	}
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return nil, util.AddErrorContext(contextError, err)
+	}
+	defer db.Close()
+
+	job, err = scanJob(db.QueryRow(`
+        UPDATE jobs
+        SET status = $1, attempts = 0, run_at = $2, updated_at = $2
+        WHERE id = $3 AND status = $4
+        RETURNING `+jobColumns+`;
+    `, StatusPending, now, id, StatusFailed))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// Retried by another request at the same time
+			return nil, util.AddErrorContext(contextError, errordefs.ErrJobNotRetryable)
+		}
+		return nil, util.AddErrorContext(contextError, err)
+	}
+
+	return job, nil
+}
+
+// RetryFailed schedules all failed jobs to run now with all their attempts again.
+// It returns the number of jobs scheduled.
+func RetryFailed(now time.Time) (int, error) {
+	contextError := "error retrying the failed jobs"
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return 0, util.AddErrorContext(contextError, err)
+	}
+	defer db.Close()
+
+	result, err := db.Exec(`
+        UPDATE jobs
+        SET status = $1, attempts = 0, run_at = $2, updated_at = $2
+        WHERE status = $3;
+    `, StatusPending, now, StatusFailed)
+	if err != nil {
+		return 0, util.AddErrorContext(contextError, err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, util.AddErrorContext(contextError, err)
+	}
+
+	return int(rowsAffected), nil
+}
@@ -0,0 +1,52 @@
+package jobs
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	expected := map[int]time.Duration{
+		1:  30 * time.Second,
+		2:  time.Minute,
+		3:  2 * time.Minute,
+		4:  4 * time.Minute,
+		8:  BackoffMax,
+		50: BackoffMax,
+	}
+	for attempt, backoff := range expected {
+		if got := Backoff(attempt); got != backoff {
+			t.Errorf("expected backoff %s for attempt %d, got %s", backoff, attempt, got)
+		}
+	}
+}
+
+func TestJobFinish(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Should succeed", func(t *testing.T) {
+		job := &Job{Status: StatusRunning, Attempts: 2, MaxAttempts: 3, LastError: "timeout"}
+		job.finish(nil, now)
+		if job.Status != StatusSucceeded || job.LastError != "" {
+			t.Fatalf("expected job to succeed, got status %s and error %s", job.Status, job.LastError)
+		}
+	})
+	t.Run("Should schedule a retry with backoff", func(t *testing.T) {
+		job := &Job{Status: StatusRunning, Attempts: 2, MaxAttempts: 3}
+		job.finish(fmt.Errorf("timeout"), now)
+		if job.Status != StatusPending || job.LastError != "timeout" {
+			t.Fatalf("expected job to be pending with the error, got status %s and error %s", job.Status, job.LastError)
+		}
+		if !job.RunAt.Equal(now.Add(time.Minute)) {
+			t.Fatalf("expected job to run at %s, got %s", now.Add(time.Minute), job.RunAt)
+		}
+	})
+	t.Run("Should fail after the last attempt", func(t *testing.T) {
+		job := &Job{Status: StatusRunning, Attempts: 3, MaxAttempts: 3}
+		job.finish(fmt.Errorf("timeout"), now)
+		if job.Status != StatusFailed {
+			t.Fatalf("expected job to fail, got status %s", job.Status)
+		}
+	})
+}
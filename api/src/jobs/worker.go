@@ -0,0 +1,224 @@
+package jobs
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/diogovalentte/mantium/api/src/db"
+	"github.com/diogovalentte/mantium/api/src/integrations/downloads"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// claimBatchSize is the max number of jobs run at once.
+const claimBatchSize = 20
+
+// wake is used to run the due jobs before the next poll, like right after they're enqueued.
+var wake = make(chan struct{}, 1)
+
+// Wake makes the worker run the due jobs now instead of waiting for the next poll.
+func Wake() {
+	select {
+	case wake <- struct{}{}:
+	default:
+		// The worker was already woken up
+	}
+}
+
+// StartWorker runs the due jobs every pollInterval, or when Wake is called, in another goroutine.
+// The jobs left running, like when the API stopped, are run again after their lease expires.
+func StartWorker(pollInterval time.Duration, log *zerolog.Logger) error {
+	err := resetExpiredRunningJobs(time.Now())
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+			case <-wake:
+			}
+
+			now := time.Now()
+			err := resetExpiredRunningJobs(now)
+			if err != nil {
+				log.Error().Err(err).Msg("Error resetting the expired running jobs")
+			}
+			err = RunDueJobs(now, log)
+			if err != nil {
+				log.Error().Err(err).Msg("Error running the due jobs")
+			}
+			err = deleteSucceededJobs(now.Add(-SucceededJobsTTL))
+			if err != nil {
+				log.Error().Err(err).Msg("Error deleting the old succeeded jobs")
+			}
+		}
+	}()
+
+	return nil
+}
+
+// RunDueJobs runs the pending jobs whose run time is before now until there are no more due jobs.
+// A failed job is scheduled to run again with backoff, or marked as failed if it has no attempts left.
+func RunDueJobs(now time.Time, log *zerolog.Logger) error {
+	for {
+		jobs, err := claimDueJobs(now, claimBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(jobs) == 0 {
+			return nil
+		}
+
+		for _, job := range jobs {
+			err = run(job)
+			job.finish(err, time.Now())
+			if err != nil {
+				log.Error().Err(err).Int("job_id", job.ID).Str("job_type", job.Type).Int("attempt", job.Attempts).Str("status", job.Status).Msg("Job failed")
+			}
+			saveErr := saveJobResult(job)
+			if saveErr != nil {
+				return saveErr
+			}
+		}
+	}
+}
+
+// run runs the job once.
+func run(job *Job) error {
+	switch job.Type {
+	case TypeAddMangaToIntegration:
+		return addMangaToIntegration(job)
+	default:
+		return fmt.Errorf("unknown job type '%s'", job.Type)
+	}
+}
+
+func addMangaToIntegration(job *Job) error {
+	m, err := manga.GetMangaDB(job.MangaID, "")
+	if err != nil {
+		return err
+	}
+
+	for _, integration := range downloads.GetIntegrations() {
+		if strings.EqualFold(integration.Name(), job.Integration) {
+			return integration.AddManga(m)
+		}
+	}
+
+	return fmt.Errorf("download integration '%s' is not configured in the API", job.Integration)
+}
+
+// claimDueJobs marks up to limit due jobs as running and returns them.
+// The jobs are locked, so they're not claimed by other workers at the same time.
+func claimDueJobs(now time.Time, limit int) ([]*Job, error) {
+	contextError := "error claiming the due jobs"
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return nil, util.AddErrorContext(contextError, err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`
+        UPDATE jobs
+        SET status = $1, attempts = attempts + 1, updated_at = $2
+        WHERE id IN (
+            SELECT id FROM jobs
+            WHERE status = $3 AND run_at <= $2
+            ORDER BY run_at, id
+            LIMIT $4
+            FOR UPDATE SKIP LOCKED
+        )
+        RETURNING `+jobColumns+`;
+    `, StatusRunning, now, StatusPending, limit)
+	if err != nil {
+		return nil, util.AddErrorContext(contextError, err)
+	}
+	defer rows.Close()
+
+	jobs := []*Job{}
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, util.AddErrorContext(contextError, err)
+		}
+		jobs = append(jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, util.AddErrorContext(contextError, err)
+	}
+
+	return jobs, nil
+}
+
+func saveJobResult(job *Job) error {
+	contextError := fmt.Sprintf("error saving the result of job '%d'", job.ID)
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return util.AddErrorContext(contextError, err)
+	}
+	defer db.Close()
+
+	_, err = db.Exec(`
+        UPDATE jobs
+        SET status = $1, last_error = $2, run_at = $3, updated_at = $4
+        WHERE id = $5;
+    `, job.Status, job.LastError, job.RunAt, job.UpdatedAt, job.ID)
+	if err != nil {
+		return util.AddErrorContext(contextError, err)
+	}
+
+	return nil
+}
+
+// resetExpiredRunningJobs schedules the running jobs whose lease has expired to run now, like
+// when the API stopped while running them. The jobs of other API instances that are still
+// running are not changed.
+func resetExpiredRunningJobs(now time.Time) error {
+	contextError := "error resetting the expired running jobs"
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return util.AddErrorContext(contextError, err)
+	}
+	defer db.Close()
+
+	_, err = db.Exec(`
+        UPDATE jobs
+        SET status = $1, run_at = $2, updated_at = $2
+        WHERE status = $3 AND updated_at <= $4;
+    `, StatusPending, now, StatusRunning, now.Add(-RunningJobsLease))
+	if err != nil {
+		return util.AddErrorContext(contextError, err)
+	}
+
+	return nil
+}
+
+func deleteSucceededJobs(before time.Time) error {
+	contextError := "error deleting the old succeeded jobs"
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return util.AddErrorContext(contextError, err)
+	}
+	defer db.Close()
+
+	_, err = db.Exec(`
+        DELETE FROM jobs
+        WHERE status = $1 AND updated_at < $2;
+    `, StatusSucceeded, before)
+	if err != nil {
+		return util.AddErrorContext(contextError, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,141 @@
+package manga
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/diogovalentte/mantium/api/src/db"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// MultiMangaFilter selects multimangas, like the multimangas of a bulk operation.
+// A multimanga matches if it matches all the filter fields. Empty fields match all multimangas.
+type MultiMangaFilter struct {
+	// Status matches the multimangas with any of the statuses.
+	Status []Status `json:"status,omitempty"`
+	// Tags matches the multimangas with any of the tags.
+	Tags []string `json:"tags,omitempty"`
+	// Source matches the multimangas whose current manga is from the source, like "mangadex".
+	Source string `json:"source,omitempty"`
+	// Unread matches the multimangas with (true) or without (false) unread chapters.
+	Unread *bool `json:"unread,omitempty"`
+}
+
+// IsEmpty returns true if the filter has no fields, so it matches all multimangas.
+func (f *MultiMangaFilter) IsEmpty() bool {
+	return len(f.Status) == 0 && len(f.Tags) == 0 && f.Source == "" && f.Unread == nil
+}
+
+// Matches returns true if the multimanga matches the filter.
+func (f *MultiMangaFilter) Matches(mm *MultiManga) bool {
+	if len(f.Status) > 0 && !slices.Contains(f.Status, mm.Status) {
+		return false
+	}
+	if !HasAnyTag(mm.Tags, f.Tags) {
+		return false
+	}
+	if f.Source != "" && (mm.CurrentManga == nil || !strings.EqualFold(mm.CurrentManga.Source, f.Source)) {
+		return false
+	}
+	if f.Unread != nil && (mm.UnreadChapters > 0) != *f.Unread {
+		return false
+	}
+
+	return true
+}
+
+// BulkUpdateStatusInDB updates the status of the multimangas in the database in one transaction.
+// It returns the error of each multimanga that couldn't be updated by its ID. These multimangas
+// are not changed, but the others are. The returned error is set if the transaction failed.
+func BulkUpdateStatusInDB(multimangas []*MultiManga, status Status) (map[ID]error, error) {
+	errors, err := bulkUpdateInDB(multimangas, func(mm *MultiManga, tx *sql.Tx) error {
+		return updateMultiMangaStatusDB(mm, status, tx)
+	})
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf("error updating the status of %d multimangas in DB", len(multimangas)), err)
+	}
+	for _, mm := range multimangas {
+		if errors[mm.ID] == nil {
+			mm.Status = status
+		}
+	}
+
+	return errors, nil
+}
+
+// BulkAddTagInDB adds the tag to the multimangas in the database in one transaction.
+// The tags are normalized with NormalizeTags. It returns the errors like BulkUpdateStatusInDB.
+func BulkAddTagInDB(multimangas []*MultiManga, tag string) (map[ID]error, error) {
+	newTags := make(map[ID][]string, len(multimangas))
+	errors, err := bulkUpdateInDB(multimangas, func(mm *MultiManga, tx *sql.Tx) error {
+		tags := NormalizeTags(append(slices.Clone(mm.Tags), tag))
+		newTags[mm.ID] = tags
+		return updateMultiMangaTagsDB(mm.ID, tags, tx)
+	})
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf("error adding tag '%s' to %d multimangas in DB", tag, len(multimangas)), err)
+	}
+	for _, mm := range multimangas {
+		if errors[mm.ID] == nil {
+			mm.Tags = newTags[mm.ID]
+		}
+	}
+
+	return errors, nil
+}
+
+// BulkDeleteFromDB deletes the multimangas, their mangas, and their chapters from the
+// database in one transaction. It returns the errors like BulkUpdateStatusInDB.
+func BulkDeleteFromDB(multimangas []*MultiManga) (map[ID]error, error) {
+	errors, err := bulkUpdateInDB(multimangas, deleteMultiMangaDB)
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf("error deleting %d multimangas from DB", len(multimangas)), err)
+	}
+
+	return errors, nil
+}
+
+// bulkUpdateInDB runs update with each multimanga in one transaction. Each update runs in a
+// savepoint, so a failed update is rolled back without rolling back the other multimangas' updates.
+func bulkUpdateInDB(multimangas []*MultiManga, update func(mm *MultiManga, tx *sql.Tx) error) (map[ID]error, error) {
+	db, err := db.OpenConn()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	errors := map[ID]error{}
+	for _, mm := range multimangas {
+		_, err = tx.Exec(`SAVEPOINT bulk_item;`)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		updateErr := update(mm, tx)
+		if updateErr != nil {
+			errors[mm.ID] = updateErr
+			_, err = tx.Exec(`ROLLBACK TO SAVEPOINT bulk_item;`)
+		} else {
+			_, err = tx.Exec(`RELEASE SAVEPOINT bulk_item;`)
+		}
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return errors, nil
+}
@@ -0,0 +1,37 @@
+package manga
+
+import "testing"
+
+func TestMultiMangaFilterMatches(t *testing.T) {
+	unread, read := true, false
+	mm := &MultiManga{
+		Status:         1,
+		Tags:           []string{"action", "favorite"},
+		CurrentManga:   &Manga{Source: "mangadex.org"},
+		UnreadChapters: 2,
+	}
+
+	tests := []struct {
+		name    string
+		filter  MultiMangaFilter
+		matches bool
+	}{
+		{"Empty filter", MultiMangaFilter{}, true},
+		{"Same status", MultiMangaFilter{Status: []Status{1, 2}}, true},
+		{"Other status", MultiMangaFilter{Status: []Status{4}}, false},
+		{"Any tag", MultiMangaFilter{Tags: []string{"Favorite", "drama"}}, true},
+		{"Other tags", MultiMangaFilter{Tags: []string{"drama"}}, false},
+		{"Same source", MultiMangaFilter{Source: "MangaDex.org"}, true},
+		{"Other source", MultiMangaFilter{Source: "comick.io"}, false},
+		{"Unread", MultiMangaFilter{Unread: &unread}, true},
+		{"Read", MultiMangaFilter{Unread: &read}, false},
+		{"All fields", MultiMangaFilter{Status: []Status{1}, Tags: []string{"action"}, Source: "mangadex.org", Unread: &unread}, true},
+		{"One field doesn't match", MultiMangaFilter{Status: []Status{1}, Source: "comick.io"}, false},
+	}
+
+	for _, test := range tests {
+		if matches := test.filter.Matches(mm); matches != test.matches {
+			t.Errorf("%s: expected Matches to return %v, got %v", test.name, test.matches, matches)
+		}
+	}
+}
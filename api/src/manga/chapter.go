@@ -32,7 +32,13 @@ type Chapter struct {
 	Name string
 	// InteralID is a unique identifier for the chapter in the source
 	InternalID string
-	Type       Type
+	// Language is the language of the chapter release. It's only set by the sources
+	// with releases in multiple languages and isn't stored in the database.
+	Language string
+	// Groups are the scanlation groups of the chapter release. They're only set by the sources
+	// with releases by multiple groups and aren't stored in the database.
+	Groups []string
+	Type   Type
 }
 
 func (c Chapter) String() string {
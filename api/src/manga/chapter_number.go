@@ -0,0 +1,216 @@
+package manga
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ChapterNumber is a chapter parsed from the chapter string of a source,
+// like "10.5", "Vol.3 Ch.12", "#012", "10a", "60-End", or "Oneshot".
+type ChapterNumber struct {
+	// Raw is the normalized chapter string: lower case and trimmed.
+	Raw    string
+	Volume float64
+	Number float64
+	// Part is the part of the chapter, like 2 in "10 Part 2" or "10b".
+	// It's 0 when the chapter has no parts.
+	Part int
+	// HasVolume and HasNumber are false when
+	// the chapter string has no volume or number.
+	HasVolume bool
+	HasNumber bool
+	// Special is true for extras, omakes, oneshots, etc.
+	// A special with a number comes after the chapter with the same number.
+	Special bool
+}
+
+var (
+	chapterVolumeRegex       = regexp.MustCompile(`\b(?:volume|vol|v)\s*\.?\s*(\d+(?:[.,]\d+)?)`)
+	chapterKeywordRegex      = regexp.MustCompile(`(?:\b(?:chapter|chap|ch|c|episode|ep)\s*\.?|#)\s*(\d+(?:[.,]\d+)?)`)
+	chapterRangeRegex        = regexp.MustCompile(`^(\d+(?:[.,]\d+)?)\s*[-~]\s*(\d+(?:[.,]\d+)?)`)
+	chapterNumberRegex       = regexp.MustCompile(`(\d+(?:[.,]\d+)?)`)
+	chapterPartRegex         = regexp.MustCompile(`\b(?:part|pt)\s*\.?\s*(\d+)`)
+	chapterLetterSuffixRegex = regexp.MustCompile(`^\d+(?:[.,]\d+)?([a-e])\b`)
+	chapterSpecialRegex      = regexp.MustCompile(`\b(?:extra|extras|ex|special|sp|omake|bonus|side\s*story|one\s*-?\s*shot|oneshot)\b`)
+	chapterPrologueRegex     = regexp.MustCompile(`\b(?:prologue|prolog)\b`)
+)
+
+// ParseChapterNumber parses a chapter string from a source.
+func ParseChapterNumber(chapter string) ChapterNumber {
+	raw := strings.ToLower(strings.TrimSpace(chapter))
+	n := ChapterNumber{Raw: raw}
+	rest := raw
+
+	if match := chapterVolumeRegex.FindStringSubmatchIndex(rest); match != nil {
+		n.Volume, n.HasVolume = parseChapterFloat(rest[match[2]:match[3]])
+		rest = rest[:match[0]] + " " + rest[match[1]:]
+	}
+
+	if match := chapterPartRegex.FindStringSubmatchIndex(rest); match != nil {
+		part, err := strconv.Atoi(rest[match[2]:match[3]])
+		if err == nil {
+			n.Part = part
+		}
+		rest = rest[:match[0]] + " " + rest[match[1]:]
+	}
+
+	if chapterSpecialRegex.MatchString(rest) {
+		n.Special = true
+	}
+
+	numberStr := ""
+	if match := chapterKeywordRegex.FindStringSubmatchIndex(rest); match != nil {
+		numberStr = rest[match[2]:match[3]]
+		rest = rest[match[2]:]
+	} else {
+		rest = strings.TrimSpace(rest)
+		if match := chapterNumberRegex.FindStringSubmatchIndex(rest); match != nil {
+			numberStr = rest[match[2]:match[3]]
+			rest = rest[match[2]:]
+		}
+	}
+
+	if numberStr != "" {
+		// Ranges like "1-3" are considered the last chapter of the range,
+		// "60-End" is considered 60.
+		if match := chapterRangeRegex.FindStringSubmatch(rest); match != nil {
+			start, okStart := parseChapterFloat(match[1])
+			end, okEnd := parseChapterFloat(match[2])
+			if okStart && okEnd && end > start {
+				numberStr = match[2]
+			}
+		}
+		n.Number, n.HasNumber = parseChapterFloat(numberStr)
+
+		if n.Part == 0 {
+			if match := chapterLetterSuffixRegex.FindStringSubmatch(rest); match != nil {
+				n.Part = int(match[1][0]-'a') + 1
+			}
+		}
+	} else if chapterPrologueRegex.MatchString(rest) {
+		n.Number, n.HasNumber = 0, true
+	}
+
+	return n
+}
+
+func parseChapterFloat(s string) (float64, bool) {
+	f, err := strconv.ParseFloat(strings.Replace(s, ",", ".", 1), 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return f, true
+}
+
+// Compare compares the chapter with the other chapter. It returns -1 if the
+// chapter comes before the other, 1 if it comes after, and 0 if they are the same chapter.
+// It returns false if the chapters can't be compared, like "Extra" and "Oneshot".
+func (n ChapterNumber) Compare(other ChapterNumber) (int, bool) {
+	// Chapters can restart at each volume, so the volume is compared
+	// first when both chapters have one.
+	if n.HasVolume && other.HasVolume && n.Volume != other.Volume {
+		return compareFloats(n.Volume, other.Volume), true
+	}
+
+	if n.HasNumber && other.HasNumber {
+		if n.Number != other.Number {
+			return compareFloats(n.Number, other.Number), true
+		}
+		if n.Part != other.Part {
+			return compareFloats(float64(n.Part), float64(other.Part)), true
+		}
+		if n.Special != other.Special {
+			if n.Special {
+				return 1, true
+			}
+			return -1, true
+		}
+		return 0, true
+	}
+
+	if n.Raw == other.Raw {
+		return 0, true
+	}
+
+	return 0, false
+}
+
+// Equal returns true if the chapters are the same chapter,
+// like "Chapter 10" and "10".
+func (n ChapterNumber) Equal(other ChapterNumber) bool {
+	cmp, ok := n.Compare(other)
+	return ok && cmp == 0
+}
+
+func compareFloats(a, b float64) int {
+	if a < b {
+		return -1
+	}
+	if a > b {
+		return 1
+	}
+	return 0
+}
+
+// CompareChapters compares two chapters by their chapter number.
+// If they can't be compared by the number or are the same number, they're
+// compared by the release date (UpdatedAt). The second return is false if
+// the chapters were compared by the release date.
+func CompareChapters(a, b *Chapter) (int, bool) {
+	cmp, ok := ParseChapterNumber(a.Chapter).Compare(ParseChapterNumber(b.Chapter))
+	if ok {
+		return cmp, true
+	}
+
+	if a.UpdatedAt.Before(b.UpdatedAt) {
+		return -1, false
+	}
+	if a.UpdatedAt.After(b.UpdatedAt) {
+		return 1, false
+	}
+	return 0, false
+}
+
+// IsChapterUnread returns true if the last released chapter
+// comes after the last read chapter. If the chapters can't be compared,
+// it's unread if they are different chapters, with different URLs.
+func IsChapterUnread(lastReleasedChapter, lastReadChapter *Chapter) bool {
+	if lastReleasedChapter == nil {
+		return false
+	}
+	if lastReadChapter == nil {
+		return true
+	}
+
+	cmp, ok := ParseChapterNumber(lastReleasedChapter.Chapter).Compare(ParseChapterNumber(lastReadChapter.Chapter))
+	if ok {
+		return cmp > 0
+	}
+
+	return lastReleasedChapter.URL == "" || lastReleasedChapter.URL != lastReadChapter.URL
+}
+
+// EstimateUnreadChapters estimates the number of unread chapters
+// from the last released and last read chapters' numbers.
+// If the chapters have no numbers, it's 1 when the chapter is unread.
+// It returns false if the number can't be estimated because there's no
+// last read chapter, as the first chapter isn't always chapter 1.
+func EstimateUnreadChapters(lastReleasedChapter, lastReadChapter *Chapter) (int, bool) {
+	if !IsChapterUnread(lastReleasedChapter, lastReadChapter) {
+		return 0, true
+	}
+	if lastReadChapter == nil {
+		return 0, false
+	}
+
+	released := ParseChapterNumber(lastReleasedChapter.Chapter)
+	read := ParseChapterNumber(lastReadChapter.Chapter)
+	if !released.HasNumber || !read.HasNumber || (released.HasVolume && read.HasVolume && released.Volume != read.Volume) {
+		return 1, true
+	}
+
+	return int(math.Max(1, math.Floor(released.Number)-math.Floor(read.Number))), true
+}
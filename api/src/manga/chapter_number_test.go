@@ -0,0 +1,151 @@
+package manga
+
+import (
+	"testing"
+	"time"
+)
+
+// Chapter strings in the formats returned by the sources
+var chapterNumberTestTable = []struct {
+	chapter   string
+	hasNumber bool
+	number    float64
+	hasVolume bool
+	volume    float64
+	part      int
+	special   bool
+}{
+	{chapter: "108", hasNumber: true, number: 108},     // MangaDex, MangaUpdates
+	{chapter: "155.2", hasNumber: true, number: 155.2}, // MangaHub
+	{chapter: "10,5", hasNumber: true, number: 10.5},
+	{chapter: "60-End", hasNumber: true, number: 60}, // RawKuma
+	{chapter: "1-3", hasNumber: true, number: 3},
+	{chapter: "#012", hasNumber: true, number: 12},
+	{chapter: "ex", special: true},
+	{chapter: "Chapter 122", hasNumber: true, number: 122},
+	{chapter: "Chap 18", hasNumber: true, number: 18},
+	{chapter: "Ch.12.5", hasNumber: true, number: 12.5},
+	{chapter: "Vol.3 Ch.12", hasNumber: true, number: 12, hasVolume: true, volume: 3},
+	{chapter: "v.3 c.12", hasNumber: true, number: 12, hasVolume: true, volume: 3},
+	{chapter: "Volume 2 Chapter 7", hasNumber: true, number: 7, hasVolume: true, volume: 2},
+	{chapter: "Vol.4", hasVolume: true, volume: 4},
+	{chapter: "10a", hasNumber: true, number: 10, part: 1},
+	{chapter: "Chapter 10 Part 2", hasNumber: true, number: 10, part: 2},
+	{chapter: "10 Extra", hasNumber: true, number: 10, special: true},
+	{chapter: "Extra", special: true},
+	{chapter: "Oneshot", special: true},
+	{chapter: "One-Shot", special: true},
+	{chapter: "Side Story 3", hasNumber: true, number: 3, special: true},
+	{chapter: "Prologue", hasNumber: true, number: 0},
+}
+
+func TestParseChapterNumber(t *testing.T) {
+	for _, test := range chapterNumberTestTable {
+		n := ParseChapterNumber(test.chapter)
+		if n.HasNumber != test.hasNumber || n.Number != test.number || n.HasVolume != test.hasVolume || n.Volume != test.volume || n.Part != test.part || n.Special != test.special {
+			t.Fatalf("ParseChapterNumber(%q) = %+v, expected %+v", test.chapter, n, test)
+		}
+	}
+}
+
+func TestCompareChapterNumbers(t *testing.T) {
+	tests := []struct {
+		a, b       string
+		cmp        int
+		comparable bool
+	}{
+		{"10", "10.5", -1, true},
+		{"10.5", "11", -1, true},
+		{"Chapter 10", "10", 0, true},
+		{"#010", "10", 0, true},
+		{"10", "10a", -1, true},
+		{"10b", "10a", 1, true},
+		{"10 Extra", "10", 1, true},
+		{"10 Extra", "11", -1, true},
+		{"Vol.3 Ch.12", "Vol.2 Ch.20", 1, true},
+		{"Vol.3 Ch.12", "13", -1, true},
+		{"Vol.4", "Vol.3 Ch.12", 1, true},
+		{"Oneshot", "oneshot", 0, true},
+		{"Extra", "Oneshot", 0, false},
+		{"Extra", "10", 0, false},
+		{"1-3", "2", 1, true},
+		{"60-End", "60", 0, true},
+	}
+
+	for _, test := range tests {
+		cmp, ok := ParseChapterNumber(test.a).Compare(ParseChapterNumber(test.b))
+		if cmp != test.cmp || ok != test.comparable {
+			t.Fatalf("Compare(%q, %q) = (%d, %v), expected (%d, %v)", test.a, test.b, cmp, ok, test.cmp, test.comparable)
+		}
+	}
+}
+
+func TestIsChapterUnread(t *testing.T) {
+	chapter := func(c string) *Chapter {
+		return &Chapter{Chapter: c, Name: c, Type: 1}
+	}
+	chapterWithURL := func(c, url string) *Chapter {
+		return &Chapter{Chapter: c, Name: c, URL: url, Type: 1}
+	}
+
+	tests := []struct {
+		released, read *Chapter
+		unread         bool
+		unreadCount    int
+		estimated      bool
+	}{
+		{chapter("10"), chapter("10"), false, 0, true},
+		{chapter("Chapter 10"), chapter("10"), false, 0, true},
+		{chapter("10.5"), chapter("10"), true, 1, true},
+		{chapter("15"), chapter("10"), true, 5, true},
+		// The user read a chapter from another source of the multimanga that is ahead
+		{chapter("10"), chapter("11"), false, 0, true},
+		{chapter("Extra"), chapter("10"), true, 1, true},
+		{chapter("Oneshot"), chapter("Oneshot"), false, 0, true},
+		// The source renamed the chapter
+		{chapterWithURL("Special", "https://example.com/1"), chapterWithURL("Extra", "https://example.com/1"), false, 0, true},
+		{chapterWithURL("Special", "https://example.com/2"), chapterWithURL("Extra", "https://example.com/1"), true, 1, true},
+		{chapter("Vol.2 Ch.1"), chapter("Vol.1 Ch.9"), true, 1, true},
+		{chapter("3"), nil, true, 0, false},
+		{nil, chapter("3"), false, 0, true},
+	}
+
+	for _, test := range tests {
+		unread := IsChapterUnread(test.released, test.read)
+		if unread != test.unread {
+			t.Fatalf("IsChapterUnread(%v, %v) = %v, expected %v", test.released, test.read, unread, test.unread)
+		}
+		unreadCount, estimated := EstimateUnreadChapters(test.released, test.read)
+		if unreadCount != test.unreadCount || estimated != test.estimated {
+			t.Fatalf("EstimateUnreadChapters(%v, %v) = (%d, %v), expected (%d, %v)", test.released, test.read, unreadCount, estimated, test.unreadCount, test.estimated)
+		}
+	}
+}
+
+func TestGetLatestMangaChapterNumbers(t *testing.T) {
+	now := time.Now()
+	mangaWithChapter := func(c string, updatedAt time.Time) *Manga {
+		return &Manga{LastReleasedChapter: &Chapter{Chapter: c, Name: c, UpdatedAt: updatedAt, Type: 1}}
+	}
+
+	t.Run("Should pick the manga with the biggest chapter number", func(t *testing.T) {
+		latest := mangaWithChapter("Vol.3 Ch.12.5", now.Add(-time.Hour))
+		m, err := GetLatestManga([]*Manga{mangaWithChapter("12", now), latest})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m != latest {
+			t.Fatalf("expected manga with chapter %s, got %s", latest.LastReleasedChapter.Chapter, m.LastReleasedChapter.Chapter)
+		}
+	})
+	t.Run("Should pick the manga that released last if the chapters can't be compared", func(t *testing.T) {
+		latest := mangaWithChapter("Extra", now)
+		m, err := GetLatestManga([]*Manga{mangaWithChapter("12", now.Add(-time.Hour)), latest})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m != latest {
+			t.Fatalf("expected manga with chapter %s, got %s", latest.LastReleasedChapter.Chapter, m.LastReleasedChapter.Chapter)
+		}
+	})
+}
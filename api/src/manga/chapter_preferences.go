@@ -0,0 +1,305 @@
+package manga
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/diogovalentte/mantium/api/src/db"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// ChapterPreferences are the preferences used to choose between the releases of
+// the same chapter when the manga source has multiple releases of a chapter,
+// in different languages or by different scanlation groups, like MangaDex and ComicK.
+type ChapterPreferences struct {
+	// Languages are the languages of the releases to use, from the most to the least preferred.
+	// Releases in other languages are ignored. If it's empty, the source's default language is used.
+	Languages []string `json:"languages"`
+	// Groups are the preferred groups, from the most to the least preferred.
+	// Releases by other groups are used only when there is no release by these groups.
+	Groups []string `json:"groups"`
+	// BlockedGroups are the groups whose releases are ignored.
+	BlockedGroups []string `json:"blocked_groups"`
+	// WaitHours is how many hours to wait for a release by one of the preferred groups
+	// before using a release by other groups. If it's 0, it doesn't wait.
+	WaitHours int `json:"wait_hours"`
+	MangaID   ID  `json:"manga_id"`
+}
+
+// Normalize trims the languages and groups and removes the empty and duplicate ones.
+// Languages and groups are compared case-insensitively.
+func (p *ChapterPreferences) Normalize() {
+	p.Languages = normalizePreferences(p.Languages)
+	p.Groups = normalizePreferences(p.Groups)
+	p.BlockedGroups = normalizePreferences(p.BlockedGroups)
+}
+
+func normalizePreferences(values []string) []string {
+	normalized := []string{}
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value != "" && preferenceIndex(normalized, value) == -1 {
+			normalized = append(normalized, value)
+		}
+	}
+
+	return normalized
+}
+
+// IsEmpty returns true if the preferences don't change the releases chosen by the source.
+func (p *ChapterPreferences) IsEmpty() bool {
+	return p == nil || (len(p.Languages) == 0 && len(p.Groups) == 0 && len(p.BlockedGroups) == 0)
+}
+
+// SelectReleases chooses one release for each chapter from the releases of a manga,
+// sorted from the newest to the oldest chapter, and returns them in the same order.
+// The releases in other languages or by blocked groups are ignored. Of the rest,
+// the release in the most preferred language, then by the most preferred group, is chosen.
+// If WaitHours is set and no preferred group released the chapter yet, the chapter
+// is skipped until WaitHours passed since its first release.
+func (p *ChapterPreferences) SelectReleases(releases []*Chapter, currentTime time.Time) []*Chapter {
+	if p.IsEmpty() {
+		return releases
+	}
+
+	chapters := []string{}
+	chapterReleases := map[string][]*Chapter{}
+	for _, release := range releases {
+		if !p.allows(release) {
+			continue
+		}
+		if _, ok := chapterReleases[release.Chapter]; !ok {
+			chapters = append(chapters, release.Chapter)
+		}
+		chapterReleases[release.Chapter] = append(chapterReleases[release.Chapter], release)
+	}
+
+	selected := make([]*Chapter, 0, len(chapters))
+	for _, chapter := range chapters {
+		best := chapterReleases[chapter][0]
+		firstReleasedAt := best.UpdatedAt
+		for _, release := range chapterReleases[chapter][1:] {
+			if p.isPreferred(release, best) {
+				best = release
+			}
+			if release.UpdatedAt.Before(firstReleasedAt) {
+				firstReleasedAt = release.UpdatedAt
+			}
+		}
+
+		if p.WaitHours > 0 && len(p.Groups) > 0 && p.groupRank(best) == len(p.Groups) &&
+			currentTime.Sub(firstReleasedAt) < time.Duration(p.WaitHours)*time.Hour {
+			continue
+		}
+
+		selected = append(selected, best)
+	}
+
+	return selected
+}
+
+// allows returns false if the release is in a language not preferred or by a blocked group.
+func (p *ChapterPreferences) allows(release *Chapter) bool {
+	if len(p.Languages) > 0 && preferenceIndex(p.Languages, release.Language) == -1 {
+		return false
+	}
+	for _, group := range release.Groups {
+		if preferenceIndex(p.BlockedGroups, group) != -1 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isPreferred returns true if the release is preferred over the other release.
+func (p *ChapterPreferences) isPreferred(release, other *Chapter) bool {
+	releaseLanguageRank, otherLanguageRank := p.languageRank(release), p.languageRank(other)
+	if releaseLanguageRank != otherLanguageRank {
+		return releaseLanguageRank < otherLanguageRank
+	}
+
+	return p.groupRank(release) < p.groupRank(other)
+}
+
+func (p *ChapterPreferences) languageRank(release *Chapter) int {
+	if index := preferenceIndex(p.Languages, release.Language); index != -1 {
+		return index
+	}
+
+	return len(p.Languages)
+}
+
+// groupRank returns the position of the release's most preferred group in the preferred
+// groups, or the number of preferred groups if the release isn't by any of them.
+func (p *ChapterPreferences) groupRank(release *Chapter) int {
+	rank := len(p.Groups)
+	for _, group := range release.Groups {
+		if index := preferenceIndex(p.Groups, group); index != -1 && index < rank {
+			rank = index
+		}
+	}
+
+	return rank
+}
+
+func preferenceIndex(values []string, value string) int {
+	return slices.IndexFunc(values, func(v string) bool {
+		return strings.EqualFold(v, value)
+	})
+}
+
+// preferredGroup returns the most preferred group, truncated to the 30
+// characters of the mangas.preferred_group column.
+func (p *ChapterPreferences) preferredGroup() string {
+	if len(p.Groups) == 0 {
+		return ""
+	}
+	group := []rune(p.Groups[0])
+	if len(group) > 30 {
+		group = group[:30]
+	}
+
+	return string(group)
+}
+
+// UpsertIntoDB saves the manga chapter preferences in the database.
+// The manga's preferred group is set to the most preferred group.
+func (p *ChapterPreferences) UpsertIntoDB() error {
+	contextError := "error upserting manga '%d' chapter preferences into DB"
+
+	p.Normalize()
+	if p.WaitHours < 0 {
+		return util.AddErrorContext(fmt.Sprintf(contextError, p.MangaID), fmt.Errorf("wait hours should be 0 or greater"))
+	}
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf(contextError, p.MangaID), err)
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf(contextError, p.MangaID), err)
+	}
+
+	_, err = tx.Exec(`
+        INSERT INTO chapter_preferences (manga_id, languages, groups, blocked_groups, wait_hours)
+        VALUES ($1, $2, $3, $4, $5)
+        ON CONFLICT (manga_id)
+        DO UPDATE
+            SET languages = EXCLUDED.languages, groups = EXCLUDED.groups,
+                blocked_groups = EXCLUDED.blocked_groups, wait_hours = EXCLUDED.wait_hours;
+    `, p.MangaID, pq.Array(p.Languages), pq.Array(p.Groups), pq.Array(p.BlockedGroups), p.WaitHours)
+	if err != nil {
+		tx.Rollback()
+		return util.AddErrorContext(fmt.Sprintf(contextError, p.MangaID), err)
+	}
+
+	_, err = tx.Exec(`
+        UPDATE mangas
+        SET preferred_group = $1
+        WHERE id = $2;
+    `, p.preferredGroup(), p.MangaID)
+	if err != nil {
+		tx.Rollback()
+		return util.AddErrorContext(fmt.Sprintf(contextError, p.MangaID), err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf(contextError, p.MangaID), err)
+	}
+
+	return nil
+}
+
+// GetChapterPreferencesDB returns the manga chapter preferences from the database.
+// If the manga doesn't have preferences, empty preferences are returned.
+func GetChapterPreferencesDB(mangaID ID) (*ChapterPreferences, error) {
+	contextError := "error getting manga '%d' chapter preferences from DB"
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, mangaID), err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`
+        SELECT
+            `+chapterPreferencesColumns+`
+        FROM
+            chapter_preferences
+        WHERE
+            manga_id = $1;
+    `, mangaID)
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, mangaID), err)
+	}
+	defer rows.Close()
+
+	preferences, err := scanChapterPreferences(rows)
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, mangaID), err)
+	}
+	if p, ok := preferences[mangaID]; ok {
+		return p, nil
+	}
+
+	return &ChapterPreferences{MangaID: mangaID, Languages: []string{}, Groups: []string{}, BlockedGroups: []string{}}, nil
+}
+
+// GetAllChapterPreferencesDB returns the chapter preferences of all mangas
+// that have preferences from the database, by manga ID.
+func GetAllChapterPreferencesDB() (map[ID]*ChapterPreferences, error) {
+	contextError := "error getting all chapter preferences from DB"
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return nil, util.AddErrorContext(contextError, err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`
+        SELECT
+            ` + chapterPreferencesColumns + `
+        FROM
+            chapter_preferences;
+    `)
+	if err != nil {
+		return nil, util.AddErrorContext(contextError, err)
+	}
+	defer rows.Close()
+
+	preferences, err := scanChapterPreferences(rows)
+	if err != nil {
+		return nil, util.AddErrorContext(contextError, err)
+	}
+
+	return preferences, nil
+}
+
+const chapterPreferencesColumns = `manga_id, languages, groups, blocked_groups, wait_hours`
+
+func scanChapterPreferences(rows *sql.Rows) (map[ID]*ChapterPreferences, error) {
+	preferences := map[ID]*ChapterPreferences{}
+	for rows.Next() {
+		p := &ChapterPreferences{}
+		err := rows.Scan(&p.MangaID, pq.Array(&p.Languages), pq.Array(&p.Groups), pq.Array(&p.BlockedGroups), &p.WaitHours)
+		if err != nil {
+			return nil, err
+		}
+		preferences[p.MangaID] = p
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return preferences, nil
+}
@@ -0,0 +1,130 @@
+package manga
+
+import (
+	"fmt"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestSelectReleases(t *testing.T) {
+	currentTime := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	release := func(chapter, language string, hoursAgo int, groups ...string) *Chapter {
+		return &Chapter{
+			Chapter:   chapter,
+			URL:       fmt.Sprintf("https://mangadex.org/chapter/%s-%s-%d", chapter, language, hoursAgo),
+			Language:  language,
+			Groups:    groups,
+			UpdatedAt: currentTime.Add(-time.Duration(hoursAgo) * time.Hour),
+		}
+	}
+
+	// From the newest to the oldest chapter, like the sources return them
+	releases := []*Chapter{
+		release("12", "en", 2, "Fast Scans"),
+		release("11", "en", 30, "Fast Scans"),
+		release("11", "pt-br", 29, "Brazil Scans"),
+		release("11", "en", 20, "Good Scans"),
+		release("10", "en", 100, "Fast Scans", "Bad Scans"),
+		release("10", "en", 90, "Other Scans"),
+		release("9", "en", 200),
+	}
+
+	tests := []struct {
+		name        string
+		preferences *ChapterPreferences
+		expected    []*Chapter
+	}{
+		{
+			name:        "Empty preferences keep all releases",
+			preferences: &ChapterPreferences{},
+			expected:    releases,
+		},
+		{
+			name:        "Preferred group is chosen",
+			preferences: &ChapterPreferences{Groups: []string{"good scans"}},
+			expected:    []*Chapter{releases[0], releases[3], releases[4], releases[6]},
+		},
+		{
+			name:        "Blocked groups are ignored",
+			preferences: &ChapterPreferences{BlockedGroups: []string{"Bad Scans"}},
+			expected:    []*Chapter{releases[0], releases[1], releases[5], releases[6]},
+		},
+		{
+			name:        "Preferred language is chosen before the preferred group",
+			preferences: &ChapterPreferences{Languages: []string{"pt-br", "en"}, Groups: []string{"Good Scans"}},
+			expected:    []*Chapter{releases[0], releases[2], releases[4], releases[6]},
+		},
+		{
+			name:        "Other languages are ignored",
+			preferences: &ChapterPreferences{Languages: []string{"pt-br"}},
+			expected:    []*Chapter{releases[2]},
+		},
+		{
+			name:        "Waits for the preferred group",
+			preferences: &ChapterPreferences{Groups: []string{"Good Scans"}, WaitHours: 24},
+			expected:    []*Chapter{releases[3], releases[4], releases[6]},
+		},
+		{
+			name:        "Stops waiting for the preferred group",
+			preferences: &ChapterPreferences{Groups: []string{"Good Scans"}, WaitHours: 1},
+			expected:    []*Chapter{releases[0], releases[3], releases[4], releases[6]},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			selected := test.preferences.SelectReleases(releases, currentTime)
+			if len(selected) != len(test.expected) {
+				t.Fatalf("expected %d releases, got %d: %v", len(test.expected), len(selected), selected)
+			}
+			for i := range selected {
+				if selected[i] != test.expected[i] {
+					t.Fatalf("expected release %d to be %s, got %s", i, test.expected[i], selected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNormalizeChapterPreferences(t *testing.T) {
+	preferences := &ChapterPreferences{
+		Languages:     []string{" en ", "EN", "", "pt-br"},
+		Groups:        []string{"Good Scans", "good scans"},
+		BlockedGroups: nil,
+	}
+	preferences.Normalize()
+
+	if len(preferences.Languages) != 2 || preferences.Languages[0] != "en" || preferences.Languages[1] != "pt-br" {
+		t.Fatalf("unexpected languages: %v", preferences.Languages)
+	}
+	if len(preferences.Groups) != 1 || preferences.Groups[0] != "Good Scans" {
+		t.Fatalf("unexpected groups: %v", preferences.Groups)
+	}
+	if preferences.BlockedGroups == nil || len(preferences.BlockedGroups) != 0 {
+		t.Fatalf("expected blocked groups to be empty, not nil")
+	}
+}
+
+func TestChapterPreferencesPreferredGroup(t *testing.T) {
+	tests := map[string]string{
+		"":                                  "",
+		"Good Scans":                        "Good Scans",
+		"Scanlation Group With A Long Name": "Scanlation Group With A Long N",
+		"翻訳グループ・ファンサブ・スキャンレーション・チーム・ジャパン・東京支部": "翻訳グループ・ファンサブ・スキャンレーション・チーム・ジャパ",
+	}
+
+	for group, expected := range tests {
+		preferences := &ChapterPreferences{}
+		if group != "" {
+			preferences.Groups = []string{group}
+		}
+		preferredGroup := preferences.preferredGroup()
+		if preferredGroup != expected {
+			t.Errorf("expected group '%s' to be '%s', got '%s'", group, expected, preferredGroup)
+		}
+		if !utf8.ValidString(preferredGroup) {
+			t.Errorf("expected group '%s' to be valid UTF-8", preferredGroup)
+		}
+	}
+}
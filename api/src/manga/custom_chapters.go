@@ -0,0 +1,226 @@
+package manga
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/diogovalentte/mantium/api/src/db"
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// CustomChapter is a chapter of a custom manga's chapter list.
+// The chapters are added by the user or by the custom manga's watcher.
+// When a custom manga has a chapter list, its last released chapter is
+// the last chapter of the list, so it has unread chapters when the last
+// chapter of the list comes after its last read chapter.
+type CustomChapter struct {
+	// ReleasedAt is when the chapter was released, or when the watcher found it.
+	ReleasedAt time.Time `json:"released_at"`
+	Chapter    string    `json:"chapter"`
+	Name       string    `json:"name"`
+	// URL is the URL of the chapter.
+	// If the user doesn't provide a URL, it should be like CustomMangaURLPrefix/<uuid>.
+	URL string `json:"url"`
+}
+
+// GetCustomChaptersFromDB returns the custom manga chapter list, sorted from the first to the last chapter.
+func (m *Manga) GetCustomChaptersFromDB() ([]*CustomChapter, error) {
+	contextError := "error getting custom manga '%s' chapters from DB"
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, m), err)
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, m), err)
+	}
+	defer tx.Rollback()
+
+	chapters, err := getCustomChapters(m.ID, tx)
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, m), err)
+	}
+
+	return chapters, nil
+}
+
+// UpsertCustomChaptersInDB adds the chapters to the custom manga chapter list,
+// or updates them if they're already in the list. It also updates the custom
+// manga's last released chapter to the last chapter of the list.
+func (m *Manga) UpsertCustomChaptersInDB(chapters []*CustomChapter) error {
+	contextError := "error upserting %d chapters into custom manga '%s' chapter list in DB"
+
+	if m.Source != CustomMangaSource {
+		return util.AddErrorContext(fmt.Sprintf(contextError, len(chapters), m), fmt.Errorf("manga is not a custom manga"))
+	}
+
+	err := m.updateCustomChaptersInDB(func(tx *sql.Tx) error {
+		for _, chapter := range chapters {
+			if chapter.Chapter == "" || chapter.Name == "" {
+				return fmt.Errorf("chapter and name can't be empty")
+			}
+			_, err := tx.Exec(`
+                INSERT INTO custom_manga_chapters (manga_id, chapter, name, url, released_at)
+                VALUES ($1, $2, $3, $4, $5)
+                ON CONFLICT (manga_id, chapter)
+                DO UPDATE
+                    SET name = EXCLUDED.name, url = EXCLUDED.url, released_at = EXCLUDED.released_at;
+            `, m.ID, chapter.Chapter, chapter.Name, chapter.URL, chapter.ReleasedAt)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf(contextError, len(chapters), m), err)
+	}
+
+	return nil
+}
+
+// DeleteCustomChapterFromDB deletes the chapter from the custom manga chapter list.
+// It also updates the custom manga's last released chapter to the last chapter of the list.
+// If the list is empty after deleting the chapter, the last released chapter is kept.
+func (m *Manga) DeleteCustomChapterFromDB(chapter string) error {
+	contextError := "error deleting chapter '%s' from custom manga '%s' chapter list in DB"
+
+	err := m.updateCustomChaptersInDB(func(tx *sql.Tx) error {
+		result, err := tx.Exec(`
+            DELETE FROM custom_manga_chapters
+            WHERE manga_id = $1 AND chapter = $2;
+        `, m.ID, chapter)
+		if err != nil {
+			return err
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return errordefs.ErrCustomChapterNotFoundDB
+		}
+
+		return nil
+	})
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf(contextError, chapter, m), err)
+	}
+
+	return nil
+}
+
+// updateCustomChaptersInDB runs update and then sets the custom manga's
+// last released chapter to the last chapter of the list in one transaction.
+func (m *Manga) updateCustomChaptersInDB(update func(tx *sql.Tx) error) error {
+	db, err := db.OpenConn()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+
+	err = update(tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	chapters, err := getCustomChapters(m.ID, tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var lastReleasedChapter *Chapter
+	if len(chapters) > 0 {
+		lastChapter := chapters[len(chapters)-1]
+		lastReleasedChapter = &Chapter{
+			Chapter:   lastChapter.Chapter,
+			Name:      lastChapter.Name,
+			URL:       lastChapter.URL,
+			UpdatedAt: lastChapter.ReleasedAt,
+			Type:      1,
+		}
+		err = upsertMangaChapter(m.ID, lastReleasedChapter, tx)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+	if lastReleasedChapter != nil {
+		m.LastReleasedChapter = lastReleasedChapter
+	}
+
+	return nil
+}
+
+func getCustomChapters(mangaID ID, tx *sql.Tx) ([]*CustomChapter, error) {
+	rows, err := tx.Query(`
+        SELECT
+            chapter, name, url, released_at
+        FROM
+            custom_manga_chapters
+        WHERE
+            manga_id = $1;
+    `, mangaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	chapters := []*CustomChapter{}
+	for rows.Next() {
+		var chapter CustomChapter
+		err = rows.Scan(&chapter.Chapter, &chapter.Name, &chapter.URL, &chapter.ReleasedAt)
+		if err != nil {
+			return nil, err
+		}
+		chapters = append(chapters, &chapter)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	SortCustomChapters(chapters)
+
+	return chapters, nil
+}
+
+// hasCustomChapters returns true if the custom manga has a chapter list.
+func hasCustomChapters(mangaID ID, tx *sql.Tx) (bool, error) {
+	var exists bool
+	err := tx.QueryRow(`
+        SELECT EXISTS (SELECT 1 FROM custom_manga_chapters WHERE manga_id = $1);
+    `, mangaID).Scan(&exists)
+
+	return exists, err
+}
+
+// SortCustomChapters sorts the chapters from the first to the last chapter
+// by their parsed chapter number, else by release date.
+func SortCustomChapters(chapters []*CustomChapter) {
+	sort.SliceStable(chapters, func(i, j int) bool {
+		cmp, _ := CompareChapters(
+			&Chapter{Chapter: chapters[i].Chapter, UpdatedAt: chapters[i].ReleasedAt},
+			&Chapter{Chapter: chapters[j].Chapter, UpdatedAt: chapters[j].ReleasedAt},
+		)
+		return cmp < 0
+	})
+}
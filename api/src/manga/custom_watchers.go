@@ -0,0 +1,206 @@
+package manga
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/diogovalentte/mantium/api/src/db"
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// CustomMangaWatcher checks a page for new chapters of a custom manga,
+// like the custom manga's page in a site Mantium doesn't support.
+// The chapters are found in the page with a CSS selector, a regex, or both.
+type CustomMangaWatcher struct {
+	// LastCheckedAt is when the page was last checked. It's zero if it was never checked.
+	LastCheckedAt time.Time `json:"last_checked_at"`
+	URL           string    `json:"url"`
+	// Selector is the CSS selector of the elements with the chapters, like "ul.chapters a".
+	// If it's empty, the whole page text is used.
+	Selector string `json:"selector"`
+	// Regex is matched against the text of the selected elements to get the chapters.
+	// If it has a capturing group, the first group is the chapter, else the whole match.
+	// If it's empty, the text of each element is a chapter.
+	Regex string `json:"regex"`
+	// LastChapter is the last chapter found in the page.
+	LastChapter string `json:"last_chapter"`
+	// LastError is the error of the last check, if it failed.
+	LastError string `json:"last_error"`
+	MangaID   ID     `json:"manga_id"`
+}
+
+// UpsertIntoDB inserts the watcher into the database, or updates the
+// watcher of the custom manga. The last check result is reset.
+func (w *CustomMangaWatcher) UpsertIntoDB() error {
+	contextError := "error upserting custom manga '%d' watcher into DB"
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf(contextError, w.MangaID), err)
+	}
+	defer db.Close()
+
+	_, err = db.Exec(`
+        INSERT INTO custom_manga_watchers (manga_id, url, selector, regex)
+        VALUES ($1, $2, $3, $4)
+        ON CONFLICT (manga_id)
+        DO UPDATE
+            SET url = EXCLUDED.url, selector = EXCLUDED.selector, regex = EXCLUDED.regex,
+                last_chapter = '', last_error = '', last_checked_at = NULL;
+    `, w.MangaID, w.URL, w.Selector, w.Regex)
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf(contextError, w.MangaID), err)
+	}
+	w.LastChapter = ""
+	w.LastError = ""
+	w.LastCheckedAt = time.Time{}
+
+	return nil
+}
+
+// UpdateCheckResultInDB saves the result of a check of the watched page.
+// If the check failed, the last chapter found is kept.
+func (w *CustomMangaWatcher) UpdateCheckResultInDB(checkedAt time.Time, lastChapter string, checkErr error) error {
+	contextError := "error updating custom manga '%d' watcher check result in DB"
+
+	if checkErr == nil {
+		w.LastChapter = lastChapter
+		w.LastError = ""
+	} else {
+		w.LastError = checkErr.Error()
+	}
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf(contextError, w.MangaID), err)
+	}
+	defer db.Close()
+
+	_, err = db.Exec(`
+        UPDATE custom_manga_watchers
+        SET last_chapter = $1, last_error = $2, last_checked_at = $3
+        WHERE manga_id = $4;
+    `, w.LastChapter, w.LastError, checkedAt, w.MangaID)
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf(contextError, w.MangaID), err)
+	}
+	w.LastCheckedAt = checkedAt
+
+	return nil
+}
+
+// DeleteCustomMangaWatcherDB deletes the watcher of the custom manga from the database.
+func DeleteCustomMangaWatcherDB(mangaID ID) error {
+	contextError := "error deleting custom manga '%d' watcher from DB"
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf(contextError, mangaID), err)
+	}
+	defer db.Close()
+
+	result, err := db.Exec(`
+        DELETE FROM custom_manga_watchers
+        WHERE manga_id = $1;
+    `, mangaID)
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf(contextError, mangaID), err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf(contextError, mangaID), err)
+	}
+	if rowsAffected == 0 {
+		return util.AddErrorContext(fmt.Sprintf(contextError, mangaID), errordefs.ErrCustomMangaWatcherNotFoundDB)
+	}
+
+	return nil
+}
+
+// GetCustomMangaWatcherDB returns the watcher of the custom manga from the database.
+func GetCustomMangaWatcherDB(mangaID ID) (*CustomMangaWatcher, error) {
+	contextError := "error getting custom manga '%d' watcher from DB"
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, mangaID), err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`
+        SELECT
+            `+customMangaWatcherColumns+`
+        FROM
+            custom_manga_watchers
+        WHERE
+            manga_id = $1;
+    `, mangaID)
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, mangaID), err)
+	}
+	defer rows.Close()
+
+	watchers, err := scanCustomMangaWatchers(rows)
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, mangaID), err)
+	}
+	if len(watchers) == 0 {
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, mangaID), errordefs.ErrCustomMangaWatcherNotFoundDB)
+	}
+
+	return watchers[0], nil
+}
+
+// GetCustomMangaWatchersDB returns all custom manga watchers from the database.
+func GetCustomMangaWatchersDB() ([]*CustomMangaWatcher, error) {
+	contextError := "error getting custom manga watchers from DB"
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return nil, util.AddErrorContext(contextError, err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`
+        SELECT
+            ` + customMangaWatcherColumns + `
+        FROM
+            custom_manga_watchers
+        ORDER BY
+            manga_id;
+    `)
+	if err != nil {
+		return nil, util.AddErrorContext(contextError, err)
+	}
+	defer rows.Close()
+
+	watchers, err := scanCustomMangaWatchers(rows)
+	if err != nil {
+		return nil, util.AddErrorContext(contextError, err)
+	}
+
+	return watchers, nil
+}
+
+const customMangaWatcherColumns = "manga_id, url, selector, regex, last_chapter, last_error, last_checked_at"
+
+func scanCustomMangaWatchers(rows *sql.Rows) ([]*CustomMangaWatcher, error) {
+	watchers := []*CustomMangaWatcher{}
+	for rows.Next() {
+		var w CustomMangaWatcher
+		var lastCheckedAt sql.NullTime
+		err := rows.Scan(&w.MangaID, &w.URL, &w.Selector, &w.Regex, &w.LastChapter, &w.LastError, &lastCheckedAt)
+		if err != nil {
+			return nil, err
+		}
+		w.LastCheckedAt = lastCheckedAt.Time
+		watchers = append(watchers, &w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return watchers, nil
+}
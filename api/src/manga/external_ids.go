@@ -0,0 +1,390 @@
+package manga
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/lib/pq"
+
+	"github.com/diogovalentte/mantium/api/src/db"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// ExternalIDs are the IDs of a multimanga's work in sites that identify works
+// regardless of the source, like MangaUpdates and AniList. They're used to know
+// when mangas from different sources are the same work.
+type ExternalIDs struct {
+	// ResolvedAt is when the IDs were last resolved from the sources. It's zero if they never were.
+	ResolvedAt time.Time `json:"resolved_at"`
+	// MangaUpdatesID is the MangaUpdates series ID, like the MangaUpdates source's manga internal ID.
+	MangaUpdatesID string `json:"mangaupdates_id"`
+	AniListID      string `json:"anilist_id"`
+	MALID          string `json:"mal_id"`
+	// ManualIDs are the names of the IDs set or removed by the user, like "anilist_id".
+	// They aren't filled when the IDs are resolved from the sources.
+	ManualIDs    []string `json:"manual_ids"`
+	MultiMangaID ID       `json:"multimanga_id"`
+}
+
+// IsEmpty returns true if the multimanga has no external ID.
+func (ids *ExternalIDs) IsEmpty() bool {
+	return ids == nil || (ids.MangaUpdatesID == "" && ids.AniListID == "" && ids.MALID == "")
+}
+
+// IsComplete returns true if the multimanga has all external IDs,
+// or the missing ones were removed by the user.
+func (ids *ExternalIDs) IsComplete() bool {
+	if ids == nil {
+		return false
+	}
+	for _, field := range ids.fields() {
+		if *field.id == "" && !ids.IsManual(field.name) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsManual returns true if the ID with the name, like "anilist_id", was set or removed by the user.
+func (ids *ExternalIDs) IsManual(name string) bool {
+	return slices.Contains(ids.ManualIDs, name)
+}
+
+// SetManual sets whether the ID with the name, like "anilist_id", was set or removed by the user.
+func (ids *ExternalIDs) SetManual(name string, manual bool) {
+	ids.ManualIDs = slices.DeleteFunc(ids.ManualIDs, func(n string) bool { return n == name })
+	if manual {
+		ids.ManualIDs = append(ids.ManualIDs, name)
+		sort.Strings(ids.ManualIDs)
+	}
+}
+
+type externalIDField struct {
+	id   *string
+	name string
+}
+
+// fields returns the IDs by their names.
+func (ids *ExternalIDs) fields() []externalIDField {
+	return []externalIDField{
+		{&ids.MangaUpdatesID, "mangaupdates_id"},
+		{&ids.AniListID, "anilist_id"},
+		{&ids.MALID, "mal_id"},
+	}
+}
+
+// Merge sets the empty IDs to the other IDs. The IDs already set and the
+// IDs set or removed by the user aren't replaced by the sources' IDs.
+// It returns true if any ID was set.
+func (ids *ExternalIDs) Merge(other *ExternalIDs) bool {
+	if other == nil {
+		return false
+	}
+
+	var merged bool
+	otherFields := other.fields()
+	for i, field := range ids.fields() {
+		if otherID := *otherFields[i].id; *field.id == "" && otherID != "" && !ids.IsManual(field.name) {
+			*field.id = otherID
+			merged = true
+		}
+	}
+
+	return merged
+}
+
+// URLs returns the URLs of the multimanga's work in the sites of its external IDs, by site.
+// The MangaUpdates URL can be added to the multimanga to track the work in the MangaUpdates source.
+func (ids *ExternalIDs) URLs() map[string]string {
+	urls := map[string]string{}
+	if seriesID, err := strconv.ParseInt(ids.MangaUpdatesID, 10, 64); err == nil {
+		urls["mangaupdates"] = "https://www.mangaupdates.com/series/" + strconv.FormatInt(seriesID, 36)
+	}
+	if ids.AniListID != "" {
+		urls["anilist"] = "https://anilist.co/manga/" + ids.AniListID
+	}
+	if ids.MALID != "" {
+		urls["mal"] = "https://myanimelist.net/manga/" + ids.MALID
+	}
+
+	return urls
+}
+
+// ExternalIDsFromLinks returns the external IDs in the links of a manga
+// in MangaDex or ComicK, like {"mu": "pb8uwds", "al": "30002", "mal": "2"}.
+// The MangaUpdates IDs in the links are in base 36, as in the MangaUpdates URLs.
+// The old numeric MangaUpdates IDs are ignored, as they're not the series IDs.
+func ExternalIDsFromLinks(links map[string]string) *ExternalIDs {
+	ids := &ExternalIDs{}
+	if mu := strings.TrimSpace(links["mu"]); mu != "" && strings.IndexFunc(mu, unicode.IsLetter) != -1 {
+		if seriesID, err := strconv.ParseInt(mu, 36, 64); err == nil {
+			ids.MangaUpdatesID = strconv.FormatInt(seriesID, 10)
+		}
+	}
+	if al := strings.TrimSpace(links["al"]); isNumericID(al) {
+		ids.AniListID = al
+	}
+	if mal := strings.TrimSpace(links["mal"]); isNumericID(mal) {
+		ids.MALID = mal
+	}
+
+	return ids
+}
+
+var mangaUpdatesURLRegex = regexp.MustCompile(`mangaupdates\.com/series/([0-9a-zA-Z]+)`)
+
+// MangaUpdatesIDFromURL returns the MangaUpdates series ID of a MangaUpdates
+// series URL, like https://www.mangaupdates.com/series/pb8uwds/berserk.
+// It returns an empty string if the URL isn't a MangaUpdates series URL.
+func MangaUpdatesIDFromURL(mangaURL string) string {
+	matches := mangaUpdatesURLRegex.FindStringSubmatch(mangaURL)
+	if len(matches) < 2 {
+		return ""
+	}
+	seriesID, err := strconv.ParseInt(strings.ToLower(matches[1]), 36, 64)
+	if err != nil {
+		return ""
+	}
+
+	return strconv.FormatInt(seriesID, 10)
+}
+
+func isNumericID(id string) bool {
+	if id == "" {
+		return false
+	}
+	_, err := strconv.ParseUint(id, 10, 64)
+
+	return err == nil
+}
+
+// DuplicateGroup is a group of multimangas that seem to be the same work.
+type DuplicateGroup struct {
+	// Reasons are why the multimangas are grouped, like "mangaupdates_id: 1" or "name: berserk".
+	Reasons       []string `json:"reasons"`
+	MultiMangaIDs []ID     `json:"multimanga_ids"`
+	// Names are the multimangas names, in the same order as the IDs.
+	Names []string `json:"names"`
+}
+
+// FindDuplicates groups the multimangas that have the same external IDs or the
+// same normalized name, by their ID. The multimangas without duplicates aren't returned.
+func FindDuplicates(names map[ID]string, externalIDs map[ID]*ExternalIDs) []*DuplicateGroup {
+	multimangaIDs := make([]ID, 0, len(names))
+	for id := range names {
+		multimangaIDs = append(multimangaIDs, id)
+	}
+	for id := range externalIDs {
+		if _, ok := names[id]; !ok {
+			multimangaIDs = append(multimangaIDs, id)
+		}
+	}
+	sort.Slice(multimangaIDs, func(i, j int) bool { return multimangaIDs[i] < multimangaIDs[j] })
+
+	// Union-find of the multimangas that share a key
+	parents := map[ID]ID{}
+	var find func(id ID) ID
+	find = func(id ID) ID {
+		if parents[id] != id {
+			parents[id] = find(parents[id])
+		}
+		return parents[id]
+	}
+	keysOwner := map[string]ID{}
+	sharedKeys := map[string]bool{}
+	for _, id := range multimangaIDs {
+		parents[id] = id
+	}
+	for _, id := range multimangaIDs {
+		keys := []string{}
+		if name := util.NormalizeName(names[id]); name != "" {
+			keys = append(keys, "name: "+name)
+		}
+		if ids := externalIDs[id]; ids != nil {
+			if ids.MangaUpdatesID != "" {
+				keys = append(keys, "mangaupdates_id: "+ids.MangaUpdatesID)
+			}
+			if ids.AniListID != "" {
+				keys = append(keys, "anilist_id: "+ids.AniListID)
+			}
+			if ids.MALID != "" {
+				keys = append(keys, "mal_id: "+ids.MALID)
+			}
+		}
+		for _, key := range keys {
+			owner, ok := keysOwner[key]
+			if !ok {
+				keysOwner[key] = id
+				continue
+			}
+			sharedKeys[key] = true
+			if rootID, rootOwner := find(id), find(owner); rootID != rootOwner {
+				if rootID < rootOwner {
+					parents[rootOwner] = rootID
+				} else {
+					parents[rootID] = rootOwner
+				}
+			}
+		}
+	}
+
+	groups := map[ID]*DuplicateGroup{}
+	roots := []ID{}
+	for _, id := range multimangaIDs {
+		root := find(id)
+		if _, ok := groups[root]; !ok {
+			groups[root] = &DuplicateGroup{Reasons: []string{}, MultiMangaIDs: []ID{}, Names: []string{}}
+			roots = append(roots, root)
+		}
+		groups[root].MultiMangaIDs = append(groups[root].MultiMangaIDs, id)
+		groups[root].Names = append(groups[root].Names, names[id])
+	}
+	for key := range sharedKeys {
+		group := groups[find(keysOwner[key])]
+		group.Reasons = append(group.Reasons, key)
+	}
+
+	duplicates := []*DuplicateGroup{}
+	for _, root := range roots {
+		if group := groups[root]; len(group.MultiMangaIDs) > 1 {
+			sort.Strings(group.Reasons)
+			duplicates = append(duplicates, group)
+		}
+	}
+
+	return duplicates
+}
+
+// UpsertIntoDB saves the multimanga external IDs in the database.
+func (ids *ExternalIDs) UpsertIntoDB() error {
+	contextError := "error upserting multimanga '%d' external IDs into DB"
+
+	for _, field := range ids.fields() {
+		if *field.id != "" && !isNumericID(*field.id) {
+			return util.AddErrorContext(fmt.Sprintf(contextError, ids.MultiMangaID), fmt.Errorf("%s should be a number", field.name))
+		}
+	}
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf(contextError, ids.MultiMangaID), err)
+	}
+	defer db.Close()
+
+	var resolvedAt *time.Time
+	if !ids.ResolvedAt.IsZero() {
+		resolvedAt = &ids.ResolvedAt
+	}
+	if ids.ManualIDs == nil {
+		ids.ManualIDs = []string{}
+	}
+	_, err = db.Exec(`
+        INSERT INTO multimanga_external_ids (multimanga_id, mangaupdates_id, anilist_id, mal_id, resolved_at, manual_ids)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        ON CONFLICT (multimanga_id)
+        DO UPDATE
+            SET mangaupdates_id = EXCLUDED.mangaupdates_id, anilist_id = EXCLUDED.anilist_id,
+                mal_id = EXCLUDED.mal_id, resolved_at = EXCLUDED.resolved_at, manual_ids = EXCLUDED.manual_ids;
+    `, ids.MultiMangaID, ids.MangaUpdatesID, ids.AniListID, ids.MALID, resolvedAt, pq.Array(ids.ManualIDs))
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf(contextError, ids.MultiMangaID), err)
+	}
+
+	return nil
+}
+
+// GetExternalIDsDB returns the multimanga external IDs from the database.
+// If the multimanga doesn't have external IDs, empty IDs are returned.
+func GetExternalIDsDB(multimangaID ID) (*ExternalIDs, error) {
+	contextError := "error getting multimanga '%d' external IDs from DB"
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, multimangaID), err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`
+        SELECT
+            `+externalIDsColumns+`
+        FROM
+            multimanga_external_ids
+        WHERE
+            multimanga_id = $1;
+    `, multimangaID)
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, multimangaID), err)
+	}
+	defer rows.Close()
+
+	externalIDs, err := scanExternalIDs(rows)
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, multimangaID), err)
+	}
+	if ids, ok := externalIDs[multimangaID]; ok {
+		return ids, nil
+	}
+
+	return &ExternalIDs{MultiMangaID: multimangaID, ManualIDs: []string{}}, nil
+}
+
+// GetAllExternalIDsDB returns the external IDs of all multimangas
+// that have external IDs from the database, by multimanga ID.
+func GetAllExternalIDsDB() (map[ID]*ExternalIDs, error) {
+	contextError := "error getting all multimangas external IDs from DB"
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return nil, util.AddErrorContext(contextError, err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`
+        SELECT
+            ` + externalIDsColumns + `
+        FROM
+            multimanga_external_ids;
+    `)
+	if err != nil {
+		return nil, util.AddErrorContext(contextError, err)
+	}
+	defer rows.Close()
+
+	externalIDs, err := scanExternalIDs(rows)
+	if err != nil {
+		return nil, util.AddErrorContext(contextError, err)
+	}
+
+	return externalIDs, nil
+}
+
+const externalIDsColumns = `multimanga_id, mangaupdates_id, anilist_id, mal_id, resolved_at, manual_ids`
+
+func scanExternalIDs(rows *sql.Rows) (map[ID]*ExternalIDs, error) {
+	externalIDs := map[ID]*ExternalIDs{}
+	for rows.Next() {
+		ids := &ExternalIDs{}
+		var resolvedAt sql.NullTime
+		err := rows.Scan(&ids.MultiMangaID, &ids.MangaUpdatesID, &ids.AniListID, &ids.MALID, &resolvedAt, pq.Array(&ids.ManualIDs))
+		if err != nil {
+			return nil, err
+		}
+		if resolvedAt.Valid {
+			ids.ResolvedAt = resolvedAt.Time
+		}
+		externalIDs[ids.MultiMangaID] = ids
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return externalIDs, nil
+}
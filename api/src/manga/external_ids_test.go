@@ -0,0 +1,119 @@
+package manga
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExternalIDsFromLinks(t *testing.T) {
+	ids := ExternalIDsFromLinks(map[string]string{"mu": "pb8uwds", "al": "30002", "mal": "2", "raw": "https://example.com"})
+	expected := &ExternalIDs{MangaUpdatesID: "55099564912", AniListID: "30002", MALID: "2"}
+	if !reflect.DeepEqual(ids, expected) {
+		t.Fatalf("expected %v, got %v", expected, ids)
+	}
+
+	// The old numeric MangaUpdates IDs and invalid IDs are ignored
+	ids = ExternalIDsFromLinks(map[string]string{"mu": "88", "al": "abc"})
+	if !ids.IsEmpty() {
+		t.Fatalf("expected empty IDs, got %v", ids)
+	}
+}
+
+func TestMangaUpdatesIDFromURL(t *testing.T) {
+	tests := map[string]string{
+		"https://www.mangaupdates.com/series/pb8uwds/berserk": "55099564912",
+		"https://www.mangaupdates.com/series/pb8uwds":         "55099564912",
+		"https://mangadex.org/title/pb8uwds":                  "",
+	}
+
+	for mangaURL, expected := range tests {
+		if id := MangaUpdatesIDFromURL(mangaURL); id != expected {
+			t.Errorf("expected '%s' to have ID '%s', got '%s'", mangaURL, expected, id)
+		}
+	}
+}
+
+func TestExternalIDsMerge(t *testing.T) {
+	ids := &ExternalIDs{MangaUpdatesID: "1"}
+	if !ids.Merge(&ExternalIDs{MangaUpdatesID: "2", AniListID: "3"}) {
+		t.Fatal("expected the IDs to be merged")
+	}
+	if ids.MangaUpdatesID != "1" || ids.AniListID != "3" || ids.MALID != "" {
+		t.Fatalf("unexpected merged IDs: %v", ids)
+	}
+	if ids.Merge(&ExternalIDs{AniListID: "4"}) {
+		t.Fatal("expected no ID to be merged")
+	}
+
+	// The IDs set or removed by the user aren't filled
+	ids = &ExternalIDs{AniListID: "5", ManualIDs: []string{"anilist_id", "mal_id"}}
+	if ids.Merge(&ExternalIDs{AniListID: "3", MALID: "2"}) {
+		t.Fatal("expected the manual IDs not to be merged")
+	}
+	if ids.AniListID != "5" || ids.MALID != "" {
+		t.Fatalf("unexpected merged IDs: %v", ids)
+	}
+	if !ids.Merge(&ExternalIDs{MangaUpdatesID: "1", MALID: "2"}) || ids.MangaUpdatesID != "1" || ids.MALID != "" {
+		t.Fatalf("expected only the MangaUpdates ID to be merged, got %v", ids)
+	}
+}
+
+func TestExternalIDsManual(t *testing.T) {
+	ids := &ExternalIDs{MangaUpdatesID: "1", AniListID: "3"}
+	if ids.IsComplete() {
+		t.Fatal("expected the IDs not to be complete")
+	}
+
+	// A removed ID is manual, so it's not missing
+	ids.SetManual("mal_id", true)
+	ids.SetManual("anilist_id", true)
+	ids.SetManual("mal_id", true)
+	if !reflect.DeepEqual(ids.ManualIDs, []string{"anilist_id", "mal_id"}) {
+		t.Fatalf("unexpected manual IDs: %v", ids.ManualIDs)
+	}
+	if !ids.IsComplete() {
+		t.Fatal("expected the IDs to be complete")
+	}
+
+	ids.SetManual("mal_id", false)
+	if ids.IsManual("mal_id") || !ids.IsManual("anilist_id") || ids.IsComplete() {
+		t.Fatalf("expected only the AniList ID to be manual, got %v", ids.ManualIDs)
+	}
+}
+
+func TestExternalIDsURLs(t *testing.T) {
+	urls := (&ExternalIDs{MangaUpdatesID: "55099564912", MALID: "2"}).URLs()
+	expected := map[string]string{
+		"mangaupdates": "https://www.mangaupdates.com/series/pb8uwds",
+		"mal":          "https://myanimelist.net/manga/2",
+	}
+	if !reflect.DeepEqual(urls, expected) {
+		t.Fatalf("expected %v, got %v", expected, urls)
+	}
+}
+
+func TestFindDuplicates(t *testing.T) {
+	names := map[ID]string{
+		1: "Berserk",
+		2: "Berserk!",
+		3: "Vagabond",
+		4: "Vagabond (Official)",
+		5: "Kingdom",
+		6: "Claymore",
+	}
+	externalIDs := map[ID]*ExternalIDs{
+		3: {MultiMangaID: 3, MangaUpdatesID: "10"},
+		4: {MultiMangaID: 4, MangaUpdatesID: "10", AniListID: "20"},
+		6: {MultiMangaID: 6, AniListID: "20"},
+		5: {MultiMangaID: 5, AniListID: "30"},
+	}
+
+	duplicates := FindDuplicates(names, externalIDs)
+	expected := []*DuplicateGroup{
+		{Reasons: []string{"name: berserk"}, MultiMangaIDs: []ID{1, 2}, Names: []string{"Berserk", "Berserk!"}},
+		{Reasons: []string{"anilist_id: 20", "mangaupdates_id: 10"}, MultiMangaIDs: []ID{3, 4, 6}, Names: []string{"Vagabond", "Vagabond (Official)", "Claymore"}},
+	}
+	if !reflect.DeepEqual(duplicates, expected) {
+		t.Fatalf("expected %v, got %v", expected, duplicates)
+	}
+}
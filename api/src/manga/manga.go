@@ -2,10 +2,13 @@
 package manga
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"sort"
 
+	"github.com/lib/pq"
+
 	"github.com/diogovalentte/mantium/api/src/db"
 	"github.com/diogovalentte/mantium/api/src/errordefs"
 	"github.com/diogovalentte/mantium/api/src/util"
@@ -23,6 +26,15 @@ type (
 	Status int
 )
 
+// The manga/multimanga statuses.
+const (
+	StatusReading Status = iota + 1
+	StatusCompleted
+	StatusOnHold
+	StatusDropped
+	StatusPlanToRead
+)
+
 const (
 	// CustomMangaSource is the source of custom mangas.
 	CustomMangaSource = "custom_manga"
@@ -51,7 +63,8 @@ type Manga struct {
 	// InteralID is a unique identifier for the manga in the source
 	InternalID string
 	// PreferredGroup is the preferred group that translates (and more) the manga.
-	// Not all sources have multiple groups. Currently not used.
+	// Not all sources have multiple groups. It's the most preferred group
+	// of the manga's ChapterPreferences, which are used to choose the chapters.
 	PreferredGroup string
 	// CoverImgURL is the URL of the cover image
 	CoverImgURL string
@@ -73,6 +86,12 @@ type Manga struct {
 	// CoverImgFixed is true if the cover image is fixed. If true, the cover image will not be updated when updating the manga metadata.
 	// It's used for when the cover image is manually set by the user.
 	CoverImgFixed bool
+	// UnreadChapters is the number of unread chapters. It's only set in list responses.
+	// For a multimanga's current manga, it's the multimanga's unread chapters.
+	UnreadChapters int
+	// Tags are the user's tags of a custom manga. For a multimanga's
+	// current manga, they're the multimanga's tags. It's only set in list responses.
+	Tags []string
 }
 
 func (m Manga) String() string {
@@ -1068,6 +1087,12 @@ func getMangasWithoutMultiMangasFromDB(db *sql.DB) ([]*Manga, error) {
 
 // GetCustomMangasDB gets all custom mangas from the database
 func GetCustomMangasDB() ([]*Manga, error) {
+	return GetCustomMangasDBContext(context.Background())
+}
+
+// GetCustomMangasDBContext is like GetCustomMangasDB, but the query
+// receives ctx, so it's traced as a child of the ctx span, like a request's span.
+func GetCustomMangasDBContext(ctx context.Context) ([]*Manga, error) {
 	contextError := "error getting custom mangas from DB"
 
 	db, err := db.OpenConn()
@@ -1076,7 +1101,7 @@ func GetCustomMangasDB() ([]*Manga, error) {
 	}
 	defer db.Close()
 
-	mangas, err := getCustomMangasFromDB(db)
+	mangas, err := getCustomMangasFromDB(ctx, db)
 	if err != nil {
 		return nil, util.AddErrorContext(contextError, err)
 	}
@@ -1084,7 +1109,7 @@ func GetCustomMangasDB() ([]*Manga, error) {
 	return mangas, nil
 }
 
-func getCustomMangasFromDB(db *sql.DB) ([]*Manga, error) {
+func getCustomMangasFromDB(ctx context.Context, db *sql.DB) ([]*Manga, error) {
 	query := `
         SELECT 
             mangas.id AS manga_id,
@@ -1097,6 +1122,7 @@ func getCustomMangasFromDB(db *sql.DB) ([]*Manga, error) {
             mangas.cover_img,
             mangas.cover_img_resized,
             mangas.status,
+            mangas.tags,
             
             last_released_chapter.url AS last_released_chapter_url,
             last_released_chapter.chapter AS last_released_chapter,
@@ -1121,7 +1147,7 @@ func getCustomMangasFromDB(db *sql.DB) ([]*Manga, error) {
             mangas.source = $1
         ;
     `
-	rows, err := db.Query(query, CustomMangaSource)
+	rows, err := db.QueryContext(ctx, query, CustomMangaSource)
 	if err != nil {
 		return nil, err
 	}
@@ -1145,7 +1171,7 @@ func getCustomMangasFromDB(db *sql.DB) ([]*Manga, error) {
 		err := rows.Scan(
 			&currentManga.ID, &currentManga.Source, &currentManga.URL, &currentManga.Name,
 			&currentManga.InternalID, &currentManga.PreferredGroup, &currentManga.CoverImgURL,
-			&currentManga.CoverImg, &currentManga.CoverImgResized, &currentManga.Status,
+			&currentManga.CoverImg, &currentManga.CoverImgResized, &currentManga.Status, pq.Array(&currentManga.Tags),
 
 			&lastReleasedChapterURL, &lastReleasedChapterChapter, &lastReleasedChapterName,
 			&lastReleasedChapterInternalID, &lastReleasedChapterUpdatedAt, &lastReleasedChapterType,
@@ -1314,13 +1340,17 @@ func getLibraryStatsFromDB(db *sql.DB) (map[string]int, error) {
         ),
         unread_custom_mangas AS (
             SELECT
-                COUNT(*) AS total_mangas
+                COUNT(m.*) AS total_mangas
             FROM
-                mangas
+                mangas AS m
+            LEFT JOIN
+                chapters AS c ON c.id = m.last_read_chapter
+            LEFT JOIN
+                chapters AS cc ON cc.id = m.last_released_chapter
             WHERE
-                last_released_chapter IS NULL
-                AND last_read_chapter IS NOT NULL
-                AND source = $1
+                m.last_read_chapter IS NOT NULL
+                AND (m.last_released_chapter IS NULL OR cc.chapter <> c.chapter)
+                AND m.source = $1
         ),
         total_custom_mangas AS (
             SELECT
@@ -1354,7 +1384,9 @@ func getLibraryStatsFromDB(db *sql.DB) (map[string]int, error) {
 }
 
 // UpdateCustomMangaLastReadChapterInDB updates the last read chapter of a custom manga in the database.
-// It also needs to delete the last released chapter.
+// If the custom manga has no more chapters (the last released chapter is set), the last released
+// chapter is also updated. If the custom manga has a chapter list, the last released chapter
+// is the last chapter of the list, so it isn't changed.
 func UpdateCustomMangaLastReadChapterInDB(m *Manga, chapter *Chapter) error {
 	contextError := "error updating custom manga '%s' last read chapter to '%s' in DB"
 
@@ -1379,7 +1411,13 @@ func UpdateCustomMangaLastReadChapterInDB(m *Manga, chapter *Chapter) error {
 		return util.AddErrorContext(fmt.Sprintf(contextError, m, chapter), err)
 	}
 
-	if m.LastReleasedChapter != nil {
+	hasChapterList, err := hasCustomChapters(m.ID, tx)
+	if err != nil {
+		tx.Rollback()
+		return util.AddErrorContext(fmt.Sprintf(contextError, m, chapter), err)
+	}
+
+	if m.LastReleasedChapter != nil && !hasChapterList {
 		rChapter := *chapter
 		rChapter.Type = 1
 		err = upsertMangaChapter(m.ID, &rChapter, tx)
@@ -1516,8 +1554,8 @@ func ValidateStatus(status Status) error {
 }
 
 // FilterUnreadChapterMangas filters a list of mangas to return
-// mangas where the last released chapter is different from the
-// last read chapter
+// mangas where the last released chapter comes after the
+// last read chapter (see IsChapterUnread)
 func FilterUnreadChapterMangas(mangas []*Manga) []*Manga {
 	unreadChapterMangas := []*Manga{}
 
@@ -1525,7 +1563,7 @@ func FilterUnreadChapterMangas(mangas []*Manga) []*Manga {
 		if (manga.LastReleasedChapter != nil && manga.LastReadChapter == nil) || (manga.LastReleasedChapter == nil && manga.LastReadChapter != nil) {
 			unreadChapterMangas = append(unreadChapterMangas, manga)
 		} else if manga.LastReleasedChapter != nil && manga.LastReadChapter != nil {
-			if manga.LastReleasedChapter.Chapter != manga.LastReadChapter.Chapter {
+			if IsChapterUnread(manga.LastReleasedChapter, manga.LastReadChapter) {
 				unreadChapterMangas = append(unreadChapterMangas, manga)
 			}
 		}
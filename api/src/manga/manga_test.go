@@ -3,6 +3,7 @@ package manga
 import (
 	"fmt"
 	"os"
+	"reflect"
 	"testing"
 	"time"
 
@@ -425,6 +426,39 @@ func TestMangaWithoutChaptersDBLifeCycle(t *testing.T) {
 	})
 }
 
+func TestCustomMangaTagsDB(t *testing.T) {
+	manga := getMangaCopy(mangaTest)
+	manga.Source = CustomMangaSource
+	manga.URL = CustomMangaURLPrefix + "/tagged-manga"
+	manga.LastReleasedChapter = nil
+	manga.LastReadChapter = nil
+
+	err := manga.InsertIntoDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer manga.DeleteFromDB()
+
+	err = manga.UpdateTagsInDB([]string{"Favorite", "to read"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mangas, err := GetCustomMangasDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range mangas {
+		if m.ID == manga.ID {
+			if !reflect.DeepEqual(m.Tags, manga.Tags) {
+				t.Fatalf("expected tags %v, got %v", manga.Tags, m.Tags)
+			}
+			return
+		}
+	}
+	t.Fatal("custom manga not found in the custom mangas")
+}
+
 func getMangaCopy(source *Manga) *Manga {
 	manga := *source
 	if source.LastReleasedChapter != nil {
@@ -1,12 +1,14 @@
 package manga
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
-	"strconv"
 	"strings"
 
+	"github.com/lib/pq"
+
 	"github.com/diogovalentte/mantium/api/src/db"
 	"github.com/diogovalentte/mantium/api/src/errordefs"
 	"github.com/diogovalentte/mantium/api/src/util"
@@ -30,11 +32,18 @@ type MultiManga struct {
 	// Else, use the multimanga's cover image fields.
 	// It's used for when the cover image is manually set by the user.
 	CoverImgFixed bool
+	// RereadCount is how many times the user started rereading the multimanga.
+	RereadCount int
+	// UnreadChapters is the number of unread chapters stored in the multimanga_chapters table
+	// or, if greater, estimated from the last released and last read chapters' numbers.
+	UnreadChapters int
+	// Tags are the user's tags, like "favorite" or "weekly". They're lower case and sorted.
+	Tags []string
 }
 
 func (mm MultiManga) String() string {
-	returnStr := fmt.Sprintf("MultiManga{ID: %d, Status: %d, CoverImg: []byte, CoverImgResized: %v, CoverImgURL: %s, CoverImgFixed: %v, LastReadChapter: %s, CurrentManga: %s, Mangas: [",
-		mm.ID, mm.Status, mm.CoverImgResized, mm.CoverImgURL, mm.CoverImgFixed, mm.LastReadChapter, mm.CurrentManga)
+	returnStr := fmt.Sprintf("MultiManga{ID: %d, Status: %d, CoverImg: []byte, CoverImgResized: %v, CoverImgURL: %s, CoverImgFixed: %v, RereadCount: %d, UnreadChapters: %d, Tags: %v, LastReadChapter: %s, CurrentManga: %s, Mangas: [",
+		mm.ID, mm.Status, mm.CoverImgResized, mm.CoverImgURL, mm.CoverImgFixed, mm.RereadCount, mm.UnreadChapters, mm.Tags, mm.LastReadChapter, mm.CurrentManga)
 
 	for _, manga := range mm.Mangas {
 		returnStr += manga.String() + ", "
@@ -524,6 +533,12 @@ func (mm *MultiManga) RemoveManga(m *Manga) error {
 
 // GetMultiMangaFromDB gets a multimanga from the database by its ID
 func GetMultiMangaFromDB(multimangaID ID) (*MultiManga, error) {
+	return GetMultiMangaFromDBContext(context.Background(), multimangaID)
+}
+
+// GetMultiMangaFromDBContext is like GetMultiMangaFromDB, but the queries
+// receive ctx, so they are traced as children of the ctx span, like a request's span.
+func GetMultiMangaFromDBContext(ctx context.Context, multimangaID ID) (*MultiManga, error) {
 	contextError := "error getting multimanga with ID '%d' from DB"
 
 	db, err := db.OpenConn()
@@ -532,7 +547,7 @@ func GetMultiMangaFromDB(multimangaID ID) (*MultiManga, error) {
 	}
 	defer db.Close()
 
-	mm, err := getMultiMangaFromDB(multimangaID, db)
+	mm, err := getMultiMangaFromDB(ctx, multimangaID, db)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, util.AddErrorContext(fmt.Sprintf(contextError, multimangaID), errordefs.ErrMultiMangaNotFoundDB)
@@ -547,6 +562,12 @@ func GetMultiMangaFromDB(multimangaID ID) (*MultiManga, error) {
 // If getMangas is false, gets only the multimanga's current manga. Also add it to the multimanga.Mangas slice.
 // If true, gets all mangas in the multimanga, and set one of them as the current manga (slow).
 func GetMultiMangasDB(getMangas bool) ([]*MultiManga, error) {
+	return GetMultiMangasDBContext(context.Background(), getMangas)
+}
+
+// GetMultiMangasDBContext is like GetMultiMangasDB, but the queries
+// receive ctx, so they are traced as children of the ctx span, like a request's span.
+func GetMultiMangasDBContext(ctx context.Context, getMangas bool) ([]*MultiManga, error) {
 	contextError := "error getting multimangas from DB"
 
 	db, err := db.OpenConn()
@@ -557,9 +578,9 @@ func GetMultiMangasDB(getMangas bool) ([]*MultiManga, error) {
 
 	var multimangas []*MultiManga
 	if !getMangas {
-		multimangas, err = getMultiMangasWithoutMangasDB(db)
+		multimangas, err = getMultiMangasWithoutMangasDB(ctx, db)
 	} else {
-		multimangas, err = getMultiMangasWithMangasDB(db)
+		multimangas, err = getMultiMangasWithMangasDB(ctx, db)
 	}
 	if err != nil {
 		return nil, util.AddErrorContext(contextError, err)
@@ -568,7 +589,7 @@ func GetMultiMangasDB(getMangas bool) ([]*MultiManga, error) {
 	return multimangas, nil
 }
 
-func getMultiMangasWithoutMangasDB(db *sql.DB) ([]*MultiManga, error) {
+func getMultiMangasWithoutMangasDB(ctx context.Context, db *sql.DB) ([]*MultiManga, error) {
 	query := `
         SELECT 
             mm.id AS multimanga_id,
@@ -577,6 +598,9 @@ func getMultiMangasWithoutMangasDB(db *sql.DB) ([]*MultiManga, error) {
             mm.cover_img_url AS multimanga_cover_img_url,
             mm.cover_img_resized AS multimanga_cover_img_resized,
             mm.cover_img_fixed AS multimanga_cover_img_fixed,
+            mm.reread_count AS multimanga_reread_count,
+            mm.tags AS multimanga_tags,
+            (SELECT COUNT(*) FROM multimanga_chapters AS mmc WHERE mmc.multimanga_id = mm.id AND NOT mmc.read) AS multimanga_unread_chapters,
 
             -- current manga
             cm.id AS manga_id,
@@ -618,7 +642,7 @@ func getMultiMangasWithoutMangasDB(db *sql.DB) ([]*MultiManga, error) {
         LEFT JOIN
             chapters AS last_read_chapter ON last_read_chapter.id = mm.last_read_chapter
         GROUP BY
-            mm.id, cm.id, mm.status, mm.cover_img, mm.cover_img_url, mm.cover_img_resized, mm.cover_img_fixed,
+            mm.id, cm.id, mm.status, mm.cover_img, mm.cover_img_url, mm.cover_img_resized, mm.cover_img_fixed, mm.reread_count, mm.tags,
             cm.source, cm.url, cm.name, cm.internal_id, cm.preferred_group, cm.cover_img_url, cm.cover_img, cm.cover_img_resized,
             last_released_chapter.url, last_released_chapter.chapter, last_released_chapter.name, last_released_chapter.internal_id,
             last_released_chapter.updated_at, last_released_chapter.type,
@@ -626,7 +650,7 @@ func getMultiMangasWithoutMangasDB(db *sql.DB) ([]*MultiManga, error) {
             last_read_chapter.updated_at, last_read_chapter.type
     ;
     `
-	rows, err := db.Query(query)
+	rows, err := db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -657,6 +681,9 @@ func getMultiMangasWithoutMangasDB(db *sql.DB) ([]*MultiManga, error) {
 			&multimanga.CoverImgURL,
 			&multimanga.CoverImgResized,
 			&multimanga.CoverImgFixed,
+			&multimanga.RereadCount,
+			pq.Array(&multimanga.Tags),
+			&multimanga.UnreadChapters,
 			&currentManga.ID,
 			&currentManga.Source,
 			&currentManga.URL,
@@ -715,6 +742,7 @@ func getMultiMangasWithoutMangasDB(db *sql.DB) ([]*MultiManga, error) {
 		currentManga.Status = multimanga.Status
 		multimanga.CurrentManga = &currentManga
 		multimanga.Mangas = append(multimanga.Mangas, &currentManga)
+		setMultiMangaUnreadChapters(&multimanga)
 
 		err = validateMultiManga(&multimanga)
 		if err != nil {
@@ -727,7 +755,7 @@ func getMultiMangasWithoutMangasDB(db *sql.DB) ([]*MultiManga, error) {
 	return multiMangas, nil
 }
 
-func getMultiMangasWithMangasDB(db *sql.DB) ([]*MultiManga, error) {
+func getMultiMangasWithMangasDB(ctx context.Context, db *sql.DB) ([]*MultiManga, error) {
 	query := `
         SELECT 
             multimangas.id AS multimanga_id,
@@ -737,6 +765,9 @@ func getMultiMangasWithMangasDB(db *sql.DB) ([]*MultiManga, error) {
             multimangas.cover_img_resized AS multimanga_cover_img_resized,
             multimangas.cover_img_fixed AS multimanga_cover_img_fixed,
             multimangas.current_manga AS multimanga_current_manga,
+            multimangas.reread_count AS multimanga_reread_count,
+            multimangas.tags AS multimanga_tags,
+            (SELECT COUNT(*) FROM multimanga_chapters AS mmc WHERE mmc.multimanga_id = multimangas.id AND NOT mmc.read) AS multimanga_unread_chapters,
 
             -- last read chapter
             last_read_chapter.url AS last_read_chapter_url,
@@ -753,7 +784,7 @@ func getMultiMangasWithMangasDB(db *sql.DB) ([]*MultiManga, error) {
             chapters AS last_read_chapter ON last_read_chapter.id = multimangas.last_read_chapter
     ;
     `
-	rows, err := db.Query(query)
+	rows, err := db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -780,6 +811,9 @@ func getMultiMangasWithMangasDB(db *sql.DB) ([]*MultiManga, error) {
 			&multimanga.CoverImgResized,
 			&multimanga.CoverImgFixed,
 			&currentMangaID,
+			&multimanga.RereadCount,
+			pq.Array(&multimanga.Tags),
+			&multimanga.UnreadChapters,
 			&multiLastReadChapterURL,
 			&multiLastReadChapterChapter,
 			&multiLastReadChapterName,
@@ -801,7 +835,7 @@ func getMultiMangasWithMangasDB(db *sql.DB) ([]*MultiManga, error) {
 			multimanga.LastReadChapter = &multiLastReadChapter
 		}
 
-		mangas, err := getMultiMangaMangasFromDB(multimanga.ID, db)
+		mangas, err := getMultiMangaMangasFromDB(ctx, multimanga.ID, db)
 		if err != nil {
 			return nil, err
 		}
@@ -817,6 +851,7 @@ func getMultiMangasWithMangasDB(db *sql.DB) ([]*MultiManga, error) {
 		if multimanga.CurrentManga == nil {
 			return nil, fmt.Errorf("current manga of multimanga with ID '%d' not found in DB", multimanga.ID)
 		}
+		setMultiMangaUnreadChapters(&multimanga)
 
 		err = validateMultiManga(&multimanga)
 		if err != nil {
@@ -829,7 +864,7 @@ func getMultiMangasWithMangasDB(db *sql.DB) ([]*MultiManga, error) {
 	return multiMangas, nil
 }
 
-func getMultiMangaFromDB(multimangaID ID, db *sql.DB) (*MultiManga, error) {
+func getMultiMangaFromDB(ctx context.Context, multimangaID ID, db *sql.DB) (*MultiManga, error) {
 	var currentMangaID sql.NullInt64
 	var lastReadChapterID sql.NullInt64
 
@@ -837,13 +872,14 @@ func getMultiMangaFromDB(multimangaID ID, db *sql.DB) (*MultiManga, error) {
 
 	query := `
         SELECT
-            id, status, cover_img, cover_img_resized, cover_img_url, cover_img_fixed, current_manga, last_read_chapter
+            id, status, cover_img, cover_img_resized, cover_img_url, cover_img_fixed, current_manga, last_read_chapter, reread_count, tags,
+            (SELECT COUNT(*) FROM multimanga_chapters WHERE multimanga_id = multimangas.id AND NOT read)
         FROM
             multimangas
         WHERE
             id = $1;
     `
-	err := db.QueryRow(query, multimangaID).Scan(&mm.ID, &mm.Status, &mm.CoverImg, &mm.CoverImgResized, &mm.CoverImgURL, &mm.CoverImgFixed, &currentMangaID, &lastReadChapterID)
+	err := db.QueryRowContext(ctx, query, multimangaID).Scan(&mm.ID, &mm.Status, &mm.CoverImg, &mm.CoverImgResized, &mm.CoverImgURL, &mm.CoverImgFixed, &currentMangaID, &lastReadChapterID, &mm.RereadCount, pq.Array(&mm.Tags), &mm.UnreadChapters)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, errordefs.ErrMultiMangaNotFoundDB
@@ -851,7 +887,7 @@ func getMultiMangaFromDB(multimangaID ID, db *sql.DB) (*MultiManga, error) {
 		return nil, err
 	}
 
-	mangas, err := getMultiMangaMangasFromDB(mm.ID, db)
+	mangas, err := getMultiMangaMangasFromDB(ctx, mm.ID, db)
 	if err != nil {
 		return nil, err
 	}
@@ -870,6 +906,7 @@ func getMultiMangaFromDB(multimangaID ID, db *sql.DB) (*MultiManga, error) {
 		}
 		mm.LastReadChapter = chapter
 	}
+	setMultiMangaUnreadChapters(mm)
 
 	err = validateMultiManga(mm)
 	if err != nil {
@@ -879,7 +916,7 @@ func getMultiMangaFromDB(multimangaID ID, db *sql.DB) (*MultiManga, error) {
 	return mm, nil
 }
 
-func getMultiMangaMangasFromDB(multiMangaID ID, db *sql.DB) ([]*Manga, error) {
+func getMultiMangaMangasFromDB(ctx context.Context, multiMangaID ID, db *sql.DB) ([]*Manga, error) {
 	query := `
         SELECT 
             multimangas.status AS multimanga_status,
@@ -911,7 +948,7 @@ func getMultiMangaMangasFromDB(multiMangaID ID, db *sql.DB) ([]*Manga, error) {
         WHERE 
             multimangas.id = $1
     `
-	rows, err := db.Query(query, multiMangaID)
+	rows, err := db.QueryContext(ctx, query, multiMangaID)
 	if err != nil {
 		return nil, err
 	}
@@ -1051,7 +1088,22 @@ func validateMultiManga(mm *MultiManga) error {
 	return nil
 }
 
+// setMultiMangaUnreadChapters sets the multimanga unread chapters to the
+// unread chapters stored or, if greater, the unread chapters estimated from
+// the current manga last released chapter and the multimanga last read chapter.
+// The stored chapters can miss chapters released between metadata updates.
+func setMultiMangaUnreadChapters(mm *MultiManga) {
+	if mm.CurrentManga == nil {
+		return
+	}
+	if estimated, ok := EstimateUnreadChapters(mm.CurrentManga.LastReleasedChapter, mm.LastReadChapter); ok {
+		mm.UnreadChapters = max(mm.UnreadChapters, estimated)
+	}
+}
+
 // GetLatestManga: tries to return the manga with the latest chapter.
+// The chapters are compared using their parsed chapter number (see ParseChapterNumber).
+// If the numbers can't be compared or are equal, the manga that released the chapter last is returned.
 func GetLatestManga(mangas []*Manga) (*Manga, error) {
 	if len(mangas) == 0 {
 		return nil, errordefs.ErrMultiMangaMangaListIsEmpty
@@ -1071,26 +1123,8 @@ func GetLatestManga(mangas []*Manga) (*Manga, error) {
 			continue
 		}
 
-		currentChapterInt, err := strconv.ParseFloat(currentChapter.Chapter, 64)
-		if err != nil {
-			if currentChapter.UpdatedAt.Before(newChapter.UpdatedAt) {
-				currentManga = manga
-			}
-			continue
-		}
-		newChapterInt, err := strconv.ParseFloat(newChapter.Chapter, 64)
-		if err != nil {
-			if currentChapter.UpdatedAt.Before(newChapter.UpdatedAt) {
-				currentManga = manga
-			}
-			continue
-		}
-		if currentChapterInt < newChapterInt {
-			currentManga = manga
-			continue
-		} else if currentChapterInt > newChapterInt {
-			continue
-		} else if currentChapter.UpdatedAt.Before(newChapter.UpdatedAt) {
+		cmp, _ := CompareChapters(currentChapter, newChapter)
+		if cmp < 0 || (cmp == 0 && currentChapter.UpdatedAt.Before(newChapter.UpdatedAt)) {
 			currentManga = manga
 		}
 	}
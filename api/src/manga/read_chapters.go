@@ -0,0 +1,525 @@
+package manga
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/diogovalentte/mantium/api/src/db"
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// ReadChapter is a chapter of a multimanga with the user's read state.
+// The chapters are stored by chapter number, so the same chapter from
+// different mangas of the multimanga is stored only once.
+type ReadChapter struct {
+	// ReleasedAt is when the chapter was released by the source.
+	ReleasedAt time.Time `json:"released_at"`
+	// ReadAt is the last time the user read the chapter.
+	ReadAt     time.Time `json:"read_at"`
+	Chapter    string    `json:"chapter"`
+	Name       string    `json:"name"`
+	URL        string    `json:"url"`
+	InternalID string    `json:"internal_id"`
+	Read       bool      `json:"read"`
+	// ReadCount is how many times the user read the chapter.
+	// Greater than 1 means the chapter was reread.
+	ReadCount int `json:"read_count"`
+}
+
+// SyncChaptersInDB stores the chapters in the multimanga chapters.
+// It doesn't change the read state of chapters already stored.
+func (mm *MultiManga) SyncChaptersInDB(chapters []*Chapter) error {
+	contextError := "error syncing multimanga '%d' chapters in DB"
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf(contextError, mm.ID), err)
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf(contextError, mm.ID), err)
+	}
+
+	err = upsertMultiMangaChapters(mm.ID, chapters, tx)
+	if err != nil {
+		tx.Rollback()
+		return util.AddErrorContext(fmt.Sprintf(contextError, mm.ID), err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf(contextError, mm.ID), err)
+	}
+
+	return nil
+}
+
+func upsertMultiMangaChapters(multiMangaID ID, chapters []*Chapter, tx *sql.Tx) error {
+	for _, chapter := range chapters {
+		if chapter == nil || chapter.Chapter == "" {
+			continue
+		}
+		var releasedAt interface{}
+		if chapter.Type != 2 && !chapter.UpdatedAt.IsZero() {
+			releasedAt = chapter.UpdatedAt
+		}
+		_, err := tx.Exec(`
+            INSERT INTO multimanga_chapters (multimanga_id, chapter, name, url, internal_id, released_at)
+            VALUES ($1, $2, $3, $4, $5, $6)
+            ON CONFLICT (multimanga_id, chapter)
+            DO UPDATE
+                SET name = EXCLUDED.name, url = EXCLUDED.url, internal_id = EXCLUDED.internal_id,
+                    released_at = COALESCE(multimanga_chapters.released_at, EXCLUDED.released_at);
+        `, multiMangaID, chapter.Chapter, chapter.Name, chapter.URL, chapter.InternalID, releasedAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetChaptersFromDB returns the multimanga stored chapters
+// with their read state, sorted from the first to the last chapter.
+func (mm *MultiManga) GetChaptersFromDB() ([]*ReadChapter, error) {
+	contextError := "error getting multimanga '%d' chapters from DB"
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, mm.ID), err)
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, mm.ID), err)
+	}
+	defer tx.Rollback()
+
+	chapters, err := getMultiMangaChapters(mm.ID, tx)
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, mm.ID), err)
+	}
+
+	return chapters, nil
+}
+
+func getMultiMangaChapters(multiMangaID ID, tx *sql.Tx) ([]*ReadChapter, error) {
+	rows, err := tx.Query(`
+        SELECT
+            chapter, name, url, internal_id, released_at, read, read_at, read_count
+        FROM
+            multimanga_chapters
+        WHERE
+            multimanga_id = $1;
+    `, multiMangaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	chapters := []*ReadChapter{}
+	for rows.Next() {
+		var chapter ReadChapter
+		var releasedAt, readAt sql.NullTime
+		err = rows.Scan(&chapter.Chapter, &chapter.Name, &chapter.URL, &chapter.InternalID, &releasedAt, &chapter.Read, &readAt, &chapter.ReadCount)
+		if err != nil {
+			return nil, err
+		}
+		chapter.ReleasedAt = releasedAt.Time
+		chapter.ReadAt = readAt.Time
+		chapters = append(chapters, &chapter)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sortReadChapters(chapters)
+
+	return chapters, nil
+}
+
+// MarkChaptersInDB marks the chapters as read or unread.
+// The chapters must be stored in the multimanga chapters.
+// The multimanga last read chapter is updated based on the new read state.
+func (mm *MultiManga) MarkChaptersInDB(chapters []string, read bool, readAt time.Time) error {
+	contextError := "error marking multimanga '%d' chapters %v as read=%v in DB"
+
+	err := mm.updateReadStateInDB(func(stored []*ReadChapter, tx *sql.Tx) error {
+		for _, chapter := range chapters {
+			if findReadChapter(stored, chapter) == -1 {
+				return util.AddErrorContext(fmt.Sprintf("chapter '%s'", chapter), errordefs.ErrChapterNotFoundDB)
+			}
+		}
+		return setChaptersReadState(mm.ID, chapters, read, readAt, tx)
+	})
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf(contextError, mm.ID, chapters, read), err)
+	}
+
+	return nil
+}
+
+// MarkChapterRangeInDB marks all chapters from fromChapter to toChapter (inclusive)
+// as read or unread. The chapters must be stored in the multimanga chapters.
+func (mm *MultiManga) MarkChapterRangeInDB(fromChapter, toChapter string, read bool, readAt time.Time) error {
+	contextError := "error marking multimanga '%d' chapters from '%s' to '%s' as read=%v in DB"
+
+	err := mm.updateReadStateInDB(func(stored []*ReadChapter, tx *sql.Tx) error {
+		fromIdx := findReadChapter(stored, fromChapter)
+		if fromIdx == -1 {
+			return util.AddErrorContext(fmt.Sprintf("chapter '%s'", fromChapter), errordefs.ErrChapterNotFoundDB)
+		}
+		toIdx := findReadChapter(stored, toChapter)
+		if toIdx == -1 {
+			return util.AddErrorContext(fmt.Sprintf("chapter '%s'", toChapter), errordefs.ErrChapterNotFoundDB)
+		}
+		if fromIdx > toIdx {
+			fromIdx, toIdx = toIdx, fromIdx
+		}
+
+		return setChaptersReadState(mm.ID, readChaptersNumbers(stored[fromIdx:toIdx+1]), read, readAt, tx)
+	})
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf(contextError, mm.ID, fromChapter, toChapter, read), err)
+	}
+
+	return nil
+}
+
+// MarkChaptersReadUpToInDB marks all chapters up to the chapter (inclusive) as read.
+// The chapter is stored in the multimanga chapters. If unmarkAfter is true, the chapters
+// after it are marked as unread, so the chapter becomes the multimanga last read chapter.
+// Else, the chapters after it keep their read state.
+func (mm *MultiManga) MarkChaptersReadUpToInDB(chapter *Chapter, readAt time.Time, unmarkAfter bool) error {
+	contextError := "error marking multimanga '%d' chapters read up to chapter '%s' in DB"
+
+	err := mm.updateReadStateInDB(func(stored []*ReadChapter, tx *sql.Tx) error {
+		// Upserts the chapter so the derived last read chapter is this exact chapter
+		err := upsertMultiMangaChapters(mm.ID, []*Chapter{chapter}, tx)
+		if err != nil {
+			return err
+		}
+		stored, err = getMultiMangaChapters(mm.ID, tx)
+		if err != nil {
+			return err
+		}
+		idx := findReadChapter(stored, chapter.Chapter)
+
+		err = setChaptersReadState(mm.ID, readChaptersNumbers(stored[:idx+1]), true, readAt, tx)
+		if err != nil {
+			return err
+		}
+		if unmarkAfter {
+			err = setChaptersReadState(mm.ID, readChaptersNumbers(stored[idx+1:]), false, readAt, tx)
+			if err != nil {
+				return err
+			}
+		}
+		// The chapter is the one the user just read, even if it was already read before.
+		_, err = tx.Exec(`
+            UPDATE multimanga_chapters
+            SET read_at = $3
+            WHERE multimanga_id = $1 AND chapter = $2;
+        `, mm.ID, chapter.Chapter, readAt)
+
+		return err
+	})
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf(contextError, mm.ID, chapter.Chapter), err)
+	}
+
+	return nil
+}
+
+// StartRereadInDB increments the multimanga reread count
+// and marks all its chapters as unread.
+func (mm *MultiManga) StartRereadInDB() error {
+	contextError := "error starting multimanga '%d' reread in DB"
+
+	err := mm.updateReadStateInDB(func(stored []*ReadChapter, tx *sql.Tx) error {
+		result, err := tx.Exec(`
+            UPDATE multimangas
+            SET reread_count = reread_count + 1
+            WHERE id = $1;
+        `, mm.ID)
+		if err != nil {
+			return err
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return errordefs.ErrMultiMangaNotFoundDB
+		}
+
+		return setChaptersReadState(mm.ID, readChaptersNumbers(stored), false, time.Time{}, tx)
+	})
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf(contextError, mm.ID), err)
+	}
+	mm.RereadCount++
+
+	return nil
+}
+
+// updateReadStateInDB runs update in a transaction with the multimanga
+// stored chapters and then updates the multimanga last read chapter
+// and unread chapters count based on the new read state.
+func (mm *MultiManga) updateReadStateInDB(update func(stored []*ReadChapter, tx *sql.Tx) error) error {
+	db, err := db.OpenConn()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+
+	stored, err := getMultiMangaChapters(mm.ID, tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	stored, err = backfillLastReadChapter(mm.ID, stored, tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = update(stored, tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	stored, err = getMultiMangaChapters(mm.ID, tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	lastReadChapter, err := deriveMultiMangaLastReadChapter(mm.ID, stored, tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+	mm.LastReadChapter = lastReadChapter
+	mm.UnreadChapters = 0
+	for _, chapter := range stored {
+		if !chapter.Read {
+			mm.UnreadChapters++
+		}
+	}
+	setMultiMangaUnreadChapters(mm)
+
+	return nil
+}
+
+// backfillLastReadChapter stores the multimanga last read chapter in the multimanga
+// chapters and marks it and the chapters before it as read if no stored chapter is read.
+// Multimangas added before the chapters' read state was stored, or with the last read
+// chapter set directly, only have the last read chapter, which would be deleted when
+// deriving it from the stored chapters.
+func backfillLastReadChapter(multiMangaID ID, stored []*ReadChapter, tx *sql.Tx) ([]*ReadChapter, error) {
+	for _, chapter := range stored {
+		if chapter.Read {
+			return stored, nil
+		}
+	}
+
+	var chapter Chapter
+	var updatedAt sql.NullTime
+	err := tx.QueryRow(`
+        SELECT
+            chapters.url, chapters.chapter, chapters.name, chapters.internal_id, chapters.updated_at
+        FROM
+            multimangas
+        JOIN
+            chapters ON chapters.id = multimangas.last_read_chapter
+        WHERE
+            multimangas.id = $1;
+    `, multiMangaID).Scan(&chapter.URL, &chapter.Chapter, &chapter.Name, &chapter.InternalID, &updatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return stored, nil
+		}
+		return nil, err
+	}
+	if chapter.Chapter == "" {
+		return stored, nil
+	}
+	chapter.Type = 2
+	readAt := updatedAt.Time
+	if readAt.IsZero() {
+		readAt = time.Now()
+	}
+
+	err = upsertMultiMangaChapters(multiMangaID, []*Chapter{&chapter}, tx)
+	if err != nil {
+		return nil, err
+	}
+	stored, err = getMultiMangaChapters(multiMangaID, tx)
+	if err != nil {
+		return nil, err
+	}
+	idx := findReadChapter(stored, chapter.Chapter)
+	err = setChaptersReadState(multiMangaID, readChaptersNumbers(stored[:idx+1]), true, readAt, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return getMultiMangaChapters(multiMangaID, tx)
+}
+
+func setChaptersReadState(multiMangaID ID, chapters []string, read bool, readAt time.Time, tx *sql.Tx) error {
+	if len(chapters) == 0 {
+		return nil
+	}
+
+	var err error
+	if read {
+		_, err = tx.Exec(`
+            UPDATE multimanga_chapters
+            SET read = TRUE, read_at = $3, read_count = read_count + 1
+            WHERE multimanga_id = $1 AND chapter = ANY($2) AND NOT read;
+        `, multiMangaID, pq.Array(chapters), readAt)
+	} else {
+		_, err = tx.Exec(`
+            UPDATE multimanga_chapters
+            SET read = FALSE
+            WHERE multimanga_id = $1 AND chapter = ANY($2) AND read;
+        `, multiMangaID, pq.Array(chapters))
+	}
+
+	return err
+}
+
+// deriveMultiMangaLastReadChapter sets the multimanga last read chapter
+// to the last read chapter in the stored chapters. If no chapter is read,
+// the multimanga last read chapter is deleted.
+func deriveMultiMangaLastReadChapter(multiMangaID ID, stored []*ReadChapter, tx *sql.Tx) (*Chapter, error) {
+	var lastRead *ReadChapter
+	for _, chapter := range stored {
+		if chapter.Read {
+			lastRead = chapter
+		}
+	}
+
+	if lastRead == nil {
+		_, err := tx.Exec(`
+            UPDATE multimangas
+            SET last_read_chapter = NULL
+            WHERE id = $1;
+        `, multiMangaID)
+		if err != nil {
+			return nil, err
+		}
+		_, err = tx.Exec(`
+            DELETE FROM chapters
+            WHERE multimanga_id = $1 AND type = 2;
+        `, multiMangaID)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+
+	chapter := &Chapter{
+		URL:        lastRead.URL,
+		Chapter:    lastRead.Chapter,
+		Name:       lastRead.Name,
+		InternalID: lastRead.InternalID,
+		UpdatedAt:  lastRead.ReadAt.Truncate(time.Second),
+		Type:       2,
+	}
+	if chapter.Name == "" {
+		chapter.Name = chapter.Chapter
+	}
+	err := upsertMultiMangaChapter(multiMangaID, chapter, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return chapter, nil
+}
+
+// sortReadChapters sorts the chapters from the first to the last chapter.
+// The chapters with a number come first, sorted by their number, and then the
+// chapters without a number, like "Extra", sorted by release date and URL.
+// The volume is only used if all chapters with a number have a volume, so
+// the order is the same regardless of the chapters being compared.
+func sortReadChapters(chapters []*ReadChapter) {
+	numbers := make(map[*ReadChapter]ChapterNumber, len(chapters))
+	useVolume := true
+	for _, chapter := range chapters {
+		number := ParseChapterNumber(chapter.Chapter)
+		numbers[chapter] = number
+		if number.HasNumber && !number.HasVolume {
+			useVolume = false
+		}
+	}
+
+	sort.SliceStable(chapters, func(i, j int) bool {
+		a, b := numbers[chapters[i]], numbers[chapters[j]]
+		if a.HasNumber != b.HasNumber {
+			return a.HasNumber
+		}
+		if a.HasNumber {
+			if useVolume && a.Volume != b.Volume {
+				return a.Volume < b.Volume
+			}
+			if a.Number != b.Number {
+				return a.Number < b.Number
+			}
+			if a.Part != b.Part {
+				return a.Part < b.Part
+			}
+			if a.Special != b.Special {
+				return b.Special
+			}
+		} else if !chapters[i].ReleasedAt.Equal(chapters[j].ReleasedAt) {
+			return chapters[i].ReleasedAt.Before(chapters[j].ReleasedAt)
+		}
+		if chapters[i].URL != chapters[j].URL {
+			return chapters[i].URL < chapters[j].URL
+		}
+
+		return chapters[i].Chapter < chapters[j].Chapter
+	})
+}
+
+func findReadChapter(chapters []*ReadChapter, chapter string) int {
+	for i, c := range chapters {
+		if c.Chapter == chapter {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func readChaptersNumbers(chapters []*ReadChapter) []string {
+	numbers := make([]string, 0, len(chapters))
+	for _, chapter := range chapters {
+		numbers = append(numbers, chapter.Chapter)
+	}
+
+	return numbers
+}
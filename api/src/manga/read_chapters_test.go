@@ -0,0 +1,91 @@
+package manga
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSortReadChapters(t *testing.T) {
+	now := time.Now()
+	chapters := []*ReadChapter{
+		{Chapter: "10", ReleasedAt: now},
+		{Chapter: "2", ReleasedAt: now.Add(-2 * time.Hour)},
+		{Chapter: "10.5", ReleasedAt: now.Add(time.Hour)},
+		{Chapter: "1", ReleasedAt: now.Add(-3 * time.Hour)},
+	}
+
+	sortReadChapters(chapters)
+
+	expected := []string{"1", "2", "10", "10.5"}
+	for i, chapter := range chapters {
+		if chapter.Chapter != expected[i] {
+			t.Fatalf("expected chapters order %v, got %v", expected, readChaptersNumbers(chapters))
+		}
+	}
+
+	// The chapters without a number come after the chapters with a number
+	// sorted by release date, and the volumes are only used if all chapters have one.
+	chapters = []*ReadChapter{
+		{Chapter: "Extra", ReleasedAt: now, URL: "https://example.com/extra"},
+		{Chapter: "Vol.2 Ch.1", ReleasedAt: now},
+		{Chapter: "Oneshot", ReleasedAt: now.Add(-time.Hour)},
+		{Chapter: "Ch.5", ReleasedAt: now},
+		{Chapter: "Vol.1 Ch.10", ReleasedAt: now},
+	}
+	sortReadChapters(chapters)
+	expected = []string{"Vol.2 Ch.1", "Ch.5", "Vol.1 Ch.10", "Oneshot", "Extra"}
+	for i, chapter := range chapters {
+		if chapter.Chapter != expected[i] {
+			t.Fatalf("expected chapters order %v, got %v", expected, readChaptersNumbers(chapters))
+		}
+	}
+
+	if idx := findReadChapter(chapters, "Ch.5"); idx != 1 {
+		t.Fatalf("expected chapter Ch.5 at index 1, got %d", idx)
+	}
+	if idx := findReadChapter(chapters, "11"); idx != -1 {
+		t.Fatalf("expected chapter 11 to not be found, got %d", idx)
+	}
+}
+
+func TestBackfillLastReadChapterDB(t *testing.T) {
+	// The multimanga is inserted with only the last read chapter, like the multimangas
+	// added before the chapters' read state was stored.
+	multiManga := getMultiMangaCopy(multiMangaTest)
+	err := multiManga.InsertIntoDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer multiManga.DeleteFromDB()
+
+	chapters := []*Chapter{
+		{URL: "https://testingsite/manga/best-manga/chapter-14", Name: "Chapter 14", Chapter: "14", Type: 1},
+		{URL: "https://testingsite/manga/best-manga/chapter-16", Name: "Chapter 16", Chapter: "16", Type: 1},
+	}
+	err = multiManga.SyncChaptersInDB(chapters)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = multiManga.MarkChaptersInDB([]string{"16"}, false, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if multiManga.LastReadChapter == nil || multiManga.LastReadChapter.Chapter != multiMangaTest.LastReadChapter.Chapter {
+		t.Fatalf("expected last read chapter %s to be kept, got %v", multiMangaTest.LastReadChapter.Chapter, multiManga.LastReadChapter)
+	}
+
+	stored, err := multiManga.GetChaptersFromDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]bool{"14": true, "15": true, "16": false}
+	if len(stored) != len(expected) {
+		t.Fatalf("expected chapters %v, got %v", expected, readChaptersNumbers(stored))
+	}
+	for _, chapter := range stored {
+		if chapter.Read != expected[chapter.Chapter] {
+			t.Errorf("expected chapter %s read to be %t", chapter.Chapter, expected[chapter.Chapter])
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package manga
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/diogovalentte/mantium/api/src/db"
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// Relation is a title related to a manga in the manga source, like a sequel or a spin-off.
+type Relation struct {
+	CreatedAt time.Time `json:"created_at"`
+	// Type is the relation type normalized by NormalizeRelationType, like "sequel" or "side_story".
+	Type string `json:"type"`
+	Name string `json:"name"`
+	// URL is the related title URL in the manga source.
+	URL        string `json:"url"`
+	InternalID string `json:"internal_id"`
+	Source     string `json:"source"`
+	// InLibrary is true if a manga with the related title URL is in the library.
+	// It's not stored in the DB.
+	InLibrary bool `json:"in_library"`
+	MangaID   ID   `json:"manga_id"`
+}
+
+// followUpRelationTypes are the relation types of titles that follow a manga story.
+var followUpRelationTypes = []string{"sequel", "side_story", "spin_off", "alternate_story"}
+
+// NormalizeRelationType returns the relation type in lowercase with words separated
+// by underscores, so the relation types of different sources can be compared.
+// For example, "Spin-Off" becomes "spin_off".
+func NormalizeRelationType(relationType string) string {
+	relationType = strings.ToLower(strings.TrimSpace(relationType))
+	relationType = strings.NewReplacer("-", " ", "_", " ").Replace(relationType)
+
+	return strings.Join(strings.Fields(relationType), "_")
+}
+
+// IsFollowUp returns true if the related title follows the manga story, like a sequel.
+func (r *Relation) IsFollowUp() bool {
+	for _, relationType := range followUpRelationTypes {
+		if r.Type == relationType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SyncMangaRelationsDB replaces the manga relations in the database with the relations
+// and sets when they were checked. The relations that were already in the database keep
+// when they were first seen. It returns the relations that weren't in the database.
+// If the manga relations were never checked before, no relation is returned, as they're not new.
+func SyncMangaRelationsDB(mangaID ID, relations []*Relation, checkedAt time.Time) ([]*Relation, error) {
+	contextError := "error syncing manga '%d' relations in DB"
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, mangaID), err)
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, mangaID), err)
+	}
+
+	var checkedBefore bool
+	err = tx.QueryRow(`
+        SELECT EXISTS (SELECT 1 FROM manga_relations_checks WHERE manga_id = $1);
+    `, mangaID).Scan(&checkedBefore)
+	if err != nil {
+		tx.Rollback()
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, mangaID), err)
+	}
+
+	rows, err := tx.Query(`
+        SELECT url, created_at FROM manga_relations WHERE manga_id = $1;
+    `, mangaID)
+	if err != nil {
+		tx.Rollback()
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, mangaID), err)
+	}
+	oldCreatedAt := map[string]time.Time{}
+	for rows.Next() {
+		var url string
+		var createdAt time.Time
+		if err = rows.Scan(&url, &createdAt); err != nil {
+			rows.Close()
+			tx.Rollback()
+			return nil, util.AddErrorContext(fmt.Sprintf(contextError, mangaID), err)
+		}
+		oldCreatedAt[url] = createdAt
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		tx.Rollback()
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, mangaID), err)
+	}
+
+	_, err = tx.Exec(`
+        DELETE FROM manga_relations WHERE manga_id = $1;
+    `, mangaID)
+	if err != nil {
+		tx.Rollback()
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, mangaID), err)
+	}
+
+	newRelations := []*Relation{}
+	syncedURLs := map[string]bool{}
+	for _, relation := range relations {
+		if syncedURLs[relation.URL] {
+			continue
+		}
+		syncedURLs[relation.URL] = true
+
+		relation.MangaID = mangaID
+		createdAt, existed := oldCreatedAt[relation.URL]
+		if existed {
+			relation.CreatedAt = createdAt
+		} else if relation.CreatedAt.IsZero() {
+			relation.CreatedAt = checkedAt
+		}
+		_, err = tx.Exec(`
+            INSERT INTO manga_relations (manga_id, url, internal_id, type, name, source, created_at)
+            VALUES ($1, $2, $3, $4, $5, $6, $7);
+        `, mangaID, relation.URL, relation.InternalID, relation.Type, relation.Name, relation.Source, relation.CreatedAt)
+		if err != nil {
+			tx.Rollback()
+			return nil, util.AddErrorContext(fmt.Sprintf(contextError, mangaID), err)
+		}
+
+		if checkedBefore && !existed {
+			newRelations = append(newRelations, relation)
+		}
+	}
+
+	_, err = tx.Exec(`
+        INSERT INTO manga_relations_checks (manga_id, checked_at)
+        VALUES ($1, $2)
+        ON CONFLICT (manga_id)
+        DO UPDATE
+            SET checked_at = EXCLUDED.checked_at;
+    `, mangaID, checkedAt)
+	if err != nil {
+		tx.Rollback()
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, mangaID), err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, mangaID), err)
+	}
+
+	return newRelations, nil
+}
+
+// GetRelationsCheckedAtDB returns when the relations of each manga were last checked, by manga ID.
+// The mangas whose relations were never checked aren't returned.
+func GetRelationsCheckedAtDB() (map[ID]time.Time, error) {
+	contextError := "error getting when the manga relations were checked from DB"
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return nil, util.AddErrorContext(contextError, err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`
+        SELECT
+            manga_id, checked_at
+        FROM
+            manga_relations_checks;
+    `)
+	if err != nil {
+		return nil, util.AddErrorContext(contextError, err)
+	}
+	defer rows.Close()
+
+	checkedAt := map[ID]time.Time{}
+	for rows.Next() {
+		var mangaID ID
+		var t time.Time
+		if err = rows.Scan(&mangaID, &t); err != nil {
+			return nil, util.AddErrorContext(contextError, err)
+		}
+		checkedAt[mangaID] = t
+	}
+	if err = rows.Err(); err != nil {
+		return nil, util.AddErrorContext(contextError, err)
+	}
+
+	return checkedAt, nil
+}
+
+// GetMultiMangaRelationsDB returns the relations of the multimanga mangas from the database.
+func GetMultiMangaRelationsDB(multimangaID ID) ([]*Relation, error) {
+	contextError := "error getting multimanga '%d' relations from DB"
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, multimangaID), err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`
+        SELECT
+            `+relationColumns+`
+        FROM
+            manga_relations r
+        JOIN
+            mangas m ON m.id = r.manga_id
+        WHERE
+            m.multimanga_id = $1
+        ORDER BY
+            r.created_at, r.name;
+    `, multimangaID)
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, multimangaID), err)
+	}
+	defer rows.Close()
+
+	relations, err := scanRelations(rows)
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, multimangaID), err)
+	}
+
+	return relations, nil
+}
+
+// GetMangaRelationDB returns a relation of the manga by the related title URL from the database.
+func GetMangaRelationDB(mangaID ID, url string) (*Relation, error) {
+	contextError := "error getting manga '%d' relation with URL '%s' from DB"
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, mangaID, url), err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`
+        SELECT
+            `+relationColumns+`
+        FROM
+            manga_relations r
+        WHERE
+            r.manga_id = $1 AND r.url = $2;
+    `, mangaID, url)
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, mangaID, url), err)
+	}
+	defer rows.Close()
+
+	relations, err := scanRelations(rows)
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, mangaID, url), err)
+	}
+	if len(relations) == 0 {
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, mangaID, url), errordefs.ErrRelationNotFoundDB)
+	}
+
+	return relations[0], nil
+}
+
+const relationColumns = `r.manga_id, r.url, r.internal_id, r.type, r.name, r.source, r.created_at,
+            EXISTS (SELECT 1 FROM mangas WHERE mangas.url = r.url)`
+
+func scanRelations(rows *sql.Rows) ([]*Relation, error) {
+	relations := []*Relation{}
+	for rows.Next() {
+		r := &Relation{}
+		err := rows.Scan(&r.MangaID, &r.URL, &r.InternalID, &r.Type, &r.Name, &r.Source, &r.CreatedAt, &r.InLibrary)
+		if err != nil {
+			return nil, err
+		}
+		relations = append(relations, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return relations, nil
+}
@@ -0,0 +1,85 @@
+package manga
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNormalizeRelationType(t *testing.T) {
+	tests := map[string]string{
+		"sequel":           "sequel",
+		"Side Story":       "side_story",
+		"Spin-Off":         "spin_off",
+		" alternate_story": "alternate_story",
+		"Main  Story":      "main_story",
+	}
+
+	for relationType, expected := range tests {
+		if normalized := NormalizeRelationType(relationType); normalized != expected {
+			t.Errorf("expected '%s' to be normalized to '%s', got '%s'", relationType, expected, normalized)
+		}
+	}
+}
+
+func TestRelationIsFollowUp(t *testing.T) {
+	tests := map[string]bool{
+		"sequel":          true,
+		"side_story":      true,
+		"spin_off":        true,
+		"alternate_story": true,
+		"prequel":         false,
+		"main_story":      false,
+		"adapted_from":    false,
+	}
+
+	for relationType, expected := range tests {
+		relation := &Relation{Type: relationType}
+		if relation.IsFollowUp() != expected {
+			t.Errorf("expected relation type '%s' follow up to be %t", relationType, expected)
+		}
+	}
+}
+
+func TestSyncMangaRelationsDB(t *testing.T) {
+	manga := getMangaCopy(mangaTest)
+	manga.URL = "https://testingsite/manga/related-manga"
+	err := manga.InsertIntoDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer manga.DeleteFromDB()
+
+	firstCheck := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	relations := []*Relation{{URL: "https://testingsite/manga/sequel", Type: "sequel", Source: "testing"}}
+	_, err = SyncMangaRelationsDB(manga.ID, relations, firstCheck)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secondCheck := firstCheck.Add(24 * time.Hour)
+	relations = []*Relation{
+		{URL: "https://testingsite/manga/sequel", Type: "sequel", Source: "testing"},
+		{URL: "https://testingsite/manga/side-story", Type: "side_story", Source: "testing"},
+	}
+	newRelations, err := SyncMangaRelationsDB(manga.ID, relations, secondCheck)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(newRelations) != 1 || newRelations[0].URL != "https://testingsite/manga/side-story" {
+		t.Fatalf("expected only the side story to be new, got %v", newRelations)
+	}
+
+	expected := map[string]time.Time{
+		"https://testingsite/manga/sequel":     firstCheck,
+		"https://testingsite/manga/side-story": secondCheck,
+	}
+	for url, createdAt := range expected {
+		relation, err := GetMangaRelationDB(manga.ID, url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !relation.CreatedAt.Equal(createdAt) {
+			t.Errorf("expected relation '%s' to be created at %s, got %s", url, createdAt, relation.CreatedAt)
+		}
+	}
+}
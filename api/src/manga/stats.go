@@ -0,0 +1,612 @@
+package manga
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/diogovalentte/mantium/api/src/db"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// UnknownGenre is the genre used in the stats when
+// the reading activity has no genres.
+const UnknownGenre = "Unknown"
+
+// ReadingActivity is an entry of the reading activity log.
+// An entry is recorded every time the user advances the
+// last read chapter of a multimanga or custom manga.
+type ReadingActivity struct {
+	// ReadAt is when the user set the chapter as read.
+	ReadAt time.Time `json:"read_at"`
+	// ChapterReleasedAt is when the chapter was released by the source.
+	// It's zero when it's unknown, like with custom mangas.
+	ChapterReleasedAt time.Time `json:"chapter_released_at"`
+	// MangaName and Source are stored so the log is kept
+	// after the manga is deleted.
+	MangaName string   `json:"manga_name"`
+	Source    string   `json:"source"`
+	Genres    []string `json:"genres"`
+	// Chapter is the chapter the user read.
+	Chapter    string `json:"chapter"`
+	ChapterURL string `json:"chapter_url"`
+	// PreviousChapter is the last read chapter before this entry.
+	PreviousChapter string `json:"previous_chapter"`
+	// ChaptersRead is how many chapters the user read in this entry,
+	// calculated from the previous chapter. It's 1 when the chapters
+	// are not numbers.
+	ChaptersRead int `json:"chapters_read"`
+	ID           int `json:"id"`
+	// MultiMangaID is 0 when it's a custom manga or the multimanga was deleted.
+	MultiMangaID ID `json:"multimanga_id"`
+	// MangaID is the manga the chapter was read from.
+	// It's 0 when the manga was deleted.
+	MangaID ID `json:"manga_id"`
+}
+
+// ReadingPeriodStats is the amount of chapters read in a period.
+type ReadingPeriodStats struct {
+	// PeriodStart is the first day of the period.
+	PeriodStart  time.Time `json:"period_start"`
+	ChaptersRead int       `json:"chapters_read"`
+	// Mangas is the number of different mangas read in the period.
+	Mangas int `json:"mangas"`
+}
+
+// ReadingStreaks are the streaks of consecutive days
+// with at least one chapter read.
+type ReadingStreaks struct {
+	CurrentStreakStart time.Time `json:"current_streak_start"`
+	LongestStreakStart time.Time `json:"longest_streak_start"`
+	LongestStreakEnd   time.Time `json:"longest_streak_end"`
+	// CurrentStreak is the number of days of the current streak.
+	// The streak is still active if the user didn't read anything
+	// today, but read yesterday.
+	CurrentStreak int `json:"current_streak"`
+	LongestStreak int `json:"longest_streak"`
+}
+
+// ReadingBreakdown is the amount of chapters read grouped by a key,
+// like a source or a genre.
+type ReadingBreakdown struct {
+	Key          string `json:"key"`
+	ChaptersRead int    `json:"chapters_read"`
+	Mangas       int    `json:"mangas"`
+}
+
+// ReleaseToReadStats is the time between a chapter
+// being released and being read by the user.
+type ReleaseToReadStats struct {
+	AverageSeconds int64 `json:"average_seconds"`
+	MedianSeconds  int64 `json:"median_seconds"`
+	// Samples is the number of reading activities with
+	// a known chapter release date used in the calculation.
+	Samples int `json:"samples"`
+}
+
+// BacklogSnapshot is the size of the user's backlog
+// (mangas with unread chapters) in a day.
+type BacklogSnapshot struct {
+	Day    time.Time `json:"day"`
+	Unread int       `json:"unread"`
+	Total  int       `json:"total"`
+}
+
+// RecordReadingActivity inserts a reading activity into the log
+// if the new chapter advances the user's progress.
+// previousChapter can be nil. getGenres is only called if the activity advances
+// the progress and has no genres, as getting them can request the manga source.
+// It can be nil. It returns false if the activity wasn't recorded because it
+// doesn't advance the progress.
+func RecordReadingActivity(activity *ReadingActivity, previousChapter *Chapter, getGenres func() []string) (bool, error) {
+	contextError := "error recording reading activity of manga '%s' chapter '%s'"
+
+	if previousChapter != nil {
+		activity.PreviousChapter = previousChapter.Chapter
+	}
+	chaptersRead, advanced := chaptersAdvanced(activity.PreviousChapter, activity.Chapter)
+	if !advanced {
+		return false, nil
+	}
+	activity.ChaptersRead = chaptersRead
+	if activity.Genres == nil && getGenres != nil {
+		activity.Genres = getGenres()
+	}
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return false, util.AddErrorContext(fmt.Sprintf(contextError, activity.MangaName, activity.Chapter), err)
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return false, util.AddErrorContext(fmt.Sprintf(contextError, activity.MangaName, activity.Chapter), err)
+	}
+
+	err = insertReadingActivityIntoDB(activity, tx)
+	if err != nil {
+		tx.Rollback()
+		return false, util.AddErrorContext(fmt.Sprintf(contextError, activity.MangaName, activity.Chapter), err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return false, util.AddErrorContext(fmt.Sprintf(contextError, activity.MangaName, activity.Chapter), err)
+	}
+
+	return true, nil
+}
+
+func insertReadingActivityIntoDB(activity *ReadingActivity, tx *sql.Tx) error {
+	var multiMangaID, mangaID, releasedAt interface{}
+	if activity.MultiMangaID > 0 {
+		multiMangaID = activity.MultiMangaID
+	}
+	if activity.MangaID > 0 {
+		mangaID = activity.MangaID
+	}
+	if !activity.ChapterReleasedAt.IsZero() {
+		releasedAt = activity.ChapterReleasedAt
+	}
+	genres := activity.Genres
+	if genres == nil {
+		genres = []string{}
+	}
+
+	err := tx.QueryRow(`
+        INSERT INTO reading_activity
+            (multimanga_id, manga_id, manga_name, source, genres, chapter, chapter_url, previous_chapter, chapters_read, chapter_released_at, read_at)
+        VALUES
+            ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+        RETURNING id;
+    `, multiMangaID, mangaID, activity.MangaName, activity.Source, pq.Array(genres), activity.Chapter, activity.ChapterURL, activity.PreviousChapter, activity.ChaptersRead, releasedAt, activity.ReadAt).Scan(&activity.ID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetMangaGenresDB returns the genres of the manga's last reading activity with genres.
+// It returns nil if no reading activity of the manga has genres.
+func GetMangaGenresDB(mangaID ID) ([]string, error) {
+	contextError := "error getting manga '%d' genres from the reading activity"
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, mangaID), err)
+	}
+	defer db.Close()
+
+	var genres []string
+	err = db.QueryRow(`
+        SELECT
+            genres
+        FROM
+            reading_activity
+        WHERE
+            manga_id = $1 AND cardinality(genres) > 0
+        ORDER BY
+            read_at DESC
+        LIMIT 1;
+    `, mangaID).Scan(pq.Array(&genres))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, mangaID), err)
+	}
+
+	return genres, nil
+}
+
+// chaptersAdvanced returns how many chapters were read when going from
+// the previous chapter to the new chapter and whether the new chapter
+// advances the progress. Chapters that can't be compared always advance
+// the progress by one chapter if they are different.
+func chaptersAdvanced(previousChapter, newChapter string) (int, bool) {
+	if previousChapter == newChapter {
+		return 0, false
+	}
+	if previousChapter == "" {
+		return 1, true
+	}
+
+	previous := ParseChapterNumber(previousChapter)
+	current := ParseChapterNumber(newChapter)
+	cmp, ok := current.Compare(previous)
+	if !ok {
+		return 1, true
+	}
+	if cmp <= 0 {
+		return 0, false
+	}
+
+	read := 1
+	if current.HasNumber && previous.HasNumber && (!current.HasVolume || !previous.HasVolume || current.Volume == previous.Volume) {
+		read = int(math.Max(1, math.Floor(current.Number)-math.Floor(previous.Number)))
+	}
+
+	return read, true
+}
+
+// GetReadingActivity returns the reading activity log between from and to, newest first.
+// If limit is <= 0, all activities are returned.
+func GetReadingActivity(from, to time.Time, limit int) ([]*ReadingActivity, error) {
+	contextError := "error getting reading activity"
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return nil, util.AddErrorContext(contextError, err)
+	}
+	defer db.Close()
+
+	activities, err := getReadingActivityFromDB(from, to, limit, db)
+	if err != nil {
+		return nil, util.AddErrorContext(contextError, err)
+	}
+
+	return activities, nil
+}
+
+func getReadingActivityFromDB(from, to time.Time, limit int, db *sql.DB) ([]*ReadingActivity, error) {
+	query := `
+        SELECT
+            id, COALESCE(multimanga_id, 0), COALESCE(manga_id, 0), manga_name, source, genres, chapter, chapter_url, previous_chapter, chapters_read, chapter_released_at, read_at
+        FROM
+            reading_activity
+        WHERE
+            read_at >= $1 AND read_at < $2
+        ORDER BY
+            read_at DESC, id DESC
+    `
+	args := []interface{}{from, to}
+	if limit > 0 {
+		query += " LIMIT $3"
+		args = append(args, limit)
+	}
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	activities := []*ReadingActivity{}
+	for rows.Next() {
+		var activity ReadingActivity
+		var releasedAt sql.NullTime
+		err = rows.Scan(&activity.ID, &activity.MultiMangaID, &activity.MangaID, &activity.MangaName, &activity.Source, pq.Array(&activity.Genres), &activity.Chapter, &activity.ChapterURL, &activity.PreviousChapter, &activity.ChaptersRead, &releasedAt, &activity.ReadAt)
+		if err != nil {
+			return nil, err
+		}
+		if releasedAt.Valid {
+			activity.ChapterReleasedAt = releasedAt.Time
+		}
+		activities = append(activities, &activity)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return activities, nil
+}
+
+// GetChaptersReadPerPeriod returns the chapters read grouped by period,
+// which can be "day", "week", or "month".
+func GetChaptersReadPerPeriod(period string, from, to time.Time) ([]*ReadingPeriodStats, error) {
+	contextError := "error getting chapters read per %s"
+
+	if period != "day" && period != "week" && period != "month" {
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, period), fmt.Errorf("invalid period '%s', should be day, week, or month", period))
+	}
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, period), err)
+	}
+	defer db.Close()
+
+	// period is validated above, so it's safe to use it in the query
+	rows, err := db.Query(`
+        SELECT
+            date_trunc('`+period+`', read_at) AS period_start,
+            SUM(chapters_read),
+            COUNT(DISTINCT COALESCE(multimanga_id::text, manga_id::text, manga_name))
+        FROM
+            reading_activity
+        WHERE
+            read_at >= $1 AND read_at < $2
+        GROUP BY
+            period_start
+        ORDER BY
+            period_start;
+    `, from, to)
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, period), err)
+	}
+	defer rows.Close()
+
+	stats := []*ReadingPeriodStats{}
+	for rows.Next() {
+		var s ReadingPeriodStats
+		err = rows.Scan(&s.PeriodStart, &s.ChaptersRead, &s.Mangas)
+		if err != nil {
+			return nil, util.AddErrorContext(fmt.Sprintf(contextError, period), err)
+		}
+		stats = append(stats, &s)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, period), err)
+	}
+
+	return stats, nil
+}
+
+// GetReadingStreaks returns the current and longest reading streaks.
+func GetReadingStreaks() (*ReadingStreaks, error) {
+	contextError := "error getting reading streaks"
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return nil, util.AddErrorContext(contextError, err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`
+        SELECT DISTINCT
+            read_at::date AS day
+        FROM
+            reading_activity
+        ORDER BY
+            day;
+    `)
+	if err != nil {
+		return nil, util.AddErrorContext(contextError, err)
+	}
+	defer rows.Close()
+
+	days := []time.Time{}
+	for rows.Next() {
+		var day time.Time
+		err = rows.Scan(&day)
+		if err != nil {
+			return nil, util.AddErrorContext(contextError, err)
+		}
+		days = append(days, day)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, util.AddErrorContext(contextError, err)
+	}
+
+	return calculateReadingStreaks(days, time.Now()), nil
+}
+
+// calculateReadingStreaks calculates the streaks from a list
+// of distinct days sorted in ascending order.
+func calculateReadingStreaks(days []time.Time, now time.Time) *ReadingStreaks {
+	streaks := &ReadingStreaks{}
+	if len(days) == 0 {
+		return streaks
+	}
+
+	truncateDay := func(t time.Time) time.Time {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+
+	streakStart := truncateDay(days[0])
+	previous := streakStart
+	streakLength := 1
+	updateLongest := func(end time.Time) {
+		if streakLength > streaks.LongestStreak {
+			streaks.LongestStreak = streakLength
+			streaks.LongestStreakStart = streakStart
+			streaks.LongestStreakEnd = end
+		}
+	}
+	for _, d := range days[1:] {
+		day := truncateDay(d)
+		if day.Equal(previous) {
+			continue
+		}
+		if day.Equal(previous.AddDate(0, 0, 1)) {
+			streakLength++
+		} else {
+			updateLongest(previous)
+			streakStart = day
+			streakLength = 1
+		}
+		previous = day
+	}
+	updateLongest(previous)
+
+	today := truncateDay(now)
+	if previous.Equal(today) || previous.Equal(today.AddDate(0, 0, -1)) {
+		streaks.CurrentStreak = streakLength
+		streaks.CurrentStreakStart = streakStart
+	}
+
+	return streaks
+}
+
+// GetChaptersReadPerSource returns the chapters read grouped by source.
+func GetChaptersReadPerSource(from, to time.Time) ([]*ReadingBreakdown, error) {
+	contextError := "error getting chapters read per source"
+
+	breakdown, err := getReadingBreakdown("source", from, to)
+	if err != nil {
+		return nil, util.AddErrorContext(contextError, err)
+	}
+
+	return breakdown, nil
+}
+
+// GetChaptersReadPerGenre returns the chapters read grouped by genre.
+// Activities without genres are grouped in the UnknownGenre.
+func GetChaptersReadPerGenre(from, to time.Time) ([]*ReadingBreakdown, error) {
+	contextError := "error getting chapters read per genre"
+
+	breakdown, err := getReadingBreakdown(fmt.Sprintf("unnest(CASE WHEN cardinality(genres) = 0 THEN ARRAY['%s'] ELSE genres END)", UnknownGenre), from, to)
+	if err != nil {
+		return nil, util.AddErrorContext(contextError, err)
+	}
+
+	return breakdown, nil
+}
+
+// getReadingBreakdown groups the reading activity by keyExpr.
+// keyExpr must not be a user input.
+func getReadingBreakdown(keyExpr string, from, to time.Time) ([]*ReadingBreakdown, error) {
+	db, err := db.OpenConn()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`
+        SELECT
+            key,
+            SUM(chapters_read) AS total_chapters,
+            COUNT(DISTINCT COALESCE(multimanga_id::text, manga_id::text, manga_name))
+        FROM (
+            SELECT
+                `+keyExpr+` AS key, chapters_read, multimanga_id, manga_id, manga_name
+            FROM
+                reading_activity
+            WHERE
+                read_at >= $1 AND read_at < $2
+        ) AS activity
+        GROUP BY
+            key
+        ORDER BY
+            total_chapters DESC, key;
+    `, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	breakdown := []*ReadingBreakdown{}
+	for rows.Next() {
+		var b ReadingBreakdown
+		err = rows.Scan(&b.Key, &b.ChaptersRead, &b.Mangas)
+		if err != nil {
+			return nil, err
+		}
+		breakdown = append(breakdown, &b)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return breakdown, nil
+}
+
+// GetReleaseToReadStats returns the average and median time
+// between a chapter being released and being read.
+func GetReleaseToReadStats(from, to time.Time) (*ReleaseToReadStats, error) {
+	contextError := "error getting release to read stats"
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return nil, util.AddErrorContext(contextError, err)
+	}
+	defer db.Close()
+
+	var stats ReleaseToReadStats
+	var average, median sql.NullFloat64
+	err = db.QueryRow(`
+        SELECT
+            COUNT(*),
+            AVG(EXTRACT(EPOCH FROM (read_at - chapter_released_at))),
+            percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM (read_at - chapter_released_at)))
+        FROM
+            reading_activity
+        WHERE
+            chapter_released_at IS NOT NULL
+            AND read_at >= chapter_released_at
+            AND read_at >= $1 AND read_at < $2;
+    `, from, to).Scan(&stats.Samples, &average, &median)
+	if err != nil {
+		return nil, util.AddErrorContext(contextError, err)
+	}
+	stats.AverageSeconds = int64(average.Float64)
+	stats.MedianSeconds = int64(median.Float64)
+
+	return &stats, nil
+}
+
+// SaveBacklogSnapshot saves today's backlog size in the database.
+// If there is already a snapshot for today, it's updated.
+func SaveBacklogSnapshot() error {
+	contextError := "error saving backlog snapshot"
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return util.AddErrorContext(contextError, err)
+	}
+	defer db.Close()
+
+	stats, err := getLibraryStatsFromDB(db)
+	if err != nil {
+		return util.AddErrorContext(contextError, err)
+	}
+
+	_, err = db.Exec(`
+        INSERT INTO backlog_snapshots (day, unread, total)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (day)
+        DO UPDATE
+            SET unread = EXCLUDED.unread, total = EXCLUDED.total;
+    `, time.Now().Format("2006-01-02"), stats["Unread"], stats["Total"])
+	if err != nil {
+		return util.AddErrorContext(contextError, err)
+	}
+
+	return nil
+}
+
+// GetBacklogSnapshots returns the backlog snapshots from from (inclusive) to to (exclusive).
+func GetBacklogSnapshots(from, to time.Time) ([]*BacklogSnapshot, error) {
+	contextError := "error getting backlog snapshots"
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return nil, util.AddErrorContext(contextError, err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`
+        SELECT
+            day, unread, total
+        FROM
+            backlog_snapshots
+        WHERE
+            day >= $1::date AND day < $2::date
+        ORDER BY
+            day;
+    `, from, to)
+	if err != nil {
+		return nil, util.AddErrorContext(contextError, err)
+	}
+	defer rows.Close()
+
+	snapshots := []*BacklogSnapshot{}
+	for rows.Next() {
+		var s BacklogSnapshot
+		err = rows.Scan(&s.Day, &s.Unread, &s.Total)
+		if err != nil {
+			return nil, util.AddErrorContext(contextError, err)
+		}
+		snapshots = append(snapshots, &s)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, util.AddErrorContext(contextError, err)
+	}
+
+	return snapshots, nil
+}
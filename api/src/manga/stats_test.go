@@ -0,0 +1,65 @@
+package manga
+
+import (
+	"testing"
+	"time"
+)
+
+func TestChaptersAdvanced(t *testing.T) {
+	tests := []struct {
+		previous     string
+		current      string
+		chaptersRead int
+		advanced     bool
+	}{
+		{"", "1", 1, true},
+		{"", "", 0, false},
+		{"10", "10", 0, false},
+		{"10", "13", 3, true},
+		{"10", "10.5", 1, true},
+		{"10.5", "11", 1, true},
+		{"13", "10", 0, false},
+		{"Oneshot", "1", 1, true},
+		{"1", "Extra", 1, true},
+	}
+
+	for _, test := range tests {
+		chaptersRead, advanced := chaptersAdvanced(test.previous, test.current)
+		if chaptersRead != test.chaptersRead || advanced != test.advanced {
+			t.Fatalf("chaptersAdvanced(%q, %q) = (%d, %v), expected (%d, %v)", test.previous, test.current, chaptersRead, advanced, test.chaptersRead, test.advanced)
+		}
+	}
+}
+
+func TestCalculateReadingStreaks(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2024, time.March, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	t.Run("Should return empty streaks without activity", func(t *testing.T) {
+		streaks := calculateReadingStreaks(nil, day(10))
+		if streaks.CurrentStreak != 0 || streaks.LongestStreak != 0 {
+			t.Fatalf("expected empty streaks, got %+v", streaks)
+		}
+	})
+	t.Run("Should calculate current and longest streaks", func(t *testing.T) {
+		days := []time.Time{day(1), day(2), day(3), day(4), day(7), day(8), day(9)}
+		streaks := calculateReadingStreaks(days, day(10))
+		if streaks.LongestStreak != 4 || !streaks.LongestStreakStart.Equal(day(1)) || !streaks.LongestStreakEnd.Equal(day(4)) {
+			t.Fatalf("unexpected longest streak: %+v", streaks)
+		}
+		if streaks.CurrentStreak != 3 || !streaks.CurrentStreakStart.Equal(day(7)) {
+			t.Fatalf("unexpected current streak: %+v", streaks)
+		}
+	})
+	t.Run("Should not have a current streak if the last read was before yesterday", func(t *testing.T) {
+		days := []time.Time{day(1), day(2)}
+		streaks := calculateReadingStreaks(days, day(10))
+		if streaks.CurrentStreak != 0 {
+			t.Fatalf("expected no current streak, got %+v", streaks)
+		}
+		if streaks.LongestStreak != 2 {
+			t.Fatalf("expected longest streak of 2 days, got %+v", streaks)
+		}
+	})
+}
@@ -0,0 +1,171 @@
+package manga
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"github.com/diogovalentte/mantium/api/src/db"
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// NormalizeTags returns the tags trimmed, in lower case, sorted, and without duplicates or empty tags.
+func NormalizeTags(tags []string) []string {
+	normalized := []string{}
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !slices.Contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+	slices.Sort(normalized)
+
+	return normalized
+}
+
+// HasAnyTag returns true if the tags contain any of the wanted tags.
+// Tags are compared case-insensitively. It returns true if wanted is empty.
+func HasAnyTag(tags, wanted []string) bool {
+	if len(wanted) == 0 {
+		return true
+	}
+	for _, tag := range tags {
+		for _, w := range wanted {
+			if strings.EqualFold(tag, w) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// UpdateTagsInDB updates the multimanga tags in the database.
+// The tags are normalized with NormalizeTags.
+func (mm *MultiManga) UpdateTagsInDB(tags []string) error {
+	contextError := "error updating multimanga '%d' tags in DB"
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf(contextError, mm.ID), err)
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf(contextError, mm.ID), err)
+	}
+
+	tags = NormalizeTags(tags)
+	err = updateMultiMangaTagsDB(mm.ID, tags, tx)
+	if err != nil {
+		tx.Rollback()
+		return util.AddErrorContext(fmt.Sprintf(contextError, mm.ID), err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf(contextError, mm.ID), err)
+	}
+	mm.Tags = tags
+
+	return nil
+}
+
+func updateMultiMangaTagsDB(multiMangaID ID, tags []string, tx *sql.Tx) error {
+	result, err := tx.Exec(`
+        UPDATE multimangas
+        SET tags = $1
+        WHERE id = $2;
+    `, pq.Array(tags), multiMangaID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errordefs.ErrMultiMangaNotFoundDB
+	}
+
+	return nil
+}
+
+// UpdateTagsInDB updates the custom manga tags in the database.
+// The tags are normalized with NormalizeTags.
+// Mangas from a multimanga use the multimanga tags instead.
+func (m *Manga) UpdateTagsInDB(tags []string) error {
+	contextError := "error updating manga '%s' tags in DB"
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf(contextError, m), err)
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf(contextError, m), err)
+	}
+
+	tags = NormalizeTags(tags)
+	err = updateMangaTagsDB(m, tags, tx)
+	if err != nil {
+		tx.Rollback()
+		return util.AddErrorContext(fmt.Sprintf(contextError, m), err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf(contextError, m), err)
+	}
+	m.Tags = tags
+
+	return nil
+}
+
+func updateMangaTagsDB(m *Manga, tags []string, tx *sql.Tx) error {
+	err := validateManga(m)
+	if err != nil {
+		return err
+	}
+
+	var result sql.Result
+	if m.ID > 0 {
+		result, err = tx.Exec(`
+            UPDATE mangas
+            SET tags = $1
+            WHERE id = $2;
+        `, pq.Array(tags), m.ID)
+		if err != nil {
+			return err
+		}
+	} else if m.URL != "" {
+		result, err = tx.Exec(`
+            UPDATE mangas
+            SET tags = $1
+            WHERE url = $2;
+        `, pq.Array(tags), m.URL)
+		if err != nil {
+			return err
+		}
+	} else {
+		return errordefs.ErrMangaHasNoIDOrURL
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errordefs.ErrMangaNotFoundDB
+	}
+
+	return nil
+}
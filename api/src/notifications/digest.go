@@ -0,0 +1,431 @@
+package notifications
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diogovalentte/mantium/api/src/db"
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/telemetry"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// DigestEvent is a new released chapter buffered to be sent in the digest.
+type DigestEvent struct {
+	ReleasedAt time.Time `json:"released_at"`
+	CreatedAt  time.Time `json:"created_at"`
+	MangaName  string    `json:"manga_name"`
+	MangaURL   string    `json:"manga_url"`
+	// PreviousChapter is the last released chapter before this chapter, empty if none.
+	PreviousChapter string   `json:"previous_chapter"`
+	Chapter         string   `json:"chapter"`
+	ChapterName     string   `json:"chapter_name"`
+	ChapterURL      string   `json:"chapter_url"`
+	ID              int      `json:"id"`
+	MultiMangaID    manga.ID `json:"multimanga_id"`
+	MangaID         manga.ID `json:"manga_id"`
+}
+
+// Digest is the summary of the new released chapters sent by the notifiers.
+type Digest struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+	// Groups are the new chapters grouped by multimanga, sorted by manga name.
+	Groups        []*DigestGroup `json:"groups"`
+	TotalChapters int            `json:"total_chapters"`
+}
+
+// DigestGroup are a multimanga's new chapters in the digest.
+type DigestGroup struct {
+	MangaName string `json:"manga_name"`
+	MangaURL  string `json:"manga_url"`
+	// FirstChapter and LastChapter are the range of new chapters, like "11" and "30".
+	// They're equal when there is only one new chapter.
+	FirstChapter   string `json:"first_chapter"`
+	LastChapter    string `json:"last_chapter"`
+	LastChapterURL string `json:"last_chapter_url"`
+	// Chapters are the released chapters the update job found, from the first to the last.
+	// Sources can release many chapters at once, so it can have less chapters than ChaptersCount.
+	Chapters      []*DigestEvent `json:"chapters"`
+	ChaptersCount int            `json:"chapters_count"`
+	MultiMangaID  manga.ID       `json:"multimanga_id"`
+}
+
+// Range returns the range of new chapters, like "11-30" or "30".
+func (g *DigestGroup) Range() string {
+	if g.FirstChapter == g.LastChapter {
+		return g.LastChapter
+	}
+	return fmt.Sprintf("%s-%s", g.FirstChapter, g.LastChapter)
+}
+
+// BufferDigestEvent adds the manga last released chapter to the digest buffer.
+func BufferDigestEvent(m *manga.Manga, previousChapter *manga.Chapter, now time.Time) error {
+	contextError := "error adding manga '%s' new chapter to the digest"
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf(contextError, m.URL), err)
+	}
+	defer db.Close()
+
+	var previous sql.NullString
+	if previousChapter != nil {
+		previous = sql.NullString{String: previousChapter.Chapter, Valid: true}
+	}
+	var releasedAt sql.NullTime
+	if !m.LastReleasedChapter.UpdatedAt.IsZero() {
+		releasedAt = sql.NullTime{Time: m.LastReleasedChapter.UpdatedAt, Valid: true}
+	}
+
+	_, err = db.Exec(`
+        INSERT INTO digest_events
+            (multimanga_id, manga_id, manga_name, manga_url, previous_chapter, chapter, chapter_name, chapter_url, released_at, created_at)
+        VALUES
+            ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10);
+    `, nullID(m.MultiMangaID), nullID(m.ID), m.Name, m.URL, previous, m.LastReleasedChapter.Chapter, m.LastReleasedChapter.Name, m.LastReleasedChapter.URL, releasedAt, now)
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf(contextError, m.URL), err)
+	}
+
+	return nil
+}
+
+// GetDigestEvents returns the buffered digest events, oldest first.
+func GetDigestEvents() ([]*DigestEvent, error) {
+	contextError := "error getting digest events from DB"
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return nil, util.AddErrorContext(contextError, err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`
+        SELECT
+            id, multimanga_id, manga_id, manga_name, manga_url, previous_chapter, chapter, chapter_name, chapter_url, released_at, created_at
+        FROM
+            digest_events
+        ORDER BY
+            created_at, id;
+    `)
+	if err != nil {
+		return nil, util.AddErrorContext(contextError, err)
+	}
+	defer rows.Close()
+
+	events := []*DigestEvent{}
+	for rows.Next() {
+		var (
+			event                 DigestEvent
+			multiMangaID, mangaID sql.NullInt64
+			previous              sql.NullString
+			releasedAt            sql.NullTime
+		)
+		err = rows.Scan(&event.ID, &multiMangaID, &mangaID, &event.MangaName, &event.MangaURL, &previous, &event.Chapter, &event.ChapterName, &event.ChapterURL, &releasedAt, &event.CreatedAt)
+		if err != nil {
+			return nil, util.AddErrorContext(contextError, err)
+		}
+		event.MultiMangaID = manga.ID(multiMangaID.Int64)
+		event.MangaID = manga.ID(mangaID.Int64)
+		event.PreviousChapter = previous.String
+		event.ReleasedAt = releasedAt.Time
+		events = append(events, &event)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, util.AddErrorContext(contextError, err)
+	}
+
+	return events, nil
+}
+
+// BuildDigest groups the digest events by multimanga. The events must be sorted from oldest to newest.
+func BuildDigest(events []*DigestEvent, from, to time.Time) *Digest {
+	digest := &Digest{From: from, To: to, Groups: []*DigestGroup{}}
+
+	groups := map[string]*DigestGroup{}
+	for _, event := range events {
+		// Events of deleted multimangas are grouped by the manga URL
+		key := event.MangaURL
+		if event.MultiMangaID != 0 {
+			key = event.MultiMangaID.String()
+		}
+		group, ok := groups[key]
+		if !ok {
+			group = &DigestGroup{MultiMangaID: event.MultiMangaID}
+			groups[key] = group
+			digest.Groups = append(digest.Groups, group)
+		}
+		group.Chapters = append(group.Chapters, event)
+	}
+
+	for _, group := range digest.Groups {
+		first := group.Chapters[0]
+		last := group.Chapters[len(group.Chapters)-1]
+		group.MangaName = last.MangaName
+		group.MangaURL = last.MangaURL
+		group.LastChapter = last.Chapter
+		group.LastChapterURL = last.ChapterURL
+		group.FirstChapter, group.ChaptersCount = digestChaptersRange(first.PreviousChapter, first.Chapter, last.Chapter, len(group.Chapters))
+		digest.TotalChapters += group.ChaptersCount
+	}
+
+	sort.SliceStable(digest.Groups, func(i, j int) bool {
+		return digest.Groups[i].MangaName < digest.Groups[j].MangaName
+	})
+
+	return digest
+}
+
+// digestChaptersRange returns the first new chapter and the number of new chapters.
+// When the chapters are numbered, the first new chapter is the one after the previous
+// chapter, so the chapters the update job didn't see are also in the range.
+func digestChaptersRange(previousChapter, firstChapter, lastChapter string, events int) (string, int) {
+	if previousChapter == "" {
+		return firstChapter, events
+	}
+
+	previous := manga.ParseChapterNumber(previousChapter)
+	last := manga.ParseChapterNumber(lastChapter)
+	if !previous.HasNumber || !last.HasNumber || last.Special || (previous.HasVolume && last.HasVolume && previous.Volume != last.Volume) {
+		return firstChapter, events
+	}
+
+	next := math.Floor(previous.Number) + 1
+	if next >= last.Number {
+		return firstChapter, events
+	}
+
+	count := int(math.Floor(last.Number) - math.Floor(previous.Number))
+	if count < events {
+		count = events
+	}
+
+	return strconv.FormatFloat(next, 'f', -1, 64), count
+}
+
+// SendDigestIfDue sends the digest with the notifiers if the digest schedule is
+// due and there are buffered events. It returns true if the digest was sent by
+// any notifier. Each notifier is retried on its own, and the notifiers that sent
+// the digest don't send it again if another notifier fails. The events are kept
+// in the buffer until all notifiers send them, and the failed notifiers send the
+// same digest in the next call, even if the schedule isn't due. If there are no
+// notifiers, the events are kept and an error is returned.
+func SendDigestIfDue(notifiers []DigestNotifier, retries int, retryInterval time.Duration, now time.Time) (bool, error) {
+	contextError := "error sending digest"
+
+	global, err := GetGlobalRules()
+	if err != nil {
+		return false, util.AddErrorContext(contextError, err)
+	}
+
+	events, err := GetDigestEvents()
+	if err != nil {
+		return false, util.AddErrorContext(contextError, err)
+	}
+	if len(events) == 0 {
+		return false, nil
+	}
+
+	sentEventIDs, err := getDigestNotifiersLastEventID()
+	if err != nil {
+		return false, util.AddErrorContext(contextError, err)
+	}
+
+	lastSentAt, err := getDigestLastSentAt()
+	if err != nil {
+		return false, util.AddErrorContext(contextError, err)
+	}
+	from := events[0].CreatedAt
+	if !lastSentAt.IsZero() && lastSentAt.After(from) {
+		from = lastSentAt
+	}
+
+	events, partial := digestBatch(events, notifiers, sentEventIDs)
+	if !partial && global.NextDigestTime(from).After(now) {
+		return false, nil
+	}
+	if len(notifiers) == 0 {
+		return false, util.AddErrorContext(contextError, errordefs.ErrNoDigestNotifiers)
+	}
+	lastEventID := events[len(events)-1].ID
+
+	var sent bool
+	var errors []string
+	for _, notifier := range notifiers {
+		pending := digestNotifierEvents(events, sentEventIDs[notifier.Name()])
+		if len(pending) == 0 {
+			continue
+		}
+		err = sendDigestWithRetries(notifier, BuildDigest(pending, from, now), retries, retryInterval)
+		if err != nil {
+			errors = append(errors, err.Error())
+			continue
+		}
+		sent = true
+		err = setDigestNotifierLastEventID(notifier.Name(), lastEventID)
+		if err != nil {
+			errors = append(errors, err.Error())
+		}
+	}
+	if len(errors) > 0 {
+		return sent, util.AddErrorContext(contextError, fmt.Errorf("%s", strings.Join(errors, "; ")))
+	}
+
+	err = deleteDigestEvents(lastEventID, now)
+	if err != nil {
+		return true, util.AddErrorContext(contextError, err)
+	}
+
+	return true, nil
+}
+
+// digestBatch returns the events of the digest to send. If some notifiers sent a digest
+// that other notifiers failed to send, it's partial and only the events of the failed digest
+// are returned, else all events are returned. The events must be sorted from oldest to newest.
+func digestBatch(events []*DigestEvent, notifiers []DigestNotifier, sentEventIDs map[string]int) ([]*DigestEvent, bool) {
+	var lastSentEventID int
+	for _, notifier := range notifiers {
+		if id := sentEventIDs[notifier.Name()]; id > lastSentEventID {
+			lastSentEventID = id
+		}
+	}
+	if lastSentEventID < events[0].ID {
+		return events, false
+	}
+
+	batch := []*DigestEvent{}
+	for _, event := range events {
+		if event.ID <= lastSentEventID {
+			batch = append(batch, event)
+		}
+	}
+
+	return batch, true
+}
+
+// digestNotifierEvents returns the events the notifier didn't send yet.
+func digestNotifierEvents(events []*DigestEvent, sentEventID int) []*DigestEvent {
+	pending := []*DigestEvent{}
+	for _, event := range events {
+		if event.ID > sentEventID {
+			pending = append(pending, event)
+		}
+	}
+
+	return pending
+}
+
+func sendDigestWithRetries(notifier DigestNotifier, digest *Digest, retries int, retryInterval time.Duration) error {
+	// The digest is always sent at least once
+	retries = max(retries, 1)
+
+	var err error
+	for i := 0; i < retries; i++ {
+		err = notifier.SendDigest(digest)
+		telemetry.ObserveNotification(notifier.Name(), err)
+		if err == nil {
+			return nil
+		}
+		if i != retries-1 {
+			time.Sleep(retryInterval)
+		}
+	}
+
+	return err
+}
+
+// getDigestNotifiersLastEventID returns the ID of the last event sent by each notifier.
+func getDigestNotifiersLastEventID() (map[string]int, error) {
+	db, err := db.OpenConn()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`SELECT notifier, last_event_id FROM digest_notifiers;`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sentEventIDs := map[string]int{}
+	for rows.Next() {
+		var notifier string
+		var lastEventID int
+		err = rows.Scan(&notifier, &lastEventID)
+		if err != nil {
+			return nil, err
+		}
+		sentEventIDs[notifier] = lastEventID
+	}
+
+	return sentEventIDs, rows.Err()
+}
+
+func setDigestNotifierLastEventID(notifier string, lastEventID int) error {
+	db, err := db.OpenConn()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	_, err = db.Exec(`
+        INSERT INTO digest_notifiers (notifier, last_event_id)
+        VALUES ($1, $2)
+        ON CONFLICT (notifier) DO UPDATE SET last_event_id = EXCLUDED.last_event_id;
+    `, notifier, lastEventID)
+
+	return err
+}
+
+func getDigestLastSentAt() (time.Time, error) {
+	db, err := db.OpenConn()
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer db.Close()
+
+	var lastSentAt sql.NullTime
+	err = db.QueryRow(`SELECT digest_last_sent_at FROM notification_rules WHERE multimanga_id IS NULL;`).Scan(&lastSentAt)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return lastSentAt.Time, nil
+}
+
+// deleteDigestEvents deletes the sent events up to the last sent event ID
+// and saves when the digest was sent.
+func deleteDigestEvents(lastEventID int, sentAt time.Time) error {
+	db, err := db.OpenConn()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`DELETE FROM digest_events WHERE id <= $1;`, lastEventID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	_, err = tx.Exec(`UPDATE notification_rules SET digest_last_sent_at = $1 WHERE multimanga_id IS NULL;`, sentAt)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
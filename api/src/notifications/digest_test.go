@@ -0,0 +1,141 @@
+package notifications
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildDigest(t *testing.T) {
+	now := time.Date(2024, 5, 10, 9, 0, 0, 0, time.UTC)
+	events := []*DigestEvent{
+		{MultiMangaID: 1, MangaName: "One Piece", MangaURL: "https://mangadex.org/title/1", PreviousChapter: "10", Chapter: "30", ChapterURL: "https://mangadex.org/chapter/30"},
+		{MultiMangaID: 2, MangaName: "Berserk", MangaURL: "https://mangadex.org/title/2", PreviousChapter: "100", Chapter: "101", ChapterURL: "https://mangadex.org/chapter/101"},
+		{MultiMangaID: 1, MangaName: "One Piece", MangaURL: "https://mangadex.org/title/1", PreviousChapter: "30", Chapter: "31", ChapterURL: "https://mangadex.org/chapter/31"},
+		{MultiMangaID: 3, MangaName: "Custom", MangaURL: "https://mangadex.org/title/3", PreviousChapter: "5", Chapter: "Extra", ChapterURL: "https://mangadex.org/chapter/extra"},
+	}
+
+	digest := BuildDigest(events, now.Add(-24*time.Hour), now)
+	if len(digest.Groups) != 3 {
+		t.Fatalf("expected 3 groups, got %d", len(digest.Groups))
+	}
+	// 21 + 1 + 1
+	if digest.TotalChapters != 23 {
+		t.Fatalf("expected 23 chapters, got %d", digest.TotalChapters)
+	}
+
+	expected := []struct {
+		name   string
+		rng    string
+		count  int
+		events int
+	}{
+		{"Berserk", "101", 1, 1},
+		{"Custom", "Extra", 1, 1},
+		{"One Piece", "11-31", 21, 2},
+	}
+	for i, e := range expected {
+		group := digest.Groups[i]
+		if group.MangaName != e.name || group.Range() != e.rng || group.ChaptersCount != e.count || len(group.Chapters) != e.events {
+			t.Fatalf("group %d: expected %+v, got name %s, range %s, count %d, events %d", i, e, group.MangaName, group.Range(), group.ChaptersCount, len(group.Chapters))
+		}
+	}
+	if digest.Groups[2].LastChapterURL != "https://mangadex.org/chapter/31" {
+		t.Fatalf("expected the last chapter URL, got %s", digest.Groups[2].LastChapterURL)
+	}
+}
+
+func TestRenderDigest(t *testing.T) {
+	now := time.Date(2024, 5, 10, 9, 0, 0, 0, time.UTC)
+	events := []*DigestEvent{
+		{MultiMangaID: 1, MangaName: `One "Piece" <b>`, MangaURL: "https://mangadex.org/title/1", PreviousChapter: "10", Chapter: "12", ChapterURL: "https://mangadex.org/chapter/12"},
+	}
+	digest := BuildDigest(events, now.Add(-24*time.Hour), now)
+
+	t.Run("Should render Ntfy markdown", func(t *testing.T) {
+		rendered, err := RenderDigest(NtfyDigestTemplate, digest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(rendered, "chapters 11-12 (2 new)") || !strings.Contains(rendered, "(https://mangadex.org/chapter/12)") {
+			t.Fatalf("unexpected rendered digest: %s", rendered)
+		}
+	})
+	t.Run("Should render escaped HTML email", func(t *testing.T) {
+		rendered, err := RenderDigest(EmailDigestTemplate, digest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(rendered, "<b>") || !strings.Contains(rendered, `href="https://mangadex.org/chapter/12"`) {
+			t.Fatalf("unexpected rendered digest: %s", rendered)
+		}
+	})
+	t.Run("Should render valid webhook JSON", func(t *testing.T) {
+		rendered, err := RenderDigest(WebhookDigestTemplate, digest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var payload struct {
+			Mangas []struct {
+				Name  string `json:"name"`
+				Range string `json:"range"`
+			} `json:"mangas"`
+			TotalChapters int `json:"total_chapters"`
+		}
+		err = json.Unmarshal([]byte(rendered), &payload)
+		if err != nil {
+			t.Fatalf("invalid JSON: %s\n%s", err, rendered)
+		}
+		if payload.TotalChapters != 2 || len(payload.Mangas) != 1 || payload.Mangas[0].Name != `One "Piece" <b>` || payload.Mangas[0].Range != "11-12" {
+			t.Fatalf("unexpected payload: %+v", payload)
+		}
+	})
+}
+
+func TestDigestBatch(t *testing.T) {
+	events := []*DigestEvent{{ID: 4}, {ID: 5}, {ID: 7}}
+	notifiers := []DigestNotifier{&NtfyDigestNotifier{}, &EmailDigestNotifier{}}
+
+	t.Run("Should send all events if no notifier sent them", func(t *testing.T) {
+		batch, partial := digestBatch(events, notifiers, map[string]int{"ntfy": 3, "email": 3})
+		if partial || len(batch) != 3 {
+			t.Fatalf("expected all 3 events and not partial, got %d events and partial %t", len(batch), partial)
+		}
+	})
+	t.Run("Should send only the failed digest events to the notifier that failed", func(t *testing.T) {
+		sentEventIDs := map[string]int{"ntfy": 5, "email": 3}
+		batch, partial := digestBatch(events, notifiers, sentEventIDs)
+		if !partial || len(batch) != 2 || batch[1].ID != 5 {
+			t.Fatalf("expected events up to 5 and partial, got %d events and partial %t", len(batch), partial)
+		}
+		if pending := digestNotifierEvents(batch, sentEventIDs["ntfy"]); len(pending) != 0 {
+			t.Fatalf("expected no pending events for ntfy, got %d", len(pending))
+		}
+		if pending := digestNotifierEvents(batch, sentEventIDs["email"]); len(pending) != 2 {
+			t.Fatalf("expected 2 pending events for email, got %d", len(pending))
+		}
+	})
+}
+
+type countingDigestNotifier struct {
+	sent int
+}
+
+func (n *countingDigestNotifier) Name() string { return "counting" }
+
+func (n *countingDigestNotifier) SendDigest(*Digest) error {
+	n.sent++
+	return nil
+}
+
+func TestSendDigestWithRetries(t *testing.T) {
+	notifier := &countingDigestNotifier{}
+	err := sendDigestWithRetries(notifier, &Digest{}, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if notifier.sent != 1 {
+		t.Fatalf("expected the digest to be sent once with 0 retries, got %d", notifier.sent)
+	}
+}
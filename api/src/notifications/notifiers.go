@@ -0,0 +1,235 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/diogovalentte/mantium/api/src/config"
+	"github.com/diogovalentte/mantium/api/src/integrations/ntfy"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+//go:embed templates
+var defaultTemplates embed.FS
+
+const (
+	// NtfyDigestTemplate is the Ntfy digest template file name. It's rendered as markdown.
+	NtfyDigestTemplate = "digest_ntfy.md.tmpl"
+	// EmailDigestTemplate is the email digest template file name. It's rendered as HTML.
+	EmailDigestTemplate = "digest_email.html.tmpl"
+	// WebhookDigestTemplate is the webhook digest template file name. It's rendered as a JSON payload.
+	WebhookDigestTemplate = "digest_webhook.json.tmpl"
+)
+
+// DigestNotifier sends the digest to a notification service.
+type DigestNotifier interface {
+	// Name returns the notifier name, like "ntfy".
+	Name() string
+	SendDigest(digest *Digest) error
+}
+
+// GetDigestNotifiers returns the configured digest notifiers.
+func GetDigestNotifiers() []DigestNotifier {
+	notifiers := []DigestNotifier{}
+	if config.GlobalConfigs.Ntfy.Address != "" {
+		notifiers = append(notifiers, &NtfyDigestNotifier{})
+	}
+	if config.GlobalConfigs.Email.Valid {
+		notifiers = append(notifiers, &EmailDigestNotifier{})
+	}
+	if config.GlobalConfigs.Webhook.Valid {
+		notifiers = append(notifiers, &WebhookDigestNotifier{})
+	}
+
+	return notifiers
+}
+
+// NtfyDigestNotifier sends the digest to Ntfy as a markdown message.
+type NtfyDigestNotifier struct{}
+
+// Name returns the notifier name.
+func (n *NtfyDigestNotifier) Name() string {
+	return "ntfy"
+}
+
+// SendDigest sends the digest to Ntfy.
+func (n *NtfyDigestNotifier) SendDigest(digest *Digest) error {
+	contextError := "error sending digest to Ntfy"
+
+	message, err := RenderDigest(NtfyDigestTemplate, digest)
+	if err != nil {
+		return util.AddErrorContext(contextError, err)
+	}
+
+	publisher, err := ntfy.GetNtfyPublisher()
+	if err != nil {
+		return util.AddErrorContext(contextError, err)
+	}
+
+	var clickURL *url.URL
+	if len(digest.Groups) == 1 {
+		clickURL, _ = url.Parse(digest.Groups[0].LastChapterURL)
+	}
+
+	err = publisher.SendMarkdownMessage(context.Background(), digestTitle(digest), message, clickURL)
+	if err != nil {
+		return util.AddErrorContext(contextError, err)
+	}
+
+	return nil
+}
+
+// EmailDigestNotifier sends the digest as an HTML email.
+type EmailDigestNotifier struct{}
+
+// Name returns the notifier name.
+func (n *EmailDigestNotifier) Name() string {
+	return "email"
+}
+
+// SendDigest sends the digest email.
+func (n *EmailDigestNotifier) SendDigest(digest *Digest) error {
+	contextError := "error sending digest email"
+
+	body, err := RenderDigest(EmailDigestTemplate, digest)
+	if err != nil {
+		return util.AddErrorContext(contextError, err)
+	}
+
+	configs := config.GlobalConfigs.Email
+	var msg bytes.Buffer
+	msg.WriteString(fmt.Sprintf("From: %s\r\n", configs.From))
+	msg.WriteString(fmt.Sprintf("To: %s\r\n", strings.Join(configs.To, ", ")))
+	msg.WriteString(fmt.Sprintf("Subject: %s\r\n", digestTitle(digest)))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/html; charset=\"UTF-8\"\r\n\r\n")
+	msg.WriteString(body)
+
+	var auth smtp.Auth
+	if configs.Username != "" {
+		auth = smtp.PlainAuth("", configs.Username, configs.Password, configs.Host)
+	}
+
+	err = smtp.SendMail(fmt.Sprintf("%s:%s", configs.Host, configs.Port), auth, configs.From, configs.To, msg.Bytes())
+	if err != nil {
+		return util.AddErrorContext(contextError, err)
+	}
+
+	return nil
+}
+
+// WebhookDigestNotifier sends the digest as a JSON payload to a webhook.
+type WebhookDigestNotifier struct{}
+
+// Name returns the notifier name.
+func (n *WebhookDigestNotifier) Name() string {
+	return "webhook"
+}
+
+// SendDigest sends the digest to the webhook.
+func (n *WebhookDigestNotifier) SendDigest(digest *Digest) error {
+	contextError := "error sending digest to webhook"
+
+	payload, err := RenderDigest(WebhookDigestTemplate, digest)
+	if err != nil {
+		return util.AddErrorContext(contextError, err)
+	}
+	if !json.Valid([]byte(payload)) {
+		return util.AddErrorContext(contextError, fmt.Errorf("the webhook template didn't render a valid JSON"))
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Post(config.GlobalConfigs.Webhook.URL, "application/json", strings.NewReader(payload))
+	if err != nil {
+		return util.AddErrorContext(contextError, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return util.AddErrorContext(contextError, fmt.Errorf("non-2xx status code -> (%d)", resp.StatusCode))
+	}
+
+	return nil
+}
+
+func digestTitle(digest *Digest) string {
+	if digest.TotalChapters == 1 {
+		return "(Mantium) 1 new chapter"
+	}
+	return fmt.Sprintf("(Mantium) %d new chapters of %d mangas", digest.TotalChapters, len(digest.Groups))
+}
+
+// RenderDigest renders the digest with the template. The template is read
+// from the notifications templates directory if it exists there, else the
+// default template is used. HTML templates are escaped as HTML.
+func RenderDigest(templateName string, digest *Digest) (string, error) {
+	contextError := fmt.Sprintf("error rendering digest template '%s'", templateName)
+
+	content, err := readTemplate(templateName)
+	if err != nil {
+		return "", util.AddErrorContext(contextError, err)
+	}
+
+	funcs := map[string]any{
+		"json": func(v any) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		},
+	}
+
+	var buf bytes.Buffer
+	if strings.HasSuffix(templateName, ".html.tmpl") {
+		tmpl, err := htmltemplate.New(templateName).Funcs(funcs).Parse(content)
+		if err != nil {
+			return "", util.AddErrorContext(contextError, err)
+		}
+		err = tmpl.Execute(&buf, digest)
+		if err != nil {
+			return "", util.AddErrorContext(contextError, err)
+		}
+	} else {
+		tmpl, err := texttemplate.New(templateName).Funcs(funcs).Parse(content)
+		if err != nil {
+			return "", util.AddErrorContext(contextError, err)
+		}
+		err = tmpl.Execute(&buf, digest)
+		if err != nil {
+			return "", util.AddErrorContext(contextError, err)
+		}
+	}
+
+	return strings.TrimSpace(buf.String()), nil
+}
+
+func readTemplate(templateName string) (string, error) {
+	templatesDir := config.GlobalConfigs.Notifications.TemplatesDir
+	if templatesDir != "" {
+		path := filepath.Join(templatesDir, templateName)
+		if util.FileExists(path) {
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return "", err
+			}
+			return string(content), nil
+		}
+	}
+
+	content, err := defaultTemplates.ReadFile("templates/" + templateName)
+	if err != nil {
+		return "", err
+	}
+
+	return string(content), nil
+}
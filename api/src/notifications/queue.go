@@ -0,0 +1,165 @@
+package notifications
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/diogovalentte/mantium/api/src/db"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// QueuedNotification is a new chapter notification deferred by the rules.
+type QueuedNotification struct {
+	CreatedAt    time.Time `json:"created_at"`
+	DeliverAfter time.Time `json:"deliver_after"`
+	MangaName    string    `json:"manga_name"`
+	MangaURL     string    `json:"manga_url"`
+	Chapter      string    `json:"chapter"`
+	ChapterName  string    `json:"chapter_name"`
+	ChapterURL   string    `json:"chapter_url"`
+	// Reason is why the notification was deferred, like "quiet_hours".
+	Reason       string   `json:"reason"`
+	ID           int      `json:"id"`
+	MultiMangaID manga.ID `json:"multimanga_id"`
+	MangaID      manga.ID `json:"manga_id"`
+}
+
+// Enqueue adds the manga last released chapter notification to the queue.
+// It replaces the multimanga's older notifications, as only the last released chapter is notified.
+// If the manga isn't in a multimanga, it replaces the manga's older notifications.
+func Enqueue(m *manga.Manga, decision Decision, now time.Time) error {
+	contextError := "error adding notification of manga '%s' to the queue"
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf(contextError, m.URL), err)
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf(contextError, m.URL), err)
+	}
+
+	if m.MultiMangaID != 0 {
+		_, err = tx.Exec(`
+            DELETE FROM notification_queue
+            WHERE multimanga_id = $1;
+        `, m.MultiMangaID)
+	} else {
+		_, err = tx.Exec(`
+            DELETE FROM notification_queue
+            WHERE multimanga_id IS NULL AND manga_id = $1;
+        `, m.ID)
+	}
+	if err != nil {
+		tx.Rollback()
+		return util.AddErrorContext(fmt.Sprintf(contextError, m.URL), err)
+	}
+
+	_, err = tx.Exec(`
+        INSERT INTO notification_queue
+            (multimanga_id, manga_id, manga_name, manga_url, chapter, chapter_name, chapter_url, reason, created_at, deliver_after)
+        VALUES
+            ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10);
+    `, nullID(m.MultiMangaID), nullID(m.ID), m.Name, m.URL, m.LastReleasedChapter.Chapter, m.LastReleasedChapter.Name, m.LastReleasedChapter.URL, decision.Reason, now, decision.DeliverAfter)
+	if err != nil {
+		tx.Rollback()
+		return util.AddErrorContext(fmt.Sprintf(contextError, m.URL), err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf(contextError, m.URL), err)
+	}
+
+	return nil
+}
+
+// GetQueue returns the queued notifications ordered by when they should be sent.
+// If onlyDue is true, it returns only the notifications that should be sent until now.
+func GetQueue(onlyDue bool, now time.Time) ([]*QueuedNotification, error) {
+	contextError := "error getting notifications queue from DB"
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return nil, util.AddErrorContext(contextError, err)
+	}
+	defer db.Close()
+
+	query := `
+        SELECT
+            id, multimanga_id, manga_id, manga_name, manga_url, chapter, chapter_name, chapter_url, reason, created_at, deliver_after
+        FROM
+            notification_queue
+    `
+	args := []interface{}{}
+	if onlyDue {
+		query += " WHERE deliver_after <= $1"
+		args = append(args, now)
+	}
+	query += " ORDER BY deliver_after, id;"
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, util.AddErrorContext(contextError, err)
+	}
+	defer rows.Close()
+
+	queue := []*QueuedNotification{}
+	for rows.Next() {
+		var n QueuedNotification
+		var multiMangaID, mangaID sql.NullInt64
+		err = rows.Scan(&n.ID, &multiMangaID, &mangaID, &n.MangaName, &n.MangaURL, &n.Chapter, &n.ChapterName, &n.ChapterURL, &n.Reason, &n.CreatedAt, &n.DeliverAfter)
+		if err != nil {
+			return nil, util.AddErrorContext(contextError, err)
+		}
+		n.MultiMangaID = manga.ID(multiMangaID.Int64)
+		n.MangaID = manga.ID(mangaID.Int64)
+		queue = append(queue, &n)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, util.AddErrorContext(contextError, err)
+	}
+
+	return queue, nil
+}
+
+// DeleteFromQueue deletes the notifications from the queue.
+func DeleteFromQueue(ids []int) error {
+	contextError := "error deleting notifications from the queue"
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return util.AddErrorContext(contextError, err)
+	}
+	defer db.Close()
+
+	ids64 := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		ids64 = append(ids64, int64(id))
+	}
+	_, err = db.Exec(`DELETE FROM notification_queue WHERE id = ANY($1);`, pq.Array(ids64))
+	if err != nil {
+		return util.AddErrorContext(contextError, err)
+	}
+
+	return nil
+}
+
+// nullID returns a NULL for the ID 0, as the ID columns reference other tables.
+func nullID(id manga.ID) sql.NullInt64 {
+	if id == 0 {
+		return sql.NullInt64{}
+	}
+
+	return sql.NullInt64{Int64: int64(id), Valid: true}
+}
@@ -0,0 +1,444 @@
+// Package notifications implements the rules that decide if and when
+// the user is notified about new chapters and the deferred notifications queue
+package notifications
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/diogovalentte/mantium/api/src/db"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// Rules are the notification rules of a multimanga or the global rules.
+// In a multimanga's rules, nil fields inherit the global rules' value.
+type Rules struct {
+	// Mute disables the notifications.
+	Mute *bool `json:"mute"`
+	// NumberedChaptersOnly notifies only chapters with a number that aren't specials,
+	// like "10" and "10.5", but not "Extra" or "10 Extra".
+	NumberedChaptersOnly *bool `json:"numbered_chapters_only"`
+	// Digest buffers the new chapters into a digest sent on the digest schedule.
+	Digest *bool `json:"digest"`
+	// MinGapMinutes is the minimum time between two notifications of the same multimanga.
+	// Notifications inside the gap are deferred to the end of the gap.
+	MinGapMinutes *int `json:"min_gap_minutes"`
+	// QuietHoursStart and QuietHoursEnd are like "22:00" and "07:00", in the API timezone.
+	// Notifications during the quiet hours are deferred to the end of the quiet hours.
+	QuietHoursStart *string `json:"quiet_hours_start"`
+	QuietHoursEnd   *string `json:"quiet_hours_end"`
+	// DigestFrequency, DigestWeekday, and DigestTime are the digest schedule.
+	// They're only used in the global rules. DigestFrequency is "daily" or "weekly",
+	// DigestWeekday is the weekly digest day, from 0 (Sunday) to 6 (Saturday), and
+	// DigestTime is when the digest is sent, like "09:00".
+	DigestFrequency *string `json:"digest_frequency"`
+	DigestWeekday   *int    `json:"digest_weekday"`
+	DigestTime      *string `json:"digest_time"`
+	// MultiMangaID is 0 for the global rules.
+	MultiMangaID manga.ID `json:"multimanga_id"`
+}
+
+// Action is what to do with a new chapter notification.
+type Action int
+
+const (
+	// ActionSend sends the notification now.
+	ActionSend Action = iota
+	// ActionSkip doesn't send the notification.
+	ActionSkip
+	// ActionDefer adds the notification to the deferred queue,
+	// or to the digest buffer if the reason is ReasonDigest.
+	ActionDefer
+)
+
+const (
+	// ReasonQuietHours is the reason of notifications deferred by the quiet hours.
+	ReasonQuietHours = "quiet_hours"
+	// ReasonMinGap is the reason of notifications deferred by the minimum gap.
+	ReasonMinGap = "min_gap"
+	// ReasonDigest is the reason of notifications buffered into the digest.
+	ReasonDigest = "digest"
+	// ReasonMuted is the reason of notifications skipped because the multimanga is muted.
+	ReasonMuted = "muted"
+	// ReasonNotNumbered is the reason of notifications skipped because the chapter isn't numbered.
+	ReasonNotNumbered = "not_numbered"
+)
+
+const (
+	// DigestFrequencyDaily sends the digest every day.
+	DigestFrequencyDaily = "daily"
+	// DigestFrequencyWeekly sends the digest every week.
+	DigestFrequencyWeekly = "weekly"
+)
+
+// Decision is the result of evaluating the rules for a new chapter.
+type Decision struct {
+	// DeliverAfter is when a deferred notification should be sent.
+	DeliverAfter time.Time
+	Reason       string
+	Action       Action
+}
+
+// Merge returns the rules with the nil fields set to the global rules' value.
+func (r *Rules) Merge(global *Rules) *Rules {
+	merged := *global
+	merged.MultiMangaID = r.MultiMangaID
+	if r.Mute != nil {
+		merged.Mute = r.Mute
+	}
+	if r.NumberedChaptersOnly != nil {
+		merged.NumberedChaptersOnly = r.NumberedChaptersOnly
+	}
+	if r.Digest != nil {
+		merged.Digest = r.Digest
+	}
+	if r.MinGapMinutes != nil {
+		merged.MinGapMinutes = r.MinGapMinutes
+	}
+	if r.QuietHoursStart != nil && r.QuietHoursEnd != nil {
+		merged.QuietHoursStart = r.QuietHoursStart
+		merged.QuietHoursEnd = r.QuietHoursEnd
+	}
+
+	return &merged
+}
+
+// Validate validates the rules' values.
+func (r *Rules) Validate() error {
+	contextError := "error validating notification rules"
+
+	if r.MinGapMinutes != nil && *r.MinGapMinutes < 0 {
+		return util.AddErrorContext(contextError, fmt.Errorf("min_gap_minutes must be greater than or equal to 0"))
+	}
+	if (r.QuietHoursStart == nil) != (r.QuietHoursEnd == nil) {
+		return util.AddErrorContext(contextError, fmt.Errorf("quiet_hours_start and quiet_hours_end must be provided together"))
+	}
+	if r.DigestFrequency != nil && *r.DigestFrequency != DigestFrequencyDaily && *r.DigestFrequency != DigestFrequencyWeekly {
+		return util.AddErrorContext(contextError, fmt.Errorf("digest_frequency must be '%s' or '%s'", DigestFrequencyDaily, DigestFrequencyWeekly))
+	}
+	if r.DigestWeekday != nil && (*r.DigestWeekday < 0 || *r.DigestWeekday > 6) {
+		return util.AddErrorContext(contextError, fmt.Errorf("digest_weekday must be between 0 (Sunday) and 6 (Saturday)"))
+	}
+	for name, value := range map[string]*string{"quiet_hours_start": r.QuietHoursStart, "quiet_hours_end": r.QuietHoursEnd, "digest_time": r.DigestTime} {
+		if value == nil || *value == "" {
+			continue
+		}
+		if _, err := parseClock(*value); err != nil {
+			return util.AddErrorContext(contextError, fmt.Errorf("%s must be like HH:MM", name))
+		}
+	}
+
+	return nil
+}
+
+// Evaluate decides what to do with the notification of the chapter using the merged rules.
+// lastNotifiedAt is when the multimanga was last notified, zero if never.
+func (r *Rules) Evaluate(chapter *manga.Chapter, lastNotifiedAt, now time.Time) Decision {
+	if r.Mute != nil && *r.Mute {
+		return Decision{Action: ActionSkip, Reason: ReasonMuted}
+	}
+	if r.NumberedChaptersOnly != nil && *r.NumberedChaptersOnly {
+		number := manga.ParseChapterNumber(chapter.Chapter)
+		if !number.HasNumber || number.Special {
+			return Decision{Action: ActionSkip, Reason: ReasonNotNumbered}
+		}
+	}
+	if r.Digest != nil && *r.Digest {
+		return Decision{Action: ActionDefer, Reason: ReasonDigest, DeliverAfter: r.NextDigestTime(now)}
+	}
+	if r.MinGapMinutes != nil && *r.MinGapMinutes > 0 && !lastNotifiedAt.IsZero() {
+		gapEnd := lastNotifiedAt.Add(time.Duration(*r.MinGapMinutes) * time.Minute)
+		if gapEnd.After(now) {
+			return Decision{Action: ActionDefer, Reason: ReasonMinGap, DeliverAfter: r.afterQuietHours(gapEnd)}
+		}
+	}
+	if deliverAfter := r.afterQuietHours(now); !deliverAfter.Equal(now) {
+		return Decision{Action: ActionDefer, Reason: ReasonQuietHours, DeliverAfter: deliverAfter}
+	}
+
+	return Decision{Action: ActionSend}
+}
+
+// afterQuietHours returns t if it's not in the quiet hours,
+// else it returns the end of the quiet hours.
+func (r *Rules) afterQuietHours(t time.Time) time.Time {
+	if r.QuietHoursStart == nil || r.QuietHoursEnd == nil || *r.QuietHoursStart == "" || *r.QuietHoursEnd == "" {
+		return t
+	}
+	start, err := parseClock(*r.QuietHoursStart)
+	if err != nil {
+		return t
+	}
+	end, err := parseClock(*r.QuietHoursEnd)
+	if err != nil || start == end {
+		return t
+	}
+
+	minutes := t.Hour()*60 + t.Minute()
+	var inQuietHours bool
+	if start < end {
+		inQuietHours = minutes >= start && minutes < end
+	} else {
+		inQuietHours = minutes >= start || minutes < end
+	}
+	if !inQuietHours {
+		return t
+	}
+
+	return nextClock(t, end)
+}
+
+// NextDigestTime returns the next digest time after t using the digest schedule.
+func (r *Rules) NextDigestTime(t time.Time) time.Time {
+	digestTime := 9 * 60
+	if r.DigestTime != nil {
+		if clock, err := parseClock(*r.DigestTime); err == nil {
+			digestTime = clock
+		}
+	}
+
+	next := nextClock(t, digestTime)
+	if r.DigestFrequency != nil && *r.DigestFrequency == DigestFrequencyWeekly {
+		weekday := time.Monday
+		if r.DigestWeekday != nil {
+			weekday = time.Weekday(*r.DigestWeekday)
+		}
+		next = next.AddDate(0, 0, (int(weekday)-int(next.Weekday())+7)%7)
+	}
+
+	return next
+}
+
+// parseClock parses a time like "22:30" and returns the minutes since midnight.
+func parseClock(clock string) (int, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, err
+	}
+
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// nextClock returns the next time after t at the clock minutes since midnight.
+func nextClock(t time.Time, clock int) time.Time {
+	next := time.Date(t.Year(), t.Month(), t.Day(), clock/60, clock%60, 0, 0, t.Location())
+	if !next.After(t) {
+		next = next.AddDate(0, 0, 1)
+	}
+
+	return next
+}
+
+// GetGlobalRules returns the global notification rules.
+func GetGlobalRules() (*Rules, error) {
+	return GetRules(0)
+}
+
+// GetRules returns the notification rules of the multimanga, or the global
+// rules if multiMangaID is 0. If the multimanga has no rules, all fields are nil.
+func GetRules(multiMangaID manga.ID) (*Rules, error) {
+	contextError := "error getting notification rules of multimanga '%d' from DB"
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, multiMangaID), err)
+	}
+	defer db.Close()
+
+	rules, _, err := getRulesFromDB(multiMangaID, db)
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, multiMangaID), err)
+	}
+
+	return rules, nil
+}
+
+// GetEffectiveRules returns the multimanga rules merged with the global rules
+// and when the multimanga was last notified.
+func GetEffectiveRules(multiMangaID manga.ID) (*Rules, time.Time, error) {
+	contextError := "error getting effective notification rules of multimanga '%d' from DB"
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return nil, time.Time{}, util.AddErrorContext(fmt.Sprintf(contextError, multiMangaID), err)
+	}
+	defer db.Close()
+
+	global, _, err := getRulesFromDB(0, db)
+	if err != nil {
+		return nil, time.Time{}, util.AddErrorContext(fmt.Sprintf(contextError, multiMangaID), err)
+	}
+	if multiMangaID == 0 {
+		return global, time.Time{}, nil
+	}
+
+	rules, lastNotifiedAt, err := getRulesFromDB(multiMangaID, db)
+	if err != nil {
+		return nil, time.Time{}, util.AddErrorContext(fmt.Sprintf(contextError, multiMangaID), err)
+	}
+
+	return rules.Merge(global), lastNotifiedAt, nil
+}
+
+func getRulesFromDB(multiMangaID manga.ID, db *sql.DB) (*Rules, time.Time, error) {
+	var (
+		rules                       Rules
+		mute, numberedOnly, digest  sql.NullBool
+		minGap                      sql.NullInt64
+		quietStart, quietEnd        sql.NullString
+		digestTime, digestFrequency sql.NullString
+		digestWeekday               sql.NullInt64
+		lastNotifiedAt              sql.NullTime
+	)
+	rules.MultiMangaID = multiMangaID
+
+	var multiMangaIDArg interface{}
+	if multiMangaID != 0 {
+		multiMangaIDArg = multiMangaID
+	}
+	err := db.QueryRow(`
+        SELECT
+            mute, numbered_chapters_only, digest, min_gap_minutes, quiet_hours_start, quiet_hours_end, digest_frequency, digest_weekday, digest_time, last_notified_at
+        FROM
+            notification_rules
+        WHERE
+            multimanga_id IS NOT DISTINCT FROM $1;
+    `, multiMangaIDArg).Scan(&mute, &numberedOnly, &digest, &minGap, &quietStart, &quietEnd, &digestFrequency, &digestWeekday, &digestTime, &lastNotifiedAt)
+	if err != nil {
+		if err == sql.ErrNoRows && multiMangaID != 0 {
+			return &rules, time.Time{}, nil
+		}
+		return nil, time.Time{}, err
+	}
+
+	if mute.Valid {
+		rules.Mute = &mute.Bool
+	}
+	if numberedOnly.Valid {
+		rules.NumberedChaptersOnly = &numberedOnly.Bool
+	}
+	if digest.Valid {
+		rules.Digest = &digest.Bool
+	}
+	if minGap.Valid {
+		v := int(minGap.Int64)
+		rules.MinGapMinutes = &v
+	}
+	if quietStart.Valid && quietEnd.Valid {
+		rules.QuietHoursStart = &quietStart.String
+		rules.QuietHoursEnd = &quietEnd.String
+	}
+	if digestFrequency.Valid {
+		rules.DigestFrequency = &digestFrequency.String
+	}
+	if digestWeekday.Valid {
+		v := int(digestWeekday.Int64)
+		rules.DigestWeekday = &v
+	}
+	if digestTime.Valid {
+		rules.DigestTime = &digestTime.String
+	}
+
+	return &rules, lastNotifiedAt.Time, nil
+}
+
+// SaveRules saves the rules in the DB. For the global rules, nil fields
+// keep their current value. For a multimanga's rules, nil fields inherit
+// the global rules' value.
+func SaveRules(rules *Rules) error {
+	contextError := "error saving notification rules of multimanga '%d' in DB"
+
+	err := rules.Validate()
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf(contextError, rules.MultiMangaID), err)
+	}
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf(contextError, rules.MultiMangaID), err)
+	}
+	defer db.Close()
+
+	if rules.MultiMangaID == 0 {
+		_, err = db.Exec(`
+            UPDATE notification_rules
+            SET
+                mute = COALESCE($1, mute),
+                numbered_chapters_only = COALESCE($2, numbered_chapters_only),
+                digest = COALESCE($3, digest),
+                min_gap_minutes = COALESCE($4, min_gap_minutes),
+                quiet_hours_start = CASE WHEN $5::varchar IS NULL THEN quiet_hours_start ELSE NULLIF($5, '') END,
+                quiet_hours_end = CASE WHEN $6::varchar IS NULL THEN quiet_hours_end ELSE NULLIF($6, '') END,
+                digest_time = COALESCE($7, digest_time),
+                digest_frequency = COALESCE($8, digest_frequency),
+                digest_weekday = COALESCE($9, digest_weekday)
+            WHERE
+                multimanga_id IS NULL;
+        `, rules.Mute, rules.NumberedChaptersOnly, rules.Digest, rules.MinGapMinutes, rules.QuietHoursStart, rules.QuietHoursEnd, rules.DigestTime, rules.DigestFrequency, rules.DigestWeekday)
+	} else {
+		_, err = db.Exec(`
+            INSERT INTO notification_rules
+                (multimanga_id, mute, numbered_chapters_only, digest, min_gap_minutes, quiet_hours_start, quiet_hours_end)
+            VALUES
+                ($1, $2, $3, $4, $5, $6, $7)
+            ON CONFLICT (multimanga_id)
+            DO UPDATE
+                SET mute = EXCLUDED.mute, numbered_chapters_only = EXCLUDED.numbered_chapters_only, digest = EXCLUDED.digest,
+                    min_gap_minutes = EXCLUDED.min_gap_minutes, quiet_hours_start = EXCLUDED.quiet_hours_start, quiet_hours_end = EXCLUDED.quiet_hours_end;
+        `, rules.MultiMangaID, rules.Mute, rules.NumberedChaptersOnly, rules.Digest, rules.MinGapMinutes, rules.QuietHoursStart, rules.QuietHoursEnd)
+	}
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf(contextError, rules.MultiMangaID), err)
+	}
+
+	return nil
+}
+
+// DeleteRules deletes the multimanga's rules, so it uses the global rules.
+func DeleteRules(multiMangaID manga.ID) error {
+	contextError := "error deleting notification rules of multimanga '%d' from DB"
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf(contextError, multiMangaID), err)
+	}
+	defer db.Close()
+
+	// Keeps the row if it has the last notified time, but resets the rules
+	_, err = db.Exec(`
+        UPDATE notification_rules
+        SET mute = NULL, numbered_chapters_only = NULL, digest = NULL, min_gap_minutes = NULL, quiet_hours_start = NULL, quiet_hours_end = NULL
+        WHERE multimanga_id = $1;
+    `, multiMangaID)
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf(contextError, multiMangaID), err)
+	}
+
+	return nil
+}
+
+// SetLastNotifiedAt saves when the multimanga was last notified.
+func SetLastNotifiedAt(multiMangaID manga.ID, notifiedAt time.Time) error {
+	contextError := "error setting multimanga '%d' last notified time in DB"
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf(contextError, multiMangaID), err)
+	}
+	defer db.Close()
+
+	_, err = db.Exec(`
+        INSERT INTO notification_rules (multimanga_id, last_notified_at)
+        VALUES ($1, $2)
+        ON CONFLICT (multimanga_id)
+        DO UPDATE
+            SET last_notified_at = EXCLUDED.last_notified_at;
+    `, multiMangaID, notifiedAt)
+	if err != nil {
+		return util.AddErrorContext(fmt.Sprintf(contextError, multiMangaID), err)
+	}
+
+	return nil
+}
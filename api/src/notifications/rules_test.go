@@ -0,0 +1,200 @@
+package notifications
+
+import (
+	"testing"
+	"time"
+
+	"github.com/diogovalentte/mantium/api/src/manga"
+)
+
+func TestEvaluate(t *testing.T) {
+	boolPtr := func(b bool) *bool { return &b }
+	intPtr := func(i int) *int { return &i }
+	strPtr := func(s string) *string { return &s }
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, 5, 10, hour, minute, 0, 0, time.UTC)
+	}
+	chapter := &manga.Chapter{Chapter: "10", Type: 1}
+
+	tests := []struct {
+		name           string
+		rules          Rules
+		chapter        *manga.Chapter
+		lastNotifiedAt time.Time
+		now            time.Time
+		expected       Decision
+	}{
+		{
+			name:     "Should send without rules",
+			chapter:  chapter,
+			now:      at(12, 0),
+			expected: Decision{Action: ActionSend},
+		},
+		{
+			name:     "Should skip muted",
+			rules:    Rules{Mute: boolPtr(true), Digest: boolPtr(true)},
+			chapter:  chapter,
+			now:      at(12, 0),
+			expected: Decision{Action: ActionSkip, Reason: ReasonMuted},
+		},
+		{
+			name:     "Should skip specials if numbered chapters only",
+			rules:    Rules{NumberedChaptersOnly: boolPtr(true)},
+			chapter:  &manga.Chapter{Chapter: "10 Extra", Type: 1},
+			now:      at(12, 0),
+			expected: Decision{Action: ActionSkip, Reason: ReasonNotNumbered},
+		},
+		{
+			name:     "Should skip chapters without number if numbered chapters only",
+			rules:    Rules{NumberedChaptersOnly: boolPtr(true)},
+			chapter:  &manga.Chapter{Chapter: "Oneshot", Type: 1},
+			now:      at(12, 0),
+			expected: Decision{Action: ActionSkip, Reason: ReasonNotNumbered},
+		},
+		{
+			name:     "Should send numbered chapters if numbered chapters only",
+			rules:    Rules{NumberedChaptersOnly: boolPtr(true)},
+			chapter:  &manga.Chapter{Chapter: "10.5", Type: 1},
+			now:      at(12, 0),
+			expected: Decision{Action: ActionSend},
+		},
+		{
+			name:     "Should defer digest to the next digest time",
+			rules:    Rules{Digest: boolPtr(true), DigestTime: strPtr("09:00")},
+			chapter:  chapter,
+			now:      at(12, 0),
+			expected: Decision{Action: ActionDefer, Reason: ReasonDigest, DeliverAfter: at(9, 0).AddDate(0, 0, 1)},
+		},
+		{
+			name:     "Should defer digest to the digest time of the same day",
+			rules:    Rules{Digest: boolPtr(true), DigestTime: strPtr("20:30")},
+			chapter:  chapter,
+			now:      at(12, 0),
+			expected: Decision{Action: ActionDefer, Reason: ReasonDigest, DeliverAfter: at(20, 30)},
+		},
+		{
+			name:           "Should defer inside the min gap",
+			rules:          Rules{MinGapMinutes: intPtr(60)},
+			chapter:        chapter,
+			lastNotifiedAt: at(11, 30),
+			now:            at(12, 0),
+			expected:       Decision{Action: ActionDefer, Reason: ReasonMinGap, DeliverAfter: at(12, 30)},
+		},
+		{
+			name:           "Should send after the min gap",
+			rules:          Rules{MinGapMinutes: intPtr(60)},
+			chapter:        chapter,
+			lastNotifiedAt: at(10, 30),
+			now:            at(12, 0),
+			expected:       Decision{Action: ActionSend},
+		},
+		{
+			name:     "Should defer inside the quiet hours",
+			rules:    Rules{QuietHoursStart: strPtr("11:00"), QuietHoursEnd: strPtr("13:00")},
+			chapter:  chapter,
+			now:      at(12, 0),
+			expected: Decision{Action: ActionDefer, Reason: ReasonQuietHours, DeliverAfter: at(13, 0)},
+		},
+		{
+			name:     "Should defer inside the quiet hours crossing midnight",
+			rules:    Rules{QuietHoursStart: strPtr("22:00"), QuietHoursEnd: strPtr("07:00")},
+			chapter:  chapter,
+			now:      at(23, 0),
+			expected: Decision{Action: ActionDefer, Reason: ReasonQuietHours, DeliverAfter: at(7, 0).AddDate(0, 0, 1)},
+		},
+		{
+			name:     "Should defer after midnight inside the quiet hours crossing midnight",
+			rules:    Rules{QuietHoursStart: strPtr("22:00"), QuietHoursEnd: strPtr("07:00")},
+			chapter:  chapter,
+			now:      at(3, 0),
+			expected: Decision{Action: ActionDefer, Reason: ReasonQuietHours, DeliverAfter: at(7, 0)},
+		},
+		{
+			name:     "Should send outside the quiet hours",
+			rules:    Rules{QuietHoursStart: strPtr("22:00"), QuietHoursEnd: strPtr("07:00")},
+			chapter:  chapter,
+			now:      at(12, 0),
+			expected: Decision{Action: ActionSend},
+		},
+		{
+			name:           "Should defer min gap ending inside the quiet hours to the quiet hours end",
+			rules:          Rules{MinGapMinutes: intPtr(120), QuietHoursStart: strPtr("22:00"), QuietHoursEnd: strPtr("07:00")},
+			chapter:        chapter,
+			lastNotifiedAt: at(21, 0),
+			now:            at(21, 30),
+			expected:       Decision{Action: ActionDefer, Reason: ReasonMinGap, DeliverAfter: at(7, 0).AddDate(0, 0, 1)},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			decision := test.rules.Evaluate(test.chapter, test.lastNotifiedAt, test.now)
+			if decision.Action != test.expected.Action || decision.Reason != test.expected.Reason || !decision.DeliverAfter.Equal(test.expected.DeliverAfter) {
+				t.Fatalf("expected %+v, got %+v", test.expected, decision)
+			}
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	boolPtr := func(b bool) *bool { return &b }
+	strPtr := func(s string) *string { return &s }
+
+	global := &Rules{Mute: boolPtr(false), Digest: boolPtr(true), QuietHoursStart: strPtr("22:00"), QuietHoursEnd: strPtr("07:00"), DigestTime: strPtr("09:00")}
+	rules := &Rules{MultiMangaID: 1, Mute: boolPtr(true)}
+
+	merged := rules.Merge(global)
+	if merged.MultiMangaID != 1 || !*merged.Mute || !*merged.Digest || *merged.QuietHoursStart != "22:00" || *merged.DigestTime != "09:00" {
+		t.Fatalf("unexpected merged rules: %+v", merged)
+	}
+	if *global.Mute {
+		t.Fatal("merge changed the global rules")
+	}
+}
+
+func TestNextDigestTime(t *testing.T) {
+	strPtr := func(s string) *string { return &s }
+	intPtr := func(i int) *int { return &i }
+	// Friday
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		rules    Rules
+		expected time.Time
+	}{
+		{
+			name:     "Should default to daily at 09:00",
+			expected: time.Date(2024, 5, 11, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "Should use the daily digest time of the same day",
+			rules:    Rules{DigestFrequency: strPtr(DigestFrequencyDaily), DigestTime: strPtr("18:00")},
+			expected: time.Date(2024, 5, 10, 18, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "Should use the next weekday",
+			rules:    Rules{DigestFrequency: strPtr(DigestFrequencyWeekly), DigestWeekday: intPtr(int(time.Monday)), DigestTime: strPtr("09:00")},
+			expected: time.Date(2024, 5, 13, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "Should use the same weekday if the time didn't pass",
+			rules:    Rules{DigestFrequency: strPtr(DigestFrequencyWeekly), DigestWeekday: intPtr(int(time.Friday)), DigestTime: strPtr("18:00")},
+			expected: time.Date(2024, 5, 10, 18, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "Should use the next week if the time passed",
+			rules:    Rules{DigestFrequency: strPtr(DigestFrequencyWeekly), DigestWeekday: intPtr(int(time.Friday)), DigestTime: strPtr("09:00")},
+			expected: time.Date(2024, 5, 17, 9, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			next := test.rules.NextDigestTime(now)
+			if !next.Equal(test.expected) {
+				t.Fatalf("expected %s, got %s", test.expected, next)
+			}
+		})
+	}
+}
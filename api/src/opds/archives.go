@@ -0,0 +1,284 @@
+package opds
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diogovalentte/mantium/api/src/config"
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/integrations/suwayomi"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// ArchiveContentTypes are the content types of the supported archive extensions.
+var ArchiveContentTypes = map[string]string{
+	".cbz":  "application/vnd.comicbook+zip",
+	".zip":  "application/zip",
+	".cbr":  "application/vnd.comicbook-rar",
+	".cb7":  "application/x-cb7",
+	".pdf":  "application/pdf",
+	".epub": "application/epub+zip",
+}
+
+// Archive is a downloaded chapter archive of a multimanga.
+type Archive struct {
+	Updated time.Time
+	// ID identifies the archive in its provider, like its file path.
+	ID          string
+	Title       string
+	FileName    string
+	ContentType string
+	// Size is -1 if unknown.
+	Size int64
+	// Chapter is the chapter number, or -1 if unknown.
+	Chapter float64
+}
+
+// ArchiveProvider lists and opens the downloaded chapter archives of the multimangas.
+type ArchiveProvider interface {
+	// Archives returns the multimanga archives sorted by chapter, newest first.
+	Archives(mm *manga.MultiManga) ([]*Archive, error)
+	// Open returns the archive content. The caller must close it.
+	Open(mm *manga.MultiManga, archiveID string) (io.ReadCloser, *Archive, error)
+}
+
+// NewArchiveProvider returns the archive provider set in the configs.
+// The local archives directory is preferred over the Suwayomi integration.
+func NewArchiveProvider() (ArchiveProvider, error) {
+	if config.GlobalConfigs.OPDS.ArchivesDir != "" {
+		return &LocalArchiveProvider{Dir: config.GlobalConfigs.OPDS.ArchivesDir}, nil
+	}
+	if config.GlobalConfigs.Suwayomi.Valid {
+		s := &suwayomi.Suwayomi{}
+		s.Init()
+		return &SuwayomiArchiveProvider{Suwayomi: s}, nil
+	}
+
+	return nil, errordefs.ErrOPDSArchivesDisabled
+}
+
+// LocalArchiveProvider provides the archives from a local directory.
+// The archives of a manga should be in a directory with the manga name,
+// in the root directory or one level below it, like "<Dir>/<Manga Name>/"
+// or "<Dir>/<Source>/<Manga Name>/". The names are compared ignoring
+// case, spaces, and punctuation.
+type LocalArchiveProvider struct {
+	Dir string
+}
+
+// Archives returns the archives in the directories of the multimanga mangas.
+func (p *LocalArchiveProvider) Archives(mm *manga.MultiManga) ([]*Archive, error) {
+	errorContext := "error while getting local archives of multimanga '%d'"
+
+	names := map[string]bool{}
+	for _, m := range mm.Mangas {
+		names[util.NormalizeName(m.Name)] = true
+	}
+	if mm.CurrentManga != nil {
+		names[util.NormalizeName(mm.CurrentManga.Name)] = true
+	}
+
+	mangaDirs, err := p.findMangaDirs(names)
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(errorContext, mm.ID), err)
+	}
+
+	archives := []*Archive{}
+	for _, dir := range mangaDirs {
+		entries, err := os.ReadDir(filepath.Join(p.Dir, filepath.FromSlash(dir)))
+		if err != nil {
+			return nil, util.AddErrorContext(fmt.Sprintf(errorContext, mm.ID), err)
+		}
+		for _, entry := range entries {
+			contentType, ok := ArchiveContentTypes[strings.ToLower(filepath.Ext(entry.Name()))]
+			if entry.IsDir() || !ok {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil {
+				return nil, util.AddErrorContext(fmt.Sprintf(errorContext, mm.ID), err)
+			}
+			archives = append(archives, &Archive{
+				ID:          path.Join(dir, entry.Name()),
+				Title:       strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name())),
+				FileName:    entry.Name(),
+				ContentType: contentType,
+				Size:        info.Size(),
+				Updated:     info.ModTime(),
+				Chapter:     parseArchiveChapter(entry.Name(), path.Base(dir)),
+			})
+		}
+	}
+	SortArchives(archives)
+
+	return archives, nil
+}
+
+// findMangaDirs returns the slash separated paths, relative to the root
+// directory, of the directories with any of the names.
+func (p *LocalArchiveProvider) findMangaDirs(names map[string]bool) ([]string, error) {
+	dirs := []string{}
+	entries, err := os.ReadDir(p.Dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if names[util.NormalizeName(entry.Name())] {
+			dirs = append(dirs, entry.Name())
+			continue
+		}
+		subEntries, err := os.ReadDir(filepath.Join(p.Dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		for _, subEntry := range subEntries {
+			if subEntry.IsDir() && names[util.NormalizeName(subEntry.Name())] {
+				dirs = append(dirs, path.Join(entry.Name(), subEntry.Name()))
+			}
+		}
+	}
+
+	return dirs, nil
+}
+
+// Open opens a multimanga archive. Only archives returned by Archives can be
+// opened, so the archive ID can't be used to read other files.
+func (p *LocalArchiveProvider) Open(mm *manga.MultiManga, archiveID string) (io.ReadCloser, *Archive, error) {
+	archive, err := findArchive(p, mm, archiveID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file, err := os.Open(filepath.Join(p.Dir, filepath.FromSlash(archive.ID)))
+	if err != nil {
+		return nil, nil, util.AddErrorContext(fmt.Sprintf("error while opening archive '%s'", archive.ID), err)
+	}
+
+	return file, archive, nil
+}
+
+// SuwayomiArchiveProvider proxies the chapters downloaded by Suwayomi.
+type SuwayomiArchiveProvider struct {
+	Suwayomi *suwayomi.Suwayomi
+}
+
+// Archives returns the downloaded chapters of the multimanga mangas that are in the Suwayomi library.
+// Mangas not in the library or from sources not supported by Suwayomi are ignored.
+func (p *SuwayomiArchiveProvider) Archives(mm *manga.MultiManga) ([]*Archive, error) {
+	errorContext := "error while getting Suwayomi archives of multimanga '%d'"
+
+	supportedSources := suwayomi.SupportedSources()
+	archives := []*Archive{}
+	for _, m := range mm.Mangas {
+		if !slices.Contains(supportedSources, m.Source) {
+			continue
+		}
+		mangaID, err := p.Suwayomi.GetLibraryMangaID(m)
+		if err != nil {
+			if util.ErrorContains(err, errordefs.ErrMangaNotInLibrary.Error()) {
+				continue
+			}
+			return nil, util.AddErrorContext(fmt.Sprintf(errorContext, mm.ID), err)
+		}
+		chapters, err := p.Suwayomi.GetChapters(mangaID)
+		if err != nil {
+			return nil, util.AddErrorContext(fmt.Sprintf(errorContext, mm.ID), err)
+		}
+		for _, chapter := range chapters {
+			if !chapter.IsDownloaded {
+				continue
+			}
+			title := chapter.Name
+			if title == "" {
+				title = fmt.Sprintf("Chapter %s", strconv.FormatFloat(chapter.ChapterNumber, 'f', -1, 64))
+			}
+			archives = append(archives, &Archive{
+				ID:          fmt.Sprintf("%d:%d", mangaID, chapter.SourceOrder),
+				Title:       title,
+				FileName:    fmt.Sprintf("%s - %s.cbz", m.Name, title),
+				ContentType: ArchiveContentTypes[".cbz"],
+				Size:        -1,
+				Chapter:     chapter.ChapterNumber,
+			})
+		}
+	}
+	SortArchives(archives)
+
+	return archives, nil
+}
+
+// Open returns the archive downloaded from Suwayomi.
+func (p *SuwayomiArchiveProvider) Open(mm *manga.MultiManga, archiveID string) (io.ReadCloser, *Archive, error) {
+	archive, err := findArchive(p, mm, archiveID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var mangaID, chapterIndex int
+	_, err = fmt.Sscanf(archive.ID, "%d:%d", &mangaID, &chapterIndex)
+	if err != nil {
+		return nil, nil, util.AddErrorContext(fmt.Sprintf("error while parsing archive ID '%s'", archive.ID), err)
+	}
+	body, size, err := p.Suwayomi.GetChapterArchive(mangaID, chapterIndex)
+	if err != nil {
+		return nil, nil, err
+	}
+	archive.Size = size
+
+	return body, archive, nil
+}
+
+func findArchive(p ArchiveProvider, mm *manga.MultiManga, archiveID string) (*Archive, error) {
+	archives, err := p.Archives(mm)
+	if err != nil {
+		return nil, err
+	}
+	for _, archive := range archives {
+		if archive.ID == archiveID {
+			return archive, nil
+		}
+	}
+
+	return nil, util.AddErrorContext(fmt.Sprintf("error while getting archive '%s' of multimanga '%d'", archiveID, mm.ID), errordefs.ErrOPDSArchiveNotFound)
+}
+
+// SortArchives sorts the archives by chapter number, newest first.
+// Archives without a chapter number are sorted by title after them.
+func SortArchives(archives []*Archive) {
+	sort.SliceStable(archives, func(i, j int) bool {
+		if archives[i].Chapter != archives[j].Chapter {
+			return archives[i].Chapter > archives[j].Chapter
+		}
+		return archives[i].Title < archives[j].Title
+	})
+}
+
+// parseArchiveChapter returns the chapter number in an archive file name, like
+// "Berserk - Vol. 1 Ch. 10.5.cbz" or "Berserk 010.cbz", or -1 if not found. The manga
+// name is removed from the start of the file name, as it can have numbers too.
+func parseArchiveChapter(fileName, mangaName string) float64 {
+	name := strings.TrimSuffix(fileName, filepath.Ext(fileName))
+	if mangaName != "" && len(name) > len(mangaName) && strings.EqualFold(name[:len(mangaName)], mangaName) {
+		name = name[len(mangaName):]
+	}
+	name = strings.ReplaceAll(name, "_", " ")
+
+	chapter := manga.ParseChapterNumber(name)
+	if !chapter.HasNumber {
+		return -1
+	}
+
+	return chapter.Number
+}
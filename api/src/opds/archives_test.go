@@ -0,0 +1,101 @@
+package opds
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/manga"
+)
+
+func TestLocalArchiveProvider(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"Berserk/Berserk - Ch. 1.cbz":                   "chapter 1",
+		"Berserk/Berserk - Ch. 10.5.cbz":                "chapter 10.5",
+		"Berserk/cover.jpg":                             "cover",
+		"MangaDex (EN)/berserk!/Berserk 002.zip":        "chapter 2",
+		"MangaDex (EN)/Chainsaw Man/Chainsaw Man 1.cbz": "other manga",
+		"secret.txt": "secret",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	provider := &LocalArchiveProvider{Dir: dir}
+	mm := &manga.MultiManga{ID: 1, Mangas: []*manga.Manga{{Name: "Berserk"}}}
+
+	t.Run("Should list the archives of the multimanga mangas", func(t *testing.T) {
+		archives, err := provider.Archives(mm)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := []string{"Berserk/Berserk - Ch. 10.5.cbz", "MangaDex (EN)/berserk!/Berserk 002.zip", "Berserk/Berserk - Ch. 1.cbz"}
+		if len(archives) != len(expected) {
+			t.Fatalf("expected %d archives, got %d", len(expected), len(archives))
+		}
+		for i, archive := range archives {
+			if archive.ID != expected[i] {
+				t.Fatalf("expected archive %d to be '%s', got '%s'", i, expected[i], archive.ID)
+			}
+		}
+		if archives[0].ContentType != ArchiveContentTypes[".cbz"] || archives[0].Chapter != 10.5 || archives[0].Size != int64(len("chapter 10.5")) {
+			t.Fatalf("unexpected archive: %+v", archives[0])
+		}
+	})
+	t.Run("Should open an archive", func(t *testing.T) {
+		reader, archive, err := provider.Open(mm, "Berserk/Berserk - Ch. 1.cbz")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer reader.Close()
+		content, err := io.ReadAll(reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(content) != "chapter 1" || archive.FileName != "Berserk - Ch. 1.cbz" {
+			t.Fatalf("unexpected archive '%s': %+v", content, archive)
+		}
+	})
+	for _, archiveID := range []string{"secret.txt", "../secret.txt", "Berserk/../secret.txt", "Berserk/cover.jpg", "MangaDex (EN)/Chainsaw Man/Chainsaw Man 1.cbz"} {
+		t.Run("Should not open a file that is not a multimanga archive", func(t *testing.T) {
+			_, _, err := provider.Open(mm, archiveID)
+			if err == nil || !strings.Contains(err.Error(), errordefs.ErrOPDSArchiveNotFound.Error()) {
+				t.Fatalf("expected archive not found error for '%s', got %v", archiveID, err)
+			}
+		})
+	}
+}
+
+func TestParseArchiveChapter(t *testing.T) {
+	tests := map[string]float64{
+		"Berserk - Vol. 1 Ch. 10.5.cbz":  10.5,
+		"Berserk - Chapter 3.cbz":        3,
+		"Kaiju No. 8 - 010.cbz":          10,
+		"Kaiju No. 8 - Chapter 100.cbz":  100,
+		"Oneshot.cbz":                    -1,
+		"Berserk #7.cbz":                 7,
+		"Berserk_Ch.0012_The Guts.cbz":   12,
+		"[Group] Berserk v02 c015.5.cbz": 15.5,
+	}
+	for name, expected := range tests {
+		t.Run("Should parse "+name, func(t *testing.T) {
+			mangaName := "Berserk"
+			if strings.HasPrefix(name, "Kaiju") {
+				mangaName = "Kaiju No. 8"
+			}
+			if chapter := parseArchiveChapter(name, mangaName); chapter != expected {
+				t.Fatalf("expected %v, got %v", expected, chapter)
+			}
+		})
+	}
+}
@@ -0,0 +1,340 @@
+// Package opds implements the OPDS 1.2 and 2.0 catalogs of the multimangas,
+// used by e-readers like KOReader, Panels, and Chunky to browse the library
+// and download the chapter archives.
+package opds
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+const (
+	// NavigationContentType is the content type of the OPDS 1.2 navigation feeds.
+	NavigationContentType = "application/atom+xml;profile=opds-catalog;kind=navigation"
+	// AcquisitionContentType is the content type of the OPDS 1.2 acquisition feeds.
+	AcquisitionContentType = "application/atom+xml;profile=opds-catalog;kind=acquisition"
+	// JSONContentType is the content type of the OPDS 2.0 feeds.
+	JSONContentType = "application/opds+json"
+
+	// BasePath is the API path of the OPDS routes.
+	BasePath = "/v1/opds"
+
+	relAcquisition = "http://opds-spec.org/acquisition"
+	relImage       = "http://opds-spec.org/image"
+	relThumbnail   = "http://opds-spec.org/image/thumbnail"
+)
+
+var statusNames = map[manga.Status]string{
+	1: "Reading",
+	2: "Completed",
+	3: "On hold",
+	4: "Dropped",
+	5: "Plan to read",
+}
+
+// Catalog builds the OPDS feeds. The links are absolute URLs using the BaseURL.
+type Catalog struct {
+	Updated time.Time
+	// BaseURL is the API URL, like https://mantium.domain.com.
+	BaseURL string
+}
+
+// RootURL returns the OPDS 1.2 root feed URL.
+func (c *Catalog) RootURL() string {
+	return c.BaseURL + BasePath + "/v1.2/catalog"
+}
+
+// RootURLV2 returns the OPDS 2.0 root feed URL.
+func (c *Catalog) RootURLV2() string {
+	return c.BaseURL + BasePath + "/v2.0/catalog"
+}
+
+// MultiMangaURL returns the OPDS 1.2 feed URL of the multimanga archives.
+func (c *Catalog) MultiMangaURL(mm *manga.MultiManga) string {
+	return fmt.Sprintf("%s%s/v1.2/multimanga?id=%d", c.BaseURL, BasePath, mm.ID)
+}
+
+// MultiMangaURLV2 returns the OPDS 2.0 feed URL of the multimanga archives.
+func (c *Catalog) MultiMangaURLV2(mm *manga.MultiManga) string {
+	return fmt.Sprintf("%s%s/v2.0/multimanga?id=%d", c.BaseURL, BasePath, mm.ID)
+}
+
+// CoverURL returns the multimanga cover image URL.
+func (c *Catalog) CoverURL(mm *manga.MultiManga) string {
+	return fmt.Sprintf("%s%s/cover?id=%d", c.BaseURL, BasePath, mm.ID)
+}
+
+// ArchiveURL returns the URL to download the archive.
+func (c *Catalog) ArchiveURL(mm *manga.MultiManga, archive *Archive) string {
+	return fmt.Sprintf("%s%s/archive?id=%d&archive=%s", c.BaseURL, BasePath, mm.ID, url.QueryEscape(archive.ID))
+}
+
+// multiMangaTitle returns the name of the multimanga current manga.
+func multiMangaTitle(mm *manga.MultiManga) string {
+	if mm.CurrentManga != nil {
+		return mm.CurrentManga.Name
+	}
+	return fmt.Sprintf("Multimanga %d", mm.ID)
+}
+
+// multiMangaSummary returns the status and the last read and released chapters of the multimanga.
+func multiMangaSummary(mm *manga.MultiManga) string {
+	parts := []string{}
+	if name, ok := statusNames[mm.Status]; ok {
+		parts = append(parts, name)
+	}
+	if mm.LastReadChapter != nil {
+		parts = append(parts, fmt.Sprintf("Last read chapter: %s", mm.LastReadChapter.Chapter))
+	}
+	if mm.CurrentManga != nil && mm.CurrentManga.LastReleasedChapter != nil {
+		parts = append(parts, fmt.Sprintf("Last released chapter: %s", mm.CurrentManga.LastReleasedChapter.Chapter))
+	}
+	if mm.UnreadChapters > 0 {
+		parts = append(parts, fmt.Sprintf("%d unread chapter(s)", mm.UnreadChapters))
+	}
+
+	return strings.Join(parts, " | ")
+}
+
+// multiMangaUpdated returns when the multimanga current manga last released a chapter.
+func (c *Catalog) multiMangaUpdated(mm *manga.MultiManga) time.Time {
+	if mm.CurrentManga != nil && mm.CurrentManga.LastReleasedChapter != nil && !mm.CurrentManga.LastReleasedChapter.UpdatedAt.IsZero() {
+		return mm.CurrentManga.LastReleasedChapter.UpdatedAt
+	}
+	return c.Updated
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	DC      string      `xml:"xmlns:dc,attr"`
+	OPDS    string      `xml:"xmlns:opds,attr"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  atomAuthor  `xml:"author"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Href   string `xml:"href,attr"`
+	Rel    string `xml:"rel,attr,omitempty"`
+	Type   string `xml:"type,attr,omitempty"`
+	Title  string `xml:"title,attr,omitempty"`
+	Length int64  `xml:"length,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Content    *atomContent   `xml:"content,omitempty"`
+	Categories []atomCategory `xml:"category"`
+	Links      []atomLink     `xml:"link"`
+}
+
+type atomContent struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+func (c *Catalog) newAtomFeed(id, title, selfURL, selfType string) atomFeed {
+	return atomFeed{
+		DC:      "http://purl.org/dc/terms/",
+		OPDS:    "http://opds-spec.org/2010/catalog",
+		ID:      id,
+		Title:   title,
+		Updated: c.Updated.Format(time.RFC3339),
+		Author:  atomAuthor{Name: "Mantium"},
+		Links: []atomLink{
+			{Href: selfURL, Rel: "self", Type: selfType},
+			{Href: c.RootURL(), Rel: "start", Type: NavigationContentType},
+		},
+		Entries: []atomEntry{},
+	}
+}
+
+func (c *Catalog) coverLinks(mm *manga.MultiManga) []atomLink {
+	return []atomLink{
+		{Href: c.CoverURL(mm), Rel: relImage, Type: "image/jpeg"},
+		{Href: c.CoverURL(mm), Rel: relThumbnail, Type: "image/jpeg"},
+	}
+}
+
+// RootV1 returns the OPDS 1.2 navigation feed with an entry for each multimanga.
+func (c *Catalog) RootV1(multimangas []*manga.MultiManga) ([]byte, error) {
+	doc := c.newAtomFeed("urn:mantium:opds:catalog", "Mantium", c.RootURL(), NavigationContentType)
+	for _, mm := range multimangas {
+		entry := atomEntry{
+			ID:      fmt.Sprintf("urn:mantium:multimanga:%d", mm.ID),
+			Title:   multiMangaTitle(mm),
+			Updated: c.multiMangaUpdated(mm).Format(time.RFC3339),
+			Content: &atomContent{Type: "text", Value: multiMangaSummary(mm)},
+			Links: append([]atomLink{
+				{Href: c.MultiMangaURL(mm), Rel: "subsection", Type: AcquisitionContentType},
+			}, c.coverLinks(mm)...),
+		}
+		for _, tag := range mm.Tags {
+			entry.Categories = append(entry.Categories, atomCategory{Term: tag})
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+
+	return marshalXML(doc)
+}
+
+// MultiMangaV1 returns the OPDS 1.2 acquisition feed with the multimanga archives.
+func (c *Catalog) MultiMangaV1(mm *manga.MultiManga, archives []*Archive) ([]byte, error) {
+	doc := c.newAtomFeed(fmt.Sprintf("urn:mantium:opds:multimanga:%d", mm.ID), multiMangaTitle(mm), c.MultiMangaURL(mm), AcquisitionContentType)
+	doc.Links = append(doc.Links, atomLink{Href: c.RootURL(), Rel: "up", Type: NavigationContentType})
+	for _, archive := range archives {
+		updated := archive.Updated
+		if updated.IsZero() {
+			updated = c.Updated
+		}
+		entry := atomEntry{
+			ID:      fmt.Sprintf("urn:mantium:multimanga:%d:archive:%s", mm.ID, url.PathEscape(archive.ID)),
+			Title:   archive.Title,
+			Updated: updated.Format(time.RFC3339),
+			Links: append([]atomLink{
+				{Href: c.ArchiveURL(mm, archive), Rel: relAcquisition, Type: archive.ContentType, Title: archive.FileName, Length: max(archive.Size, 0)},
+			}, c.coverLinks(mm)...),
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+
+	return marshalXML(doc)
+}
+
+func marshalXML(doc any) ([]byte, error) {
+	content, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, util.AddErrorContext("error while creating OPDS feed", err)
+	}
+
+	return append([]byte(xml.Header), content...), nil
+}
+
+type jsonFeed struct {
+	Metadata     jsonMetadata       `json:"metadata"`
+	Links        []jsonLink         `json:"links"`
+	Navigation   []jsonLink         `json:"navigation,omitempty"`
+	Publications []*jsonPublication `json:"publications,omitempty"`
+}
+
+type jsonMetadata struct {
+	Title         string   `json:"title"`
+	Type          string   `json:"@type,omitempty"`
+	Identifier    string   `json:"identifier,omitempty"`
+	Modified      string   `json:"modified,omitempty"`
+	Description   string   `json:"description,omitempty"`
+	Subject       []string `json:"subject,omitempty"`
+	NumberOfItems *int     `json:"numberOfItems,omitempty"`
+}
+
+type jsonLink struct {
+	Href   string `json:"href"`
+	Rel    string `json:"rel,omitempty"`
+	Type   string `json:"type,omitempty"`
+	Title  string `json:"title,omitempty"`
+	Length int64  `json:"length,omitempty"`
+}
+
+type jsonPublication struct {
+	Metadata jsonMetadata `json:"metadata"`
+	Links    []jsonLink   `json:"links"`
+	Images   []jsonLink   `json:"images"`
+}
+
+// RootV2 returns the OPDS 2.0 navigation feed with a link for each multimanga.
+func (c *Catalog) RootV2(multimangas []*manga.MultiManga) ([]byte, error) {
+	doc := jsonFeed{
+		Metadata: jsonMetadata{Title: "Mantium", Modified: c.Updated.Format(time.RFC3339)},
+		Links: []jsonLink{
+			{Href: c.RootURLV2(), Rel: "self", Type: JSONContentType},
+			{Href: c.RootURLV2(), Rel: "start", Type: JSONContentType},
+		},
+		Navigation: []jsonLink{},
+	}
+	for _, mm := range multimangas {
+		doc.Navigation = append(doc.Navigation, jsonLink{
+			Href:  c.MultiMangaURLV2(mm),
+			Rel:   "subsection",
+			Type:  JSONContentType,
+			Title: multiMangaTitle(mm),
+		})
+	}
+
+	return marshalJSON(doc)
+}
+
+// MultiMangaV2 returns the OPDS 2.0 feed with a publication for each multimanga archive.
+func (c *Catalog) MultiMangaV2(mm *manga.MultiManga, archives []*Archive) ([]byte, error) {
+	numberOfItems := len(archives)
+	doc := jsonFeed{
+		Metadata: jsonMetadata{
+			Title:         multiMangaTitle(mm),
+			Identifier:    fmt.Sprintf("urn:mantium:multimanga:%d", mm.ID),
+			Modified:      c.multiMangaUpdated(mm).Format(time.RFC3339),
+			Description:   multiMangaSummary(mm),
+			Subject:       mm.Tags,
+			NumberOfItems: &numberOfItems,
+		},
+		Links: []jsonLink{
+			{Href: c.MultiMangaURLV2(mm), Rel: "self", Type: JSONContentType},
+			{Href: c.RootURLV2(), Rel: "start", Type: JSONContentType},
+			{Href: c.RootURLV2(), Rel: "up", Type: JSONContentType},
+		},
+		Publications: []*jsonPublication{},
+	}
+	for _, archive := range archives {
+		publication := &jsonPublication{
+			Metadata: jsonMetadata{
+				Title:      archive.Title,
+				Type:       "http://schema.org/ComicIssue",
+				Identifier: fmt.Sprintf("urn:mantium:multimanga:%d:archive:%s", mm.ID, url.PathEscape(archive.ID)),
+			},
+			Links: []jsonLink{
+				{Href: c.ArchiveURL(mm, archive), Rel: relAcquisition, Type: archive.ContentType, Title: archive.FileName, Length: max(archive.Size, 0)},
+			},
+			Images: []jsonLink{
+				{Href: c.CoverURL(mm), Type: "image/jpeg"},
+			},
+		}
+		if !archive.Updated.IsZero() {
+			publication.Metadata.Modified = archive.Updated.Format(time.RFC3339)
+		}
+		doc.Publications = append(doc.Publications, publication)
+	}
+
+	return marshalJSON(doc)
+}
+
+func marshalJSON(doc any) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(doc)
+	if err != nil {
+		return nil, util.AddErrorContext("error while creating OPDS feed", err)
+	}
+
+	return buf.Bytes(), nil
+}
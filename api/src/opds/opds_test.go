@@ -0,0 +1,114 @@
+package opds
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diogovalentte/mantium/api/src/manga"
+)
+
+var (
+	catalog = &Catalog{BaseURL: "https://mantium.domain.com", Updated: time.Date(2024, 5, 10, 9, 0, 0, 0, time.UTC)}
+	mm      = &manga.MultiManga{
+		ID:              1,
+		Status:          1,
+		Tags:            []string{"weekly"},
+		LastReadChapter: &manga.Chapter{Chapter: "9"},
+		CurrentManga: &manga.Manga{
+			Name:                "Berserk & Co",
+			LastReleasedChapter: &manga.Chapter{Chapter: "10", UpdatedAt: time.Date(2024, 5, 9, 9, 0, 0, 0, time.UTC)},
+		},
+	}
+	archives = []*Archive{
+		{ID: "Berserk/Berserk - Ch. 10.cbz", Title: "Berserk - Ch. 10", FileName: "Berserk - Ch. 10.cbz", ContentType: ArchiveContentTypes[".cbz"], Size: 100, Chapter: 10},
+		{ID: "3:9", Title: "Chapter 9", FileName: "Berserk - Chapter 9.cbz", ContentType: ArchiveContentTypes[".cbz"], Size: -1, Chapter: 9},
+	}
+)
+
+func TestCatalogV1(t *testing.T) {
+	t.Run("Should create the root navigation feed", func(t *testing.T) {
+		content, err := catalog.RootV1([]*manga.MultiManga{mm})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var doc atomFeed
+		if err := xml.Unmarshal(content, &doc); err != nil {
+			t.Fatalf("invalid XML: %s\n%s", err, content)
+		}
+		if len(doc.Entries) != 1 || doc.Entries[0].Title != "Berserk & Co" {
+			t.Fatalf("unexpected entries: %+v", doc.Entries)
+		}
+		entry := doc.Entries[0]
+		if entry.Links[0].Href != "https://mantium.domain.com/v1/opds/v1.2/multimanga?id=1" || entry.Links[0].Type != AcquisitionContentType {
+			t.Fatalf("unexpected subsection link: %+v", entry.Links[0])
+		}
+		if entry.Links[1].Rel != relImage || entry.Links[1].Href != "https://mantium.domain.com/v1/opds/cover?id=1" {
+			t.Fatalf("unexpected image link: %+v", entry.Links[1])
+		}
+		if !strings.Contains(entry.Content.Value, "Last read chapter: 9") || entry.Updated != "2024-05-09T09:00:00Z" {
+			t.Fatalf("unexpected entry: %+v", entry)
+		}
+	})
+	t.Run("Should create the multimanga acquisition feed", func(t *testing.T) {
+		content, err := catalog.MultiMangaV1(mm, archives)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var doc atomFeed
+		if err := xml.Unmarshal(content, &doc); err != nil {
+			t.Fatalf("invalid XML: %s\n%s", err, content)
+		}
+		if len(doc.Entries) != 2 {
+			t.Fatalf("expected 2 entries, got %d", len(doc.Entries))
+		}
+		acquisition := doc.Entries[0].Links[0]
+		if acquisition.Rel != relAcquisition || acquisition.Type != "application/vnd.comicbook+zip" || acquisition.Length != 100 {
+			t.Fatalf("unexpected acquisition link: %+v", acquisition)
+		}
+		if acquisition.Href != "https://mantium.domain.com/v1/opds/archive?id=1&archive=Berserk%2FBerserk+-+Ch.+10.cbz" {
+			t.Fatalf("unexpected acquisition link href: %s", acquisition.Href)
+		}
+		if doc.Entries[1].Links[0].Length != 0 {
+			t.Fatal("expected no length for archives with unknown size")
+		}
+	})
+}
+
+func TestCatalogV2(t *testing.T) {
+	t.Run("Should create the root navigation feed", func(t *testing.T) {
+		content, err := catalog.RootV2([]*manga.MultiManga{mm})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var doc jsonFeed
+		if err := json.Unmarshal(content, &doc); err != nil {
+			t.Fatalf("invalid JSON: %s\n%s", err, content)
+		}
+		if len(doc.Navigation) != 1 || doc.Navigation[0].Href != "https://mantium.domain.com/v1/opds/v2.0/multimanga?id=1" || doc.Navigation[0].Title != "Berserk & Co" {
+			t.Fatalf("unexpected navigation: %+v", doc.Navigation)
+		}
+	})
+	t.Run("Should create the multimanga feed", func(t *testing.T) {
+		content, err := catalog.MultiMangaV2(mm, archives)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var doc jsonFeed
+		if err := json.Unmarshal(content, &doc); err != nil {
+			t.Fatalf("invalid JSON: %s\n%s", err, content)
+		}
+		if len(doc.Publications) != 2 || *doc.Metadata.NumberOfItems != 2 {
+			t.Fatalf("expected 2 publications, got %d", len(doc.Publications))
+		}
+		publication := doc.Publications[1]
+		if publication.Metadata.Title != "Chapter 9" || publication.Links[0].Href != "https://mantium.domain.com/v1/opds/archive?id=1&archive=3%3A9" {
+			t.Fatalf("unexpected publication: %+v", publication)
+		}
+		if publication.Images[0].Href != "https://mantium.domain.com/v1/opds/cover?id=1" {
+			t.Fatalf("unexpected publication images: %+v", publication.Images)
+		}
+	})
+}
@@ -0,0 +1,264 @@
+// Package recommendations ranks the titles similar to the library mangas to recommend them.
+package recommendations
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/sources/models"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// StatusWeights are the weights of the library mangas by status. A title similar to a
+// manga adds the manga's weight to its score. The dropped mangas aren't used.
+var StatusWeights = map[manga.Status]float64{
+	1: 3, // reading
+	2: 3, // completed
+	3: 1, // on hold
+	5: 1, // plan to read
+}
+
+var statusNames = map[manga.Status]string{
+	1: "reading",
+	2: "completed",
+	3: "on hold",
+	5: "plan to read",
+}
+
+const (
+	// topGenres is the number of the profile's genres a title's genres are matched with.
+	topGenres = 5
+	// genreMatchWeight is added to a title's score for each genre it matches.
+	genreMatchWeight = 1
+)
+
+// Seed is a library manga and the titles similar to it.
+type Seed struct {
+	SimilarTitles *models.SimilarTitles
+	Name          string
+	Status        manga.Status
+	// MultiMangaID identifies the seed, as different multimangas can have the same name.
+	MultiMangaID manga.ID
+}
+
+// Profile is built from the library mangas used to find the recommendations.
+type Profile struct {
+	// Genres are the genres of the seeds weighted by the seeds' status, from the highest weight.
+	Genres []*GenreWeight `json:"genres"`
+	// Seeds are the names of the library mangas used to find the recommendations.
+	Seeds []string `json:"seeds"`
+}
+
+// GenreWeight is a genre and its weight in the profile.
+type GenreWeight struct {
+	Genre  string  `json:"genre"`
+	Weight float64 `json:"weight"`
+}
+
+// Recommendation is a title recommended based on the library mangas.
+type Recommendation struct {
+	Name       string `json:"name"`
+	URL        string `json:"url"`
+	InternalID string `json:"internal_id"`
+	Source     string `json:"source"`
+	CoverURL   string `json:"cover_url"`
+	// Reason explains the score, like "Similar to Berserk (reading)".
+	Reason string `json:"reason"`
+	// SimilarTo are the names of the library mangas the title is similar to.
+	SimilarTo []string `json:"similar_to"`
+	// MatchedGenres are the title's genres in the profile's top genres.
+	MatchedGenres []string `json:"matched_genres"`
+	// Score is the sum of the weights of the library mangas the title is similar
+	// to, plus 1 for each of its genres in the profile's top genres.
+	Score float64 `json:"score"`
+	// genres are the title's genres listed by the sources, in lower case.
+	genres []string
+	// similarToIDs are the multimanga IDs of the seeds in SimilarTo, in the same order.
+	similarToIDs []manga.ID
+}
+
+// Library has the mangas already in the library, which aren't recommended.
+// The mangas are matched by URL, normalized name, and MangaUpdates series ID.
+type Library struct {
+	urls            map[string]bool
+	names           map[string]bool
+	mangaUpdatesIDs map[string]bool
+}
+
+// NewLibrary returns the library of the mangas.
+func NewLibrary(mangas []*manga.Manga) *Library {
+	library := &Library{urls: map[string]bool{}, names: map[string]bool{}, mangaUpdatesIDs: map[string]bool{}}
+	for _, m := range mangas {
+		library.urls[m.URL] = true
+		if name := util.NormalizeName(m.Name); name != "" {
+			library.names[name] = true
+		}
+		if m.Source == "mangaupdates" && m.InternalID != "" {
+			library.mangaUpdatesIDs[m.InternalID] = true
+		}
+	}
+
+	return library
+}
+
+// AddExternalIDs adds the multimangas external IDs to the library, so the titles
+// with the same MangaUpdates series ID as a library manga from any source aren't recommended.
+func (l *Library) AddExternalIDs(externalIDs map[manga.ID]*manga.ExternalIDs) {
+	for _, ids := range externalIDs {
+		if ids.MangaUpdatesID != "" {
+			l.mangaUpdatesIDs[ids.MangaUpdatesID] = true
+		}
+	}
+}
+
+// Contains returns true if the title is in the library.
+func (l *Library) Contains(title *models.SimilarTitle) bool {
+	if l.urls[title.URL] {
+		return true
+	}
+	if title.Source == "mangaupdates" && title.InternalID != "" && l.mangaUpdatesIDs[title.InternalID] {
+		return true
+	}
+	normalizedName := util.NormalizeName(title.Name)
+
+	return normalizedName != "" && l.names[normalizedName]
+}
+
+// BuildProfile returns the profile built from the seeds.
+func BuildProfile(seeds []*Seed) *Profile {
+	profile := &Profile{Genres: []*GenreWeight{}, Seeds: make([]string, 0, len(seeds))}
+	genreWeights := map[string]*GenreWeight{}
+	for _, seed := range seeds {
+		profile.Seeds = append(profile.Seeds, seed.Name)
+		if seed.SimilarTitles == nil {
+			continue
+		}
+		for _, genre := range normalizeGenres(seed.SimilarTitles.Genres) {
+			if _, ok := genreWeights[genre]; !ok {
+				genreWeights[genre] = &GenreWeight{Genre: genre}
+				profile.Genres = append(profile.Genres, genreWeights[genre])
+			}
+			genreWeights[genre].Weight += StatusWeights[seed.Status]
+		}
+	}
+	sort.SliceStable(profile.Genres, func(i, j int) bool {
+		if profile.Genres[i].Weight != profile.Genres[j].Weight {
+			return profile.Genres[i].Weight > profile.Genres[j].Weight
+		}
+		return profile.Genres[i].Genre < profile.Genres[j].Genre
+	})
+
+	return profile
+}
+
+// Rank returns the profile built from the seeds and the titles similar to the seeds
+// that aren't in the library, from the highest to the lowest score, up to the limit.
+// The same title listed by multiple seeds or sources is matched by its normalized name.
+func Rank(seeds []*Seed, library *Library, limit int) (*Profile, []*Recommendation) {
+	profile := BuildProfile(seeds)
+	top := map[string]bool{}
+	for i := 0; i < len(profile.Genres) && i < topGenres; i++ {
+		top[profile.Genres[i].Genre] = true
+	}
+
+	recommendations := []*Recommendation{}
+	byKey := map[string]*Recommendation{}
+	seedsStatus := map[manga.ID]manga.Status{}
+	for _, seed := range seeds {
+		seedsStatus[seed.MultiMangaID] = seed.Status
+		if seed.SimilarTitles == nil {
+			continue
+		}
+		for _, title := range seed.SimilarTitles.Titles {
+			if library.Contains(title) {
+				continue
+			}
+
+			key := util.NormalizeName(title.Name)
+			if key == "" {
+				key = title.URL
+			}
+			recommendation, ok := byKey[key]
+			if !ok {
+				recommendation = &Recommendation{
+					Name:          title.Name,
+					URL:           title.URL,
+					InternalID:    title.InternalID,
+					Source:        title.Source,
+					CoverURL:      title.CoverURL,
+					SimilarTo:     []string{},
+					MatchedGenres: []string{},
+					genres:        []string{},
+				}
+				byKey[key] = recommendation
+				recommendations = append(recommendations, recommendation)
+			}
+			for _, genre := range normalizeGenres(title.Genres) {
+				if !slices.Contains(recommendation.genres, genre) {
+					recommendation.genres = append(recommendation.genres, genre)
+				}
+			}
+			if !slices.Contains(recommendation.similarToIDs, seed.MultiMangaID) {
+				recommendation.similarToIDs = append(recommendation.similarToIDs, seed.MultiMangaID)
+				recommendation.SimilarTo = append(recommendation.SimilarTo, seed.Name)
+				recommendation.Score += StatusWeights[seed.Status]
+			}
+		}
+	}
+
+	for _, recommendation := range recommendations {
+		for _, genre := range recommendation.genres {
+			if top[genre] {
+				recommendation.MatchedGenres = append(recommendation.MatchedGenres, genre)
+			}
+		}
+		recommendation.Score += float64(len(recommendation.MatchedGenres)) * genreMatchWeight
+		recommendation.Score = math.Round(recommendation.Score*10) / 10
+		recommendation.Reason = getReason(recommendation, seedsStatus)
+	}
+
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		if recommendations[i].Score != recommendations[j].Score {
+			return recommendations[i].Score > recommendations[j].Score
+		}
+		if len(recommendations[i].SimilarTo) != len(recommendations[j].SimilarTo) {
+			return len(recommendations[i].SimilarTo) > len(recommendations[j].SimilarTo)
+		}
+		return recommendations[i].Name < recommendations[j].Name
+	})
+	if limit >= 0 && len(recommendations) > limit {
+		recommendations = recommendations[:limit]
+	}
+
+	return profile, recommendations
+}
+
+func getReason(recommendation *Recommendation, seedsStatus map[manga.ID]manga.Status) string {
+	similarTo := make([]string, 0, len(recommendation.SimilarTo))
+	for i, name := range recommendation.SimilarTo {
+		similarTo = append(similarTo, fmt.Sprintf("%s (%s)", name, statusNames[seedsStatus[recommendation.similarToIDs[i]]]))
+	}
+	reason := fmt.Sprintf("Similar to %s", strings.Join(similarTo, ", "))
+	if len(recommendation.MatchedGenres) > 0 {
+		reason += fmt.Sprintf("; matches your top genres: %s", strings.Join(recommendation.MatchedGenres, ", "))
+	}
+
+	return reason
+}
+
+// normalizeGenres returns the genres trimmed, in lower case, and without duplicates or empty genres.
+func normalizeGenres(genres []string) []string {
+	normalized := []string{}
+	for _, genre := range genres {
+		genre = strings.ToLower(strings.TrimSpace(genre))
+		if genre != "" && !slices.Contains(normalized, genre) {
+			normalized = append(normalized, genre)
+		}
+	}
+
+	return normalized
+}
@@ -0,0 +1,148 @@
+package recommendations
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/sources/models"
+)
+
+func getTestSeeds() []*Seed {
+	return []*Seed{
+		{
+			MultiMangaID: 1,
+			Name:         "Berserk",
+			Status:       1,
+			SimilarTitles: &models.SimilarTitles{
+				Genres: []string{"Action", "Drama", "Fantasy"},
+				Titles: []*models.SimilarTitle{
+					{Name: "Claymore", URL: "https://www.mangaupdates.com/series/1", InternalID: "1", Source: "mangaupdates"},
+					{Name: "Vagabond", URL: "https://www.mangaupdates.com/series/2", InternalID: "2", Source: "mangaupdates"},
+					{Name: "One Punch-Man", URL: "https://www.mangaupdates.com/series/3", InternalID: "3", Source: "mangaupdates"},
+				},
+			},
+		},
+		{
+			MultiMangaID: 2,
+			Name:         "Vinland Saga",
+			Status:       2,
+			SimilarTitles: &models.SimilarTitles{
+				Genres: []string{"action", "drama", "historical"},
+				Titles: []*models.SimilarTitle{
+					{Name: "Vagabond", URL: "https://mangadex.org/title/2", Source: "mangadex", Genres: []string{"Action", "Historical"}},
+					{Name: "Kingdom", URL: "https://mangadex.org/title/4", Source: "mangadex", Genres: []string{"Action", "Historical"}},
+				},
+			},
+		},
+		{
+			MultiMangaID: 3,
+			Name:         "Yotsuba&!",
+			Status:       3,
+			SimilarTitles: &models.SimilarTitles{
+				Genres: []string{"Comedy", "Slice of Life"},
+				Titles: []*models.SimilarTitle{
+					{Name: "Azumanga Daioh", URL: "https://comick.io/comic/5", Source: "comick"},
+					{Name: "Claymore", URL: "https://comick.io/comic/1", Source: "comick"},
+				},
+			},
+		},
+	}
+}
+
+func TestRank(t *testing.T) {
+	library := NewLibrary([]*manga.Manga{
+		{Name: "Berserk", URL: "https://mangadex.org/title/berserk"},
+		{Name: "One-Punch Man", URL: "https://comick.io/comic/one-punch-man"},
+		{Name: "Kingdom (Official)", URL: "https://mangadex.org/title/4"},
+	})
+
+	profile, recommendations := Rank(getTestSeeds(), library, -1)
+
+	expectedGenres := []string{"action", "drama", "fantasy", "historical", "comedy", "slice of life"}
+	if len(profile.Genres) != len(expectedGenres) {
+		t.Fatalf("expected %d genres, got %d", len(expectedGenres), len(profile.Genres))
+	}
+	for i, genre := range expectedGenres {
+		if profile.Genres[i].Genre != genre {
+			t.Fatalf("expected genre %d to be '%s', got '%s'", i, genre, profile.Genres[i].Genre)
+		}
+	}
+	if profile.Genres[0].Weight != 6 {
+		t.Fatalf("expected action weight to be 6, got %f", profile.Genres[0].Weight)
+	}
+
+	// Kingdom is excluded by URL and One Punch-Man by name
+	expected := []struct {
+		name      string
+		url       string
+		score     float64
+		similarTo []string
+	}{
+		{name: "Vagabond", url: "https://www.mangaupdates.com/series/2", score: 8, similarTo: []string{"Berserk", "Vinland Saga"}},
+		{name: "Claymore", url: "https://www.mangaupdates.com/series/1", score: 4, similarTo: []string{"Berserk", "Yotsuba&!"}},
+		{name: "Azumanga Daioh", url: "https://comick.io/comic/5", score: 1, similarTo: []string{"Yotsuba&!"}},
+	}
+	if len(recommendations) != len(expected) {
+		t.Fatalf("expected %d recommendations, got %d", len(expected), len(recommendations))
+	}
+	for i, e := range expected {
+		r := recommendations[i]
+		if r.Name != e.name || r.URL != e.url || r.Score != e.score || !slices.Equal(r.SimilarTo, e.similarTo) {
+			t.Fatalf("expected recommendation %d to be %v, got %v", i, e, r)
+		}
+	}
+
+	if !slices.Equal(recommendations[0].MatchedGenres, []string{"action", "historical"}) {
+		t.Fatalf("unexpected matched genres: %v", recommendations[0].MatchedGenres)
+	}
+	expectedReason := "Similar to Berserk (reading), Vinland Saga (completed); matches your top genres: action, historical"
+	if recommendations[0].Reason != expectedReason {
+		t.Fatalf("expected reason '%s', got '%s'", expectedReason, recommendations[0].Reason)
+	}
+}
+
+func TestRankLimit(t *testing.T) {
+	_, recommendations := Rank(getTestSeeds(), NewLibrary(nil), 2)
+	if len(recommendations) != 2 {
+		t.Fatalf("expected 2 recommendations, got %d", len(recommendations))
+	}
+}
+
+func TestRankExcludesLibraryExternalIDs(t *testing.T) {
+	library := NewLibrary(nil)
+	library.AddExternalIDs(map[manga.ID]*manga.ExternalIDs{
+		1: {MultiMangaID: 1, MangaUpdatesID: "2"},
+		2: {MultiMangaID: 2},
+	})
+
+	_, recommendations := Rank(getTestSeeds(), library, -1)
+	for _, r := range recommendations {
+		if r.InternalID == "2" && r.Source == "mangaupdates" {
+			t.Fatalf("expected the title with the library's MangaUpdates ID to be excluded, got %v", r)
+		}
+	}
+	if len(recommendations) != 5 {
+		t.Fatalf("expected 5 recommendations, got %d", len(recommendations))
+	}
+}
+
+func TestRankSeedsWithSameName(t *testing.T) {
+	titles := &models.SimilarTitles{Titles: []*models.SimilarTitle{{Name: "Vagabond", URL: "https://mangadex.org/title/2", Source: "mangadex"}}}
+	seeds := []*Seed{
+		{MultiMangaID: 1, Name: "Berserk", Status: 1, SimilarTitles: titles},
+		{MultiMangaID: 2, Name: "Berserk", Status: 5, SimilarTitles: titles},
+	}
+
+	_, recommendations := Rank(seeds, NewLibrary(nil), -1)
+	if len(recommendations) != 1 {
+		t.Fatalf("expected 1 recommendation, got %d", len(recommendations))
+	}
+	if recommendations[0].Score != 4 {
+		t.Fatalf("expected score 4, got %f", recommendations[0].Score)
+	}
+	expectedReason := "Similar to Berserk (reading), Berserk (plan to read)"
+	if recommendations[0].Reason != expectedReason {
+		t.Fatalf("expected reason '%s', got '%s'", expectedReason, recommendations[0].Reason)
+	}
+}
@@ -0,0 +1,144 @@
+package routes
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/diogovalentte/mantium/api/src/actions"
+	"github.com/diogovalentte/mantium/api/src/config"
+	"github.com/diogovalentte/mantium/api/src/dashboard"
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/sources"
+)
+
+// ActionsRoutes sets the signed action links routes
+func ActionsRoutes(group *gin.RouterGroup) {
+	{
+		group.GET("/actions/mark_read", MarkReadAction)
+		group.POST("/actions/mark_read", MarkReadAction)
+	}
+}
+
+// @Summary Mark chapter as read using an action link
+// @Description Sets a multimanga last read chapter to the chapter signed in the action link token. The links are created by the API and used in the iFrame and notifications, and expire after ACTION_LINKS_TTL_HOURS. GET requests (opened in the browser) return an HTML page, POST requests return JSON. If the chapter was already read, nothing changes.
+// @Produce json,html
+// @Param token query string true "Action link token"
+// @Success 200 {object} responseMessage
+// @Router /actions/mark_read [get]
+// @Router /actions/mark_read [post]
+func MarkReadAction(c *gin.Context) {
+	currentTime := time.Now()
+
+	respond := func(statusCode int, message string) {
+		if c.Request.Method == http.MethodGet {
+			c.Data(statusCode, "text/html; charset=utf-8", getActionResultPage(message))
+			return
+		}
+		c.JSON(statusCode, gin.H{"message": message})
+	}
+
+	if !config.GlobalConfigs.ActionLinks.Valid {
+		respond(http.StatusNotFound, errordefs.ErrActionLinksDisabled.Error())
+		return
+	}
+
+	action, err := actions.Verify(c.Query("token"), config.GlobalConfigs.ActionLinks.Secret, currentTime)
+	if err != nil {
+		if strings.Contains(err.Error(), errordefs.ErrActionLinkExpired.Error()) {
+			respond(http.StatusGone, err.Error())
+			return
+		}
+		respond(http.StatusForbidden, err.Error())
+		return
+	}
+	if action.Type != actions.TypeMarkRead {
+		respond(http.StatusBadRequest, errordefs.ErrActionLinkInvalid.Error())
+		return
+	}
+
+	multimanga, err := manga.GetMultiMangaFromDB(action.MultiMangaID)
+	if err != nil {
+		if strings.Contains(err.Error(), errordefs.ErrMultiMangaNotFoundDB.Error()) {
+			respond(http.StatusNotFound, err.Error())
+			return
+		}
+		respond(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	var mangaGetChapterFrom *manga.Manga
+	for _, m := range multimanga.Mangas {
+		if m.ID == action.MangaID {
+			mangaGetChapterFrom = m
+			break
+		}
+	}
+	if mangaGetChapterFrom == nil {
+		respond(http.StatusNotFound, errordefs.ErrMangaNotFoundInMultiManga.Error())
+		return
+	}
+
+	var chapter *manga.Chapter
+	if mangaGetChapterFrom.LastReleasedChapter != nil && mangaGetChapterFrom.LastReleasedChapter.Chapter == action.Chapter {
+		chapterCopy := *mangaGetChapterFrom.LastReleasedChapter
+		chapter = &chapterCopy
+	} else {
+		// A newer chapter was released after the link was created
+		var preferences *manga.ChapterPreferences
+		preferences, err = manga.GetChapterPreferencesDB(mangaGetChapterFrom.ID)
+		if err != nil {
+			respond(http.StatusInternalServerError, err.Error())
+			return
+		}
+		chapter, err = sources.GetChapterMetadata(c.Request.Context(), mangaGetChapterFrom.URL, mangaGetChapterFrom.InternalID, action.Chapter, action.ChapterURL, "", preferences)
+		if err != nil {
+			respond(http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	if multimanga.LastReadChapter != nil && !manga.IsChapterUnread(chapter, multimanga.LastReadChapter) {
+		respond(http.StatusOK, fmt.Sprintf("Chapter %s of %s was already read", chapter.Chapter, mangaGetChapterFrom.Name))
+		return
+	}
+
+	err = setMultiMangaLastReadChapter(c.Request.Context(), multimanga, mangaGetChapterFrom, chapter, currentTime, false)
+	if err != nil {
+		respond(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	dashboard.UpdateDashboard()
+
+	respond(http.StatusOK, fmt.Sprintf("Chapter %s of %s marked as read", chapter.Chapter, mangaGetChapterFrom.Name))
+}
+
+var actionResultPageTemplate = template.Must(template.New("action").Parse(`<!doctype html>
+<html lang="en">
+  <head>
+    <meta charset="UTF-8" />
+    <meta name="viewport" content="width=device-width, initial-scale=1.0" />
+    <title>Mantium</title>
+  </head>
+  <body style="font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, Helvetica, Arial, sans-serif; text-align: center; margin-top: 20vh;">
+    <h2>Mantium</h2>
+    <p>{{ . }}</p>
+  </body>
+</html>
+`))
+
+func getActionResultPage(message string) []byte {
+	var buf strings.Builder
+	err := actionResultPageTemplate.Execute(&buf, message)
+	if err != nil {
+		return []byte(message)
+	}
+
+	return []byte(buf.String())
+}
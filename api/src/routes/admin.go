@@ -0,0 +1,153 @@
+package routes
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+
+	"github.com/diogovalentte/mantium/api/src/config"
+	"github.com/diogovalentte/mantium/api/src/dashboard"
+	"github.com/diogovalentte/mantium/api/src/db"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/sources"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// AdminRoutes sets the admin routes, used for maintenance tasks
+func AdminRoutes(group *gin.RouterGroup) {
+	{
+		group.PATCH("/admin/source_tld", ChangeSourceTLD)
+		group.POST("/admin/migrations", ApplyMigrations)
+		group.POST("/admin/cover_imgs", RefetchCoverImgs)
+	}
+}
+
+var tldRegex = regexp.MustCompile(`^[a-z]{2,63}$`)
+
+// @Summary Change source TLD
+// @Description Changes the TLD of all mangas URLs of a source in the database, like when the source changes its domain and the old one doesn't redirect to the new one. Sources with a TLD set in the code, like klmanga, have it applied again when the API starts.
+// @Produce json
+// @Param source query string true "Source name" Example(klmanga)
+// @Param tld query string true "New TLD, without the dot" Example(st)
+// @Success 200 {object} responseMessage
+// @Router /admin/source_tld [patch]
+func ChangeSourceTLD(c *gin.Context) {
+	source := c.Query("source")
+	if _, ok := sources.Sources[source]; !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("source '%s' not found", source)})
+		return
+	}
+	tld := c.Query("tld")
+	if !tldRegex.MatchString(tld) {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "tld must have only lower case letters, like 'io'"})
+		return
+	}
+
+	err := sources.ChangeSourceTLDInDB(source, tld)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	dashboard.UpdateDashboard()
+
+	c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("Source %s TLD changed to %s successfully", source, tld)})
+}
+
+// @Summary Apply migrations
+// @Description Creates the database tables and applies the database migrations again, like when the API starts. It can be used to fix the database after restoring a backup.
+// @Produce json
+// @Success 200 {object} responseMessage
+// @Router /admin/migrations [post]
+func ApplyMigrations(c *gin.Context) {
+	_db, err := db.OpenConn()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	defer _db.Close()
+
+	logger := util.GetLogger(zerolog.Level(config.GlobalConfigs.API.LogLevelInt))
+	err = db.CreateTables(_db, logger)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Migrations applied successfully"})
+}
+
+// @Summary Refetch cover images
+// @Description Gets the cover image of all multimangas' mangas from their source again. Custom mangas and mangas with a cover image set by the user are ignored. If it fails to get a manga cover image, it will continue with the next manga.
+// @Produce json
+// @Param include_fixed query bool false "Also refetch the cover images set by the user" Example(true)
+// @Success 200 {object} responseMessage
+// @Router /admin/cover_imgs [post]
+func RefetchCoverImgs(c *gin.Context) {
+	includeFixed := c.Query("include_fixed") == "true"
+
+	multimangas, err := manga.GetMultiMangasDB(true)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	logger := util.GetLogger(zerolog.Level(config.GlobalConfigs.API.LogLevelInt))
+	retries := 3
+	retryInterval := 3 * time.Second
+	var updated int
+	var errorSlice []string
+	for _, multimanga := range multimangas {
+		for _, m := range multimanga.Mangas {
+			if m.Source == manga.CustomMangaSource || (m.CoverImgFixed && !includeFixed) {
+				continue
+			}
+
+			err = refetchMangaCoverImg(c.Request.Context(), m, retries, retryInterval)
+			if err != nil {
+				logger.Error().Err(err).Str("manga_url", m.URL).Msg("error refetching manga cover image, will continue with the next manga...")
+				errorSlice = append(errorSlice, err.Error())
+				continue
+			}
+			updated++
+		}
+	}
+
+	dashboard.UpdateDashboard()
+
+	if len(errorSlice) > 0 {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": fmt.Sprintf("some errors occured while refetching the cover images, check the logs for more information. Last error: %s", errorSlice[len(errorSlice)-1]), "errors": errorSlice})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("%d cover images refetched successfully", updated)})
+}
+
+func refetchMangaCoverImg(ctx context.Context, m *manga.Manga, retries int, retryInterval time.Duration) error {
+	var updatedManga *manga.Manga
+	var err error
+	for i := 0; i < retries; i++ {
+		updatedManga, err = sources.GetMangaMetadata(ctx, m.URL, m.InternalID, nil)
+		if err == nil && len(updatedManga.CoverImg) > 0 {
+			break
+		}
+		if i < retries-1 {
+			time.Sleep(retryInterval)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if len(updatedManga.CoverImg) == 0 {
+		return fmt.Errorf("cover image of manga '%s' not found in source", m)
+	}
+
+	m.CoverImgFixed = false
+
+	return m.UpdateCoverImgInDB(updatedManga.CoverImg, updatedManga.CoverImgResized, updatedManga.CoverImgURL)
+}
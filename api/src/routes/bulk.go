@@ -0,0 +1,345 @@
+package routes
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/diogovalentte/mantium/api/src/config"
+	"github.com/diogovalentte/mantium/api/src/dashboard"
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/integrations/downloads"
+	"github.com/diogovalentte/mantium/api/src/jobs"
+	"github.com/diogovalentte/mantium/api/src/manga"
+)
+
+// Bulk operations
+const (
+	BulkOperationSetStatus         = "set_status"
+	BulkOperationMarkLatestRead    = "mark_latest_read"
+	BulkOperationDelete            = "delete"
+	BulkOperationAddTag            = "add_tag"
+	BulkOperationPushToIntegration = "push_to_integration"
+)
+
+// BulkMultiMangasRequest is the request body for the BulkMultiMangas route.
+// Either IDs or Filter must be provided.
+type BulkMultiMangasRequest struct {
+	Filter *manga.MultiMangaFilter `json:"filter"`
+	// Operation is one of the bulk operations, like "set_status".
+	Operation string     `json:"operation" binding:"required,oneof=set_status mark_latest_read delete add_tag push_to_integration"`
+	IDs       []manga.ID `json:"ids"`
+	// Status is the new status of the set_status operation.
+	Status manga.Status `json:"status" binding:"gte=0,lte=5"`
+	// Tag is the tag of the add_tag operation.
+	Tag string `json:"tag"`
+	// Integration is the download integration of the push_to_integration operation, like "suwayomi".
+	Integration string `json:"integration"`
+}
+
+// BulkMultiMangaResult is the result of a bulk operation with a multimanga.
+type BulkMultiMangaResult struct {
+	Error        string   `json:"error,omitempty"`
+	MultiMangaID manga.ID `json:"multimanga_id"`
+	// JobID is the ID of the job enqueued by the push_to_integration operation.
+	JobID int `json:"job_id,omitempty"`
+	// Skipped is why the operation skipped the multimanga, like the push_to_integration
+	// operation with a custom manga. Skipped multimangas are successful.
+	Skipped string `json:"skipped,omitempty"`
+	Success bool   `json:"success"`
+}
+
+// bulkMultiMangasResponse is the response of the BulkMultiMangas route.
+type bulkMultiMangasResponse struct {
+	Message string                  `json:"message"`
+	Results []*BulkMultiMangaResult `json:"results"`
+}
+
+// @Summary Bulk multimangas operation
+// @Description Runs an operation with many multimangas, selected by their IDs or by a filter. The operations are: set_status (requires status), mark_latest_read (sets the last read chapter to the current manga's last released chapter), delete, add_tag (requires tag), and push_to_integration (requires integration, like suwayomi; the current manga is added to it by a background job, and custom mangas and mangas from sources the integration doesn't support are skipped). The set_status, add_tag, and delete operations run in one transaction. The removal policies of the download integrations are applied like when updating or deleting one multimanga. It returns the result of each multimanga, and status 500 if any failed.
+// @Accept json
+// @Produce json
+// @Param request body BulkMultiMangasRequest true "Multimangas and operation, like the dropped multimangas filter and the delete operation"
+// @Success 200 {object} bulkMultiMangasResponse
+// @Router /multimangas/bulk [post]
+func BulkMultiMangas(c *gin.Context) {
+	currentTime := time.Now()
+
+	var requestData BulkMultiMangasRequest
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "invalid JSON fields, refer to the API documentation"})
+		return
+	}
+	if (len(requestData.IDs) > 0) == (requestData.Filter != nil) {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "either ids or filter must be provided"})
+		return
+	}
+	// Prevents running an operation, like delete, with the whole library by mistake
+	if requestData.Filter != nil && requestData.Filter.IsEmpty() {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "filter must have at least one field"})
+		return
+	}
+
+	var integration downloads.DownloadIntegration
+	switch requestData.Operation {
+	case BulkOperationSetStatus:
+		if requestData.Status == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "status must be provided"})
+			return
+		}
+	case BulkOperationAddTag:
+		if len(manga.NormalizeTags([]string{requestData.Tag})) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "tag must be provided"})
+			return
+		}
+	case BulkOperationPushToIntegration:
+		for _, i := range downloads.GetIntegrations() {
+			if strings.EqualFold(i.Name(), requestData.Integration) {
+				integration = i
+				break
+			}
+		}
+		if integration == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("download integration '%s' is not configured in the API", requestData.Integration)})
+			return
+		}
+	}
+
+	allMultimangas, err := manga.GetMultiMangasDB(true)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	multimangas, results := selectBulkMultiMangas(allMultimangas, requestData.IDs, requestData.Filter)
+
+	var operationResults []*BulkMultiMangaResult
+	switch requestData.Operation {
+	case BulkOperationSetStatus:
+		operationResults, err = bulkSetStatus(multimangas, requestData.Status)
+	case BulkOperationAddTag:
+		operationResults, err = bulkAddTag(multimangas, requestData.Tag)
+	case BulkOperationDelete:
+		operationResults, err = bulkDelete(multimangas)
+	case BulkOperationMarkLatestRead:
+		operationResults = bulkMarkLatestRead(c.Request.Context(), multimangas, currentTime)
+	case BulkOperationPushToIntegration:
+		operationResults = bulkPushToIntegration(multimangas, integration, currentTime)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	results = append(results, operationResults...)
+
+	if len(multimangas) > 0 && requestData.Operation != BulkOperationPushToIntegration {
+		dashboard.UpdateDashboard()
+	}
+
+	var failed int
+	for _, result := range results {
+		if !result.Success {
+			failed++
+		}
+	}
+	if failed > 0 {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": fmt.Sprintf("operation failed with %d of %d multimangas, check the results for more information", failed, len(results)), "results": results})
+		return
+	}
+
+	c.JSON(http.StatusOK, bulkMultiMangasResponse{Message: fmt.Sprintf("Bulk operation done successfully with %d multimangas", len(results)), Results: results})
+}
+
+// selectBulkMultiMangas returns the multimangas with the IDs or matching the filter.
+// It also returns the failed results of the IDs not found.
+func selectBulkMultiMangas(multimangas []*manga.MultiManga, ids []manga.ID, filter *manga.MultiMangaFilter) ([]*manga.MultiManga, []*BulkMultiMangaResult) {
+	selected := []*manga.MultiManga{}
+	results := []*BulkMultiMangaResult{}
+	if filter != nil {
+		for _, mm := range multimangas {
+			if filter.Matches(mm) {
+				selected = append(selected, mm)
+			}
+		}
+		return selected, results
+	}
+
+	multimangasByID := make(map[manga.ID]*manga.MultiManga, len(multimangas))
+	for _, mm := range multimangas {
+		multimangasByID[mm.ID] = mm
+	}
+	seen := map[manga.ID]bool{}
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		mm, ok := multimangasByID[id]
+		if !ok {
+			results = append(results, &BulkMultiMangaResult{MultiMangaID: id, Error: errordefs.ErrMultiMangaNotFoundDB.Error()})
+			continue
+		}
+		selected = append(selected, mm)
+	}
+
+	return selected, results
+}
+
+func bulkSetStatus(multimangas []*manga.MultiManga, status manga.Status) ([]*BulkMultiMangaResult, error) {
+	previousStatus := make(map[manga.ID]manga.Status, len(multimangas))
+	for _, mm := range multimangas {
+		previousStatus[mm.ID] = mm.Status
+	}
+
+	errors, err := manga.BulkUpdateStatusInDB(multimangas, status)
+	if err != nil {
+		return nil, err
+	}
+
+	event := downloads.StatusRemovalEvent(status)
+	results := newBulkResults(multimangas, errors)
+	if event == "" || len(downloads.GetIntegrations()) == 0 {
+		return results, nil
+	}
+	keepMangas, keepErr := getRemovalKeepMangas(multimangas)
+	for i, mm := range multimangas {
+		if !results[i].Success || previousStatus[mm.ID] == status {
+			continue
+		}
+		if keepErr != nil {
+			setBulkIntegrationsErrors(results[i], "status updated in DB, but error executing integrations: ", []error{keepErr})
+			continue
+		}
+		setBulkIntegrationsErrors(results[i], "status updated in DB, but error executing integrations: ", applyRemovalPolicies(event, mm.Mangas, keepMangas))
+	}
+
+	return results, nil
+}
+
+func bulkAddTag(multimangas []*manga.MultiManga, tag string) ([]*BulkMultiMangaResult, error) {
+	errors, err := manga.BulkAddTagInDB(multimangas, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	return newBulkResults(multimangas, errors), nil
+}
+
+func bulkDelete(multimangas []*manga.MultiManga) ([]*BulkMultiMangaResult, error) {
+	errors, err := manga.BulkDeleteFromDB(multimangas)
+	if err != nil {
+		return nil, err
+	}
+
+	results := newBulkResults(multimangas, errors)
+	if len(downloads.GetIntegrations()) == 0 {
+		return results, nil
+	}
+	keepMangas, keepErr := getRemovalKeepMangas(multimangas)
+	for i, mm := range multimangas {
+		if !results[i].Success {
+			continue
+		}
+		if keepErr != nil {
+			setBulkIntegrationsErrors(results[i], "deleted from DB, but error executing integrations: ", []error{keepErr})
+			continue
+		}
+		setBulkIntegrationsErrors(results[i], "deleted from DB, but error executing integrations: ", applyRemovalPolicies(config.RemovalEventDelete, mm.Mangas, keepMangas))
+	}
+
+	return results, nil
+}
+
+// bulkMarkLatestRead sets the multimangas' last read chapter to their current manga's last
+// released chapter. Each multimanga is updated in its own transaction, like when updating one.
+func bulkMarkLatestRead(ctx context.Context, multimangas []*manga.MultiManga, currentTime time.Time) []*BulkMultiMangaResult {
+	results := make([]*BulkMultiMangaResult, 0, len(multimangas))
+	for _, mm := range multimangas {
+		result := &BulkMultiMangaResult{MultiMangaID: mm.ID, Success: true}
+		results = append(results, result)
+
+		releasedChapter := mm.CurrentManga.LastReleasedChapter
+		if releasedChapter == nil {
+			result.Success = false
+			result.Error = errordefs.ErrLastReleasedChapterNotFound.Error()
+			continue
+		}
+		if mm.LastReadChapter != nil && mm.LastReadChapter.Chapter == releasedChapter.Chapter {
+			continue
+		}
+
+		chapter := *releasedChapter
+		err := setMultiMangaLastReadChapter(ctx, mm, mm.CurrentManga, &chapter, currentTime, false)
+		if err != nil {
+			result.Success = false
+			result.Error = err.Error()
+		}
+	}
+
+	return results
+}
+
+func bulkPushToIntegration(multimangas []*manga.MultiManga, integration downloads.DownloadIntegration, currentTime time.Time) []*BulkMultiMangaResult {
+	results := make([]*BulkMultiMangaResult, 0, len(multimangas))
+	for _, mm := range multimangas {
+		result := &BulkMultiMangaResult{MultiMangaID: mm.ID, Success: true}
+		results = append(results, result)
+
+		if mm.CurrentManga.Source == manga.CustomMangaSource {
+			result.Skipped = "custom mangas can't be added to download integrations"
+			continue
+		}
+		if !slices.Contains(integration.SupportedSources(), mm.CurrentManga.Source) {
+			result.Skipped = fmt.Sprintf("%s doesn't support the source %s", integration.Name(), mm.CurrentManga.Source)
+			continue
+		}
+
+		job, err := jobs.Enqueue(jobs.TypeAddMangaToIntegration, integration.Name(), mm.CurrentManga.ID, config.GlobalConfigs.Jobs.MaxAttempts, currentTime)
+		if err != nil {
+			result.Success = false
+			result.Error = err.Error()
+			continue
+		}
+		result.JobID = job.ID
+	}
+	for _, result := range results {
+		if result.JobID != 0 {
+			jobs.Wake()
+			break
+		}
+	}
+
+	return results
+}
+
+// newBulkResults returns the results of the multimangas from the errors returned by a bulk DB function.
+func newBulkResults(multimangas []*manga.MultiManga, errors map[manga.ID]error) []*BulkMultiMangaResult {
+	results := make([]*BulkMultiMangaResult, 0, len(multimangas))
+	for _, mm := range multimangas {
+		result := &BulkMultiMangaResult{MultiMangaID: mm.ID, Success: true}
+		if err := errors[mm.ID]; err != nil {
+			result.Success = false
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+
+	return results
+}
+
+func setBulkIntegrationsErrors(result *BulkMultiMangaResult, message string, integrationsErrors []error) {
+	if len(integrationsErrors) == 0 {
+		return
+	}
+
+	result.Success = false
+	result.Error = message
+	for _, err := range integrationsErrors {
+		result.Error += err.Error() + " "
+	}
+	result.Error = strings.TrimSpace(result.Error)
+}
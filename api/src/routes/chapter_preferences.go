@@ -0,0 +1,134 @@
+package routes
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/sources"
+	"github.com/diogovalentte/mantium/api/src/sources/models"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// @Summary Get manga chapter preferences
+// @Description Gets the manga chapter preferences. They're empty if the preferences were never set. You must provide either the manga ID or the manga URL.
+// @Produce json
+// @Param id query int false "Manga ID" Example(1)
+// @Param url query string false "Manga URL" Example("https://mangadex.org/title/1/one-piece")
+// @Success 200 {object} manga.ChapterPreferences "{"chapter_preferences": preferencesObj}"
+// @Router /manga/chapter_preferences [get]
+func GetMangaChapterPreferences(c *gin.Context) {
+	mangaGet, ok := getMangaWithReleases(c)
+	if !ok {
+		return
+	}
+
+	preferences, err := manga.GetChapterPreferencesDB(mangaGet.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"chapter_preferences": preferences})
+}
+
+// @Summary Set manga chapter preferences
+// @Description Sets the preferences used to choose between the releases of the same chapter in sources with releases in multiple languages or by multiple scanlation groups, like MangaDex and ComicK. Only releases in the languages are used, from the most to the least preferred; if empty, the source's default language is used. The release by the group with the highest priority is used, and the releases by blocked groups are ignored. If wait_hours is set, a new chapter isn't used until a preferred group releases it or the hours pass. The preferences are used to get the manga chapters and, in the next mangas metadata update, its last released chapter. You must provide either the manga ID or the manga URL.
+// @Accept json
+// @Produce json
+// @Param id query int false "Manga ID" Example(1)
+// @Param url query string false "Manga URL" Example("https://mangadex.org/title/1/one-piece")
+// @Param chapter_preferences body SetMangaChapterPreferencesRequest true "Chapter preferences"
+// @Success 200 {object} manga.ChapterPreferences "{"message": "Manga chapter preferences set successfully", "chapter_preferences": preferencesObj}"
+// @Router /manga/chapter_preferences [put]
+func SetMangaChapterPreferences(c *gin.Context) {
+	var requestData SetMangaChapterPreferencesRequest
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "invalid JSON fields, refer to the API documentation"})
+		return
+	}
+
+	mangaSet, ok := getMangaWithReleases(c)
+	if !ok {
+		return
+	}
+
+	preferences := &manga.ChapterPreferences{
+		MangaID:       mangaSet.ID,
+		Languages:     requestData.Languages,
+		Groups:        requestData.Groups,
+		BlockedGroups: requestData.BlockedGroups,
+		WaitHours:     requestData.WaitHours,
+	}
+	err := preferences.UpsertIntoDB()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Manga chapter preferences set successfully", "chapter_preferences": preferences})
+}
+
+// SetMangaChapterPreferencesRequest is the request body for the SetMangaChapterPreferences route
+type SetMangaChapterPreferencesRequest struct {
+	Languages     []string `json:"languages"`
+	Groups        []string `json:"groups"`
+	BlockedGroups []string `json:"blocked_groups"`
+	WaitHours     int      `json:"wait_hours" binding:"gte=0"`
+}
+
+// getMangaWithReleases gets the manga by the ID or URL in the query and checks
+// if its source has multiple releases of the chapters. If not, it responds with an error.
+func getMangaWithReleases(c *gin.Context) (*manga.Manga, bool) {
+	mangaID, mangaURL, err := getMangaIDAndURL(c.Query("id"), c.Query("url"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return nil, false
+	}
+
+	mangaGet, err := manga.GetMangaDB(mangaID, mangaURL)
+	if err != nil {
+		if strings.Contains(err.Error(), errordefs.ErrMangaNotFoundDB.Error()) {
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return nil, false
+	}
+
+	if mangaGet.Source == manga.CustomMangaSource {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "custom mangas don't have chapter preferences"})
+		return nil, false
+	}
+	source, err := sources.GetSource(mangaGet.URL)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return nil, false
+	}
+	if _, ok := source.(models.ReleasesLister); !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "the manga source doesn't have multiple releases of the same chapter"})
+		return nil, false
+	}
+
+	return mangaGet, true
+}
+
+// getMangaChapterPreferences returns the chapter preferences of the manga by its ID or URL.
+// It returns nil if the manga isn't in the database, like when it's being added.
+func getMangaChapterPreferences(mangaID manga.ID, mangaURL string) (*manga.ChapterPreferences, error) {
+	if mangaID <= 0 {
+		mangaGet, err := manga.GetMangaDBByURL(mangaURL)
+		if err != nil {
+			if util.ErrorContains(err, errordefs.ErrMangaNotFoundDB.Error()) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		mangaID = mangaGet.ID
+	}
+
+	return manga.GetChapterPreferencesDB(mangaID)
+}
@@ -0,0 +1,412 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"github.com/diogovalentte/mantium/api/src/dashboard"
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/notifications"
+	"github.com/diogovalentte/mantium/api/src/util"
+	"github.com/diogovalentte/mantium/api/src/watcher"
+)
+
+// @Summary Get custom manga chapters
+// @Description Gets the custom manga chapter list, sorted from the first to the last chapter. You must provide either the manga ID or the manga URL.
+// @Produce json
+// @Param id query int false "Manga ID" Example(1)
+// @Param url query string false "Manga URL" Example("https://mangadex.org/title/1/one-piece")
+// @Success 200 {array} manga.CustomChapter "{"chapters": [chapterObj]}"
+// @Router /custom_manga/chapters [get]
+func GetCustomMangaChapters(c *gin.Context) {
+	customManga, ok := getCustomManga(c)
+	if !ok {
+		return
+	}
+
+	chapters, err := customManga.GetCustomChaptersFromDB()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	for _, chapter := range chapters {
+		if strings.HasPrefix(chapter.URL, manga.CustomMangaURLPrefix) {
+			chapter.URL = ""
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"chapters": chapters})
+}
+
+// @Summary Add custom manga chapters
+// @Description Adds the chapters to the custom manga chapter list, or updates them if they're already in the list. The custom manga's last released chapter is the last chapter of the list, so the custom manga has unread chapters when the last chapter of the list comes after its last read chapter. The chapter name defaults to the chapter, and the release date to now. You must provide either the manga ID or the manga URL.
+// @Accept json
+// @Produce json
+// @Param id query int false "Manga ID" Example(1)
+// @Param url query string false "Manga URL" Example("https://mangadex.org/title/1/one-piece")
+// @Param chapters body AddCustomMangaChaptersRequest true "Chapters"
+// @Success 200 {object} responseMessage
+// @Router /custom_manga/chapters [post]
+func AddCustomMangaChapters(c *gin.Context) {
+	currentTime := time.Now()
+
+	var requestData AddCustomMangaChaptersRequest
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "invalid JSON fields, refer to the API documentation"})
+		return
+	}
+
+	customManga, ok := getCustomManga(c)
+	if !ok {
+		return
+	}
+
+	chapters := make([]*manga.CustomChapter, 0, len(requestData.Chapters))
+	for _, requestChapter := range requestData.Chapters {
+		chapter := &manga.CustomChapter{
+			Chapter:    strings.TrimSpace(requestChapter.Chapter),
+			Name:       strings.TrimSpace(requestChapter.Name),
+			URL:        requestChapter.URL,
+			ReleasedAt: requestChapter.ReleasedAt.Truncate(time.Second),
+		}
+		if chapter.Chapter == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "chapter can't be empty"})
+			return
+		}
+		if chapter.Name == "" {
+			chapter.Name = chapter.Chapter
+		}
+		if chapter.URL == "" {
+			chapter.URL = manga.CustomMangaURLPrefix + "/" + uuid.New().String()
+		}
+		if chapter.ReleasedAt.IsZero() {
+			chapter.ReleasedAt = currentTime.Truncate(time.Second)
+		}
+		chapters = append(chapters, chapter)
+	}
+
+	err := customManga.UpsertCustomChaptersInDB(chapters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	dashboard.UpdateDashboard()
+
+	c.JSON(http.StatusOK, gin.H{"message": "Custom manga chapters added successfully"})
+}
+
+// AddCustomMangaChaptersRequest is the request body for the AddCustomMangaChapters route
+type AddCustomMangaChaptersRequest struct {
+	Chapters []struct {
+		ReleasedAt time.Time `json:"released_at"`
+		Chapter    string    `json:"chapter" binding:"required"`
+		Name       string    `json:"name"`
+		URL        string    `json:"url" binding:"omitempty,http_url"`
+	} `json:"chapters" binding:"required,min=1,dive"`
+}
+
+// @Summary Delete custom manga chapter
+// @Description Deletes a chapter from the custom manga chapter list. If the list is empty after deleting the chapter, the custom manga's last released chapter is kept. You must provide either the manga ID or the manga URL.
+// @Produce json
+// @Param id query int false "Manga ID" Example(1)
+// @Param url query string false "Manga URL" Example("https://mangadex.org/title/1/one-piece")
+// @Param chapter query string true "Chapter" Example("12")
+// @Success 200 {object} responseMessage
+// @Router /custom_manga/chapter [delete]
+func DeleteCustomMangaChapter(c *gin.Context) {
+	chapter := c.Query("chapter")
+	if chapter == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "chapter is required"})
+		return
+	}
+
+	customManga, ok := getCustomManga(c)
+	if !ok {
+		return
+	}
+
+	err := customManga.DeleteCustomChapterFromDB(chapter)
+	if err != nil {
+		if util.ErrorContains(err, errordefs.ErrCustomChapterNotFoundDB.Error()) {
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	dashboard.UpdateDashboard()
+
+	c.JSON(http.StatusOK, gin.H{"message": "Custom manga chapter deleted successfully"})
+}
+
+// @Summary Get custom manga watcher
+// @Description Gets the custom manga watcher, with the result of its last check. You must provide either the manga ID or the manga URL.
+// @Produce json
+// @Param id query int false "Manga ID" Example(1)
+// @Param url query string false "Manga URL" Example("https://mangadex.org/title/1/one-piece")
+// @Success 200 {object} manga.CustomMangaWatcher "{"watcher": watcherObj}"
+// @Router /custom_manga/watcher [get]
+func GetCustomMangaWatcher(c *gin.Context) {
+	customManga, ok := getCustomManga(c)
+	if !ok {
+		return
+	}
+
+	w, err := manga.GetCustomMangaWatcherDB(customManga.ID)
+	if err != nil {
+		if util.ErrorContains(err, errordefs.ErrCustomMangaWatcherNotFoundDB.Error()) {
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"watcher": w})
+}
+
+// @Summary Set custom manga watcher
+// @Description Sets a watcher that checks a page for new chapters of the custom manga when the mangas metadata is updated. The chapters are the text of the elements matched by the CSS selector, or the whole page text if it's empty. If the regex is set, the chapters are its matches in the text instead, or its first capturing group if it has one. The last chapter found is added to the custom manga chapter list; the first check doesn't notify. If the page has no chapters anymore, the check fails and the next chapter found is handled like in the first check. The page is checked right away, and the chapter found is returned. You must provide either the manga ID or the manga URL.
+// @Accept json
+// @Produce json
+// @Param id query int false "Manga ID" Example(1)
+// @Param url query string false "Manga URL" Example("https://mangadex.org/title/1/one-piece")
+// @Param watcher body SetCustomMangaWatcherRequest true "Watcher"
+// @Success 200 {object} manga.CustomMangaWatcher "{"message": "Custom manga watcher set successfully", "watcher": watcherObj}"
+// @Router /custom_manga/watcher [put]
+func SetCustomMangaWatcher(c *gin.Context) {
+	var requestData SetCustomMangaWatcherRequest
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "invalid JSON fields, refer to the API documentation"})
+		return
+	}
+	if requestData.Selector == "" && requestData.Regex == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "you must provide the selector, the regex, or both"})
+		return
+	}
+	if err := watcher.Validate(requestData.Selector, requestData.Regex); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	customManga, ok := getCustomManga(c)
+	if !ok {
+		return
+	}
+
+	w := &manga.CustomMangaWatcher{
+		MangaID:  customManga.ID,
+		URL:      requestData.URL,
+		Selector: requestData.Selector,
+		Regex:    requestData.Regex,
+	}
+	err := w.UpsertIntoDB()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	_, err = checkCustomMangaWatcher(c.Request.Context(), customManga, w, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "watcher set, but error checking the page: " + err.Error(), "watcher": w})
+		return
+	}
+
+	dashboard.UpdateDashboard()
+
+	c.JSON(http.StatusOK, gin.H{"message": "Custom manga watcher set successfully", "watcher": w})
+}
+
+// SetCustomMangaWatcherRequest is the request body for the SetCustomMangaWatcher route
+type SetCustomMangaWatcherRequest struct {
+	URL      string `json:"url" binding:"required,http_url"`
+	Selector string `json:"selector"`
+	Regex    string `json:"regex"`
+}
+
+// @Summary Delete custom manga watcher
+// @Description Deletes the custom manga watcher. The chapters it found are kept in the chapter list. You must provide either the manga ID or the manga URL.
+// @Produce json
+// @Param id query int false "Manga ID" Example(1)
+// @Param url query string false "Manga URL" Example("https://mangadex.org/title/1/one-piece")
+// @Success 200 {object} responseMessage
+// @Router /custom_manga/watcher [delete]
+func DeleteCustomMangaWatcher(c *gin.Context) {
+	customManga, ok := getCustomManga(c)
+	if !ok {
+		return
+	}
+
+	err := manga.DeleteCustomMangaWatcherDB(customManga.ID)
+	if err != nil {
+		if util.ErrorContains(err, errordefs.ErrCustomMangaWatcherNotFoundDB.Error()) {
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Custom manga watcher deleted successfully"})
+}
+
+// @Summary Check custom manga watcher
+// @Description Checks the custom manga watcher page for new chapters now. It doesn't notify. You must provide either the manga ID or the manga URL.
+// @Produce json
+// @Param id query int false "Manga ID" Example(1)
+// @Param url query string false "Manga URL" Example("https://mangadex.org/title/1/one-piece")
+// @Success 200 {object} manga.CustomMangaWatcher "{"message": "New chapter found", "new_chapter": true, "watcher": watcherObj}"
+// @Router /custom_manga/watcher/check [post]
+func CheckCustomMangaWatcher(c *gin.Context) {
+	customManga, ok := getCustomManga(c)
+	if !ok {
+		return
+	}
+
+	w, err := manga.GetCustomMangaWatcherDB(customManga.ID)
+	if err != nil {
+		if util.ErrorContains(err, errordefs.ErrCustomMangaWatcherNotFoundDB.Error()) {
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	newChapter, err := checkCustomMangaWatcher(c.Request.Context(), customManga, w, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error(), "watcher": w})
+		return
+	}
+
+	message := "No new chapter found"
+	if newChapter {
+		message = "New chapter found"
+		dashboard.UpdateDashboard()
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message, "new_chapter": newChapter, "watcher": w})
+}
+
+// getCustomManga gets the custom manga of the id or url query parameters.
+// If it fails, it responds to the request and returns false.
+func getCustomManga(c *gin.Context) (*manga.Manga, bool) {
+	mangaID, mangaURL, err := getMangaIDAndURL(c.Query("id"), c.Query("url"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return nil, false
+	}
+
+	customManga, err := manga.GetMangaDB(mangaID, mangaURL)
+	if err != nil {
+		if strings.Contains(err.Error(), errordefs.ErrMangaNotFoundDB.Error()) {
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return nil, false
+	}
+
+	if customManga.Source != manga.CustomMangaSource {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "the manga is not a custom manga"})
+		return nil, false
+	}
+
+	return customManga, true
+}
+
+// checkCustomMangaWatcher checks the watcher page and adds the last chapter found
+// to the custom manga chapter list if it's a new chapter. It returns true if it's
+// a new chapter and the page was checked before, so the user can be notified.
+// If the page has no chapters anymore, like when the site removes them or changes its
+// layout, it returns an error and the watcher last chapter is cleared, so the chapters
+// found when the page has chapters again are not compared with the old ones.
+func checkCustomMangaWatcher(ctx context.Context, customManga *manga.Manga, w *manga.CustomMangaWatcher, currentTime time.Time) (bool, error) {
+	lastChapter, checkErr := watcher.Check(ctx, w)
+	if checkErr != nil {
+		if util.ErrorContains(checkErr, errordefs.ErrCustomMangaWatcherNoChapters.Message) {
+			w.LastChapter = ""
+		}
+		err := w.UpdateCheckResultInDB(currentTime, "", checkErr)
+		if err != nil {
+			return false, util.AddErrorContext(checkErr.Error(), err)
+		}
+		return false, checkErr
+	}
+
+	firstCheck := w.LastChapter == ""
+	isNew := watcher.IsNewChapter(lastChapter, w.LastChapter)
+	if isNew {
+		chapter := &manga.CustomChapter{
+			Chapter:    lastChapter,
+			Name:       lastChapter,
+			URL:        w.URL,
+			ReleasedAt: currentTime.Truncate(time.Second),
+		}
+		err := customManga.UpsertCustomChaptersInDB([]*manga.CustomChapter{chapter})
+		if err != nil {
+			return false, err
+		}
+	}
+
+	err := w.UpdateCheckResultInDB(currentTime, lastChapter, nil)
+	if err != nil {
+		return false, err
+	}
+
+	return isNew && !firstCheck, nil
+}
+
+// checkCustomMangaWatchers checks all custom manga watchers and returns the custom mangas with new chapters.
+func checkCustomMangaWatchers(ctx context.Context, logger *zerolog.Logger) ([]*manga.Manga, []string) {
+	errors := []string{}
+
+	watchers, err := manga.GetCustomMangaWatchersDB()
+	if err != nil {
+		return nil, append(errors, err.Error())
+	}
+
+	mangasWithNewChapter := []*manga.Manga{}
+	for _, w := range watchers {
+		customManga, err := manga.GetMangaDBByID(w.MangaID)
+		if err != nil {
+			errors = append(errors, err.Error())
+			continue
+		}
+
+		newChapter, err := checkCustomMangaWatcher(ctx, customManga, w, time.Now())
+		if err != nil {
+			logger.Error().Err(err).Str("manga_url", customManga.URL).Msg("Error checking custom manga watcher.\nWill continue with the next custom manga...")
+			errors = append(errors, err.Error())
+			continue
+		}
+		if newChapter {
+			mangasWithNewChapter = append(mangasWithNewChapter, customManga)
+		}
+	}
+
+	return mangasWithNewChapter, errors
+}
+
+// notifyCustomMangaNewChapter notifies a custom manga new chapter found by its watcher.
+// Custom mangas don't have notification rules, so only the global mute rule is used.
+func notifyCustomMangaNewChapter(m *manga.Manga, retries int, retryInterval time.Duration, logger *zerolog.Logger) error {
+	rules, _, err := notifications.GetEffectiveRules(0)
+	if err != nil {
+		return err
+	}
+	if rules.Mute != nil && *rules.Mute {
+		return nil
+	}
+
+	return retryNotification(func() error { return NotifyMangaLastReleasedChapterUpdate(m) }, retries, retryInterval, logger, m.URL)
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/diogovalentte/mantium/api/src/config"
 	"github.com/diogovalentte/mantium/api/src/dashboard"
+	"github.com/diogovalentte/mantium/api/src/iframe"
 )
 
 // DashboardRoutes sets the routes for the dashboard.
@@ -61,6 +62,17 @@ func UpdateDashboardConfigs(c *gin.Context) {
 		return
 	}
 
+	if newConfigs.Display.IframeCSSVariables == nil {
+		// Clients that don't know about the iframe CSS variables shouldn't reset them
+		newConfigs.Display.IframeCSSVariables = config.GlobalConfigs.DashboardConfigs.Display.IframeCSSVariables
+	} else {
+		err = iframe.ValidateCSSVariables(newConfigs.Display.IframeCSSVariables)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
+	}
+
 	err = config.SaveConfigsToDB(&newConfigs)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": fmt.Sprintf("error while saving configs: %s", err.Error())})
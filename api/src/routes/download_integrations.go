@@ -0,0 +1,381 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+
+	"github.com/diogovalentte/mantium/api/src/config"
+	"github.com/diogovalentte/mantium/api/src/integrations/downloads"
+	"github.com/diogovalentte/mantium/api/src/jobs"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// DownloadIntegrationsRoutes sets the download integrations (Kaizoku, Tranga, and Suwayomi) routes.
+func DownloadIntegrationsRoutes(group *gin.RouterGroup) {
+	{
+		group.GET("/integrations/downloads/drift", GetDownloadIntegrationsDrift)
+		group.POST("/integrations/downloads/reconcile", ReconcileDownloadIntegrations)
+		group.POST("/integrations/downloads/cleanup", CleanupDownloadIntegrations)
+	}
+}
+
+// @Summary Get download integrations drift
+// @Description Compares Mantium's library with the library of the download integrations (Kaizoku, Tranga, and Suwayomi). Mantium's library is the multimangas' current manga, or all multimangas' mangas if set in the dashboard configs. The multimangas' other mangas are neither added nor reported as extra, and Kaizoku doesn't report extra mangas if it's set to try other sources. Custom mangas and mangas whose status has an action in the integration's removal policy, like dropped mangas, are ignored.
+// @Produce json
+// @Param integration query string false "Only get the drift of this integration." Example(suwayomi)
+// @Success 200 {array} downloads.Drift
+// @Router /integrations/downloads/drift [get]
+func GetDownloadIntegrationsDrift(c *gin.Context) {
+	integrations, err := getDownloadIntegrationsFromQuery(c.Query("integration"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	allMangas := config.GlobalConfigs.DashboardConfigs.Integrations.AddAllMultiMangaMangasToDownloadIntegrations
+	mangas, err := getDownloadIntegrationsMangas(allMangas, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	otherMangas, err := getDownloadIntegrationsOtherMangas(allMangas)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	drifts := make([]*downloads.Drift, 0, len(integrations))
+	for _, integration := range integrations {
+		drift, err := downloads.GetDrift(integration, mangas, otherMangas)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			return
+		}
+		drifts = append(drifts, drift)
+	}
+
+	c.JSON(http.StatusOK, drifts)
+}
+
+// @Summary Reconcile download integrations
+// @Description Adds the mangas in Mantium's library that are missing in the download integrations (Kaizoku, Tranga, and Suwayomi) to them. Mantium's library is the multimangas' current manga, or all multimangas' mangas if set in the dashboard configs. The multimangas' other mangas are neither added nor reported as extra, and Kaizoku doesn't report extra mangas if it's set to try other sources. Custom mangas and mangas whose status has an action in the integration's removal policy, like dropped mangas, are ignored. If it fails to add or remove a manga, it will continue with the next manga.
+// @Produce json
+// @Param integration query string false "Only reconcile this integration." Example(suwayomi)
+// @Param remove query bool false "Also remove the mangas that are not in Mantium from the integrations, like mangas deleted in Mantium. The downloaded chapters are kept. Be careful, it also removes the mangas added to the integrations outside Mantium." Example(true)
+// @Success 200 {array} downloads.ReconcileResult
+// @Router /integrations/downloads/reconcile [post]
+func ReconcileDownloadIntegrations(c *gin.Context) {
+	integrations, err := getDownloadIntegrationsFromQuery(c.Query("integration"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	remove := c.Query("remove") == "true"
+
+	allMangas := config.GlobalConfigs.DashboardConfigs.Integrations.AddAllMultiMangaMangasToDownloadIntegrations
+	mangas, err := getDownloadIntegrationsMangas(allMangas, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	otherMangas, err := getDownloadIntegrationsOtherMangas(allMangas)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	logger := util.GetLogger(zerolog.Level(config.GlobalConfigs.API.LogLevelInt))
+	results := make([]*downloads.ReconcileResult, 0, len(integrations))
+	var hasErrors bool
+	for _, integration := range integrations {
+		result, err := downloads.Reconcile(integration, mangas, otherMangas, remove)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			return
+		}
+		for _, errStr := range result.Errors {
+			logger.Error().Str("integration", integration.Name()).Msg("Error reconciling download integration: " + errStr)
+			hasErrors = true
+		}
+		results = append(results, result)
+	}
+
+	if hasErrors {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "some errors occured while reconciling the download integrations, check the logs for more information", "results": results})
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
+// @Summary Clean up download integrations
+// @Description Applies the removal policies of the download integrations (Kaizoku, Tranga, and Suwayomi) to the whole library. The "delete" policy action is done with the mangas in the integrations that are not in Mantium, like mangas deleted in Mantium, and the "drop" and "complete" policy actions with the mangas of dropped and completed multimangas. The policies are set by the environment variables `KAIZOKU_REMOVAL_POLICY`, `TRANGA_REMOVAL_POLICY`, and `SUWAYOMI_REMOVAL_POLICY`. Be careful, the "delete" policy action is also done with the mangas added to the integrations outside Mantium, so use the dry-run mode first.
+// @Produce json
+// @Param integration query string false "Only clean up this integration." Example(suwayomi)
+// @Param dry_run query bool false "Only return the actions that would be done." Example(true)
+// @Success 200 {array} downloads.Removal
+// @Router /integrations/downloads/cleanup [post]
+func CleanupDownloadIntegrations(c *gin.Context) {
+	integrations, err := getDownloadIntegrationsFromQuery(c.Query("integration"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	dryRun := c.Query("dry_run") == "true"
+
+	mangas, err := getDownloadIntegrationsMangas(true, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	logger := util.GetLogger(zerolog.Level(config.GlobalConfigs.API.LogLevelInt))
+	removals := []*downloads.Removal{}
+	var hasErrors bool
+	for _, integration := range integrations {
+		integrationRemovals, err := downloads.Cleanup(integration, mangas, dryRun)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			return
+		}
+		for _, removal := range integrationRemovals {
+			if removal.Error != "" {
+				logger.Error().Str("integration", integration.Name()).Str("manga", removal.Manga.Title).Msg("Error cleaning up download integration: " + removal.Error)
+				hasErrors = true
+			}
+		}
+		removals = append(removals, integrationRemovals...)
+	}
+
+	if hasErrors {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "some errors occured while cleaning up the download integrations, check the logs for more information", "removals": removals})
+		return
+	}
+
+	c.JSON(http.StatusOK, removals)
+}
+
+// getDownloadIntegrationsFromQuery returns the configured download integrations.
+// If name is not empty, it returns only the integration with the name.
+func getDownloadIntegrationsFromQuery(name string) ([]downloads.DownloadIntegration, error) {
+	integrations := downloads.GetIntegrations()
+	if len(integrations) == 0 {
+		return nil, fmt.Errorf("no download integration (Kaizoku, Tranga, or Suwayomi) is configured in the API")
+	}
+	if name == "" {
+		return integrations, nil
+	}
+
+	for _, integration := range integrations {
+		if strings.EqualFold(integration.Name(), name) {
+			return []downloads.DownloadIntegration{integration}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s is not configured in the API", name)
+}
+
+// getDownloadIntegrationsMangas returns the mangas that should be in the download integrations.
+// If allMangas is false, only the multimangas' current manga is returned.
+// If statusFilter is not empty, only the mangas of multimangas with these statuses are returned.
+func getDownloadIntegrationsMangas(allMangas bool, statusFilter []int) ([]*manga.Manga, error) {
+	multimangas, err := manga.GetMultiMangasDB(allMangas)
+	if err != nil {
+		return nil, err
+	}
+
+	mangas := []*manga.Manga{}
+	for _, multimanga := range multimangas {
+		if len(statusFilter) > 0 && !slices.Contains(statusFilter, int(multimanga.Status)) {
+			continue
+		}
+		if !allMangas {
+			multimanga.CurrentManga.Status = multimanga.Status
+			mangas = append(mangas, multimanga.CurrentManga)
+			continue
+		}
+		for _, m := range multimanga.Mangas {
+			m.Status = multimanga.Status
+			mangas = append(mangas, m)
+		}
+	}
+
+	return mangas, nil
+}
+
+// getDownloadIntegrationsOtherMangas returns the Mantium mangas that shouldn't be in the
+// download integrations, but shouldn't be removed from them either. If allMangas is false,
+// they're the multimangas' mangas that are not the current manga, like the mangas added to
+// the integrations when all multimangas' mangas were added.
+func getDownloadIntegrationsOtherMangas(allMangas bool) ([]*manga.Manga, error) {
+	if allMangas {
+		return nil, nil
+	}
+
+	multimangas, err := manga.GetMultiMangasDB(true)
+	if err != nil {
+		return nil, err
+	}
+
+	mangas := []*manga.Manga{}
+	for _, multimanga := range multimangas {
+		for _, m := range multimanga.Mangas {
+			if m.ID != multimanga.CurrentManga.ID {
+				mangas = append(mangas, m)
+			}
+		}
+	}
+
+	return mangas, nil
+}
+
+// enqueueAddMangaJobs enqueues the jobs that add the manga to all configured download integrations.
+// The jobs are run in the background and retried if they fail.
+func enqueueAddMangaJobs(m *manga.Manga, currentTime time.Time) ([]*jobs.Job, error) {
+	enqueued := []*jobs.Job{}
+	for _, integration := range downloads.GetIntegrations() {
+		job, err := jobs.Enqueue(jobs.TypeAddMangaToIntegration, integration.Name(), m.ID, config.GlobalConfigs.Jobs.MaxAttempts, currentTime)
+		if err != nil {
+			return enqueued, err
+		}
+		enqueued = append(enqueued, job)
+	}
+	if len(enqueued) > 0 {
+		jobs.Wake()
+	}
+
+	return enqueued, nil
+}
+
+// getJobIDs returns the IDs of the jobs, like the jobs enqueued by a request.
+func getJobIDs(jobsSlice []*jobs.Job) []int {
+	ids := make([]int, 0, len(jobsSlice))
+	for _, job := range jobsSlice {
+		ids = append(ids, job.ID)
+	}
+
+	return ids
+}
+
+// applyRemovalPolicies applies the removal policy of the event of all configured download integrations
+// to the mangas, usually a multimanga's mangas. The integration mangas of keepMangas are not changed.
+func applyRemovalPolicies(event string, mangas, keepMangas []*manga.Manga) []error {
+	var errors []error
+	for _, integration := range downloads.GetIntegrations() {
+		removals, err := downloads.ApplyRemovalPolicy(integration, event, mangas, keepMangas, false)
+		if err != nil {
+			errors = append(errors, err)
+			continue
+		}
+		for _, removal := range removals {
+			if removal.Error != "" {
+				errors = append(errors, fmt.Errorf("error while doing action '%s' with manga '%s' in %s: %s", removal.Action, removal.Manga.Title, integration.Name(), removal.Error))
+			}
+		}
+	}
+
+	return errors
+}
+
+// applyMultiMangasRemovalPolicies applies the removal policy of the event of all configured
+// download integrations to the multimangas' mangas. The integration mangas also of the other
+// multimangas in the library, like mangas with the same name in Kaizoku, are not changed.
+func applyMultiMangasRemovalPolicies(event string, multimangas ...*manga.MultiManga) []error {
+	if len(downloads.GetIntegrations()) == 0 {
+		return nil
+	}
+
+	keepMangas, err := getRemovalKeepMangas(multimangas)
+	if err != nil {
+		return []error{err}
+	}
+	mangas := []*manga.Manga{}
+	for _, mm := range multimangas {
+		mangas = append(mangas, mm.Mangas...)
+	}
+
+	return applyRemovalPolicies(event, mangas, keepMangas)
+}
+
+// getRemovalKeepMangas returns the mangas of the library's multimangas other than the multimangas,
+// which integration mangas must not be changed when applying the removal policies to the multimangas.
+// If multimangas is empty, all mangas in the library are returned.
+func getRemovalKeepMangas(multimangas []*manga.MultiManga) ([]*manga.Manga, error) {
+	library, err := manga.GetMultiMangasDB(true)
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[manga.ID]bool, len(multimangas))
+	for _, mm := range multimangas {
+		ids[mm.ID] = true
+	}
+
+	keepMangas := []*manga.Manga{}
+	for _, mm := range library {
+		if !ids[mm.ID] {
+			keepMangas = append(keepMangas, mm.Mangas...)
+		}
+	}
+
+	return keepMangas, nil
+}
+
+// addMangasToDownloadIntegration adds the multimangas' current manga to the download integration with the name.
+// The multimangas can be filtered by the "status" query parameter.
+func addMangasToDownloadIntegration(c *gin.Context, name string) {
+	statusFilterStr := c.Query("status")
+	var statusFilter []int
+	if statusFilterStr != "" {
+		statusStrings := strings.Split(statusFilterStr, ",")
+		for _, statusStr := range statusStrings {
+			status, err := strconv.Atoi(statusStr)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"message": "status must be a list of numbers"})
+				return
+			}
+			statusFilter = append(statusFilter, status)
+		}
+	}
+
+	integrations, err := getDownloadIntegrationsFromQuery(name)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	integration := integrations[0]
+
+	mangas, err := getDownloadIntegrationsMangas(false, statusFilter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	logger := util.GetLogger(zerolog.Level(config.GlobalConfigs.API.LogLevelInt))
+	var errorSlice []string
+	for _, m := range mangas {
+		if m.Source == manga.CustomMangaSource {
+			continue
+		}
+
+		err = integration.AddManga(m)
+		if err != nil {
+			logger.Error().Err(err).Str("manga_url", m.URL).Msgf("error adding manga to %s, will continue with the next manga...", name)
+			errorSlice = append(errorSlice, err.Error())
+		}
+	}
+
+	if len(errorSlice) > 0 {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": fmt.Sprintf("some errors occured while adding some mangas to %s, check the logs for more information. Last error: %s", name, errorSlice[len(errorSlice)-1]), "errors": errorSlice})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("Mangas added to %s successfully", name)})
+}
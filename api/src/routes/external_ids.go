@@ -0,0 +1,265 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/sources"
+)
+
+// externalIDsResolveInterval is the minimum interval between the resolutions of a multimanga
+// external IDs when some of them are missing, as the sources rarely add them.
+const externalIDsResolveInterval = 7 * 24 * time.Hour
+
+// @Summary Get multimanga external IDs
+// @Description Gets the multimanga IDs in MangaUpdates, AniList, and MyAnimeList, and the URLs of these sites' pages of the multimanga. The IDs are empty if they were never resolved or set. The MangaUpdates URL can be added to the multimanga to also track it in the MangaUpdates source.
+// @Produce json
+// @Param id query int true "Multimanga ID" Example(1)
+// @Success 200 {object} manga.ExternalIDs "{"external_ids": externalIDsObj, "urls": {"mangaupdates": "https://www.mangaupdates.com/series/pb8uwds"}}"
+// @Router /multimanga/external_ids [get]
+func GetMultiMangaExternalIDs(c *gin.Context) {
+	multimanga, ok := getMultiMangaFromQuery(c)
+	if !ok {
+		return
+	}
+
+	externalIDs, err := manga.GetExternalIDsDB(multimanga.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"external_ids": externalIDs, "urls": externalIDs.URLs()})
+}
+
+// @Summary Set multimanga external IDs
+// @Description Sets the multimanga IDs in MangaUpdates, AniList, and MyAnimeList, replacing the current IDs. An empty ID removes it. The IDs changed or removed are marked as manual and aren't filled or replaced when the IDs are resolved from the sources. The IDs in automatic_ids, like "anilist_id", stop being manual, so they're filled again if empty.
+// @Accept json
+// @Produce json
+// @Param id query int true "Multimanga ID" Example(1)
+// @Param external_ids body SetMultiMangaExternalIDsRequest true "External IDs"
+// @Success 200 {object} manga.ExternalIDs "{"message": "Multimanga external IDs set successfully", "external_ids": externalIDsObj}"
+// @Router /multimanga/external_ids [put]
+func SetMultiMangaExternalIDs(c *gin.Context) {
+	var requestData SetMultiMangaExternalIDsRequest
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "invalid JSON fields, refer to the API documentation"})
+		return
+	}
+
+	multimanga, ok := getMultiMangaFromQuery(c)
+	if !ok {
+		return
+	}
+
+	externalIDs, err := manga.GetExternalIDsDB(multimanga.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	for _, field := range []struct {
+		name  string
+		id    *string
+		value string
+	}{
+		{"mangaupdates_id", &externalIDs.MangaUpdatesID, requestData.MangaUpdatesID},
+		{"anilist_id", &externalIDs.AniListID, requestData.AniListID},
+		{"mal_id", &externalIDs.MALID, requestData.MALID},
+	} {
+		if *field.id != field.value {
+			*field.id = field.value
+			externalIDs.SetManual(field.name, true)
+		}
+	}
+	for _, name := range requestData.AutomaticIDs {
+		externalIDs.SetManual(name, false)
+	}
+	err = externalIDs.UpsertIntoDB()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Multimanga external IDs set successfully", "external_ids": externalIDs})
+}
+
+// SetMultiMangaExternalIDsRequest is the request body for the SetMultiMangaExternalIDs route
+type SetMultiMangaExternalIDsRequest struct {
+	MangaUpdatesID string `json:"mangaupdates_id" binding:"omitempty,numeric"`
+	AniListID      string `json:"anilist_id" binding:"omitempty,numeric"`
+	MALID          string `json:"mal_id" binding:"omitempty,numeric"`
+	// AutomaticIDs are the names of the IDs that stop being manual, like "anilist_id".
+	AutomaticIDs []string `json:"automatic_ids" binding:"dive,oneof=mangaupdates_id anilist_id mal_id"`
+}
+
+// @Summary Resolve multimanga external IDs
+// @Description Gets the multimanga IDs in MangaUpdates, AniList, and MyAnimeList from the sources that know them (MangaDex, MangaUpdates, and ComicK) and fills the empty IDs that aren't manual. The IDs are also resolved when updating the mangas metadata.
+// @Produce json
+// @Param id query int true "Multimanga ID" Example(1)
+// @Success 200 {object} manga.ExternalIDs "{"message": "Multimanga external IDs resolved successfully", "external_ids": externalIDsObj}"
+// @Router /multimanga/external_ids/resolve [post]
+func ResolveMultiMangaExternalIDs(c *gin.Context) {
+	multimanga, ok := getMultiMangaFromQuery(c)
+	if !ok {
+		return
+	}
+	if !hasExternalIDsSource(multimanga) {
+		c.JSON(http.StatusBadRequest, gin.H{"message": errordefs.ErrNoExternalIDsSources.Error()})
+		return
+	}
+
+	externalIDs, err := manga.GetExternalIDsDB(multimanga.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	logger := zerolog.Ctx(c.Request.Context())
+	externalIDs, errors := resolveMultiMangaExternalIDs(c.Request.Context(), multimanga, externalIDs, 1, 0, logger)
+	if len(errors) > 0 && externalIDs.ResolvedAt.IsZero() {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": errors[0]})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Multimanga external IDs resolved successfully", "external_ids": externalIDs})
+}
+
+// @Summary Get multimangas external IDs
+// @Description Gets the IDs in MangaUpdates, AniList, and MyAnimeList of all multimangas with external IDs, sorted by multimanga ID.
+// @Produce json
+// @Success 200 {array} manga.ExternalIDs "{"external_ids": [externalIDsObj]}"
+// @Router /multimangas/external_ids [get]
+func GetMultiMangasExternalIDs(c *gin.Context) {
+	externalIDsMap, err := manga.GetAllExternalIDsDB()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	externalIDs := make([]*manga.ExternalIDs, 0, len(externalIDsMap))
+	for _, ids := range externalIDsMap {
+		externalIDs = append(externalIDs, ids)
+	}
+	sort.Slice(externalIDs, func(i, j int) bool { return externalIDs[i].MultiMangaID < externalIDs[j].MultiMangaID })
+
+	c.JSON(http.StatusOK, gin.H{"external_ids": externalIDs})
+}
+
+// @Summary Get duplicated multimangas
+// @Description Gets the groups of multimangas that seem to be the same work, as they have the same MangaUpdates, AniList, or MyAnimeList ID, or the same current manga name ignoring case, spaces, and punctuation. The mangas of a group can be moved to a single multimanga.
+// @Produce json
+// @Success 200 {array} manga.DuplicateGroup "{"duplicates": [duplicateGroupObj]}"
+// @Router /multimangas/duplicates [get]
+func GetDuplicatedMultiMangas(c *gin.Context) {
+	multimangas, err := manga.GetMultiMangasDB(true)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	externalIDs, err := manga.GetAllExternalIDsDB()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	names := make(map[manga.ID]string, len(multimangas))
+	for _, multimanga := range multimangas {
+		names[multimanga.ID] = multimanga.CurrentManga.Name
+	}
+
+	c.JSON(http.StatusOK, gin.H{"duplicates": manga.FindDuplicates(names, externalIDs)})
+}
+
+// hasExternalIDsSource returns true if any of the multimanga mangas is from a source that knows its external IDs.
+func hasExternalIDsSource(multimanga *manga.MultiManga) bool {
+	for _, m := range multimanga.Mangas {
+		if sources.HasExternalIDs(m.URL) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// shouldResolveExternalIDs returns true if the multimanga external IDs were never
+// resolved, or if some of them are missing and they weren't resolved recently.
+func shouldResolveExternalIDs(externalIDs *manga.ExternalIDs, currentTime time.Time) bool {
+	if externalIDs == nil || externalIDs.ResolvedAt.IsZero() {
+		return true
+	}
+
+	return !externalIDs.IsComplete() && currentTime.Sub(externalIDs.ResolvedAt) >= externalIDsResolveInterval
+}
+
+// resolveMultiMangaExternalIDs gets the external IDs of the multimanga mangas from the sources
+// that know them, from the current manga, fills the multimanga's empty IDs that aren't manual,
+// and stores them in the database. The IDs aren't stored if they can't be got from any source,
+// and then the returned IDs' ResolvedAt is zero.
+func resolveMultiMangaExternalIDs(ctx context.Context, multimanga *manga.MultiManga, externalIDs *manga.ExternalIDs, retries int, retryInterval time.Duration, logger *zerolog.Logger) (*manga.ExternalIDs, []string) {
+	var errors []string
+	resolved := &manga.ExternalIDs{MultiMangaID: multimanga.ID}
+	if externalIDs != nil {
+		*resolved = *externalIDs
+		resolved.ResolvedAt = time.Time{}
+	}
+
+	mangas := []*manga.Manga{}
+	if multimanga.CurrentManga != nil {
+		mangas = append(mangas, multimanga.CurrentManga)
+	}
+	for _, m := range multimanga.Mangas {
+		if multimanga.CurrentManga == nil || m.ID != multimanga.CurrentManga.ID {
+			mangas = append(mangas, m)
+		}
+	}
+
+	var gotAny bool
+	for _, m := range mangas {
+		if resolved.IsComplete() {
+			break
+		}
+		if !sources.HasExternalIDs(m.URL) {
+			continue
+		}
+
+		var mangaExternalIDs *manga.ExternalIDs
+		var err error
+		for i := 0; i < retries; i++ {
+			mangaExternalIDs, err = sources.GetExternalIDs(ctx, m.URL, m.InternalID)
+			if err == nil {
+				break
+			}
+			if i != retries-1 {
+				logger.Error().Err(err).Str("manga_url", m.URL).Msgf("Error getting manga external IDs, retrying in %.2f seconds...", retryInterval.Seconds())
+				time.Sleep(retryInterval)
+			}
+		}
+		if err != nil {
+			logger.Error().Err(err).Str("manga_url", m.URL).Msg("Error getting manga external IDs, will continue with the next manga...")
+			errors = append(errors, err.Error())
+			continue
+		}
+		gotAny = true
+		resolved.Merge(mangaExternalIDs)
+	}
+	if !gotAny && !resolved.IsComplete() {
+		return resolved, errors
+	}
+
+	resolved.ResolvedAt = time.Now().Truncate(time.Second)
+	err := resolved.UpsertIntoDB()
+	if err != nil {
+		logger.Error().Err(err).Int("multimanga_id", int(multimanga.ID)).Msg("Error saving multimanga external IDs to DB")
+		errors = append(errors, err.Error())
+		resolved.ResolvedAt = time.Time{}
+	}
+
+	return resolved, errors
+}
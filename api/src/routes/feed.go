@@ -0,0 +1,195 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/diogovalentte/mantium/api/src/feed"
+	"github.com/diogovalentte/mantium/api/src/iframe"
+	"github.com/diogovalentte/mantium/api/src/manga"
+)
+
+// FeedRoutes sets the feeds and widget routes
+func FeedRoutes(group *gin.RouterGroup) {
+	{
+		group.GET("/widget", GetWidget)
+		group.GET("/feed/rss", GetRSSFeed)
+		group.GET("/feed/atom", GetAtomFeed)
+		group.GET("/feed/json", GetJSONFeed)
+	}
+}
+
+// @Summary Dashboard widget
+// @Description Returns a summary of the library for dashboard widgets, like Homepage's customapi widget: the number of unread mangas and chapters, the latest released chapters, and the number of mangas with unread chapters per status (backlog). The tags and source filters apply to everything, the status filter doesn't apply to the backlog.
+// @Produce json
+// @Param status query string false "Comma separated list of statuses of the mangas (1: reading, 2: completed, 3: on hold, 4: dropped, 5: plan to read). 0 means all statuses. Defaults to 1,2." Example(1,2)
+// @Param tags query string false "Comma separated list of tags. Only mangas with any of the tags are used." Example(weekly)
+// @Param source query string false "Comma separated list of sources. Only mangas from the sources are used." Example(mangadex)
+// @Param limit query int false "Number of latest releases. Defaults to 5." Example(5)
+// @Param unread query bool false "If true, the latest releases have only mangas with unread chapters. Defaults to false." Example(true)
+// @Success 200 {object} feed.Widget
+// @Router /widget [get]
+func GetWidget(c *gin.Context) {
+	options := &iframe.Options{
+		Statuses: []manga.Status{1, 2},
+		Sort:     iframe.SortReleased,
+		Limit:    5,
+	}
+	err := iframe.ParseFilterOptions(c.Request.URL.Query(), options)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	allMangas, err := getLibraryMangas()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	backlogMangas := iframe.FilterMangas(allMangas, &iframe.Options{
+		Tags:    options.Tags,
+		Sources: options.Sources,
+		Sort:    iframe.SortReleased,
+		Limit:   -1,
+	})
+	mangas := iframe.FilterMangas(backlogMangas, &iframe.Options{
+		Statuses: options.Statuses,
+		Sort:     iframe.SortReleased,
+		Limit:    -1,
+	})
+	latestMangas := iframe.FilterMangas(mangas, options)
+
+	c.JSON(http.StatusOK, feed.NewWidget(mangas, backlogMangas, latestMangas))
+}
+
+// @Summary RSS feed
+// @Description Returns an RSS 2.0 feed with the last released chapter of the mangas, newest first.
+// @Produce xml
+// @Param status query string false "Comma separated list of statuses of the mangas (1: reading, 2: completed, 3: on hold, 4: dropped, 5: plan to read). Defaults to all statuses." Example(1,2)
+// @Param tags query string false "Comma separated list of tags. Only mangas with any of the tags are used." Example(weekly)
+// @Param source query string false "Comma separated list of sources. Only mangas from the sources are used." Example(mangadex)
+// @Param limit query int false "Max number of items. Defaults to 50." Example(50)
+// @Param unread query bool false "If true, only mangas with unread chapters are used. Defaults to false." Example(true)
+// @Param api_url query string false "API URL used in the feed links. Defaults to the request URL." Example(https://sub.domain.com)
+// @Success 200 {string} string "RSS feed"
+// @Router /feed/rss [get]
+func GetRSSFeed(c *gin.Context) {
+	f, statusCode, err := getFeed(c)
+	if err != nil {
+		c.JSON(statusCode, gin.H{"message": err.Error()})
+		return
+	}
+
+	content, err := f.RSS()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.Data(http.StatusOK, feed.RSSContentType, content)
+}
+
+// @Summary Atom feed
+// @Description Returns an Atom 1.0 feed with the last released chapter of the mangas, newest first.
+// @Produce xml
+// @Param status query string false "Comma separated list of statuses of the mangas (1: reading, 2: completed, 3: on hold, 4: dropped, 5: plan to read). Defaults to all statuses." Example(1,2)
+// @Param tags query string false "Comma separated list of tags. Only mangas with any of the tags are used." Example(weekly)
+// @Param source query string false "Comma separated list of sources. Only mangas from the sources are used." Example(mangadex)
+// @Param limit query int false "Max number of items. Defaults to 50." Example(50)
+// @Param unread query bool false "If true, only mangas with unread chapters are used. Defaults to false." Example(true)
+// @Param api_url query string false "API URL used in the feed links. Defaults to the request URL." Example(https://sub.domain.com)
+// @Success 200 {string} string "Atom feed"
+// @Router /feed/atom [get]
+func GetAtomFeed(c *gin.Context) {
+	f, statusCode, err := getFeed(c)
+	if err != nil {
+		c.JSON(statusCode, gin.H{"message": err.Error()})
+		return
+	}
+
+	content, err := f.Atom()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.Data(http.StatusOK, feed.AtomContentType, content)
+}
+
+// @Summary JSON feed
+// @Description Returns a JSON Feed 1.1 with the last released chapter of the mangas, newest first.
+// @Produce json
+// @Param status query string false "Comma separated list of statuses of the mangas (1: reading, 2: completed, 3: on hold, 4: dropped, 5: plan to read). Defaults to all statuses." Example(1,2)
+// @Param tags query string false "Comma separated list of tags. Only mangas with any of the tags are used." Example(weekly)
+// @Param source query string false "Comma separated list of sources. Only mangas from the sources are used." Example(mangadex)
+// @Param limit query int false "Max number of items. Defaults to 50." Example(50)
+// @Param unread query bool false "If true, only mangas with unread chapters are used. Defaults to false." Example(true)
+// @Param api_url query string false "API URL used in the feed links. Defaults to the request URL." Example(https://sub.domain.com)
+// @Success 200 {string} string "JSON feed"
+// @Router /feed/json [get]
+func GetJSONFeed(c *gin.Context) {
+	f, statusCode, err := getFeed(c)
+	if err != nil {
+		c.JSON(statusCode, gin.H{"message": err.Error()})
+		return
+	}
+
+	content, err := f.JSON()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.Data(http.StatusOK, feed.JSONContentType, content)
+}
+
+// getFeed returns the feed of the mangas filtered by the request query.
+// If an error occurs, it also returns the response status code.
+func getFeed(c *gin.Context) (*feed.Feed, int, error) {
+	options := &iframe.Options{
+		Statuses: []manga.Status{},
+		Sort:     iframe.SortReleased,
+		Limit:    50,
+	}
+	query := c.Request.URL.Query()
+	err := iframe.ParseFilterOptions(query, options)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	baseURL := getRequestBaseURL(c)
+	if apiURL := query.Get("api_url"); apiURL != "" {
+		_, err = url.ParseRequestURI(apiURL)
+		if err != nil {
+			return nil, http.StatusBadRequest, fmt.Errorf("api_url must be a valid URL like 'http://192.168.1.46:8080' or 'https://sub.domain.com'")
+		}
+		baseURL = strings.TrimSuffix(apiURL, "/")
+	}
+
+	allMangas, err := getLibraryMangas()
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	mangas := iframe.FilterMangas(allMangas, options)
+
+	return feed.New("Mantium - New chapters", baseURL, baseURL+c.Request.URL.RequestURI(), mangas), http.StatusOK, nil
+}
+
+// getRequestBaseURL returns the scheme and host used by the client to request the API,
+// considering the X-Forwarded-Proto header set by reverse proxies.
+func getRequestBaseURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+
+	return scheme + "://" + c.Request.Host
+}
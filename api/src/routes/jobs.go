@@ -0,0 +1,140 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/jobs"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// JobsRoutes sets the background jobs routes
+func JobsRoutes(group *gin.RouterGroup) {
+	{
+		group.GET("/jobs", GetJobs)
+		group.POST("/jobs/retry", RetryFailedJobs)
+		group.GET("/job", GetJob)
+		group.POST("/job/retry", RetryJob)
+	}
+}
+
+// @Summary Get jobs
+// @Description Gets the background jobs, the newest first, like the jobs that add the mangas to the download integrations. Succeeded jobs are deleted after 7 days.
+// @Produce json
+// @Param status query string false "Filter by status: pending, running, succeeded, or failed" Example(failed)
+// @Param manga_id query int false "Filter by manga ID" Example(1)
+// @Success 200 {array} jobs.Job "{"jobs": [jobObj]}"
+// @Router /jobs [get]
+func GetJobs(c *gin.Context) {
+	status := c.Query("status")
+	if status != "" && !slices.Contains([]string{jobs.StatusPending, jobs.StatusRunning, jobs.StatusSucceeded, jobs.StatusFailed}, status) {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "status must be pending, running, succeeded, or failed"})
+		return
+	}
+	var mangaID manga.ID
+	if mangaIDStr := c.Query("manga_id"); mangaIDStr != "" {
+		mangaIDInt, err := strconv.Atoi(mangaIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "manga_id must be a number"})
+			return
+		}
+		mangaID = manga.ID(mangaIDInt)
+	}
+
+	jobsSlice, err := jobs.GetJobs(status, mangaID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"jobs": jobsSlice})
+}
+
+// @Summary Get job
+// @Description Gets a background job, like to check if a manga was added to a download integration.
+// @Produce json
+// @Param id query int true "Job ID" Example(1)
+// @Success 200 {object} jobs.Job "{"job": jobObj}"
+// @Router /job [get]
+func GetJob(c *gin.Context) {
+	jobID, ok := getJobID(c)
+	if !ok {
+		return
+	}
+
+	job, err := jobs.GetJob(jobID)
+	if err != nil {
+		if util.ErrorContains(err, errordefs.ErrJobNotFound.Error()) {
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"job": job})
+}
+
+// @Summary Retry job
+// @Description Runs a failed background job again now, with all its attempts again.
+// @Produce json
+// @Param id query int true "Job ID" Example(1)
+// @Success 200 {object} jobs.Job "{"message": "Job scheduled to run again", "job": jobObj}"
+// @Router /job/retry [post]
+func RetryJob(c *gin.Context) {
+	jobID, ok := getJobID(c)
+	if !ok {
+		return
+	}
+
+	job, err := jobs.Retry(jobID, time.Now())
+	if err != nil {
+		switch {
+		case util.ErrorContains(err, errordefs.ErrJobNotFound.Error()):
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		case util.ErrorContains(err, errordefs.ErrJobNotRetryable.Error()):
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		}
+		return
+	}
+	jobs.Wake()
+
+	c.JSON(http.StatusOK, gin.H{"message": "Job scheduled to run again", "job": job})
+}
+
+// @Summary Retry failed jobs
+// @Description Runs all failed background jobs again now, with all their attempts again.
+// @Produce json
+// @Success 200 {object} responseMessage
+// @Router /jobs/retry [post]
+func RetryFailedJobs(c *gin.Context) {
+	count, err := jobs.RetryFailed(time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	if count > 0 {
+		jobs.Wake()
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("%d failed jobs scheduled to run again", count)})
+}
+
+func getJobID(c *gin.Context) (int, bool) {
+	jobID, err := strconv.Atoi(c.Query("id"))
+	if err != nil || jobID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "id must be a positive number"})
+		return 0, false
+	}
+
+	return jobID, true
+}
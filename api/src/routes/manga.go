@@ -4,9 +4,7 @@ package routes
 import (
 	"bytes"
 	"context"
-	"encoding/base64"
 	"fmt"
-	"html/template"
 	"io"
 	"mime/multipart"
 	"net/http"
@@ -22,16 +20,19 @@ import (
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 
+	"github.com/diogovalentte/mantium/api/src/actions"
 	"github.com/diogovalentte/mantium/api/src/config"
 	"github.com/diogovalentte/mantium/api/src/dashboard"
 	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/iframe"
+	"github.com/diogovalentte/mantium/api/src/integrations/downloads"
 	"github.com/diogovalentte/mantium/api/src/integrations/kaizoku"
 	"github.com/diogovalentte/mantium/api/src/integrations/ntfy"
-	"github.com/diogovalentte/mantium/api/src/integrations/suwayomi"
-	"github.com/diogovalentte/mantium/api/src/integrations/tranga"
+	"github.com/diogovalentte/mantium/api/src/jobs"
 	"github.com/diogovalentte/mantium/api/src/manga"
 	"github.com/diogovalentte/mantium/api/src/sources"
 	"github.com/diogovalentte/mantium/api/src/sources/models"
+	"github.com/diogovalentte/mantium/api/src/telemetry"
 	"github.com/diogovalentte/mantium/api/src/util"
 )
 
@@ -49,9 +50,19 @@ func MangaRoutes(group *gin.RouterGroup) {
 		group.PATCH("/manga/last_read_chapter", UpdateMangaLastReadChapter)
 		group.PATCH("/manga/cover_img", UpdateMangaCoverImg)
 		group.POST("/manga/turn_into_multimanga", TurnIntoMultiManga)
+		group.GET("/manga/chapter_preferences", GetMangaChapterPreferences)
+		group.PUT("/manga/chapter_preferences", SetMangaChapterPreferences)
 
 		group.POST("/custom_manga", AddCustomManga)
 		group.PATCH("/custom_manga/has_more_chapters", UpdateCustomMangaMoreChapters)
+		group.PATCH("/custom_manga/tags", UpdateCustomMangaTags)
+		group.GET("/custom_manga/chapters", GetCustomMangaChapters)
+		group.POST("/custom_manga/chapters", AddCustomMangaChapters)
+		group.DELETE("/custom_manga/chapter", DeleteCustomMangaChapter)
+		group.GET("/custom_manga/watcher", GetCustomMangaWatcher)
+		group.PUT("/custom_manga/watcher", SetCustomMangaWatcher)
+		group.DELETE("/custom_manga/watcher", DeleteCustomMangaWatcher)
+		group.POST("/custom_manga/watcher/check", CheckCustomMangaWatcher)
 
 		group.POST("/multimanga", AddMultiManga)
 		group.DELETE("/multimanga", DeleteMultiManga)
@@ -60,28 +71,42 @@ func MangaRoutes(group *gin.RouterGroup) {
 		group.GET("/multimanga/chapters", GetMultiMangaChapters)
 		group.PATCH("/multimanga/status", UpdateMultiMangaStatus)
 		group.PATCH("/multimanga/last_read_chapter", UpdateMultiMangaLastReadChapter)
+		group.GET("/multimanga/read_chapters", GetMultiMangaReadChapters)
+		group.PATCH("/multimanga/read_chapters", MarkMultiMangaChapters)
+		group.PATCH("/multimanga/read_chapters/range", MarkMultiMangaChapterRange)
+		group.PATCH("/multimanga/read_chapters/up_to", MarkMultiMangaChaptersReadUpTo)
+		group.POST("/multimanga/reread", StartMultiMangaReread)
 		group.PATCH("/multimanga/cover_img", UpdateMultiMangaCoverImg)
+		group.PATCH("/multimanga/tags", UpdateMultiMangaTags)
 		group.POST("/multimanga/manga", AddMangaToMultiManga)
 		group.DELETE("/multimanga/manga", RemoveMangaFromMultiManga)
+		group.POST("/multimanga/relation", AddRelatedMultiManga)
+		group.GET("/multimanga/external_ids", GetMultiMangaExternalIDs)
+		group.PUT("/multimanga/external_ids", SetMultiMangaExternalIDs)
+		group.POST("/multimanga/external_ids/resolve", ResolveMultiMangaExternalIDs)
 
 		group.POST("/mangas/search", SearchManga)
 		group.GET("/mangas", GetMangas)
 		group.GET("/multimangas", GetMultiMangas)
+		group.POST("/multimangas/bulk", BulkMultiMangas)
+		group.GET("/multimangas/external_ids", GetMultiMangasExternalIDs)
+		group.GET("/multimangas/duplicates", GetDuplicatedMultiMangas)
 		group.GET("/mangas/iframe", GetMangasiFrame)
 		group.PATCH("/mangas/metadata", UpdateMangasMetadata)
 		group.POST("/mangas/add_to_kaizoku", AddMangasToKaizoku)
 		group.POST("/mangas/add_to_tranga", AddMangasToTranga)
 		group.POST("/mangas/add_to_suwayomi", AddMangasToSuwayomi)
+		group.POST("/mangas/sync_read_progress", SyncMangasReadProgress)
 		group.GET("/mangas/stats", GetLibraryStats)
 	}
 }
 
 // @Summary Add manga
-// @Description Gets a manga metadata from source and inserts into the database.
+// @Description Gets a manga metadata from source and inserts into the database. The manga is added to the download integrations by background jobs, whose IDs are returned; check them in the jobs routes.
 // @Accept json
 // @Produce json
 // @Param manga body AddMangaRequest true "Manga data"
-// @Success 200 {object} responseMessage
+// @Success 200 {object} responseMessageWithJobs
 // @Router /manga [post]
 func AddManga(c *gin.Context) {
 	currentTime := time.Now()
@@ -92,7 +117,7 @@ func AddManga(c *gin.Context) {
 		return
 	}
 
-	mangaAdd, err := sources.GetMangaMetadata(requestData.URL, requestData.MangaInternalID)
+	mangaAdd, err := sources.GetMangaMetadata(c.Request.Context(), requestData.URL, requestData.MangaInternalID, nil)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
@@ -105,7 +130,7 @@ func AddManga(c *gin.Context) {
 	mangaAdd.Status = manga.Status(requestData.Status)
 
 	if requestData.LastReadChapter != "" || requestData.LastReadChapterURL != "" {
-		mangaAdd.LastReadChapter, err = sources.GetChapterMetadata(requestData.URL, requestData.MangaInternalID, requestData.LastReadChapter, requestData.LastReadChapterURL, requestData.LastReadChapterInternalID)
+		mangaAdd.LastReadChapter, err = sources.GetChapterMetadata(c.Request.Context(), requestData.URL, requestData.MangaInternalID, requestData.LastReadChapter, requestData.LastReadChapterURL, requestData.LastReadChapterInternalID, nil)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 			return
@@ -133,49 +158,15 @@ func AddManga(c *gin.Context) {
 		return
 	}
 
-	var integrationsErrors []error
-	if config.GlobalConfigs.Kaizoku.Valid {
-		kaizoku := kaizoku.Kaizoku{}
-		kaizoku.Init()
-		err = kaizoku.AddManga(mangaAdd, config.GlobalConfigs.Kaizoku.TryOtherSources)
-		if err != nil {
-			integrationsErrors = append(integrationsErrors, util.AddErrorContext("manga added to DB, but error while adding it to Kaizoku", err))
-		}
-	}
-
-	if config.GlobalConfigs.Tranga.Valid {
-		tranga := tranga.Tranga{}
-		tranga.Init()
-		err = tranga.AddManga(mangaAdd)
-		if err != nil {
-			integrationsErrors = append(integrationsErrors, util.AddErrorContext("manga added to DB, but error while adding it to Tranga", err))
-		}
-	}
-
-	if config.GlobalConfigs.Suwayomi.Valid {
-		suwayomi := suwayomi.Suwayomi{}
-		suwayomi.Init()
-
-		err = suwayomi.AddManga(mangaAdd, config.GlobalConfigs.DashboardConfigs.Integrations.EnqueueAllSuwayomiChaptersToDownload)
-		if err != nil {
-			integrationsErrors = append(integrationsErrors, util.AddErrorContext("manga added to DB, but error while adding it to Suwayomi", err))
-		}
-	}
+	dashboard.UpdateDashboard()
 
-	if len(integrationsErrors) > 0 {
-		fullMsg := "manga added to DB, but error executing integrations: "
-		for _, err := range integrationsErrors {
-			zerolog.Ctx(c.Request.Context()).Error().Err(err).Msg("error while adding manga to at least one integration")
-			fullMsg += err.Error() + " "
-		}
-		dashboard.UpdateDashboard()
-		c.JSON(http.StatusInternalServerError, gin.H{"message": fullMsg})
+	integrationsJobs, err := enqueueAddMangaJobs(mangaAdd, currentTime)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": fmt.Sprintf("manga added to DB, but error enqueueing the download integrations jobs: %s", err), "jobs": getJobIDs(integrationsJobs)})
 		return
 	}
 
-	dashboard.UpdateDashboard()
-
-	c.JSON(http.StatusOK, gin.H{"message": "Manga added successfully"})
+	c.JSON(http.StatusOK, gin.H{"message": "Manga added successfully", "jobs": getJobIDs(integrationsJobs)})
 }
 
 // AddMangaRequest is the request body for the AddManga route
@@ -275,7 +266,13 @@ func GetManga(c *gin.Context) {
 // @Router /manga/metadata [get]
 func GetMangaMetadata(c *gin.Context) {
 	mangaURL := c.Query("url")
-	mangaGet, err := sources.GetMangaMetadata(mangaURL, "")
+	preferences, err := getMangaChapterPreferences(0, mangaURL)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	mangaGet, err := sources.GetMangaMetadata(c.Request.Context(), mangaURL, "", preferences)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
@@ -316,7 +313,13 @@ func GetMangaChapters(c *gin.Context) {
 		mangaURL = mangaGet.URL
 	}
 
-	chapters, err := sources.GetMangaChapters(mangaURL, mangaInternalID)
+	preferences, err := getMangaChapterPreferences(mangaID, mangaURL)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	chapters, err := sources.GetMangaChapters(c.Request.Context(), mangaURL, mangaInternalID, preferences)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
@@ -504,12 +507,20 @@ func UpdateMangaLastReadChapter(c *gin.Context) {
 		if requestData.Chapter == "" && requestData.ChapterURL == "" {
 			chapter = mangaUpdate.LastReleasedChapter
 		} else {
-			chapter, err = sources.GetChapterMetadata(mangaUpdate.URL, mangaInternalID, requestData.Chapter, requestData.ChapterURL, requestData.ChapterInternalID)
+			var preferences *manga.ChapterPreferences
+			preferences, err = manga.GetChapterPreferencesDB(mangaUpdate.ID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+				return
+			}
+			chapter, err = sources.GetChapterMetadata(c.Request.Context(), mangaUpdate.URL, mangaInternalID, requestData.Chapter, requestData.ChapterURL, requestData.ChapterInternalID, preferences)
 			if err != nil {
 				c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 				return
 			}
 		}
+		chapterReleasedAt := chapter.UpdatedAt
+		previousChapter := mangaUpdate.LastReadChapter
 		chapter.Type = 2
 		chapter.UpdatedAt = currentTime.Truncate(time.Second)
 
@@ -518,6 +529,8 @@ func UpdateMangaLastReadChapter(c *gin.Context) {
 			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 			return
 		}
+
+		recordReadingActivity(c.Request.Context(), mangaUpdate.MultiMangaID, mangaUpdate, chapter, previousChapter, chapterReleasedAt)
 	} else {
 		if requestData.Chapter == "" {
 			if requestData.ChapterURL == "" {
@@ -541,11 +554,14 @@ func UpdateMangaLastReadChapter(c *gin.Context) {
 			if chapter.URL == "" {
 				chapter.URL = manga.CustomMangaURLPrefix + "/" + uuid.New().String()
 			}
+			previousChapter := mangaUpdate.LastReadChapter
 			err = manga.UpdateCustomMangaLastReadChapterInDB(mangaUpdate, chapter)
 			if err != nil {
 				c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 				return
 			}
+
+			recordReadingActivity(c.Request.Context(), 0, mangaUpdate, chapter, previousChapter, time.Time{})
 		}
 	}
 
@@ -687,7 +703,7 @@ func UpdateMangaCoverImg(c *gin.Context) {
 
 		var updatedManga *manga.Manga
 		for i := 0; i < retries; i++ {
-			updatedManga, err = sources.GetMangaMetadata(mangaToUpdate.URL, mangaInternalID)
+			updatedManga, err = sources.GetMangaMetadata(c.Request.Context(), mangaToUpdate.URL, mangaInternalID, nil)
 			if err != nil {
 				if i == retries-1 {
 					c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
@@ -884,7 +900,7 @@ type AddCustomMangaRequest struct {
 }
 
 // @Summary Update custom manga no more chapters
-// @Description Update if a custom manga has more chapters or not.
+// @Description Update if a custom manga has more chapters or not. It can't be used with custom mangas with a chapter list, as they have more chapters when the last chapter of the list comes after their last read chapter.
 // @Accept json
 // @Produce json
 // @Param id query int false "Manga ID" Example(1)
@@ -924,6 +940,16 @@ func UpdateCustomMangaMoreChapters(c *gin.Context) {
 		return
 	}
 
+	chapters, err := mangaToUpdate.GetCustomChaptersFromDB()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	if len(chapters) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "the custom manga has a chapter list, add or delete chapters from it instead"})
+		return
+	}
+
 	if hasMoreChapters == "true" {
 		err = mangaToUpdate.DeleteLastReleasedChapterFromDB()
 	} else {
@@ -946,11 +972,11 @@ func UpdateCustomMangaMoreChapters(c *gin.Context) {
 }
 
 // @Summary Add multimanga
-// @Description Gets a manga metadata from source and inserts it as the current manga of a new multimanga into the database.
+// @Description Gets a manga metadata from source and inserts it as the current manga of a new multimanga into the database. The manga is added to the download integrations by background jobs, whose IDs are returned; check them in the jobs routes.
 // @Accept json
 // @Produce json
 // @Param manga body AddMangaRequest true "Current manga data"
-// @Success 200 {object} responseMessage
+// @Success 200 {object} responseMessageWithJobs
 // @Router /multimanga [post]
 func AddMultiManga(c *gin.Context) {
 	currentTime := time.Now()
@@ -961,23 +987,33 @@ func AddMultiManga(c *gin.Context) {
 		return
 	}
 
-	currentManga, err := sources.GetMangaMetadata(requestData.URL, requestData.MangaInternalID)
+	integrationsJobs, statusCode, err := addMultiManga(c.Request.Context(), &requestData, currentTime)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		c.JSON(statusCode, gin.H{"message": err.Error(), "jobs": getJobIDs(integrationsJobs)})
 		return
 	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Multimanga added successfully", "jobs": getJobIDs(integrationsJobs)})
+}
+
+// addMultiManga gets a manga metadata from source and inserts it as the current manga
+// of a new multimanga into the database, then enqueues the download integrations jobs.
+// If it fails, it returns the HTTP status code of the error.
+func addMultiManga(ctx context.Context, requestData *AddMangaRequest, currentTime time.Time) ([]*jobs.Job, int, error) {
+	currentManga, err := sources.GetMangaMetadata(ctx, requestData.URL, requestData.MangaInternalID, nil)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
 	if !slices.Contains(config.GlobalConfigs.DashboardConfigs.Manga.AllowedSources, currentManga.Source) {
-		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("source %s is not allowed", currentManga.Source)})
-		return
+		return nil, http.StatusBadRequest, fmt.Errorf("source %s is not allowed", currentManga.Source)
 	}
 
 	currentManga.Status = manga.Status(requestData.Status)
 
 	if requestData.LastReadChapter != "" || requestData.LastReadChapterURL != "" {
-		currentManga.LastReadChapter, err = sources.GetChapterMetadata(requestData.URL, requestData.MangaInternalID, requestData.LastReadChapter, requestData.LastReadChapterURL, requestData.LastReadChapterInternalID)
+		currentManga.LastReadChapter, err = sources.GetChapterMetadata(ctx, requestData.URL, requestData.MangaInternalID, requestData.LastReadChapter, requestData.LastReadChapterURL, requestData.LastReadChapterInternalID, nil)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
-			return
+			return nil, http.StatusInternalServerError, err
 		}
 		currentManga.LastReadChapter.Type = 2
 		currentManga.LastReadChapter.UpdatedAt = currentTime.Truncate(time.Second)
@@ -986,8 +1022,7 @@ func AddMultiManga(c *gin.Context) {
 	if len(currentManga.CoverImg) == 0 {
 		currentManga.CoverImg, err = util.GetDefaultCoverImg()
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
-			return
+			return nil, http.StatusInternalServerError, err
 		}
 		currentManga.CoverImgURL = models.DefaultCoverImgURL
 		currentManga.CoverImgResized = true
@@ -1006,57 +1041,21 @@ func AddMultiManga(c *gin.Context) {
 
 	err = multiManga.InsertIntoDB()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
-		return
-	}
-
-	var integrationsErrors []error
-	if config.GlobalConfigs.Kaizoku.Valid {
-		kaizoku := kaizoku.Kaizoku{}
-		kaizoku.Init()
-		err = kaizoku.AddManga(currentManga, config.GlobalConfigs.Kaizoku.TryOtherSources)
-		if err != nil {
-			integrationsErrors = append(integrationsErrors, util.AddErrorContext("multimanga added to DB, but error while adding current manga to Kaizoku", err))
-		}
+		return nil, http.StatusInternalServerError, err
 	}
 
-	if config.GlobalConfigs.Tranga.Valid {
-		tranga := tranga.Tranga{}
-		tranga.Init()
-		err = tranga.AddManga(currentManga)
-		if err != nil {
-			integrationsErrors = append(integrationsErrors, util.AddErrorContext("multimanga added to DB, but error while adding current manga to Tranga", err))
-		}
-	}
-
-	if config.GlobalConfigs.Suwayomi.Valid {
-		suwayomi := suwayomi.Suwayomi{}
-		suwayomi.Init()
-
-		err = suwayomi.AddManga(currentManga, config.GlobalConfigs.DashboardConfigs.Integrations.EnqueueAllSuwayomiChaptersToDownload)
-		if err != nil {
-			integrationsErrors = append(integrationsErrors, util.AddErrorContext("multimanga added to DB, but error while adding current manga to Suwayomi", err))
-		}
-	}
+	dashboard.UpdateDashboard()
 
-	if len(integrationsErrors) > 0 {
-		fullMsg := "multimang added to DB, but error executing integrations: "
-		for _, err := range integrationsErrors {
-			zerolog.Ctx(c.Request.Context()).Error().Err(err).Msg("error while adding multimanga's current manga to at least one integration")
-			fullMsg += err.Error() + " "
-		}
-		dashboard.UpdateDashboard()
-		c.JSON(http.StatusInternalServerError, gin.H{"message": fullMsg})
-		return
+	integrationsJobs, err := enqueueAddMangaJobs(currentManga, currentTime)
+	if err != nil {
+		return integrationsJobs, http.StatusInternalServerError, fmt.Errorf("multimanga added to DB, but error enqueueing the download integrations jobs: %s", err)
 	}
 
-	dashboard.UpdateDashboard()
-
-	c.JSON(http.StatusOK, gin.H{"message": "Multimanga added successfully"})
+	return integrationsJobs, http.StatusOK, nil
 }
 
 // @Summary Delete multimanga
-// @Description Deletes a multimanga from the database.
+// @Description Deletes a multimanga from the database. The "delete" removal policy of the download integrations is applied to its mangas.
 // @Produce json
 // @Param id query int true "Multimanga ID" Example(1)
 // @Success 200 {object} responseMessage
@@ -1091,14 +1090,25 @@ func DeleteMultiManga(c *gin.Context) {
 
 	dashboard.UpdateDashboard()
 
+	integrationsErrors := applyMultiMangasRemovalPolicies(config.RemovalEventDelete, multimangaDelete)
+	if len(integrationsErrors) > 0 {
+		fullMsg := "multimanga deleted from DB, but error executing integrations: "
+		for _, err := range integrationsErrors {
+			zerolog.Ctx(c.Request.Context()).Error().Err(err).Msg("error while applying the removal policy of at least one integration")
+			fullMsg += err.Error() + " "
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": fullMsg})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "Multimanga deleted successfully"})
 }
 
 // @Summary Get multimanga
-// @Description Gets a multimanga from the database.
+// @Description Gets a multimanga from the database, with the titles related to its mangas, like sequels and spin-offs, and its IDs in MangaUpdates, AniList, and MyAnimeList. The relations are fetched from the sources that list them (MangaDex and MangaUpdates) when updating the mangas metadata, at most once a day.
 // @Produce json
 // @Param id query int true "Multimanga ID" Example(1)
-// @Success 200 {object} manga.MultiManga "{"multimanga": multimangaObj}"
+// @Success 200 {object} manga.MultiManga "{"multimanga": multimangaObj, "relations": [relationObj], "external_ids": externalIDsObj}"
 // @Router /multimanga [get]
 func GetMultiManga(c *gin.Context) {
 	multimangaIDStr := c.Query("id")
@@ -1112,7 +1122,7 @@ func GetMultiManga(c *gin.Context) {
 		return
 	}
 
-	multimangaGet, err := manga.GetMultiMangaFromDB(manga.ID(multimangaID))
+	multimangaGet, err := manga.GetMultiMangaFromDBContext(c.Request.Context(), manga.ID(multimangaID))
 	if err != nil {
 		if strings.Contains(err.Error(), errordefs.ErrMangaNotFoundDB.Error()) {
 			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
@@ -1122,8 +1132,19 @@ func GetMultiManga(c *gin.Context) {
 		return
 	}
 
-	resMap := map[string]manga.MultiManga{"multimanga": *multimangaGet}
-	c.JSON(http.StatusOK, resMap)
+	relations, err := manga.GetMultiMangaRelationsDB(multimangaGet.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	externalIDs, err := manga.GetExternalIDsDB(multimangaGet.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"multimanga": *multimangaGet, "relations": relations, "external_ids": externalIDs})
 }
 
 // @Summary Choose current manga
@@ -1231,7 +1252,13 @@ func GetMultiMangaChapters(c *gin.Context) {
 		return
 	}
 
-	chapters, err := sources.GetMangaChapters(multimanga.CurrentManga.URL, multimanga.CurrentManga.InternalID)
+	preferences, err := manga.GetChapterPreferencesDB(multimanga.CurrentManga.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	chapters, err := sources.GetMangaChapters(c.Request.Context(), multimanga.CurrentManga.URL, multimanga.CurrentManga.InternalID, preferences)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
@@ -1242,7 +1269,7 @@ func GetMultiMangaChapters(c *gin.Context) {
 }
 
 // @Summary Update multimanga status
-// @Description Updates a multimanga status in the database.
+// @Description Updates a multimanga status in the database. If the status is set to completed or dropped, the "complete" or "drop" removal policy of the download integrations is applied to its mangas.
 // @Produce json
 // @Param id query int true "Multimanga ID" Example(1)
 // @Param status body UpdateMangaStatusRequest true "Multimanga status"
@@ -1276,6 +1303,7 @@ func UpdateMultiMangaStatus(c *gin.Context) {
 		return
 	}
 
+	previousStatus := multimanga.Status
 	err = multimanga.UpdateStatusInDB(requestData.Status)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
@@ -1284,6 +1312,19 @@ func UpdateMultiMangaStatus(c *gin.Context) {
 
 	dashboard.UpdateDashboard()
 
+	if event := downloads.StatusRemovalEvent(requestData.Status); event != "" && requestData.Status != previousStatus {
+		integrationsErrors := applyMultiMangasRemovalPolicies(event, multimanga)
+		if len(integrationsErrors) > 0 {
+			fullMsg := "multimanga status updated in DB, but error executing integrations: "
+			for _, err := range integrationsErrors {
+				zerolog.Ctx(c.Request.Context()).Error().Err(err).Msg("error while applying the removal policy of at least one integration")
+				fullMsg += err.Error() + " "
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"message": fullMsg})
+			return
+		}
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "Multimanga status updated successfully"})
 }
 
@@ -1293,10 +1334,11 @@ type UpdateMangaStatusRequest struct {
 }
 
 // @Summary Update multimanga last read chapter
-// @Description Updates a multimanga last read chapter in the database. It also needs to know from which manga the chapter is from. If both `chapter` and `chapter_url` are empty strings in the body, set the last read chapter to the last released chapter in the database.
+// @Description Updates a multimanga last read chapter in the database. It also needs to know from which manga the chapter is from. If both `chapter` and `chapter_url` are empty strings in the body, set the last read chapter to the last released chapter in the database. The chapters up to the chapter are marked as read. The chapters after it keep their read state, unless `unmark_after` is true.
 // @Produce json
 // @Param id query int true "Multimanga ID" Example(1)
 // @Param manga_id query int true "Manga ID" Example(1)
+// @Param unmark_after query bool false "Mark the chapters after the chapter as unread, so it becomes the last read chapter even if a later chapter was read" Example(false)
 // @Param chapter body UpdateMangaChapterRequest true "Chapter"
 // @Success 200 {object} responseMessage
 // @Router /multimanga/last_read_chapter [patch]
@@ -1347,6 +1389,12 @@ func UpdateMultiMangaLastReadChapter(c *gin.Context) {
 		return
 	}
 
+	unmarkAfter, err := strconv.ParseBool(c.DefaultQuery("unmark_after", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "unmark_after must be a boolean"})
+		return
+	}
+
 	var requestData UpdateMangaChapterRequest
 	if err := c.ShouldBindJSON(&requestData); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"message": "invalid JSON fields, refer to the API documentation"})
@@ -1357,16 +1405,19 @@ func UpdateMultiMangaLastReadChapter(c *gin.Context) {
 	if requestData.Chapter == "" && requestData.ChapterURL == "" {
 		chapter = mangaGetChapterFrom.LastReleasedChapter
 	} else {
-		chapter, err = sources.GetChapterMetadata(mangaGetChapterFrom.URL, mangaGetChapterFrom.InternalID, requestData.Chapter, requestData.ChapterURL, requestData.ChapterInternalID)
+		var preferences *manga.ChapterPreferences
+		preferences, err = manga.GetChapterPreferencesDB(mangaGetChapterFrom.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			return
+		}
+		chapter, err = sources.GetChapterMetadata(c.Request.Context(), mangaGetChapterFrom.URL, mangaGetChapterFrom.InternalID, requestData.Chapter, requestData.ChapterURL, requestData.ChapterInternalID, preferences)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 			return
 		}
 	}
-	chapter.Type = 2
-	chapter.UpdatedAt = currentTime.Truncate(time.Second)
-
-	err = multimanga.UpsertChapterIntoDB(chapter)
+	err = setMultiMangaLastReadChapter(c.Request.Context(), multimanga, mangaGetChapterFrom, chapter, currentTime, unmarkAfter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
@@ -1377,6 +1428,29 @@ func UpdateMultiMangaLastReadChapter(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Multimanga last read chapter updated successfully"})
 }
 
+// setMultiMangaLastReadChapter marks the chapters up to the chapter of mangaGetChapterFrom
+// as read at currentTime and records the reading activity. The chapter only becomes the
+// multimanga last read chapter if no later chapter is read, unless unmarkAfter is true.
+func setMultiMangaLastReadChapter(ctx context.Context, multimanga *manga.MultiManga, mangaGetChapterFrom *manga.Manga, chapter *manga.Chapter, currentTime time.Time, unmarkAfter bool) error {
+	chapterReleasedAt := chapter.UpdatedAt
+	previousChapter := multimanga.LastReadChapter
+	releasedChapter := *chapter
+	releasedChapter.Type = 1
+	chapter.Type = 2
+	chapter.UpdatedAt = currentTime.Truncate(time.Second)
+
+	// The last read chapter is derived from the chapters read state,
+	// so setting it means reading all chapters up to it.
+	err := multimanga.MarkChaptersReadUpToInDB(&releasedChapter, chapter.UpdatedAt, unmarkAfter)
+	if err != nil {
+		return err
+	}
+
+	recordReadingActivity(ctx, multimanga.ID, mangaGetChapterFrom, chapter, previousChapter, chapterReleasedAt)
+
+	return nil
+}
+
 // UpdateMangaChapterRequest is the request body for updating a manga chapter
 type UpdateMangaChapterRequest struct {
 	Chapter           string `json:"chapter,omitempty"`
@@ -1510,12 +1584,12 @@ func UpdateMultiMangaCoverImg(c *gin.Context) {
 }
 
 // @Summary Add manga to multimanga list
-// @Description Adds a manga to a multimanga list in the database.
+// @Description Adds a manga to a multimanga list in the database. If the dashboard is configured to add all multimanga's mangas to the download integrations, the manga is added to them by background jobs, whose IDs are returned.
 // @Accept json
 // @Produce json
 // @Param id query int true "Multimanga ID" Example(1)
 // @Param manga body AddMangaToMultiMangaRequest true "Manga data"
-// @Success 200 {object} responseMessage
+// @Success 200 {object} responseMessageWithJobs
 // @Router /multimanga/manga [post]
 func AddMangaToMultiManga(c *gin.Context) {
 	currentTime := time.Now()
@@ -1537,7 +1611,7 @@ func AddMangaToMultiManga(c *gin.Context) {
 		return
 	}
 
-	mangaAdd, err := sources.GetMangaMetadata(requestData.MangaURL, requestData.MangaInternalID)
+	mangaAdd, err := sources.GetMangaMetadata(c.Request.Context(), requestData.MangaURL, requestData.MangaInternalID, nil)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
@@ -1576,51 +1650,18 @@ func AddMangaToMultiManga(c *gin.Context) {
 		return
 	}
 
-	if config.GlobalConfigs.DashboardConfigs.Integrations.AddAllMultiMangaMangasToDownloadIntegrations {
-		var integrationsErrors []error
-		if config.GlobalConfigs.Kaizoku.Valid {
-			kaizoku := kaizoku.Kaizoku{}
-			kaizoku.Init()
-			err = kaizoku.AddManga(mangaAdd, config.GlobalConfigs.Kaizoku.TryOtherSources)
-			if err != nil {
-				integrationsErrors = append(integrationsErrors, util.AddErrorContext("manga added to multimanga, but error while adding current manga to Kaizoku", err))
-			}
-		}
-
-		if config.GlobalConfigs.Tranga.Valid {
-			tranga := tranga.Tranga{}
-			tranga.Init()
-			err = tranga.AddManga(mangaAdd)
-			if err != nil {
-				integrationsErrors = append(integrationsErrors, util.AddErrorContext("manga added to multimanga, but error while adding current manga to Tranga", err))
-			}
-		}
-
-		if config.GlobalConfigs.Suwayomi.Valid {
-			suwayomi := suwayomi.Suwayomi{}
-			suwayomi.Init()
-
-			err = suwayomi.AddManga(mangaAdd, config.GlobalConfigs.DashboardConfigs.Integrations.EnqueueAllSuwayomiChaptersToDownload)
-			if err != nil {
-				integrationsErrors = append(integrationsErrors, util.AddErrorContext("manga added to multimanga, but error while adding it to Suwayomi", err))
-			}
-		}
+	dashboard.UpdateDashboard()
 
-		if len(integrationsErrors) > 0 {
-			fullMsg := "manga added to multimanga, but error executing integrations: "
-			for _, err := range integrationsErrors {
-				zerolog.Ctx(c.Request.Context()).Error().Err(err).Msg("error while adding manga to at least one integration")
-				fullMsg += err.Error() + " "
-			}
-			dashboard.UpdateDashboard()
-			c.JSON(http.StatusInternalServerError, gin.H{"message": fullMsg})
+	integrationsJobs := []*jobs.Job{}
+	if config.GlobalConfigs.DashboardConfigs.Integrations.AddAllMultiMangaMangasToDownloadIntegrations {
+		integrationsJobs, err = enqueueAddMangaJobs(mangaAdd, time.Now())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": fmt.Sprintf("manga added to multimanga, but error enqueueing the download integrations jobs: %s", err), "jobs": getJobIDs(integrationsJobs)})
 			return
 		}
 	}
 
-	dashboard.UpdateDashboard()
-
-	c.JSON(http.StatusOK, gin.H{"message": "Manga added to multimanga successfully"})
+	c.JSON(http.StatusOK, gin.H{"message": "Manga added to multimanga successfully", "jobs": getJobIDs(integrationsJobs)})
 }
 
 // AddMangaToMultiMangaRequest is the request body for the AddManga route
@@ -1630,7 +1671,7 @@ type AddMangaToMultiMangaRequest struct {
 }
 
 // @Summary Remove manga from multimanga list
-// @Description Removes a manga from a multimanga list in the database.
+// @Description Removes a manga from a multimanga list in the database. The "delete" removal policy of the download integrations is applied to the manga.
 // @Accept json
 // @Produce json
 // @Param id query int true "Multimanga ID" Example(1)
@@ -1690,6 +1731,26 @@ func RemoveMangaFromMultiManga(c *gin.Context) {
 
 	dashboard.UpdateDashboard()
 
+	var integrationsErrors []error
+	if len(downloads.GetIntegrations()) > 0 {
+		// The manga is already removed from the database, so all mangas in the library are kept.
+		keepMangas, err := getRemovalKeepMangas(nil)
+		if err != nil {
+			integrationsErrors = append(integrationsErrors, err)
+		} else {
+			integrationsErrors = applyRemovalPolicies(config.RemovalEventDelete, []*manga.Manga{mangaToRemove}, keepMangas)
+		}
+	}
+	if len(integrationsErrors) > 0 {
+		fullMsg := "manga removed from multimanga, but error executing integrations: "
+		for _, err := range integrationsErrors {
+			zerolog.Ctx(c.Request.Context()).Error().Err(err).Msg("error while applying the removal policy of at least one integration")
+			fullMsg += err.Error() + " "
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": fullMsg})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "Manga removed from multimanga successfully"})
 }
 
@@ -1710,7 +1771,7 @@ func SearchManga(c *gin.Context) {
 	if requestData.Limit == 0 {
 		requestData.Limit = 20
 	}
-	mangas, err := sources.SearchManga(requestData.Term, requestData.Source, requestData.Limit)
+	mangas, err := sources.SearchManga(c.Request.Context(), requestData.Term, requestData.Source, requestData.Limit)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
@@ -1737,7 +1798,7 @@ type SearchMangaRequest struct {
 // @Success 200 {array} manga.Manga "{"mangas": [mangaObj]}"
 // @Router /mangas [get]
 func GetMangas(c *gin.Context) {
-	mangas, err := manga.GetCustomMangasDB()
+	mangas, err := manga.GetCustomMangasDBContext(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
@@ -1752,9 +1813,10 @@ func GetMangas(c *gin.Context) {
 		if m.LastReleasedChapter != nil && strings.HasPrefix(m.LastReleasedChapter.URL, manga.CustomMangaURLPrefix) {
 			m.LastReadChapter.URL = ""
 		}
+		setCustomMangaUnreadChapters(m)
 	}
 
-	multimangas, err := manga.GetMultiMangasDB(false)
+	multimangas, err := manga.GetMultiMangasDBContext(c.Request.Context(), false)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
@@ -1762,6 +1824,8 @@ func GetMangas(c *gin.Context) {
 	for _, multimanga := range multimangas {
 		multimanga.CurrentManga.LastReadChapter = multimanga.LastReadChapter
 		multimanga.CurrentManga.Status = multimanga.Status
+		multimanga.CurrentManga.UnreadChapters = multimanga.UnreadChapters
+		multimanga.CurrentManga.Tags = multimanga.Tags
 		if multimanga.CoverImgFixed {
 			multimanga.CurrentManga.CoverImg = multimanga.CoverImg
 			multimanga.CurrentManga.CoverImgURL = multimanga.CoverImgURL
@@ -1792,65 +1856,64 @@ func GetMultiMangas(c *gin.Context) {
 }
 
 // @Summary Mangas iFrame
-// @Description Returns an iFrame with mangas. Only mangas with unread chapters, and status reading or completed. Sort by last released chapter date.
+// @Description Returns an iFrame with mangas. By default, only mangas with unread chapters, and status reading or completed, sorted by last released chapter date. The templates can be overridden in the directory set by the IFRAME_TEMPLATES_DIR environment variable.
 // @Success 200 {string} string "HTML content"
 // @Produce html
 // @Param api_url query string true "API URL used by your browser. Used for the button that updates the last read chater, as your browser needs to send a request to the API to update the chapter." Example(https://sub.domain.com)
 // @Param theme query string false "IFrame theme, defaults to light. If it's different from your dashboard theme, the background turns may turn white" Example(light)
+// @Param layout query string false "IFrame layout: list, compact, grid, carousel, or next_up. Defaults to list." Example(grid)
 // @Param limit query int false "Limits the number of items in the iFrame." Example(5)
+// @Param status query string false "Comma separated list of statuses of the mangas to show (1: reading, 2: completed, 3: on hold, 4: dropped, 5: plan to read). 0 means all statuses. Defaults to 1,2." Example(1,2,5)
+// @Param tags query string false "Comma separated list of tags. Only mangas with any of the tags are shown." Example(action,weekly)
+// @Param source query string false "Comma separated list of sources. Only mangas from the sources are shown." Example(mangadex,comick)
+// @Param sort query string false "Sort by released (last released chapter date), name, unread (unread chapters count), or read (last read chapter date). Defaults to released." Example(name)
+// @Param unread query bool false "If true, shows only mangas with unread chapters. Defaults to true." Example(false)
 // @Param showBackgroundErrorWarning query bool false "If true, shows a warning in the iFrame if an error occurred in the background. Defaults to true." Example(true)
+// @Param css_variable query string false "Sets a CSS variable of the iFrame, like css_released_color=%23fa5252 sets --mantium-released-color. Overrides the iFrame CSS variables of the dashboard configs." Example(%23fa5252)
 // @Router /mangas/iframe [get]
 func GetMangasiFrame(c *gin.Context) {
-	queryLimit := c.Query("limit")
-	var limit int
-	var err error
-	if queryLimit == "" {
-		limit = -1
-	} else {
-		limit, err = strconv.Atoi(queryLimit)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"message": "limit must be a number"})
-		}
+	options, err := iframe.ParseOptions(c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
 	}
 
-	theme := c.Query("theme")
-	if theme == "" {
-		theme = "light"
-	} else if theme != "dark" && theme != "light" {
-		c.JSON(http.StatusBadRequest, gin.H{"message": "theme must be 'dark' or 'light'"})
+	allMangas, err := getLibraryMangas()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
 	}
+	mangas := iframe.FilterMangas(allMangas, options)
 
-	apiURL := c.Query("api_url")
-	_, err = url.ParseRequestURI(apiURL)
+	html, err := getMangasiFrame(mangas, options)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"message": "api_url must be a valid URL like 'http://192.168.1.46:8080' or 'https://sub.domain.com'"})
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
 	}
 
-	showBackgroundErrorWarning := true
-	showBackgroundErrorWarningStr := c.Query("showBackgroundErrorWarning")
-	if showBackgroundErrorWarningStr != "" {
-		showBackgroundErrorWarning, err = strconv.ParseBool(showBackgroundErrorWarningStr)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"message": "showBackgroundErrorWarning must be a boolean"})
-			return
-		}
-	}
+	c.Data(http.StatusOK, "text/html", html)
+}
 
+// getLibraryMangas returns the custom mangas and the current manga of
+// each multimanga, with the multimanga's last read chapter, status,
+// unread chapters, tags, and fixed cover image.
+func getLibraryMangas() ([]*manga.Manga, error) {
 	allMangas, err := manga.GetCustomMangasDB()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
-		return
+		return nil, err
+	}
+	for _, m := range allMangas {
+		setCustomMangaUnreadChapters(m)
 	}
 	multimangas, err := manga.GetMultiMangasDB(false)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
-		return
+		return nil, err
 	}
 	for _, multimanga := range multimangas {
 		multimanga.CurrentManga.LastReadChapter = multimanga.LastReadChapter
 		multimanga.CurrentManga.Status = multimanga.Status
+		multimanga.CurrentManga.UnreadChapters = multimanga.UnreadChapters
+		multimanga.CurrentManga.Tags = multimanga.Tags
 		if multimanga.CoverImgFixed {
 			multimanga.CurrentManga.CoverImg = multimanga.CoverImg
 			multimanga.CurrentManga.CoverImgURL = multimanga.CoverImgURL
@@ -1859,492 +1922,25 @@ func GetMangasiFrame(c *gin.Context) {
 		}
 		allMangas = append(allMangas, multimanga.CurrentManga)
 	}
-	allUnreadMangas := manga.FilterUnreadChapterMangas(allMangas)
-	mangas := []*manga.Manga{}
-	for _, manga := range allUnreadMangas {
-		if manga.Status == 1 || manga.Status == 2 {
-			mangas = append(mangas, manga)
-		}
-	}
-	manga.SortMangasByLastReleasedChapterUpdatedAt(mangas)
-
-	if limit >= 0 && limit < len(mangas) {
-		mangas = mangas[:limit]
-	}
 
-	html, err := getMangasiFrame(mangas, theme, apiURL, showBackgroundErrorWarning)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
-		return
-	}
-
-	c.Data(http.StatusOK, "text/html", []byte(html))
+	return allMangas, nil
 }
 
-func getMangasiFrame(mangas []*manga.Manga, theme, apiURL string, showBackgroundErrorWarning bool) ([]byte, error) {
-	html := `
-<!doctype html>
-<html lang="en">
-  <head>
-    <meta charset="UTF-8" />
-    <meta name="viewport" content="width=device-width, initial-scale=1.0" />
-    <meta name="referrer" content="no-referrer"> <!-- If not set, can't load Mangedex images when behind a domain or reverse proxy -->
-    <script src="https://kit.fontawesome.com/3f763b063a.js" crossorigin="anonymous"></script>
-    <meta name="color-scheme" content="{{ .Theme }}">
-    <title>Mantium</title>
-    <style>
-        body {
-            background: transparent !important;
-            margin: 0;
-            padding: 0;
-        }
-
-        .mangas-container {
-            height: 84px;
-
-            position: relative;
-            display: flex;
-            align-items: center;
-            justify-content: space-between;
-            margin: 8.50px;
-
-            border-radius: 10px;
-            border: 1px solid rgba(56, 58, 64, 1);
-        }
-
-        .background-image {
-            background-position: center;
-            background-size: cover;
-            position: absolute;
-            filter: brightness(0.3);
-            top: 0;
-            left: 0;
-            right: 0;
-            bottom: 0;
-            z-index: -1;
-            border-radius: 10px;
-        }
-
-        .manga-cover {
-            border-radius: 2px;
-            margin-left: 20px;
-            margin-right: 20px;
-            object-fit: cover;
-            width: 30px;
-            height: 50px;
-        }
-
-        .text-wrap {
-            flex-grow: 1;
-            overflow: hidden;
-            white-space: nowrap;
-            text-overflow: ellipsis;
-            width: 1px !important;
-            margin-right: 10px 0px 10px 10px;
-
-            /* if the attributes below are overwritten in the inner elements, this set the ellipsis properties only */
-            color: white; 
-            font-weight: bold;
-        }
-
-        .manga-name {
-            font-size: 15px;
-            color: white;
-            font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Roboto, Helvetica, Arial, sans-serif, "Apple Color Emoji", "Segoe UI Emoji";
-            text-decoration: none;
-            font-weight: bold;
-        }
-
-        .manga-name:hover {
-            text-decoration: underline;
-        }
-
-        .new-chapter-container {
-            display: inline-block;
-            padding: 8px 0px;
-            margin: 20px 10px;
-            background-color: transparent;
-            border-radius: 5px;
-            width: 162px;
-            text-align: center;
-        }
-
-        .chapter-label {
-            text-decoration: none;
-            font-size: 20px;
-            font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Roboto,
-              Helvetica, Arial, sans-serif, "Apple Color Emoji", "Segoe UI Emoji";
-        }
-
-        a.chapter-label:hover {
-            text-decoration: underline;
-        }
-
-        .last-released-chapter-label {
-            color: rgb(101, 206, 230);
-        }
-
-        .last-read-chapter-label {
-            color: rgb(210, 101, 230);
-        }
-
-        .chapter-gt-label {
-            color: rgb(101, 206, 230);
-        }
-
-        .set-last-read-button {
-            color: white;
-            background-color: #04c9b7;
-            padding: 0.25rem 0.75rem;
-            border-radius: 0.5rem;
-            border: 1px solid rgb(4, 201, 183);
-            margin-top: 10px;
-            font-weight: bold;
-        }
-
-        button.set-last-read-button:hover {
-            filter: brightness(0.9)
-        }
-
-        .delete-background-error-container {
-            display: inline-block;
-            padding: 8px 0px;
-            margin: 20px 10px;
-            background-color: transparent;
-            border-radius: 5px;
-            width: 162px;
-            text-align: center;
-        }
-
-        #delete-background-error-button {
-            color: red;
-            background-color: white;
-            padding: 0.25rem 0.75rem;
-            border-radius: 0.5rem;
-            border: 1px solid rgb(4, 201, 183);
-            font-weight: bold;
-        }
-
-        button#delete-background-error-button:hover {
-            filter: brightness(0.9)
-        }
-
-        .info-label {
-            text-decoration: none;
-            font-family: ui-sans-serif, system-ui, -apple-system, BlinkMacSystemFont,
-              Segoe UI, Roboto, Helvetica Neue, Arial, Noto Sans, sans-serif, Apple Color Emoji,
-              Segoe UI Emoji, Segoe UI Symbol, Noto Color Emoji;
-            font-feature-settings: normal;
-            font-variation-settings: normal;
-            font-weight: 600;
-            color: #4f6164;
-            font-size: 1rem;
-            line-height: 1.5rem;
-        }
-
-        ::-webkit-scrollbar {
-            width: 7px;
-        }
-
-        ::-webkit-scrollbar-thumb {
-            background-color: {{ .ScrollbarThumbBackgroundColor }};
-            border-radius: 2.3px;
-        }
-
-        ::-webkit-scrollbar-track {
-            background-color: transparent;
-        }
-
-        ::-webkit-scrollbar-track:hover {
-            background-color: {{ .ScrollbarTrackBackgroundColor }};
-        }
-    </style>
-
-    <script>
-      function setMangaLastReadChapter(mangaId) {
-        try {
-            var xhr = new XMLHttpRequest();
-            var url = '{{ .APIURL }}/v1/manga/last_read_chapter?id=' + encodeURIComponent(mangaId);
-            xhr.open('PATCH', url, true);
-            xhr.setRequestHeader('Content-Type', 'application/json');
-
-            xhr.onload = function () {
-              if (xhr.status >= 200 && xhr.status < 300) {
-                console.log('Request to update manga', mangaId, ' last read chapter finished with success:', xhr.responseText);
-                location.reload();
-              } else {
-                console.log('Request to update manga', mangaId, ' last read chapter failed:', xhr.responseText);
-                handleSetLastReadChapterError("manga-" + mangaId)
-              }
-            };
-
-            xhr.onerror = function () {
-              console.log('Request to update manga', mangaId, ' last read chapter failed:', xhr.responseText);
-              handleSetLastReadChapterError(mangaId)
-            };
-
-            var body = {};
-
-            xhr.send(JSON.stringify(body));
-        } catch (error) {
-            console.log('Request to update manga', mangaId, ' last read chapter failed:', error);
-            handleSetLastReadChapterError("manga-" + mangaId)
-        }
-      }
-
-      function setMultiMangaLastReadChapter(multimangaId, mangaId) {
-        try {
-            var xhr = new XMLHttpRequest();
-            var url = '{{ .APIURL }}/v1/multimanga/last_read_chapter?id=' + encodeURIComponent(multimangaId) + '&manga_id=' + encodeURIComponent(mangaId);
-            xhr.open('PATCH', url, true);
-            xhr.setRequestHeader('Content-Type', 'application/json');
-
-            xhr.onload = function () {
-              if (xhr.status >= 200 && xhr.status < 300) {
-                console.log('Request to update multimanga', multimangaId, ' last read chapter finished with success:', xhr.responseText);
-                location.reload();
-              } else {
-                console.log('Request to update multimanga', multimangaId, ' last read chapter failed:', xhr.responseText);
-                handleSetLastReadChapterError("manga-" + mangaId)
-              }
-            };
-
-            xhr.onerror = function () {
-              console.log('Request to update multimanga', multimangaId, ' last read chapter failed:', xhr.responseText);
-              handleSetLastReadChapterError(mangaId)
-            };
-
-            var body = {};
-
-            xhr.send(JSON.stringify(body));
-        } catch (error) {
-            console.log('Request to update multimanga', multimangaId, ' last read chapter failed:', error);
-            handleSetLastReadChapterError("manga-" + mangaId)
-        }
-      }
-
-      function setCustomMangaNoHasMoreChapter(mangaId) {
-        try {
-            var xhr = new XMLHttpRequest();
-            var url = '{{ .APIURL }}/v1/custom_manga/has_more_chapters?has_more_chapters=false&id=' + encodeURIComponent(mangaId);
-            xhr.open('PATCH', url, true);
-            xhr.setRequestHeader('Content-Type', 'application/json');
-
-            xhr.onload = function () {
-              if (xhr.status >= 200 && xhr.status < 300) {
-                console.log('Request to update custom manga', mangaId, ' has no more chapters finished with success:', xhr.responseText);
-                location.reload();
-              } else {
-                console.log('Request to update manga', mangaId, ' has no more chapters failed:', xhr.responseText);
-                handleSetLastReadChapterError("manga-" + mangaId)
-              }
-            };
-
-            xhr.onerror = function () {
-              console.log('Request to update manga', mangaId, ' has no more chapters failed:', xhr.responseText);
-              handleSetLastReadChapterError(mangaId)
-            };
-
-            var body = {};
-
-            xhr.send(JSON.stringify(body));
-        } catch (error) {
-            console.log('Request to update manga', mangaId, ' has no more chapters failed:', error);
-            handleSetLastReadChapterError("manga-" + mangaId)
-        }
-      }
-
-      function handleSetLastReadChapterError(buttonId) {
-        var button = document.getElementById(buttonId);
-        button.textContent = "! ERROR !";
-        button.style.backgroundColor = "red";
-        button.style.borderColor = "red";
-      }
-
-      function deleteBackgroundError() {
-        try {
-            var xhr = new XMLHttpRequest();
-            var url = '{{ .APIURL }}/v1/dashboard/last_background_error';
-            xhr.open('DELETE', url, true);
-            xhr.setRequestHeader('Content-Type', 'application/json');
-
-            xhr.onload = function () {
-              if (xhr.status >= 200 && xhr.status < 300) {
-                console.log('Request to delete background error finished with success:', xhr.responseText);
-                location.reload();
-              } else {
-                console.log('Request to delete background error failed:', xhr.responseText);
-                handleDeleteBackgroundError()
-              }
-            };
-
-            xhr.onerror = function () {
-              console.log('Request to delete background error failed:', xhr.responseText);
-              handleDeleteBackgroundError()
-            };
-
-            xhr.send();
-        } catch (error) {
-            console.log('Request to delete background error failed:', xhr.responseText);
-            handleDeleteBackgroundError()
-        }
-      }
-
-      function handleDeleteBackgroundError() {
-        var button = document.getElementById('delete-background-error-button');
-        button.textContent = "! ERROR !";
-      }
-    </script>
-
-    <script>
-        let lastUpdate = null;
-
-        async function fetchData() {
-            try {
-                var url = '{{ .APIURL }}/v1/dashboard/last_update';
-                const response = await fetch(url);
-                const data = await response.json();
-
-                if (lastUpdate === null) {
-                    lastUpdate = data.message;
-                } else {
-                    if (data.message !== lastUpdate) {
-                        lastUpdate = data.message;
-                        location.reload();
-                    }
-                }
-            } catch (error) {
-                console.error('Error getting last update from the API:', error);
-            }
-        }
-
-        function fetchAndUpdate() {
-            fetchData();
-            setTimeout(fetchAndUpdate, 5000); // 5 seconds
-        }
-
-        fetchAndUpdate();
-    </script>
-
-  </head>
-<body>
-{{ if .ShowBackgroundError }}
-<div class="mangas-container" style="background-color: red;">
-    <div class="text-wrap" style="margin-left: 20px;">
-        <span class="manga-name">An error occured in the background.</span>
-
-        <div>
-            <span style="margin-right: 7px;" class="info-label"><i class="fa-solid fa-calendar-days"></i> {{ .BackgroundErrorTime.Format "2006-01-02 15:04:05" }}</span>
-        </div>
-    </div>
-    <div class="delete-background-error-container">
-        <button id="delete-background-error-button" onclick="deleteBackgroundError()" onmouseenter="this.style.cursor='pointer';">Delete Error</button>
-    </div>
-</div>
-{{ end }}
-{{range .Mangas }}
-    <div class="mangas-container">
-
-    <div style="background-image: url('data:image/jpeg;base64,{{ encodeImage .CoverImg }}');" class="background-image"></div>
-
-        <img
-            class="manga-cover"
-            src="data:image/jpeg;base64,{{ encodeImage .CoverImg }}"
-            alt="Manga Cover"
-        />
-
-        <div class="text-wrap">
-            <a {{ if not (isCustomMangaURL .URL) }} href="{{ .URL }}" {{ end }} target="_blank" class="manga-name">{{ .Name }}</a>
-        </div>
-
-        <div class="new-chapter-container">
-            {{ if not (isCustomManga .Source) }}
-                {{ if .LastReadChapter }}
-                    <a href="{{ .LastReadChapter.URL }}" class="chapter-label last-read-chapter-label" target="_blank">{{ .LastReadChapter.Chapter }}</a>
-                {{ else }}
-                    <a href="{{ .URL }}" class="chapter-label last-read-chapter-label" target="_blank">N/A</a>
-                {{ end }}
-                <span class="chapter-label chapter-gt-label"> &lt; </span>
-                {{ if .LastReleasedChapter }}
-                    <a href="{{ .LastReleasedChapter.URL }}" class="chapter-label last-released-chapter-label" target="_blank">{{ .LastReleasedChapter.Chapter }}</a>
-                {{ else }}
-                    <a href="{{ .URL }}" class="chapter-label last-released-chapter-label" target="_blank">N/A</a>
-                {{ end }}
-
-                <div>
-                    <button id="manga-{{ .ID }}" onclick="{{ if eq .MultiMangaID 0 }}setMangaLastReadChapter('{{ .ID }}'){{ else }}setMultiMangaLastReadChapter('{{ .MultiMangaID }}', '{{ .ID }}'){{ end }}" class="set-last-read-button" onmouseenter="this.style.cursor='pointer';">Set last read</button>
-                </div>
-            {{ else }}
-                <a {{ if not (isCustomMangaURL .LastReadChapter.URL) }} href="{{ .LastReadChapter.URL }}"{{end}} class="chapter-label last-released-chapter-label" target="_blank">{{ .LastReadChapter.Chapter }}</a>
-
-                <div>
-                    <button id="manga-{{ .ID }}" onclick="setCustomMangaNoHasMoreChapter('{{ .ID }}')" class="set-last-read-button" onmouseenter="this.style.cursor='pointer';">No more chapters</button>
-                </div>
-            {{ end }}
-        </div>
-
-    </div>
-{{end}}
-</body>
-</html>
-	`
-	scrollbarThumbBackgroundColor := "rgba(209, 219, 227, 1)"
-	scrollbarTrackBackgroundColor := "#ffffff"
-	if theme == "dark" {
-		scrollbarThumbBackgroundColor = "#484d64"
-		scrollbarTrackBackgroundColor = "rgba(37, 40, 53, 1)"
-	}
-
-	templateData := iframeTemplateData{
-		Mangas:                        mangas,
-		Theme:                         theme,
-		APIURL:                        apiURL,
-		ScrollbarThumbBackgroundColor: scrollbarThumbBackgroundColor,
-		ScrollbarTrackBackgroundColor: scrollbarTrackBackgroundColor,
-	}
+func getMangasiFrame(mangas []*manga.Manga, options *iframe.Options) ([]byte, error) {
+	templateData := iframe.NewTemplateData(mangas, options)
 	lastBackgroundError := dashboard.GetLastBackgroundError()
-	if lastBackgroundError.Message != "" && showBackgroundErrorWarning {
+	if lastBackgroundError.Message != "" && options.ShowBackgroundErrorWarning {
 		templateData.ShowBackgroundError = true
 		templateData.BackgroundErrorTime = lastBackgroundError.Time
 	}
 
-	encodeImageF := template.FuncMap{
-		"encodeImage": func(bytes []byte) string {
-			return base64.StdEncoding.EncodeToString(bytes)
-		},
-		"isCustomManga": func(source string) bool {
-			return source == manga.CustomMangaSource
-		},
-		"isCustomMangaURL": func(url string) bool {
-			return strings.HasPrefix(url, manga.CustomMangaURLPrefix)
-		},
-	}
-
-	tmpl := template.Must(template.New("mangas").Funcs(encodeImageF).Parse(html))
-
-	var buf bytes.Buffer
-	err := tmpl.Execute(&buf, templateData)
-	if err != nil {
-		return []byte{}, err
-	}
-
-	return buf.Bytes(), nil
-}
-
-type iframeTemplateData struct {
-	BackgroundErrorTime           time.Time
-	Theme                         string
-	APIURL                        string
-	ScrollbarThumbBackgroundColor string
-	ScrollbarTrackBackgroundColor string
-	Mangas                        []*manga.Manga
-	ShowBackgroundError           bool
+	return iframe.Render(templateData)
 }
 
 // @Summary Update mangas metadata
-// @Description Get the mangas metadata from the sources and update them in the database.
+// @Description Get the mangas metadata from the sources and update them in the database. The titles related to the mangas, like sequels, are fetched at most once a day from the sources that list them. The multimangas IDs in MangaUpdates, AniList, and MyAnimeList are resolved if they never were, or at most once a week if some are missing. The custom manga watchers are also checked for new chapters. If Komga or Kavita are configured, their libraries are scanned when there are new chapters, and the read progress is synced from them.
 // @Produce json
-// @Param notify query string false "Notify if a new chapter was released for the manga (only of mangas with status reading or completed)."
+// @Param notify query string false "Notify if a new chapter was released for the manga (only of mangas with status reading or completed), and if the NOTIFICATIONS_NEW_RELATIONS environment variable is true, if a completed manga has a new related title, like a sequel. The notifications follow the notification rules, and the deferred notifications that are due are also sent."
 // @Success 200 {object} responseMessage
 // @Router /mangas/metadata [patch]
 func UpdateMangasMetadata(c *gin.Context) {
@@ -2356,25 +1952,21 @@ func UpdateMangasMetadata(c *gin.Context) {
 
 	var mangasWithNewChapter []*manga.Manga
 
+	runStart := time.Now()
 	logger := util.GetLogger(zerolog.Level(config.GlobalConfigs.API.LogLevelInt))
 	errors := map[string][]string{
-		"manga_metadata": {},
-		"ntfy":           {},
-		"tranga":         {},
-		"kaizoku":        {},
-		"suwayomi":       {},
+		"manga_metadata":        {},
+		"ntfy":                  {},
+		"tranga":                {},
+		"kaizoku":               {},
+		"suwayomi":              {},
+		"custom_manga_watchers": {},
+		"manga_relations":       {},
+		"external_ids":          {},
 	}
 	var newMetadata bool
-	var trangaInt *tranga.Tranga
-	if config.GlobalConfigs.Tranga.Valid {
-		trangaInt = &tranga.Tranga{}
-		trangaInt.Init()
-	}
-	var suwayomiInt *suwayomi.Suwayomi
-	if config.GlobalConfigs.Suwayomi.Valid {
-		suwayomiInt = &suwayomi.Suwayomi{}
-		suwayomiInt.Init()
-	}
+	readingIntegrations := getReadingIntegrations()
+	downloadIntegrations := downloads.GetIntegrations()
 	retries := 3
 	retryInterval := 3 * time.Second
 
@@ -2383,10 +1975,35 @@ func UpdateMangasMetadata(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
 	}
+	chapterPreferences, err := manga.GetAllChapterPreferencesDB()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	relationsCheckedAt, err := manga.GetRelationsCheckedAtDB()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	externalIDs, err := manga.GetAllExternalIDsDB()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	// Used to get the range of new chapters in the digest
+	previousReleasedChapters := make(map[manga.ID]*manga.Chapter, len(multimangas))
+	for _, multimanga := range multimangas {
+		previousReleasedChapters[multimanga.ID] = multimanga.CurrentManga.LastReleasedChapter
+	}
 
 	type result struct {
 		mangaWithNewChapters *manga.Manga
+		multimanga           *manga.MultiManga
+		newRelations         []*manga.Relation
 		multimangaErrors     []string
+		relationsErrors      []string
+		externalIDsErrors    []string
 	}
 
 	results := make(chan result, len(multimangas))
@@ -2405,13 +2022,23 @@ func UpdateMangasMetadata(c *gin.Context) {
 		go func(chunk []*manga.MultiManga) {
 			defer wg.Done()
 			for _, multimangaToUpdate := range chunk {
-				mangaWithNewChapters, multimangaNewMetadata, multimangaErrors := updateMultiMangaMetadata(multimangaToUpdate, retries, retryInterval, logger)
+				mangaWithNewChapters, multimangaNewMetadata, multimangaErrors := updateMultiMangaMetadata(c.Request.Context(), multimangaToUpdate, chapterPreferences, retries, retryInterval, logger)
 				if multimangaNewMetadata {
 					newMetadata = true
 				}
+				newRelations, relationsErrors := updateMultiMangaRelations(c.Request.Context(), multimangaToUpdate, relationsCheckedAt, retries, retryInterval, logger)
+				var externalIDsErrors []string
+				multimangaExternalIDs := externalIDs[multimangaToUpdate.ID]
+				if shouldResolveExternalIDs(multimangaExternalIDs, runStart) && hasExternalIDsSource(multimangaToUpdate) {
+					_, externalIDsErrors = resolveMultiMangaExternalIDs(c.Request.Context(), multimangaToUpdate, multimangaExternalIDs, retries, retryInterval, logger)
+				}
 				result := result{
 					mangaWithNewChapters: mangaWithNewChapters,
+					multimanga:           multimangaToUpdate,
+					newRelations:         newRelations,
 					multimangaErrors:     multimangaErrors,
+					relationsErrors:      relationsErrors,
+					externalIDsErrors:    externalIDsErrors,
 				}
 				results <- result
 			}
@@ -2430,59 +2057,80 @@ func UpdateMangasMetadata(c *gin.Context) {
 		if len(res.multimangaErrors) > 0 {
 			errors["manga_metadata"] = append(errors["manga_metadata"], res.multimangaErrors...)
 		}
+		errors["manga_relations"] = append(errors["manga_relations"], res.relationsErrors...)
+		errors["external_ids"] = append(errors["external_ids"], res.externalIDsErrors...)
+		// Notify only the new relations of multimangas with status 2 (completed)
+		if notify && config.GlobalConfigs.Notifications.NewRelations && len(res.newRelations) > 0 && res.multimanga.Status == 2 {
+			err = notifyNewRelations(res.multimanga.CurrentManga, res.newRelations, retries, retryInterval, logger)
+			if err != nil {
+				errors["ntfy"] = append(errors["ntfy"], err.Error())
+			}
+		}
+	}
+
+	customMangasWithNewChapter, watchersErrors := checkCustomMangaWatchers(c.Request.Context(), logger)
+	errors["custom_manga_watchers"] = append(errors["custom_manga_watchers"], watchersErrors...)
+	if len(customMangasWithNewChapter) > 0 {
+		newMetadata = true
 	}
 
 	if newMetadata {
 		dashboard.UpdateDashboard()
 	}
 
+	for _, m := range customMangasWithNewChapter {
+		if notify && (m.Status == 1 || m.Status == 2) {
+			err = notifyCustomMangaNewChapter(m, retries, retryInterval, logger)
+			if err != nil {
+				errors["ntfy"] = append(errors["ntfy"], err.Error())
+			}
+		}
+	}
+
 	for _, m := range mangasWithNewChapter {
 		// Notify only if the manga's status is 1 (reading) or 2 (completed)
+		// The multimanga notification rules are evaluated before notifying
 		if notify && (m.Status == 1 || m.Status == 2) {
-			for j := range retries {
-				err = NotifyMangaLastReleasedChapterUpdate(m)
-				if err != nil {
-					if j == retries-1 {
-						logger.Error().Err(err).Str("manga_url", m.URL).Msg(fmt.Sprintf("Manga metadata updated in DB, but error while notifying: %s.\nWill continue with the next manga...", err.Error()))
-						errors["ntfy"] = append(errors["ntfy"], err.Error())
-						break
-					}
-					logger.Error().Err(err).Str("manga_url", m.URL).Msgf("Manga metadata updated in DB, but error while notifying: %s.\nRetrying in %.2f seconds...", err.Error(), retryInterval.Seconds())
-					continue
-				}
-				break
+			err = notifyMangaWithRules(m, previousReleasedChapters[m.MultiMangaID], retries, retryInterval, logger)
+			if err != nil {
+				errors["ntfy"] = append(errors["ntfy"], err.Error())
 			}
 		}
 
-		if trangaInt != nil {
-			err = trangaInt.StartJob(m)
+		for _, integration := range downloadIntegrations {
+			err = integration.EnqueueChapter(m, m.LastReleasedChapter)
 			if err != nil {
-				logger.Error().Err(err).Str("manga_url", m.URL).Msg("Manga metadata updated in DB, but error starting job in Tranga.\nWill continue with the next manga...")
-				errors["tranga"] = append(errors["tranga"], err.Error())
+				logger.Error().Err(err).Str("integration", integration.Name()).Str("manga_url", m.URL).Msg("Manga metadata updated in DB, but error enqueueing the new chapter download.\nWill continue with the next manga...")
+				name := strings.ToLower(integration.Name())
+				errors[name] = append(errors[name], err.Error())
 			}
-
 		}
+	}
 
-		if suwayomiInt != nil {
-			mangaID, err := suwayomiInt.GetLibraryMangaID(m)
+	// The queue is sent by its own periodic job if it's enabled
+	if notify && config.GlobalConfigs.Notifications.SendQueueMinutes == 0 {
+		errors["ntfy"] = append(errors["ntfy"], sendDueQueuedNotifications(retries, retryInterval, logger)...)
+	}
+
+	// Komga and Kavita only see the new chapters after scanning their libraries
+	if len(mangasWithNewChapter) > 0 {
+		for name, integration := range readingIntegrations {
+			err = integration.ScanMangasLibraries(mangasWithNewChapter)
 			if err != nil {
-				logger.Error().Err(err).Str("manga_url", m.URL).Msg("Manga metadata updated in DB, but error getting manga ID from Suwayomi.\nWill continue with the next manga...")
-				errors["suwayomi"] = append(errors["suwayomi"], err.Error())
-			} else {
-				chapter, err := suwayomiInt.GetChapter(mangaID, m.LastReleasedChapter.URL)
-				if err != nil {
-					logger.Error().Err(err).Str("manga_url", m.URL).Str("suwayomi_manga_id", strconv.Itoa(mangaID)).Msg("Manga metadata updated in DB, but error getting chapter from Suwayomi.\nWill continue with the next manga...")
-					errors["suwayomi"] = append(errors["suwayomi"], err.Error())
-				} else {
-					err = suwayomiInt.EnqueueChapterDownloads([]int{chapter.ID})
-					if err != nil {
-						logger.Error().Err(err).Str("manga_url", m.URL).Str("suwayomi_chapter_id", strconv.Itoa(chapter.ID)).Msg("Manga metadata updated in DB, but error updating chapter in Suwayomi.\nWill continue with the next manga...")
-						errors["suwayomi"] = append(errors["suwayomi"], err.Error())
-					}
-				}
+				logger.Error().Err(err).Str("integration", name).Msg("Mangas metadata updated in DB, but error scanning libraries")
+				errors[name] = append(errors[name], err.Error())
 			}
 		}
 	}
+	if len(readingIntegrations) > 0 {
+		readProgressUpdated, readProgressErrors := syncReadProgress(c.Request.Context(), multimangas, readingIntegrations, logger)
+		if readProgressUpdated {
+			dashboard.UpdateDashboard()
+		}
+		for name, errSlice := range readProgressErrors {
+			errors[name] = append(errors[name], errSlice...)
+		}
+	}
 
 	if config.GlobalConfigs.Kaizoku.Valid && newMetadata {
 		err = KaizokuTriggerChaptersDownload(logger)
@@ -2491,6 +2139,14 @@ func UpdateMangasMetadata(c *gin.Context) {
 		}
 	}
 
+	err = manga.SaveBacklogSnapshot()
+	if err != nil {
+		logger.Error().Err(err).Msg("Mangas metadata updated in DB, but error saving backlog snapshot")
+		errors["manga_metadata"] = append(errors["manga_metadata"], err.Error())
+	}
+
+	telemetry.ObserveUpdateMangasMetadata(time.Since(runStart), len(mangasWithNewChapter))
+
 	for _, errSlice := range errors {
 		if len(errSlice) > 0 {
 			c.JSON(http.StatusInternalServerError, gin.H{"message": "some errors occured while updating the mangas metadata, check the logs for more information", "errors": errors})
@@ -2513,66 +2169,7 @@ func AddMangasToKaizoku(c *gin.Context) {
 		return
 	}
 
-	statusFilterStr := c.Query("status")
-	var statusFilter []int
-	if statusFilterStr != "" {
-		statusStrings := strings.Split(statusFilterStr, ",")
-		for _, statusStr := range statusStrings {
-			status, err := strconv.Atoi(statusStr)
-			if err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"message": "status must be a list of numbers"})
-				return
-			}
-			statusFilter = append(statusFilter, status)
-		}
-	}
-
-	mangas := []*manga.Manga{}
-	multimangas, err := manga.GetMultiMangasDB(false)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
-		return
-	}
-	for _, multimanga := range multimangas {
-		multimanga.CurrentManga.Status = multimanga.Status
-		mangas = append(mangas, multimanga.CurrentManga)
-	}
-
-	kaizoku := kaizoku.Kaizoku{}
-	kaizoku.Init()
-	logger := util.GetLogger(zerolog.Level(config.GlobalConfigs.API.LogLevelInt))
-	var lastError error
-	for _, dbManga := range mangas {
-		if dbManga.Source == manga.CustomMangaSource {
-			continue
-		}
-
-		if len(statusFilter) > 0 {
-			var found bool
-			for _, status := range statusFilter {
-				if dbManga.Status == manga.Status(status) {
-					found = true
-					break
-				}
-			}
-			if !found {
-				continue
-			}
-		}
-		err = kaizoku.AddManga(dbManga, config.GlobalConfigs.Kaizoku.TryOtherSources)
-		if err != nil {
-			logger.Error().Err(err).Str("manga_url", dbManga.URL).Msg("error adding manga to Kaizoku, will continue with the next manga...")
-			lastError = err
-			continue
-		}
-	}
-
-	if lastError != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"message": "some errors occured while adding some mangas to Kaizoku, check the logs for more information. Last error: " + lastError.Error()})
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{"message": "Mangas added to Kaizoku successfully"})
+	addMangasToDownloadIntegration(c, "Kaizoku")
 }
 
 // @Summary Add mangas to Tranga
@@ -2587,66 +2184,7 @@ func AddMangasToTranga(c *gin.Context) {
 		return
 	}
 
-	statusFilterStr := c.Query("status")
-	var statusFilter []int
-	if statusFilterStr != "" {
-		statusStrings := strings.Split(statusFilterStr, ",")
-		for _, statusStr := range statusStrings {
-			status, err := strconv.Atoi(statusStr)
-			if err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"message": "status must be a list of numbers"})
-				return
-			}
-			statusFilter = append(statusFilter, status)
-		}
-	}
-
-	mangas := []*manga.Manga{}
-	multimangas, err := manga.GetMultiMangasDB(false)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
-		return
-	}
-	for _, multimanga := range multimangas {
-		multimanga.CurrentManga.Status = multimanga.Status
-		mangas = append(mangas, multimanga.CurrentManga)
-	}
-
-	trangaInt := tranga.Tranga{}
-	trangaInt.Init()
-	logger := util.GetLogger(zerolog.Level(config.GlobalConfigs.API.LogLevelInt))
-	var errorSlice []string
-	for _, dbManga := range mangas {
-		if dbManga.Source == manga.CustomMangaSource {
-			continue
-		}
-
-		if len(statusFilter) > 0 {
-			var found bool
-			for _, status := range statusFilter {
-				if dbManga.Status == manga.Status(status) {
-					found = true
-					break
-				}
-			}
-			if !found {
-				continue
-			}
-		}
-		err = trangaInt.AddManga(dbManga)
-		if err != nil {
-			logger.Error().Err(err).Str("manga_url", dbManga.URL).Msg("error adding manga to Tranga, will continue with the next manga...")
-			errorSlice = append(errorSlice, err.Error())
-			continue
-		}
-	}
-
-	if len(errorSlice) > 0 {
-		c.JSON(http.StatusInternalServerError, gin.H{"message": "some errors occured while adding some mangas to Kaizoku, check the logs for more information", "errors": errorSlice})
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{"message": "Mangas added to Tranga successfully"})
+	addMangasToDownloadIntegration(c, "Tranga")
 }
 
 // @Summary Add mangas to Suwayomi
@@ -2661,67 +2199,7 @@ func AddMangasToSuwayomi(c *gin.Context) {
 		return
 	}
 
-	statusFilterStr := c.Query("status")
-	var statusFilter []int
-	if statusFilterStr != "" {
-		statusStrings := strings.Split(statusFilterStr, ",")
-		for _, statusStr := range statusStrings {
-			status, err := strconv.Atoi(statusStr)
-			if err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"message": "status must be a list of numbers"})
-				return
-			}
-			statusFilter = append(statusFilter, status)
-		}
-	}
-
-	mangas := []*manga.Manga{}
-	multimangas, err := manga.GetMultiMangasDB(false)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
-		return
-	}
-	for _, multimanga := range multimangas {
-		multimanga.CurrentManga.Status = multimanga.Status
-		mangas = append(mangas, multimanga.CurrentManga)
-	}
-
-	suwayomi := suwayomi.Suwayomi{}
-	suwayomi.Init()
-	logger := util.GetLogger(zerolog.Level(config.GlobalConfigs.API.LogLevelInt))
-	var lastError error
-
-	for _, dbManga := range mangas {
-		if dbManga.Source == manga.CustomMangaSource {
-			continue
-		}
-
-		if len(statusFilter) > 0 {
-			var found bool
-			for _, status := range statusFilter {
-				if dbManga.Status == manga.Status(status) {
-					found = true
-					break
-				}
-			}
-			if !found {
-				continue
-			}
-		}
-		err = suwayomi.AddManga(dbManga, config.GlobalConfigs.DashboardConfigs.Integrations.EnqueueAllSuwayomiChaptersToDownload)
-		if err != nil {
-			logger.Error().Err(err).Str("manga_url", dbManga.URL).Msg("error adding manga to Suwayomi, will continue with the next manga...")
-			lastError = err
-			continue
-		}
-	}
-
-	if lastError != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"message": "some errors occured while adding some mangas to Suwayomi, check the logs for more information. Last error: " + lastError.Error()})
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{"message": "Mangas added to Suwayomi successfully"})
+	addMangasToDownloadIntegration(c, "Suwayomi")
 }
 
 // @Summary Get library stats
@@ -2793,6 +2271,20 @@ func NotifyMangaLastReleasedChapterUpdate(m *manga.Manga) error {
 		ClickURL: chapterLink,
 	}
 
+	// The mark as read button is only added if the action links are enabled
+	if markReadLink, err := actions.MarkReadLink("", m, time.Now()); err == nil {
+		markReadURL, err := url.Parse(markReadLink)
+		if err != nil {
+			return err
+		}
+		msg.Actions = append(msg.Actions, &gotfy.HttpAction[string]{
+			Label:  "Mark as read",
+			URL:    markReadURL,
+			Method: http.MethodPost,
+			Clear:  true,
+		})
+	}
+
 	ctx := context.Background()
 	err = publisher.SendMessage(ctx, msg)
 	if err != nil {
@@ -2802,6 +2294,22 @@ func NotifyMangaLastReleasedChapterUpdate(m *manga.Manga) error {
 	return nil
 }
 
+// setCustomMangaUnreadChapters sets the custom manga unread chapters.
+// A custom manga's last read chapter is the next chapter to read, so it
+// has one unread chapter until the user sets it has no more chapters.
+// If it has a chapter list, the unread chapters are estimated from the
+// last chapter of the list, which is its last released chapter. Without a last
+// read chapter, the count can't be estimated, so it has one unread chapter.
+func setCustomMangaUnreadChapters(m *manga.Manga) {
+	if m.LastReleasedChapter == nil && m.LastReadChapter != nil {
+		m.UnreadChapters = 1
+	} else if unreadChapters, ok := manga.EstimateUnreadChapters(m.LastReleasedChapter, m.LastReadChapter); ok {
+		m.UnreadChapters = unreadChapters
+	} else {
+		m.UnreadChapters = 1
+	}
+}
+
 func isNewChapterDifferentFromOld(oldChapter *manga.Chapter, newChapter *manga.Chapter) bool {
 	if oldChapter == nil && newChapter != nil {
 		return true
@@ -2927,9 +2435,10 @@ func getCheckFixOutOfSyncChaptersActiveWaitingJobs(kaizoku *kaizoku.Kaizoku) (in
 }
 
 // updateMultiMangaMetadata gets the manga metadata from the sources for all the multimanga' mangas and updates it in the database.
+// The mangas' last released chapters are chosen using their chapter preferences, by manga ID.
 // Returns the updated current manga if the current manga has a new released chapter, else nil.
 // Also returns a bool indicating if any metadata was updated and a slice of errors.
-func updateMultiMangaMetadata(multimanga *manga.MultiManga, retries int, retryInterval time.Duration, logger *zerolog.Logger) (*manga.Manga, bool, []string) {
+func updateMultiMangaMetadata(ctx context.Context, multimanga *manga.MultiManga, chapterPreferences map[manga.ID]*manga.ChapterPreferences, retries int, retryInterval time.Duration, logger *zerolog.Logger) (*manga.Manga, bool, []string) {
 	var err error
 	var errors []string
 	var newMetadata bool
@@ -2938,7 +2447,7 @@ func updateMultiMangaMetadata(multimanga *manga.MultiManga, retries int, retryIn
 	for _, mangaToUpdate := range multimanga.Mangas {
 		var updatedManga *manga.Manga
 		for i := 0; i < retries; i++ {
-			updatedManga, err = sources.GetMangaMetadata(mangaToUpdate.URL, mangaToUpdate.InternalID)
+			updatedManga, err = sources.GetMangaMetadata(ctx, mangaToUpdate.URL, mangaToUpdate.InternalID, chapterPreferences[mangaToUpdate.ID])
 			if err != nil {
 				if i != retries-1 {
 					logger.Error().Err(err).Str("manga_url", mangaToUpdate.URL).Msgf("Error getting manga metadata, retrying in %.2f seconds...", retryInterval.Seconds())
@@ -2990,6 +2499,14 @@ func updateMultiMangaMetadata(multimanga *manga.MultiManga, retries int, retryIn
 				continue
 			}
 			newMetadata = true
+
+			if mangaHasNewReleasedChapter && updatedManga.LastReleasedChapter != nil {
+				err = multimanga.SyncChaptersInDB([]*manga.Chapter{updatedManga.LastReleasedChapter})
+				if err != nil {
+					logger.Error().Err(err).Str("manga_url", mangaToUpdate.URL).Msg("Manga metadata updated in DB, but error storing the new chapter in the multimanga chapters")
+					errors = append(errors, err.Error())
+				}
+			}
 		}
 	}
 	if mangasHaveNewChapter {
@@ -0,0 +1,313 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/diogovalentte/mantium/api/src/dashboard"
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/sources"
+)
+
+// @Summary Get multimanga chapters read state
+// @Description Gets the multimanga stored chapters with their read state, sorted from the first to the last chapter. It also returns the unread chapters count and the reread count.
+// @Produce json
+// @Param id query int true "Multimanga ID" Example(1)
+// @Param sync query bool false "Sync the stored chapters with the current manga's chapters from the source first" Example(true)
+// @Success 200 {array} manga.ReadChapter "{"chapters": [readChapterObj], "unread_chapters": 0, "reread_count": 0}"
+// @Router /multimanga/read_chapters [get]
+func GetMultiMangaReadChapters(c *gin.Context) {
+	multimanga, ok := getMultiMangaFromQuery(c)
+	if !ok {
+		return
+	}
+
+	if !syncMultiMangaChaptersIfRequested(c, multimanga) {
+		return
+	}
+
+	chapters, err := multimanga.GetChaptersFromDB()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	var unread int
+	for _, chapter := range chapters {
+		if !chapter.Read {
+			unread++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"chapters": chapters, "unread_chapters": unread, "reread_count": multimanga.RereadCount})
+}
+
+// @Summary Mark multimanga chapters
+// @Description Marks specific multimanga chapters as read or unread, like an out-of-order special chapter. The chapters must be stored, use the sync query to store them first. The multimanga last read chapter is set to the last read chapter.
+// @Accept json
+// @Produce json
+// @Param id query int true "Multimanga ID" Example(1)
+// @Param sync query bool false "Sync the stored chapters with the current manga's chapters from the source first" Example(true)
+// @Param chapters body MarkChaptersRequest true "Chapters to mark"
+// @Success 200 {object} responseMessage
+// @Router /multimanga/read_chapters [patch]
+func MarkMultiMangaChapters(c *gin.Context) {
+	var requestData MarkChaptersRequest
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "invalid JSON fields, refer to the API documentation"})
+		return
+	}
+
+	multimanga, ok := getMultiMangaFromQuery(c)
+	if !ok {
+		return
+	}
+
+	if !syncMultiMangaChaptersIfRequested(c, multimanga) {
+		return
+	}
+
+	previousChapter := multimanga.LastReadChapter
+	err := multimanga.MarkChaptersInDB(requestData.Chapters, *requestData.Read, time.Now().Truncate(time.Second))
+	if err != nil {
+		respondMarkChaptersError(c, err)
+		return
+	}
+	recordMultiMangaReadingActivity(c.Request.Context(), multimanga, previousChapter)
+
+	dashboard.UpdateDashboard()
+
+	c.JSON(http.StatusOK, gin.H{"message": "Multimanga chapters updated successfully"})
+}
+
+// MarkChaptersRequest is the request body for marking chapters as read or unread
+type MarkChaptersRequest struct {
+	Read     *bool    `json:"read" binding:"required"`
+	Chapters []string `json:"chapters" binding:"required,min=1"`
+}
+
+// @Summary Mark multimanga chapters range
+// @Description Marks all multimanga chapters from from_chapter to to_chapter (inclusive) as read or unread. The chapters must be stored, use the sync query to store them first. The multimanga last read chapter is set to the last read chapter.
+// @Accept json
+// @Produce json
+// @Param id query int true "Multimanga ID" Example(1)
+// @Param sync query bool false "Sync the stored chapters with the current manga's chapters from the source first" Example(true)
+// @Param range body MarkChapterRangeRequest true "Chapters range to mark"
+// @Success 200 {object} responseMessage
+// @Router /multimanga/read_chapters/range [patch]
+func MarkMultiMangaChapterRange(c *gin.Context) {
+	var requestData MarkChapterRangeRequest
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "invalid JSON fields, refer to the API documentation"})
+		return
+	}
+
+	multimanga, ok := getMultiMangaFromQuery(c)
+	if !ok {
+		return
+	}
+
+	if !syncMultiMangaChaptersIfRequested(c, multimanga) {
+		return
+	}
+
+	previousChapter := multimanga.LastReadChapter
+	err := multimanga.MarkChapterRangeInDB(requestData.FromChapter, requestData.ToChapter, *requestData.Read, time.Now().Truncate(time.Second))
+	if err != nil {
+		respondMarkChaptersError(c, err)
+		return
+	}
+	recordMultiMangaReadingActivity(c.Request.Context(), multimanga, previousChapter)
+
+	dashboard.UpdateDashboard()
+
+	c.JSON(http.StatusOK, gin.H{"message": "Multimanga chapters updated successfully"})
+}
+
+// MarkChapterRangeRequest is the request body for marking a range of chapters as read or unread
+type MarkChapterRangeRequest struct {
+	Read        *bool  `json:"read" binding:"required"`
+	FromChapter string `json:"from_chapter" binding:"required"`
+	ToChapter   string `json:"to_chapter" binding:"required"`
+}
+
+// @Summary Mark multimanga chapters read up to a chapter
+// @Description Marks all multimanga chapters up to the chapter (inclusive) as read. The chapters after it keep their read state, unless `unmark_after` is true. The chapter must be stored, use the sync query to store it first.
+// @Accept json
+// @Produce json
+// @Param id query int true "Multimanga ID" Example(1)
+// @Param unmark_after query bool false "Mark the chapters after the chapter as unread, so it becomes the multimanga last read chapter" Example(false)
+// @Param sync query bool false "Sync the stored chapters with the current manga's chapters from the source first" Example(true)
+// @Param chapter body MarkChaptersReadUpToRequest true "Last chapter to mark as read"
+// @Success 200 {object} responseMessage
+// @Router /multimanga/read_chapters/up_to [patch]
+func MarkMultiMangaChaptersReadUpTo(c *gin.Context) {
+	var requestData MarkChaptersReadUpToRequest
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "invalid JSON fields, refer to the API documentation"})
+		return
+	}
+
+	unmarkAfter, err := strconv.ParseBool(c.DefaultQuery("unmark_after", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "unmark_after must be a boolean"})
+		return
+	}
+
+	multimanga, ok := getMultiMangaFromQuery(c)
+	if !ok {
+		return
+	}
+
+	if !syncMultiMangaChaptersIfRequested(c, multimanga) {
+		return
+	}
+
+	chapters, err := multimanga.GetChaptersFromDB()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	var chapter *manga.Chapter
+	for _, storedChapter := range chapters {
+		if storedChapter.Chapter == requestData.Chapter {
+			chapter = &manga.Chapter{
+				URL:        storedChapter.URL,
+				Chapter:    storedChapter.Chapter,
+				Name:       storedChapter.Name,
+				InternalID: storedChapter.InternalID,
+				UpdatedAt:  storedChapter.ReleasedAt,
+				Type:       1,
+			}
+			break
+		}
+	}
+	if chapter == nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": errordefs.ErrChapterNotFoundDB.Error()})
+		return
+	}
+
+	previousChapter := multimanga.LastReadChapter
+	err = multimanga.MarkChaptersReadUpToInDB(chapter, time.Now().Truncate(time.Second), unmarkAfter)
+	if err != nil {
+		respondMarkChaptersError(c, err)
+		return
+	}
+	recordMultiMangaReadingActivity(c.Request.Context(), multimanga, previousChapter)
+
+	dashboard.UpdateDashboard()
+
+	c.JSON(http.StatusOK, gin.H{"message": "Multimanga chapters updated successfully"})
+}
+
+// MarkChaptersReadUpToRequest is the request body for marking all chapters up to a chapter as read
+type MarkChaptersReadUpToRequest struct {
+	Chapter string `json:"chapter" binding:"required"`
+}
+
+// @Summary Start multimanga reread
+// @Description Increments the multimanga reread count and marks all its stored chapters as unread. The chapters keep their read count.
+// @Produce json
+// @Param id query int true "Multimanga ID" Example(1)
+// @Success 200 {object} responseMessage
+// @Router /multimanga/reread [post]
+func StartMultiMangaReread(c *gin.Context) {
+	multimanga, ok := getMultiMangaFromQuery(c)
+	if !ok {
+		return
+	}
+
+	err := multimanga.StartRereadInDB()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	dashboard.UpdateDashboard()
+
+	c.JSON(http.StatusOK, gin.H{"message": "Multimanga reread started successfully"})
+}
+
+// getMultiMangaFromQuery gets the multimanga from the id query.
+// If it returns false, the response was already sent.
+func getMultiMangaFromQuery(c *gin.Context) (*manga.MultiManga, bool) {
+	multimangaIDStr := c.Query("id")
+	if multimangaIDStr == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "id must be provided"})
+		return nil, false
+	}
+	multimangaID, err := strconv.Atoi(multimangaIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "id must be a number"})
+		return nil, false
+	}
+
+	multimanga, err := manga.GetMultiMangaFromDBContext(c.Request.Context(), manga.ID(multimangaID))
+	if err != nil {
+		if strings.Contains(err.Error(), errordefs.ErrMultiMangaNotFoundDB.Error()) {
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return nil, false
+	}
+
+	return multimanga, true
+}
+
+// syncMultiMangaChaptersIfRequested stores the current manga's chapters from the source
+// in the multimanga chapters if the sync query is true.
+// If it returns false, the response was already sent.
+func syncMultiMangaChaptersIfRequested(c *gin.Context, multimanga *manga.MultiManga) bool {
+	syncStr := c.DefaultQuery("sync", "false")
+	sync, err := strconv.ParseBool(syncStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "sync must be a boolean"})
+		return false
+	}
+	if !sync {
+		return true
+	}
+
+	preferences, err := manga.GetChapterPreferencesDB(multimanga.CurrentManga.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return false
+	}
+	chapters, err := sources.GetMangaChapters(c.Request.Context(), multimanga.CurrentManga.URL, multimanga.CurrentManga.InternalID, preferences)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return false
+	}
+
+	err = multimanga.SyncChaptersInDB(chapters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return false
+	}
+
+	return true
+}
+
+func respondMarkChaptersError(c *gin.Context, err error) {
+	if strings.Contains(err.Error(), errordefs.ErrChapterNotFoundDB.Error()) {
+		c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+}
+
+// recordMultiMangaReadingActivity records the reading activity
+// if the multimanga last read chapter changed.
+func recordMultiMangaReadingActivity(ctx context.Context, multimanga *manga.MultiManga, previousChapter *manga.Chapter) {
+	if multimanga.LastReadChapter == nil {
+		return
+	}
+	recordReadingActivity(ctx, multimanga.ID, multimanga.CurrentManga, multimanga.LastReadChapter, previousChapter, time.Time{})
+}
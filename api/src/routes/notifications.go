@@ -0,0 +1,370 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+
+	"github.com/diogovalentte/mantium/api/src/config"
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/notifications"
+	"github.com/diogovalentte/mantium/api/src/telemetry"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// notificationRulesResponse is the response of the GetNotificationRules route.
+type notificationRulesResponse struct {
+	Rules *notifications.Rules `json:"rules"`
+	// EffectiveRules are the rules with the null fields inherited from the global rules.
+	EffectiveRules *notifications.Rules `json:"effective_rules"`
+}
+
+// notificationsQueueResponse is the response of the GetNotificationsQueue route.
+type notificationsQueueResponse struct {
+	Queue []*notifications.QueuedNotification `json:"queue"`
+}
+
+// notificationsDigestResponse is the response of the GetNotificationsDigest route.
+type notificationsDigestResponse struct {
+	Digest       *notifications.Digest `json:"digest"`
+	NextDigestAt time.Time             `json:"next_digest_at"`
+}
+
+// NotificationsRoutes sets the notification rules routes
+func NotificationsRoutes(group *gin.RouterGroup) {
+	{
+		group.GET("/notifications/rules", GetNotificationRules)
+		group.PUT("/notifications/rules", UpdateNotificationRules)
+		group.DELETE("/notifications/rules", DeleteNotificationRules)
+		group.GET("/notifications/queue", GetNotificationsQueue)
+		group.POST("/notifications/queue/send", SendDueNotifications)
+		group.GET("/notifications/digest", GetNotificationsDigest)
+	}
+}
+
+// @Summary Get notification rules
+// @Description Gets the notification rules of a multimanga or the global rules if the multimanga_id is not provided. The multimanga rules' null fields inherit the global rules, the effective rules are returned in the effective_rules field.
+// @Produce json
+// @Param multimanga_id query int false "Multimanga ID" Example(1)
+// @Success 200 {object} notificationRulesResponse
+// @Router /notifications/rules [get]
+func GetNotificationRules(c *gin.Context) {
+	multiMangaID, ok := getNotificationRulesMultiMangaID(c)
+	if !ok {
+		return
+	}
+
+	rules, err := notifications.GetRules(multiMangaID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	effectiveRules, _, err := notifications.GetEffectiveRules(multiMangaID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, notificationRulesResponse{Rules: rules, EffectiveRules: effectiveRules})
+}
+
+// @Summary Update notification rules
+// @Description Updates the notification rules of a multimanga or the global rules if the multimanga_id is not provided. For the global rules, null fields keep their current value. For a multimanga, null fields inherit the global rules. The quiet hours and digest time must be like HH:MM, set the quiet hours to empty strings to disable them. The digest schedule (digest_frequency, digest_weekday, and digest_time) is only set in the global rules.
+// @Accept json
+// @Produce json
+// @Param multimanga_id query int false "Multimanga ID" Example(1)
+// @Param rules body notifications.Rules true "Notification rules"
+// @Success 200 {object} responseMessage
+// @Router /notifications/rules [put]
+func UpdateNotificationRules(c *gin.Context) {
+	multiMangaID, ok := getNotificationRulesMultiMangaID(c)
+	if !ok {
+		return
+	}
+
+	var rules notifications.Rules
+	if err := c.ShouldBindJSON(&rules); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "invalid JSON fields, refer to the API documentation"})
+		return
+	}
+	rules.MultiMangaID = multiMangaID
+	if multiMangaID != 0 {
+		rules.DigestFrequency = nil
+		rules.DigestWeekday = nil
+		rules.DigestTime = nil
+	}
+
+	if err := rules.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	err := notifications.SaveRules(&rules)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Notification rules updated successfully"})
+}
+
+// @Summary Delete multimanga notification rules
+// @Description Deletes the notification rules of a multimanga, so it uses the global rules.
+// @Produce json
+// @Param multimanga_id query int true "Multimanga ID" Example(1)
+// @Success 200 {object} responseMessage
+// @Router /notifications/rules [delete]
+func DeleteNotificationRules(c *gin.Context) {
+	multiMangaID, ok := getNotificationRulesMultiMangaID(c)
+	if !ok {
+		return
+	}
+	if multiMangaID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "multimanga_id must be provided, the global rules can't be deleted"})
+		return
+	}
+
+	err := notifications.DeleteRules(multiMangaID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Notification rules deleted successfully"})
+}
+
+// @Summary Get notifications queue
+// @Description Gets the notifications deferred by the notification rules, like by the quiet hours or the minimum gap. They're sent periodically when they're due.
+// @Produce json
+// @Success 200 {object} notificationsQueueResponse
+// @Router /notifications/queue [get]
+func GetNotificationsQueue(c *gin.Context) {
+	queue, err := notifications.GetQueue(false, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, notificationsQueueResponse{Queue: queue})
+}
+
+// @Summary Send due notifications
+// @Description Sends the notifications in the queue that are due and the digest if it's due. It's called periodically by the API, see NOTIFICATIONS_SEND_QUEUE_MINUTES. If it fails to send a notification, it will continue with the next one.
+// @Produce json
+// @Success 200 {object} responseMessage
+// @Router /notifications/queue/send [post]
+func SendDueNotifications(c *gin.Context) {
+	logger := util.GetLogger(zerolog.Level(config.GlobalConfigs.API.LogLevelInt))
+	errors := sendDueQueuedNotifications(3, 3*time.Second, logger)
+	if len(errors) > 0 {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "some errors occured while sending the due notifications, check the logs for more information", "errors": errors})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Due notifications sent successfully"})
+}
+
+// @Summary Get notifications digest
+// @Description Gets the new chapters buffered for the next digest, grouped by multimanga, and when the digest is scheduled. The digest is sent periodically when it's due.
+// @Produce json
+// @Param format query string false "Render the digest with a notifier template instead of returning it as JSON" Enums(ntfy, email, webhook)
+// @Success 200 {object} notificationsDigestResponse
+// @Router /notifications/digest [get]
+func GetNotificationsDigest(c *gin.Context) {
+	templates := map[string]string{
+		"ntfy":    notifications.NtfyDigestTemplate,
+		"email":   notifications.EmailDigestTemplate,
+		"webhook": notifications.WebhookDigestTemplate,
+	}
+	format := c.Query("format")
+	templateName, ok := templates[format]
+	if format != "" && !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "format must be ntfy, email, or webhook"})
+		return
+	}
+
+	global, err := notifications.GetGlobalRules()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	events, err := notifications.GetDigestEvents()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	now := time.Now()
+	from := now
+	if len(events) > 0 {
+		from = events[0].CreatedAt
+	}
+	digest := notifications.BuildDigest(events, from, now)
+
+	if format != "" {
+		rendered, err := notifications.RenderDigest(templateName, digest)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			return
+		}
+		contentTypes := map[string]string{"ntfy": "text/markdown; charset=utf-8", "email": "text/html; charset=utf-8", "webhook": "application/json"}
+		c.Data(http.StatusOK, contentTypes[format], []byte(rendered))
+		return
+	}
+
+	c.JSON(http.StatusOK, notificationsDigestResponse{Digest: digest, NextDigestAt: global.NextDigestTime(now)})
+}
+
+// getNotificationRulesMultiMangaID gets the multimanga ID from the multimanga_id query.
+// It returns 0 for the global rules. If it returns false, the response was already sent.
+func getNotificationRulesMultiMangaID(c *gin.Context) (manga.ID, bool) {
+	multiMangaIDStr := c.Query("multimanga_id")
+	if multiMangaIDStr == "" {
+		return 0, true
+	}
+	multiMangaID, err := strconv.Atoi(multiMangaIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "multimanga_id must be a number"})
+		return 0, false
+	}
+
+	_, err = manga.GetMultiMangaFromDB(manga.ID(multiMangaID))
+	if err != nil {
+		if strings.Contains(err.Error(), errordefs.ErrMultiMangaNotFoundDB.Error()) {
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+			return 0, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return 0, false
+	}
+
+	return manga.ID(multiMangaID), true
+}
+
+// notifyMangaWithRules evaluates the manga's multimanga notification rules and
+// notifies the manga last released chapter, defers it to the notifications queue, or skips it.
+func notifyMangaWithRules(m *manga.Manga, previousChapter *manga.Chapter, retries int, retryInterval time.Duration, logger *zerolog.Logger) error {
+	rules, lastNotifiedAt, err := notifications.GetEffectiveRules(m.MultiMangaID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	decision := rules.Evaluate(m.LastReleasedChapter, lastNotifiedAt, now)
+	switch decision.Action {
+	case notifications.ActionSkip:
+		logger.Debug().Str("manga_url", m.URL).Str("reason", decision.Reason).Msg("Skipping manga new chapter notification")
+		return nil
+	case notifications.ActionDefer:
+		logger.Debug().Str("manga_url", m.URL).Str("reason", decision.Reason).Time("deliver_after", decision.DeliverAfter).Msg("Deferring manga new chapter notification")
+		if decision.Reason == notifications.ReasonDigest {
+			return notifications.BufferDigestEvent(m, previousChapter, now)
+		}
+		return notifications.Enqueue(m, decision, now)
+	}
+
+	err = retryNotification(func() error { return NotifyMangaLastReleasedChapterUpdate(m) }, retries, retryInterval, logger, m.URL)
+	if err != nil {
+		return err
+	}
+
+	return notifications.SetLastNotifiedAt(m.MultiMangaID, now)
+}
+
+// sendDueQueuedNotificationsMutex prevents the periodic job and the mangas metadata
+// update from sending the same queued notification at the same time.
+var sendDueQueuedNotificationsMutex = &sync.Mutex{}
+
+// sendDueQueuedNotifications sends the notifications in the queue that are due
+// and the digest if it's due. Notifications of multimangas that were muted
+// after the notification was queued are dropped.
+func sendDueQueuedNotifications(retries int, retryInterval time.Duration, logger *zerolog.Logger) []string {
+	sendDueQueuedNotificationsMutex.Lock()
+	defer sendDueQueuedNotificationsMutex.Unlock()
+
+	errors := []string{}
+	now := time.Now()
+
+	queue, err := notifications.GetQueue(true, now)
+	if err != nil {
+		return append(errors, err.Error())
+	}
+
+	var doneIDs []int
+	for _, queued := range queue {
+		rules, _, err := notifications.GetEffectiveRules(queued.MultiMangaID)
+		if err != nil {
+			errors = append(errors, err.Error())
+			continue
+		}
+		if rules.Mute != nil && *rules.Mute {
+			doneIDs = append(doneIDs, queued.ID)
+			continue
+		}
+
+		m := &manga.Manga{
+			ID:           queued.MangaID,
+			Name:         queued.MangaName,
+			URL:          queued.MangaURL,
+			MultiMangaID: queued.MultiMangaID,
+			LastReleasedChapter: &manga.Chapter{
+				Chapter: queued.Chapter,
+				Name:    queued.ChapterName,
+				URL:     queued.ChapterURL,
+				Type:    1,
+			},
+		}
+		err = retryNotification(func() error { return NotifyMangaLastReleasedChapterUpdate(m) }, retries, retryInterval, logger, m.URL)
+		if err != nil {
+			errors = append(errors, err.Error())
+			continue
+		}
+		doneIDs = append(doneIDs, queued.ID)
+		err = notifications.SetLastNotifiedAt(queued.MultiMangaID, now)
+		if err != nil {
+			errors = append(errors, err.Error())
+		}
+	}
+
+	err = notifications.DeleteFromQueue(doneIDs)
+	if err != nil {
+		errors = append(errors, err.Error())
+	}
+
+	sent, err := notifications.SendDigestIfDue(notifications.GetDigestNotifiers(), retries, retryInterval, now)
+	if err != nil {
+		logger.Error().Err(err).Msg("Error sending the digest, will try again in the next update")
+		errors = append(errors, err.Error())
+	} else if sent {
+		logger.Info().Msg("Digest sent")
+	}
+
+	return errors
+}
+
+func retryNotification(notify func() error, retries int, retryInterval time.Duration, logger *zerolog.Logger, mangaURL string) error {
+	var err error
+	for j := range retries {
+		err = notify()
+		if err == nil {
+			telemetry.ObserveNotification("ntfy", nil)
+			return nil
+		}
+		if j == retries-1 {
+			logger.Error().Err(err).Str("manga_url", mangaURL).Msg(fmt.Sprintf("Manga metadata updated in DB, but error while notifying: %s.\nWill continue with the next manga...", err.Error()))
+			telemetry.ObserveNotification("ntfy", err)
+			break
+		}
+		logger.Error().Err(err).Str("manga_url", mangaURL).Msgf("Manga metadata updated in DB, but error while notifying: %s.\nRetrying in %.2f seconds...", err.Error(), retryInterval.Seconds())
+		time.Sleep(retryInterval)
+	}
+
+	return err
+}
@@ -0,0 +1,234 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/opds"
+)
+
+// OPDSRoutes sets the OPDS catalog routes
+func OPDSRoutes(group *gin.RouterGroup) {
+	{
+		group.GET("/opds", GetOPDSCatalog)
+		group.GET("/opds/v1.2/catalog", GetOPDSCatalog)
+		group.GET("/opds/v1.2/multimanga", GetOPDSMultiManga)
+		group.GET("/opds/v2.0/catalog", GetOPDSCatalogV2)
+		group.GET("/opds/v2.0/multimanga", GetOPDSMultiMangaV2)
+		group.GET("/opds/cover", GetOPDSCover)
+		group.GET("/opds/archive", GetOPDSArchive)
+	}
+}
+
+// @Summary OPDS 1.2 catalog
+// @Description Returns the OPDS 1.2 navigation feed with the multimangas. Each entry links to the multimanga feed with its downloaded chapter archives. It's used by e-readers like KOReader, Panels, and Chunky.
+// @Produce xml
+// @Param api_url query string false "API URL used in the feed links. Defaults to the request URL." Example(https://sub.domain.com)
+// @Success 200 {string} string "OPDS feed"
+// @Router /opds/v1.2/catalog [get]
+func GetOPDSCatalog(c *gin.Context) {
+	multimangas, err := manga.GetMultiMangasDB(false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	content, err := getOPDSCatalog(c).RootV1(multimangas)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.Data(http.StatusOK, opds.NavigationContentType, content)
+}
+
+// @Summary OPDS 1.2 multimanga feed
+// @Description Returns the OPDS 1.2 acquisition feed with the multimanga downloaded chapter archives, newest first. The archives are from the OPDS_ARCHIVES_DIR directory or, if not set, proxied from Suwayomi.
+// @Produce xml
+// @Param id query int true "Multimanga ID" Example(1)
+// @Param api_url query string false "API URL used in the feed links. Defaults to the request URL." Example(https://sub.domain.com)
+// @Success 200 {string} string "OPDS feed"
+// @Router /opds/v1.2/multimanga [get]
+func GetOPDSMultiManga(c *gin.Context) {
+	multimanga, archives, statusCode, err := getOPDSMultiMangaArchives(c)
+	if err != nil {
+		c.JSON(statusCode, gin.H{"message": err.Error()})
+		return
+	}
+
+	content, err := getOPDSCatalog(c).MultiMangaV1(multimanga, archives)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.Data(http.StatusOK, opds.AcquisitionContentType, content)
+}
+
+// @Summary OPDS 2.0 catalog
+// @Description Returns the OPDS 2.0 navigation feed with the multimangas. Each link goes to the multimanga feed with its downloaded chapter archives.
+// @Produce json
+// @Param api_url query string false "API URL used in the feed links. Defaults to the request URL." Example(https://sub.domain.com)
+// @Success 200 {string} string "OPDS feed"
+// @Router /opds/v2.0/catalog [get]
+func GetOPDSCatalogV2(c *gin.Context) {
+	multimangas, err := manga.GetMultiMangasDB(false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	content, err := getOPDSCatalog(c).RootV2(multimangas)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.Data(http.StatusOK, opds.JSONContentType, content)
+}
+
+// @Summary OPDS 2.0 multimanga feed
+// @Description Returns the OPDS 2.0 feed with a publication for each multimanga downloaded chapter archive, newest first. The archives are from the OPDS_ARCHIVES_DIR directory or, if not set, proxied from Suwayomi.
+// @Produce json
+// @Param id query int true "Multimanga ID" Example(1)
+// @Param api_url query string false "API URL used in the feed links. Defaults to the request URL." Example(https://sub.domain.com)
+// @Success 200 {string} string "OPDS feed"
+// @Router /opds/v2.0/multimanga [get]
+func GetOPDSMultiMangaV2(c *gin.Context) {
+	multimanga, archives, statusCode, err := getOPDSMultiMangaArchives(c)
+	if err != nil {
+		c.JSON(statusCode, gin.H{"message": err.Error()})
+		return
+	}
+
+	content, err := getOPDSCatalog(c).MultiMangaV2(multimanga, archives)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.Data(http.StatusOK, opds.JSONContentType, content)
+}
+
+// @Summary OPDS multimanga cover
+// @Description Returns the multimanga cover image.
+// @Produce image/jpeg,image/png
+// @Param id query int true "Multimanga ID" Example(1)
+// @Success 200 {file} file "Cover image"
+// @Router /opds/cover [get]
+func GetOPDSCover(c *gin.Context) {
+	multimanga, statusCode, err := getOPDSMultiManga(c)
+	if err != nil {
+		c.JSON(statusCode, gin.H{"message": err.Error()})
+		return
+	}
+
+	coverImg, coverImgURL := multimanga.CoverImg, multimanga.CoverImgURL
+	if !multimanga.CoverImgFixed && multimanga.CurrentManga != nil {
+		coverImg, coverImgURL = multimanga.CurrentManga.CoverImg, multimanga.CurrentManga.CoverImgURL
+	}
+	if len(coverImg) == 0 {
+		if coverImgURL != "" {
+			c.Redirect(http.StatusFound, coverImgURL)
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{"message": "multimanga has no cover image"})
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=86400")
+	c.Data(http.StatusOK, http.DetectContentType(coverImg), coverImg)
+}
+
+// @Summary OPDS chapter archive
+// @Description Downloads a multimanga chapter archive listed in the multimanga feed.
+// @Produce application/vnd.comicbook+zip
+// @Param id query int true "Multimanga ID" Example(1)
+// @Param archive query string true "Archive ID from the multimanga feed" Example(Berserk/Berserk - Ch. 1.cbz)
+// @Success 200 {file} file "Chapter archive"
+// @Router /opds/archive [get]
+func GetOPDSArchive(c *gin.Context) {
+	provider, err := opds.NewArchiveProvider()
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		return
+	}
+
+	multimanga, statusCode, err := getOPDSMultiManga(c)
+	if err != nil {
+		c.JSON(statusCode, gin.H{"message": err.Error()})
+		return
+	}
+
+	reader, archive, err := provider.Open(multimanga, c.Query("archive"))
+	if err != nil {
+		if strings.Contains(err.Error(), errordefs.ErrOPDSArchiveNotFound.Error()) {
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	defer reader.Close()
+
+	c.DataFromReader(http.StatusOK, archive.Size, archive.ContentType, reader, map[string]string{
+		"Content-Disposition": fmt.Sprintf("attachment; filename=%s", strconv.Quote(archive.FileName)),
+	})
+}
+
+func getOPDSCatalog(c *gin.Context) *opds.Catalog {
+	baseURL := strings.TrimSuffix(c.Query("api_url"), "/")
+	if baseURL == "" {
+		baseURL = getRequestBaseURL(c)
+	}
+
+	return &opds.Catalog{BaseURL: baseURL, Updated: time.Now()}
+}
+
+func getOPDSMultiManga(c *gin.Context) (*manga.MultiManga, int, error) {
+	multimangaIDStr := c.Query("id")
+	if multimangaIDStr == "" {
+		return nil, http.StatusBadRequest, fmt.Errorf("id must be provided")
+	}
+	multimangaID, err := strconv.Atoi(multimangaIDStr)
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("id must be a number")
+	}
+
+	multimanga, err := manga.GetMultiMangaFromDB(manga.ID(multimangaID))
+	if err != nil {
+		if strings.Contains(err.Error(), errordefs.ErrMultiMangaNotFoundDB.Error()) {
+			return nil, http.StatusNotFound, err
+		}
+		return nil, http.StatusInternalServerError, err
+	}
+
+	return multimanga, http.StatusOK, nil
+}
+
+// getOPDSMultiMangaArchives returns the multimanga and its archives.
+// If the archives are disabled, the multimanga has no archives.
+func getOPDSMultiMangaArchives(c *gin.Context) (*manga.MultiManga, []*opds.Archive, int, error) {
+	multimanga, statusCode, err := getOPDSMultiManga(c)
+	if err != nil {
+		return nil, nil, statusCode, err
+	}
+
+	provider, err := opds.NewArchiveProvider()
+	if err != nil {
+		return multimanga, []*opds.Archive{}, http.StatusOK, nil
+	}
+	archives, err := provider.Archives(multimanga)
+	if err != nil {
+		return nil, nil, http.StatusInternalServerError, err
+	}
+
+	return multimanga, archives, http.StatusOK, nil
+}
@@ -0,0 +1,166 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+
+	"github.com/diogovalentte/mantium/api/src/config"
+	"github.com/diogovalentte/mantium/api/src/dashboard"
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/integrations/kavita"
+	"github.com/diogovalentte/mantium/api/src/integrations/komga"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/sources"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// readingIntegration is an integration with a server where the downloaded
+// chapters are read, like Komga and Kavita.
+type readingIntegration interface {
+	// ScanMangasLibraries scans the libraries of the mangas, so the server adds the new chapters.
+	ScanMangasLibraries(mangas []*manga.Manga) error
+	// GetLastReadChapter returns the number of the last chapter read in the server
+	// of the mangas' series, or an empty string if no chapter was read.
+	GetLastReadChapter(mangas []*manga.Manga) (string, error)
+}
+
+// getReadingIntegrations returns the configured reading integrations by name.
+func getReadingIntegrations() map[string]readingIntegration {
+	integrations := map[string]readingIntegration{}
+	if config.GlobalConfigs.Komga.Valid {
+		komgaInt := &komga.Komga{}
+		komgaInt.Init()
+		integrations["komga"] = komgaInt
+	}
+	if config.GlobalConfigs.Kavita.Valid {
+		kavitaInt := &kavita.Kavita{}
+		kavitaInt.Init()
+		integrations["kavita"] = kavitaInt
+	}
+
+	return integrations
+}
+
+// @Summary Sync read progress
+// @Description Sets the multimangas' last read chapter to the last chapter read in Komga and Kavita if it's newer. The multimangas are matched to the series by name, and by the series links in Komga. It's also done when the mangas metadata is updated.
+// @Produce json
+// @Success 200 {object} responseMessage
+// @Router /mangas/sync_read_progress [post]
+func SyncMangasReadProgress(c *gin.Context) {
+	integrations := getReadingIntegrations()
+	if len(integrations) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "no reading integration (Komga or Kavita) is configured in the API"})
+		return
+	}
+
+	multimangas, err := manga.GetMultiMangasDB(true)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	logger := util.GetLogger(zerolog.Level(config.GlobalConfigs.API.LogLevelInt))
+	updated, errors := syncReadProgress(c.Request.Context(), multimangas, integrations, logger)
+	if updated {
+		dashboard.UpdateDashboard()
+	}
+	for _, errSlice := range errors {
+		if len(errSlice) > 0 {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "some errors occured while syncing the read progress, check the logs for more information", "errors": errors})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Read progress synced successfully"})
+}
+
+// syncReadProgress syncs the multimangas' last read chapter from the reading integrations.
+// It returns whether any multimanga was updated and the errors by integration name.
+// The multimangas are synced in parallel, limited by the update mangas parallel jobs,
+// and the integrations one after the other, so a multimanga isn't synced by two
+// integrations at the same time.
+func syncReadProgress(ctx context.Context, multimangas []*manga.MultiManga, integrations map[string]readingIntegration, logger *zerolog.Logger) (bool, map[string][]string) {
+	var updated bool
+	errors := map[string][]string{}
+	for name, integration := range integrations {
+		multimangasUpdated := make([]bool, len(multimangas))
+		multimangasErrors := make([]error, len(multimangas))
+		semaphore := make(chan struct{}, max(1, config.GlobalConfigs.PeriodicallyUpdateMangas.ParallelJobs))
+		var wg sync.WaitGroup
+		for i, multimanga := range multimangas {
+			wg.Add(1)
+			go func(i int, multimanga *manga.MultiManga) {
+				defer wg.Done()
+				semaphore <- struct{}{}
+				defer func() { <-semaphore }()
+
+				multimangasUpdated[i], multimangasErrors[i] = syncMultiMangaReadProgress(ctx, multimanga, integration, time.Now())
+			}(i, multimanga)
+		}
+		wg.Wait()
+
+		errors[name] = []string{}
+		for i, err := range multimangasErrors {
+			if err != nil {
+				logger.Error().Err(err).Str("integration", name).Int("multimanga_id", int(multimangas[i].ID)).Msg("Error syncing multimanga read progress.\nWill continue with the next multimanga...")
+				errors[name] = append(errors[name], err.Error())
+				continue
+			}
+			if multimangasUpdated[i] {
+				updated = true
+			}
+		}
+	}
+
+	return updated, errors
+}
+
+// syncMultiMangaReadProgress sets the multimanga last read chapter to the last chapter read in
+// the reading integration if it's newer. Multimangas not found in the integration are ignored.
+// The chapter is got from the multimanga current manga source, so custom mangas are ignored.
+func syncMultiMangaReadProgress(ctx context.Context, multimanga *manga.MultiManga, integration readingIntegration, currentTime time.Time) (bool, error) {
+	currentManga := multimanga.CurrentManga
+	if currentManga == nil || currentManga.Source == manga.CustomMangaSource {
+		return false, nil
+	}
+
+	chapterNumber, err := integration.GetLastReadChapter(multimanga.Mangas)
+	if err != nil {
+		if util.ErrorContains(err, errordefs.ErrSeriesNotFound.Error()) {
+			return false, nil
+		}
+		return false, err
+	}
+	if chapterNumber == "" {
+		return false, nil
+	}
+	if multimanga.LastReadChapter != nil {
+		// Only chapters that can be compared are synced, so different
+		// numbering schemes don't override the user's progress.
+		cmp, ok := manga.ParseChapterNumber(chapterNumber).Compare(manga.ParseChapterNumber(multimanga.LastReadChapter.Chapter))
+		if !ok || cmp <= 0 {
+			return false, nil
+		}
+	}
+
+	preferences, err := manga.GetChapterPreferencesDB(currentManga.ID)
+	if err != nil {
+		return false, err
+	}
+	chapter, err := sources.GetChapterMetadata(ctx, currentManga.URL, currentManga.InternalID, chapterNumber, "", "", preferences)
+	if err != nil {
+		return false, err
+	}
+	err = setMultiMangaLastReadChapter(ctx, multimanga, currentManga, chapter, currentTime, false)
+	if err != nil {
+		return false, err
+	}
+	multimanga.LastReadChapter = chapter
+
+	return true, nil
+}
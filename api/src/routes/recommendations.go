@@ -0,0 +1,264 @@
+package routes
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/recommendations"
+	"github.com/diogovalentte/mantium/api/src/sources"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+const (
+	defaultRecommendationsLimit = 20
+	defaultRecommendationsSeeds = 10
+	maxRecommendationsSeeds     = 30
+	// recommendationsParallelRequests is the number of seeds whose similar titles are requested in parallel.
+	recommendationsParallelRequests = 3
+)
+
+// recommendationsQueryParams are the query parameters of the GetRecommendations route.
+// The other parameters are rejected instead of ignored.
+var recommendationsQueryParams = map[string]bool{"limit": true, "seeds": true, "tags": true}
+
+// recommendationsResponse is the response of the GetRecommendations route.
+type recommendationsResponse struct {
+	Profile         *recommendations.Profile          `json:"profile"`
+	Recommendations []*recommendations.Recommendation `json:"recommendations"`
+}
+
+// RecommendationsRoutes sets the recommendations routes
+func RecommendationsRoutes(group *gin.RouterGroup) {
+	{
+		group.GET("/recommendations", GetRecommendations)
+		group.POST("/recommendations/add", AddRecommendation)
+	}
+}
+
+// @Summary Get recommendations
+// @Description Recommends titles based on the library. The multimangas with the status reading, completed, on hold, or plan to read from MangaDex, MangaUpdates, or ComicK are used as seeds, from the reading and completed ones to the most recently read. The sources are requested for the titles similar to each seed and the seed genres, which build the profile. The titles already in the library, matched by URL, name, or MangaUpdates ID, aren't recommended. The score of a title is the sum of the weights of the seeds it's similar to (3 for reading and completed, 1 for on hold and plan to read), plus 1 for each of its genres in the profile's top 5 genres. The tags only choose the seeds and aren't weighted in the profile, as all seeds have the tags and the similar titles from the sources have no tags. The multimangas have no rating, so the profile isn't weighted by ratings, and the unsupported query parameters, like ratings, are rejected. This is a heavy operation, as each seed is a request to its source.
+// @Produce json
+// @Param limit query int false "Max number of recommendations. Defaults to 20." Example(20)
+// @Param seeds query int false "Max number of multimangas used as seeds. Defaults to 10, max 30." Example(10)
+// @Param tags query string false "Use only the multimangas with any of the tags as seeds" Example("favorite,weekly")
+// @Success 200 {object} recommendationsResponse
+// @Router /recommendations [get]
+func GetRecommendations(c *gin.Context) {
+	for param := range c.Request.URL.Query() {
+		if recommendationsQueryParams[param] {
+			continue
+		}
+		if strings.HasPrefix(param, "rating") {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "the multimangas have no rating, so the recommendations can't be weighted by ratings"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("unsupported query parameter '%s'", param)})
+		return
+	}
+
+	limit := defaultRecommendationsLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "limit must be a number greater than 0"})
+			return
+		}
+	}
+	seedsLimit := defaultRecommendationsSeeds
+	if seedsStr := c.Query("seeds"); seedsStr != "" {
+		var err error
+		seedsLimit, err = strconv.Atoi(seedsStr)
+		if err != nil || seedsLimit < 1 || seedsLimit > maxRecommendationsSeeds {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "seeds must be a number between 1 and 30"})
+			return
+		}
+	}
+	tags := []string{}
+	for _, tag := range strings.Split(c.Query("tags"), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	multimangas, err := manga.GetMultiMangasDB(true)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	customMangas, err := manga.GetCustomMangasDB()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	libraryMangas := customMangas
+	for _, multimanga := range multimangas {
+		libraryMangas = append(libraryMangas, multimanga.Mangas...)
+	}
+
+	logger := zerolog.Ctx(c.Request.Context())
+	seeds, err := getRecommendationsSeeds(c.Request.Context(), multimangas, tags, seedsLimit, logger)
+	if err != nil {
+		if util.ErrorContains(err, errordefs.ErrNoRecommendationSeeds.Error()) {
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	library := recommendations.NewLibrary(libraryMangas)
+	externalIDs, err := manga.GetAllExternalIDsDB()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	library.AddExternalIDs(externalIDs)
+
+	profile, recommended := recommendations.Rank(seeds, library, limit)
+
+	c.JSON(http.StatusOK, recommendationsResponse{Profile: profile, Recommendations: recommended})
+}
+
+// @Summary Add recommendation
+// @Description Adds a recommended title as a new multimanga with the status plan to read. The manga is added to the download integrations by background jobs, whose IDs are returned; check them in the jobs routes.
+// @Accept json
+// @Produce json
+// @Param recommendation body AddRecommendationRequest true "Recommended title data"
+// @Success 200 {object} responseMessageWithJobs
+// @Router /recommendations/add [post]
+func AddRecommendation(c *gin.Context) {
+	currentTime := time.Now()
+
+	var requestData AddRecommendationRequest
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "invalid JSON fields, refer to the API documentation"})
+		return
+	}
+
+	_, err := manga.GetMangaDBByURL(requestData.URL)
+	if err == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "the recommended title is already in the library"})
+		return
+	}
+	if !util.ErrorContains(err, errordefs.ErrMangaNotFoundDB.Error()) {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	integrationsJobs, statusCode, err := addMultiManga(c.Request.Context(), &AddMangaRequest{
+		URL:             requestData.URL,
+		MangaInternalID: requestData.MangaInternalID,
+		Status:          5,
+	}, currentTime)
+	if err != nil {
+		c.JSON(statusCode, gin.H{"message": err.Error(), "jobs": getJobIDs(integrationsJobs)})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Recommendation added successfully", "jobs": getJobIDs(integrationsJobs)})
+}
+
+// AddRecommendationRequest is the request body for the AddRecommendation route
+type AddRecommendationRequest struct {
+	URL             string `json:"url" binding:"required,http_url"`
+	MangaInternalID string `json:"internal_id"`
+}
+
+// getRecommendationsSeeds chooses the multimangas used as seeds and gets the titles similar to them.
+// The seeds whose similar titles can't be got are skipped, unless all of them fail.
+// The tags are only a filter: every seed has one of them, so weighting the profile
+// by them wouldn't change the scores, and the similar titles have no tags to match.
+func getRecommendationsSeeds(ctx context.Context, multimangas []*manga.MultiManga, tags []string, limit int, logger *zerolog.Logger) ([]*recommendations.Seed, error) {
+	type seedManga struct {
+		multimanga *manga.MultiManga
+		manga      *manga.Manga
+	}
+
+	candidates := []*seedManga{}
+	for _, multimanga := range multimangas {
+		if recommendations.StatusWeights[multimanga.Status] == 0 || !manga.HasAnyTag(multimanga.Tags, tags) {
+			continue
+		}
+		// The current manga is preferred, as it's the one the user sees
+		mangas := append([]*manga.Manga{multimanga.CurrentManga}, multimanga.Mangas...)
+		for _, m := range mangas {
+			if m != nil && sources.HasSimilarTitles(m.URL) {
+				candidates = append(candidates, &seedManga{multimanga: multimanga, manga: m})
+				break
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, errordefs.ErrNoRecommendationSeeds
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		iWeight, jWeight := recommendations.StatusWeights[candidates[i].multimanga.Status], recommendations.StatusWeights[candidates[j].multimanga.Status]
+		if iWeight != jWeight {
+			return iWeight > jWeight
+		}
+		return getLastReadAt(candidates[i].multimanga).After(getLastReadAt(candidates[j].multimanga))
+	})
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+
+	seeds := make([]*recommendations.Seed, len(candidates))
+	errs := make([]error, len(candidates))
+	semaphore := make(chan struct{}, recommendationsParallelRequests)
+	var wg sync.WaitGroup
+	for i, candidate := range candidates {
+		wg.Add(1)
+		go func(i int, candidate *seedManga) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			similarTitles, err := sources.GetSimilarTitles(ctx, candidate.manga.URL, candidate.manga.InternalID)
+			if err != nil {
+				logger.Error().Err(err).Str("manga_url", candidate.manga.URL).Msg("Error getting similar titles, will continue with the next seed...")
+				errs[i] = err
+				return
+			}
+			seeds[i] = &recommendations.Seed{
+				MultiMangaID:  candidate.multimanga.ID,
+				Name:          candidate.multimanga.CurrentManga.Name,
+				Status:        candidate.multimanga.Status,
+				SimilarTitles: similarTitles,
+			}
+		}(i, candidate)
+	}
+	wg.Wait()
+
+	gotSeeds := []*recommendations.Seed{}
+	for _, seed := range seeds {
+		if seed != nil {
+			gotSeeds = append(gotSeeds, seed)
+		}
+	}
+	if len(gotSeeds) == 0 {
+		return nil, util.AddErrorContext("error getting the similar titles of all seeds", errs[0])
+	}
+
+	return gotSeeds, nil
+}
+
+func getLastReadAt(multimanga *manga.MultiManga) time.Time {
+	if multimanga.LastReadChapter == nil {
+		return time.Time{}
+	}
+
+	return multimanga.LastReadChapter.UpdatedAt
+}
@@ -0,0 +1,192 @@
+package routes
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/AnthonyHewins/gotfy"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/integrations/ntfy"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/notifications"
+	"github.com/diogovalentte/mantium/api/src/sources"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// relationsCheckInterval is the minimum interval between the checks of a manga relations,
+// as the relations rarely change and checking them is an extra request to the source.
+const relationsCheckInterval = 24 * time.Hour
+
+// @Summary Add related multimanga
+// @Description Adds a title related to a manga, like a sequel, as a new multimanga. The title must be one of the relations returned by the get multimanga route. The manga is added to the download integrations by background jobs, whose IDs are returned; check them in the jobs routes.
+// @Accept json
+// @Produce json
+// @Param relation body AddRelatedMultiMangaRequest true "Related title data"
+// @Success 200 {object} responseMessageWithJobs
+// @Router /multimanga/relation [post]
+func AddRelatedMultiManga(c *gin.Context) {
+	currentTime := time.Now()
+
+	var requestData AddRelatedMultiMangaRequest
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "invalid JSON fields, refer to the API documentation"})
+		return
+	}
+
+	relation, err := manga.GetMangaRelationDB(manga.ID(requestData.MangaID), requestData.URL)
+	if err != nil {
+		if util.ErrorContains(err, errordefs.ErrRelationNotFoundDB.Error()) {
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	if relation.InLibrary {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "the related title is already in the library"})
+		return
+	}
+
+	status := requestData.Status
+	if status == 0 {
+		status = 5
+	}
+	integrationsJobs, statusCode, err := addMultiManga(c.Request.Context(), &AddMangaRequest{
+		URL:             relation.URL,
+		MangaInternalID: relation.InternalID,
+		Status:          status,
+	}, currentTime)
+	if err != nil {
+		c.JSON(statusCode, gin.H{"message": err.Error(), "jobs": getJobIDs(integrationsJobs)})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Related multimanga added successfully", "jobs": getJobIDs(integrationsJobs)})
+}
+
+// AddRelatedMultiMangaRequest is the request body for the AddRelatedMultiManga route
+type AddRelatedMultiMangaRequest struct {
+	// URL is the related title URL.
+	URL string `json:"url" binding:"required,http_url"`
+	// MangaID is the ID of the manga the title is related to.
+	MangaID int `json:"manga_id" binding:"required"`
+	// Status is the new multimanga status. Defaults to 5 (plan to read).
+	Status int `json:"status" binding:"gte=0,lte=5"`
+}
+
+// updateMultiMangaRelations gets the titles related to the multimanga mangas from the
+// sources that list them and stores them in the database. The mangas whose relations were
+// checked less than relationsCheckInterval ago are skipped.
+// It returns the new relations of titles that follow the mangas story, like sequels.
+func updateMultiMangaRelations(ctx context.Context, multimanga *manga.MultiManga, relationsCheckedAt map[manga.ID]time.Time, retries int, retryInterval time.Duration, logger *zerolog.Logger) ([]*manga.Relation, []string) {
+	var newRelations []*manga.Relation
+	var errors []string
+	currentTime := time.Now().Truncate(time.Second)
+
+	for _, m := range multimanga.Mangas {
+		if !sources.HasRelations(m.URL) {
+			continue
+		}
+		if checkedAt, ok := relationsCheckedAt[m.ID]; ok && currentTime.Sub(checkedAt) < relationsCheckInterval {
+			continue
+		}
+
+		var relations []*manga.Relation
+		var err error
+		for i := 0; i < retries; i++ {
+			relations, err = sources.GetMangaRelations(ctx, m.URL, m.InternalID)
+			if err == nil {
+				break
+			}
+			if i != retries-1 {
+				logger.Error().Err(err).Str("manga_url", m.URL).Msgf("Error getting manga relations, retrying in %.2f seconds...", retryInterval.Seconds())
+				time.Sleep(retryInterval)
+			}
+		}
+		if err != nil {
+			logger.Error().Err(err).Str("manga_url", m.URL).Msg("Error getting manga relations, will continue with the next manga...")
+			errors = append(errors, err.Error())
+			continue
+		}
+
+		mangaNewRelations, err := manga.SyncMangaRelationsDB(m.ID, relations, currentTime)
+		if err != nil {
+			logger.Error().Err(err).Str("manga_url", m.URL).Msg("Error saving manga relations to DB, will continue with the next manga...")
+			errors = append(errors, err.Error())
+			continue
+		}
+		for _, relation := range mangaNewRelations {
+			if relation.IsFollowUp() {
+				newRelations = append(newRelations, relation)
+			}
+		}
+	}
+
+	return newRelations, errors
+}
+
+// notifyNewRelations notifies the new titles related to a manga if
+// the manga's multimanga notifications aren't muted.
+func notifyNewRelations(m *manga.Manga, relations []*manga.Relation, retries int, retryInterval time.Duration, logger *zerolog.Logger) error {
+	rules, _, err := notifications.GetEffectiveRules(m.MultiMangaID)
+	if err != nil {
+		return err
+	}
+	if rules.Mute != nil && *rules.Mute {
+		return nil
+	}
+
+	return retryNotification(func() error { return NotifyMangaNewRelations(m, relations) }, retries, retryInterval, logger, m.URL)
+}
+
+// NotifyMangaNewRelations notifies the new titles related to a manga, like a sequel
+func NotifyMangaNewRelations(m *manga.Manga, relations []*manga.Relation) error {
+	publisher, err := ntfy.GetNtfyPublisher()
+	if err != nil {
+		return err
+	}
+
+	title := fmt.Sprintf("(Mantium) New related title of manga: %s", m.Name)
+
+	lines := make([]string, 0, len(relations))
+	actions := []gotfy.ActionButton{}
+	for _, relation := range relations {
+		relationType := strings.ReplaceAll(relation.Type, "_", " ")
+		lines = append(lines, fmt.Sprintf("New %s: %s", relationType, relation.Name))
+
+		// Ntfy allows up to 3 actions
+		if len(actions) < 3 {
+			relationLink, err := url.Parse(relation.URL)
+			if err != nil {
+				return err
+			}
+			actions = append(actions, &gotfy.ViewAction{
+				Label: fmt.Sprintf("Open %s", relationType),
+				Link:  relationLink,
+				Clear: false,
+			})
+		}
+	}
+
+	msg := &gotfy.Message{
+		Topic:   publisher.Topic,
+		Title:   title,
+		Message: strings.Join(lines, "\n"),
+		Actions: actions,
+	}
+
+	ctx := context.Background()
+	err = publisher.SendMessage(ctx, msg)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -4,3 +4,10 @@ package routes
 type responseMessage struct {
 	Message string `json:"message"`
 }
+
+// responseMessageWithJobs is the response of the routes that enqueue background jobs.
+type responseMessageWithJobs struct {
+	Message string `json:"message"`
+	// Jobs are the IDs of the enqueued jobs
+	Jobs []int `json:"jobs"`
+}
@@ -0,0 +1,105 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/sources"
+	"github.com/diogovalentte/mantium/api/src/sources/models"
+	"github.com/diogovalentte/mantium/api/src/sources/scraper"
+)
+
+// SourcesRoutes sets the sources routes
+func SourcesRoutes(group *gin.RouterGroup) {
+	{
+		group.POST("/sources/scraper/validate", ValidateScraperSource)
+	}
+}
+
+// ValidateScraperSourceRequest is the request body for the ValidateScraperSource route.
+type ValidateScraperSourceRequest struct {
+	Config *scraper.Config `json:"config" binding:"required"`
+	// URL is the URL of a manga in the source.
+	URL string `json:"url" binding:"required,http_url"`
+	// SearchTerm is searched if provided.
+	SearchTerm string `json:"search_term"`
+}
+
+// ValidateScraperSourceResult is what the scraper source config found in the manga and search pages.
+type ValidateScraperSourceResult struct {
+	LastReleasedChapter *manga.Chapter              `json:"last_released_chapter"`
+	Name                string                      `json:"name"`
+	CoverImgURL         string                      `json:"cover_img_url"`
+	Chapters            []*manga.Chapter            `json:"chapters"`
+	SearchResults       []*models.MangaSearchResult `json:"search_results"`
+	// Errors are the errors of each step: manga, chapters, and search.
+	Errors map[string]string `json:"errors"`
+}
+
+// @Summary Validate scraper source
+// @Description Validates a scraper source config and test-runs it with a manga URL without registering it. It returns the manga and chapters found in the manga page, the search results if search_term is provided, and the error of each step. The config can't have the name of a built-in source.
+// @Accept json
+// @Produce json
+// @Param request body ValidateScraperSourceRequest true "Config and manga URL."
+// @Success 200 {object} ValidateScraperSourceResult "{"message": "Config is valid", "result": resultObj}"
+// @Router /sources/scraper/validate [post]
+func ValidateScraperSource(c *gin.Context) {
+	var requestData ValidateScraperSourceRequest
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "invalid JSON fields, refer to the API documentation"})
+		return
+	}
+
+	// The registered scraper sources can be validated again
+	builtInSources := []string{}
+	for name, source := range sources.GetSources() {
+		if _, ok := source.(*scraper.Source); !ok {
+			builtInSources = append(builtInSources, name)
+		}
+	}
+	if err := requestData.Config.Validate(builtInSources); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	source := scraper.New(requestData.Config)
+	result := ValidateScraperSourceResult{
+		Chapters:      []*manga.Chapter{},
+		SearchResults: []*models.MangaSearchResult{},
+		Errors:        map[string]string{},
+	}
+
+	m, err := source.GetMangaMetadata(requestData.URL, "")
+	if err != nil {
+		result.Errors["manga"] = err.Error()
+	} else {
+		result.Name = m.Name
+		result.CoverImgURL = m.CoverImgURL
+		result.LastReleasedChapter = m.LastReleasedChapter
+	}
+
+	chapters, err := source.GetChaptersMetadata(requestData.URL, "")
+	if err != nil {
+		result.Errors["chapters"] = err.Error()
+	} else {
+		result.Chapters = chapters
+	}
+
+	if requestData.SearchTerm != "" {
+		searchResults, err := source.Search(requestData.SearchTerm, 20)
+		if err != nil {
+			result.Errors["search"] = err.Error()
+		} else {
+			result.SearchResults = searchResults
+		}
+	}
+
+	message := "Config is valid"
+	if len(result.Errors) > 0 {
+		message = "Config is valid, but some steps failed"
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message, "result": result})
+}
@@ -0,0 +1,290 @@
+package routes
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+
+	"github.com/diogovalentte/mantium/api/src/config"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/sources"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// StatsRoutes sets the reading stats routes
+func StatsRoutes(group *gin.RouterGroup) {
+	{
+		group.GET("/stats/activity", GetReadingActivity)
+		group.GET("/stats/chapters_read", GetChaptersReadPerPeriod)
+		group.GET("/stats/streaks", GetReadingStreaks)
+		group.GET("/stats/sources", GetChaptersReadPerSource)
+		group.GET("/stats/genres", GetChaptersReadPerGenre)
+		group.GET("/stats/release_to_read", GetReleaseToReadStats)
+		group.GET("/stats/backlog", GetBacklogSnapshots)
+	}
+}
+
+// @Summary Get reading activity
+// @Description Returns the reading activity log, newest first. An activity is recorded every time the last read chapter of a multimanga or custom manga advances.
+// @Produce json
+// @Param from query string false "Start date (inclusive)" Example(2024-01-01)
+// @Param to query string false "End date (inclusive)" Example(2024-12-31)
+// @Param limit query int false "Max number of activities to return" Example(50)
+// @Success 200 {array} manga.ReadingActivity "{"activities": [readingActivityObj]}"
+// @Router /stats/activity [get]
+func GetReadingActivity(c *gin.Context) {
+	from, to, err := getStatsInterval(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	limit := -1
+	limitStr := c.Query("limit")
+	if limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "limit must be a number"})
+			return
+		}
+	}
+
+	activities, err := manga.GetReadingActivity(from, to, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"activities": activities})
+}
+
+// @Summary Get chapters read per period
+// @Description Returns the number of chapters and mangas read per day, week, or month.
+// @Produce json
+// @Param period query string false "Period to group by: day, week, or month. Defaults to day." Example(week)
+// @Param from query string false "Start date (inclusive)" Example(2024-01-01)
+// @Param to query string false "End date (inclusive)" Example(2024-12-31)
+// @Success 200 {array} manga.ReadingPeriodStats "{"stats": [readingPeriodStatsObj]}"
+// @Router /stats/chapters_read [get]
+func GetChaptersReadPerPeriod(c *gin.Context) {
+	period := c.DefaultQuery("period", "day")
+	if period != "day" && period != "week" && period != "month" {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "period must be day, week, or month"})
+		return
+	}
+
+	from, to, err := getStatsInterval(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	stats, err := manga.GetChaptersReadPerPeriod(period, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"stats": stats})
+}
+
+// @Summary Get reading streaks
+// @Description Returns the current and longest streaks of consecutive days with at least one chapter read.
+// @Produce json
+// @Success 200 {object} manga.ReadingStreaks "{"streaks": readingStreaksObj}"
+// @Router /stats/streaks [get]
+func GetReadingStreaks(c *gin.Context) {
+	streaks, err := manga.GetReadingStreaks()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"streaks": streaks})
+}
+
+// @Summary Get chapters read per source
+// @Description Returns the number of chapters and mangas read grouped by source.
+// @Produce json
+// @Param from query string false "Start date (inclusive)" Example(2024-01-01)
+// @Param to query string false "End date (inclusive)" Example(2024-12-31)
+// @Success 200 {array} manga.ReadingBreakdown "{"stats": [readingBreakdownObj]}"
+// @Router /stats/sources [get]
+func GetChaptersReadPerSource(c *gin.Context) {
+	from, to, err := getStatsInterval(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	stats, err := manga.GetChaptersReadPerSource(from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"stats": stats})
+}
+
+// @Summary Get chapters read per genre
+// @Description Returns the number of chapters and mangas read grouped by genre. The genres are got from the sources that list them (MangaDex, MangaUpdates, and ComicK) when the reading activity is recorded. Activities without genres, like of custom mangas, are grouped in the "Unknown" genre.
+// @Produce json
+// @Param from query string false "Start date (inclusive)" Example(2024-01-01)
+// @Param to query string false "End date (inclusive)" Example(2024-12-31)
+// @Success 200 {array} manga.ReadingBreakdown "{"stats": [readingBreakdownObj]}"
+// @Router /stats/genres [get]
+func GetChaptersReadPerGenre(c *gin.Context) {
+	from, to, err := getStatsInterval(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	stats, err := manga.GetChaptersReadPerGenre(from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"stats": stats})
+}
+
+// @Summary Get release to read stats
+// @Description Returns the average and median time in seconds between a chapter being released and being read.
+// @Produce json
+// @Param from query string false "Start date (inclusive)" Example(2024-01-01)
+// @Param to query string false "End date (inclusive)" Example(2024-12-31)
+// @Success 200 {object} manga.ReleaseToReadStats "{"stats": releaseToReadStatsObj}"
+// @Router /stats/release_to_read [get]
+func GetReleaseToReadStats(c *gin.Context) {
+	from, to, err := getStatsInterval(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	stats, err := manga.GetReleaseToReadStats(from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"stats": stats})
+}
+
+// @Summary Get backlog over time
+// @Description Returns the daily snapshots of the number of mangas with unread chapters. A snapshot is saved when the mangas metadata are updated and when a chapter is read.
+// @Produce json
+// @Param from query string false "Start date (inclusive)" Example(2024-01-01)
+// @Param to query string false "End date (inclusive)" Example(2024-12-31)
+// @Success 200 {array} manga.BacklogSnapshot "{"snapshots": [backlogSnapshotObj]}"
+// @Router /stats/backlog [get]
+func GetBacklogSnapshots(c *gin.Context) {
+	from, to, err := getStatsInterval(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	snapshots, err := manga.GetBacklogSnapshots(from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"snapshots": snapshots})
+}
+
+// getStatsInterval gets the from and to query parameters as dates (YYYY-MM-DD).
+// By default, from is the beginning of time and to is today.
+// The returned to is the start of the day after the to date.
+func getStatsInterval(c *gin.Context) (time.Time, time.Time, error) {
+	var from, to time.Time
+	var err error
+
+	fromStr := c.Query("from")
+	if fromStr != "" {
+		from, err = time.ParseInLocation(time.DateOnly, fromStr, time.Local)
+		if err != nil {
+			return from, to, fmt.Errorf("from must be a date like YYYY-MM-DD")
+		}
+	}
+
+	toStr := c.Query("to")
+	if toStr != "" {
+		to, err = time.ParseInLocation(time.DateOnly, toStr, time.Local)
+		if err != nil {
+			return from, to, fmt.Errorf("to must be a date like YYYY-MM-DD")
+		}
+	} else {
+		now := time.Now()
+		to = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	}
+	to = to.AddDate(0, 0, 1)
+
+	if !from.Before(to) {
+		return from, to, fmt.Errorf("from must be before or equal to to")
+	}
+
+	return from, to, nil
+}
+
+// recordReadingActivity records the user reading a chapter in the reading activity log
+// and updates today's backlog snapshot. Errors are only logged because the last read
+// chapter was already updated.
+func recordReadingActivity(ctx context.Context, multiMangaID manga.ID, m *manga.Manga, chapter, previousChapter *manga.Chapter, chapterReleasedAt time.Time) {
+	logger := util.GetLogger(zerolog.Level(config.GlobalConfigs.API.LogLevelInt))
+
+	activity := &manga.ReadingActivity{
+		MultiMangaID:      multiMangaID,
+		MangaID:           m.ID,
+		MangaName:         m.Name,
+		Source:            m.Source,
+		Chapter:           chapter.Chapter,
+		ChapterURL:        chapter.URL,
+		ChapterReleasedAt: chapterReleasedAt,
+		ReadAt:            chapter.UpdatedAt,
+	}
+	_, err := manga.RecordReadingActivity(activity, previousChapter, func() []string {
+		return getReadingActivityGenres(ctx, m, logger)
+	})
+	if err != nil {
+		logger.Error().Err(err).Str("manga_url", m.URL).Msg("Last read chapter updated, but error recording reading activity")
+		return
+	}
+
+	err = manga.SaveBacklogSnapshot()
+	if err != nil {
+		logger.Error().Err(err).Msg("Last read chapter updated, but error saving backlog snapshot")
+	}
+}
+
+// getReadingActivityGenres returns the manga genres recorded in its previous reading
+// activities, or gets them from the manga source if the source lists them.
+// The activity is recorded without genres (grouped in the unknown genre) if they can't be got.
+func getReadingActivityGenres(ctx context.Context, m *manga.Manga, logger *zerolog.Logger) []string {
+	if m.ID > 0 {
+		genres, err := manga.GetMangaGenresDB(m.ID)
+		if err != nil {
+			logger.Error().Err(err).Str("manga_url", m.URL).Msg("Error getting manga genres from DB, will get them from the source")
+		} else if len(genres) > 0 {
+			return genres
+		}
+	}
+	if m.Source == manga.CustomMangaSource || !sources.HasGenres(m.URL) {
+		return nil
+	}
+
+	genres, err := sources.GetMangaGenres(ctx, m.URL, m.InternalID)
+	if err != nil {
+		logger.Error().Err(err).Str("manga_url", m.URL).Msg("Error getting manga genres, the reading activity will be recorded without genres")
+		return nil
+	}
+
+	return genres
+}
@@ -0,0 +1,96 @@
+package routes
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/diogovalentte/mantium/api/src/dashboard"
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/manga"
+)
+
+// @Summary Update multimanga tags
+// @Description Replaces the multimanga tags. The tags are stored trimmed, in lower case, and sorted. An empty list removes all tags.
+// @Accept json
+// @Produce json
+// @Param id query int true "Multimanga ID" Example(1)
+// @Param tags body UpdateTagsRequest true "Multimanga tags"
+// @Success 200 {object} responseMessage
+// @Router /multimanga/tags [patch]
+func UpdateMultiMangaTags(c *gin.Context) {
+	var requestData UpdateTagsRequest
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "invalid JSON fields, refer to the API documentation"})
+		return
+	}
+
+	multimanga, ok := getMultiMangaFromQuery(c)
+	if !ok {
+		return
+	}
+
+	err := multimanga.UpdateTagsInDB(requestData.Tags)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	dashboard.UpdateDashboard()
+
+	c.JSON(http.StatusOK, gin.H{"message": "Multimanga tags updated successfully"})
+}
+
+// @Summary Update custom manga tags
+// @Description Replaces the custom manga tags. The tags are stored trimmed, in lower case, and sorted. An empty list removes all tags.
+// @Accept json
+// @Produce json
+// @Param id query int false "Manga ID" Example(1)
+// @Param url query string false "Manga URL" Example("https://mangadex.org/title/1/one-piece")
+// @Param tags body UpdateTagsRequest true "Manga tags"
+// @Success 200 {object} responseMessage
+// @Router /custom_manga/tags [patch]
+func UpdateCustomMangaTags(c *gin.Context) {
+	var requestData UpdateTagsRequest
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "invalid JSON fields, refer to the API documentation"})
+		return
+	}
+
+	mangaID, mangaURL, err := getMangaIDAndURL(c.Query("id"), c.Query("url"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	mangaToUpdate, err := manga.GetMangaDB(mangaID, mangaURL)
+	if err != nil {
+		if strings.Contains(err.Error(), errordefs.ErrMangaNotFoundDB.Error()) {
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	if mangaToUpdate.Source != manga.CustomMangaSource {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "you can only update the tags of custom mangas, use the multimanga tags for other mangas"})
+		return
+	}
+
+	err = mangaToUpdate.UpdateTagsInDB(requestData.Tags)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	dashboard.UpdateDashboard()
+
+	c.JSON(http.StatusOK, gin.H{"message": "Custom manga tags updated successfully"})
+}
+
+// UpdateTagsRequest is the request body for updating tags
+type UpdateTagsRequest struct {
+	Tags []string `json:"tags" binding:"required"`
+}
@@ -0,0 +1,165 @@
+package routes
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+
+	"github.com/diogovalentte/mantium/api/src/config"
+	"github.com/diogovalentte/mantium/api/src/dashboard"
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/sources"
+	"github.com/diogovalentte/mantium/api/src/webhooks"
+)
+
+// WebhooksRoutes sets the inbound webhooks routes
+func WebhooksRoutes(group *gin.RouterGroup) {
+	{
+		group.POST("/webhooks/chapter_read", ChapterReadWebhook)
+	}
+}
+
+// @Summary Chapter read webhook
+// @Description Receives a chapter read in an external reader, like Suwayomi, Komga, or a Tachiyomi sync server, and sets the multimanga last read chapter to it. The manga is matched by its URL in the source. The last read chapter only moves forward, so events of chapters older than the last read chapter are ignored. If the chapters numbers can't be compared (like `Extra` and `Oneshot`), the event chapter must have been released after the last read chapter or be listed before it in the source, otherwise it's ignored. Events with an idempotency key already received in the last 7 days are also ignored. Each integration has its own token, set in the READ_WEBHOOK_TOKENS environment variable and sent as a bearer token in the Authorization header or in the token query.
+// @Accept json
+// @Produce json
+// @Param token query string false "Integration token, if not sent in the Authorization header"
+// @Param Idempotency-Key header string false "Event idempotency key, if not sent in the body"
+// @Param event body webhooks.ChapterReadEvent true "Chapter read event"
+// @Success 200 {object} responseMessage
+// @Router /webhooks/chapter_read [post]
+func ChapterReadWebhook(c *gin.Context) {
+	currentTime := time.Now()
+
+	if !config.GlobalConfigs.ReadWebhooks.Valid {
+		c.JSON(http.StatusNotFound, gin.H{"message": errordefs.ErrReadWebhooksDisabled.Error()})
+		return
+	}
+	integration, err := webhooks.Authenticate(c.GetHeader("Authorization"), c.Query("token"), config.GlobalConfigs.ReadWebhooks.Tokens)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": err.Error()})
+		return
+	}
+
+	var event webhooks.ChapterReadEvent
+	if err := c.ShouldBindJSON(&event); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "invalid JSON fields, refer to the API documentation"})
+		return
+	}
+	if event.Chapter == "" && event.ChapterURL == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "chapter or chapter_url must be provided"})
+		return
+	}
+	if idempotencyKey := c.GetHeader("Idempotency-Key"); idempotencyKey != "" {
+		event.IdempotencyKey = idempotencyKey
+	}
+	if len(event.IdempotencyKey) > 255 {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "idempotency key must have at most 255 characters"})
+		return
+	}
+
+	if event.IdempotencyKey != "" {
+		claimed, err := webhooks.ClaimEvent(integration, event.IdempotencyKey, currentTime)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			return
+		}
+		if !claimed {
+			c.JSON(http.StatusOK, gin.H{"message": "Event already received"})
+			return
+		}
+	}
+
+	statusCode, message, err := processChapterReadEvent(c.Request.Context(), &event, currentTime)
+	if err != nil {
+		if event.IdempotencyKey != "" {
+			// The event wasn't processed, so the integration can retry it
+			releaseErr := webhooks.ReleaseEvent(integration, event.IdempotencyKey)
+			if releaseErr != nil {
+				zerolog.Ctx(c.Request.Context()).Error().Err(releaseErr).Str("integration", integration).Msg("Error releasing read webhook event")
+			}
+		}
+		c.JSON(statusCode, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(statusCode, gin.H{"message": message})
+}
+
+// processChapterReadEvent sets the multimanga last read chapter to the event chapter if it's newer.
+// It returns the response status code and message, or an error if the event wasn't processed.
+func processChapterReadEvent(ctx context.Context, event *webhooks.ChapterReadEvent, currentTime time.Time) (int, string, error) {
+	m, err := webhooks.GetMangaByURL(event.MangaURL)
+	if err != nil {
+		if strings.Contains(err.Error(), errordefs.ErrMangaNotFoundDB.Error()) {
+			return http.StatusNotFound, "", err
+		}
+		return http.StatusInternalServerError, "", err
+	}
+	if m.Source == manga.CustomMangaSource {
+		return http.StatusBadRequest, "", fmt.Errorf("manga '%s' is a custom manga", m.Name)
+	}
+	if m.MultiMangaID == 0 {
+		return http.StatusNotFound, "", fmt.Errorf("manga '%s' is not from a multimanga", m.Name)
+	}
+
+	multimanga, err := manga.GetMultiMangaFromDB(m.MultiMangaID)
+	if err != nil {
+		if strings.Contains(err.Error(), errordefs.ErrMultiMangaNotFoundDB.Error()) {
+			return http.StatusNotFound, "", err
+		}
+		return http.StatusInternalServerError, "", err
+	}
+
+	var mangaGetChapterFrom *manga.Manga
+	for _, multimangaManga := range multimanga.Mangas {
+		if multimangaManga.ID == m.ID {
+			mangaGetChapterFrom = multimangaManga
+			break
+		}
+	}
+	if mangaGetChapterFrom == nil {
+		return http.StatusNotFound, "", errordefs.ErrMangaNotFoundInMultiManga
+	}
+
+	// Check before getting the chapter from the source, as
+	// readers usually send an event for every chapter read.
+	if ahead, comparable := webhooks.IsChapterAhead(event.Chapter, multimanga.LastReadChapter); comparable && !ahead {
+		return http.StatusOK, fmt.Sprintf("Chapter %s of %s is not newer than the last read chapter, ignoring", event.Chapter, mangaGetChapterFrom.Name), nil
+	}
+
+	preferences, err := manga.GetChapterPreferencesDB(mangaGetChapterFrom.ID)
+	if err != nil {
+		return http.StatusInternalServerError, "", err
+	}
+	chapter, err := sources.GetChapterMetadata(ctx, mangaGetChapterFrom.URL, mangaGetChapterFrom.InternalID, event.Chapter, event.ChapterURL, "", preferences)
+	if err != nil {
+		return http.StatusInternalServerError, "", err
+	}
+	// The source chapters are only needed to check the chapters that can't be compared by number.
+	var chapters []*manga.Chapter
+	if _, comparable := webhooks.IsChapterAhead(chapter.Chapter, multimanga.LastReadChapter); !comparable {
+		chapters, err = sources.GetMangaChapters(ctx, mangaGetChapterFrom.URL, mangaGetChapterFrom.InternalID, preferences)
+		if err != nil {
+			return http.StatusInternalServerError, "", err
+		}
+	}
+	if !webhooks.IsChapterNewer(chapter, multimanga.LastReadChapter, chapters) {
+		return http.StatusOK, fmt.Sprintf("Chapter %s of %s is not newer than the last read chapter, ignoring", chapter.Chapter, mangaGetChapterFrom.Name), nil
+	}
+
+	err = setMultiMangaLastReadChapter(ctx, multimanga, mangaGetChapterFrom, chapter, currentTime, false)
+	if err != nil {
+		return http.StatusInternalServerError, "", err
+	}
+
+	dashboard.UpdateDashboard()
+
+	return http.StatusOK, fmt.Sprintf("Chapter %s of %s marked as read", chapter.Chapter, mangaGetChapterFrom.Name), nil
+}
@@ -0,0 +1,96 @@
+// Package asura provides the implementation of the manga.Source interface for the Asura Scans source
+package asura
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gocolly/colly/v2"
+
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// baseSiteURL is the site URL used if the source has no base URL.
+const baseSiteURL = "https://asuracomic.net"
+
+// Source is the struct for the Asura Scans source
+type Source struct {
+	c *colly.Collector
+	// baseURL is the site URL, like https://asuracomic.net. It's baseSiteURL if empty.
+	baseURL string
+}
+
+func (Source) GetName() string {
+	return "asura"
+}
+
+var userAgent = "Mozilla/5.0 (X11; Linux x86_64; rv:30.0) Gecko/20100101 Firefox/30.0"
+
+func newCollector() *colly.Collector {
+	c := colly.NewCollector(
+		colly.UserAgent(userAgent),
+	)
+
+	return c
+}
+
+func (s *Source) getBaseURL() string {
+	if s.baseURL != "" {
+		return s.baseURL
+	}
+
+	return baseSiteURL
+}
+
+func (s *Source) resetCollector() {
+	if s.c != nil {
+		s.c.Wait()
+	}
+
+	s.c = newCollector()
+}
+
+// GetFormattedMangaURL returns the manga URL, like https://asuracomic.net/series/omniscient-reader-b7a3c6f1,
+// from a manga or chapter URL.
+func (s *Source) GetFormattedMangaURL(mangaURL string) (string, error) {
+	errorContext := "error while getting manga URL '%s'"
+
+	parsedURL, err := url.Parse(mangaURL)
+	if err != nil {
+		return "", util.AddErrorContext(fmt.Sprintf(errorContext, mangaURL), err)
+	}
+	pathParts := strings.Split(strings.Trim(parsedURL.Path, "/"), "/")
+	if len(pathParts) < 2 || pathParts[0] != "series" || pathParts[1] == "" {
+		return "", util.AddErrorContext(fmt.Sprintf(errorContext, mangaURL), fmt.Errorf("manga slug not found"))
+	}
+
+	return fmt.Sprintf("%s/series/%s", s.getBaseURL(), pathParts[1]), nil
+}
+
+var chapterNumberRegex = regexp.MustCompile(`(?i)chapter\s*(\d+(?:\.\d+)?)`)
+
+// getChapterNumber returns the chapter number in the chapter name, like "12" in "Chapter 12 The End".
+// If there's no number, it returns the name.
+func getChapterNumber(chapterName string) string {
+	if matches := chapterNumberRegex.FindStringSubmatch(chapterName); len(matches) > 1 {
+		return matches[1]
+	}
+
+	return chapterName
+}
+
+var ordinalSuffixRegex = regexp.MustCompile(`(\d+)(?:st|nd|rd|th)`)
+
+// parseChapterDate parses the chapter dates shown by the site, like "November 21st 2023".
+func parseChapterDate(chapterDate string) (time.Time, error) {
+	chapterDate = ordinalSuffixRegex.ReplaceAllString(strings.Join(strings.Fields(chapterDate), " "), "$1")
+	releaseTime, err := time.Parse("January 2 2006", chapterDate)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return releaseTime.Truncate(time.Second), nil
+}
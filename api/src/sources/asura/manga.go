@@ -0,0 +1,146 @@
+package asura
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gocolly/colly/v2"
+
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/sources/models"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// GetMangaMetadata scrapes the manga page and return the manga data
+func (s *Source) GetMangaMetadata(mangaURL, _ string) (*manga.Manga, error) {
+	s.resetCollector()
+
+	errorContext := "error while getting manga metadata"
+
+	formattedURL, err := s.GetFormattedMangaURL(mangaURL)
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+
+	mangaReturn := &manga.Manga{}
+	mangaReturn.Source = "asura"
+	mangaReturn.URL = formattedURL
+
+	var sharedErr error
+
+	// manga name
+	s.c.OnHTML("span.text-xl.font-bold", func(e *colly.HTMLElement) {
+		if mangaReturn.Name == "" {
+			mangaReturn.Name = strings.TrimSpace(e.Text)
+		}
+	})
+
+	// manga cover
+	s.c.OnHTML("img[alt='poster']", func(e *colly.HTMLElement) {
+		if mangaReturn.CoverImgURL != "" {
+			return
+		}
+		coverURL := e.Request.AbsoluteURL(e.Attr("src"))
+
+		coverImg, resized, err := util.GetImageFromURL(coverURL, 3, 1*time.Second)
+		if err == nil {
+			mangaReturn.CoverImgURL = coverURL
+			mangaReturn.CoverImgResized = resized
+			mangaReturn.CoverImg = coverImg
+		}
+	})
+
+	// last released chapter
+	s.c.OnHTML(chapterListSelector, func(e *colly.HTMLElement) {
+		if mangaReturn.LastReleasedChapter != nil || sharedErr != nil {
+			return
+		}
+		chapter, err := getChapterFromElement(e)
+		if err != nil {
+			sharedErr = err
+			return
+		}
+		mangaReturn.LastReleasedChapter = chapter
+	})
+
+	err = s.c.Visit(formattedURL)
+	if err != nil {
+		if err.Error() == "Not Found" {
+			return nil, util.AddErrorContext(errorContext, errordefs.ErrMangaNotFound)
+		}
+		return nil, util.AddErrorContext(errorContext, util.AddErrorContext("error while visiting manga URL", err))
+	}
+	if sharedErr != nil {
+		return nil, util.AddErrorContext(errorContext, sharedErr)
+	}
+	if mangaReturn.Name == "" {
+		return nil, util.AddErrorContext(errorContext, errordefs.ErrMangaNotFound)
+	}
+
+	return mangaReturn, nil
+}
+
+func (s *Source) Search(term string, limit int) ([]*models.MangaSearchResult, error) {
+	errorContext := "error while searching manga"
+	mangaSearchResults := []*models.MangaSearchResult{}
+	pageNumber := 1
+	term = url.QueryEscape(term)
+
+	for len(mangaSearchResults) < limit {
+		s.resetCollector()
+		nextPage := false
+
+		s.c.OnHTML("div.grid > a[href]", func(e *colly.HTMLElement) {
+			if len(mangaSearchResults) >= limit {
+				return
+			}
+
+			mangaURL, err := s.GetFormattedMangaURL(e.Request.AbsoluteURL(e.Attr("href")))
+			if err != nil {
+				return
+			}
+			mangaSearchResult := &models.MangaSearchResult{
+				Source:   "asura",
+				URL:      mangaURL,
+				Name:     strings.TrimSpace(e.DOM.Find("span.font-bold").First().Text()),
+				CoverURL: e.Request.AbsoluteURL(e.DOM.Find("img").AttrOr("src", "")),
+			}
+			if mangaSearchResult.CoverURL == "" {
+				mangaSearchResult.CoverURL = models.DefaultCoverImgURL
+			}
+			e.DOM.Find("span").EachWithBreak(func(_ int, span *goquery.Selection) bool {
+				text := strings.Join(strings.Fields(span.Text()), " ")
+				if strings.HasPrefix(text, "Chapter ") {
+					mangaSearchResult.LastChapter = getChapterNumber(text)
+					return false
+				}
+				return true
+			})
+
+			mangaSearchResults = append(mangaSearchResults, mangaSearchResult)
+		})
+
+		// The next page link is disabled in the last page
+		s.c.OnHTML("a:contains('Next')", func(e *colly.HTMLElement) {
+			if !strings.Contains(e.Attr("style"), "pointer-events:none") && e.Attr("href") != "" {
+				nextPage = true
+			}
+		})
+
+		searchURL := fmt.Sprintf("%s/series?page=%d&name=%s", s.getBaseURL(), pageNumber, term)
+		err := s.c.Visit(searchURL)
+		if err != nil {
+			return nil, util.AddErrorContext(errorContext, util.AddErrorContext("error while visiting search URL", err))
+		}
+		if !nextPage {
+			break
+		}
+		pageNumber++
+	}
+
+	return mangaSearchResults, nil
+}
@@ -0,0 +1,173 @@
+package asura
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/sources/sourcestest"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// newTestSource returns a source that uses a server with the pages in testdata, and the server URL.
+func newTestSource(t *testing.T) (*Source, string) {
+	server := sourcestest.NewFixtureServer(t, map[string]string{
+		"/series/omniscient-readers-viewpoint-b7a3c6f1":       "omniscient-readers-viewpoint.html",
+		"/series/nano-machine-5b7c0f2d":                       "nano-machine.html",
+		"/series?page=1&name=Omniscient+Reader%27s+Viewpoint": "search_omniscient_page1.html",
+		"/series?page=2&name=Omniscient+Reader%27s+Viewpoint": "search_omniscient_page2.html",
+		"/series?page=1&name=Nano+Machine":                    "search_nano_machine.html",
+	}, "https://asuracomic.net", "https://gg.asuracomic.net")
+
+	return &Source{baseURL: server.URL}, server.URL
+}
+
+type mangaTestType struct {
+	expected *manga.Manga
+	url      string
+}
+
+func getMangasTestTable(baseURL string) []mangaTestType {
+	return []mangaTestType{
+		{
+			expected: &manga.Manga{
+				Name:            "Omniscient Reader's Viewpoint",
+				Source:          "asura",
+				URL:             baseURL + "/series/omniscient-readers-viewpoint-b7a3c6f1",
+				CoverImgURL:     baseURL + "/storage/media/283/conversions/01J2ZRA6B1C5AK9VY0QW4XJ6N1-optimized.webp",
+				CoverImgResized: true,
+				LastReleasedChapter: &manga.Chapter{
+					Chapter:   "237",
+					Name:      "Chapter 237",
+					URL:       baseURL + "/series/omniscient-readers-viewpoint-b7a3c6f1/chapter/237",
+					UpdatedAt: time.Date(2024, 10, 2, 0, 0, 0, 0, time.UTC),
+					Type:      1,
+				},
+			},
+			url: baseURL + "/series/omniscient-readers-viewpoint-b7a3c6f1/chapter/236",
+		},
+		{
+			expected: &manga.Manga{
+				Name:            "Nano Machine",
+				Source:          "asura",
+				URL:             baseURL + "/series/nano-machine-5b7c0f2d",
+				CoverImgURL:     baseURL + "/storage/media/178/conversions/01J2ZQB5T8E0M7N3J4W0G8K2R5-optimized.webp",
+				CoverImgResized: true,
+				LastReleasedChapter: &manga.Chapter{
+					Chapter:   "232",
+					Name:      "Chapter 232",
+					URL:       baseURL + "/series/nano-machine-5b7c0f2d/chapter/232",
+					UpdatedAt: time.Date(2024, 10, 5, 0, 0, 0, 0, time.UTC),
+					Type:      1,
+				},
+			},
+			url: baseURL + "/series/nano-machine-5b7c0f2d",
+		},
+	}
+}
+
+func TestGetMangaMetadata(t *testing.T) {
+	source, baseURL := newTestSource(t)
+	mangasTestTable := getMangasTestTable(baseURL)
+
+	t.Run("Should scrape metadata from multiple mangas", func(t *testing.T) {
+		for _, test := range mangasTestTable {
+			expected := test.expected
+			mangaURL := test.url
+
+			actualManga, err := source.GetMangaMetadata(mangaURL, "")
+			if err != nil {
+				t.Fatalf("error while getting manga: %v", err)
+			}
+
+			if actualManga.CoverImg == nil {
+				t.Fatalf("expected manga.CoverImg to be different than nil")
+			}
+			actualManga.CoverImg = nil
+
+			if !reflect.DeepEqual(actualManga, expected) {
+				t.Fatalf("expected manga %s, got %s", expected, actualManga)
+			}
+		}
+	})
+	t.Run("Should not scrape metadata from multiple mangas", func(t *testing.T) {
+		for _, test := range mangasTestTable {
+			mangaURL := test.expected.URL + "salt"
+
+			_, err := source.GetMangaMetadata(mangaURL, "")
+			if err != nil {
+				if util.ErrorContains(err, errordefs.ErrMangaNotFound.Error()) {
+					continue
+				}
+				t.Fatalf("expected error, got %s", err)
+			}
+			t.Fatalf("expected error, got nil")
+		}
+	})
+}
+
+func TestSearch(t *testing.T) {
+	source, baseURL := newTestSource(t)
+	mangasTestTable := getMangasTestTable(baseURL)
+
+	t.Run("Should search for multiple mangas", func(t *testing.T) {
+		for _, test := range mangasTestTable {
+			mangaName := test.expected.Name
+
+			results, err := source.Search(mangaName, 20)
+			if err != nil {
+				t.Fatalf("error while searching: %v", err)
+			}
+
+			if len(results) == 0 {
+				t.Fatalf("expected results to be different than 0")
+			}
+			if results[0].URL != test.expected.URL || results[0].Name != mangaName || results[0].LastChapter != test.expected.LastReleasedChapter.Chapter {
+				t.Fatalf("expected the first result to be the manga %s, got %+v", test.expected, results[0])
+			}
+		}
+	})
+	t.Run("Should search the next pages until the limit", func(t *testing.T) {
+		mangaName := mangasTestTable[0].expected.Name
+
+		results, err := source.Search(mangaName, 20)
+		if err != nil {
+			t.Fatalf("error while searching: %v", err)
+		}
+		if len(results) != 2 {
+			t.Fatalf("expected 2 results from 2 pages, got %d", len(results))
+		}
+
+		results, err = source.Search(mangaName, 1)
+		if err != nil {
+			t.Fatalf("error while searching: %v", err)
+		}
+		if len(results) != 1 {
+			t.Fatalf("expected 1 result with limit 1, got %d", len(results))
+		}
+	})
+}
+
+func TestGetFormattedMangaURL(t *testing.T) {
+	source := Source{}
+
+	for _, mangaURL := range []string{
+		"https://asuracomic.net/series/omniscient-readers-viewpoint-b7a3c6f1",
+		"https://asuracomic.net/series/omniscient-readers-viewpoint-b7a3c6f1/",
+		"https://asuracomic.net/series/omniscient-readers-viewpoint-b7a3c6f1/chapter/237",
+	} {
+		formattedURL, err := source.GetFormattedMangaURL(mangaURL)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if expected := "https://asuracomic.net/series/omniscient-readers-viewpoint-b7a3c6f1"; formattedURL != expected {
+			t.Errorf("expected URL %s, got %s", expected, formattedURL)
+		}
+	}
+
+	if _, err := source.GetFormattedMangaURL("https://asuracomic.net/series?page=1"); err == nil {
+		t.Fatal("expected error with the series list URL")
+	}
+}
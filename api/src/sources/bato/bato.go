@@ -0,0 +1,127 @@
+// Package bato provides the implementation of the manga.Source interface for the Bato.to source
+package bato
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gocolly/colly/v2"
+
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// baseSiteURL is the site URL used if the source has no base URL.
+const baseSiteURL = "https://bato.to"
+
+// Source is the struct for the Bato.to source
+type Source struct {
+	c *colly.Collector
+	// baseURL is the site URL, like https://bato.to. It's baseSiteURL if empty.
+	baseURL string
+	// timeNow is used to parse the relative release dates, like "3 days ago". It's time.Now if nil.
+	timeNow func() time.Time
+}
+
+func (Source) GetName() string {
+	return "bato"
+}
+
+var userAgent = "Mozilla/5.0 (X11; Linux x86_64; rv:30.0) Gecko/20100101 Firefox/30.0"
+
+func newCollector() *colly.Collector {
+	c := colly.NewCollector(
+		colly.UserAgent(userAgent),
+	)
+
+	return c
+}
+
+func (s *Source) getBaseURL() string {
+	if s.baseURL != "" {
+		return s.baseURL
+	}
+
+	return baseSiteURL
+}
+
+func (s *Source) now() time.Time {
+	if s.timeNow != nil {
+		return s.timeNow()
+	}
+
+	return time.Now()
+}
+
+func (s *Source) resetCollector() {
+	if s.c != nil {
+		s.c.Wait()
+	}
+
+	s.c = newCollector()
+}
+
+var mangaIDRegex = regexp.MustCompile(`/series/(\d+)`)
+
+// GetFormattedMangaURL returns the manga URL without the slug, like https://bato.to/series/72315
+func (s *Source) GetFormattedMangaURL(mangaURL string) (string, error) {
+	errorContext := "error while getting manga URL '%s'"
+
+	matches := mangaIDRegex.FindStringSubmatch(mangaURL)
+	if len(matches) < 2 {
+		return "", util.AddErrorContext(fmt.Sprintf(errorContext, mangaURL), fmt.Errorf("manga ID not found"))
+	}
+
+	return fmt.Sprintf("%s/series/%s", s.getBaseURL(), matches[1]), nil
+}
+
+var chapterNumberRegex = regexp.MustCompile(`(?i)(?:chapter|ch\.|episode|ep\.)\s*(\d+(?:\.\d+)?)`)
+
+// getChapterNumber returns the chapter number in the chapter name, like "12" in "Vol.2 Chapter 12: The End".
+// If there's no number, it returns the name.
+func getChapterNumber(chapterName string) string {
+	if matches := chapterNumberRegex.FindStringSubmatch(chapterName); len(matches) > 1 {
+		return matches[1]
+	}
+
+	return chapterName
+}
+
+var relativeTimeRegex = regexp.MustCompile(`^(\d+|an?) (sec|min|hour|day|week|month|year)s? ago$`)
+
+// parseRelativeTime parses the relative release dates shown by the site, like "3 days ago", from now.
+func parseRelativeTime(relativeTime string, now time.Time) (time.Time, error) {
+	now = now.UTC().Truncate(time.Second)
+
+	relativeTime = strings.ToLower(strings.TrimSpace(relativeTime))
+	if relativeTime == "just now" {
+		return now, nil
+	}
+	matches := relativeTimeRegex.FindStringSubmatch(relativeTime)
+	if len(matches) < 3 {
+		return time.Time{}, fmt.Errorf("invalid relative time '%s'", relativeTime)
+	}
+	quantity := 1
+	if matches[1] != "a" && matches[1] != "an" {
+		quantity, _ = strconv.Atoi(matches[1])
+	}
+
+	switch matches[2] {
+	case "sec":
+		return now.Add(-time.Duration(quantity) * time.Second), nil
+	case "min":
+		return now.Add(-time.Duration(quantity) * time.Minute), nil
+	case "hour":
+		return now.Add(-time.Duration(quantity) * time.Hour), nil
+	case "day":
+		return now.AddDate(0, 0, -quantity), nil
+	case "week":
+		return now.AddDate(0, 0, -quantity*7), nil
+	case "month":
+		return now.AddDate(0, -quantity, 0), nil
+	default:
+		return now.AddDate(-quantity, 0, 0), nil
+	}
+}
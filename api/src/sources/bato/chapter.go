@@ -0,0 +1,134 @@
+package bato
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gocolly/colly/v2"
+
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// GetChapterMetadata returns a chapter by its chapter or URL
+func (s *Source) GetChapterMetadata(mangaURL, _, chapter, chapterURL, _ string) (*manga.Chapter, error) {
+	errorContext := "error while getting metadata of chapter"
+
+	if chapter == "" && chapterURL == "" {
+		return nil, util.AddErrorContext(errorContext, errordefs.ErrChapterHasNoChapterOrURL)
+	}
+
+	chapters, err := s.GetChaptersMetadata(mangaURL, "")
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+
+	if chapter != "" {
+		for _, c := range chapters {
+			if c.Chapter == chapter {
+				return c, nil
+			}
+		}
+	}
+	if chapterURL != "" {
+		for _, c := range chapters {
+			if c.URL == chapterURL {
+				return c, nil
+			}
+		}
+	}
+
+	return nil, util.AddErrorContext(errorContext, errordefs.ErrChapterNotFound)
+}
+
+// GetLastChapterMetadata scrapes the manga page and return the latest chapter
+func (s *Source) GetLastChapterMetadata(mangaURL, _ string) (*manga.Chapter, error) {
+	errorContext := "error while getting last chapter metadata"
+
+	chapters, err := s.GetChaptersMetadata(mangaURL, "")
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+	if len(chapters) == 0 {
+		return nil, util.AddErrorContext(errorContext, errordefs.ErrChapterNotFound)
+	}
+
+	return chapters[0], nil
+}
+
+// GetChaptersMetadata scrapes the manga page and return the chapters, from the newest to the oldest
+func (s *Source) GetChaptersMetadata(mangaURL, _ string) ([]*manga.Chapter, error) {
+	s.resetCollector()
+
+	errorContext := "error while getting chapters metadata"
+
+	formattedURL, err := s.GetFormattedMangaURL(mangaURL)
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+
+	chapters := []*manga.Chapter{}
+	var sharedErr error
+	var mangaFound bool
+
+	s.c.OnHTML("h3.item-title", func(_ *colly.HTMLElement) {
+		mangaFound = true
+	})
+
+	s.c.OnHTML("div.episode-list div.main > div.item", func(e *colly.HTMLElement) {
+		if sharedErr != nil {
+			return
+		}
+		chapter, err := getChapterFromElement(e, s.now())
+		if err != nil {
+			sharedErr = err
+			return
+		}
+		chapters = append(chapters, chapter)
+	})
+
+	err = s.c.Visit(formattedURL)
+	if err != nil {
+		if err.Error() == "Not Found" {
+			return nil, util.AddErrorContext(errorContext, errordefs.ErrMangaNotFound)
+		}
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+	if sharedErr != nil {
+		return nil, util.AddErrorContext(errorContext, sharedErr)
+	}
+	if !mangaFound {
+		return nil, util.AddErrorContext(errorContext, errordefs.ErrMangaNotFound)
+	}
+
+	return chapters, nil
+}
+
+// getChapterFromElement returns the chapter of a chapter list item.
+// The relative release dates are parsed from now.
+func getChapterFromElement(e *colly.HTMLElement, now time.Time) (*manga.Chapter, error) {
+	chapterElement := e.DOM.Find("a.chapt")
+	chapterURL := chapterElement.AttrOr("href", "")
+	if chapterURL == "" {
+		return nil, errordefs.ErrChapterURLNotFound
+	}
+
+	chapterName := strings.Join(strings.Fields(chapterElement.Text()), " ")
+	chapter := getChapterNumber(chapterName)
+
+	chapterDate := e.DOM.Find("div.extra > i").Last().Text()
+	releaseTime, err := parseRelativeTime(chapterDate, now)
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf("error while parsing chapter '%s' date", chapter), err)
+	}
+
+	return &manga.Chapter{
+		URL:       e.Request.AbsoluteURL(chapterURL),
+		Chapter:   chapter,
+		Name:      chapterName,
+		Type:      1,
+		UpdatedAt: releaseTime,
+	}, nil
+}
@@ -0,0 +1,154 @@
+package bato
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+type chapterTestType struct {
+	expected *manga.Chapter
+	url      string
+}
+
+func getChapterTestTable(baseURL string) []chapterTestType {
+	return []chapterTestType{
+		{
+			expected: &manga.Chapter{
+				Chapter:   "201",
+				Name:      "Chapter 201 : Season 2 Prologue",
+				URL:       baseURL + "/chapter/2712604",
+				UpdatedAt: time.Date(2024, 8, 5, 12, 0, 0, 0, time.UTC),
+				Type:      1,
+			},
+			url: baseURL + "/series/72315/omniscient-reader",
+		},
+		{
+			expected: &manga.Chapter{
+				Chapter:   "1",
+				Name:      "Vol.1 Chapter 1",
+				URL:       baseURL + "/chapter/1068844",
+				UpdatedAt: time.Date(2019, 10, 5, 12, 0, 0, 0, time.UTC),
+				Type:      1,
+			},
+			url: baseURL + "/series/74357/solo-leveling",
+		},
+	}
+}
+
+func TestGetChapterMetadata(t *testing.T) {
+	source, baseURL := newTestSource(t)
+	chapterTestTable := getChapterTestTable(baseURL)
+
+	t.Run("Should scrape the metadata of a chapter from multiple mangas", func(t *testing.T) {
+		for _, test := range chapterTestTable {
+			expected := test.expected
+			mangaURL := test.url
+
+			actualChapter, err := source.GetChapterMetadata(mangaURL, "", expected.Chapter, "", "")
+			if err != nil {
+				t.Fatalf("error while getting chapter: %v", err)
+			}
+			if !reflect.DeepEqual(actualChapter, expected) {
+				t.Fatalf("expected chapter %s, got %s", expected, actualChapter)
+			}
+
+			actualChapter, err = source.GetChapterMetadata(mangaURL, "", "", expected.URL, "")
+			if err != nil {
+				t.Fatalf("error while getting chapter by URL: %v", err)
+			}
+			if !reflect.DeepEqual(actualChapter, expected) {
+				t.Fatalf("expected chapter %s, got %s", expected, actualChapter)
+			}
+		}
+	})
+	t.Run("Should not find a chapter not in the manga", func(t *testing.T) {
+		_, err := source.GetChapterMetadata(chapterTestTable[0].url, "", "1000", "", "")
+		if err == nil || !util.ErrorContains(err, errordefs.ErrChapterNotFound.Error()) {
+			t.Fatalf("expected chapter not found error, got %v", err)
+		}
+	})
+}
+
+func TestGetLastChapterMetadata(t *testing.T) {
+	source, baseURL := newTestSource(t)
+
+	t.Run("Should scrape the metadata of the last chapter of multiple mangas", func(t *testing.T) {
+		for _, test := range getMangasTestTable(baseURL) {
+			expected := test.expected.LastReleasedChapter
+			mangaURL := test.expected.URL
+
+			actualChapter, err := source.GetLastChapterMetadata(mangaURL, "")
+			if err != nil {
+				t.Fatalf("error while getting chapter: %v", err)
+			}
+
+			if !reflect.DeepEqual(actualChapter, expected) {
+				t.Fatalf("expected chapter %s, got %s", expected, actualChapter)
+			}
+		}
+	})
+}
+
+type chaptersTestType struct {
+	url      string
+	quantity int
+}
+
+func getChaptersTestTable(baseURL string) []chaptersTestType {
+	return []chaptersTestType{
+		{
+			url:      baseURL + "/series/72315",
+			quantity: 4,
+		},
+		{
+			url:      baseURL + "/series/74357",
+			quantity: 3,
+		},
+	}
+}
+
+func TestGetChaptersMetadata(t *testing.T) {
+	source, baseURL := newTestSource(t)
+
+	t.Run("Should scrape the metadata of multiple chapters", func(t *testing.T) {
+		for _, test := range getChaptersTestTable(baseURL) {
+			mangaURL := test.url
+
+			chapters, err := source.GetChaptersMetadata(mangaURL, "")
+			if err != nil {
+				t.Fatalf("error while getting chapters: %v", err)
+			}
+
+			if len(chapters) != test.quantity {
+				t.Fatalf("expected %d chapters, got %d", test.quantity, len(chapters))
+			}
+		}
+	})
+	t.Run("Should not scrape the metadata of multiple chapters", func(t *testing.T) {
+		for _, test := range getChaptersTestTable(baseURL) {
+			// The manga URL is formatted to its ID, so the salt is a digit
+			mangaURL := test.url + "0"
+
+			_, err := source.GetChaptersMetadata(mangaURL, "")
+			if err == nil || !util.ErrorContains(err, errordefs.ErrMangaNotFound.Error()) {
+				t.Fatalf("expected manga not found error, got %v", err)
+			}
+		}
+	})
+	t.Run("Should parse the relative release dates", func(t *testing.T) {
+		chapters, err := source.GetChaptersMetadata(getChaptersTestTable(baseURL)[0].url, "")
+		if err != nil {
+			t.Fatalf("error while getting chapters: %v", err)
+		}
+
+		expected := time.Date(2020, 10, 5, 12, 0, 0, 0, time.UTC)
+		if chapters[3].Chapter != "Prologue" || !chapters[3].UpdatedAt.Equal(expected) {
+			t.Fatalf("expected the chapter Prologue released at %s, got %s at %s", expected, chapters[3].Chapter, chapters[3].UpdatedAt)
+		}
+	})
+}
@@ -0,0 +1,132 @@
+package bato
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gocolly/colly/v2"
+
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/sources/models"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// GetMangaMetadata scrapes the manga page and return the manga data
+func (s *Source) GetMangaMetadata(mangaURL, _ string) (*manga.Manga, error) {
+	s.resetCollector()
+
+	errorContext := "error while getting manga metadata"
+
+	formattedURL, err := s.GetFormattedMangaURL(mangaURL)
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+
+	mangaReturn := &manga.Manga{}
+	mangaReturn.Source = "bato"
+	mangaReturn.URL = formattedURL
+
+	var sharedErr error
+
+	// manga name
+	s.c.OnHTML("h3.item-title > a", func(e *colly.HTMLElement) {
+		mangaReturn.Name = strings.TrimSpace(e.Text)
+	})
+
+	// manga cover
+	s.c.OnHTML("div.attr-cover > img", func(e *colly.HTMLElement) {
+		coverURL := e.Request.AbsoluteURL(e.Attr("src"))
+
+		coverImg, resized, err := util.GetImageFromURL(coverURL, 3, 1*time.Second)
+		if err == nil {
+			mangaReturn.CoverImgURL = coverURL
+			mangaReturn.CoverImgResized = resized
+			mangaReturn.CoverImg = coverImg
+		}
+	})
+
+	// last released chapter
+	s.c.OnHTML("div.episode-list div.main > div.item:first-child", func(e *colly.HTMLElement) {
+		chapter, err := getChapterFromElement(e, s.now())
+		if err != nil {
+			sharedErr = err
+			return
+		}
+		mangaReturn.LastReleasedChapter = chapter
+	})
+
+	err = s.c.Visit(formattedURL)
+	if err != nil {
+		if err.Error() == "Not Found" {
+			return nil, util.AddErrorContext(errorContext, errordefs.ErrMangaNotFound)
+		}
+		return nil, util.AddErrorContext(errorContext, util.AddErrorContext("error while visiting manga URL", err))
+	}
+	if sharedErr != nil {
+		return nil, util.AddErrorContext(errorContext, sharedErr)
+	}
+	if mangaReturn.Name == "" {
+		return nil, util.AddErrorContext(errorContext, errordefs.ErrMangaNotFound)
+	}
+
+	return mangaReturn, nil
+}
+
+func (s *Source) Search(term string, limit int) ([]*models.MangaSearchResult, error) {
+	errorContext := "error while searching manga"
+	mangaSearchResults := []*models.MangaSearchResult{}
+	pageNumber := 1
+	term = url.QueryEscape(term)
+
+	for len(mangaSearchResults) < limit {
+		s.resetCollector()
+		nextPage := false
+
+		s.c.OnHTML("div#series-list > div.item", func(e *colly.HTMLElement) {
+			if len(mangaSearchResults) >= limit {
+				return
+			}
+
+			mangaURL, err := s.GetFormattedMangaURL(e.DOM.Find("a.item-title").AttrOr("href", ""))
+			if err != nil {
+				return
+			}
+			mangaSearchResult := &models.MangaSearchResult{
+				Source:   "bato",
+				URL:      mangaURL,
+				Name:     strings.TrimSpace(e.DOM.Find("a.item-title").Text()),
+				CoverURL: e.Request.AbsoluteURL(e.DOM.Find("a.item-cover > img").AttrOr("src", "")),
+			}
+			if mangaSearchResult.CoverURL == "" {
+				mangaSearchResult.CoverURL = models.DefaultCoverImgURL
+			}
+
+			lastChapter := e.DOM.Find("div.item-volch > a").First()
+			if lastChapter.Length() > 0 {
+				mangaSearchResult.LastChapter = getChapterNumber(strings.Join(strings.Fields(lastChapter.Text()), " "))
+				mangaSearchResult.LastChapterURL = e.Request.AbsoluteURL(lastChapter.AttrOr("href", ""))
+			}
+
+			mangaSearchResults = append(mangaSearchResults, mangaSearchResult)
+		})
+
+		s.c.OnHTML("ul.pagination > li.page-item.active + li.page-item > a.page-link", func(_ *colly.HTMLElement) {
+			nextPage = true
+		})
+
+		searchURL := fmt.Sprintf("%s/search?word=%s&page=%d", s.getBaseURL(), term, pageNumber)
+		err := s.c.Visit(searchURL)
+		if err != nil {
+			return nil, util.AddErrorContext(errorContext, util.AddErrorContext("error while visiting search URL", err))
+		}
+		if !nextPage {
+			break
+		}
+		pageNumber++
+	}
+
+	return mangaSearchResults, nil
+}
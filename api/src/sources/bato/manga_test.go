@@ -0,0 +1,207 @@
+package bato
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/sources/sourcestest"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// testNow is the time the fixtures' relative release dates, like "3 days ago", are parsed from.
+var testNow = time.Date(2024, 10, 5, 12, 0, 0, 0, time.UTC)
+
+// newTestSource returns a source that uses a server with the pages in testdata, and the server URL.
+func newTestSource(t *testing.T) (*Source, string) {
+	server := sourcestest.NewFixtureServer(t, map[string]string{
+		"/series/72315":                         "omniscient-reader.html",
+		"/series/74357":                         "solo-leveling.html",
+		"/search?word=Omniscient+Reader&page=1": "search_omniscient_reader_page1.html",
+		"/search?word=Omniscient+Reader&page=2": "search_omniscient_reader_page2.html",
+		"/search?word=Solo+Leveling&page=1":     "search_solo_leveling.html",
+	}, "https://bato.to", "https://xfs-n03.xfsbb.com", "https://xfs-n07.xfsbb.com", "https://xfs-n12.xfsbb.com")
+
+	return &Source{baseURL: server.URL, timeNow: func() time.Time { return testNow }}, server.URL
+}
+
+type mangaTestType struct {
+	expected *manga.Manga
+	url      string
+}
+
+func getMangasTestTable(baseURL string) []mangaTestType {
+	return []mangaTestType{
+		{
+			expected: &manga.Manga{
+				Name:            "Omniscient Reader",
+				Source:          "bato",
+				URL:             baseURL + "/series/72315",
+				CoverImgURL:     baseURL + "/thumb/W600/ampi/a4d/a4d1b3f0c9e2e7a8b6c5d4e3f2a1b0c9d8e7f6a5_600_849_151201.jpeg",
+				CoverImgResized: true,
+				LastReleasedChapter: &manga.Chapter{
+					Chapter:   "237",
+					Name:      "Chapter 237",
+					URL:       baseURL + "/chapter/2950001",
+					UpdatedAt: time.Date(2024, 10, 2, 12, 0, 0, 0, time.UTC),
+					Type:      1,
+				},
+			},
+			url: baseURL + "/series/72315/omniscient-reader",
+		},
+		{
+			expected: &manga.Manga{
+				Name:            "Solo Leveling",
+				Source:          "bato",
+				URL:             baseURL + "/series/74357",
+				CoverImgURL:     baseURL + "/thumb/W600/ampi/1c9/1c9e3f5a7b2d4c6e8f0a1b3c5d7e9f1a2b4c6d8e_600_853_168945.jpeg",
+				CoverImgResized: true,
+				LastReleasedChapter: &manga.Chapter{
+					Chapter:   "200",
+					Name:      "Chapter 200 : [END]",
+					URL:       baseURL + "/chapter/1656772",
+					UpdatedAt: time.Date(2021, 10, 5, 12, 0, 0, 0, time.UTC),
+					Type:      1,
+				},
+			},
+			url: baseURL + "/series/74357",
+		},
+	}
+}
+
+func TestGetMangaMetadata(t *testing.T) {
+	source, baseURL := newTestSource(t)
+	mangasTestTable := getMangasTestTable(baseURL)
+
+	t.Run("Should scrape metadata from multiple mangas", func(t *testing.T) {
+		for _, test := range mangasTestTable {
+			expected := test.expected
+			mangaURL := test.url
+
+			actualManga, err := source.GetMangaMetadata(mangaURL, "")
+			if err != nil {
+				t.Fatalf("error while getting manga: %v", err)
+			}
+
+			if actualManga.CoverImg == nil {
+				t.Fatalf("expected manga.CoverImg to be different than nil")
+			}
+			actualManga.CoverImg = nil
+
+			if !reflect.DeepEqual(actualManga, expected) {
+				t.Fatalf("expected manga %s, got %s", expected, actualManga)
+			}
+		}
+	})
+	t.Run("Should not scrape metadata from multiple mangas", func(t *testing.T) {
+		for _, test := range mangasTestTable {
+			// The manga URL is formatted to its ID, so the salt is a digit
+			mangaURL := test.expected.URL + "0"
+
+			_, err := source.GetMangaMetadata(mangaURL, "")
+			if err != nil {
+				if util.ErrorContains(err, errordefs.ErrMangaNotFound.Error()) {
+					continue
+				}
+				t.Fatalf("expected error, got %s", err)
+			}
+			t.Fatalf("expected error, got nil")
+		}
+	})
+}
+
+func TestSearch(t *testing.T) {
+	source, baseURL := newTestSource(t)
+	mangasTestTable := getMangasTestTable(baseURL)
+
+	t.Run("Should search for multiple mangas", func(t *testing.T) {
+		for _, test := range mangasTestTable {
+			mangaName := test.expected.Name
+
+			results, err := source.Search(mangaName, 20)
+			if err != nil {
+				t.Fatalf("error while searching: %v", err)
+			}
+
+			if len(results) == 0 {
+				t.Fatalf("expected results to be different than 0")
+			}
+			if results[0].URL != test.expected.URL || results[0].Name != mangaName || results[0].LastChapter != test.expected.LastReleasedChapter.Chapter ||
+				results[0].LastChapterURL != test.expected.LastReleasedChapter.URL {
+				t.Fatalf("expected the first result to be the manga %s, got %+v", test.expected, results[0])
+			}
+		}
+	})
+	t.Run("Should search the next pages until the limit", func(t *testing.T) {
+		mangaName := mangasTestTable[0].expected.Name
+
+		results, err := source.Search(mangaName, 20)
+		if err != nil {
+			t.Fatalf("error while searching: %v", err)
+		}
+		if len(results) != 2 {
+			t.Fatalf("expected 2 results from 2 pages, got %d", len(results))
+		}
+
+		results, err = source.Search(mangaName, 1)
+		if err != nil {
+			t.Fatalf("error while searching: %v", err)
+		}
+		if len(results) != 1 {
+			t.Fatalf("expected 1 result with limit 1, got %d", len(results))
+		}
+	})
+}
+
+func TestGetFormattedMangaURL(t *testing.T) {
+	source := Source{}
+
+	for _, mangaURL := range []string{
+		"https://bato.to/series/72315",
+		"https://bato.to/series/72315/omniscient-reader",
+		"https://bato.to/series/72315/omniscient-reader?page=2",
+	} {
+		formattedURL, err := source.GetFormattedMangaURL(mangaURL)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if expected := "https://bato.to/series/72315"; formattedURL != expected {
+			t.Errorf("expected URL %s, got %s", expected, formattedURL)
+		}
+	}
+
+	if _, err := source.GetFormattedMangaURL("https://bato.to/chapter/2950001"); err == nil {
+		t.Fatal("expected error with a chapter URL")
+	}
+}
+
+func TestParseRelativeTime(t *testing.T) {
+	tests := []struct {
+		relativeTime string
+		expected     time.Time
+	}{
+		{"just now", testNow},
+		{"30 secs ago", testNow.Add(-30 * time.Second)},
+		{"5 mins ago", testNow.Add(-5 * time.Minute)},
+		{"an hour ago", testNow.Add(-time.Hour)},
+		{"1 day ago", testNow.AddDate(0, 0, -1)},
+		{"2 weeks ago", testNow.AddDate(0, 0, -14)},
+		{"3 months ago", testNow.AddDate(0, -3, 0)},
+		{"a year ago", testNow.AddDate(-1, 0, 0)},
+	}
+	for _, test := range tests {
+		actual, err := parseRelativeTime(test.relativeTime, testNow)
+		if err != nil {
+			t.Fatalf("unexpected error parsing '%s': %v", test.relativeTime, err)
+		}
+		if !actual.Equal(test.expected) {
+			t.Errorf("expected '%s' to be %s, got %s", test.relativeTime, test.expected, actual)
+		}
+	}
+
+	if _, err := parseRelativeTime("yesterday", testNow); err == nil {
+		t.Fatal("expected error")
+	}
+}
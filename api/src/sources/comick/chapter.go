@@ -2,6 +2,8 @@ package comick
 
 import (
 	"fmt"
+	"net/url"
+	"sort"
 	"strings"
 
 	"github.com/diogovalentte/mantium/api/src/errordefs"
@@ -136,7 +138,7 @@ func (s *Source) GetChaptersMetadata(mangaURL, _ string) ([]*manga.Chapter, erro
 	errChan := make(chan error)
 	done := make(chan struct{})
 
-	go generateMangaChapters(s, mangaURL, chaptersChan, errChan)
+	go generateMangaChapters(s, mangaURL, "en", chaptersChan, errChan)
 
 	var chapters []*manga.Chapter
 	go func() {
@@ -159,22 +161,68 @@ func (s *Source) GetChaptersMetadata(mangaURL, _ string) ([]*manga.Chapter, erro
 	}
 }
 
+// GetReleasesMetadata returns all releases of the manga chapters in the languages,
+// from the newest to the oldest chapter. If languages is empty, English is used.
+func (s *Source) GetReleasesMetadata(mangaURL, _ string, languages []string) ([]*manga.Chapter, error) {
+	s.checkClient()
+
+	errorContext := "error while getting releases metadata"
+
+	if len(languages) == 0 {
+		languages = []string{"en"}
+	}
+
+	var releases []*manga.Chapter
+	for _, language := range languages {
+		chaptersChan := make(chan *manga.Chapter)
+		errChan := make(chan error)
+		done := make(chan struct{})
+
+		go generateMangaChapters(s, mangaURL, language, chaptersChan, errChan)
+
+		go func() {
+			for chapter := range chaptersChan {
+				releases = append(releases, chapter)
+			}
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case err := <-errChan:
+			return nil, util.AddErrorContext(errorContext, err)
+		}
+	}
+
+	// The releases of each language are sorted by chapter, but not among them
+	if len(languages) > 1 {
+		sort.SliceStable(releases, func(i, j int) bool {
+			comparison, _ := manga.CompareChapters(releases[i], releases[j])
+			return comparison > 0
+		})
+	}
+
+	return releases, nil
+}
+
 type getChaptersAPIResponse struct {
 	Chapters []chapterAPIResponse `json:"chapters"`
 }
 
 type chapterAPIResponse struct {
-	Chap      string `json:"chap"`
-	Title     string `json:"title"`
-	CreatedAt string `json:"created_at"`
-	HID       string `json:"hid"`
+	Chap      string   `json:"chap"`
+	Title     string   `json:"title"`
+	CreatedAt string   `json:"created_at"`
+	HID       string   `json:"hid"`
+	Lang      string   `json:"lang"`
+	GroupName []string `json:"group_name"`
 }
 
-// generateMangaChapters generates the chapters of a manga and sends them to the channel.
+// generateMangaChapters generates the chapters of a manga in the language and sends them to the channel.
 // It sends an error to the error channel if something goes wrong.
 // It closes the chapters channel when there is no more chapters to send.
 // It requests the mangas from the API using the chapter for ordering.
-func generateMangaChapters(s *Source, mangaURL string, chaptersChan chan *manga.Chapter, errChan chan error) {
+func generateMangaChapters(s *Source, mangaURL, language string, chaptersChan chan *manga.Chapter, errChan chan error) {
 	defer close(chaptersChan)
 
 	mangaHID, err := s.getMangaHID(mangaURL)
@@ -186,7 +234,7 @@ func generateMangaChapters(s *Source, mangaURL string, chaptersChan chan *manga.
 	currentPage := 1
 	for {
 
-		mangaAPIURL := fmt.Sprintf("%s/comic/%s/chapters?lang=en&page=%d", baseAPIURL, mangaHID, currentPage)
+		mangaAPIURL := fmt.Sprintf("%s/comic/%s/chapters?lang=%s&page=%d", baseAPIURL, mangaHID, url.QueryEscape(language), currentPage)
 		var chaptersAPIResp getChaptersAPIResponse
 		_, err = s.client.Request("GET", mangaAPIURL, nil, &chaptersAPIResp)
 		if err != nil {
@@ -207,6 +255,12 @@ func generateMangaChapters(s *Source, mangaURL string, chaptersChan chan *manga.
 				errChan <- err
 				return
 			}
+			chapterReturn.Language = chapter.Lang
+			for _, group := range chapter.GroupName {
+				if group != "" {
+					chapterReturn.Groups = append(chapterReturn.Groups, group)
+				}
+			}
 			chaptersChan <- chapterReturn
 		}
 
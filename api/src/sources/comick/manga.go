@@ -33,7 +33,7 @@ func (s *Source) GetMangaMetadata(mangaURL, _ string) (*manga.Manga, error) {
 	_, err = s.client.Request("GET", mangaAPIURL, nil, &mangaAPIResp)
 	if err != nil {
 		if util.ErrorContains(err, "non-200 status code -> (404)") {
-			return nil, errordefs.ErrMangaNotFound
+			return nil, util.AddErrorContext(errorContext, errordefs.ErrMangaNotFound)
 		}
 		return nil, util.AddErrorContext(errorContext, err)
 	}
@@ -76,6 +76,119 @@ func (s *Source) GetMangaMetadata(mangaURL, _ string) (*manga.Manga, error) {
 	return mangaReturn, nil
 }
 
+// GetMangaGenres returns the manga genres, without the themes or formats.
+func (s *Source) GetMangaGenres(mangaURL, _ string) ([]string, error) {
+	s.checkClient()
+
+	errorContext := "error while getting manga genres"
+
+	mangaID, err := getMangaSlug(mangaURL)
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+
+	mangaAPIURL := fmt.Sprintf("%s/comic/%s", baseAPIURL, mangaID)
+	var mangaAPIResp getMangaAPIResponse
+	_, err = s.client.Request("GET", mangaAPIURL, nil, &mangaAPIResp)
+	if err != nil {
+		if util.ErrorContains(err, "non-200 status code -> (404)") {
+			return nil, util.AddErrorContext(errorContext, errordefs.ErrMangaNotFound)
+		}
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+
+	return mangaAPIResp.Comic.getGenres(), nil
+}
+
+// getGenres returns the comic genres, without the themes or formats.
+func (c *comic) getGenres() []string {
+	genres := []string{}
+	for _, genre := range c.MDComicMDGenres {
+		if genre.MDGenres.Name != "" && (genre.MDGenres.Group == "" || strings.EqualFold(genre.MDGenres.Group, "genre")) {
+			genres = append(genres, genre.MDGenres.Name)
+		}
+	}
+
+	return genres
+}
+
+// GetExternalIDs returns the manga IDs in MangaUpdates, AniList, and MyAnimeList from its links
+func (s *Source) GetExternalIDs(mangaURL, _ string) (*manga.ExternalIDs, error) {
+	s.checkClient()
+
+	errorContext := "error while getting manga external IDs"
+
+	mangaID, err := getMangaSlug(mangaURL)
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+
+	mangaAPIURL := fmt.Sprintf("%s/comic/%s", baseAPIURL, mangaID)
+	var mangaAPIResp getMangaAPIResponse
+	_, err = s.client.Request("GET", mangaAPIURL, nil, &mangaAPIResp)
+	if err != nil {
+		if util.ErrorContains(err, "non-200 status code -> (404)") {
+			return nil, util.AddErrorContext(errorContext, errordefs.ErrMangaNotFound)
+		}
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+
+	links := map[string]string{}
+	for site, link := range mangaAPIResp.Comic.Links {
+		if id, ok := link.(string); ok {
+			links[site] = id
+		}
+	}
+
+	return manga.ExternalIDsFromLinks(links), nil
+}
+
+// GetSimilarTitles returns the manga genres and the comics recommended by the ComicK users.
+func (s *Source) GetSimilarTitles(mangaURL, _ string) (*models.SimilarTitles, error) {
+	s.checkClient()
+
+	errorContext := "error while getting similar titles"
+
+	mangaID, err := getMangaSlug(mangaURL)
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+
+	mangaAPIURL := fmt.Sprintf("%s/comic/%s", baseAPIURL, mangaID)
+	var mangaAPIResp getMangaAPIResponse
+	_, err = s.client.Request("GET", mangaAPIURL, nil, &mangaAPIResp)
+	if err != nil {
+		if util.ErrorContains(err, "non-200 status code -> (404)") {
+			return nil, util.AddErrorContext(errorContext, errordefs.ErrMangaNotFound)
+		}
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+
+	comic := &mangaAPIResp.Comic
+	similarTitles := &models.SimilarTitles{
+		Genres: comic.getGenres(),
+		Titles: make([]*models.SimilarTitle, 0, len(comic.Recommendations)),
+	}
+	for _, recommendation := range comic.Recommendations {
+		relates := recommendation.Relates
+		if relates.HID == "" {
+			continue
+		}
+		similarTitle := &models.SimilarTitle{
+			URL:      fmt.Sprintf("%s/comic/%s", baseSiteURL, relates.HID),
+			Name:     relates.Title,
+			Source:   "comick",
+			CoverURL: models.DefaultCoverImgURL,
+		}
+		if len(relates.MDCovers) > 0 && relates.MDCovers[0].B2Key != "" {
+			similarTitle.CoverURL = fmt.Sprintf("%s/%s", baseUploadsURL, relates.MDCovers[0].B2Key)
+		}
+		similarTitles.Titles = append(similarTitles.Titles, similarTitle)
+	}
+
+	return similarTitles, nil
+}
+
 type getMangaAPIResponse struct {
 	Comic comic `json:"comic"`
 }
@@ -89,6 +202,23 @@ type comic struct {
 	ID          int       `json:"id"`
 	Year        int       `json:"year"`
 	Status      int       `json:"status"`
+	// Recommendations and genres are only in the comic endpoint
+	Recommendations []struct {
+		Relates struct {
+			Title    string    `json:"title"`
+			HID      string    `json:"hid"`
+			MDCovers []mdCover `json:"md_covers"`
+		} `json:"relates"`
+	} `json:"recommendations"`
+	MDComicMDGenres []struct {
+		MDGenres struct {
+			Name  string `json:"name"`
+			Group string `json:"group"`
+		} `json:"md_genres"`
+	} `json:"md_comic_md_genres"`
+	// Links are the comic IDs in other sites, like {"mu": "pb8uwds", "al": "30002"}.
+	// They're only in the comic endpoint.
+	Links map[string]any `json:"links"`
 }
 
 type mdCover struct {
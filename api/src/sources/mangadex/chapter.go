@@ -2,7 +2,9 @@ package mangadex
 
 import (
 	"fmt"
+	"net/url"
 	"regexp"
+	"strings"
 
 	"github.com/diogovalentte/mantium/api/src/errordefs"
 	"github.com/diogovalentte/mantium/api/src/manga"
@@ -164,7 +166,7 @@ func (s *Source) GetChaptersMetadata(mangaURL, _ string) ([]*manga.Chapter, erro
 	errChan := make(chan error)
 	done := make(chan struct{})
 
-	go generateMangaFeed(s, mangaURL, chaptersChan, errChan)
+	go generateMangaFeed(s, mangaURL, nil, chaptersChan, errChan)
 
 	var chapters []*manga.Chapter
 	go func() {
@@ -187,11 +189,41 @@ func (s *Source) GetChaptersMetadata(mangaURL, _ string) ([]*manga.Chapter, erro
 	}
 }
 
-// generateMangaFeed generates the chapters of a manga and sends them to the channel.
+// GetReleasesMetadata returns all releases of the manga chapters in the languages,
+// from the newest to the oldest chapter. If languages is empty, English is used.
+func (s *Source) GetReleasesMetadata(mangaURL, _ string, languages []string) ([]*manga.Chapter, error) {
+	s.checkClient()
+
+	errorContext := "error while getting releases metadata"
+
+	chaptersChan := make(chan *manga.Chapter)
+	errChan := make(chan error)
+	done := make(chan struct{})
+
+	go generateMangaFeed(s, mangaURL, languages, chaptersChan, errChan)
+
+	var chapters []*manga.Chapter
+	go func() {
+		for chapter := range chaptersChan {
+			chapters = append(chapters, chapter)
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return chapters, nil
+	case err := <-errChan:
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+}
+
+// generateMangaFeed generates the chapters of a manga in the languages and sends them to the channel.
+// If languages is empty, English is used.
 // It sends an error to the error channel if something goes wrong.
 // It closes the chapters channel when there is no more chapters to send.
 // It requests the mangas from the API using the chapter for ordering.
-func generateMangaFeed(s *Source, mangaURL string, chaptersChan chan<- *manga.Chapter, errChan chan<- error) {
+func generateMangaFeed(s *Source, mangaURL string, languages []string, chaptersChan chan<- *manga.Chapter, errChan chan<- error) {
 	defer close(chaptersChan)
 
 	mangaID, err := getMangaID(mangaURL)
@@ -200,12 +232,20 @@ func generateMangaFeed(s *Source, mangaURL string, chaptersChan chan<- *manga.Ch
 		return
 	}
 
+	if len(languages) == 0 {
+		languages = []string{"en"}
+	}
+	var languagesQuery strings.Builder
+	for _, language := range languages {
+		languagesQuery.WriteString("&translatedLanguage[]=" + url.QueryEscape(language))
+	}
+
 	requestLimit := 500
 	requestOffset := 0
 	totalChapters := 1
 
 	for totalChapters >= requestOffset {
-		mangaAPIURL := fmt.Sprintf("%s/manga/%s/feed?contentRating[]=safe&contentRating[]=suggestive&contentRating[]=erotica&contentRating[]=pornographic&translatedLanguage[]=en&order[chapter]=desc&limit=%d&offset=%d", baseAPIURL, mangaID, requestLimit, requestOffset)
+		mangaAPIURL := fmt.Sprintf("%s/manga/%s/feed?contentRating[]=safe&contentRating[]=suggestive&contentRating[]=erotica&contentRating[]=pornographic%s&includes[]=scanlation_group&order[chapter]=desc&limit=%d&offset=%d", baseAPIURL, mangaID, languagesQuery.String(), requestLimit, requestOffset)
 		var feedAPIResp getMangaFeedAPIResponse
 		_, err = s.client.Request("GET", mangaAPIURL, nil, &feedAPIResp)
 		if err != nil {
@@ -247,6 +287,16 @@ func generateMangaFeed(s *Source, mangaURL string, chaptersChan chan<- *manga.Ch
 				return
 			}
 
+			chapterReturn.Language = attributes.TranslatedLanguage
+			for _, relationship := range chapterReq.Relationships {
+				if relationship.Type != "scanlation_group" {
+					continue
+				}
+				if groupName, ok := relationship.Attributes["name"].(string); ok && groupName != "" {
+					chapterReturn.Groups = append(chapterReturn.Groups, groupName)
+				}
+			}
+
 			chaptersChan <- chapterReturn
 		}
 	}
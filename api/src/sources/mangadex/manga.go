@@ -92,6 +92,189 @@ func (s *Source) GetMangaMetadata(mangaURL, _ string) (*manga.Manga, error) {
 	return mangaReturn, nil
 }
 
+// GetMangaRelations returns the mangas related to a manga, like its sequels
+func (s *Source) GetMangaRelations(mangaURL, _ string) ([]*manga.Relation, error) {
+	s.checkClient()
+
+	errorContext := "error while getting manga relations"
+
+	mangadexMangaID, err := getMangaID(mangaURL)
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+
+	mangaAPIURL := fmt.Sprintf("%s/manga/%s?includes[]=manga", baseAPIURL, mangadexMangaID)
+	var mangaAPIResp getMangaAPIResponse
+	_, err = s.client.Request("GET", mangaAPIURL, nil, &mangaAPIResp)
+	if err != nil {
+		if util.ErrorContains(err, "non-200 status code -> (404)") {
+			return nil, util.AddErrorContext(errorContext, errordefs.ErrMangaNotFound)
+		}
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+
+	relations := []*manga.Relation{}
+	for _, relationship := range mangaAPIResp.Data.Relationships {
+		if relationship.Type != "manga" || relationship.Related == "" {
+			continue
+		}
+
+		relation := &manga.Relation{
+			Type:   manga.NormalizeRelationType(relationship.Related),
+			URL:    fmt.Sprintf("%s/title/%s", baseSiteURL, relationship.ID),
+			Source: "mangadex",
+		}
+		if titles, ok := relationship.Attributes["title"].(map[string]interface{}); ok {
+			title := localisedStrings{}
+			for language, t := range titles {
+				if t, ok := t.(string); ok {
+					title[language] = t
+				}
+			}
+			relation.Name = title.get()
+		}
+		relations = append(relations, relation)
+	}
+
+	return relations, nil
+}
+
+// GetMangaGenres returns the manga genres, without the themes or formats.
+func (s *Source) GetMangaGenres(mangaURL, _ string) ([]string, error) {
+	s.checkClient()
+
+	errorContext := "error while getting manga genres"
+
+	mangadexMangaID, err := getMangaID(mangaURL)
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+
+	mangaAPIURL := fmt.Sprintf("%s/manga/%s", baseAPIURL, mangadexMangaID)
+	var mangaAPIResp getMangaAPIResponse
+	_, err = s.client.Request("GET", mangaAPIURL, nil, &mangaAPIResp)
+	if err != nil {
+		if util.ErrorContains(err, "non-200 status code -> (404)") {
+			return nil, util.AddErrorContext(errorContext, errordefs.ErrMangaNotFound)
+		}
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+
+	return getGenres(mangaAPIResp.Data.Attributes.Tags), nil
+}
+
+// GetExternalIDs returns the manga IDs in MangaUpdates, AniList, and MyAnimeList from its links
+func (s *Source) GetExternalIDs(mangaURL, _ string) (*manga.ExternalIDs, error) {
+	s.checkClient()
+
+	errorContext := "error while getting manga external IDs"
+
+	mangadexMangaID, err := getMangaID(mangaURL)
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+
+	mangaAPIURL := fmt.Sprintf("%s/manga/%s", baseAPIURL, mangadexMangaID)
+	var mangaAPIResp getMangaAPIResponse
+	_, err = s.client.Request("GET", mangaAPIURL, nil, &mangaAPIResp)
+	if err != nil {
+		if util.ErrorContains(err, "non-200 status code -> (404)") {
+			return nil, util.AddErrorContext(errorContext, errordefs.ErrMangaNotFound)
+		}
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+
+	return manga.ExternalIDsFromLinks(mangaAPIResp.Data.Attributes.Links), nil
+}
+
+// similarTitlesGenres is the max number of the manga genres
+// the similar titles must have, so the search isn't too narrow.
+const similarTitlesGenres = 3
+
+// GetSimilarTitles returns the manga genres and the most followed mangas with its first genres,
+// as MangaDex doesn't have recommendations
+func (s *Source) GetSimilarTitles(mangaURL, _ string) (*models.SimilarTitles, error) {
+	s.checkClient()
+
+	errorContext := "error while getting similar titles"
+
+	mangadexMangaID, err := getMangaID(mangaURL)
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+
+	mangaAPIURL := fmt.Sprintf("%s/manga/%s", baseAPIURL, mangadexMangaID)
+	var mangaAPIResp getMangaAPIResponse
+	_, err = s.client.Request("GET", mangaAPIURL, nil, &mangaAPIResp)
+	if err != nil {
+		if util.ErrorContains(err, "non-200 status code -> (404)") {
+			return nil, util.AddErrorContext(errorContext, errordefs.ErrMangaNotFound)
+		}
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+
+	similarTitles := &models.SimilarTitles{
+		Genres: getGenres(mangaAPIResp.Data.Attributes.Tags),
+		Titles: []*models.SimilarTitle{},
+	}
+
+	var genresQuery string
+	var genresCount int
+	for _, t := range mangaAPIResp.Data.Attributes.Tags {
+		if t.Attributes.Group == "genre" && genresCount < similarTitlesGenres {
+			genresQuery += "&includedTags[]=" + t.ID
+			genresCount++
+		}
+	}
+	if genresCount == 0 {
+		return similarTitles, nil
+	}
+
+	searchURL := fmt.Sprintf("%s/manga?includes[]=cover_art&limit=11&includedTagsMode=AND&order[followedCount]=desc&contentRating[]=safe&contentRating[]=suggestive%s", baseAPIURL, genresQuery)
+	var searchAPIResp searchMangaAPIResponse
+	_, err = s.client.Request("GET", searchURL, nil, &searchAPIResp)
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+
+	for _, mangaData := range searchAPIResp.Data {
+		if mangaData.ID == mangadexMangaID {
+			continue
+		}
+
+		similarTitle := &models.SimilarTitle{
+			URL:      fmt.Sprintf("%s/title/%s", baseSiteURL, mangaData.ID),
+			Name:     mangaData.Attributes.Title.get(),
+			Source:   "mangadex",
+			CoverURL: models.DefaultCoverImgURL,
+			Genres:   getGenres(mangaData.Attributes.Tags),
+		}
+		for _, relationship := range mangaData.Relationships {
+			if relationship.Type == "cover_art" {
+				if coverFileName, ok := relationship.Attributes["fileName"].(string); ok {
+					similarTitle.CoverURL = fmt.Sprintf("%s/covers/%s/%s", baseUploadsURL, mangaData.ID, coverFileName)
+					break
+				}
+			}
+		}
+		similarTitles.Titles = append(similarTitles.Titles, similarTitle)
+	}
+
+	return similarTitles, nil
+}
+
+// getGenres returns the names of the tags in the genre group
+func getGenres(tags []tag) []string {
+	genres := []string{}
+	for _, t := range tags {
+		if t.Attributes.Group == "genre" {
+			genres = append(genres, t.Attributes.Name.get())
+		}
+	}
+
+	return genres
+}
+
 type getMangaAPIResponse struct {
 	Result   string `json:"result"`
 	Response string `json:"response"`
@@ -9,6 +9,8 @@ type genericRelationship struct {
 	Attributes map[string]interface{} `json:"attributes"`
 	ID         string                 `json:"id"`
 	Type       string                 `json:"type"`
+	// Related is the relation type of the relationships with other mangas, like "sequel".
+	Related string `json:"related"`
 }
 
 type localisedStrings map[string]string
@@ -82,6 +84,7 @@ type mangaAttributes struct {
 	AltTitles             []localisedStrings `json:"altTitles"`
 	Year                  int                `json:"year"`
 	LatestUploadedChapter string             `json:"latestUploadedChapter"`
+	Tags                  []tag              `json:"tags"`
 }
 
 type coverAttributes map[string]interface{}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -72,6 +73,154 @@ func (s *Source) GetMangaMetadata(mangaURL, mangaInternalID string) (*manga.Mang
 	return mangaReturn, nil
 }
 
+// GetMangaRelations returns the series related to a manga, like its sequels.
+func (s *Source) GetMangaRelations(mangaURL, mangaInternalID string) ([]*manga.Relation, error) {
+	s.checkClient()
+
+	errorContext := "error while getting manga relations"
+	var err error
+
+	if mangaInternalID == "" {
+		mangaInternalID, err = s.getMangaIDFromURL(mangaURL)
+		if err != nil {
+			return nil, util.AddErrorContext(errorContext, err)
+		}
+	}
+
+	mangaAPIURL := fmt.Sprintf("%s/v1/series/%s", baseAPIURL, mangaInternalID)
+	var mangaAPIResp seriesAPIResp
+	_, err = s.client.Request("GET", mangaAPIURL, nil, &mangaAPIResp)
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+
+	relations := make([]*manga.Relation, 0, len(mangaAPIResp.RelatedSeries))
+	for _, related := range mangaAPIResp.RelatedSeries {
+		if related.RelatedSeriesURL == "" {
+			continue
+		}
+		relations = append(relations, &manga.Relation{
+			Type:       manga.NormalizeRelationType(related.RelationType),
+			Name:       related.RelatedSeriesName,
+			URL:        related.RelatedSeriesURL,
+			InternalID: strconv.Itoa(related.RelatedSeriesID),
+			Source:     "mangaupdates",
+		})
+	}
+
+	return relations, nil
+}
+
+// GetMangaGenres returns the manga genres.
+func (s *Source) GetMangaGenres(mangaURL, mangaInternalID string) ([]string, error) {
+	s.checkClient()
+
+	errorContext := "error while getting manga genres"
+	var err error
+
+	if mangaInternalID == "" {
+		mangaInternalID, err = s.getMangaIDFromURL(mangaURL)
+		if err != nil {
+			return nil, util.AddErrorContext(errorContext, err)
+		}
+	}
+
+	mangaAPIURL := fmt.Sprintf("%s/v1/series/%s", baseAPIURL, mangaInternalID)
+	var mangaAPIResp seriesAPIResp
+	_, err = s.client.Request("GET", mangaAPIURL, nil, &mangaAPIResp)
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+
+	return mangaAPIResp.getGenres(), nil
+}
+
+func (r *seriesAPIResp) getGenres() []string {
+	genres := make([]string, 0, len(r.Genres))
+	for _, genre := range r.Genres {
+		genres = append(genres, genre.Genre)
+	}
+
+	return genres
+}
+
+// GetExternalIDs returns the manga MangaUpdates series ID, which is the manga internal ID.
+// MangaUpdates doesn't list the manga IDs in other sites.
+func (s *Source) GetExternalIDs(mangaURL, mangaInternalID string) (*manga.ExternalIDs, error) {
+	s.checkClient()
+
+	errorContext := "error while getting manga external IDs"
+
+	if mangaInternalID == "" {
+		mangaInternalID = manga.MangaUpdatesIDFromURL(mangaURL)
+	}
+	if mangaInternalID == "" {
+		var err error
+		mangaInternalID, err = s.getMangaIDFromURL(mangaURL)
+		if err != nil {
+			return nil, util.AddErrorContext(errorContext, err)
+		}
+	}
+
+	return &manga.ExternalIDs{MangaUpdatesID: mangaInternalID}, nil
+}
+
+// GetSimilarTitles returns the manga genres and the series recommended by the MangaUpdates users.
+func (s *Source) GetSimilarTitles(mangaURL, mangaInternalID string) (*models.SimilarTitles, error) {
+	s.checkClient()
+
+	errorContext := "error while getting similar titles"
+	var err error
+
+	if mangaInternalID == "" {
+		mangaInternalID, err = s.getMangaIDFromURL(mangaURL)
+		if err != nil {
+			return nil, util.AddErrorContext(errorContext, err)
+		}
+	}
+
+	mangaAPIURL := fmt.Sprintf("%s/v1/series/%s", baseAPIURL, mangaInternalID)
+	var mangaAPIResp seriesAPIResp
+	_, err = s.client.Request("GET", mangaAPIURL, nil, &mangaAPIResp)
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+
+	similarTitles := &models.SimilarTitles{
+		Genres: mangaAPIResp.getGenres(),
+		Titles: make([]*models.SimilarTitle, 0, len(mangaAPIResp.Recommendations)),
+	}
+
+	recommendations := mangaAPIResp.Recommendations
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		return recommendations[i].Weight > recommendations[j].Weight
+	})
+	for _, recommendation := range recommendations {
+		if recommendation.SeriesURL == "" {
+			continue
+		}
+		coverURL, ok := recommendation.SeriesImage.URL["original"]
+		if !ok {
+			for _, url := range recommendation.SeriesImage.URL {
+				coverURL = url
+				break
+			}
+		}
+		if coverURL == "" {
+			coverURL = models.DefaultCoverImgURL
+		}
+		similarTitles.Titles = append(similarTitles.Titles, &models.SimilarTitle{
+			URL:        recommendation.SeriesURL,
+			InternalID: strconv.Itoa(recommendation.SeriesID),
+			Name:       recommendation.SeriesName,
+			Source:     "mangaupdates",
+			CoverURL:   coverURL,
+		})
+	}
+
+	return similarTitles, nil
+}
+
 func (s *Source) Search(term string, limit int) ([]*models.MangaSearchResult, error) {
 	s.checkClient()
 
@@ -134,11 +283,29 @@ type seriesAPIResp struct {
 	Image struct {
 		URL map[string]string `json:"url"`
 	} `json:"image"`
-	Title       string `json:"title"`
-	URL         string `json:"url"`
-	Description string `json:"description"`
-	Year        string `json:"year"`
-	ID          int    `json:"series_id"`
+	Title         string `json:"title"`
+	URL           string `json:"url"`
+	Description   string `json:"description"`
+	Year          string `json:"year"`
+	RelatedSeries []struct {
+		RelationType      string `json:"relation_type"`
+		RelatedSeriesName string `json:"related_series_name"`
+		RelatedSeriesURL  string `json:"related_series_url"`
+		RelatedSeriesID   int    `json:"related_series_id"`
+	} `json:"related_series"`
+	Genres []struct {
+		Genre string `json:"genre"`
+	} `json:"genres"`
+	Recommendations []struct {
+		SeriesImage struct {
+			URL map[string]string `json:"url"`
+		} `json:"series_image"`
+		SeriesName string `json:"series_name"`
+		SeriesURL  string `json:"series_url"`
+		SeriesID   int    `json:"series_id"`
+		Weight     int    `json:"weight"`
+	} `json:"recommendations"`
+	ID int `json:"series_id"`
 }
 
 func (s *Source) getMangaIDFromURL(mangaURL string) (string, error) {
@@ -18,6 +18,72 @@ type Source interface {
 	GetName() string
 }
 
+// URLMatcher is implemented by the sources whose manga URLs can't be matched
+// by the source name in the URL domain, like the scraper and Suwayomi sources.
+type URLMatcher interface {
+	// MatchesURL returns true if the manga URL is from the source.
+	MatchesURL(mangaURL string) bool
+}
+
+// GenresGetter is implemented by the sources that list the genres of a manga,
+// like MangaDex. The genres are used in the reading stats.
+type GenresGetter interface {
+	// GetMangaGenres returns the manga genres, like "Action" and "Drama".
+	GetMangaGenres(mangaURL, mangaInternalID string) ([]string, error)
+}
+
+// ReleasesLister is implemented by the sources with multiple releases of the same
+// chapter, in different languages or by different scanlation groups, like MangaDex.
+// The manga chapter preferences are used to choose between the releases.
+type ReleasesLister interface {
+	// GetReleasesMetadata returns all releases of the manga chapters in the languages,
+	// from the newest to the oldest chapter, with their language and groups.
+	// If languages is empty, the source's default language is used.
+	GetReleasesMetadata(mangaURL, mangaInternalID string, languages []string) ([]*manga.Chapter, error)
+}
+
+// RelationsGetter is implemented by the sources that list the titles related
+// to a manga, like its sequels and spin-offs, like MangaDex and MangaUpdates.
+type RelationsGetter interface {
+	// GetMangaRelations returns the titles related to the manga.
+	// The relation types are normalized with manga.NormalizeRelationType.
+	GetMangaRelations(mangaURL, mangaInternalID string) ([]*manga.Relation, error)
+}
+
+// SimilarTitlesLister is implemented by the sources that list titles similar to
+// a manga, like MangaUpdates' recommendations. They're used to recommend titles
+// based on the mangas in the library.
+type SimilarTitlesLister interface {
+	// GetSimilarTitles returns the manga genres and the titles similar to it.
+	GetSimilarTitles(mangaURL, mangaInternalID string) (*SimilarTitles, error)
+}
+
+// ExternalIDsGetter is implemented by the sources that know the IDs of a manga in
+// sites that identify works regardless of the source, like MangaUpdates and AniList.
+// The IDs are used to know when mangas from different sources are the same work.
+type ExternalIDsGetter interface {
+	// GetExternalIDs returns the manga external IDs. The IDs the source doesn't know are empty.
+	GetExternalIDs(mangaURL, mangaInternalID string) (*manga.ExternalIDs, error)
+}
+
+// SimilarTitles are the genres of a manga and the titles similar to it.
+type SimilarTitles struct {
+	Genres []string
+	// Titles are sorted from the most to the least similar.
+	Titles []*SimilarTitle
+}
+
+// SimilarTitle is a title similar to a manga.
+type SimilarTitle struct {
+	URL        string
+	InternalID string
+	Name       string
+	Source     string
+	CoverURL   string
+	// Genres are empty if the source doesn't list the similar titles' genres.
+	Genres []string
+}
+
 type MangaSearchResult struct {
 	URL            string
 	Name           string
@@ -0,0 +1,205 @@
+package scraper
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/andybalholm/cascadia"
+	"gopkg.in/yaml.v3"
+
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// Config defines a scraper source. It's usually loaded from a YAML or JSON file.
+type Config struct {
+	// Name is the source name, like "mysite". The source is used for
+	// the mangas whose URL host is the BaseURL host.
+	Name string `yaml:"name" json:"name"`
+	// BaseURL is the site URL, like "https://mysite.com".
+	BaseURL string `yaml:"base_url" json:"base_url"`
+	// SearchURL is the search page URL. "{term}" is replaced by the search term.
+	// If it's empty, the source doesn't support searching.
+	SearchURL string `yaml:"search_url" json:"search_url"`
+	// Manga are the fields of the manga page.
+	Manga struct {
+		Name  Field `yaml:"name" json:"name"`
+		Cover Field `yaml:"cover" json:"cover"`
+	} `yaml:"manga" json:"manga"`
+	// Chapters are the fields of the chapter list in the manga page.
+	Chapters struct {
+		// List is the selector of each chapter element in the manga page.
+		// The other chapter fields are selected inside each chapter element.
+		List string `yaml:"list" json:"list"`
+		// Number is the chapter number field. If it's empty, the number is the first number in the chapter name.
+		Number Field `yaml:"number" json:"number"`
+		Name   Field `yaml:"name" json:"name"`
+		// URL is the chapter URL field. It defaults to the "href" attribute of the chapter element.
+		URL  Field `yaml:"url" json:"url"`
+		Date Field `yaml:"date" json:"date"`
+		// DateFormat is the Go layout of the chapter date, like "January 2, 2006".
+		DateFormat string `yaml:"date_format" json:"date_format"`
+		// OldestFirst is true if the page lists the oldest chapter first.
+		OldestFirst bool `yaml:"oldest_first" json:"oldest_first"`
+	} `yaml:"chapters" json:"chapters"`
+	// Search are the fields of the search page.
+	Search struct {
+		// Results is the selector of each search result element.
+		// The other search fields are selected inside each result element.
+		Results     string `yaml:"results" json:"results"`
+		Name        Field  `yaml:"name" json:"name"`
+		URL         Field  `yaml:"url" json:"url"`
+		Cover       Field  `yaml:"cover" json:"cover"`
+		LastChapter Field  `yaml:"last_chapter" json:"last_chapter"`
+	} `yaml:"search" json:"search"`
+}
+
+// Field selects a value in a page.
+type Field struct {
+	// Selector is the CSS selector of the element. If it's empty, the current element is used,
+	// like the chapter element.
+	Selector string `yaml:"selector" json:"selector"`
+	// Attr is the attribute with the value, like "href". If it's empty, the element text is used.
+	Attr string `yaml:"attr" json:"attr"`
+	// Regex is matched against the value. If it has a capturing group, the value is the first group, else the whole match.
+	Regex string `yaml:"regex" json:"regex"`
+}
+
+// IsEmpty returns true if the field selects nothing.
+func (f Field) IsEmpty() bool {
+	return f.Selector == "" && f.Attr == "" && f.Regex == ""
+}
+
+func (f Field) validate(name string) error {
+	if f.Selector != "" {
+		if _, err := cascadia.Compile(f.Selector); err != nil {
+			return fmt.Errorf("invalid %s selector '%s': %s", name, f.Selector, err)
+		}
+	}
+	if f.Regex != "" {
+		if _, err := regexp.Compile(f.Regex); err != nil {
+			return fmt.Errorf("invalid %s regex '%s': %s", name, f.Regex, err)
+		}
+	}
+
+	return nil
+}
+
+var nameRegex = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+// Validate returns an error if the config is invalid.
+// The builtInSources can't be used as the config name.
+func (c *Config) Validate(builtInSources []string) error {
+	contextError := fmt.Sprintf("error validating scraper source '%s'", c.Name)
+
+	if !nameRegex.MatchString(c.Name) {
+		return util.AddErrorContext(contextError, fmt.Errorf("name must have only lower case letters, numbers, '-', and '_'"))
+	}
+	if slices.Contains(builtInSources, c.Name) {
+		return util.AddErrorContext(contextError, fmt.Errorf("name is already used by a built-in source"))
+	}
+
+	baseURL, err := url.Parse(c.BaseURL)
+	if err != nil || (baseURL.Scheme != "http" && baseURL.Scheme != "https") || baseURL.Host == "" {
+		return util.AddErrorContext(contextError, fmt.Errorf("base_url must be an HTTP URL"))
+	}
+
+	if c.Manga.Name.IsEmpty() {
+		return util.AddErrorContext(contextError, fmt.Errorf("manga.name is required"))
+	}
+	if c.Chapters.List == "" {
+		return util.AddErrorContext(contextError, fmt.Errorf("chapters.list is required"))
+	}
+	if _, err := cascadia.Compile(c.Chapters.List); err != nil {
+		return util.AddErrorContext(contextError, fmt.Errorf("invalid chapters.list selector '%s': %s", c.Chapters.List, err))
+	}
+	if !c.Chapters.Date.IsEmpty() && c.Chapters.DateFormat == "" {
+		return util.AddErrorContext(contextError, fmt.Errorf("chapters.date_format is required with chapters.date"))
+	}
+
+	fields := map[string]Field{
+		"manga.name":          c.Manga.Name,
+		"manga.cover":         c.Manga.Cover,
+		"chapters.number":     c.Chapters.Number,
+		"chapters.name":       c.Chapters.Name,
+		"chapters.url":        c.Chapters.URL,
+		"chapters.date":       c.Chapters.Date,
+		"search.name":         c.Search.Name,
+		"search.url":          c.Search.URL,
+		"search.cover":        c.Search.Cover,
+		"search.last_chapter": c.Search.LastChapter,
+	}
+	for name, field := range fields {
+		if err := field.validate(name); err != nil {
+			return util.AddErrorContext(contextError, err)
+		}
+	}
+
+	if c.SearchURL != "" {
+		if !strings.Contains(c.SearchURL, "{term}") {
+			return util.AddErrorContext(contextError, fmt.Errorf("search_url must have '{term}'"))
+		}
+		if c.Search.Results == "" || c.Search.Name.IsEmpty() || c.Search.URL.IsEmpty() {
+			return util.AddErrorContext(contextError, fmt.Errorf("search.results, search.name, and search.url are required with search_url"))
+		}
+		if _, err := cascadia.Compile(c.Search.Results); err != nil {
+			return util.AddErrorContext(contextError, fmt.Errorf("invalid search.results selector '%s': %s", c.Search.Results, err))
+		}
+	}
+
+	return nil
+}
+
+// ParseConfig parses a YAML or JSON config.
+func ParseConfig(data []byte) (*Config, error) {
+	var c Config
+	err := yaml.Unmarshal(data, &c)
+	if err != nil {
+		return nil, util.AddErrorContext("error parsing scraper source config", err)
+	}
+
+	return &c, nil
+}
+
+// LoadConfigs loads and validates the configs of the .yaml, .yml, and .json files in the directory.
+func LoadConfigs(dir string, builtInSources []string) ([]*Config, error) {
+	contextError := fmt.Sprintf("error loading scraper sources from '%s'", dir)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, util.AddErrorContext(contextError, err)
+	}
+
+	configs := []*Config{}
+	names := []string{}
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml" && ext != ".json") {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, util.AddErrorContext(contextError, err)
+		}
+		c, err := ParseConfig(data)
+		if err != nil {
+			return nil, util.AddErrorContext(contextError, util.AddErrorContext(entry.Name(), err))
+		}
+		err = c.Validate(builtInSources)
+		if err != nil {
+			return nil, util.AddErrorContext(contextError, util.AddErrorContext(entry.Name(), err))
+		}
+		if slices.Contains(names, c.Name) {
+			return nil, util.AddErrorContext(contextError, fmt.Errorf("source '%s' is defined in more than one file", c.Name))
+		}
+		names = append(names, c.Name)
+		configs = append(configs, c)
+	}
+
+	return configs, nil
+}
@@ -0,0 +1,340 @@
+// Package scraper provides a generic implementation of the manga.Source interface
+// for sites scraped with CSS selectors defined in a config, like a YAML file.
+package scraper
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gocolly/colly/v2"
+
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/sources/models"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// Source is the struct for a scraper source
+type Source struct {
+	config *Config
+}
+
+// New returns a source that scrapes the site defined in the config.
+// The config should be validated before.
+func New(config *Config) *Source {
+	return &Source{config: config}
+}
+
+func (s *Source) GetName() string {
+	return s.config.Name
+}
+
+// MatchesURL returns true if the URL host is the site host, so sites
+// with similar domains, like a built-in source's domain, aren't matched.
+func (s *Source) MatchesURL(mangaURL string) bool {
+	u, err := url.Parse(mangaURL)
+	if err != nil {
+		return false
+	}
+	baseURL, err := url.Parse(s.config.BaseURL)
+	if err != nil {
+		return false
+	}
+
+	return u.Host != "" && strings.EqualFold(u.Host, baseURL.Host)
+}
+
+var userAgent = "Mozilla/5.0 (X11; Linux x86_64; rv:30.0) Gecko/20100101 Firefox/30.0"
+
+// chapterNumberRegex gets the chapter number from the chapter name when there's no number field.
+var chapterNumberRegex = regexp.MustCompile(`\d+(?:\.\d+)?`)
+
+// visit gets the page and returns its document.
+func visit(pageURL string) (*goquery.Selection, error) {
+	c := colly.NewCollector(
+		colly.UserAgent(userAgent),
+	)
+
+	var doc *goquery.Selection
+	c.OnHTML("html", func(e *colly.HTMLElement) {
+		doc = e.DOM
+	})
+
+	err := c.Visit(pageURL)
+	if err != nil {
+		if err.Error() == "Not Found" {
+			return nil, errordefs.ErrMangaNotFound
+		}
+		return nil, util.AddErrorContext("error while visiting URL", err)
+	}
+	if doc == nil {
+		return nil, fmt.Errorf("page '%s' is not an HTML page", pageURL)
+	}
+
+	return doc, nil
+}
+
+// GetMangaMetadata scrapes the manga page and return the manga data
+func (s *Source) GetMangaMetadata(mangaURL, _ string) (*manga.Manga, error) {
+	errorContext := "error while getting manga metadata"
+
+	doc, err := visit(mangaURL)
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+
+	mangaReturn := &manga.Manga{
+		Source: s.config.Name,
+		URL:    mangaURL,
+		Name:   s.config.Manga.Name.value(doc),
+	}
+	if mangaReturn.Name == "" {
+		return nil, util.AddErrorContext(errorContext, errordefs.ErrMangaNotFound)
+	}
+
+	if !s.config.Manga.Cover.IsEmpty() {
+		coverURL := resolveURL(mangaURL, s.config.Manga.Cover.value(doc))
+		if coverURL != "" {
+			coverImg, resized, err := util.GetImageFromURL(coverURL, 3, 1*time.Second)
+			if err == nil {
+				mangaReturn.CoverImgURL = coverURL
+				mangaReturn.CoverImgResized = resized
+				mangaReturn.CoverImg = coverImg
+			}
+		}
+	}
+
+	chapters, err := s.parseChapters(mangaURL, doc)
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+	if len(chapters) > 0 {
+		mangaReturn.LastReleasedChapter = chapters[0]
+	}
+
+	return mangaReturn, nil
+}
+
+// GetChapterMetadata returns a chapter by its chapter or URL
+func (s *Source) GetChapterMetadata(mangaURL, _, chapter, chapterURL, _ string) (*manga.Chapter, error) {
+	errorContext := "error while getting metadata of chapter"
+
+	if chapter == "" && chapterURL == "" {
+		return nil, util.AddErrorContext(errorContext, errordefs.ErrChapterHasNoChapterOrURL)
+	}
+
+	chapters, err := s.GetChaptersMetadata(mangaURL, "")
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+
+	for _, c := range chapters {
+		if (chapter != "" && c.Chapter == chapter) || (chapter == "" && c.URL == chapterURL) {
+			return c, nil
+		}
+	}
+	if chapter != "" && chapterURL != "" {
+		for _, c := range chapters {
+			if c.URL == chapterURL {
+				return c, nil
+			}
+		}
+	}
+
+	return nil, util.AddErrorContext(errorContext, errordefs.ErrChapterNotFound)
+}
+
+// GetLastChapterMetadata scrapes the manga page and return the latest chapter
+func (s *Source) GetLastChapterMetadata(mangaURL, _ string) (*manga.Chapter, error) {
+	errorContext := "error while getting last chapter metadata"
+
+	chapters, err := s.GetChaptersMetadata(mangaURL, "")
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+	if len(chapters) == 0 {
+		return nil, util.AddErrorContext(errorContext, errordefs.ErrChapterNotFound)
+	}
+
+	return chapters[0], nil
+}
+
+// GetChaptersMetadata scrapes the manga page and return the chapters, from the newest to the oldest
+func (s *Source) GetChaptersMetadata(mangaURL, _ string) ([]*manga.Chapter, error) {
+	errorContext := "error while getting chapters metadata"
+
+	doc, err := visit(mangaURL)
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+
+	chapters, err := s.parseChapters(mangaURL, doc)
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+
+	return chapters, nil
+}
+
+// parseChapters returns the chapters in the manga page, from the newest to the oldest.
+func (s *Source) parseChapters(mangaURL string, doc *goquery.Selection) ([]*manga.Chapter, error) {
+	config := s.config.Chapters
+	urlField := config.URL
+	if urlField.IsEmpty() {
+		urlField = Field{Attr: "href"}
+	}
+
+	chapters := []*manga.Chapter{}
+	var sharedErr error
+	doc.Find(config.List).EachWithBreak(func(_ int, e *goquery.Selection) bool {
+		chapter := &manga.Chapter{
+			Name: config.Name.value(e),
+			URL:  resolveURL(mangaURL, urlField.value(e)),
+			Type: 1,
+		}
+		if config.Number.IsEmpty() {
+			chapter.Chapter = chapterNumberRegex.FindString(chapter.Name)
+		} else {
+			chapter.Chapter = config.Number.value(e)
+		}
+		if chapter.Chapter == "" {
+			chapter.Chapter = chapter.Name
+		}
+		if chapter.Name == "" {
+			chapter.Name = chapter.Chapter
+		}
+		if chapter.Chapter == "" {
+			return true
+		}
+		if chapter.URL == "" {
+			sharedErr = util.AddErrorContext(fmt.Sprintf("chapter '%s'", chapter.Chapter), errordefs.ErrChapterURLNotFound)
+			return false
+		}
+
+		if !config.Date.IsEmpty() {
+			date := config.Date.value(e)
+			releaseTime, err := time.Parse(config.DateFormat, date)
+			if err != nil {
+				sharedErr = util.AddErrorContext(fmt.Sprintf("error parsing chapter '%s' date '%s' with format '%s'", chapter.Chapter, date, config.DateFormat), err)
+				return false
+			}
+			chapter.UpdatedAt = releaseTime.Truncate(time.Second)
+		}
+
+		chapters = append(chapters, chapter)
+		return true
+	})
+	if sharedErr != nil {
+		return nil, sharedErr
+	}
+
+	if config.OldestFirst {
+		for i, j := 0, len(chapters)-1; i < j; i, j = i+1, j-1 {
+			chapters[i], chapters[j] = chapters[j], chapters[i]
+		}
+	}
+
+	return chapters, nil
+}
+
+// Search searches the site using the search URL.
+// Only the first page of results is used.
+func (s *Source) Search(term string, limit int) ([]*models.MangaSearchResult, error) {
+	errorContext := "error while searching manga"
+
+	if s.config.SearchURL == "" {
+		return nil, util.AddErrorContext(errorContext, fmt.Errorf("source '%s' doesn't support searching", s.config.Name))
+	}
+
+	searchURL := strings.ReplaceAll(s.config.SearchURL, "{term}", url.QueryEscape(term))
+	doc, err := visit(searchURL)
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+
+	config := s.config.Search
+	mangaSearchResults := []*models.MangaSearchResult{}
+	doc.Find(config.Results).EachWithBreak(func(_ int, e *goquery.Selection) bool {
+		if len(mangaSearchResults) >= limit {
+			return false
+		}
+
+		mangaSearchResult := &models.MangaSearchResult{
+			Source:      s.config.Name,
+			Name:        config.Name.value(e),
+			URL:         resolveURL(searchURL, config.URL.value(e)),
+			CoverURL:    resolveURL(searchURL, config.Cover.value(e)),
+			LastChapter: config.LastChapter.value(e),
+		}
+		if mangaSearchResult.Name == "" || mangaSearchResult.URL == "" {
+			return true
+		}
+		if mangaSearchResult.CoverURL == "" {
+			mangaSearchResult.CoverURL = models.DefaultCoverImgURL
+		}
+
+		mangaSearchResults = append(mangaSearchResults, mangaSearchResult)
+		return true
+	})
+
+	return mangaSearchResults, nil
+}
+
+// value returns the field value in the element, trimmed and with the spaces collapsed.
+func (f Field) value(e *goquery.Selection) string {
+	if f.IsEmpty() {
+		return ""
+	}
+
+	selection := e
+	if f.Selector != "" {
+		selection = e.Find(f.Selector).First()
+	}
+	if selection.Length() == 0 {
+		return ""
+	}
+
+	var value string
+	if f.Attr != "" {
+		value = selection.AttrOr(f.Attr, "")
+	} else {
+		value = selection.Text()
+	}
+	value = strings.Join(strings.Fields(value), " ")
+
+	if f.Regex != "" {
+		match := regexp.MustCompile(f.Regex).FindStringSubmatch(value)
+		switch {
+		case len(match) == 0:
+			value = ""
+		case len(match) > 1:
+			value = strings.TrimSpace(match[1])
+		default:
+			value = match[0]
+		}
+	}
+
+	return value
+}
+
+// resolveURL returns the URL relative to the page URL as an absolute URL.
+func resolveURL(pageURL, ref string) string {
+	if ref == "" {
+		return ""
+	}
+
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return ref
+	}
+	resolved, err := base.Parse(ref)
+	if err != nil {
+		return ref
+	}
+
+	return resolved.String()
+}
@@ -0,0 +1,257 @@
+package scraper
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+const configYAML = `
+name: localhost
+base_url: http://localhost
+search_url: "{base}/search?q={term}"
+manga:
+  name:
+    selector: h1.title
+    regex: "(.+?)(?: Raw)?$"
+  cover:
+    selector: div.thumb img
+    attr: src
+chapters:
+  list: ul.chapters > li
+  number:
+    attr: data-num
+  name:
+    selector: span.name
+  url:
+    selector: a
+    attr: href
+  date:
+    selector: span.date
+  date_format: January 2, 2006
+search:
+  results: div.results > div
+  name:
+    selector: a
+  url:
+    selector: a
+    attr: href
+  last_chapter:
+    selector: span.last
+    regex: "Chapter (\\d+)"
+`
+
+const mangaPage = `<html><body>
+<h1 class="title">My Manga Raw</h1>
+<div class="thumb"><img src="/cover.jpg"></div>
+<ul class="chapters">
+  <li data-num="12"><a href="/my-manga-chapter-12/"><span class="name">Chapter 12</span> <span class="date">November 21, 2023</span></a></li>
+  <li data-num="11"><a href="/my-manga-chapter-11/"><span class="name">Chapter 11</span> <span class="date">November 14, 2023</span></a></li>
+</ul>
+</body></html>`
+
+const searchPage = `<html><body>
+<div class="results">
+  <div><a href="/manga/my-manga">My Manga</a><span class="last">Chapter 12</span></div>
+  <div><a href="/manga/other-manga">Other Manga</a></div>
+</div>
+</body></html>`
+
+func newTestSource(t *testing.T) (*Source, string) {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/manga/my-manga":
+			w.Write([]byte(mangaPage))
+		case "/search":
+			if r.URL.Query().Get("q") != "my manga" {
+				w.Write([]byte("<html><body></body></html>"))
+				return
+			}
+			w.Write([]byte(searchPage))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	baseURL := server.URL
+
+	config, err := ParseConfig([]byte(strings.ReplaceAll(configYAML, "{base}", baseURL)))
+	if err != nil {
+		t.Fatalf("error parsing config: %v", err)
+	}
+	config.BaseURL = baseURL
+	if err := config.Validate([]string{"mangadex"}); err != nil {
+		t.Fatalf("error validating config: %v", err)
+	}
+
+	return New(config), baseURL
+}
+
+func TestGetMangaMetadata(t *testing.T) {
+	source, baseURL := newTestSource(t)
+
+	t.Run("Should scrape the manga metadata", func(t *testing.T) {
+		m, err := source.GetMangaMetadata(baseURL+"/manga/my-manga", "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if m.Name != "My Manga" || m.Source != "localhost" {
+			t.Fatalf("expected manga My Manga from localhost, got %s from %s", m.Name, m.Source)
+		}
+		expected := &manga.Chapter{
+			Chapter:   "12",
+			Name:      "Chapter 12",
+			URL:       baseURL + "/my-manga-chapter-12/",
+			UpdatedAt: time.Date(2023, 11, 21, 0, 0, 0, 0, time.UTC),
+			Type:      1,
+		}
+		if m.LastReleasedChapter == nil || !reflect.DeepEqual(m.LastReleasedChapter, expected) {
+			t.Fatalf("expected last released chapter %s, got %s", expected, m.LastReleasedChapter)
+		}
+	})
+	t.Run("Should not scrape a manga not found", func(t *testing.T) {
+		_, err := source.GetMangaMetadata(baseURL+"/manga/salt", "")
+		if err == nil || !util.ErrorContains(err, errordefs.ErrMangaNotFound.Error()) {
+			t.Fatalf("expected manga not found error, got %v", err)
+		}
+	})
+}
+
+func TestGetChaptersMetadata(t *testing.T) {
+	source, baseURL := newTestSource(t)
+
+	chapters, err := source.GetChaptersMetadata(baseURL+"/manga/my-manga", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(chapters) != 2 || chapters[0].Chapter != "12" || chapters[1].Chapter != "11" {
+		t.Fatalf("expected chapters 12 and 11, got %v", chapters)
+	}
+
+	chapter, err := source.GetChapterMetadata(baseURL+"/manga/my-manga", "", "", baseURL+"/my-manga-chapter-11/", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if chapter.Chapter != "11" {
+		t.Fatalf("expected chapter 11, got %s", chapter.Chapter)
+	}
+
+	_, err = source.GetChapterMetadata(baseURL+"/manga/my-manga", "", "13", "", "")
+	if err == nil || !util.ErrorContains(err, errordefs.ErrChapterNotFound.Error()) {
+		t.Fatalf("expected chapter not found error, got %v", err)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	source, baseURL := newTestSource(t)
+
+	results, err := source.Search("my manga", 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if results[0].Name != "My Manga" || results[0].URL != baseURL+"/manga/my-manga" || results[0].LastChapter != "12" {
+		t.Fatalf("unexpected first result: %+v", results[0])
+	}
+
+	results, err = source.Search("my manga", 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result with limit 1, got %d", len(results))
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(c *Config)
+	}{
+		{"Invalid name", func(c *Config) { c.Name = "My Site" }},
+		{"Built-in source name", func(c *Config) { c.Name = "mangadex"; c.BaseURL = "https://mangadex.org" }},
+		{"No chapter list", func(c *Config) { c.Chapters.List = "" }},
+		{"Invalid selector", func(c *Config) { c.Manga.Name.Selector = "h1[" }},
+		{"Invalid regex", func(c *Config) { c.Chapters.Number.Regex = "(\\d+" }},
+		{"Date without format", func(c *Config) { c.Chapters.DateFormat = "" }},
+		{"Search URL without term", func(c *Config) { c.SearchURL = "http://localhost/search" }},
+	}
+
+	for _, test := range tests {
+		config, err := ParseConfig([]byte(configYAML))
+		if err != nil {
+			t.Fatalf("error parsing config: %v", err)
+		}
+		if err := config.Validate([]string{"mangadex"}); err != nil {
+			t.Fatalf("expected valid config, got %v", err)
+		}
+		test.change(config)
+		if err := config.Validate([]string{"mangadex"}); err == nil {
+			t.Errorf("%s: expected error", test.name)
+		}
+	}
+}
+
+func TestMatchesURL(t *testing.T) {
+	config, err := ParseConfig([]byte(`{"name": "manga", "base_url": "https://manga.example.com", "manga": {"name": {"selector": "h1"}}, "chapters": {"list": "li"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	source := New(config)
+
+	tests := map[string]bool{
+		"https://manga.example.com/manga/one-piece": true,
+		"https://MANGA.example.com/manga/one-piece": true,
+		"https://mangadex.org/title/one-piece":      false,
+		"https://example.com/manga/one-piece":       false,
+		"not a url":                                 false,
+	}
+	for mangaURL, expected := range tests {
+		if matches := source.MatchesURL(mangaURL); matches != expected {
+			t.Errorf("expected '%s' to match %t, got %t", mangaURL, expected, matches)
+		}
+	}
+}
+
+func TestLoadConfigs(t *testing.T) {
+	dir := t.TempDir()
+	jsonConfig := `{"name": "mysite", "base_url": "https://mysite.com", "manga": {"name": {"selector": "h1"}}, "chapters": {"list": "li"}}`
+	files := map[string]string{
+		"localhost.yaml": configYAML,
+		"mysite.json":    jsonConfig,
+		"README.md":      "not a config",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	configs, err := LoadConfigs(dir, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(configs) != 2 || configs[0].Name != "localhost" || configs[1].Name != "mysite" {
+		t.Fatalf("expected configs localhost and mysite, got %d configs", len(configs))
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "mysite2.yml"), []byte(jsonConfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfigs(dir, nil); err == nil {
+		t.Fatal("expected error with a source defined twice")
+	}
+}
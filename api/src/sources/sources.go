@@ -4,12 +4,17 @@
 package sources
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/diogovalentte/mantium/api/src/db"
+	"github.com/diogovalentte/mantium/api/src/errordefs"
 	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/sources/asura"
+	"github.com/diogovalentte/mantium/api/src/sources/bato"
 	"github.com/diogovalentte/mantium/api/src/sources/comick"
 	"github.com/diogovalentte/mantium/api/src/sources/jmanga"
 	"github.com/diogovalentte/mantium/api/src/sources/klmanga"
@@ -19,6 +24,11 @@ import (
 	"github.com/diogovalentte/mantium/api/src/sources/mangaupdates"
 	"github.com/diogovalentte/mantium/api/src/sources/models"
 	"github.com/diogovalentte/mantium/api/src/sources/rawkuma"
+	"github.com/diogovalentte/mantium/api/src/sources/scraper"
+	"github.com/diogovalentte/mantium/api/src/sources/suwayomi"
+	"github.com/diogovalentte/mantium/api/src/sources/webtoons"
+	"github.com/diogovalentte/mantium/api/src/sources/weebcentral"
+	"github.com/diogovalentte/mantium/api/src/telemetry"
 	"github.com/diogovalentte/mantium/api/src/util"
 )
 
@@ -32,6 +42,10 @@ var Sources = map[string]models.Source{
 	"rawkuma":      &rawkuma.Source{},
 	"klmanga":      &klmanga.Source{},
 	"jmanga":       &jmanga.Source{},
+	"webtoons":     &webtoons.Source{},
+	"bato":         &bato.Source{},
+	"asura":        &asura.Source{},
+	"weebcentral":  &weebcentral.Source{},
 }
 
 // SourcesTLDs specifies the TLDs of the sources.
@@ -45,6 +59,34 @@ func RegisterSource(domain string, source models.Source) {
 	Sources[domain] = source
 }
 
+// RegisterScraperSources loads the scraper sources configs in the directory
+// and registers them. It returns the names of the registered sources.
+func RegisterScraperSources(dir string) ([]string, error) {
+	builtInSources := make([]string, 0, len(Sources))
+	for name := range Sources {
+		builtInSources = append(builtInSources, name)
+	}
+
+	configs, err := scraper.LoadConfigs(dir, builtInSources)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(configs))
+	for _, c := range configs {
+		RegisterSource(c.Name, scraper.New(c))
+		names = append(names, c.Name)
+	}
+
+	return names, nil
+}
+
+// RegisterSuwayomiSource registers the source that gets the mangas from
+// any source installed in the Suwayomi instance.
+func RegisterSuwayomiSource(address, username, password string, searchSources []string) {
+	RegisterSource("suwayomi", suwayomi.New(address, username, password, searchSources))
+}
+
 // DeleteSource deletes a source
 func DeleteSource(domain string) {
 	delete(Sources, domain)
@@ -71,8 +113,11 @@ func GetSources() map[string]models.Source {
 	return Sources
 }
 
-// GetMangaMetadata gets the metadata of a manga using a source
-func GetMangaMetadata(mangaURL, internalID string) (*manga.Manga, error) {
+// GetMangaMetadata gets the metadata of a manga using a source.
+// ctx is used to trace the request to the source.
+// If the source has multiple releases of the chapters, the last released chapter
+// is chosen using the chapter preferences, which can be nil.
+func GetMangaMetadata(ctx context.Context, mangaURL, internalID string, preferences *manga.ChapterPreferences) (*manga.Manga, error) {
 	contextError := "error while getting metadata of manga with URL '%s' and internal ID '%s' from source"
 
 	source, err := GetSource(mangaURL)
@@ -81,16 +126,28 @@ func GetMangaMetadata(mangaURL, internalID string) (*manga.Manga, error) {
 	}
 	contextError = fmt.Sprintf("(%s) %s", source.GetName(), contextError)
 
-	manga, err := getManga(mangaURL, internalID, source)
+	manga, err := getManga(ctx, mangaURL, internalID, source)
 	if err != nil {
 		return nil, util.AddErrorContext(fmt.Sprintf(contextError, mangaURL, internalID), err)
 	}
 
+	if lister, ok := source.(models.ReleasesLister); ok && !preferences.IsEmpty() {
+		chapters, err := getPreferredChapters(ctx, mangaURL, internalID, preferences, lister, source)
+		if err != nil {
+			return nil, util.AddErrorContext(fmt.Sprintf(contextError, mangaURL, internalID), err)
+		}
+		manga.LastReleasedChapter = nil
+		if len(chapters) > 0 {
+			manga.LastReleasedChapter = chapters[0]
+			manga.LastReleasedChapter.Type = 1
+		}
+	}
+
 	return manga, nil
 }
 
 // SearchManga searches for a manga using a source
-func SearchManga(term, sourceName string, limit int) ([]*models.MangaSearchResult, error) {
+func SearchManga(ctx context.Context, term, sourceName string, limit int) ([]*models.MangaSearchResult, error) {
 	contextError := "error while searching '%s' in '%s'"
 
 	source, ok := Sources[sourceName]
@@ -99,7 +156,7 @@ func SearchManga(term, sourceName string, limit int) ([]*models.MangaSearchResul
 	}
 	contextError = fmt.Sprintf("(%s) %s", source.GetName(), contextError)
 
-	results, err := searchManga(term, limit, source)
+	results, err := searchManga(ctx, term, limit, source)
 	if err != nil {
 		return nil, util.AddErrorContext(fmt.Sprintf(contextError, term, source), err)
 	}
@@ -110,7 +167,9 @@ func SearchManga(term, sourceName string, limit int) ([]*models.MangaSearchResul
 // GetChapterMetadata gets the metadata of a chapter using a source.
 // Each source has its own way to get the chapter. Some can't get the chapter by its URL/chapter,
 // so they get the chapter by the chapter chapter/URL.
-func GetChapterMetadata(mangaURL, mangaInternalID, chapter, chapterURL, chapterInternalID string) (*manga.Chapter, error) {
+// If the source has multiple releases of the chapters, the release of the chapter
+// is chosen using the chapter preferences, which can be nil.
+func GetChapterMetadata(ctx context.Context, mangaURL, mangaInternalID, chapter, chapterURL, chapterInternalID string, preferences *manga.ChapterPreferences) (*manga.Chapter, error) {
 	contextError := "error while getting metadata of chapter with manga URL '%s', internal ID '%s', chapter '%s', chapter URL '%s', chapter internal ID '%s'"
 
 	source, err := GetSource(mangaURL)
@@ -119,7 +178,12 @@ func GetChapterMetadata(mangaURL, mangaInternalID, chapter, chapterURL, chapterI
 	}
 	contextError = fmt.Sprintf("(%s) %s", source.GetName(), contextError)
 
-	chapterReturn, err := getChapter(mangaURL, mangaInternalID, chapter, chapterURL, chapterInternalID, source)
+	var chapterReturn *manga.Chapter
+	if lister, ok := source.(models.ReleasesLister); ok && !preferences.IsEmpty() && chapterInternalID == "" {
+		chapterReturn, err = getPreferredChapter(ctx, mangaURL, mangaInternalID, chapter, chapterURL, preferences, lister, source)
+	} else {
+		chapterReturn, err = getChapter(ctx, mangaURL, mangaInternalID, chapter, chapterURL, chapterInternalID, source)
+	}
 	if err != nil {
 		return nil, util.AddErrorContext(fmt.Sprintf(contextError, mangaURL, mangaInternalID, chapter, chapterURL, chapterInternalID), err)
 	}
@@ -127,8 +191,10 @@ func GetChapterMetadata(mangaURL, mangaInternalID, chapter, chapterURL, chapterI
 	return chapterReturn, nil
 }
 
-// GetMangaChapters gets the chapters of a manga using a source
-func GetMangaChapters(mangaURL, mangaInternalID string) ([]*manga.Chapter, error) {
+// GetMangaChapters gets the chapters of a manga using a source.
+// If the source has multiple releases of the chapters, the releases
+// are chosen using the chapter preferences, which can be nil.
+func GetMangaChapters(ctx context.Context, mangaURL, mangaInternalID string, preferences *manga.ChapterPreferences) ([]*manga.Chapter, error) {
 	contextError := "error while getting chapters from manga with URL '%s' and internal ID '%s' from source"
 
 	source, err := GetSource(mangaURL)
@@ -137,7 +203,12 @@ func GetMangaChapters(mangaURL, mangaInternalID string) ([]*manga.Chapter, error
 	}
 	contextError = fmt.Sprintf("(%s) %s", source.GetName(), contextError)
 
-	chapters, err := getChapters(mangaURL, mangaInternalID, source)
+	var chapters []*manga.Chapter
+	if lister, ok := source.(models.ReleasesLister); ok && !preferences.IsEmpty() {
+		chapters, err = getPreferredChapters(ctx, mangaURL, mangaInternalID, preferences, lister, source)
+	} else {
+		chapters, err = getChapters(ctx, mangaURL, mangaInternalID, source)
+	}
 	if err != nil {
 		return nil, util.AddErrorContext(fmt.Sprintf(contextError, mangaURL, mangaInternalID), err)
 	}
@@ -145,6 +216,150 @@ func GetMangaChapters(mangaURL, mangaInternalID string) ([]*manga.Chapter, error
 	return chapters, nil
 }
 
+// HasRelations returns true if the manga source lists the titles related to its mangas.
+func HasRelations(mangaURL string) bool {
+	source, err := GetSource(mangaURL)
+	if err != nil {
+		return false
+	}
+	_, ok := source.(models.RelationsGetter)
+
+	return ok
+}
+
+// GetMangaRelations gets the titles related to a manga, like its sequels, using a source.
+func GetMangaRelations(ctx context.Context, mangaURL, mangaInternalID string) ([]*manga.Relation, error) {
+	contextError := "error while getting relations of manga with URL '%s' and internal ID '%s' from source"
+
+	source, err := GetSource(mangaURL)
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, mangaURL, mangaInternalID), err)
+	}
+	contextError = fmt.Sprintf("(%s) %s", source.GetName(), contextError)
+
+	getter, ok := source.(models.RelationsGetter)
+	if !ok {
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, mangaURL, mangaInternalID), errordefs.ErrSourceHasNoRelations)
+	}
+
+	end := observeSourceRequest(ctx, source, "get_relations")
+	relations, err := getter.GetMangaRelations(mangaURL, mangaInternalID)
+	end(err)
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, mangaURL, mangaInternalID), err)
+	}
+
+	return relations, nil
+}
+
+// HasGenres returns true if the manga source lists the genres of its mangas.
+func HasGenres(mangaURL string) bool {
+	source, err := GetSource(mangaURL)
+	if err != nil {
+		return false
+	}
+	_, ok := source.(models.GenresGetter)
+
+	return ok
+}
+
+// GetMangaGenres gets the genres of a manga using a source.
+func GetMangaGenres(ctx context.Context, mangaURL, mangaInternalID string) ([]string, error) {
+	contextError := "error while getting genres of manga with URL '%s' and internal ID '%s' from source"
+
+	source, err := GetSource(mangaURL)
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, mangaURL, mangaInternalID), err)
+	}
+	contextError = fmt.Sprintf("(%s) %s", source.GetName(), contextError)
+
+	getter, ok := source.(models.GenresGetter)
+	if !ok {
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, mangaURL, mangaInternalID), errordefs.ErrSourceHasNoGenres)
+	}
+
+	end := observeSourceRequest(ctx, source, "get_genres")
+	genres, err := getter.GetMangaGenres(mangaURL, mangaInternalID)
+	end(err)
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, mangaURL, mangaInternalID), err)
+	}
+
+	return genres, nil
+}
+
+// HasSimilarTitles returns true if the manga source lists titles similar to its mangas.
+func HasSimilarTitles(mangaURL string) bool {
+	source, err := GetSource(mangaURL)
+	if err != nil {
+		return false
+	}
+	_, ok := source.(models.SimilarTitlesLister)
+
+	return ok
+}
+
+// GetSimilarTitles gets the genres of a manga and the titles similar to it using a source.
+func GetSimilarTitles(ctx context.Context, mangaURL, mangaInternalID string) (*models.SimilarTitles, error) {
+	contextError := "error while getting titles similar to manga with URL '%s' and internal ID '%s' from source"
+
+	source, err := GetSource(mangaURL)
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, mangaURL, mangaInternalID), err)
+	}
+	contextError = fmt.Sprintf("(%s) %s", source.GetName(), contextError)
+
+	lister, ok := source.(models.SimilarTitlesLister)
+	if !ok {
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, mangaURL, mangaInternalID), errordefs.ErrSourceHasNoSimilarTitles)
+	}
+
+	end := observeSourceRequest(ctx, source, "get_similar_titles")
+	similarTitles, err := lister.GetSimilarTitles(mangaURL, mangaInternalID)
+	end(err)
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, mangaURL, mangaInternalID), err)
+	}
+
+	return similarTitles, nil
+}
+
+// HasExternalIDs returns true if the manga source knows the external IDs of its mangas.
+func HasExternalIDs(mangaURL string) bool {
+	source, err := GetSource(mangaURL)
+	if err != nil {
+		return false
+	}
+	_, ok := source.(models.ExternalIDsGetter)
+
+	return ok
+}
+
+// GetExternalIDs gets the external IDs of a manga, like its MangaUpdates series ID, using a source.
+func GetExternalIDs(ctx context.Context, mangaURL, mangaInternalID string) (*manga.ExternalIDs, error) {
+	contextError := "error while getting external IDs of manga with URL '%s' and internal ID '%s' from source"
+
+	source, err := GetSource(mangaURL)
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, mangaURL, mangaInternalID), err)
+	}
+	contextError = fmt.Sprintf("(%s) %s", source.GetName(), contextError)
+
+	getter, ok := source.(models.ExternalIDsGetter)
+	if !ok {
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, mangaURL, mangaInternalID), errordefs.ErrSourceHasNoExternalIDs)
+	}
+
+	end := observeSourceRequest(ctx, source, "get_external_ids")
+	externalIDs, err := getter.GetExternalIDs(mangaURL, mangaInternalID)
+	end(err)
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf(contextError, mangaURL, mangaInternalID), err)
+	}
+
+	return externalIDs, nil
+}
+
 // ChangeSourceTLDInDB changes the TLD of a source in the database
 func ChangeSourceTLDInDB(sourceName, newTLD string) error {
 	contextError := "error changing source TLD in DB for source '%s' to '%s'"
@@ -183,27 +398,115 @@ func urlToSource(urlString string) (string, error) {
 	}
 	domain := parsedURL.Hostname()
 
-	for source := range Sources {
-		if strings.Contains(domain, source) {
-			return source, nil
+	for name, source := range Sources {
+		if matcher, ok := source.(models.URLMatcher); ok && matcher.MatchesURL(urlString) {
+			return name, nil
+		}
+	}
+	for name, source := range Sources {
+		if _, ok := source.(models.URLMatcher); ok {
+			continue
+		}
+		if strings.Contains(domain, name) {
+			return name, nil
 		}
 	}
 
 	return "", util.AddErrorContext(fmt.Sprintf(errorContext, urlString), fmt.Errorf("source not found"))
 }
 
-func getManga(mangaURL, mangaInternalID string, source models.Source) (*manga.Manga, error) {
-	return source.GetMangaMetadata(mangaURL, mangaInternalID)
+func getManga(ctx context.Context, mangaURL, mangaInternalID string, source models.Source) (*manga.Manga, error) {
+	end := observeSourceRequest(ctx, source, "get_manga")
+	m, err := source.GetMangaMetadata(mangaURL, mangaInternalID)
+	end(err)
+
+	return m, err
+}
+
+func searchManga(ctx context.Context, term string, limit int, source models.Source) ([]*models.MangaSearchResult, error) {
+	end := observeSourceRequest(ctx, source, "search")
+	results, err := source.Search(term, limit)
+	end(err)
+
+	return results, err
+}
+
+func getChapter(ctx context.Context, mangaURL, mangaInternalID, chapter, chapterURL, chapterInternalID string, source models.Source) (*manga.Chapter, error) {
+	end := observeSourceRequest(ctx, source, "get_chapter")
+	chapterReturn, err := source.GetChapterMetadata(mangaURL, mangaInternalID, chapter, chapterURL, chapterInternalID)
+	end(err)
+
+	return chapterReturn, err
+}
+
+func getChapters(ctx context.Context, mangaURL, mangaInternalID string, source models.Source) ([]*manga.Chapter, error) {
+	end := observeSourceRequest(ctx, source, "get_chapters")
+	chapters, err := source.GetChaptersMetadata(mangaURL, mangaInternalID)
+	end(err)
+
+	return chapters, err
+}
+
+func getReleases(ctx context.Context, mangaURL, mangaInternalID string, languages []string, lister models.ReleasesLister, source models.Source) ([]*manga.Chapter, error) {
+	end := observeSourceRequest(ctx, source, "get_releases")
+	releases, err := lister.GetReleasesMetadata(mangaURL, mangaInternalID, languages)
+	end(err)
+
+	return releases, err
 }
 
-func searchManga(term string, limit int, source models.Source) ([]*models.MangaSearchResult, error) {
-	return source.Search(term, limit)
+// getPreferredChapters returns the releases of the manga chapters chosen using the preferences,
+// from the newest to the oldest chapter.
+func getPreferredChapters(ctx context.Context, mangaURL, mangaInternalID string, preferences *manga.ChapterPreferences, lister models.ReleasesLister, source models.Source) ([]*manga.Chapter, error) {
+	releases, err := getReleases(ctx, mangaURL, mangaInternalID, preferences.Languages, lister, source)
+	if err != nil {
+		return nil, err
+	}
+
+	return preferences.SelectReleases(releases, time.Now()), nil
 }
 
-func getChapter(mangaURL, mangaInternalID, chapter, chapterURL, chapterInternalID string, source models.Source) (*manga.Chapter, error) {
-	return source.GetChapterMetadata(mangaURL, mangaInternalID, chapter, chapterURL, chapterInternalID)
+// getPreferredChapter returns a release by its URL, or the release of the chapter chosen using
+// the preferences. When getting by the chapter, it doesn't wait for the preferred groups,
+// as the chapter is usually set by the user.
+func getPreferredChapter(ctx context.Context, mangaURL, mangaInternalID, chapter, chapterURL string, preferences *manga.ChapterPreferences, lister models.ReleasesLister, source models.Source) (*manga.Chapter, error) {
+	if chapter == "" && chapterURL == "" {
+		return nil, errordefs.ErrChapterHasNoChapterOrURL
+	}
+
+	releases, err := getReleases(ctx, mangaURL, mangaInternalID, preferences.Languages, lister, source)
+	if err != nil {
+		return nil, err
+	}
+
+	if chapterURL != "" {
+		for _, release := range releases {
+			if release.URL == chapterURL {
+				return release, nil
+			}
+		}
+	}
+	if chapter != "" {
+		withoutWait := *preferences
+		withoutWait.WaitHours = 0
+		for _, release := range withoutWait.SelectReleases(releases, time.Now()) {
+			if release.Chapter == chapter {
+				return release, nil
+			}
+		}
+	}
+
+	return nil, errordefs.ErrChapterNotFound
 }
 
-func getChapters(mangaURL, mangaInternalID string, source models.Source) ([]*manga.Chapter, error) {
-	return source.GetChaptersMetadata(mangaURL, mangaInternalID)
+// observeSourceRequest starts a span and times a request to the source.
+// The returned function must be called with the request error when it finishes.
+func observeSourceRequest(ctx context.Context, source models.Source, operation string) func(error) {
+	start := time.Now()
+	_, span := telemetry.StartSpan(ctx, "source."+operation, "source", source.GetName())
+
+	return func(err error) {
+		telemetry.ObserveSourceRequest(source.GetName(), operation, start, err)
+		telemetry.EndSpan(span, err)
+	}
 }
@@ -0,0 +1,74 @@
+// Package sourcestest implements a server to test the sources with fixtures,
+// which are trimmed copies of the sources' sites pages.
+package sourcestest
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// imageExtensions are the extensions of the paths served as images, like the covers.
+var imageExtensions = []string{".jpg", ".jpeg", ".png", ".gif"}
+
+// blankWebP is a 1x1 lossless WebP image, as the standard library can't encode WebP images.
+var blankWebP = []byte("RIFF\x1a\x00\x00\x00WEBPVP8L\x0d\x00\x00\x00\x2f\x00\x00\x00\x10\x07\x10\x11\x11\x88\x88\xfe\x07\x00")
+
+// NewFixtureServer returns a server that serves the fixtures in the testdata directory of the
+// test package. fixtures maps the request path, with the query if the page depends on it, to the
+// fixture file name. The other paths with an image extension are served as a blank image, and
+// the rest return 404. The origins, like the site URL and its images CDN URL, are replaced by
+// the server URL in the fixtures, so their links point to the server. The server is closed
+// when the test ends.
+func NewFixtureServer(t *testing.T, fixtures map[string]string, origins ...string) *httptest.Server {
+	t.Helper()
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name, ok := fixtures[r.URL.RequestURI()]
+		if !ok {
+			name, ok = fixtures[r.URL.Path]
+		}
+		if ok {
+			content, err := os.ReadFile(filepath.Join("testdata", name))
+			if err != nil {
+				t.Errorf("error reading fixture '%s': %s", name, err)
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			for _, origin := range origins {
+				content = bytes.ReplaceAll(content, []byte(origin), []byte(server.URL))
+			}
+			// Some pages are HTML fragments, so the content type isn't detected
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write(content)
+			return
+		}
+
+		ext := strings.ToLower(path.Ext(r.URL.Path))
+		if ext == ".webp" {
+			w.Write(blankWebP)
+			return
+		}
+		for _, imageExt := range imageExtensions {
+			if ext == imageExt {
+				var buf bytes.Buffer
+				png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 50, 70)))
+				w.Write(buf.Bytes())
+				return
+			}
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
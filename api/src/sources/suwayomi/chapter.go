@@ -0,0 +1,104 @@
+package suwayomi
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// GetChapterMetadata returns a chapter by its internal ID, chapter, or URL
+func (s *Source) GetChapterMetadata(mangaURL, mangaInternalID, chapter, chapterURL, chapterInternalID string) (*manga.Chapter, error) {
+	errorContext := "error while getting metadata of chapter"
+
+	if chapter == "" && chapterURL == "" && chapterInternalID == "" {
+		return nil, util.AddErrorContext(errorContext, errordefs.ErrChapterHasNoChapterOrURL)
+	}
+
+	chapters, err := s.GetChaptersMetadata(mangaURL, mangaInternalID)
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+
+	for _, c := range chapters {
+		if chapterInternalID != "" && c.InternalID == chapterInternalID {
+			return c, nil
+		}
+	}
+	for _, c := range chapters {
+		if (chapter != "" && c.Chapter == chapter) || (chapter == "" && chapterURL != "" && c.URL == chapterURL) {
+			return c, nil
+		}
+	}
+
+	return nil, util.AddErrorContext(errorContext, errordefs.ErrChapterNotFound)
+}
+
+// GetLastChapterMetadata returns the last released chapter in the source
+func (s *Source) GetLastChapterMetadata(mangaURL, mangaInternalID string) (*manga.Chapter, error) {
+	errorContext := "error while getting last chapter metadata"
+
+	chapters, err := s.GetChaptersMetadata(mangaURL, mangaInternalID)
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+	if len(chapters) == 0 {
+		return nil, util.AddErrorContext(errorContext, errordefs.ErrChapterNotFound)
+	}
+
+	return chapters[0], nil
+}
+
+// GetChaptersMetadata fetches the chapters from the manga source through Suwayomi
+// and returns them, from the newest to the oldest
+func (s *Source) GetChaptersMetadata(mangaURL, mangaInternalID string) ([]*manga.Chapter, error) {
+	errorContext := "error while getting chapters metadata"
+
+	mangaID, err := s.getMangaID(mangaURL, mangaInternalID)
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+
+	apiChapters, err := s.client.FetchChapters(mangaID)
+	if err != nil {
+		if util.ErrorContains(err, "does not exist") || util.ErrorContains(err, "not found") {
+			return nil, util.AddErrorContext(errorContext, errordefs.ErrMangaNotFound)
+		}
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+
+	sort.SliceStable(apiChapters, func(i, j int) bool {
+		return apiChapters[i].SourceOrder > apiChapters[j].SourceOrder
+	})
+
+	// Some sources don't show the release date, the current time is used instead
+	currentTime := time.Now().Truncate(time.Second)
+	chapters := make([]*manga.Chapter, 0, len(apiChapters))
+	for _, apiChapter := range apiChapters {
+		chapter := &manga.Chapter{
+			Chapter:    apiChapter.Name,
+			Name:       apiChapter.Name,
+			URL:        apiChapter.RealURL,
+			InternalID: strconv.Itoa(apiChapter.ID),
+			UpdatedAt:  currentTime,
+			Type:       1,
+		}
+		if apiChapter.ChapterNumber >= 0 {
+			chapter.Chapter = strconv.FormatFloat(apiChapter.ChapterNumber, 'f', -1, 64)
+		}
+		if chapter.URL == "" {
+			chapter.URL = fmt.Sprintf("%s/chapter/%d", s.getMangaURL(mangaID), apiChapter.SourceOrder)
+		}
+		if uploadDate, err := strconv.ParseInt(apiChapter.UploadDate, 10, 64); err == nil && uploadDate > 0 {
+			chapter.UpdatedAt = time.UnixMilli(uploadDate).UTC().Truncate(time.Second)
+		}
+
+		chapters = append(chapters, chapter)
+	}
+
+	return chapters, nil
+}
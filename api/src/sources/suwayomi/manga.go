@@ -0,0 +1,127 @@
+package suwayomi
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/integrations/suwayomi"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/sources/models"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// GetMangaMetadata fetches the manga from its source through Suwayomi and return the manga data
+func (s *Source) GetMangaMetadata(mangaURL, mangaInternalID string) (*manga.Manga, error) {
+	errorContext := "error while getting manga metadata"
+
+	mangaID, err := s.getMangaID(mangaURL, mangaInternalID)
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+
+	apiManga, err := s.client.FetchManga(mangaID)
+	if err != nil {
+		if util.ErrorContains(err, "does not exist") || util.ErrorContains(err, "not found") {
+			return nil, util.AddErrorContext(errorContext, errordefs.ErrMangaNotFound)
+		}
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+
+	mangaReturn := &manga.Manga{
+		Source:     s.GetName(),
+		URL:        s.getMangaURL(apiManga.ID),
+		Name:       apiManga.Title,
+		InternalID: strconv.Itoa(apiManga.ID),
+	}
+
+	// Cover Image
+	if apiManga.ThumbnailURL != "" {
+		coverImg, err := s.client.GetImage(apiManga.ThumbnailURL)
+		if err == nil && util.IsImageValid(coverImg) {
+			mangaReturn.CoverImgURL = s.client.Address + apiManga.ThumbnailURL
+			mangaReturn.CoverImg = coverImg
+			resizedCoverImg, err := util.ResizeImage(coverImg, uint(util.DefaultImageWidth), uint(util.DefaultImageHeight))
+			if err == nil {
+				mangaReturn.CoverImg = resizedCoverImg
+				mangaReturn.CoverImgResized = true
+			}
+		}
+	}
+
+	// Last Released Chapter
+	chapters, err := s.GetChaptersMetadata(mangaReturn.URL, mangaReturn.InternalID)
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+	if len(chapters) > 0 {
+		mangaReturn.LastReleasedChapter = chapters[0]
+	}
+
+	return mangaReturn, nil
+}
+
+// Search searches the manga in the Suwayomi search sources, in order, until the limit.
+// Suwayomi saves the mangas found, so the results have a Suwayomi manga URL.
+func (s *Source) Search(term string, limit int) ([]*models.MangaSearchResult, error) {
+	errorContext := "error while searching manga"
+
+	if len(s.searchSources) == 0 {
+		return nil, util.AddErrorContext(errorContext, fmt.Errorf("no Suwayomi source to search, set the SUWAYOMI_SOURCE_SEARCH_SOURCES environment variable"))
+	}
+
+	extensions, err := s.client.FetchSources()
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+
+	mangaSearchResults := []*models.MangaSearchResult{}
+	for _, sourceName := range s.searchSources {
+		sourceID, ok := getSourceID(extensions, sourceName)
+		if !ok {
+			return nil, util.AddErrorContext(errorContext, fmt.Errorf("Suwayomi source '%s' not found/installed", sourceName))
+		}
+
+		apiMangas, err := s.client.FetchSourceMangas(sourceID, term, 1)
+		if err != nil {
+			return nil, util.AddErrorContext(errorContext, util.AddErrorContext(fmt.Sprintf("error while searching in Suwayomi source '%s'", sourceName), err))
+		}
+
+		for _, apiManga := range apiMangas {
+			if len(mangaSearchResults) >= limit {
+				return mangaSearchResults, nil
+			}
+
+			mangaSearchResult := &models.MangaSearchResult{
+				Source:      s.GetName(),
+				URL:         s.getMangaURL(apiManga.ID),
+				Name:        apiManga.Title,
+				InternalID:  strconv.Itoa(apiManga.ID),
+				Description: fmt.Sprintf("From %s: %s", sourceName, apiManga.RealURL),
+				CoverURL:    models.DefaultCoverImgURL,
+			}
+			if apiManga.ThumbnailURL != "" {
+				mangaSearchResult.CoverURL = s.client.Address + apiManga.ThumbnailURL
+			}
+			mangaSearchResults = append(mangaSearchResults, mangaSearchResult)
+		}
+	}
+
+	return mangaSearchResults, nil
+}
+
+// getSourceID returns the ID of the Suwayomi source with the display name, like "MangaDex (EN)".
+func getSourceID(extensions []*suwayomi.Extension, sourceName string) (string, bool) {
+	for _, extension := range extensions {
+		if extension.Source == nil {
+			continue
+		}
+		for _, edge := range extension.Source.Edges {
+			if edge.Node != nil && edge.Node.DisplayName == sourceName {
+				return edge.Node.ID, true
+			}
+		}
+	}
+
+	return "", false
+}
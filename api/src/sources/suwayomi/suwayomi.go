@@ -0,0 +1,64 @@
+// Package suwayomi provides the implementation of the manga.Source interface for
+// the mangas of any source installed in Suwayomi, using its GraphQL API as the
+// metadata backend. The manga URLs are the Suwayomi manga URLs, like
+// https://suwayomi.domain.com/manga/1, so they don't depend on the source site.
+package suwayomi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/integrations/suwayomi"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// Source is the implementation of the manga.Source interface for the Suwayomi sources
+type Source struct {
+	client *suwayomi.Suwayomi
+	// searchSources are the display names of the Suwayomi sources used to search, like "MangaDex (EN)".
+	searchSources []string
+}
+
+// New returns a source that gets the mangas from the Suwayomi instance in the address.
+func New(address, username, password string, searchSources []string) *Source {
+	return &Source{
+		client:        suwayomi.New(address, username, password),
+		searchSources: searchSources,
+	}
+}
+
+func (Source) GetName() string {
+	return "suwayomi"
+}
+
+// MatchesURL returns true if the URL is a manga URL of the Suwayomi instance.
+func (s *Source) MatchesURL(mangaURL string) bool {
+	return strings.HasPrefix(mangaURL, s.client.Address+"/manga/")
+}
+
+// getMangaURL returns the Suwayomi manga URL.
+func (s *Source) getMangaURL(mangaID int) string {
+	return fmt.Sprintf("%s/manga/%d", s.client.Address, mangaID)
+}
+
+// getMangaID returns the Suwayomi manga ID from the internal ID, or from the URL if empty.
+func (s *Source) getMangaID(mangaURL, mangaInternalID string) (int, error) {
+	errorContext := "error while getting manga ID from URL '%s'"
+
+	idStr := mangaInternalID
+	if idStr == "" {
+		if !s.MatchesURL(mangaURL) {
+			return 0, util.AddErrorContext(fmt.Sprintf(errorContext, mangaURL), fmt.Errorf("URL is not a manga URL of the Suwayomi instance '%s'", s.client.Address))
+		}
+		idStr = strings.SplitN(strings.TrimPrefix(mangaURL, s.client.Address+"/manga/"), "/", 2)[0]
+	}
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		return 0, util.AddErrorContext(fmt.Sprintf(errorContext, mangaURL), errordefs.ErrMangaNotFound)
+	}
+
+	return id, nil
+}
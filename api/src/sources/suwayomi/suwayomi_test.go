@@ -0,0 +1,180 @@
+package suwayomi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// newTestSource returns a source using a stub Suwayomi GraphQL API with one manga with ID 1.
+func newTestSource(t *testing.T, searchSources []string) (*Source, string) {
+	t.Helper()
+
+	responses := map[string]string{
+		"FetchManga":       `{"data": {"fetchManga": {"manga": {"id": 1, "title": "One Punch Man", "thumbnailUrl": "", "realUrl": "https://mangasite.com/manga/opm"}}}}`,
+		"FetchChapters":    `{"data": {"fetchChapters": {"chapters": [{"id": 10, "name": "Chapter 1", "chapterNumber": 1, "uploadDate": "1700000000000", "realUrl": "https://mangasite.com/opm/1", "sourceOrder": 1}, {"id": 12, "name": "Extra", "chapterNumber": -1, "uploadDate": "0", "realUrl": "", "sourceOrder": 3}, {"id": 11, "name": "Chapter 2", "chapterNumber": 2, "uploadDate": "1700086400000", "realUrl": "https://mangasite.com/opm/2", "sourceOrder": 2}]}}}`,
+		"FetchExtensions":  `{"data": {"fetchExtensions": {"extensions": [{"source": {"edges": [{"node": {"id": "100", "displayName": "Local source"}}]}}, {"source": {"edges": []}}, {"source": {"edges": [{"node": {"id": "200", "displayName": "Manga Site (EN)"}}]}}]}}}`,
+		"FetchSourceManga": `{"data": {"fetchSourceManga": {"mangas": [{"id": 1, "title": "One Punch Man", "thumbnailUrl": "/api/v1/manga/1/thumbnail", "realUrl": "https://mangasite.com/manga/opm"}, {"id": 2, "title": "One Piece", "thumbnailUrl": "", "realUrl": "https://mangasite.com/manga/op"}]}}}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/graphql" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var payload struct {
+			Variables     map[string]any `json:"variables"`
+			OperationName string         `json:"operationName"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		switch payload.OperationName {
+		case "FetchManga":
+			if payload.Variables["id"] != float64(1) {
+				w.Write([]byte(`{"data": null, "errors": [{"message": "Manga does not exist"}]}`))
+				return
+			}
+		case "FetchSourceManga":
+			if input, _ := payload.Variables["input"].(map[string]any); input["source"] != "200" || input["query"] != "one" {
+				w.Write([]byte(`{"data": {"fetchSourceManga": {"mangas": []}}}`))
+				return
+			}
+		}
+		w.Write([]byte(responses[payload.OperationName]))
+	}))
+	t.Cleanup(server.Close)
+
+	return New(server.URL, "", "", searchSources), server.URL
+}
+
+func TestGetMangaMetadata(t *testing.T) {
+	source, address := newTestSource(t, nil)
+
+	t.Run("Should get the manga metadata", func(t *testing.T) {
+		m, err := source.GetMangaMetadata(address+"/manga/1", "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if m.Name != "One Punch Man" || m.Source != "suwayomi" || m.URL != address+"/manga/1" || m.InternalID != "1" {
+			t.Fatalf("unexpected manga: %s", m)
+		}
+		if m.LastReleasedChapter == nil || m.LastReleasedChapter.Chapter != "Extra" {
+			t.Fatalf("expected last released chapter Extra, got %s", m.LastReleasedChapter)
+		}
+	})
+	t.Run("Should not get a manga not found", func(t *testing.T) {
+		_, err := source.GetMangaMetadata(address+"/manga/2", "")
+		if err == nil || !util.ErrorContains(err, errordefs.ErrMangaNotFound.Error()) {
+			t.Fatalf("expected manga not found error, got %v", err)
+		}
+	})
+	t.Run("Should not get a manga from another URL", func(t *testing.T) {
+		_, err := source.GetMangaMetadata("https://mangasite.com/manga/opm", "")
+		if err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
+
+func TestGetChaptersMetadata(t *testing.T) {
+	source, address := newTestSource(t, nil)
+
+	chapters, err := source.GetChaptersMetadata(address+"/manga/1", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(chapters) != 3 || chapters[0].Chapter != "Extra" || chapters[1].Chapter != "2" || chapters[2].Chapter != "1" {
+		t.Fatalf("expected chapters Extra, 2, and 1, got %v", chapters)
+	}
+	expected := manga.Chapter{
+		Chapter:    "2",
+		Name:       "Chapter 2",
+		URL:        "https://mangasite.com/opm/2",
+		InternalID: "11",
+		UpdatedAt:  time.Date(2023, 11, 15, 22, 13, 20, 0, time.UTC),
+		Type:       1,
+	}
+	if !reflect.DeepEqual(*chapters[1], expected) {
+		t.Fatalf("expected chapter %s, got %s", &expected, chapters[1])
+	}
+	if chapters[0].URL != address+"/manga/1/chapter/3" {
+		t.Fatalf("expected chapter without URL to have the Suwayomi URL, got %s", chapters[0].URL)
+	}
+
+	chapter, err := source.GetChapterMetadata(address+"/manga/1", "", "", "", "10")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if chapter.Chapter != "1" {
+		t.Fatalf("expected chapter 1, got %s", chapter.Chapter)
+	}
+
+	chapter, err = source.GetChapterMetadata(address+"/manga/1", "", "", "https://mangasite.com/opm/2", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if chapter.Chapter != "2" {
+		t.Fatalf("expected chapter 2, got %s", chapter.Chapter)
+	}
+
+	_, err = source.GetChapterMetadata(address+"/manga/1", "", "3", "", "")
+	if err == nil || !util.ErrorContains(err, errordefs.ErrChapterNotFound.Error()) {
+		t.Fatalf("expected chapter not found error, got %v", err)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	t.Run("Should search in the search sources", func(t *testing.T) {
+		source, address := newTestSource(t, []string{"Manga Site (EN)"})
+
+		results, err := source.Search("one", 10)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(results) != 2 {
+			t.Fatalf("expected 2 results, got %d", len(results))
+		}
+		if results[0].Name != "One Punch Man" || results[0].URL != address+"/manga/1" || results[0].CoverURL != address+"/api/v1/manga/1/thumbnail" || results[0].Source != "suwayomi" {
+			t.Fatalf("unexpected first result: %+v", results[0])
+		}
+
+		results, err = source.Search("one", 1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(results) != 1 {
+			t.Fatalf("expected 1 result with limit 1, got %d", len(results))
+		}
+	})
+	t.Run("Should not search without search sources", func(t *testing.T) {
+		source, _ := newTestSource(t, nil)
+		if _, err := source.Search("one", 10); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+	t.Run("Should not search in a source not installed", func(t *testing.T) {
+		source, _ := newTestSource(t, []string{"Other Site (EN)"})
+		if _, err := source.Search("one", 10); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
+
+func TestMatchesURL(t *testing.T) {
+	source := New("http://suwayomi:4567/", "", "", nil)
+	if !source.MatchesURL("http://suwayomi:4567/manga/1") {
+		t.Fatal("expected URL to match")
+	}
+	if source.MatchesURL("https://mangadex.org/title/1") {
+		t.Fatal("expected URL not to match")
+	}
+}
@@ -0,0 +1,161 @@
+package webtoons
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gocolly/colly/v2"
+
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// maxPages is the maximum number of episode list pages scraped.
+const maxPages = 500
+
+// GetChapterMetadata returns a chapter by its chapter or URL
+func (s *Source) GetChapterMetadata(mangaURL, _, chapter, chapterURL, _ string) (*manga.Chapter, error) {
+	errorContext := "error while getting metadata of chapter"
+
+	if chapter == "" && chapterURL == "" {
+		return nil, util.AddErrorContext(errorContext, errordefs.ErrChapterHasNoChapterOrURL)
+	}
+
+	chapters, err := s.GetChaptersMetadata(mangaURL, "")
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+
+	if chapter != "" {
+		for _, c := range chapters {
+			if c.Chapter == chapter {
+				return c, nil
+			}
+		}
+	}
+	if chapterURL != "" {
+		for _, c := range chapters {
+			if c.URL == chapterURL {
+				return c, nil
+			}
+		}
+	}
+
+	return nil, util.AddErrorContext(errorContext, errordefs.ErrChapterNotFound)
+}
+
+// GetLastChapterMetadata scrapes the manga page and return the latest chapter
+func (s *Source) GetLastChapterMetadata(mangaURL, _ string) (*manga.Chapter, error) {
+	errorContext := "error while getting last chapter metadata"
+
+	formattedURL, err := s.GetFormattedMangaURL(mangaURL)
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+
+	chapters, err := s.getPageChapters(formattedURL, 1)
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+	if len(chapters) == 0 {
+		return nil, util.AddErrorContext(errorContext, errordefs.ErrChapterNotFound)
+	}
+
+	return chapters[0], nil
+}
+
+// GetChaptersMetadata scrapes the episode list pages and return the chapters, from the newest to the oldest
+func (s *Source) GetChaptersMetadata(mangaURL, _ string) ([]*manga.Chapter, error) {
+	errorContext := "error while getting chapters metadata"
+
+	formattedURL, err := s.GetFormattedMangaURL(mangaURL)
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+
+	chapters := []*manga.Chapter{}
+	chaptersURLs := map[string]bool{}
+	for page := 1; page <= maxPages; page++ {
+		pageChapters, err := s.getPageChapters(formattedURL, page)
+		if err != nil {
+			return nil, util.AddErrorContext(errorContext, err)
+		}
+		// The site returns the last page when the page is greater than the number of pages
+		if len(pageChapters) == 0 || chaptersURLs[pageChapters[0].URL] {
+			break
+		}
+
+		for _, chapter := range pageChapters {
+			chaptersURLs[chapter.URL] = true
+			chapters = append(chapters, chapter)
+		}
+	}
+
+	return chapters, nil
+}
+
+// getPageChapters returns the chapters of an episode list page.
+func (s *Source) getPageChapters(mangaURL string, page int) ([]*manga.Chapter, error) {
+	s.resetCollector()
+
+	chapters := []*manga.Chapter{}
+	var sharedErr error
+
+	s.c.OnHTML("ul#_listUl > li", func(e *colly.HTMLElement) {
+		if sharedErr != nil {
+			return
+		}
+		chapter, err := getChapterFromElement(e)
+		if err != nil {
+			sharedErr = err
+			return
+		}
+		chapters = append(chapters, chapter)
+	})
+
+	err := s.c.Visit(fmt.Sprintf("%s&page=%d", mangaURL, page))
+	if err != nil {
+		if err.Error() == "Not Found" {
+			return nil, errordefs.ErrMangaNotFound
+		}
+		return nil, err
+	}
+	if sharedErr != nil {
+		return nil, sharedErr
+	}
+
+	return chapters, nil
+}
+
+// getChapterFromElement returns the chapter of an episode list item.
+func getChapterFromElement(e *colly.HTMLElement) (*manga.Chapter, error) {
+	chapterURL := e.DOM.Find("a").AttrOr("href", "")
+	if chapterURL == "" {
+		return nil, errordefs.ErrChapterURLNotFound
+	}
+
+	chapter := strings.TrimPrefix(strings.TrimSpace(e.DOM.Find("span.tx").Text()), "#")
+	if chapter == "" {
+		chapter = e.Attr("data-episode-no")
+	}
+	chapterName := strings.TrimSpace(e.DOM.Find("span.subj > span").First().Text())
+	if chapterName == "" {
+		chapterName = "Episode " + chapter
+	}
+
+	chapterDate := strings.TrimSpace(e.DOM.Find("span.date").Text())
+	releaseTime, err := time.Parse("Jan 2, 2006", chapterDate)
+	if err != nil {
+		return nil, util.AddErrorContext(fmt.Sprintf("error while parsing chapter '%s' date", chapter), err)
+	}
+
+	return &manga.Chapter{
+		URL:       e.Request.AbsoluteURL(chapterURL),
+		Chapter:   chapter,
+		Name:      chapterName,
+		Type:      1,
+		UpdatedAt: releaseTime.Truncate(time.Second),
+	}, nil
+}
@@ -0,0 +1,161 @@
+package webtoons
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+type chapterTestType struct {
+	expected *manga.Chapter
+	url      string
+}
+
+func getChapterTestTable(baseURL string) []chapterTestType {
+	return []chapterTestType{
+		{
+			expected: &manga.Chapter{
+				Chapter:   "2",
+				Name:      "[Season 1] Ep. 1",
+				URL:       baseURL + "/en/fantasy/tower-of-god/season-1-ep-1/viewer?title_no=95&episode_no=2",
+				UpdatedAt: time.Date(2014, 7, 1, 0, 0, 0, 0, time.UTC),
+				Type:      1,
+			},
+			url: baseURL + "/en/fantasy/tower-of-god/list?title_no=95",
+		},
+		{
+			expected: &manga.Chapter{
+				Chapter:   "287",
+				Name:      "Episode 274",
+				URL:       baseURL + "/en/romance/lore-olympus/episode-274/viewer?title_no=1320&episode_no=287",
+				UpdatedAt: time.Date(2024, 6, 23, 0, 0, 0, 0, time.UTC),
+				Type:      1,
+			},
+			url: baseURL + "/en/romance/lore-olympus/list?title_no=1320",
+		},
+	}
+}
+
+func TestGetChapterMetadata(t *testing.T) {
+	source, baseURL := newTestSource(t)
+	chapterTestTable := getChapterTestTable(baseURL)
+
+	t.Run("Should scrape the metadata of a chapter from multiple mangas", func(t *testing.T) {
+		for _, test := range chapterTestTable {
+			expected := test.expected
+			mangaURL := test.url
+
+			actualChapter, err := source.GetChapterMetadata(mangaURL, "", expected.Chapter, "", "")
+			if err != nil {
+				t.Fatalf("error while getting chapter: %v", err)
+			}
+			if !reflect.DeepEqual(actualChapter, expected) {
+				t.Fatalf("expected chapter %s, got %s", expected, actualChapter)
+			}
+
+			actualChapter, err = source.GetChapterMetadata(mangaURL, "", "", expected.URL, "")
+			if err != nil {
+				t.Fatalf("error while getting chapter by URL: %v", err)
+			}
+			if !reflect.DeepEqual(actualChapter, expected) {
+				t.Fatalf("expected chapter %s, got %s", expected, actualChapter)
+			}
+		}
+	})
+	t.Run("Should not scrape the metadata of a chapter from multiple mangas", func(t *testing.T) {
+		for _, test := range chapterTestTable {
+			expected := test.expected
+			mangaURL := test.url + "salt"
+
+			actualChapter, err := source.GetChapterMetadata(mangaURL, "", expected.Chapter, "", "")
+			if err != nil {
+				if !util.ErrorContains(err, errordefs.ErrMangaNotFound.Error()) {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			} else {
+				t.Fatalf("expected error, got nil")
+			}
+
+			if reflect.DeepEqual(actualChapter, expected) {
+				t.Fatalf("expected actual chapter %s to NOT be deep equal to expected chapter %s", actualChapter, expected)
+			}
+		}
+	})
+	t.Run("Should not find a chapter not in the manga", func(t *testing.T) {
+		_, err := source.GetChapterMetadata(chapterTestTable[0].url, "", "1000", "", "")
+		if err == nil || !util.ErrorContains(err, errordefs.ErrChapterNotFound.Error()) {
+			t.Fatalf("expected chapter not found error, got %v", err)
+		}
+	})
+}
+
+func TestGetLastChapterMetadata(t *testing.T) {
+	source, baseURL := newTestSource(t)
+
+	t.Run("Should scrape the metadata of the last chapter of multiple mangas", func(t *testing.T) {
+		for _, test := range getMangasTestTable(baseURL) {
+			expected := test.expected.LastReleasedChapter
+			mangaURL := test.expected.URL
+
+			actualChapter, err := source.GetLastChapterMetadata(mangaURL, "")
+			if err != nil {
+				t.Fatalf("error while getting chapter: %v", err)
+			}
+
+			if !reflect.DeepEqual(actualChapter, expected) {
+				t.Fatalf("expected chapter %s, got %s", expected, actualChapter)
+			}
+		}
+	})
+}
+
+type chaptersTestType struct {
+	url      string
+	quantity int
+}
+
+func getChaptersTestTable(baseURL string) []chaptersTestType {
+	return []chaptersTestType{
+		{
+			url:      baseURL + "/en/fantasy/tower-of-god/list?title_no=95",
+			quantity: 4,
+		},
+		{
+			url:      baseURL + "/en/romance/lore-olympus/list?title_no=1320",
+			quantity: 3,
+		},
+	}
+}
+
+func TestGetChaptersMetadata(t *testing.T) {
+	source, baseURL := newTestSource(t)
+
+	t.Run("Should scrape the metadata of multiple chapters from all pages", func(t *testing.T) {
+		for _, test := range getChaptersTestTable(baseURL) {
+			mangaURL := test.url
+
+			chapters, err := source.GetChaptersMetadata(mangaURL, "")
+			if err != nil {
+				t.Fatalf("error while getting chapters: %v", err)
+			}
+
+			if len(chapters) != test.quantity {
+				t.Fatalf("expected %d chapters, got %d", test.quantity, len(chapters))
+			}
+		}
+	})
+	t.Run("Should not scrape the metadata of multiple chapters", func(t *testing.T) {
+		for _, test := range getChaptersTestTable(baseURL) {
+			mangaURL := test.url + "salt"
+
+			_, err := source.GetChaptersMetadata(mangaURL, "")
+			if err == nil || !util.ErrorContains(err, errordefs.ErrMangaNotFound.Error()) {
+				t.Fatalf("expected manga not found error, got %v", err)
+			}
+		}
+	})
+}
@@ -0,0 +1,117 @@
+package webtoons
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gocolly/colly/v2"
+
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/sources/models"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// GetMangaMetadata scrapes the manga page and return the manga data
+func (s *Source) GetMangaMetadata(mangaURL, _ string) (*manga.Manga, error) {
+	s.resetCollector()
+
+	errorContext := "error while getting manga metadata"
+
+	formattedURL, err := s.GetFormattedMangaURL(mangaURL)
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+
+	mangaReturn := &manga.Manga{}
+	mangaReturn.Source = "webtoons"
+	mangaReturn.URL = formattedURL
+
+	var sharedErr error
+
+	// manga name
+	s.c.OnHTML("div.info h1.subj", func(e *colly.HTMLElement) {
+		mangaReturn.Name = strings.Join(strings.Fields(e.Text), " ")
+	})
+
+	// manga cover
+	s.c.OnHTML("meta[property='og:image']", func(e *colly.HTMLElement) {
+		coverURL := e.Attr("content")
+		if coverURL == "" {
+			return
+		}
+
+		coverImg, resized, err := util.GetImageFromURL(coverURL, 3, 1*time.Second)
+		if err == nil {
+			mangaReturn.CoverImgURL = coverURL
+			mangaReturn.CoverImgResized = resized
+			mangaReturn.CoverImg = coverImg
+		}
+	})
+
+	// last released chapter
+	s.c.OnHTML("ul#_listUl > li:first-child", func(e *colly.HTMLElement) {
+		chapter, err := getChapterFromElement(e)
+		if err != nil {
+			sharedErr = err
+			return
+		}
+		mangaReturn.LastReleasedChapter = chapter
+	})
+
+	err = s.c.Visit(formattedURL)
+	if err != nil {
+		if err.Error() == "Not Found" {
+			return nil, util.AddErrorContext(errorContext, errordefs.ErrMangaNotFound)
+		}
+		return nil, util.AddErrorContext(errorContext, util.AddErrorContext("error while visiting manga URL", err))
+	}
+	if sharedErr != nil {
+		return nil, util.AddErrorContext(errorContext, sharedErr)
+	}
+	if mangaReturn.Name == "" {
+		return nil, util.AddErrorContext(errorContext, errordefs.ErrMangaNotFound)
+	}
+
+	return mangaReturn, nil
+}
+
+// Search searches the English series. Only the first page of results is used.
+func (s *Source) Search(term string, limit int) ([]*models.MangaSearchResult, error) {
+	s.resetCollector()
+
+	errorContext := "error while searching manga"
+	mangaSearchResults := []*models.MangaSearchResult{}
+
+	s.c.OnHTML("ul.webtoon_list > li > a, ul.card_lst > li > a", func(e *colly.HTMLElement) {
+		if len(mangaSearchResults) >= limit {
+			return
+		}
+
+		mangaURL, err := s.GetFormattedMangaURL(e.Request.AbsoluteURL(e.Attr("href")))
+		if err != nil {
+			return
+		}
+		mangaSearchResult := &models.MangaSearchResult{
+			Source:   "webtoons",
+			URL:      mangaURL,
+			Name:     strings.TrimSpace(e.DOM.Find("strong.title, p.subj").First().Text()),
+			CoverURL: e.DOM.Find("img").AttrOr("src", ""),
+		}
+		if mangaSearchResult.CoverURL == "" {
+			mangaSearchResult.CoverURL = models.DefaultCoverImgURL
+		}
+
+		mangaSearchResults = append(mangaSearchResults, mangaSearchResult)
+	})
+
+	searchURL := fmt.Sprintf("%s/en/search?keyword=%s", s.getBaseURL(), url.QueryEscape(term))
+	err := s.c.Visit(searchURL)
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, util.AddErrorContext("error while visiting search URL", err))
+	}
+
+	return mangaSearchResults, nil
+}
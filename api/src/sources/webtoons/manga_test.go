@@ -0,0 +1,181 @@
+package webtoons
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/sources/sourcestest"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// newTestSource returns a source that uses a server with the pages in testdata, and the server URL.
+// Like the site, the episode list pages greater than the number of pages are the last page.
+func newTestSource(t *testing.T) (*Source, string) {
+	server := sourcestest.NewFixtureServer(t, map[string]string{
+		"/en/fantasy/tower-of-god/list?title_no=95":          "tower-of-god_page1.html",
+		"/en/fantasy/tower-of-god/list?title_no=95&page=1":   "tower-of-god_page1.html",
+		"/en/fantasy/tower-of-god/list?title_no=95&page=2":   "tower-of-god_page2.html",
+		"/en/fantasy/tower-of-god/list?title_no=95&page=3":   "tower-of-god_page2.html",
+		"/en/romance/lore-olympus/list?title_no=1320":        "lore-olympus.html",
+		"/en/romance/lore-olympus/list?title_no=1320&page=1": "lore-olympus.html",
+		"/en/romance/lore-olympus/list?title_no=1320&page=2": "lore-olympus.html",
+		"/en/search?keyword=Tower+of+God":                    "search_tower_of_god.html",
+		"/en/search?keyword=Lore+Olympus":                    "search_lore_olympus.html",
+	}, "https://www.webtoons.com", "https://swebtoon-phinf.pstatic.net", "https://webtoon-phinf.pstatic.net")
+
+	return &Source{baseURL: server.URL}, server.URL
+}
+
+type mangaTestType struct {
+	expected *manga.Manga
+	url      string
+}
+
+func getMangasTestTable(baseURL string) []mangaTestType {
+	return []mangaTestType{
+		{
+			expected: &manga.Manga{
+				Name:            "Tower of God",
+				Source:          "webtoons",
+				URL:             baseURL + "/en/fantasy/tower-of-god/list?title_no=95",
+				CoverImgURL:     baseURL + "/20190904_284/1567573591185kPBsR_JPEG/05_EC9E91ED9288EC8381EC84B8_mobile.jpg?type=crop540_540",
+				CoverImgResized: true,
+				LastReleasedChapter: &manga.Chapter{
+					Chapter:   "640",
+					Name:      "[Season 3] Ep. 220",
+					URL:       baseURL + "/en/fantasy/tower-of-god/season-3-ep-220/viewer?title_no=95&episode_no=640",
+					UpdatedAt: time.Date(2023, 11, 26, 0, 0, 0, 0, time.UTC),
+					Type:      1,
+				},
+			},
+			url: baseURL + "/en/fantasy/tower-of-god/list?title_no=95&page=2",
+		},
+		{
+			expected: &manga.Manga{
+				Name:            "Lore Olympus",
+				Source:          "webtoons",
+				URL:             baseURL + "/en/romance/lore-olympus/list?title_no=1320",
+				CoverImgURL:     baseURL + "/20180913_246/1536806880537u3NSa_JPEG/LO_mobile_thumbnail.jpg?type=crop540_540",
+				CoverImgResized: true,
+				LastReleasedChapter: &manga.Chapter{
+					Chapter:   "288",
+					Name:      "Episode 275 (Season 3 Finale)",
+					URL:       baseURL + "/en/romance/lore-olympus/episode-275-season-3-finale/viewer?title_no=1320&episode_no=288",
+					UpdatedAt: time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC),
+					Type:      1,
+				},
+			},
+			url: baseURL + "/en/romance/lore-olympus/list?title_no=1320",
+		},
+	}
+}
+
+func TestGetMangaMetadata(t *testing.T) {
+	source, baseURL := newTestSource(t)
+	mangasTestTable := getMangasTestTable(baseURL)
+
+	t.Run("Should scrape metadata from multiple mangas", func(t *testing.T) {
+		for _, test := range mangasTestTable {
+			expected := test.expected
+			mangaURL := test.url
+
+			actualManga, err := source.GetMangaMetadata(mangaURL, "")
+			if err != nil {
+				t.Fatalf("error while getting manga: %v", err)
+			}
+
+			if actualManga.CoverImg == nil {
+				t.Fatalf("expected manga.CoverImg to be different than nil")
+			}
+			actualManga.CoverImg = nil
+
+			if !reflect.DeepEqual(actualManga, expected) {
+				t.Fatalf("expected manga %s, got %s", expected, actualManga)
+			}
+		}
+	})
+	t.Run("Should not scrape metadata from multiple mangas", func(t *testing.T) {
+		for _, test := range mangasTestTable {
+			mangaURL := test.expected.URL + "salt"
+
+			_, err := source.GetMangaMetadata(mangaURL, "")
+			if err != nil {
+				if util.ErrorContains(err, errordefs.ErrMangaNotFound.Error()) {
+					continue
+				}
+				t.Fatalf("expected error, got %s", err)
+			}
+			t.Fatalf("expected error, got nil")
+		}
+	})
+}
+
+func TestSearch(t *testing.T) {
+	source, baseURL := newTestSource(t)
+	mangasTestTable := getMangasTestTable(baseURL)
+
+	t.Run("Should search for multiple mangas", func(t *testing.T) {
+		for _, test := range mangasTestTable {
+			mangaName := test.expected.Name
+
+			results, err := source.Search(mangaName, 20)
+			if err != nil {
+				t.Fatalf("error while searching: %v", err)
+			}
+
+			if len(results) == 0 {
+				t.Fatalf("expected results to be different than 0")
+			}
+			if results[0].URL != test.expected.URL || results[0].Name != mangaName {
+				t.Fatalf("expected the first result to be the manga %s, got %+v", test.expected, results[0])
+			}
+		}
+	})
+	t.Run("Should search the originals and canvas series until the limit", func(t *testing.T) {
+		mangaName := mangasTestTable[0].expected.Name
+
+		results, err := source.Search(mangaName, 20)
+		if err != nil {
+			t.Fatalf("error while searching: %v", err)
+		}
+		if len(results) != 2 {
+			t.Fatalf("expected 2 results, got %d", len(results))
+		}
+		if expected := baseURL + "/en/canvas/tower-of-god-fanart/list?title_no=512345"; results[1].URL != expected || results[1].Name != "Tower of God Fanart" {
+			t.Fatalf("expected the canvas series %s, got %+v", expected, results[1])
+		}
+
+		results, err = source.Search(mangaName, 1)
+		if err != nil {
+			t.Fatalf("error while searching: %v", err)
+		}
+		if len(results) != 1 {
+			t.Fatalf("expected 1 result with limit 1, got %d", len(results))
+		}
+	})
+}
+
+func TestGetFormattedMangaURL(t *testing.T) {
+	source := Source{}
+
+	for _, mangaURL := range []string{
+		"https://www.webtoons.com/en/fantasy/tower-of-god/list?title_no=95",
+		"https://www.webtoons.com/en/fantasy/tower-of-god/list?title_no=95&page=12",
+		"https://m.webtoons.com/en/fantasy/tower-of-god/list?page=2&title_no=95",
+	} {
+		formattedURL, err := source.GetFormattedMangaURL(mangaURL)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if expected := "https://www.webtoons.com/en/fantasy/tower-of-god/list?title_no=95"; formattedURL != expected {
+			t.Errorf("expected URL %s, got %s", expected, formattedURL)
+		}
+	}
+
+	if _, err := source.GetFormattedMangaURL("https://www.webtoons.com/en/fantasy/tower-of-god/ep-1/viewer?title_no=95&episode_no=1"); err == nil {
+		t.Fatal("expected error with an episode URL")
+	}
+}
@@ -0,0 +1,75 @@
+// Package webtoons provides the implementation of the manga.Source interface for the Webtoons source
+package webtoons
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/gocolly/colly/v2"
+
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// baseSiteURL is the site URL used if the source has no base URL.
+const baseSiteURL = "https://www.webtoons.com"
+
+// Source is the struct for the Webtoons source
+type Source struct {
+	c *colly.Collector
+	// baseURL is the site URL, like https://www.webtoons.com. It's baseSiteURL if empty.
+	baseURL string
+}
+
+func (Source) GetName() string {
+	return "webtoons"
+}
+
+var userAgent = "Mozilla/5.0 (X11; Linux x86_64; rv:30.0) Gecko/20100101 Firefox/30.0"
+
+func newCollector() *colly.Collector {
+	c := colly.NewCollector(
+		colly.UserAgent(userAgent),
+	)
+
+	// Skips the age verification page of the mature series
+	c.OnRequest(func(r *colly.Request) {
+		r.Headers.Set("Cookie", "needGDPR=false; needCCPA=false; needCOPPA=false; pagGDPR=true; atGDPR=AD_CONSENT; ageGatePass=true")
+	})
+
+	return c
+}
+
+func (s *Source) getBaseURL() string {
+	if s.baseURL != "" {
+		return s.baseURL
+	}
+
+	return baseSiteURL
+}
+
+func (s *Source) resetCollector() {
+	if s.c != nil {
+		s.c.Wait()
+	}
+
+	s.c = newCollector()
+}
+
+// GetFormattedMangaURL returns the manga episode list URL, like
+// https://www.webtoons.com/en/fantasy/tower-of-god/list?title_no=95,
+// without other query parameters, like the page.
+func (s *Source) GetFormattedMangaURL(mangaURL string) (string, error) {
+	errorContext := "error while getting manga URL '%s'"
+
+	parsedURL, err := url.Parse(mangaURL)
+	if err != nil {
+		return "", util.AddErrorContext(fmt.Sprintf(errorContext, mangaURL), err)
+	}
+	titleNo := parsedURL.Query().Get("title_no")
+	if titleNo == "" || !strings.HasSuffix(parsedURL.Path, "/list") {
+		return "", util.AddErrorContext(fmt.Sprintf(errorContext, mangaURL), fmt.Errorf("manga ID not found"))
+	}
+
+	return fmt.Sprintf("%s%s?title_no=%s", s.getBaseURL(), parsedURL.Path, titleNo), nil
+}
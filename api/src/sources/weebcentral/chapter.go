@@ -0,0 +1,111 @@
+package weebcentral
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gocolly/colly/v2"
+
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// GetChapterMetadata returns a chapter by its chapter or URL
+func (s *Source) GetChapterMetadata(mangaURL, _, chapter, chapterURL, _ string) (*manga.Chapter, error) {
+	errorContext := "error while getting metadata of chapter"
+
+	if chapter == "" && chapterURL == "" {
+		return nil, util.AddErrorContext(errorContext, errordefs.ErrChapterHasNoChapterOrURL)
+	}
+
+	chapters, err := s.GetChaptersMetadata(mangaURL, "")
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+
+	if chapter != "" {
+		for _, c := range chapters {
+			if c.Chapter == chapter {
+				return c, nil
+			}
+		}
+	}
+	if chapterURL != "" {
+		for _, c := range chapters {
+			if c.URL == chapterURL {
+				return c, nil
+			}
+		}
+	}
+
+	return nil, util.AddErrorContext(errorContext, errordefs.ErrChapterNotFound)
+}
+
+// GetLastChapterMetadata scrapes the chapter list and return the latest chapter
+func (s *Source) GetLastChapterMetadata(mangaURL, _ string) (*manga.Chapter, error) {
+	errorContext := "error while getting last chapter metadata"
+
+	chapters, err := s.GetChaptersMetadata(mangaURL, "")
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+	if len(chapters) == 0 {
+		return nil, util.AddErrorContext(errorContext, errordefs.ErrChapterNotFound)
+	}
+
+	return chapters[0], nil
+}
+
+// GetChaptersMetadata scrapes the full chapter list and return the chapters, from the newest to the oldest
+func (s *Source) GetChaptersMetadata(mangaURL, _ string) ([]*manga.Chapter, error) {
+	s.resetCollector()
+
+	errorContext := "error while getting chapters metadata"
+
+	formattedURL, err := s.GetFormattedMangaURL(mangaURL)
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+
+	chapters := []*manga.Chapter{}
+	var sharedErr error
+
+	s.c.OnHTML("a[href*='/chapters/']", func(e *colly.HTMLElement) {
+		if sharedErr != nil {
+			return
+		}
+
+		chapterName := strings.TrimSpace(e.DOM.Find("span.grow > span").First().Text())
+		chapter := getChapterNumber(chapterName)
+
+		chapterDate := e.DOM.Find("time").AttrOr("datetime", "")
+		releaseTime, err := time.Parse(time.RFC3339, chapterDate)
+		if err != nil {
+			sharedErr = util.AddErrorContext(fmt.Sprintf("error while parsing chapter '%s' date", chapter), err)
+			return
+		}
+
+		chapters = append(chapters, &manga.Chapter{
+			URL:       e.Request.AbsoluteURL(e.Attr("href")),
+			Chapter:   chapter,
+			Name:      chapterName,
+			Type:      1,
+			UpdatedAt: releaseTime.UTC().Truncate(time.Second),
+		})
+	})
+
+	err = s.c.Visit(formattedURL + "/full-chapter-list")
+	if err != nil {
+		if err.Error() == "Not Found" {
+			return nil, util.AddErrorContext(errorContext, errordefs.ErrMangaNotFound)
+		}
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+	if sharedErr != nil {
+		return nil, util.AddErrorContext(errorContext, sharedErr)
+	}
+
+	return chapters, nil
+}
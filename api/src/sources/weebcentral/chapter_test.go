@@ -0,0 +1,142 @@
+package weebcentral
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+type chapterTestType struct {
+	expected *manga.Chapter
+	url      string
+}
+
+func getChapterTestTable(baseURL string) []chapterTestType {
+	return []chapterTestType{
+		{
+			expected: &manga.Chapter{
+				Chapter:   "1",
+				Name:      "Chapter 1",
+				URL:       baseURL + "/chapters/01J76XY7EAJ9Q4F5PQ0ZNKMBSW",
+				UpdatedAt: time.Date(1997, 7, 22, 0, 0, 0, 0, time.UTC),
+				Type:      1,
+			},
+			url: baseURL + "/series/01J76XY7E9FNDZ1DBBM6PBJPFK",
+		},
+		{
+			expected: &manga.Chapter{
+				Chapter:   "0.16",
+				Name:      "Chapter 0.16",
+				URL:       baseURL + "/chapters/01J76XYD2TQ5W8E1R3Y6U9I0O4",
+				UpdatedAt: time.Date(1989, 10, 1, 0, 0, 0, 0, time.UTC),
+				Type:      1,
+			},
+			url: baseURL + "/series/01J76XYCZSCRVDHHAZAT4NDD6W/Berserk",
+		},
+	}
+}
+
+func TestGetChapterMetadata(t *testing.T) {
+	source, baseURL := newTestSource(t)
+	chapterTestTable := getChapterTestTable(baseURL)
+
+	t.Run("Should scrape the metadata of a chapter from multiple mangas", func(t *testing.T) {
+		for _, test := range chapterTestTable {
+			expected := test.expected
+			mangaURL := test.url
+
+			actualChapter, err := source.GetChapterMetadata(mangaURL, "", expected.Chapter, "", "")
+			if err != nil {
+				t.Fatalf("error while getting chapter: %v", err)
+			}
+			if !reflect.DeepEqual(actualChapter, expected) {
+				t.Fatalf("expected chapter %s, got %s", expected, actualChapter)
+			}
+
+			actualChapter, err = source.GetChapterMetadata(mangaURL, "", "", expected.URL, "")
+			if err != nil {
+				t.Fatalf("error while getting chapter by URL: %v", err)
+			}
+			if !reflect.DeepEqual(actualChapter, expected) {
+				t.Fatalf("expected chapter %s, got %s", expected, actualChapter)
+			}
+		}
+	})
+	t.Run("Should not find a chapter not in the manga", func(t *testing.T) {
+		_, err := source.GetChapterMetadata(chapterTestTable[0].url, "", "2000", "", "")
+		if err == nil || !util.ErrorContains(err, errordefs.ErrChapterNotFound.Error()) {
+			t.Fatalf("expected chapter not found error, got %v", err)
+		}
+	})
+}
+
+func TestGetLastChapterMetadata(t *testing.T) {
+	source, baseURL := newTestSource(t)
+
+	t.Run("Should scrape the metadata of the last chapter of multiple mangas", func(t *testing.T) {
+		for _, test := range getMangasTestTable(baseURL) {
+			expected := test.expected.LastReleasedChapter
+			mangaURL := test.expected.URL
+
+			actualChapter, err := source.GetLastChapterMetadata(mangaURL, "")
+			if err != nil {
+				t.Fatalf("error while getting chapter: %v", err)
+			}
+
+			if !reflect.DeepEqual(actualChapter, expected) {
+				t.Fatalf("expected chapter %s, got %s", expected, actualChapter)
+			}
+		}
+	})
+}
+
+type chaptersTestType struct {
+	url      string
+	quantity int
+}
+
+func getChaptersTestTable(baseURL string) []chaptersTestType {
+	return []chaptersTestType{
+		{
+			url:      baseURL + "/series/01J76XY7E9FNDZ1DBBM6PBJPFK",
+			quantity: 3,
+		},
+		{
+			url:      baseURL + "/series/01J76XYCZSCRVDHHAZAT4NDD6W",
+			quantity: 4,
+		},
+	}
+}
+
+func TestGetChaptersMetadata(t *testing.T) {
+	source, baseURL := newTestSource(t)
+
+	t.Run("Should scrape the metadata of multiple chapters", func(t *testing.T) {
+		for _, test := range getChaptersTestTable(baseURL) {
+			mangaURL := test.url
+
+			chapters, err := source.GetChaptersMetadata(mangaURL, "")
+			if err != nil {
+				t.Fatalf("error while getting chapters: %v", err)
+			}
+
+			if len(chapters) != test.quantity {
+				t.Fatalf("expected %d chapters, got %d", test.quantity, len(chapters))
+			}
+		}
+	})
+	t.Run("Should not scrape the metadata of multiple chapters", func(t *testing.T) {
+		for _, test := range getChaptersTestTable(baseURL) {
+			mangaURL := getNotFoundMangaURL(test.url)
+
+			_, err := source.GetChaptersMetadata(mangaURL, "")
+			if err == nil || !util.ErrorContains(err, errordefs.ErrMangaNotFound.Error()) {
+				t.Fatalf("expected manga not found error, got %v", err)
+			}
+		}
+	})
+}
@@ -0,0 +1,134 @@
+package weebcentral
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gocolly/colly/v2"
+
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/sources/models"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// GetMangaMetadata scrapes the manga page and return the manga data
+func (s *Source) GetMangaMetadata(mangaURL, _ string) (*manga.Manga, error) {
+	s.resetCollector()
+
+	errorContext := "error while getting manga metadata"
+
+	formattedURL, err := s.GetFormattedMangaURL(mangaURL)
+	if err != nil {
+		return nil, util.AddErrorContext(errorContext, err)
+	}
+
+	mangaReturn := &manga.Manga{}
+	mangaReturn.Source = "weebcentral"
+	mangaReturn.URL = formattedURL
+
+	// manga name
+	s.c.OnHTML("section h1", func(e *colly.HTMLElement) {
+		if mangaReturn.Name == "" {
+			mangaReturn.Name = strings.TrimSpace(e.Text)
+		}
+	})
+
+	// manga cover
+	s.c.OnHTML("meta[property='og:image']", func(e *colly.HTMLElement) {
+		coverURL := e.Request.AbsoluteURL(e.Attr("content"))
+		if coverURL == "" {
+			return
+		}
+
+		coverImg, resized, err := util.GetImageFromURL(coverURL, 3, 1*time.Second)
+		if err == nil {
+			mangaReturn.CoverImgURL = coverURL
+			mangaReturn.CoverImgResized = resized
+			mangaReturn.CoverImg = coverImg
+		}
+	})
+
+	err = s.c.Visit(formattedURL)
+	if err != nil {
+		if err.Error() == "Not Found" {
+			return nil, util.AddErrorContext(errorContext, errordefs.ErrMangaNotFound)
+		}
+		return nil, util.AddErrorContext(errorContext, util.AddErrorContext("error while visiting manga URL", err))
+	}
+	if mangaReturn.Name == "" {
+		return nil, util.AddErrorContext(errorContext, errordefs.ErrMangaNotFound)
+	}
+
+	// The manga page shows only the latest chapters, but they're sorted the same way as the full list
+	lastReleasedChapter, err := s.GetLastChapterMetadata(formattedURL, "")
+	if err != nil {
+		if !util.ErrorContains(err, errordefs.ErrChapterNotFound.Error()) {
+			return nil, util.AddErrorContext(errorContext, err)
+		}
+	} else {
+		mangaReturn.LastReleasedChapter = lastReleasedChapter
+	}
+
+	return mangaReturn, nil
+}
+
+// searchPageSize is the number of results in each search page.
+const searchPageSize = 32
+
+func (s *Source) Search(term string, limit int) ([]*models.MangaSearchResult, error) {
+	errorContext := "error while searching manga"
+	mangaSearchResults := []*models.MangaSearchResult{}
+	offset := 0
+	term = url.QueryEscape(term)
+
+	for len(mangaSearchResults) < limit {
+		s.resetCollector()
+		var pageResults int
+
+		s.c.OnHTML("article:has(a[href*='/series/'])", func(e *colly.HTMLElement) {
+			// The result articles have nested articles
+			if e.DOM.ParentsFiltered("article").Length() > 0 {
+				return
+			}
+			pageResults++
+			if len(mangaSearchResults) >= limit {
+				return
+			}
+
+			link := e.DOM.Find("a[href*='/series/']").First()
+			mangaURL, err := s.GetFormattedMangaURL(link.AttrOr("href", ""))
+			if err != nil {
+				return
+			}
+			mangaSearchResult := &models.MangaSearchResult{
+				Source:   "weebcentral",
+				URL:      mangaURL,
+				Name:     strings.TrimSpace(e.DOM.Find("div.text-ellipsis").First().Text()),
+				CoverURL: e.Request.AbsoluteURL(e.DOM.Find("picture img").AttrOr("src", "")),
+			}
+			if mangaSearchResult.Name == "" {
+				mangaSearchResult.Name = strings.TrimSuffix(e.DOM.Find("picture img").AttrOr("alt", ""), " cover")
+			}
+			if mangaSearchResult.CoverURL == "" {
+				mangaSearchResult.CoverURL = models.DefaultCoverImgURL
+			}
+
+			mangaSearchResults = append(mangaSearchResults, mangaSearchResult)
+		})
+
+		searchURL := fmt.Sprintf("%s/search/data?text=%s&sort=Best+Match&order=Descending&official=Any&display_mode=Full+Display&limit=%d&offset=%d", s.getBaseURL(), term, searchPageSize, offset)
+		err := s.c.Visit(searchURL)
+		if err != nil {
+			return nil, util.AddErrorContext(errorContext, util.AddErrorContext("error while visiting search URL", err))
+		}
+		if pageResults < searchPageSize {
+			break
+		}
+		offset += searchPageSize
+	}
+
+	return mangaSearchResults, nil
+}
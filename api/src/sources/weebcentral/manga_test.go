@@ -0,0 +1,179 @@
+package weebcentral
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/sources/sourcestest"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// newTestSource returns a source that uses a server with the pages in testdata, and the server URL.
+func newTestSource(t *testing.T) (*Source, string) {
+	server := sourcestest.NewFixtureServer(t, map[string]string{
+		"/series/01J76XY7E9FNDZ1DBBM6PBJPFK":                   "one-piece.html",
+		"/series/01J76XY7E9FNDZ1DBBM6PBJPFK/full-chapter-list": "one-piece_full-chapter-list.html",
+		"/series/01J76XYCZSCRVDHHAZAT4NDD6W":                   "berserk.html",
+		"/series/01J76XYCZSCRVDHHAZAT4NDD6W/full-chapter-list": "berserk_full-chapter-list.html",
+		"/search/data?text=One+Piece&sort=Best+Match&order=Descending&official=Any&display_mode=Full+Display&limit=32&offset=0": "search_one_piece.html",
+		"/search/data?text=Berserk&sort=Best+Match&order=Descending&official=Any&display_mode=Full+Display&limit=32&offset=0":   "search_berserk.html",
+	}, "https://weebcentral.com", "https://temp.compsci88.com")
+
+	return &Source{baseURL: server.URL}, server.URL
+}
+
+type mangaTestType struct {
+	expected *manga.Manga
+	url      string
+}
+
+func getMangasTestTable(baseURL string) []mangaTestType {
+	return []mangaTestType{
+		{
+			expected: &manga.Manga{
+				Name:            "One Piece",
+				Source:          "weebcentral",
+				URL:             baseURL + "/series/01J76XY7E9FNDZ1DBBM6PBJPFK",
+				CoverImgURL:     baseURL + "/cover/fallback/01J76XY7E9FNDZ1DBBM6PBJPFK.jpg",
+				CoverImgResized: true,
+				LastReleasedChapter: &manga.Chapter{
+					Chapter:   "1133",
+					Name:      "Chapter 1133",
+					URL:       baseURL + "/chapters/01JEZ4SQ9M3RJ7X6G0A2B8C1DE",
+					UpdatedAt: time.Date(2024, 12, 13, 15, 5, 2, 0, time.UTC),
+					Type:      1,
+				},
+			},
+			url: baseURL + "/series/01J76XY7E9FNDZ1DBBM6PBJPFK/One-Piece",
+		},
+		{
+			expected: &manga.Manga{
+				Name:            "Berserk",
+				Source:          "weebcentral",
+				URL:             baseURL + "/series/01J76XYCZSCRVDHHAZAT4NDD6W",
+				CoverImgURL:     baseURL + "/cover/fallback/01J76XYCZSCRVDHHAZAT4NDD6W.jpg",
+				CoverImgResized: true,
+				LastReleasedChapter: &manga.Chapter{
+					Chapter:   "378",
+					Name:      "Chapter 378",
+					URL:       baseURL + "/chapters/01JB2R6W9V4P8D0F3H5K7M1N2Q",
+					UpdatedAt: time.Date(2024, 10, 25, 3, 10, 44, 0, time.UTC),
+					Type:      1,
+				},
+			},
+			url: baseURL + "/series/01J76XYCZSCRVDHHAZAT4NDD6W",
+		},
+	}
+}
+
+// getNotFoundMangaURL returns the manga URL with another manga ID, as the URL is formatted to its ID.
+func getNotFoundMangaURL(mangaURL string) string {
+	return mangaURL[:len(mangaURL)-4] + "salt"
+}
+
+func TestGetMangaMetadata(t *testing.T) {
+	source, baseURL := newTestSource(t)
+	mangasTestTable := getMangasTestTable(baseURL)
+
+	t.Run("Should scrape metadata from multiple mangas", func(t *testing.T) {
+		for _, test := range mangasTestTable {
+			expected := test.expected
+			mangaURL := test.url
+
+			actualManga, err := source.GetMangaMetadata(mangaURL, "")
+			if err != nil {
+				t.Fatalf("error while getting manga: %v", err)
+			}
+
+			if actualManga.CoverImg == nil {
+				t.Fatalf("expected manga.CoverImg to be different than nil")
+			}
+			actualManga.CoverImg = nil
+
+			if !reflect.DeepEqual(actualManga, expected) {
+				t.Fatalf("expected manga %s, got %s", expected, actualManga)
+			}
+		}
+	})
+	t.Run("Should not scrape metadata from multiple mangas", func(t *testing.T) {
+		for _, test := range mangasTestTable {
+			mangaURL := getNotFoundMangaURL(test.expected.URL)
+
+			_, err := source.GetMangaMetadata(mangaURL, "")
+			if err != nil {
+				if util.ErrorContains(err, errordefs.ErrMangaNotFound.Error()) {
+					continue
+				}
+				t.Fatalf("expected error, got %s", err)
+			}
+			t.Fatalf("expected error, got nil")
+		}
+	})
+}
+
+func TestSearch(t *testing.T) {
+	source, baseURL := newTestSource(t)
+	mangasTestTable := getMangasTestTable(baseURL)
+
+	t.Run("Should search for multiple mangas", func(t *testing.T) {
+		for _, test := range mangasTestTable {
+			mangaName := test.expected.Name
+
+			results, err := source.Search(mangaName, 20)
+			if err != nil {
+				t.Fatalf("error while searching: %v", err)
+			}
+
+			if len(results) == 0 {
+				t.Fatalf("expected results to be different than 0")
+			}
+			if results[0].URL != test.expected.URL || results[0].Name != mangaName {
+				t.Fatalf("expected the first result to be the manga %s, got %+v", test.expected, results[0])
+			}
+		}
+	})
+	t.Run("Should search until the limit", func(t *testing.T) {
+		mangaName := mangasTestTable[0].expected.Name
+
+		results, err := source.Search(mangaName, 20)
+		if err != nil {
+			t.Fatalf("error while searching: %v", err)
+		}
+		if len(results) != 3 {
+			t.Fatalf("expected 3 results, got %d", len(results))
+		}
+
+		results, err = source.Search(mangaName, 1)
+		if err != nil {
+			t.Fatalf("error while searching: %v", err)
+		}
+		if len(results) != 1 {
+			t.Fatalf("expected 1 result with limit 1, got %d", len(results))
+		}
+	})
+}
+
+func TestGetFormattedMangaURL(t *testing.T) {
+	source := Source{}
+
+	for _, mangaURL := range []string{
+		"https://weebcentral.com/series/01J76XY7E9FNDZ1DBBM6PBJPFK",
+		"https://weebcentral.com/series/01J76XY7E9FNDZ1DBBM6PBJPFK/One-Piece",
+		"https://weebcentral.com/series/01J76XY7E9FNDZ1DBBM6PBJPFK/full-chapter-list",
+	} {
+		formattedURL, err := source.GetFormattedMangaURL(mangaURL)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if expected := "https://weebcentral.com/series/01J76XY7E9FNDZ1DBBM6PBJPFK"; formattedURL != expected {
+			t.Errorf("expected URL %s, got %s", expected, formattedURL)
+		}
+	}
+
+	if _, err := source.GetFormattedMangaURL("https://weebcentral.com/chapters/01JEZ4SQ9M3RJ7X6G0A2B8C1DE"); err == nil {
+		t.Fatal("expected error with a chapter URL")
+	}
+}
@@ -0,0 +1,85 @@
+// Package weebcentral provides the implementation of the manga.Source interface for the Weeb Central source
+package weebcentral
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/gocolly/colly/v2"
+
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// baseSiteURL is the site URL used if the source has no base URL.
+const baseSiteURL = "https://weebcentral.com"
+
+// Source is the struct for the Weeb Central source
+type Source struct {
+	c *colly.Collector
+	// baseURL is the site URL, like https://weebcentral.com. It's baseSiteURL if empty.
+	baseURL string
+}
+
+func (Source) GetName() string {
+	return "weebcentral"
+}
+
+var userAgent = "Mozilla/5.0 (X11; Linux x86_64; rv:30.0) Gecko/20100101 Firefox/30.0"
+
+func newCollector() *colly.Collector {
+	c := colly.NewCollector(
+		colly.UserAgent(userAgent),
+	)
+
+	return c
+}
+
+func (s *Source) getBaseURL() string {
+	if s.baseURL != "" {
+		return s.baseURL
+	}
+
+	return baseSiteURL
+}
+
+func (s *Source) resetCollector() {
+	if s.c != nil {
+		s.c.Wait()
+	}
+
+	s.c = newCollector()
+}
+
+var mangaIDRegex = regexp.MustCompile(`/series/([0-9A-Za-z]{26})`)
+
+// getMangaID returns the manga ULID in the manga URL.
+func getMangaID(mangaURL string) (string, error) {
+	matches := mangaIDRegex.FindStringSubmatch(mangaURL)
+	if len(matches) < 2 {
+		return "", util.AddErrorContext(fmt.Sprintf("error while getting manga ID from URL '%s'", mangaURL), fmt.Errorf("manga ID not found"))
+	}
+
+	return matches[1], nil
+}
+
+// GetFormattedMangaURL returns the manga URL without the slug, like https://weebcentral.com/series/01J76XY7E9FNDZ1DBBM6PBJPFK
+func (s *Source) GetFormattedMangaURL(mangaURL string) (string, error) {
+	mangaID, err := getMangaID(mangaURL)
+	if err != nil {
+		return "", util.AddErrorContext(fmt.Sprintf("error while getting manga URL '%s'", mangaURL), err)
+	}
+
+	return fmt.Sprintf("%s/series/%s", s.getBaseURL(), mangaID), nil
+}
+
+var chapterNumberRegex = regexp.MustCompile(`(?i)(?:chapter|episode)\s*(\d+(?:\.\d+)?)`)
+
+// getChapterNumber returns the chapter number in the chapter name, like "12" in "Chapter 12".
+// If there's no number, it returns the name.
+func getChapterNumber(chapterName string) string {
+	if matches := chapterNumberRegex.FindStringSubmatch(chapterName); len(matches) > 1 {
+		return matches[1]
+	}
+
+	return chapterName
+}
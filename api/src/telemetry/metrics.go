@@ -0,0 +1,180 @@
+// Package telemetry implements the Prometheus metrics and the OpenTelemetry tracing of the API.
+package telemetry
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const namespace = "mantium"
+
+// Registry is the registry of the API metrics.
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Number of HTTP requests by route, method, and status code.",
+	}, []string{"route", "method", "status"})
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Duration of the HTTP requests by route and method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method"})
+
+	sourceRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "source_request_duration_seconds",
+		Help:      "Duration of the requests to the sources by source and operation, like get_manga.",
+		Buckets:   []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"source", "operation"})
+	sourceRequestErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "source_request_errors_total",
+		Help:      "Number of failed requests to the sources by source and operation.",
+	}, []string{"source", "operation"})
+
+	updateMangasMetadataDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "update_mangas_metadata_duration_seconds",
+		Help:      "Duration of the runs that update the mangas metadata.",
+		Buckets:   []float64{1, 5, 15, 30, 60, 120, 300, 600, 1200},
+	})
+	newChapters = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "new_chapters_total",
+		Help:      "Number of mangas with a new released chapter found when updating the mangas metadata.",
+	})
+
+	notificationsSent = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "notifications_total",
+		Help:      "Number of notifications by notifier and result (sent or failed).",
+	}, []string{"notifier", "result"})
+
+	dbQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Duration of the database queries by operation (query or exec).",
+		Buckets:   []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5},
+	}, []string{"operation"})
+	dbQueryErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "db_query_errors_total",
+		Help:      "Number of failed database queries by operation (query or exec).",
+	}, []string{"operation"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests,
+		httpRequestDuration,
+		sourceRequestDuration,
+		sourceRequestErrors,
+		updateMangasMetadataDuration,
+		newChapters,
+		notificationsSent,
+		dbQueryDuration,
+		dbQueryErrors,
+	)
+}
+
+// MetricsHandler returns the handler of the Prometheus metrics endpoint.
+func MetricsHandler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// GinMiddleware records the HTTP requests metrics and starts a span for each request.
+// The span is set in the request context, so the handlers can start child spans from it.
+func GinMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		// The route path is used instead of the URL, so the metrics don't have a label for each ID
+		route := c.FullPath()
+		if route == "" {
+			route = "unknown"
+		}
+
+		// Continues the trace of the caller, like the dashboard, if it sent the trace headers
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+		ctx, span := tracer().Start(ctx, c.Request.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(c.Request.Method),
+				semconv.HTTPRoute(route),
+				semconv.URLPath(c.Request.URL.Path),
+			),
+		)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+		span.End()
+
+		httpRequests.WithLabelValues(route, c.Request.Method, strconv.Itoa(status)).Inc()
+		httpRequestDuration.WithLabelValues(route, c.Request.Method).Observe(time.Since(start).Seconds())
+	}
+}
+
+// ObserveSourceRequest records a request to a source that started at start.
+func ObserveSourceRequest(source, operation string, start time.Time, err error) {
+	sourceRequestDuration.WithLabelValues(source, operation).Observe(time.Since(start).Seconds())
+	if err != nil {
+		sourceRequestErrors.WithLabelValues(source, operation).Inc()
+	}
+}
+
+// ObserveUpdateMangasMetadata records a run that updated the mangas metadata.
+// newChaptersFound is the number of mangas with a new released chapter.
+func ObserveUpdateMangasMetadata(duration time.Duration, newChaptersFound int) {
+	updateMangasMetadataDuration.Observe(duration.Seconds())
+	newChapters.Add(float64(newChaptersFound))
+}
+
+// ObserveNotification records a notification sent by the notifier, like "ntfy".
+func ObserveNotification(notifier string, err error) {
+	result := "sent"
+	if err != nil {
+		result = "failed"
+	}
+	notificationsSent.WithLabelValues(notifier, result).Inc()
+}
+
+// ObserveDBQuery records a database query, where operation is "query" or "exec".
+func ObserveDBQuery(operation string, duration time.Duration, err error) {
+	dbQueryDuration.WithLabelValues(operation).Observe(duration.Seconds())
+	if err != nil {
+		dbQueryErrors.WithLabelValues(operation).Inc()
+	}
+}
+
+// spanAttributes is a shortcut to create span attributes from strings.
+func spanAttributes(keyValues ...string) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, len(keyValues)/2)
+	for i := 0; i+1 < len(keyValues); i += 2 {
+		attrs = append(attrs, attribute.String(keyValues[i], keyValues[i+1]))
+	}
+
+	return attrs
+}
@@ -0,0 +1,90 @@
+package telemetry
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	collectorpb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestGinMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(GinMiddleware())
+	router.GET("/v1/manga", func(c *gin.Context) {
+		c.JSON(http.StatusNotFound, gin.H{"message": "manga not found"})
+	})
+	router.GET("/metrics", gin.WrapH(MetricsHandler()))
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/manga?id=1", nil))
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := recorder.Body.String()
+	expected := `mantium_http_requests_total{method="GET",route="/v1/manga",status="404"} 1`
+	if !strings.Contains(body, expected) {
+		t.Fatalf("expected metrics to contain %s, got:\n%s", expected, body)
+	}
+}
+
+func TestOTLPExporter(t *testing.T) {
+	var received []*tracepb.ResourceSpans
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" || r.Header.Get("Content-Type") != "application/x-protobuf" {
+			t.Errorf("unexpected request %s with content type %s", r.URL.Path, r.Header.Get("Content-Type"))
+		}
+		authorization = r.Header.Get("Authorization")
+		body, _ := io.ReadAll(r.Body)
+		request := &collectorpb.ExportTraceServiceRequest{}
+		if err := proto.Unmarshal(body, request); err != nil {
+			t.Errorf("invalid request body: %s", err)
+		}
+		received = append(received, request.ResourceSpans...)
+	}))
+	defer server.Close()
+
+	exporter, err := newOTLPExporter(context.Background(), server.URL+"/", map[string]string{"Authorization": "Bearer token"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	previousProvider := otel.GetTracerProvider()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter))
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(previousProvider)
+
+	ctx, parent := StartSpan(context.Background(), "GET /v1/manga")
+	_, child := StartSpan(ctx, "source.get_manga", "source", "mangadex")
+	EndSpan(child, nil)
+	EndSpan(parent, nil)
+	if err := provider.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if authorization != "Bearer token" {
+		t.Fatalf("expected the authorization header to be sent, got %s", authorization)
+	}
+	if len(received) != 1 || len(received[0].ScopeSpans) != 1 {
+		t.Fatalf("expected 1 resource with 1 scope, got %v", received)
+	}
+	got := received[0].ScopeSpans[0].Spans
+	if len(got) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(got))
+	}
+	childProto, parentProto := got[0], got[1]
+	if string(childProto.ParentSpanId) != string(parentProto.SpanId) || string(childProto.TraceId) != string(parentProto.TraceId) {
+		t.Fatal("expected the source span to be a child of the route span")
+	}
+	if len(childProto.Attributes) != 1 || childProto.Attributes[0].Key != "source" || childProto.Attributes[0].Value.GetStringValue() != "mangadex" {
+		t.Fatalf("expected the source attribute, got %v", childProto.Attributes)
+	}
+}
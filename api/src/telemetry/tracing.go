@@ -0,0 +1,81 @@
+package telemetry
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/diogovalentte/mantium/api"
+
+// InitTracing sets the global tracer provider to export the spans to an
+// OTLP/HTTP collector, like Jaeger or Grafana Tempo. If endpoint is empty,
+// tracing is disabled and the spans are not recorded.
+// headers are sent with each export request, like an authorization header.
+// It returns a function that flushes the pending spans and shuts down the provider.
+func InitTracing(endpoint string, headers map[string]string, serviceName string) (func(context.Context) error, error) {
+	if endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName)))
+	if err != nil {
+		return nil, err
+	}
+
+	exporter, err := newOTLPExporter(context.Background(), endpoint, headers)
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return provider.Shutdown, nil
+}
+
+// newOTLPExporter returns an exporter that sends the spans to endpoint + "/v1/traces".
+// The spans are sent without TLS if the endpoint scheme is not https.
+func newOTLPExporter(ctx context.Context, endpoint string, headers map[string]string) (*otlptrace.Exporter, error) {
+	return otlptracehttp.New(ctx,
+		otlptracehttp.WithEndpointURL(strings.TrimSuffix(endpoint, "/")+"/v1/traces"),
+		otlptracehttp.WithHeaders(headers),
+		otlptracehttp.WithTimeout(10*time.Second),
+	)
+}
+
+func tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// StartSpan starts a span that is a child of the span in ctx, if any.
+// keyValues are pairs of attribute keys and values, like "source", "mangadex".
+func StartSpan(ctx context.Context, name string, keyValues ...string) (context.Context, trace.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return tracer().Start(ctx, name, trace.WithAttributes(spanAttributes(keyValues...)...))
+}
+
+// EndSpan records err in span, if not nil, and ends it.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
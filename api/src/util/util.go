@@ -13,6 +13,7 @@ import (
 	"os"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/nfnt/resize"
 	"github.com/rs/zerolog"
@@ -62,6 +63,19 @@ func RemoveLastOccurrence(s, old string) string {
 	return modifiedString
 }
 
+// NormalizeName returns the name in lower case without spaces and punctuation.
+// It's used to match mangas by name in other services, like "Berserk!" and "berserk".
+func NormalizeName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
 var (
 	// DefaultImageHeight is the default height of an image
 	DefaultImageHeight = 355
@@ -277,8 +291,69 @@ func RequestUpdateMangasMetadata(notify bool) (*http.Response, error) {
 	return resp, nil
 }
 
+// RequestReconcileDownloadIntegrations sends a request to the server to reconcile the download integrations
+func RequestReconcileDownloadIntegrations(remove bool) (*http.Response, error) {
+	contextErrror := "error requesting to reconcile the download integrations (remove is %v)"
+
+	client := &http.Client{}
+
+	apiPort := os.Getenv("API_PORT")
+	if apiPort == "" {
+		apiPort = "8080"
+	}
+
+	url := fmt.Sprintf("http://localhost:%s/v1/integrations/downloads/reconcile", apiPort)
+	if remove {
+		url += "?remove=true"
+	}
+	req, err := http.NewRequest("POST", url, nil)
+	if err != nil {
+		return nil, AddErrorContext(fmt.Sprintf(contextErrror, remove), err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return resp, AddErrorContext(fmt.Sprintf(contextErrror, remove), err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return resp, AddErrorContext(fmt.Sprintf(contextErrror, remove), fmt.Errorf("non-200 status code -> (%d)", resp.StatusCode))
+	}
+
+	return resp, nil
+}
+
 // FileExists checks if a file exists at the given path.
 func FileExists(path string) bool {
 	_, err := os.Stat(path)
 	return err == nil || !os.IsNotExist(err)
 }
+
+// RequestSendDueNotifications sends a request to the server to send the due notifications in the queue and the digest
+func RequestSendDueNotifications() (*http.Response, error) {
+	contextErrror := "error requesting to send the due notifications"
+
+	client := &http.Client{}
+
+	apiPort := os.Getenv("API_PORT")
+	if apiPort == "" {
+		apiPort = "8080"
+	}
+
+	url := fmt.Sprintf("http://localhost:%s/v1/notifications/queue/send", apiPort)
+	req, err := http.NewRequest("POST", url, nil)
+	if err != nil {
+		return nil, AddErrorContext(contextErrror, err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return resp, AddErrorContext(contextErrror, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return resp, AddErrorContext(contextErrror, fmt.Errorf("non-200 status code -> (%d)", resp.StatusCode))
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,20 @@
+package util
+
+import "testing"
+
+func TestNormalizeName(t *testing.T) {
+	tests := map[string]string{
+		"One-Punch Man": "onepunchman",
+		"One Punch-Man": "onepunchman",
+		"Yotsuba&!":     "yotsuba",
+		"20th Century":  "20thcentury",
+		"進撃の巨人":         "進撃の巨人",
+		" !? ":          "",
+	}
+
+	for name, expected := range tests {
+		if normalized := NormalizeName(name); normalized != expected {
+			t.Errorf("expected '%s' to be normalized to '%s', got '%s'", name, expected, normalized)
+		}
+	}
+}
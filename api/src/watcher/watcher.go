@@ -0,0 +1,179 @@
+// Package watcher checks the pages watched by custom mangas for new chapters.
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// maxPageSize is the max size of a watched page read.
+const maxPageSize = 10 << 20
+
+// HTTPClient is the client used to get the watched pages.
+var HTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+// Validate returns an error if the selector or the regex is invalid.
+func Validate(selector, regex string) error {
+	if selector != "" {
+		if _, err := cascadia.Compile(selector); err != nil {
+			return util.AddErrorContext("invalid CSS selector", err)
+		}
+	}
+	if regex != "" {
+		if _, err := regexp.Compile(regex); err != nil {
+			return util.AddErrorContext("invalid regex", err)
+		}
+	}
+
+	return nil
+}
+
+// Check gets the watched page and returns its last chapter.
+func Check(ctx context.Context, w *manga.CustomMangaWatcher) (string, error) {
+	contextError := fmt.Sprintf("error checking page '%s' of custom manga '%d'", w.URL, w.MangaID)
+
+	page, err := getPage(ctx, w.URL)
+	if err != nil {
+		return "", util.AddErrorContext(contextError, err)
+	}
+	defer page.Close()
+
+	chapters, err := ExtractChapters(page, w.Selector, w.Regex)
+	if err != nil {
+		return "", util.AddErrorContext(contextError, err)
+	}
+	if len(chapters) == 0 {
+		return "", util.AddErrorContext(contextError, errordefs.ErrCustomMangaWatcherNoChapters)
+	}
+
+	return LastChapter(chapters), nil
+}
+
+func getPage(ctx context.Context, url string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64; rv:30.0) Gecko/20100101 Firefox/30.0")
+
+	resp, err := HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("non-200 status code -> (%d)", resp.StatusCode)
+	}
+
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(resp.Body, maxPageSize), resp.Body}, nil
+}
+
+// ExtractChapters returns the chapters in the page, in the page order.
+// The chapters are the text of the elements matched by the selector, or the
+// whole page text if the selector is empty. If the regex is set, the chapters
+// are its matches in the text instead, or its first capturing group if it has one.
+func ExtractChapters(page io.Reader, selector, regex string) ([]string, error) {
+	err := Validate(selector, regex)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := goquery.NewDocumentFromReader(page)
+	if err != nil {
+		return nil, util.AddErrorContext("error parsing page", err)
+	}
+
+	var texts []string
+	if selector == "" {
+		texts = []string{doc.Text()}
+	} else {
+		doc.Find(selector).Each(func(_ int, s *goquery.Selection) {
+			texts = append(texts, s.Text())
+		})
+	}
+
+	chapters := []string{}
+	if regex == "" {
+		for _, text := range texts {
+			if chapter := strings.Join(strings.Fields(text), " "); chapter != "" {
+				chapters = append(chapters, chapter)
+			}
+		}
+		return chapters, nil
+	}
+
+	re := regexp.MustCompile(regex)
+	for _, text := range texts {
+		for _, match := range re.FindAllStringSubmatch(text, -1) {
+			chapter := match[0]
+			if len(match) > 1 {
+				chapter = match[1]
+			}
+			if chapter = strings.TrimSpace(chapter); chapter != "" {
+				chapters = append(chapters, chapter)
+			}
+		}
+	}
+
+	return chapters, nil
+}
+
+// LastChapter returns the chapter with the highest number.
+// If no chapter has a number, it returns the first chapter, as
+// pages usually show the newest chapter first.
+func LastChapter(chapters []string) string {
+	if len(chapters) == 0 {
+		return ""
+	}
+
+	last := ""
+	var lastNumber manga.ChapterNumber
+	for _, chapter := range chapters {
+		number := manga.ParseChapterNumber(chapter)
+		if !number.HasNumber {
+			continue
+		}
+		if last == "" {
+			last, lastNumber = chapter, number
+			continue
+		}
+		if cmp, ok := number.Compare(lastNumber); ok && cmp > 0 {
+			last, lastNumber = chapter, number
+		}
+	}
+	if last == "" {
+		return chapters[0]
+	}
+
+	return last
+}
+
+// IsNewChapter returns true if the chapter comes after the previous chapter.
+// If the chapters can't be compared by their numbers, it's new if they're different.
+func IsNewChapter(chapter, previous string) bool {
+	if previous == "" {
+		return chapter != ""
+	}
+
+	cmp, ok := manga.ParseChapterNumber(chapter).Compare(manga.ParseChapterNumber(previous))
+	if ok {
+		return cmp > 0
+	}
+
+	return chapter != previous
+}
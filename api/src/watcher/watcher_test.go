@@ -0,0 +1,131 @@
+package watcher
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/diogovalentte/mantium/api/src/manga"
+)
+
+const page = `<html><body>
+<h1>My Manga</h1>
+<ul class="chapters">
+  <li><a href="/c/12">Chapter 12 - The End?</a></li>
+  <li><a href="/c/11.5">Chapter 11.5</a></li>
+  <li><a href="/c/11">Chapter 11</a></li>
+</ul>
+<p>Extra: Oneshot</p>
+</body></html>`
+
+func TestExtractChapters(t *testing.T) {
+	tests := []struct {
+		name     string
+		selector string
+		regex    string
+		expected []string
+	}{
+		{"Selector", "ul.chapters a", "", []string{"Chapter 12 - The End?", "Chapter 11.5", "Chapter 11"}},
+		{"Selector and regex with group", "ul.chapters a", `Chapter ([\d.]+)`, []string{"12", "11.5", "11"}},
+		{"Regex in the whole page", "", `Chapter [\d.]+`, []string{"Chapter 12", "Chapter 11.5", "Chapter 11"}},
+		{"No matches", "ol a", "", []string{}},
+	}
+
+	for _, test := range tests {
+		chapters, err := ExtractChapters(strings.NewReader(page), test.selector, test.regex)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		if !slices.Equal(chapters, test.expected) {
+			t.Errorf("%s: expected chapters %v, got %v", test.name, test.expected, chapters)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := Validate("ul.chapters > a", `Chapter (\d+)`); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := Validate("ul[", ""); err == nil {
+		t.Fatal("expected error with invalid selector")
+	}
+	if err := Validate("", "Chapter (\\d+"); err == nil {
+		t.Fatal("expected error with invalid regex")
+	}
+}
+
+func TestLastChapter(t *testing.T) {
+	tests := []struct {
+		chapters []string
+		expected string
+	}{
+		{[]string{"11", "12", "11.5"}, "12"},
+		{[]string{"Oneshot", "Chapter 3", "Chapter 2"}, "Chapter 3"},
+		{[]string{"Prologue", "Extra"}, "Prologue"},
+		{nil, ""},
+	}
+
+	for _, test := range tests {
+		if last := LastChapter(test.chapters); last != test.expected {
+			t.Errorf("LastChapter(%v) = %q, expected %q", test.chapters, last, test.expected)
+		}
+	}
+}
+
+func TestIsNewChapter(t *testing.T) {
+	tests := []struct {
+		chapter  string
+		previous string
+		expected bool
+	}{
+		{"12", "", true},
+		{"12", "11", true},
+		{"11", "12", false},
+		{"12", "12", false},
+		{"Extra", "Prologue", true},
+		{"Extra", "Extra", false},
+	}
+
+	for _, test := range tests {
+		if isNew := IsNewChapter(test.chapter, test.previous); isNew != test.expected {
+			t.Errorf("IsNewChapter(%q, %q) = %v, expected %v", test.chapter, test.previous, isNew, test.expected)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/manga" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(page))
+	}))
+	defer server.Close()
+
+	t.Run("Should return the last chapter", func(t *testing.T) {
+		w := &manga.CustomMangaWatcher{MangaID: 1, URL: server.URL + "/manga", Selector: "ul.chapters a", Regex: `Chapter ([\d.]+)`}
+		chapter, err := Check(context.Background(), w)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if chapter != "12" {
+			t.Fatalf("expected chapter 12, got %q", chapter)
+		}
+	})
+	t.Run("Should return an error without chapters", func(t *testing.T) {
+		w := &manga.CustomMangaWatcher{MangaID: 1, URL: server.URL + "/manga", Selector: "ol a"}
+		if _, err := Check(context.Background(), w); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+	t.Run("Should return an error with non-200 status", func(t *testing.T) {
+		w := &manga.CustomMangaWatcher{MangaID: 1, URL: server.URL + "/other"}
+		if _, err := Check(context.Background(), w); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
@@ -0,0 +1,199 @@
+// Package webhooks implements the inbound webhooks, like the webhook that
+// receives the chapters read in other readers (Suwayomi, Komga, Tachiyomi sync).
+package webhooks
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/diogovalentte/mantium/api/src/db"
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/manga"
+	"github.com/diogovalentte/mantium/api/src/util"
+)
+
+// IdempotencyKeysTTL is how long the idempotency keys of the processed events are kept.
+const IdempotencyKeysTTL = 7 * 24 * time.Hour
+
+// ChapterReadEvent is a chapter read in an external reader.
+type ChapterReadEvent struct {
+	// MangaURL is the manga URL in its source, like https://mangadex.org/title/...
+	MangaURL string `json:"manga_url" binding:"required,http_url"`
+	// Chapter and ChapterURL are the chapter read. At least one must be set.
+	Chapter    string `json:"chapter,omitempty"`
+	ChapterURL string `json:"chapter_url,omitempty" binding:"omitempty,http_url"`
+	// IdempotencyKey identifies the event, so retries don't process it again.
+	// It can also be sent in the Idempotency-Key header.
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+}
+
+// Authenticate returns the integration name of the token, which can be
+// sent as a bearer token in the Authorization header or in the token query.
+func Authenticate(authorizationHeader, queryToken string, tokens map[string]string) (string, error) {
+	token := queryToken
+	if bearer, found := strings.CutPrefix(authorizationHeader, "Bearer "); found {
+		token = strings.TrimSpace(bearer)
+	}
+	if token == "" {
+		return "", errordefs.ErrReadWebhookUnauthorized
+	}
+
+	// All tokens are compared so the response time doesn't tell which one matched.
+	var integration string
+	for configToken, configIntegration := range tokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(configToken)) == 1 {
+			integration = configIntegration
+		}
+	}
+	if integration == "" {
+		return "", errordefs.ErrReadWebhookUnauthorized
+	}
+
+	return integration, nil
+}
+
+// IsChapterAhead returns whether the event chapter number is ahead of the last read chapter.
+// It returns false in comparable if the chapters can't be compared, like chapters without
+// numbers, so the event chapter must be checked with IsChapterNewer after getting it from the source.
+func IsChapterAhead(chapter string, lastReadChapter *manga.Chapter) (ahead bool, comparable bool) {
+	if lastReadChapter == nil {
+		return true, true
+	}
+	if chapter == "" {
+		return false, false
+	}
+	cmp, ok := manga.ParseChapterNumber(chapter).Compare(manga.ParseChapterNumber(lastReadChapter.Chapter))
+	if !ok {
+		return false, false
+	}
+
+	return cmp > 0, true
+}
+
+// IsChapterNewer returns whether the event chapter is newer than the last read chapter.
+// If their numbers can't be compared, it's only newer if it's provably newer: released after
+// the last read chapter or listed before it in the source chapters, which are listed from the
+// newest to the oldest. The last read chapter release date is taken from the source chapters,
+// as its UpdatedAt is when it was read.
+func IsChapterNewer(chapter, lastReadChapter *manga.Chapter, chapters []*manga.Chapter) bool {
+	if ahead, comparable := IsChapterAhead(chapter.Chapter, lastReadChapter); comparable {
+		return ahead
+	}
+
+	chapterIdx, lastReadIdx := -1, -1
+	for i, c := range chapters {
+		if chapterIdx == -1 && isSameChapter(c, chapter) {
+			chapterIdx = i
+		}
+		if lastReadIdx == -1 && isSameChapter(c, lastReadChapter) {
+			lastReadIdx = i
+		}
+	}
+	if lastReadIdx == -1 {
+		return false
+	}
+
+	releasedAt := chapter.UpdatedAt
+	if chapterIdx != -1 && releasedAt.IsZero() {
+		releasedAt = chapters[chapterIdx].UpdatedAt
+	}
+	lastReadReleasedAt := chapters[lastReadIdx].UpdatedAt
+	if !releasedAt.IsZero() && !lastReadReleasedAt.IsZero() && !releasedAt.Equal(lastReadReleasedAt) {
+		return releasedAt.After(lastReadReleasedAt)
+	}
+
+	return chapterIdx != -1 && chapterIdx < lastReadIdx
+}
+
+// isSameChapter returns whether the chapters are the same, by URL or
+// by chapter if one of them doesn't have a URL.
+func isSameChapter(a, b *manga.Chapter) bool {
+	if a.URL != "" && b.URL != "" {
+		return strings.TrimSuffix(a.URL, "/") == strings.TrimSuffix(b.URL, "/")
+	}
+
+	return a.Chapter == b.Chapter
+}
+
+// GetMangaByURL gets the manga of the event from the database. The URL
+// is also checked with or without a trailing slash, as readers store
+// the URLs differently from Mantium.
+func GetMangaByURL(mangaURL string) (*manga.Manga, error) {
+	m, err := manga.GetMangaDBByURL(mangaURL)
+	if err == nil || !util.ErrorContains(err, errordefs.ErrMangaNotFoundDB.Error()) {
+		return m, err
+	}
+
+	otherURL := mangaURL + "/"
+	if strings.HasSuffix(mangaURL, "/") {
+		otherURL = strings.TrimSuffix(mangaURL, "/")
+	}
+	otherM, otherErr := manga.GetMangaDBByURL(otherURL)
+	if otherErr != nil {
+		return nil, err
+	}
+
+	return otherM, nil
+}
+
+// ClaimEvent saves the event idempotency key of the integration. It returns false if
+// the key was already saved, meaning the event was already processed or is being processed.
+// It also deletes the keys older than IdempotencyKeysTTL.
+func ClaimEvent(integration, idempotencyKey string, now time.Time) (bool, error) {
+	contextError := fmt.Sprintf("error saving the idempotency key '%s' of integration '%s'", idempotencyKey, integration)
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return false, util.AddErrorContext(contextError, err)
+	}
+	defer db.Close()
+
+	_, err = db.Exec(`
+        DELETE FROM read_webhook_events
+        WHERE received_at < $1;
+    `, now.Add(-IdempotencyKeysTTL))
+	if err != nil {
+		return false, util.AddErrorContext(contextError, err)
+	}
+
+	result, err := db.Exec(`
+        INSERT INTO read_webhook_events
+            (integration, idempotency_key, received_at)
+        VALUES
+            ($1, $2, $3)
+        ON CONFLICT DO NOTHING;
+    `, integration, idempotencyKey, now)
+	if err != nil {
+		return false, util.AddErrorContext(contextError, err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, util.AddErrorContext(contextError, err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// ReleaseEvent deletes the event idempotency key of the integration,
+// so the event can be processed again, like when processing it failed.
+func ReleaseEvent(integration, idempotencyKey string) error {
+	contextError := fmt.Sprintf("error deleting the idempotency key '%s' of integration '%s'", idempotencyKey, integration)
+
+	db, err := db.OpenConn()
+	if err != nil {
+		return util.AddErrorContext(contextError, err)
+	}
+	defer db.Close()
+
+	_, err = db.Exec(`
+        DELETE FROM read_webhook_events
+        WHERE integration = $1 AND idempotency_key = $2;
+    `, integration, idempotencyKey)
+	if err != nil {
+		return util.AddErrorContext(contextError, err)
+	}
+
+	return nil
+}
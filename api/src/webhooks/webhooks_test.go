@@ -0,0 +1,113 @@
+package webhooks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/diogovalentte/mantium/api/src/errordefs"
+	"github.com/diogovalentte/mantium/api/src/manga"
+)
+
+var tokens = map[string]string{
+	"suwayomi-token-0123456789": "suwayomi",
+	"komga-token-0123456789":    "komga",
+}
+
+func TestAuthenticate(t *testing.T) {
+	t.Run("Should authenticate with a bearer token", func(t *testing.T) {
+		integration, err := Authenticate("Bearer komga-token-0123456789", "", tokens)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if integration != "komga" {
+			t.Fatalf("expected integration komga, got %s", integration)
+		}
+	})
+	t.Run("Should authenticate with a query token", func(t *testing.T) {
+		integration, err := Authenticate("", "suwayomi-token-0123456789", tokens)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if integration != "suwayomi" {
+			t.Fatalf("expected integration suwayomi, got %s", integration)
+		}
+	})
+	t.Run("Should prefer the bearer token over the query token", func(t *testing.T) {
+		_, err := Authenticate("Bearer wrong-token", "suwayomi-token-0123456789", tokens)
+		if err != errordefs.ErrReadWebhookUnauthorized {
+			t.Fatalf("expected ErrReadWebhookUnauthorized, got %v", err)
+		}
+	})
+	t.Run("Should not authenticate without a token", func(t *testing.T) {
+		_, err := Authenticate("", "", tokens)
+		if err != errordefs.ErrReadWebhookUnauthorized {
+			t.Fatalf("expected ErrReadWebhookUnauthorized, got %v", err)
+		}
+	})
+}
+
+func TestIsChapterAhead(t *testing.T) {
+	lastRead := &manga.Chapter{Chapter: "10"}
+	tests := []struct {
+		chapter    string
+		lastRead   *manga.Chapter
+		ahead      bool
+		comparable bool
+	}{
+		{"11", lastRead, true, true},
+		{"10.5", lastRead, true, true},
+		{"10", lastRead, false, true},
+		{"9", lastRead, false, true},
+		{"1", nil, true, true},
+		{"", lastRead, false, false},
+		{"Oneshot", lastRead, false, false},
+	}
+	for _, test := range tests {
+		ahead, comparable := IsChapterAhead(test.chapter, test.lastRead)
+		if ahead != test.ahead || comparable != test.comparable {
+			t.Errorf("expected IsChapterAhead(%q) to be %v, %v, got %v, %v", test.chapter, test.ahead, test.comparable, ahead, comparable)
+		}
+	}
+}
+
+func TestIsChapterNewer(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	chapters := []*manga.Chapter{
+		{Chapter: "Extra", URL: "https://example.com/extra", UpdatedAt: now},
+		{Chapter: "100", URL: "https://example.com/100", UpdatedAt: now.Add(-24 * time.Hour)},
+		{Chapter: "Oneshot", URL: "https://example.com/oneshot", UpdatedAt: now.Add(-48 * time.Hour)},
+	}
+	// The last read chapter UpdatedAt is when it was read
+	lastRead := &manga.Chapter{Chapter: "100", URL: "https://example.com/100", UpdatedAt: now.Add(time.Hour)}
+
+	t.Run("Should compare the chapters by number", func(t *testing.T) {
+		if !IsChapterNewer(&manga.Chapter{Chapter: "101"}, lastRead, nil) {
+			t.Error("expected chapter 101 to be newer")
+		}
+		if IsChapterNewer(&manga.Chapter{Chapter: "99"}, lastRead, nil) {
+			t.Error("expected chapter 99 to not be newer")
+		}
+	})
+	t.Run("Should only be newer if released after the last read chapter", func(t *testing.T) {
+		if !IsChapterNewer(&manga.Chapter{Chapter: "Extra", URL: "https://example.com/extra", UpdatedAt: now}, lastRead, chapters) {
+			t.Error("expected Extra to be newer")
+		}
+		if IsChapterNewer(&manga.Chapter{Chapter: "Oneshot", URL: "https://example.com/oneshot", UpdatedAt: now.Add(-48 * time.Hour)}, lastRead, chapters) {
+			t.Error("expected Oneshot to not be newer")
+		}
+	})
+	t.Run("Should use the chapters position without release dates", func(t *testing.T) {
+		undated := []*manga.Chapter{{Chapter: "Extra", URL: "https://example.com/extra"}, {Chapter: "100", URL: "https://example.com/100"}}
+		if !IsChapterNewer(&manga.Chapter{Chapter: "Extra", URL: "https://example.com/extra"}, lastRead, undated) {
+			t.Error("expected Extra to be newer")
+		}
+	})
+	t.Run("Should not be newer if it can't be proven", func(t *testing.T) {
+		if IsChapterNewer(&manga.Chapter{Chapter: "Extra", URL: "https://example.com/extra", UpdatedAt: now}, lastRead, nil) {
+			t.Error("expected Extra to not be newer without the source chapters")
+		}
+		if IsChapterNewer(&manga.Chapter{Chapter: "Special", URL: "https://example.com/special"}, lastRead, chapters) {
+			t.Error("expected a chapter not in the source chapters to not be newer")
+		}
+	})
+}
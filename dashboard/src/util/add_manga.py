@@ -35,6 +35,10 @@ def show_add_manga_form(form_type: str):
     ss[base_key + "_rawkuma"] = {}
     ss[base_key + "_klmanga"] = {}
     ss[base_key + "_jmanga"] = {}
+    ss[base_key + "_webtoons"] = {}
+    ss[base_key + "_bato"] = {}
+    ss[base_key + "_asura"] = {}
+    ss[base_key + "_weebcentral"] = {}
     ss["add_manga_search_go_back_to_tab"] = 0
 
     if form_type == "url":
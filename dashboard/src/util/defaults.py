@@ -7,6 +7,10 @@ default_sources = {
     "RawKuma": "rawkuma",
     "KLManga": "klmanga",
     "JManga": "jmanga",
+    "Webtoons": "webtoons",
+    "Bato": "bato",
+    "Asura Scans": "asura",
+    "Weeb Central": "weebcentral",
 }
 
 manga_status_options = {
@@ -148,5 +148,13 @@ def get_source_name_and_colors(source: str):
             return "KLManga", "white", "#ee2631"
         case "jmanga":
             return "JManga", "white", "#7b36ce"
+        case "webtoons":
+            return "Webtoons", "white", "#00dc64"
+        case "bato":
+            return "Bato", "white", "#2a5aa0"
+        case "asura":
+            return "Asura Scans", "white", "#913fe2"
+        case "weebcentral":
+            return "Weeb Central", "white", "#e2513c"
         case _:
             return source, "black", "white"